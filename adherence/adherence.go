@@ -0,0 +1,397 @@
+// Package adherence compares the recorded doses against the regimen to find
+// out how closely it was followed.
+//
+// Every dose after the first is due one interval after the previous dose,
+// using the interval of the regimen in effect when that dose was taken. A
+// dose taken within the on time window of the regimen of that time is on
+// time, and a dose taken after its late window, if the regimen has one, counts
+// as missed. If the gap since the previous dose is long enough to fit whole
+// intervals, those doses count as missed and the dose is compared against the
+// last due time it could have been. Doses that were skipped on purpose are
+// not missed, and the dose after a skipped one is due as if it was taken.
+//
+// No doses are due while the regimen is paused. A dose that falls due during
+// a pause, or that is still not taken when a pause starts, is due when the
+// pause ends instead. Doses taken during a pause are not compared against the
+// regimen.
+//
+// For a cyclic regimen, no doses are due on the days of the cycle without
+// doses, so they are not missed either. The dose after a break is due on the
+// first active day after it.
+package adherence
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/internal/stats"
+)
+
+// Status describes when a dose was taken relative to when it was due.
+type Status string
+
+const (
+	// StatusFirst is the status of the first dose, which has no due time.
+	StatusFirst  Status = "first"
+	StatusOnTime Status = "on-time"
+	StatusEarly  Status = "early"
+	StatusLate   Status = "late"
+	// StatusMissed is the status of a dose taken after the late window of
+	// the regimen, which counts as missed rather than late.
+	StatusMissed Status = "missed"
+	// StatusPaused is the status of a dose taken while the regimen was
+	// paused, which has no due time.
+	StatusPaused Status = "paused"
+)
+
+// Pause is a span of time during which the regimen was paused. A zero EndedAt
+// means the pause has no end.
+type Pause struct {
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// Contains returns true if t is within the pause.
+func (p Pause) Contains(t time.Time) bool {
+	return !t.Before(p.StartedAt) && (p.EndedAt.IsZero() || t.Before(p.EndedAt))
+}
+
+// AfterPauses returns when a dose due at t is due given the pauses, which
+// must be sorted by their start and not overlap. pending is the time until
+// which the dose was not taken. If t is within a pause, or a pause starts
+// after t but before pending, the dose is due when the pause ends instead. It
+// returns false if it is due after a pause that has no end.
+func AfterPauses(pauses []Pause, t, pending time.Time) (time.Time, bool) {
+	for _, p := range pauses {
+		if p.StartedAt.After(t) && !p.StartedAt.Before(pending) {
+			continue
+		}
+		if !p.EndedAt.IsZero() && !p.EndedAt.After(t) {
+			continue
+		}
+		if p.EndedAt.IsZero() {
+			return time.Time{}, false
+		}
+		t = p.EndedAt
+	}
+	return t, true
+}
+
+// pausedBetween returns true if any of the pauses overlaps the time between a
+// and b.
+func pausedBetween(pauses []Pause, a, b time.Time) bool {
+	for _, p := range pauses {
+		if p.StartedAt.Before(b) && (p.EndedAt.IsZero() || p.EndedAt.After(a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dose is a recorded dose compared against the regimen.
+type Dose struct {
+	DosageAt time.Time
+	Status   Status
+	// DueAt is the time the dose was due. It is zero for the first dose.
+	DueAt time.Time
+	// Interval is the time since the previous dose. It is zero for the first
+	// dose.
+	Interval time.Duration
+	// Lateness is how long after DueAt the dose was taken. It is negative if
+	// the dose was taken early.
+	Lateness time.Duration
+	// Missed is the number of doses that were missed between the previous
+	// dose and this one.
+	Missed int
+	// Skipped is the number of doses that were skipped on purpose between
+	// the previous dose and this one.
+	Skipped int
+}
+
+// DurationStats summarizes a set of durations. All fields are zero if there
+// are no durations, and StdDev is zero if there is only one.
+type DurationStats struct {
+	Count  int
+	Mean   time.Duration
+	StdDev time.Duration
+	Min    time.Duration
+	Median time.Duration
+	Max    time.Duration
+}
+
+func newDurationStats(ds []time.Duration) DurationStats {
+	if len(ds) == 0 {
+		return DurationStats{}
+	}
+
+	xs := make([]float64, len(ds))
+	for i, d := range ds {
+		xs[i] = float64(d)
+	}
+
+	s := DurationStats{
+		Count:  len(ds),
+		Mean:   time.Duration(stats.Mean(xs)),
+		Min:    slices.Min(ds),
+		Median: time.Duration(stats.Median(xs)),
+		Max:    slices.Max(ds),
+	}
+	if sd := stats.StdDev(xs); !math.IsNaN(sd) {
+		s.StdDev = time.Duration(sd)
+	}
+	return s
+}
+
+// Summary summarizes the doses within a time range.
+type Summary struct {
+	From time.Time
+	To   time.Time
+	// Doses is the number of doses taken within the range.
+	Doses  int
+	OnTime int
+	Early  int
+	Late   int
+	// Paused is the number of doses taken while the regimen was paused.
+	Paused int
+	// Missed is the number of doses that were due within the range but never
+	// taken or taken after their late window, including the ones missed since
+	// the last dose.
+	Missed int
+	// Skipped is the number of doses due within the range that were skipped
+	// on purpose. They are not missed.
+	Skipped int
+	// Intervals summarizes the time between consecutive doses.
+	Intervals DurationStats
+	// Lateness summarizes how late doses were taken. Early doses have a
+	// negative lateness.
+	Lateness DurationStats
+	// CurrentStreak is the number of doses in a row up to the end of the range
+	// that were not late and had no missed doses before them.
+	CurrentStreak int
+	// LongestStreak is the longest streak within the range.
+	LongestStreak int
+	// HourOfDay counts the doses taken within each hour of the day in the
+	// time zone of the regimen.
+	HourOfDay [24]int
+}
+
+// OnTimeRatio returns the fraction of due doses that were taken on time, or
+// zero if no doses were due.
+func (s Summary) OnTimeRatio() float64 {
+	due := s.OnTime + s.Early + s.Late + s.Missed
+	if due == 0 {
+		return 0
+	}
+	return float64(s.OnTime) / float64(due)
+}
+
+// Analyze compares the doses taken within [from, to] against the regimen. The
+// doses may be in any order and may include doses outside the range, which
+// are used to find when the first dose in the range was due. The returned
+// doses are the ones within the range, oldest first. The pauses must be
+// sorted by their start and not overlap. The skips are the due times of the
+// doses that were skipped on purpose, in any order.
+func Analyze(regimen hrtclicker.HRTConfig, doses []time.Time, pauses []Pause, skips []time.Time, from, to time.Time) ([]Dose, Summary) {
+	sorted := slices.Clone(doses)
+	slices.SortFunc(sorted, time.Time.Compare)
+
+	inRange := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	summary := Summary{From: from, To: to}
+	var analyzed []Dose
+	var intervals, lateness []time.Duration
+
+	for i, t := range sorted {
+		if !inRange(t) {
+			continue
+		}
+
+		var prev time.Time
+		if i > 0 {
+			prev = sorted[i-1]
+		}
+
+		dose := compare(regimen, prev, t, pauses, skips, inRange)
+		switch dose.Status {
+		case StatusLate:
+			summary.Late++
+		case StatusMissed:
+			summary.Missed++
+		case StatusEarly:
+			summary.Early++
+		case StatusOnTime:
+			summary.OnTime++
+		case StatusPaused:
+			summary.Paused++
+		}
+
+		if i > 0 && dose.Status != StatusPaused {
+			summary.Missed += dose.Missed
+			summary.Skipped += dose.Skipped
+			// The time spent paused or on a break of the cycle is not an
+			// interval of the regimen.
+			if !pausedBetween(pauses, prev, t) && !breakAfter(regimen, prev) {
+				intervals = append(intervals, dose.Interval)
+			}
+			lateness = append(lateness, dose.Lateness)
+		}
+
+		if dose.Missed > 0 {
+			summary.CurrentStreak = 0
+		}
+		switch dose.Status {
+		case StatusPaused:
+			// Doses taken while paused neither keep up nor break the
+			// streak.
+		case StatusLate, StatusMissed:
+			summary.CurrentStreak = 0
+		default:
+			summary.CurrentStreak++
+			summary.LongestStreak = max(summary.LongestStreak, summary.CurrentStreak)
+		}
+
+		summary.Doses++
+		summary.HourOfDay[t.In(regimen.Location()).Hour()]++
+		analyzed = append(analyzed, dose)
+	}
+
+	// Count the doses missed since the last dose. A dose counts as missed
+	// once the dose after it is due, so the one currently due doesn't unless
+	// its late window is over.
+	if i := lastIndexBefore(sorted, to); i != -1 {
+		dueAt, missed, skipped := countMissed(regimen, sorted[i], to, pauses, skips, inRange)
+		if late := regimen.LateWindow.AsDuration(); late > 0 && !dueAt.IsZero() && inRange(dueAt) && to.Sub(dueAt) > late {
+			missed++
+		}
+		summary.Missed += missed
+		summary.Skipped += skipped
+		if missed > 0 {
+			summary.CurrentStreak = 0
+		}
+	}
+
+	summary.Intervals = newDurationStats(intervals)
+	summary.Lateness = newDurationStats(lateness)
+
+	return analyzed, summary
+}
+
+// Compare compares the dose taken at t against the regimen, given the time of
+// the dose before it and the pauses and skips of the regimen. prev is zero if
+// t is the first dose. Unlike Analyze, all doses missed between prev and t are
+// counted.
+func Compare(regimen hrtclicker.HRTConfig, pauses []Pause, skips []time.Time, prev, t time.Time) Dose {
+	return compare(regimen, prev, t, pauses, skips, func(time.Time) bool { return true })
+}
+
+func compare(regimen hrtclicker.HRTConfig, prev, t time.Time, pauses []Pause, skips []time.Time, counted func(time.Time) bool) Dose {
+	dose := Dose{DosageAt: t, Status: StatusFirst}
+	for _, p := range pauses {
+		if p.Contains(t) {
+			dose.Status = StatusPaused
+			return dose
+		}
+	}
+	if prev.IsZero() {
+		return dose
+	}
+
+	dose.Interval = t.Sub(prev)
+	dose.DueAt, dose.Missed, dose.Skipped = countMissed(regimen, prev, t, pauses, skips, counted)
+	dose.Lateness = t.Sub(dose.DueAt)
+
+	onTime, late := regimen.OnTimeWithin(), regimen.LateWindow.AsDuration()
+	switch {
+	case late > 0 && dose.Lateness > late:
+		dose.Status = StatusMissed
+	case dose.Lateness > onTime:
+		dose.Status = StatusLate
+	case dose.Lateness < -onTime:
+		dose.Status = StatusEarly
+	default:
+		dose.Status = StatusOnTime
+	}
+	return dose
+}
+
+// countMissed counts the doses that were missed or skipped after the dose at
+// prev and before the given time, and returns the due time of the dose after
+// them. Only doses due at times for which counted returns true are counted.
+// A dose is pending until the next one is due, so the pauses starting until
+// then move it to their end, and no doses are missed once a pause without an
+// end starts. A skipped dose is not pending at all, so the dose after it is
+// due as if it was taken when it was due.
+func countMissed(regimen hrtclicker.HRTConfig, prev, before time.Time, pauses []Pause, skips []time.Time, counted func(time.Time) bool) (dueAt time.Time, missed, skipped int) {
+	dueAt = regimen.NextDoseAt(prev)
+	if !dueAt.After(prev) {
+		return dueAt, 0, 0
+	}
+	onTime := regimen.OnTimeWithin()
+	for {
+		var ok bool
+		dueAt, ok = AfterPauses(pauses, dueAt, minTime(before, regimen.NextDoseAt(dueAt)))
+		if !ok {
+			return dueAt, missed, skipped
+		}
+		if skip, ok := skippedAt(regimen, skips, dueAt); ok && skip.Before(before) {
+			if counted(dueAt) {
+				skipped++
+			}
+			dueAt = regimen.NextDoseAt(skip)
+			continue
+		}
+		next := regimen.NextDoseAt(dueAt)
+		if !next.After(dueAt) || !next.Add(-onTime).Before(before) {
+			return dueAt, missed, skipped
+		}
+		if counted(dueAt) {
+			missed++
+		}
+		dueAt = next
+	}
+}
+
+// skippedAt returns the skip of the dose due at dueAt, if any. A skip counts
+// for the dose due closest to it, within half an interval, since the due time
+// it was recorded for may have been moved by a trip.
+func skippedAt(regimen hrtclicker.HRTConfig, skips []time.Time, dueAt time.Time) (time.Time, bool) {
+	within := regimen.At(dueAt).Interval.AsDuration() / 2
+	for _, skip := range skips {
+		if skip.Sub(dueAt).Abs() < within {
+			return skip, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// breakAfter returns true if the dose after the one at prev is due after a
+// break of the cycle of the regimen.
+func breakAfter(regimen hrtclicker.HRTConfig, prev time.Time) bool {
+	interval := regimen.At(prev).Interval.AsDuration()
+	return regimen.NextDoseAt(prev).Sub(prev) > interval
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// lastIndexBefore returns the index of the last time in sorted that is not
+// after t, or -1 if there is none.
+func lastIndexBefore(sorted []time.Time, t time.Time) int {
+	i, found := slices.BinarySearchFunc(sorted, t, time.Time.Compare)
+	if found {
+		// Skip to the last of equal times.
+		for i+1 < len(sorted) && sorted[i+1].Equal(t) {
+			i++
+		}
+		return i
+	}
+	return i - 1
+}
@@ -0,0 +1,255 @@
+package adherence
+
+import (
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		lateWindow time.Duration
+		pauses     []Pause
+		skips      []time.Time
+		prev, at   time.Time
+		want       Dose
+	}{
+		{
+			name: "first dose",
+			at:   date(1, 8, 0),
+			want: Dose{Status: StatusFirst},
+		},
+		{
+			name: "on time",
+			prev: date(1, 8, 0),
+			at:   date(2, 8, 30),
+			want: Dose{Status: StatusOnTime, DueAt: date(2, 8, 0), Lateness: 30 * time.Minute},
+		},
+		{
+			name: "early",
+			prev: date(1, 8, 0),
+			at:   date(2, 6, 0),
+			want: Dose{Status: StatusEarly, DueAt: date(2, 8, 0), Lateness: -2 * time.Hour},
+		},
+		{
+			name: "late",
+			prev: date(1, 8, 0),
+			at:   date(2, 11, 0),
+			want: Dose{Status: StatusLate, DueAt: date(2, 8, 0), Lateness: 3 * time.Hour},
+		},
+		{
+			name:       "after the late window",
+			lateWindow: 6 * time.Hour,
+			prev:       date(1, 8, 0),
+			at:         date(2, 15, 0),
+			want:       Dose{Status: StatusMissed, DueAt: date(2, 8, 0), Lateness: 7 * time.Hour},
+		},
+		{
+			name: "missed doses before",
+			prev: date(1, 8, 0),
+			at:   date(4, 8, 10),
+			want: Dose{Status: StatusOnTime, DueAt: date(4, 8, 0), Lateness: 10 * time.Minute, Missed: 2},
+		},
+		{
+			name:  "skipped dose before",
+			skips: []time.Time{date(2, 8, 0)},
+			prev:  date(1, 8, 0),
+			at:    date(3, 8, 0),
+			want:  Dose{Status: StatusOnTime, DueAt: date(3, 8, 0), Skipped: 1},
+		},
+		{
+			name:   "taken while paused",
+			pauses: []Pause{{StartedAt: date(2, 0, 0), EndedAt: date(3, 0, 0)}},
+			prev:   date(1, 8, 0),
+			at:     date(2, 8, 0),
+			want:   Dose{Status: StatusPaused},
+		},
+		{
+			name:   "due at the end of a pause",
+			pauses: []Pause{{StartedAt: date(2, 0, 0), EndedAt: date(2, 12, 0)}},
+			prev:   date(1, 8, 0),
+			at:     date(2, 12, 30),
+			want:   Dose{Status: StatusOnTime, DueAt: date(2, 12, 0), Lateness: 30 * time.Minute},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			regimen := hrttest.Daily
+			regimen.LateWindow = cfgtypes.Duration(test.lateWindow)
+
+			want := test.want
+			want.DosageAt = test.at
+			if !test.prev.IsZero() && want.Status != StatusPaused {
+				want.Interval = test.at.Sub(test.prev)
+			}
+
+			got := Compare(regimen, test.pauses, test.skips, test.prev, test.at)
+			if got != want {
+				t.Errorf("Compare() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	// counts are the counts of a Summary that are compared.
+	type counts struct {
+		Doses, OnTime, Early, Late, Missed, Skipped, Paused int
+		CurrentStreak, LongestStreak                        int
+	}
+
+	tests := []struct {
+		name       string
+		lateWindow time.Duration
+		doses      []time.Time
+		pauses     []Pause
+		skips      []time.Time
+		from, to   time.Time
+		want       counts
+	}{
+		{
+			name:  "every status",
+			doses: []time.Time{date(5, 11, 0), date(1, 8, 0), date(3, 11, 0), date(2, 8, 20)},
+			from:  date(1, 0, 0),
+			to:    date(6, 0, 0),
+			want:  counts{Doses: 4, OnTime: 2, Late: 1, Missed: 1, CurrentStreak: 1, LongestStreak: 2},
+		},
+		{
+			name:  "doses before the range",
+			doses: []time.Time{date(1, 8, 0), date(2, 8, 20), date(3, 11, 0), date(5, 11, 0)},
+			from:  date(3, 0, 0),
+			to:    date(6, 0, 0),
+			want:  counts{Doses: 2, OnTime: 1, Late: 1, Missed: 1, CurrentStreak: 1, LongestStreak: 1},
+		},
+		{
+			name:  "missed since the last dose",
+			doses: []time.Time{date(1, 8, 0)},
+			from:  date(1, 0, 0),
+			to:    date(4, 9, 0),
+			want:  counts{Doses: 1, Missed: 2, LongestStreak: 1},
+		},
+		{
+			name:       "current dose after its late window",
+			lateWindow: 30 * time.Minute,
+			doses:      []time.Time{date(1, 8, 0)},
+			from:       date(1, 0, 0),
+			to:         date(4, 9, 0),
+			want:       counts{Doses: 1, Missed: 3, LongestStreak: 1},
+		},
+		{
+			name:  "skipped",
+			doses: []time.Time{date(1, 8, 0), date(3, 8, 0)},
+			skips: []time.Time{date(2, 8, 0)},
+			from:  date(1, 0, 0),
+			to:    date(3, 12, 0),
+			want:  counts{Doses: 2, OnTime: 1, Skipped: 1, CurrentStreak: 2, LongestStreak: 2},
+		},
+		{
+			name:   "paused",
+			doses:  []time.Time{date(1, 8, 0), date(2, 8, 0), date(5, 8, 0)},
+			pauses: []Pause{{StartedAt: date(2, 0, 0)}},
+			from:   date(1, 0, 0),
+			to:     date(6, 0, 0),
+			want:   counts{Doses: 3, Paused: 2, CurrentStreak: 1, LongestStreak: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			regimen := hrttest.Daily
+			regimen.LateWindow = cfgtypes.Duration(test.lateWindow)
+
+			doses, summary := Analyze(regimen, test.doses, test.pauses, test.skips, test.from, test.to)
+			if len(doses) != summary.Doses {
+				t.Errorf("Analyze() returned %d doses, want %d", len(doses), summary.Doses)
+			}
+
+			got := counts{
+				Doses:         summary.Doses,
+				OnTime:        summary.OnTime,
+				Early:         summary.Early,
+				Late:          summary.Late,
+				Missed:        summary.Missed,
+				Skipped:       summary.Skipped,
+				Paused:        summary.Paused,
+				CurrentStreak: summary.CurrentStreak,
+				LongestStreak: summary.LongestStreak,
+			}
+			if got != test.want {
+				t.Errorf("Analyze() counts = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeHourOfDay(t *testing.T) {
+	regimen := hrttest.Daily
+	regimen.TimeZone = "Europe/Berlin"
+	if _, err := time.LoadLocation(regimen.TimeZone); err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	// Summer time starts in Berlin on 2026-03-29.
+	doses := []time.Time{date(28, 7, 0), date(29, 6, 0), date(30, 6, 0)}
+	_, summary := Analyze(regimen, doses, nil, nil, date(28, 0, 0), date(31, 0, 0))
+
+	want := [24]int{8: 3}
+	if summary.HourOfDay != want {
+		t.Errorf("HourOfDay = %v, want %v", summary.HourOfDay, want)
+	}
+}
+
+func TestOnTimeRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary Summary
+		want    float64
+	}{
+		{"nothing due", Summary{Paused: 3}, 0},
+		{"all on time", Summary{OnTime: 4}, 1},
+		{"mixed", Summary{OnTime: 2, Early: 1, Late: 0, Missed: 1}, 0.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.summary.OnTimeRatio(); got != test.want {
+				t.Errorf("OnTimeRatio() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAfterPauses(t *testing.T) {
+	pauses := []Pause{
+		{StartedAt: date(2, 0, 0), EndedAt: date(3, 0, 0)},
+		{StartedAt: date(5, 0, 0)},
+	}
+
+	tests := []struct {
+		name        string
+		at, pending time.Time
+		want        time.Time
+		wantOK      bool
+	}{
+		{"before the pauses", date(1, 8, 0), date(1, 20, 0), date(1, 8, 0), true},
+		{"pending when a pause starts", date(1, 8, 0), date(2, 8, 0), date(3, 0, 0), true},
+		{"within a pause", date(2, 8, 0), date(3, 8, 0), date(3, 0, 0), true},
+		{"between the pauses", date(3, 8, 0), date(4, 8, 0), date(3, 8, 0), true},
+		{"within a pause without an end", date(5, 8, 0), date(6, 8, 0), time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := AfterPauses(pauses, test.at, test.pending)
+			if !got.Equal(test.want) || ok != test.wantOK {
+				t.Errorf("AfterPauses(%s, %s) = %s, %v, want %s, %v", test.at, test.pending, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package archive exports the database into files and imports them back.
+//
+// Doses can be exported as CSV or JSON Lines for use in other programs, or
+// every table of the database can be exported as a JSON Archive that can be
+// imported into another hrtclicker database. Importing an Archive only adds
+// the records that the database doesn't have yet, and gives them new IDs.
+package archive
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+// Format is a file format that can be exported and imported.
+type Format string
+
+const (
+	// FormatCSV is a CSV file of doses with a header row. The columns are
+	// dosage_at, hrt_type, notes and tags, with the tags comma-separated.
+	// Only dosage_at is required when importing.
+	FormatCSV Format = "csv"
+	// FormatJSONLines is a file of doses with one JSON object per line, in the
+	// same form as /dosages.json.
+	FormatJSONLines Format = "jsonl"
+	// FormatJSON is an Archive.
+	FormatJSON Format = "json"
+)
+
+// Formats lists all formats.
+var Formats = []Format{FormatCSV, FormatJSONLines, FormatJSON}
+
+// ParseFormat parses the name of a format. It also accepts file extensions.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.TrimPrefix(strings.ToLower(s), ".")); f {
+	case FormatCSV, FormatJSONLines, FormatJSON:
+		return f, nil
+	case "ndjson":
+		return FormatJSONLines, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, must be csv, jsonl or json", s)
+	}
+}
+
+// FormatFromFilename returns the format of the file with the given name
+// based on its extension.
+func FormatFromFilename(name string) (Format, error) {
+	ext := path.Ext(name)
+	if ext == "" {
+		return "", fmt.Errorf("cannot tell the format of %q without an extension", name)
+	}
+	return ParseFormat(ext)
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONLines:
+		return "application/jsonl"
+	default:
+		return "application/json"
+	}
+}
+
+// Filename returns the name of an export in the format made at the given time.
+func (f Format) Filename(t time.Time) string {
+	return "hrtclicker-" + t.Format("2006-01-02") + "." + string(f)
+}
+
+// ArchiveVersion is the version of the Archive format written by Export.
+// Version 1 only has the doses and notifications, and can still be imported.
+const ArchiveVersion = 2
+
+// Archive is the full export of the database.
+type Archive struct {
+	Version    int
+	ExportedAt time.Time
+	// Config is a snapshot of the configuration at the time of the export
+	// with its secrets removed. It is not imported.
+	Config        *hrtclicker.Config
+	Doses         []db.HRTHistory
+	Notifications []Notification
+	// OverdueNotifications records the overdue reminders that were sent, in
+	// the same way as Notifications.
+	OverdueNotifications []Notification
+	Snoozes              []db.Snoozed
+	WebhookDeliveries    []db.WebhookDelivery
+	// Stock is the inventory. The IDs of its entries are only used to refer
+	// to them within the archive, and change when it is imported.
+	Stock               []db.Stock
+	StockUsage          []db.StockUsage
+	RefillNotifications []db.RefillNotified
+	// Prescriptions are referred to by their IDs in the same way as Stock.
+	Prescriptions             []db.Prescription
+	PrescriptionNotifications []db.PrescriptionNotified
+	// LabAppointments are referred to by their IDs in the same way as Stock.
+	LabAppointments  []db.LabAppointment
+	LabResults       []db.LabResult
+	LabNotifications []db.LabNotified
+	// JournalTags refer to the IDs of JournalEntries.
+	JournalEntries       []db.JournalEntry
+	JournalTags          []db.JournalTag
+	Removals             []db.Removal
+	RemovalNotifications []db.RemovalNotified
+	Pauses               []db.Pause
+	Trips                []db.Trip
+	Skips                []db.Skip
+	// MissedNotifications records the missed dose reminders that were sent,
+	// in the same way as Notifications.
+	MissedNotifications []Notification
+}
+
+// Notification records that the reminder for the dose after the one at
+// DosageAt was sent.
+type Notification struct {
+	DosageAt time.Time
+	// NotifiedAt is zero if it is not known.
+	NotifiedAt time.Time
+}
+
+// Export writes all doses of all types, oldest first, to w in the given
+// format. cfg is only used for the Archive format.
+func Export(ctx context.Context, database *db.SQLiteDB, cfg *hrtclicker.Config, format Format, w io.Writer) error {
+	doses, err := database.AllDoses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get doses: %w", err)
+	}
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"dosage_at", "hrt_type", "notes", "tags", "zone"})
+		for _, dose := range doses {
+			cw.Write([]string{
+				dose.DosageAt.UTC().Format(time.RFC3339),
+				dose.HRTType,
+				dose.Notes,
+				dose.Tags.String(),
+				dose.Zone,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case FormatJSONLines:
+		enc := json.NewEncoder(w)
+		for _, dose := range doses {
+			if err := enc.Encode(dose); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatJSON:
+		archive := Archive{
+			Version:    ArchiveVersion,
+			ExportedAt: time.Now().UTC().Truncate(time.Second),
+			Config:     cfg.Redacted(),
+			Doses:      doses,
+		}
+		if err := exportRecords(ctx, database, &archive); err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(archive)
+
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -0,0 +1,446 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+// day is the day the records of the test database are on.
+var day = time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC)
+
+// seed fills the database with a record of every table.
+func seed(t *testing.T, database *db.SQLiteDB) {
+	t.Helper()
+	ctx := context.Background()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+
+	for _, hour := range []int{8, 16} {
+		must(database.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: at(hour),
+			HRTType:  string(hrtclicker.TypeSublingual),
+			Notes:    "with breakfast",
+			Tags:     db.NewTags("new-brand"),
+			Zone:     "Europe/Berlin",
+		}))
+	}
+
+	_, err := database.ImportNotification(ctx, db.ImportNotificationParams{
+		DosageAt:   at(8),
+		NotifiedAt: sql.NullTime{Time: at(16), Valid: true},
+	})
+	must(err)
+
+	_, err = database.ImportOverdueNotification(ctx, db.ImportOverdueNotificationParams{
+		DosageAt:   at(8),
+		NotifiedAt: sql.NullTime{Time: at(17), Valid: true},
+	})
+	must(err)
+
+	must(database.Snooze(ctx, db.SnoozeParams{
+		DosageAt:     at(16),
+		SnoozedUntil: at(25),
+	}))
+
+	must(database.LogWebhookDelivery(ctx, db.LogWebhookDeliveryParams{
+		EventID:     "01J0000000000000000000000",
+		EventType:   "dose.recorded",
+		Url:         "https://example.com/hook",
+		Attempt:     1,
+		StatusCode:  sql.NullInt64{Int64: 500, Valid: true},
+		Error:       sql.NullString{String: "server error", Valid: true},
+		AttemptedAt: at(8),
+	}))
+
+	prescription, err := database.AddPrescription(ctx, db.AddPrescriptionParams{
+		HRTType:    string(hrtclicker.TypeSublingual),
+		Prescriber: "Dr. Example",
+		Medication: "estradiol 2 mg",
+		Strength:   "2 mg",
+		Refills:    2,
+		WrittenAt:  day.AddDate(0, -1, 0),
+		ExpiresAt:  day.AddDate(1, -1, 0),
+		AddedAt:    at(6),
+	})
+	must(err)
+	must(database.MarkPrescriptionNotified(ctx, db.MarkPrescriptionNotifiedParams{
+		PrescriptionID: prescription.ID,
+		Reason:         "no_refills",
+	}))
+
+	stock, err := database.AddStock(ctx, db.AddStockParams{
+		HRTType:        string(hrtclicker.TypeSublingual),
+		Medication:     "estradiol 2 mg",
+		Quantity:       30,
+		Remaining:      30,
+		Lot:            "A123",
+		ExpiresAt:      sql.NullTime{Time: day.AddDate(1, 0, 0), Valid: true},
+		AddedAt:        at(7),
+		PrescriptionID: sql.NullInt64{Int64: prescription.ID, Valid: true},
+	})
+	must(err)
+	for _, hour := range []int{8, 16} {
+		must(database.UseStock(ctx, db.UseStockParams{
+			DosageAt: at(hour),
+			StockID:  stock.ID,
+			Amount:   1,
+		}))
+	}
+	must(database.MarkRefillNotified(ctx, stock.ID))
+
+	appointment, err := database.AddLabAppointment(ctx, db.AddLabAppointmentParams{
+		HRTType:     string(hrtclicker.TypeSublingual),
+		Target:      "trough",
+		ScheduledAt: at(7),
+		CreatedAt:   at(-48),
+	})
+	must(err)
+	_, err = database.AddLabResult(ctx, db.AddLabResultParams{
+		HRTType:       string(hrtclicker.TypeSublingual),
+		DrawnAt:       at(7),
+		Value:         142.5,
+		AppointmentID: sql.NullInt64{Int64: appointment.ID, Valid: true},
+		AddedAt:       at(12),
+	})
+	must(err)
+	must(database.MarkLabNotified(ctx, appointment.ID))
+
+	entry, err := database.AddJournalEntry(ctx, db.AddJournalEntryParams{
+		HRTType:   string(hrtclicker.TypeSublingual),
+		WrittenAt: at(20),
+		Mood:      sql.NullInt64{Int64: 4, Valid: true},
+		Notes:     "slept well",
+		AddedAt:   at(20),
+	})
+	must(err)
+	for _, tag := range []string{"fatigue", "hot flashes"} {
+		must(database.AddJournalTag(ctx, db.AddJournalTagParams{EntryID: entry.ID, Tag: tag}))
+	}
+
+	_, err = database.AddRemoval(ctx, db.AddRemovalParams{
+		DosageAt:  at(8),
+		HRTType:   string(hrtclicker.TypeSublingual),
+		RemovedAt: at(11),
+		Reason:    "fell off",
+		AddedAt:   at(12),
+	})
+	must(err)
+	must(database.MarkRemovalNotified(ctx, at(8)))
+
+	_, err = database.AddPause(ctx, db.AddPauseParams{
+		HRTType:   string(hrtclicker.TypeSublingual),
+		StartedAt: at(24),
+		EndedAt:   sql.NullTime{Time: at(24 * 8), Valid: true},
+		Reason:    "surgery",
+		AddedAt:   at(12),
+	})
+	must(err)
+
+	_, err = database.AddTrip(ctx, db.AddTripParams{
+		HRTType:         string(hrtclicker.TypeSublingual),
+		DepartureZone:   "Europe/Berlin",
+		DestinationZone: "America/New_York",
+		DepartsAt:       at(24 * 10),
+		Note:            "conference",
+		AddedAt:         at(12),
+	})
+	must(err)
+
+	_, err = database.AddSkip(ctx, db.AddSkipParams{
+		HRTType:   string(hrtclicker.TypeSublingual),
+		DueAt:     at(0),
+		SkippedAt: at(6),
+		Reason:    "missed",
+	})
+	must(err)
+	must(database.MarkMissed(ctx, at(16)))
+}
+
+// export exports the database as an Archive.
+func export(t *testing.T, database *db.SQLiteDB) Archive {
+	t.Helper()
+
+	cfg := &hrtclicker.Config{}
+	cfg.HRT.Type = hrtclicker.TypeSublingual
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), database, cfg, FormatJSON, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive Archive
+	if err := json.Unmarshal(buf.Bytes(), &archive); err != nil {
+		t.Fatal(err)
+	}
+	archive.ExportedAt = time.Time{}
+	return archive
+}
+
+func importArchive(t *testing.T, database *db.SQLiteDB, archive Archive, dryRun bool) ImportResult {
+	t.Helper()
+
+	b, err := json.Marshal(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Import(context.Background(), database, bytes.NewReader(b), ImportOptions{
+		Format: FormatJSON,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	from := hrttest.OpenDB(t)
+	seed(t, from)
+	archive := export(t, from)
+
+	if archive.Version != ArchiveVersion {
+		t.Errorf("Version = %d, want %d", archive.Version, ArchiveVersion)
+	}
+
+	// Every table has a record, so an empty field means that a table is
+	// missing from the archive.
+	v := reflect.ValueOf(archive)
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if field.Kind() == reflect.Slice && field.Len() == 0 {
+			t.Errorf("Archive.%s is empty", v.Type().Field(i).Name)
+		}
+	}
+
+	to := hrttest.OpenDB(t)
+
+	dryRun := importArchive(t, to, archive, true)
+	if got := export(t, to); len(got.Doses) != 0 {
+		t.Errorf("dry run imported %d doses", len(got.Doses))
+	}
+
+	result := importArchive(t, to, archive, false)
+	if !reflect.DeepEqual(result.RecordsAdded, dryRun.RecordsAdded) {
+		t.Errorf("RecordsAdded = %v, want %v as in the dry run", result.RecordsAdded, dryRun.RecordsAdded)
+	}
+
+	got := export(t, to)
+	if !reflect.DeepEqual(got, archive) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		wantJSON, _ := json.MarshalIndent(archive, "", "  ")
+		t.Errorf("imported archive differs:\ngot:  %s\nwant: %s", gotJSON, wantJSON)
+	}
+
+	// Importing it again adds nothing.
+	again := importArchive(t, to, archive, false)
+	if len(again.Added) != 0 || len(again.RecordsAdded) != 0 {
+		t.Errorf("importing again added %d doses and %v", len(again.Added), again.RecordsAdded)
+	}
+	if got := export(t, to); !reflect.DeepEqual(got, archive) {
+		t.Errorf("importing again changed the database")
+	}
+}
+
+func TestImportArchiveVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		wantErr string
+	}{
+		{
+			name: "version 1",
+			archive: `{
+				"Version": 1,
+				"Doses": [{"DosageAt": "2026-03-28T08:00:00Z", "HRTType": "sublingual"}],
+				"Notifications": [{"DosageAt": "2026-03-28T08:00:00Z", "NotifiedAt": "2026-03-28T16:00:00Z"}]
+			}`,
+		},
+		{
+			name:    "newer version",
+			archive: `{"Version": 1000}`,
+			wantErr: "unsupported archive version 1000",
+		},
+		{
+			name:    "missing record time",
+			archive: `{"Version": 2, "Snoozes": [{"SnoozedUntil": "2026-03-28T08:00:00Z"}]}`,
+			wantErr: "Snoozes[0]",
+		},
+		{
+			name:    "unknown stock",
+			archive: `{"Version": 2, "StockUsage": [{"DosageAt": "2026-03-28T08:00:00Z", "StockID": 7, "Amount": 1}]}`,
+			wantErr: "StockUsage[0]: unknown stock ID 7",
+		},
+		{
+			name:    "unknown prescription",
+			archive: `{"Version": 2, "Stock": [{"ID": 1, "HRTType": "gel", "AddedAt": "2026-03-28T08:00:00Z", "PrescriptionID": {"Int64": 3, "Valid": true}}]}`,
+			wantErr: "Stock[0]: unknown prescription ID 3",
+		},
+		{
+			name:    "unknown lab appointment",
+			archive: `{"Version": 2, "LabNotifications": [{"AppointmentID": 4}]}`,
+			wantErr: "LabNotifications[0]: unknown appointment ID 4",
+		},
+		{
+			name:    "invalid mood",
+			archive: `{"Version": 2, "JournalEntries": [{"ID": 1, "HRTType": "gel", "WrittenAt": "2026-03-28T20:00:00Z", "Mood": {"Int64": 9, "Valid": true}, "AddedAt": "2026-03-28T20:00:00Z"}]}`,
+			wantErr: "JournalEntries[0]: mood 9 is not from 1 to 5",
+		},
+		{
+			name:    "pause ending before it starts",
+			archive: `{"Version": 2, "Pauses": [{"HRTType": "gel", "StartedAt": "2026-03-28T08:00:00Z", "EndedAt": {"Time": "2026-03-27T08:00:00Z", "Valid": true}, "AddedAt": "2026-03-28T08:00:00Z"}]}`,
+			wantErr: "Pauses[0]: ends before it starts",
+		},
+		{
+			name:    "unknown trip zone",
+			archive: `{"Version": 2, "Trips": [{"HRTType": "gel", "DepartureZone": "Nowhere/Else", "DestinationZone": "UTC", "DepartsAt": "2026-03-28T08:00:00Z", "AddedAt": "2026-03-28T08:00:00Z"}]}`,
+			wantErr: `Trips[0]: unknown departure zone "Nowhere/Else"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Import(context.Background(), hrttest.OpenDB(t), strings.NewReader(test.archive), ImportOptions{
+				Format: FormatJSON,
+			})
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Fatalf("Import() = %v", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Fatalf("Import() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+	if err := database.RecordDosage(ctx, db.RecordDosageParams{
+		DosageAt: hrttest.Date(28, 8, 0),
+		HRTType:  string(hrtclicker.TypeGel),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	csv := strings.Join([]string{
+		"dosage_at,hrt_type,notes,tags",
+		`2026-03-27T08:00:00Z,sublingual, with breakfast ,"Nausea, fatigue"`,
+		"2026-03-27 20:00,",
+		"2026-03-27T20:00:00Z,sublingual",
+		"2026-03-28T08:00:00Z,sublingual",
+	}, "\n")
+
+	result, err := Import(ctx, database, strings.NewReader(csv), ImportOptions{
+		Format:      FormatCSV,
+		DefaultType: hrtclicker.TypeSublingual,
+		Location:    time.UTC,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Added) != 2 || !result.Added[0].DosageAt.Equal(hrttest.Date(27, 8, 0)) || !result.Added[1].DosageAt.Equal(hrttest.Date(27, 20, 0)) {
+		t.Errorf("Added = %v, want the doses on the 27th", result.Added)
+	}
+	if dose := result.Added[0]; dose.Notes != "with breakfast" || dose.Tags.String() != "fatigue, nausea" {
+		t.Errorf("Added[0] has notes %q and tags %q, want them trimmed and normalized", dose.Notes, dose.Tags)
+	}
+	if result.Duplicates != 1 {
+		t.Errorf("Duplicates = %d, want 1", result.Duplicates)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Existing.HRTType != string(hrtclicker.TypeGel) {
+		t.Errorf("Conflicts = %v, want the gel dose on the 28th", result.Conflicts)
+	}
+}
+
+func TestImportInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		file    string
+		wantErr string
+	}{
+		{
+			name:    "CSV without a time column",
+			format:  FormatCSV,
+			file:    "hrt_type\ngel\n",
+			wantErr: "no dosage_at column",
+		},
+		{
+			name:    "CSV without a type",
+			format:  FormatCSV,
+			file:    "dosage_at\n2026-03-28T08:00:00Z\n",
+			wantErr: "line 2: missing HRT type",
+		},
+		{
+			name:    "JSON Lines with an unknown type",
+			format:  FormatJSONLines,
+			file:    `{"DosageAt": "2026-03-28T08:00:00Z", "HRTType": "pill"}`,
+			wantErr: `line 1: unknown HRT type "pill"`,
+		},
+		{
+			name:    "unknown time zone",
+			format:  FormatJSONLines,
+			file:    `{"DosageAt": "2026-03-28T08:00:00Z", "HRTType": "gel", "Zone": "Nowhere/Else"}`,
+			wantErr: `line 1: unknown time zone "Nowhere/Else"`,
+		},
+		{
+			name:    "dose in the future",
+			format:  FormatJSONLines,
+			file:    `{"DosageAt": "3000-01-01T00:00:00Z", "HRTType": "gel"}`,
+			wantErr: "is in the future",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			database := hrttest.OpenDB(t)
+			_, err := Import(context.Background(), database, strings.NewReader(test.file), ImportOptions{
+				Format: test.format,
+			})
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Import() = %v, want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"csv", FormatCSV, false},
+		{".JSONL", FormatJSONLines, false},
+		{"ndjson", FormatJSONLines, false},
+		{"json", FormatJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := ParseFormat(test.in)
+			if got != test.want || (err != nil) != test.wantErr {
+				t.Errorf("ParseFormat(%q) = %q, %v, want %q", test.in, got, err, test.want)
+			}
+		})
+	}
+}
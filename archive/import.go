@@ -0,0 +1,386 @@
+package archive
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+// ImportOptions are the options for Import.
+type ImportOptions struct {
+	Format Format
+	// DefaultType is the HRT type of doses that don't have one, such as CSV
+	// files without an hrt_type column.
+	DefaultType hrtclicker.HRTType
+	// Location is the time zone of CSV times that don't have one, usually the
+	// one of the regimen. It defaults to time.Local.
+	Location *time.Location
+	// DryRun reports what would be imported without changing the database.
+	DryRun bool
+}
+
+// ImportResult reports the outcome of an Import.
+type ImportResult struct {
+	DryRun bool
+	// Added is the list of doses that were added.
+	Added []db.HRTHistory
+	// Duplicates is the number of doses that were skipped because they are
+	// already in the database or repeated in the file.
+	Duplicates int
+	// Conflicts is the list of doses that were skipped because a dose of
+	// another type exists at the same time.
+	Conflicts []Conflict
+	// RecordsAdded is the number of records other than doses added from an
+	// Archive by their kind, such as "notifications".
+	RecordsAdded map[string]int
+}
+
+func (r *ImportResult) addRecords(kind string, n int64) {
+	if n == 0 {
+		return
+	}
+	if r.RecordsAdded == nil {
+		r.RecordsAdded = make(map[string]int)
+	}
+	r.RecordsAdded[kind] += int(n)
+}
+
+// Conflict is an imported dose that conflicts with an existing one.
+type Conflict struct {
+	Imported db.HRTHistory
+	Existing db.HRTHistory
+}
+
+// errDryRun is returned within the transaction to roll back a dry run.
+var errDryRun = errors.New("dry run")
+
+// maxImportErrors is the maximum number of invalid records reported before
+// giving up on the rest of the file.
+const maxImportErrors = 20
+
+// Import reads doses from r and adds the ones that are not in the database
+// yet. Doses are deduplicated on their time and type. The file is validated
+// entirely before anything is imported, and the import happens in a single
+// transaction, so either everything or nothing is imported.
+//
+// Imported doses are not published as events, since they would all be old
+// news.
+func Import(ctx context.Context, database *db.SQLiteDB, r io.Reader, opts ImportOptions) (ImportResult, error) {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+
+	var doses []db.HRTHistory
+	var archive *Archive
+	var err error
+
+	switch opts.Format {
+	case FormatCSV:
+		doses, err = readCSV(r, opts)
+	case FormatJSONLines:
+		doses, err = readJSONLines(r, opts)
+	case FormatJSON:
+		archive, err = readArchive(r, opts)
+		if archive != nil {
+			doses = archive.Doses
+		}
+	default:
+		err = fmt.Errorf("unknown format %q", opts.Format)
+	}
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	// Sort so that the result lists doses in order, then do the rest of the
+	// work within the transaction.
+	slices.SortFunc(doses, func(a, b db.HRTHistory) int {
+		return a.DosageAt.Compare(b.DosageAt)
+	})
+
+	result := ImportResult{DryRun: opts.DryRun}
+
+	err = database.Tx(func(q *db.Queries) error {
+		for i, dose := range doses {
+			if i > 0 && dose.DosageAt.Equal(doses[i-1].DosageAt) && dose.HRTType == doses[i-1].HRTType {
+				result.Duplicates++
+				continue
+			}
+
+			existing, err := q.DoseAt(ctx, dose.DosageAt)
+			switch {
+			case err == nil && existing.HRTType == dose.HRTType:
+				result.Duplicates++
+				continue
+			case err == nil:
+				result.Conflicts = append(result.Conflicts, Conflict{
+					Imported: dose,
+					Existing: existing,
+				})
+				continue
+			case !db.IsNotFound(err):
+				return fmt.Errorf("failed to look up dose at %s: %w", dose.DosageAt, err)
+			}
+
+			if err := q.RecordDosage(ctx, db.RecordDosageParams{
+				DosageAt: dose.DosageAt,
+				HRTType:  dose.HRTType,
+				Notes:    dose.Notes,
+				Tags:     dose.Tags,
+				Zone:     dose.Zone,
+			}); err != nil {
+				if db.IsAlreadyExists(err) {
+					// Two doses of different types at the same time within
+					// the file.
+					result.Conflicts = append(result.Conflicts, Conflict{
+						Imported: dose,
+						Existing: doses[i-1],
+					})
+					continue
+				}
+				return fmt.Errorf("failed to add dose at %s: %w", dose.DosageAt, err)
+			}
+
+			result.Added = append(result.Added, dose)
+		}
+
+		if archive != nil {
+			if err := importRecords(ctx, q, archive, &result); err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+// importErrors collects the problems found in a file.
+type importErrors []error
+
+func (e *importErrors) add(line int, err error) {
+	*e = append(*e, fmt.Errorf("line %d: %w", line, err))
+}
+
+// full returns true if enough errors have been collected to give up.
+func (e importErrors) full() bool {
+	return len(e) >= maxImportErrors
+}
+
+func (e importErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	if e.full() {
+		e = append(e, errors.New("too many errors, giving up"))
+	}
+	return errors.Join(e...)
+}
+
+// validateDose checks and normalizes a dose read from a file.
+func validateDose(dose db.HRTHistory, opts ImportOptions) (db.HRTHistory, error) {
+	if dose.HRTType == "" {
+		dose.HRTType = string(opts.DefaultType)
+	}
+	if dose.HRTType == "" {
+		return dose, errors.New("missing HRT type")
+	}
+	if !hrtclicker.HRTType(dose.HRTType).IsValid() {
+		return dose, fmt.Errorf("unknown HRT type %q", dose.HRTType)
+	}
+
+	if dose.DosageAt.IsZero() {
+		return dose, errors.New("missing dosage time")
+	}
+	if dose.DosageAt.After(time.Now().Add(time.Minute)) {
+		return dose, fmt.Errorf("dosage time %s is in the future", dose.DosageAt.Format(time.RFC3339))
+	}
+
+	dose.Zone = strings.TrimSpace(dose.Zone)
+	if _, err := time.LoadLocation(dose.Zone); err != nil {
+		return dose, fmt.Errorf("unknown time zone %q", dose.Zone)
+	}
+
+	dose.DosageAt = dose.DosageAt.UTC().Truncate(time.Second)
+	dose.Notes = strings.TrimSpace(dose.Notes)
+	dose.Tags = db.NewTags(dose.Tags...)
+	return dose, nil
+}
+
+// csvTimeLayouts are the layouts accepted for times in CSV files, in the
+// order they are tried. Spreadsheets tend to not include the time zone.
+var csvTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+func parseCSVTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", s)
+}
+
+func readCSV(r io.Reader, opts ImportOptions) ([]db.HRTHistory, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	timeColumn, typeColumn, notesColumn, tagsColumn, zoneColumn := -1, -1, -1, -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "dosage_at":
+			timeColumn = i
+		case "hrt_type":
+			typeColumn = i
+		case "notes":
+			notesColumn = i
+		case "tags":
+			tagsColumn = i
+		case "zone":
+			zoneColumn = i
+		}
+	}
+	if timeColumn == -1 {
+		return nil, errors.New("CSV header has no dosage_at column")
+	}
+
+	var doses []db.HRTHistory
+	var errs importErrors
+
+	for !errs.full() {
+		record, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		if timeColumn >= len(record) {
+			errs.add(line, errors.New("missing dosage_at"))
+			continue
+		}
+
+		var dose db.HRTHistory
+		dose.DosageAt, err = parseCSVTime(record[timeColumn], opts.Location)
+		if err != nil {
+			errs.add(line, err)
+			continue
+		}
+		if typeColumn != -1 && typeColumn < len(record) {
+			dose.HRTType = strings.TrimSpace(record[typeColumn])
+		}
+		if notesColumn != -1 && notesColumn < len(record) {
+			dose.Notes = record[notesColumn]
+		}
+		if tagsColumn != -1 && tagsColumn < len(record) {
+			dose.Tags = db.ParseTags(record[tagsColumn])
+		}
+		if zoneColumn != -1 && zoneColumn < len(record) {
+			dose.Zone = record[zoneColumn]
+		}
+
+		dose, err = validateDose(dose, opts)
+		if err != nil {
+			errs.add(line, err)
+			continue
+		}
+
+		doses = append(doses, dose)
+	}
+
+	return doses, errs.err()
+}
+
+func readJSONLines(r io.Reader, opts ImportOptions) ([]db.HRTHistory, error) {
+	var doses []db.HRTHistory
+	var errs importErrors
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan() && !errs.full(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var dose db.HRTHistory
+		if err := json.Unmarshal(scanner.Bytes(), &dose); err != nil {
+			errs.add(line, err)
+			continue
+		}
+
+		dose, err := validateDose(dose, opts)
+		if err != nil {
+			errs.add(line, err)
+			continue
+		}
+
+		doses = append(doses, dose)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSON Lines: %w", err)
+	}
+
+	return doses, errs.err()
+}
+
+func readArchive(r io.Reader, opts ImportOptions) (*Archive, error) {
+	var archive Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("failed to decode archive: %w", err)
+	}
+
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", archive.Version)
+	}
+
+	var errs []error
+	for i, dose := range archive.Doses {
+		dose, err := validateDose(dose, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Doses[%d]: %w", i, err))
+			continue
+		}
+		archive.Doses[i] = dose
+	}
+
+	if err := validateRecords(&archive); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return &archive, nil
+}
@@ -0,0 +1,855 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/journal"
+)
+
+// exportRecords fills in the records of the archive other than the doses.
+func exportRecords(ctx context.Context, database *db.SQLiteDB, archive *Archive) error {
+	notified, err := database.Notifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notifications: %w", err)
+	}
+	for _, n := range notified {
+		archive.Notifications = append(archive.Notifications, notification(n.DosageAt, n.NotifiedAt))
+	}
+
+	overdue, err := database.OverdueNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get overdue notifications: %w", err)
+	}
+	for _, n := range overdue {
+		archive.OverdueNotifications = append(archive.OverdueNotifications, notification(n.DosageAt, n.NotifiedAt))
+	}
+
+	archive.Snoozes, err = database.Snoozes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get snoozes: %w", err)
+	}
+
+	archive.WebhookDeliveries, err = database.AllWebhookDeliveries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	archive.Stock, err = database.AllStock(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stock: %w", err)
+	}
+
+	archive.StockUsage, err = database.AllStockUsage(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stock usage: %w", err)
+	}
+
+	archive.RefillNotifications, err = database.RefillNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get refill notifications: %w", err)
+	}
+
+	archive.Prescriptions, err = database.AllPrescriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get prescriptions: %w", err)
+	}
+
+	archive.PrescriptionNotifications, err = database.PrescriptionNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get prescription notifications: %w", err)
+	}
+
+	archive.LabAppointments, err = database.AllLabAppointments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get lab appointments: %w", err)
+	}
+
+	archive.LabResults, err = database.AllLabResults(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get lab results: %w", err)
+	}
+
+	archive.LabNotifications, err = database.LabNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get lab notifications: %w", err)
+	}
+
+	archive.JournalEntries, err = database.AllJournalEntries(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get journal entries: %w", err)
+	}
+
+	archive.JournalTags, err = database.AllJournalTags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get journal tags: %w", err)
+	}
+
+	archive.Removals, err = database.AllRemovals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get removals: %w", err)
+	}
+
+	archive.RemovalNotifications, err = database.RemovalNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get removal notifications: %w", err)
+	}
+
+	archive.Pauses, err = database.AllPauses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get pauses: %w", err)
+	}
+
+	archive.Trips, err = database.AllTrips(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get trips: %w", err)
+	}
+
+	archive.Skips, err = database.AllSkips(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get skips: %w", err)
+	}
+
+	missed, err := database.MissedNotifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get missed dose notifications: %w", err)
+	}
+	for _, n := range missed {
+		archive.MissedNotifications = append(archive.MissedNotifications, notification(n.DosageAt, n.NotifiedAt))
+	}
+
+	return nil
+}
+
+func notification(dosageAt time.Time, notifiedAt sql.NullTime) Notification {
+	return Notification{
+		DosageAt:   dosageAt,
+		NotifiedAt: notifiedAt.Time,
+	}
+}
+
+// notifiedAt returns the time the notification was sent as it is stored.
+func (n Notification) notifiedAt() sql.NullTime {
+	return sql.NullTime{
+		Time:  n.NotifiedAt,
+		Valid: !n.NotifiedAt.IsZero(),
+	}
+}
+
+// validateRecords checks and normalizes the records of the archive other
+// than the doses.
+func validateRecords(archive *Archive) error {
+	var errs []error
+
+	for i, n := range archive.Notifications {
+		if n.DosageAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Notifications[%d]: missing dosage time", i))
+			continue
+		}
+		archive.Notifications[i] = n.normalize()
+	}
+
+	for i, n := range archive.OverdueNotifications {
+		if n.DosageAt.IsZero() {
+			errs = append(errs, fmt.Errorf("OverdueNotifications[%d]: missing dosage time", i))
+			continue
+		}
+		archive.OverdueNotifications[i] = n.normalize()
+	}
+
+	for i, s := range archive.Snoozes {
+		if s.DosageAt.IsZero() || s.SnoozedUntil.IsZero() {
+			errs = append(errs, fmt.Errorf("Snoozes[%d]: missing dosage or snooze time", i))
+			continue
+		}
+		archive.Snoozes[i] = db.Snoozed{
+			DosageAt:     normalizeTime(s.DosageAt),
+			SnoozedUntil: normalizeTime(s.SnoozedUntil),
+		}
+	}
+
+	for i, d := range archive.WebhookDeliveries {
+		if d.EventID == "" || d.Url == "" || d.AttemptedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("WebhookDeliveries[%d]: missing event ID, URL or attempt time", i))
+			continue
+		}
+		archive.WebhookDeliveries[i].AttemptedAt = d.AttemptedAt.UTC()
+	}
+
+	prescriptions := make(map[int64]bool, len(archive.Prescriptions))
+	for i, p := range archive.Prescriptions {
+		if err := validateType(p.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("Prescriptions[%d]: %w", i, err))
+			continue
+		}
+		if p.WrittenAt.IsZero() || p.ExpiresAt.IsZero() || p.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Prescriptions[%d]: missing time written, expiring or added", i))
+			continue
+		}
+		if prescriptions[p.ID] {
+			errs = append(errs, fmt.Errorf("Prescriptions[%d]: duplicate ID %d", i, p.ID))
+			continue
+		}
+		prescriptions[p.ID] = true
+
+		p.WrittenAt = normalizeTime(p.WrittenAt)
+		p.ExpiresAt = p.ExpiresAt.UTC()
+		p.AddedAt = normalizeTime(p.AddedAt)
+		archive.Prescriptions[i] = p
+	}
+
+	for i, n := range archive.PrescriptionNotifications {
+		if !prescriptions[n.PrescriptionID] {
+			errs = append(errs, fmt.Errorf("PrescriptionNotifications[%d]: unknown prescription ID %d", i, n.PrescriptionID))
+			continue
+		}
+		archive.PrescriptionNotifications[i].NotifiedAt.Time = n.NotifiedAt.Time.UTC()
+	}
+
+	stock := make(map[int64]bool, len(archive.Stock))
+	for i, entry := range archive.Stock {
+		if err := validateType(entry.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("Stock[%d]: %w", i, err))
+			continue
+		}
+		if entry.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Stock[%d]: missing time added", i))
+			continue
+		}
+		if stock[entry.ID] {
+			errs = append(errs, fmt.Errorf("Stock[%d]: duplicate ID %d", i, entry.ID))
+			continue
+		}
+		if entry.PrescriptionID.Valid && !prescriptions[entry.PrescriptionID.Int64] {
+			errs = append(errs, fmt.Errorf("Stock[%d]: unknown prescription ID %d", i, entry.PrescriptionID.Int64))
+			continue
+		}
+		stock[entry.ID] = true
+
+		entry.AddedAt = normalizeTime(entry.AddedAt)
+		entry.ExpiresAt.Time = entry.ExpiresAt.Time.UTC()
+		archive.Stock[i] = entry
+	}
+
+	for i, usage := range archive.StockUsage {
+		if usage.DosageAt.IsZero() {
+			errs = append(errs, fmt.Errorf("StockUsage[%d]: missing dosage time", i))
+			continue
+		}
+		if !stock[usage.StockID] {
+			errs = append(errs, fmt.Errorf("StockUsage[%d]: unknown stock ID %d", i, usage.StockID))
+			continue
+		}
+		archive.StockUsage[i].DosageAt = normalizeTime(usage.DosageAt)
+	}
+
+	for i, n := range archive.RefillNotifications {
+		if !stock[n.StockID] {
+			errs = append(errs, fmt.Errorf("RefillNotifications[%d]: unknown stock ID %d", i, n.StockID))
+			continue
+		}
+		archive.RefillNotifications[i].NotifiedAt.Time = n.NotifiedAt.Time.UTC()
+	}
+
+	appointments := make(map[int64]bool, len(archive.LabAppointments))
+	for i, a := range archive.LabAppointments {
+		if err := validateType(a.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("LabAppointments[%d]: %w", i, err))
+			continue
+		}
+		if a.ScheduledAt.IsZero() || a.CreatedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("LabAppointments[%d]: missing time scheduled or created", i))
+			continue
+		}
+		if appointments[a.ID] {
+			errs = append(errs, fmt.Errorf("LabAppointments[%d]: duplicate ID %d", i, a.ID))
+			continue
+		}
+		appointments[a.ID] = true
+
+		a.ScheduledAt = normalizeTime(a.ScheduledAt)
+		a.CreatedAt = normalizeTime(a.CreatedAt)
+		archive.LabAppointments[i] = a
+	}
+
+	for i, r := range archive.LabResults {
+		if err := validateType(r.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("LabResults[%d]: %w", i, err))
+			continue
+		}
+		if r.DrawnAt.IsZero() || r.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("LabResults[%d]: missing time drawn or added", i))
+			continue
+		}
+		if r.AppointmentID.Valid && !appointments[r.AppointmentID.Int64] {
+			errs = append(errs, fmt.Errorf("LabResults[%d]: unknown appointment ID %d", i, r.AppointmentID.Int64))
+			continue
+		}
+
+		r.DrawnAt = normalizeTime(r.DrawnAt)
+		r.AddedAt = normalizeTime(r.AddedAt)
+		archive.LabResults[i] = r
+	}
+
+	for i, n := range archive.LabNotifications {
+		if !appointments[n.AppointmentID] {
+			errs = append(errs, fmt.Errorf("LabNotifications[%d]: unknown appointment ID %d", i, n.AppointmentID))
+			continue
+		}
+		archive.LabNotifications[i].NotifiedAt.Time = n.NotifiedAt.Time.UTC()
+	}
+
+	entries := make(map[int64]bool, len(archive.JournalEntries))
+	for i, e := range archive.JournalEntries {
+		if err := validateType(e.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("JournalEntries[%d]: %w", i, err))
+			continue
+		}
+		if e.WrittenAt.IsZero() || e.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("JournalEntries[%d]: missing time written or added", i))
+			continue
+		}
+		if e.Mood.Valid && (e.Mood.Int64 < journal.MinMood || e.Mood.Int64 > journal.MaxMood) {
+			errs = append(errs, fmt.Errorf("JournalEntries[%d]: mood %d is not from %d to %d", i, e.Mood.Int64, journal.MinMood, journal.MaxMood))
+			continue
+		}
+		if entries[e.ID] {
+			errs = append(errs, fmt.Errorf("JournalEntries[%d]: duplicate ID %d", i, e.ID))
+			continue
+		}
+		entries[e.ID] = true
+
+		e.WrittenAt = normalizeTime(e.WrittenAt)
+		e.AddedAt = normalizeTime(e.AddedAt)
+		archive.JournalEntries[i] = e
+	}
+
+	for i, tag := range archive.JournalTags {
+		if !entries[tag.EntryID] {
+			errs = append(errs, fmt.Errorf("JournalTags[%d]: unknown entry ID %d", i, tag.EntryID))
+			continue
+		}
+		if tag.Tag == "" {
+			errs = append(errs, fmt.Errorf("JournalTags[%d]: missing tag", i))
+			continue
+		}
+	}
+
+	for i, r := range archive.Removals {
+		if err := validateType(r.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("Removals[%d]: %w", i, err))
+			continue
+		}
+		if r.DosageAt.IsZero() || r.RemovedAt.IsZero() || r.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Removals[%d]: missing dosage, removal or added time", i))
+			continue
+		}
+
+		r.DosageAt = normalizeTime(r.DosageAt)
+		r.RemovedAt = normalizeTime(r.RemovedAt)
+		r.AddedAt = normalizeTime(r.AddedAt)
+		archive.Removals[i] = r
+	}
+
+	for i, n := range archive.RemovalNotifications {
+		if n.DosageAt.IsZero() {
+			errs = append(errs, fmt.Errorf("RemovalNotifications[%d]: missing dosage time", i))
+			continue
+		}
+		archive.RemovalNotifications[i] = db.RemovalNotified{
+			DosageAt:   normalizeTime(n.DosageAt),
+			NotifiedAt: sql.NullTime{Time: n.NotifiedAt.Time.UTC(), Valid: n.NotifiedAt.Valid},
+		}
+	}
+
+	for i, p := range archive.Pauses {
+		if err := validateType(p.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("Pauses[%d]: %w", i, err))
+			continue
+		}
+		if p.StartedAt.IsZero() || p.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Pauses[%d]: missing start or added time", i))
+			continue
+		}
+		if p.EndedAt.Valid && !p.EndedAt.Time.After(p.StartedAt) {
+			errs = append(errs, fmt.Errorf("Pauses[%d]: ends before it starts", i))
+			continue
+		}
+
+		p.StartedAt = normalizeTime(p.StartedAt)
+		p.EndedAt.Time = p.EndedAt.Time.UTC()
+		p.AddedAt = normalizeTime(p.AddedAt)
+		archive.Pauses[i] = p
+	}
+
+	for i, trip := range archive.Trips {
+		if err := validateType(trip.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("Trips[%d]: %w", i, err))
+			continue
+		}
+		if trip.DepartsAt.IsZero() || trip.AddedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Trips[%d]: missing departure or added time", i))
+			continue
+		}
+		if _, err := time.LoadLocation(trip.DepartureZone); err != nil {
+			errs = append(errs, fmt.Errorf("Trips[%d]: unknown departure zone %q", i, trip.DepartureZone))
+			continue
+		}
+		if _, err := time.LoadLocation(trip.DestinationZone); err != nil {
+			errs = append(errs, fmt.Errorf("Trips[%d]: unknown destination zone %q", i, trip.DestinationZone))
+			continue
+		}
+
+		trip.DepartsAt = normalizeTime(trip.DepartsAt)
+		trip.AddedAt = normalizeTime(trip.AddedAt)
+		archive.Trips[i] = trip
+	}
+
+	for i, skip := range archive.Skips {
+		if err := validateType(skip.HRTType); err != nil {
+			errs = append(errs, fmt.Errorf("Skips[%d]: %w", i, err))
+			continue
+		}
+		if skip.DueAt.IsZero() || skip.SkippedAt.IsZero() {
+			errs = append(errs, fmt.Errorf("Skips[%d]: missing due or skipped time", i))
+			continue
+		}
+
+		skip.DueAt = normalizeTime(skip.DueAt)
+		skip.SkippedAt = normalizeTime(skip.SkippedAt)
+		archive.Skips[i] = skip
+	}
+
+	for i, n := range archive.MissedNotifications {
+		if n.DosageAt.IsZero() {
+			errs = append(errs, fmt.Errorf("MissedNotifications[%d]: missing dosage time", i))
+			continue
+		}
+		archive.MissedNotifications[i] = n.normalize()
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateType checks the HRT type of a record.
+func validateType(t string) error {
+	if !hrtclicker.HRTType(t).IsValid() {
+		return fmt.Errorf("unknown HRT type %q", t)
+	}
+	return nil
+}
+
+func (n Notification) normalize() Notification {
+	return Notification{
+		DosageAt:   normalizeTime(n.DosageAt),
+		NotifiedAt: n.NotifiedAt.UTC(),
+	}
+}
+
+// normalizeTime returns t as it is stored for the keys of records, which are
+// compared for equality.
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// importRecords adds the records of the archive other than the doses that are
+// not in the database yet, and counts them in the result.
+func importRecords(ctx context.Context, q *db.Queries, archive *Archive, result *ImportResult) error {
+	for _, n := range archive.Notifications {
+		added, err := q.ImportNotification(ctx, db.ImportNotificationParams{
+			DosageAt:   n.DosageAt,
+			NotifiedAt: n.notifiedAt(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add notification for %s: %w", n.DosageAt, err)
+		}
+		result.addRecords("notifications", added)
+	}
+
+	for _, n := range archive.OverdueNotifications {
+		added, err := q.ImportOverdueNotification(ctx, db.ImportOverdueNotificationParams{
+			DosageAt:   n.DosageAt,
+			NotifiedAt: n.notifiedAt(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add overdue notification for %s: %w", n.DosageAt, err)
+		}
+		result.addRecords("overdue notifications", added)
+	}
+
+	for _, s := range archive.Snoozes {
+		added, err := q.ImportSnooze(ctx, db.ImportSnoozeParams{
+			DosageAt:     s.DosageAt,
+			SnoozedUntil: s.SnoozedUntil,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add snooze for %s: %w", s.DosageAt, err)
+		}
+		result.addRecords("snoozes", added)
+	}
+
+	for _, d := range archive.WebhookDeliveries {
+		added, err := q.ImportWebhookDelivery(ctx, db.ImportWebhookDeliveryParams{
+			EventID:     d.EventID,
+			EventType:   d.EventType,
+			Url:         d.Url,
+			Attempt:     d.Attempt,
+			StatusCode:  d.StatusCode,
+			Error:       d.Error,
+			AttemptedAt: d.AttemptedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add webhook delivery of event %s: %w", d.EventID, err)
+		}
+		result.addRecords("webhook deliveries", added)
+	}
+
+	prescriptions, err := importPrescriptions(ctx, q, archive, result)
+	if err != nil {
+		return err
+	}
+
+	if err := importStock(ctx, q, archive, prescriptions, result); err != nil {
+		return err
+	}
+
+	if err := importLabs(ctx, q, archive, result); err != nil {
+		return err
+	}
+
+	if err := importJournal(ctx, q, archive, result); err != nil {
+		return err
+	}
+
+	for _, r := range archive.Removals {
+		added, err := q.ImportRemoval(ctx, db.ImportRemovalParams{
+			DosageAt:  r.DosageAt,
+			HRTType:   r.HRTType,
+			RemovedAt: r.RemovedAt,
+			Reason:    r.Reason,
+			AddedAt:   r.AddedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add removal of dose at %s: %w", r.DosageAt, err)
+		}
+		result.addRecords("removals", added)
+	}
+
+	for _, n := range archive.RemovalNotifications {
+		added, err := q.ImportRemovalNotification(ctx, db.ImportRemovalNotificationParams{
+			DosageAt:   n.DosageAt,
+			NotifiedAt: n.NotifiedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add removal notification for %s: %w", n.DosageAt, err)
+		}
+		result.addRecords("removal notifications", added)
+	}
+
+	for _, p := range archive.Pauses {
+		added, err := q.ImportPause(ctx, db.ImportPauseParams{
+			HRTType:   p.HRTType,
+			StartedAt: p.StartedAt,
+			EndedAt:   p.EndedAt,
+			Reason:    p.Reason,
+			AddedAt:   p.AddedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add pause started at %s: %w", p.StartedAt, err)
+		}
+		result.addRecords("pauses", added)
+	}
+
+	for _, trip := range archive.Trips {
+		added, err := q.ImportTrip(ctx, db.ImportTripParams{
+			HRTType:         trip.HRTType,
+			DepartureZone:   trip.DepartureZone,
+			DestinationZone: trip.DestinationZone,
+			DepartsAt:       trip.DepartsAt,
+			Note:            trip.Note,
+			AddedAt:         trip.AddedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add trip departing at %s: %w", trip.DepartsAt, err)
+		}
+		result.addRecords("trips", added)
+	}
+
+	for _, skip := range archive.Skips {
+		added, err := q.ImportSkip(ctx, db.ImportSkipParams{
+			HRTType:   skip.HRTType,
+			DueAt:     skip.DueAt,
+			SkippedAt: skip.SkippedAt,
+			Reason:    skip.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add skip of dose due at %s: %w", skip.DueAt, err)
+		}
+		result.addRecords("skips", added)
+	}
+
+	for _, n := range archive.MissedNotifications {
+		added, err := q.ImportMissedNotification(ctx, db.ImportMissedNotificationParams{
+			DosageAt:   n.DosageAt,
+			NotifiedAt: n.notifiedAt(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add missed dose notification for %s: %w", n.DosageAt, err)
+		}
+		result.addRecords("missed dose notifications", added)
+	}
+
+	return nil
+}
+
+// importPrescriptions adds the prescriptions with their notifications. It
+// returns the IDs in the database of the prescriptions in the archive.
+func importPrescriptions(ctx context.Context, q *db.Queries, archive *Archive, result *ImportResult) (map[int64]int64, error) {
+	ids := make(map[int64]int64, len(archive.Prescriptions))
+
+	for _, p := range archive.Prescriptions {
+		id, err := q.PrescriptionID(ctx, db.PrescriptionIDParams{
+			HRTType:    p.HRTType,
+			Prescriber: p.Prescriber,
+			Medication: p.Medication,
+			WrittenAt:  p.WrittenAt,
+			AddedAt:    p.AddedAt,
+		})
+		if err == nil {
+			ids[p.ID] = id
+			continue
+		}
+		if !db.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to look up prescription written at %s: %w", p.WrittenAt, err)
+		}
+
+		added, err := q.AddPrescription(ctx, db.AddPrescriptionParams{
+			HRTType:    p.HRTType,
+			Prescriber: p.Prescriber,
+			Medication: p.Medication,
+			Strength:   p.Strength,
+			Refills:    p.Refills,
+			WrittenAt:  p.WrittenAt,
+			ExpiresAt:  p.ExpiresAt,
+			AddedAt:    p.AddedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add prescription written at %s: %w", p.WrittenAt, err)
+		}
+		ids[p.ID] = added.ID
+		result.addRecords("prescriptions", 1)
+	}
+
+	for _, n := range archive.PrescriptionNotifications {
+		added, err := q.ImportPrescriptionNotification(ctx, db.ImportPrescriptionNotificationParams{
+			PrescriptionID: ids[n.PrescriptionID],
+			Reason:         n.Reason,
+			NotifiedAt:     n.NotifiedAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add prescription notification: %w", err)
+		}
+		result.addRecords("prescription notifications", added)
+	}
+
+	return ids, nil
+}
+
+// importStock adds the stock entries with their usage and refill
+// notifications. prescriptions maps the IDs of the prescriptions in the
+// archive to the ones in the database.
+func importStock(ctx context.Context, q *db.Queries, archive *Archive, prescriptions map[int64]int64, result *ImportResult) error {
+	// ids maps the IDs of the stock entries in the archive to the ones in the
+	// database.
+	ids := make(map[int64]int64, len(archive.Stock))
+	var added []db.Stock
+
+	for _, entry := range archive.Stock {
+		id, err := q.StockID(ctx, db.StockIDParams{
+			HRTType:    entry.HRTType,
+			Medication: entry.Medication,
+			Lot:        entry.Lot,
+			AddedAt:    entry.AddedAt,
+		})
+		if err == nil {
+			ids[entry.ID] = id
+			continue
+		}
+		if !db.IsNotFound(err) {
+			return fmt.Errorf("failed to look up stock added at %s: %w", entry.AddedAt, err)
+		}
+
+		stock, err := q.AddStock(ctx, db.AddStockParams{
+			HRTType:    entry.HRTType,
+			Medication: entry.Medication,
+			Quantity:   entry.Quantity,
+			Remaining:  entry.Remaining,
+			Lot:        entry.Lot,
+			ExpiresAt:  entry.ExpiresAt,
+			AddedAt:    entry.AddedAt,
+			PrescriptionID: sql.NullInt64{
+				Int64: prescriptions[entry.PrescriptionID.Int64],
+				Valid: entry.PrescriptionID.Valid,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add stock added at %s: %w", entry.AddedAt, err)
+		}
+		ids[entry.ID] = stock.ID
+		added = append(added, db.Stock{ID: stock.ID, Remaining: entry.Remaining})
+		result.addRecords("stock entries", 1)
+	}
+
+	for _, usage := range archive.StockUsage {
+		n, err := q.ImportStockUsage(ctx, db.ImportStockUsageParams{
+			DosageAt: usage.DosageAt,
+			StockID:  ids[usage.StockID],
+			Amount:   usage.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add stock usage of the dose at %s: %w", usage.DosageAt, err)
+		}
+		result.addRecords("stock usages", n)
+	}
+
+	// Using stock takes from what remains of it, which the added entries
+	// already account for.
+	for _, entry := range added {
+		if err := q.SetStockRemaining(ctx, db.SetStockRemainingParams{
+			Remaining: entry.Remaining,
+			ID:        entry.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to restore the remaining stock: %w", err)
+		}
+	}
+
+	for _, n := range archive.RefillNotifications {
+		added, err := q.ImportRefillNotification(ctx, db.ImportRefillNotificationParams{
+			StockID:    ids[n.StockID],
+			NotifiedAt: n.NotifiedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add refill notification: %w", err)
+		}
+		result.addRecords("refill notifications", added)
+	}
+
+	return nil
+}
+
+// importLabs adds the lab appointments with their results and notifications.
+// Results of appointments that already have one are left out.
+func importLabs(ctx context.Context, q *db.Queries, archive *Archive, result *ImportResult) error {
+	ids := make(map[int64]int64, len(archive.LabAppointments))
+
+	for _, a := range archive.LabAppointments {
+		id, err := q.LabAppointmentID(ctx, db.LabAppointmentIDParams{
+			HRTType:     a.HRTType,
+			Target:      a.Target,
+			ScheduledAt: a.ScheduledAt,
+			CreatedAt:   a.CreatedAt,
+		})
+		if err == nil {
+			ids[a.ID] = id
+			continue
+		}
+		if !db.IsNotFound(err) {
+			return fmt.Errorf("failed to look up lab appointment at %s: %w", a.ScheduledAt, err)
+		}
+
+		added, err := q.AddLabAppointment(ctx, db.AddLabAppointmentParams{
+			HRTType:     a.HRTType,
+			Target:      a.Target,
+			ScheduledAt: a.ScheduledAt,
+			Note:        a.Note,
+			CreatedAt:   a.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add lab appointment at %s: %w", a.ScheduledAt, err)
+		}
+		ids[a.ID] = added.ID
+		result.addRecords("lab appointments", 1)
+	}
+
+	for _, r := range archive.LabResults {
+		added, err := q.ImportLabResult(ctx, db.ImportLabResultParams{
+			HRTType: r.HRTType,
+			DrawnAt: r.DrawnAt,
+			Value:   r.Value,
+			AppointmentID: sql.NullInt64{
+				Int64: ids[r.AppointmentID.Int64],
+				Valid: r.AppointmentID.Valid,
+			},
+			Note:    r.Note,
+			AddedAt: r.AddedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add lab result drawn at %s: %w", r.DrawnAt, err)
+		}
+		result.addRecords("lab results", added)
+	}
+
+	for _, n := range archive.LabNotifications {
+		added, err := q.ImportLabNotification(ctx, db.ImportLabNotificationParams{
+			AppointmentID: ids[n.AppointmentID],
+			NotifiedAt:    n.NotifiedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add lab notification: %w", err)
+		}
+		result.addRecords("lab notifications", added)
+	}
+
+	return nil
+}
+
+// importJournal adds the journal entries with their tags.
+func importJournal(ctx context.Context, q *db.Queries, archive *Archive, result *ImportResult) error {
+	ids := make(map[int64]int64, len(archive.JournalEntries))
+
+	for _, e := range archive.JournalEntries {
+		id, err := q.JournalEntryID(ctx, db.JournalEntryIDParams{
+			HRTType:   e.HRTType,
+			WrittenAt: e.WrittenAt,
+			AddedAt:   e.AddedAt,
+		})
+		if err == nil {
+			ids[e.ID] = id
+			continue
+		}
+		if !db.IsNotFound(err) {
+			return fmt.Errorf("failed to look up journal entry written at %s: %w", e.WrittenAt, err)
+		}
+
+		added, err := q.AddJournalEntry(ctx, db.AddJournalEntryParams{
+			HRTType:   e.HRTType,
+			WrittenAt: e.WrittenAt,
+			Mood:      e.Mood,
+			Notes:     e.Notes,
+			AddedAt:   e.AddedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add journal entry written at %s: %w", e.WrittenAt, err)
+		}
+		ids[e.ID] = added.ID
+		result.addRecords("journal entries", 1)
+	}
+
+	for _, tag := range archive.JournalTags {
+		added, err := q.ImportJournalTag(ctx, db.ImportJournalTagParams{
+			EntryID: ids[tag.EntryID],
+			Tag:     tag.Tag,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add journal tag %q: %w", tag.Tag, err)
+		}
+		result.addRecords("journal tags", added)
+	}
+
+	return nil
+}
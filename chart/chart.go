@@ -0,0 +1,258 @@
+// Package chart renders charts as SVG on the server, so that they can be shown
+// without JavaScript and embedded into self-contained pages.
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/predict"
+)
+
+// ContentType is the MIME type of a standalone chart.
+const ContentType = "image/svg+xml"
+
+// Default sizes of a chart in pixels.
+const (
+	DefaultWidth  = 720
+	DefaultHeight = 240
+)
+
+// Margins around the plot area for the axis labels.
+const (
+	marginTop    = 10
+	marginRight  = 10
+	marginBottom = 24
+	marginLeft   = 44
+)
+
+// Point is a measured level, such as a lab result.
+type Point struct {
+	Time  time.Time
+	Value float64
+	// Label is shown when hovering over the point.
+	Label string
+}
+
+// Span is a span of time, such as a pause of the regimen. A zero To means the
+// span lasts until the end of the chart.
+type Span struct {
+	From time.Time
+	To   time.Time
+	// Label is shown when hovering over the span.
+	Label string
+}
+
+// Levels is a chart of predicted hormone levels over time.
+type Levels struct {
+	// Values are the predicted levels, oldest first.
+	Values []predict.TimeValue
+	// Baseline are the levels to compare Values against, such as without a
+	// change to the schedule, which are drawn as a dashed line.
+	Baseline []predict.TimeValue
+	// Doses are the times of the doses within the chart, which are marked
+	// with vertical lines.
+	Doses []time.Time
+	// Points are the measured levels within the chart, which are drawn as
+	// dots over the predicted levels.
+	Points []Point
+	// Target is the range of levels to aim for, which is shaded if set.
+	Target hrtclicker.LevelRange
+	// Spans are the spans of time within the chart that are shaded, such as
+	// the pauses of the regimen.
+	Spans []Span
+	// Width and Height are the size of the chart in pixels. They default to
+	// DefaultWidth and DefaultHeight.
+	Width, Height int
+	// Location is the time zone of the time axis labels. It defaults to
+	// time.Local.
+	Location *time.Location
+}
+
+// SVG renders the chart as an inline SVG element.
+func (c Levels) SVG() template.HTML {
+	var b bytes.Buffer
+	c.WriteSVG(&b)
+	return template.HTML(b.String())
+}
+
+// WriteSVG writes the chart as an SVG element to w. Colors can be changed
+// using the CSS variables --chart-line, --chart-baseline, --chart-dose,
+// --chart-point, --chart-target and --chart-span, and text uses the current
+// color.
+func (c Levels) WriteSVG(w io.Writer) error {
+	width, height := c.Width, c.Height
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s,
+		`<svg xmlns="http://www.w3.org/2000/svg" class="chart levels-chart" viewBox="0 0 %d %d" width="%d" height="%d" role="img" aria-label="Predicted levels">`,
+		width, height, width, height)
+	s.WriteString(`<style>` +
+		`.levels-chart text{font:11px sans-serif;fill:currentColor}` +
+		`.levels-chart .grid{stroke:currentColor;stroke-opacity:.15}` +
+		`.levels-chart .line{fill:none;stroke:var(--chart-line,#f89fb1);stroke-width:2}` +
+		`.levels-chart .baseline{fill:none;stroke:var(--chart-baseline,currentColor);stroke-opacity:.5;stroke-width:1.5;stroke-dasharray:4 4}` +
+		`.levels-chart .dose{stroke:var(--chart-dose,#55cdfc);stroke-dasharray:3 3}` +
+		`.levels-chart .point{fill:var(--chart-point,#55cdfc);stroke:currentColor}` +
+		`.levels-chart .target{fill:var(--chart-target,#55cdfc);fill-opacity:.15}` +
+		`.levels-chart .span{fill:var(--chart-span,currentColor);fill-opacity:.1}` +
+		`</style>`)
+
+	plotX, plotY := float64(marginLeft), float64(marginTop)
+	plotW := float64(width - marginLeft - marginRight)
+	plotH := float64(height - marginTop - marginBottom)
+
+	if len(c.Values) < 2 || plotW <= 0 || plotH <= 0 {
+		fmt.Fprintf(&s, `<text x="%d" y="%d" text-anchor="middle">No levels to show</text>`, width/2, height/2)
+		s.WriteString(`</svg>`)
+		_, err := io.WriteString(w, s.String())
+		return err
+	}
+
+	from := c.Values[0].T.Time()
+	to := c.Values[len(c.Values)-1].T.Time()
+	span := to.Sub(from)
+
+	maxValue := c.Target.Max
+	for _, v := range c.Values {
+		maxValue = max(maxValue, v.V)
+	}
+	for _, v := range c.Baseline {
+		maxValue = max(maxValue, v.V)
+	}
+	for _, p := range c.Points {
+		if !p.Time.Before(from) && !p.Time.After(to) {
+			maxValue = max(maxValue, p.Value)
+		}
+	}
+	step := niceStep(maxValue / 4)
+	top := math.Max(step, math.Ceil(maxValue/step)*step)
+
+	x := func(t time.Time) float64 {
+		return plotX + plotW*float64(t.Sub(from))/float64(span)
+	}
+	y := func(v float64) float64 {
+		return plotY + plotH*(1-v/top)
+	}
+
+	if !c.Target.IsZero() {
+		fmt.Fprintf(&s, `<rect class="target" x="%.1f" y="%.1f" width="%.1f" height="%.1f"><title>Target: %.0f to %.0f pg/mL</title></rect>`,
+			plotX, y(c.Target.Max), plotW, y(c.Target.Min)-y(c.Target.Max), c.Target.Min, c.Target.Max)
+	}
+
+	for _, span := range c.Spans {
+		start, end := span.From, span.To
+		if end.IsZero() || end.After(to) {
+			end = to
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if !start.Before(end) {
+			continue
+		}
+		fmt.Fprintf(&s, `<rect class="span" x="%.1f" y="%.1f" width="%.1f" height="%.1f"><title>%s</title></rect>`,
+			x(start), plotY, x(end)-x(start), plotH, html.EscapeString(span.Label))
+	}
+
+	// Horizontal grid lines with the level labels.
+	for v := 0.0; v <= top+step/2; v += step {
+		fmt.Fprintf(&s, `<line class="grid" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`,
+			plotX, y(v), plotX+plotW, y(v))
+		fmt.Fprintf(&s, `<text x="%.1f" y="%.1f" text-anchor="end" dominant-baseline="middle">%.0f</text>`,
+			plotX-6, y(v), v)
+	}
+
+	// Vertical grid lines at the start of days, or fewer for long ranges.
+	dayStep := max(1, int(math.Ceil(span.Hours()/24/8)))
+	y0, m0, d0 := from.In(loc).Date()
+	day := time.Date(y0, m0, d0, 0, 0, 0, 0, loc)
+	for ; !day.After(to); day = day.AddDate(0, 0, dayStep) {
+		if day.Before(from) {
+			continue
+		}
+		fmt.Fprintf(&s, `<line class="grid" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`,
+			x(day), plotY, x(day), plotY+plotH)
+		fmt.Fprintf(&s, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`,
+			x(day), height-6, day.Format("Jan 2"))
+	}
+
+	for _, dose := range c.Doses {
+		if dose.Before(from) || dose.After(to) {
+			continue
+		}
+		fmt.Fprintf(&s, `<line class="dose" x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"><title>Dose at %s</title></line>`,
+			x(dose), plotY, x(dose), plotY+plotH, dose.In(loc).Format("Mon Jan 2 15:04"))
+	}
+
+	if len(c.Baseline) > 1 {
+		s.WriteString(`<path class="baseline" d="`)
+		for i, v := range c.Baseline {
+			cmd := "L"
+			if i == 0 {
+				cmd = "M"
+			}
+			fmt.Fprintf(&s, "%s%.1f %.1f", cmd, x(v.T.Time()), y(v.V))
+		}
+		s.WriteString(`"/>`)
+	}
+
+	s.WriteString(`<path class="line" d="`)
+	for i, v := range c.Values {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&s, "%s%.1f %.1f", cmd, x(v.T.Time()), y(v.V))
+	}
+	s.WriteString(`"/>`)
+
+	for _, p := range c.Points {
+		if p.Time.Before(from) || p.Time.After(to) {
+			continue
+		}
+		title := fmt.Sprintf("%.0f pg/mL at %s", p.Value, p.Time.In(loc).Format("Mon Jan 2 15:04"))
+		if p.Label != "" {
+			title = p.Label + ": " + title
+		}
+		fmt.Fprintf(&s, `<circle class="point" cx="%.1f" cy="%.1f" r="4"><title>%s</title></circle>`,
+			x(p.Time), y(p.Value), html.EscapeString(title))
+	}
+
+	fmt.Fprintf(&s, `<text x="%.1f" y="%.1f">pg/mL</text>`, plotX+4, plotY+12)
+	s.WriteString(`</svg>`)
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+// niceStep rounds the step up to 1, 2 or 5 times a power of ten.
+func niceStep(step float64) float64 {
+	if step <= 0 {
+		return 1
+	}
+	pow := math.Pow(10, math.Floor(math.Log10(step)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if step <= m*pow {
+			return m * pow
+		}
+	}
+	return 10 * pow
+}
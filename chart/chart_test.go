@@ -0,0 +1,166 @@
+package chart
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/predict"
+)
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		step float64
+		want float64
+	}{
+		{0, 1},
+		{-5, 1},
+		{0.3, 0.5},
+		{1, 1},
+		{1.5, 2},
+		{25, 50},
+		{60, 100},
+		{100, 100},
+		{101, 200},
+	}
+
+	for _, test := range tests {
+		if got := niceStep(test.step); got != test.want {
+			t.Errorf("niceStep(%v) = %v, want %v", test.step, got, test.want)
+		}
+	}
+}
+
+func TestLevelsWriteSVG(t *testing.T) {
+	// The levels rise from 0 to 100 pg/mL over a day, so that the plot area
+	// of the default size goes from x 44 to 710 and from y 216 to 10.
+	start := hrttest.Date(28, 0, 0)
+	values := []predict.TimeValue{
+		{T: predict.Timestamp(start.Unix()), V: 0},
+		{T: predict.Timestamp(start.Add(24 * time.Hour).Unix()), V: 100},
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	tests := []struct {
+		name    string
+		chart   Levels
+		want    []string
+		notWant []string
+	}{
+		{
+			name:  "no levels",
+			chart: Levels{Values: values[:1]},
+			want:  []string{"No levels to show"},
+		},
+		{
+			name:  "line",
+			chart: Levels{Values: values, Location: time.UTC},
+			want: []string{
+				`<path class="line" d="M44.0 216.0L710.0 10.0"/>`,
+				`>Mar 28</text>`,
+				`>100</text>`,
+			},
+			notWant: []string{`class="target"`, `class="baseline"`},
+		},
+		{
+			name: "target raises the top",
+			chart: Levels{
+				Values:   values,
+				Target:   hrtclicker.LevelRange{Min: 100, Max: 300},
+				Location: time.UTC,
+			},
+			want: []string{"<title>Target: 100 to 300 pg/mL</title>", ">300</text>"},
+		},
+		{
+			name: "doses within the chart",
+			chart: Levels{
+				Values:   values,
+				Doses:    []time.Time{start.Add(12 * time.Hour), start.Add(48 * time.Hour)},
+				Location: time.UTC,
+			},
+			want:    []string{`<line class="dose" x1="377.0"`, "<title>Dose at Sat Mar 28 12:00</title>"},
+			notWant: []string{"Dose at Mon Mar 30"},
+		},
+		{
+			name: "location",
+			chart: Levels{
+				Values:   values,
+				Doses:    []time.Time{start.Add(12 * time.Hour)},
+				Location: berlin,
+			},
+			want: []string{"<title>Dose at Sat Mar 28 13:00</title>"},
+		},
+		{
+			name: "span clipped to the chart",
+			chart: Levels{
+				Values:   values,
+				Spans:    []Span{{From: start.Add(-24 * time.Hour), Label: "Paused: <surgery>"}},
+				Location: time.UTC,
+			},
+			want: []string{`<rect class="span" x="44.0" y="10.0" width="666.0" height="206.0"><title>Paused: &lt;surgery&gt;</title></rect>`},
+		},
+		{
+			name: "span outside the chart",
+			chart: Levels{
+				Values:   values,
+				Spans:    []Span{{From: start.Add(-48 * time.Hour), To: start.Add(-24 * time.Hour)}},
+				Location: time.UTC,
+			},
+			notWant: []string{`class="span"`},
+		},
+		{
+			name: "points",
+			chart: Levels{
+				Values:   values,
+				Points:   []Point{{Time: start.Add(12 * time.Hour), Value: 50, Label: "Trough <ok>"}},
+				Location: time.UTC,
+			},
+			want: []string{`<circle class="point" cx="377.0" cy="113.0" r="4"><title>Trough &lt;ok&gt;: 50 pg/mL at Sat Mar 28 12:00</title></circle>`},
+		},
+		{
+			name:  "baseline",
+			chart: Levels{Values: values, Baseline: values, Location: time.UTC},
+			want:  []string{`<path class="baseline" d="M44.0 216.0L710.0 10.0"/>`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s strings.Builder
+			if err := test.chart.WriteSVG(&s); err != nil {
+				t.Fatal(err)
+			}
+			svg := s.String()
+
+			decoder := xml.NewDecoder(strings.NewReader(svg))
+			for {
+				_, err := decoder.Token()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("SVG is not well-formed: %v\n%s", err, svg)
+				}
+			}
+
+			for _, want := range test.want {
+				if !strings.Contains(svg, want) {
+					t.Errorf("SVG does not contain %s\n%s", want, svg)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(svg, notWant) {
+					t.Errorf("SVG contains %s\n%s", notWant, svg)
+				}
+			}
+		})
+	}
+}
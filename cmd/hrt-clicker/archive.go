@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker/archive"
+)
+
+func export(ctx context.Context, args []string) error {
+	var formatName string
+	var output string
+
+	flags := newFlagSet("export", "")
+	flags.StringVar(&formatName, "format", "",
+		"format to export as: csv, jsonl or json, defaults to the output file's extension or json")
+	flags.StringVar(&output, "o", "", "file to write to instead of stdout")
+	flags.Parse(args)
+
+	format := archive.FormatJSON
+	var err error
+	switch {
+	case formatName != "":
+		format, err = archive.ParseFormat(formatName)
+	case output != "":
+		format, err = archive.FormatFromFilename(output)
+	}
+	if err != nil {
+		return err
+	}
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	if output == "" {
+		if err := b.Export(ctx, format, os.Stdout); err != nil {
+			return fmt.Errorf("failed to export: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := b.Export(ctx, format, f); err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported to %s.\n", output)
+	return nil
+}
+
+func importCmd(ctx context.Context, args []string) error {
+	var out outputFlags
+	var formatName string
+	var tz string
+	var dryRun bool
+
+	flags := newFlagSet("import", "<file>")
+	flags.StringVar(&formatName, "format", "",
+		"format of the file: csv, jsonl or json, defaults to the file's extension")
+	flags.StringVar(&tz, "tz", "",
+		"IANA time zone of CSV times without one, defaults to the time zone of the regimen")
+	flags.BoolVar(&dryRun, "dry-run", false, "only report what would be imported")
+	out.register(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errFailed
+	}
+	path := flags.Arg(0)
+
+	opts := archive.ImportOptions{
+		DefaultType: out.hrtType(),
+		DryRun:      dryRun,
+	}
+
+	var err error
+	switch {
+	case formatName != "":
+		opts.Format, err = archive.ParseFormat(formatName)
+	case path == "-":
+		err = errors.New("--format is required when reading from stdin")
+	default:
+		opts.Format, err = archive.FormatFromFilename(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if tz != "" {
+		opts.Location, err = time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("invalid --tz: %w", err)
+		}
+	}
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	result, err := b.Import(ctx, r, opts)
+	if err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+
+	return out.print(result, func() {
+		verb := "Added"
+		if result.DryRun {
+			verb = "Would add"
+		}
+
+		fmt.Printf("%s %d doses, skipped %d duplicates.\n", verb, len(result.Added), result.Duplicates)
+		kinds := make([]string, 0, len(result.RecordsAdded))
+		for kind := range result.RecordsAdded {
+			kinds = append(kinds, kind)
+		}
+		slices.Sort(kinds)
+		for _, kind := range kinds {
+			fmt.Printf("%s %d %s.\n", verb, result.RecordsAdded[kind], kind)
+		}
+
+		if len(result.Conflicts) > 0 {
+			fmt.Printf("Skipped %d doses that conflict with existing ones:\n", len(result.Conflicts))
+			for _, c := range result.Conflicts {
+				fmt.Printf("  %s: %s, but a %s dose already exists\n",
+					formatTime(c.Imported.DosageAt, loc), c.Imported.HRTType, c.Existing.HRTType)
+			}
+		}
+
+		if result.DryRun {
+			fmt.Println("Nothing was imported since this was a dry run.")
+		}
+	})
+}
@@ -0,0 +1,731 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/archive"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/inventory"
+	"libdb.so/hrtclicker/journal"
+	"libdb.so/hrtclicker/levels"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/notify"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+	"libdb.so/hrtclicker/report"
+	"libdb.so/hrtclicker/schedule"
+	"libdb.so/hrtclicker/web"
+)
+
+// backend is what the commands use to access the data. It is either the
+// SQLite database file directly or a running server through its API.
+// An empty HRT type means the default regimen.
+type backend interface {
+	Record(ctx context.Context, t hrtclicker.HRTType, at time.Time, notes string, tags db.Tags) (db.HRTHistory, error)
+	Undo(ctx context.Context, t hrtclicker.HRTType) (db.HRTHistory, error)
+	NextDose(ctx context.Context, t hrtclicker.HRTType) (schedule.NextDose, error)
+	// History returns the doses within the given duration with the most
+	// recent dose first. A zero duration returns all doses, a positive limit
+	// returns only that many of the most recent ones, and a non-empty tag
+	// returns only the doses with that tag.
+	History(ctx context.Context, t hrtclicker.HRTType, d time.Duration, limit int, tag string) ([]db.HRTHistory, error)
+	Levels(ctx context.Context, t hrtclicker.HRTType, d time.Duration) ([]predict.TimeValue, error)
+	Snooze(ctx context.Context, t hrtclicker.HRTType, d time.Duration) (db.Snoozed, error)
+	// Remove records that the application of the dose taken at dosageAt was
+	// taken off at removedAt. A zero dosageAt means the most recent dose.
+	Remove(ctx context.Context, t hrtclicker.HRTType, dosageAt, removedAt time.Time, reason string) (db.Removal, error)
+	// Pause pauses the regimen from startedAt until endedAt. A zero endedAt
+	// pauses it until it is resumed.
+	Pause(ctx context.Context, t hrtclicker.HRTType, startedAt, endedAt time.Time, reason string) (db.Pause, error)
+	// Resume ends the pause the regimen is in now.
+	Resume(ctx context.Context, t hrtclicker.HRTType) (db.Pause, error)
+	// Skip skips the dose due at dueAt so that the dose after it is due as if
+	// it was taken on time. A zero dueAt means the next dose.
+	Skip(ctx context.Context, t hrtclicker.HRTType, dueAt time.Time, reason string) (db.Skip, error)
+	NotifyTest(ctx context.Context) error
+	// Report writes the HTML report of the doses between from and to.
+	Report(ctx context.Context, t hrtclicker.HRTType, from, to time.Time, w io.Writer) error
+	Export(ctx context.Context, format archive.Format, w io.Writer) error
+	Import(ctx context.Context, r io.Reader, opts archive.ImportOptions) (archive.ImportResult, error)
+	// AddJournalEntry adds a journal entry written at the given time. A zero
+	// mood means the entry has no mood.
+	AddJournalEntry(ctx context.Context, t hrtclicker.HRTType, at time.Time, mood int, notes string, tags []string) (journal.Entry, error)
+	// Journal returns the journal entries within the given duration, oldest
+	// first. A zero duration returns all entries, and a non-empty tag returns
+	// only the entries with that tag.
+	Journal(ctx context.Context, t hrtclicker.HRTType, d time.Duration, tag string) ([]journal.Entry, error)
+	// JournalAnalysis analyzes the journal entries like Journal returns them.
+	JournalAnalysis(ctx context.Context, t hrtclicker.HRTType, d time.Duration, tag string) (journal.Analysis, error)
+	// Changes returns a channel that receives a value whenever the data might
+	// have changed. The channel is closed once ctx is canceled.
+	Changes(ctx context.Context) <-chan struct{}
+	// Location returns the time zone that the times of the regimen are shown
+	// and parsed in.
+	Location(t hrtclicker.HRTType) *time.Location
+	Close() error
+}
+
+// openBackend opens the backend chosen by the global flags.
+func openBackend() (backend, error) {
+	if serverURL != "" {
+		u, err := url.Parse(serverURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server URL: %w", err)
+		}
+		return &apiBackend{base: u, client: http.DefaultClient}, nil
+	}
+
+	cfg, err := hrtclicker.ReadJSONConfigFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	for _, name := range []string{cfg.TimeZone, cfg.HRT.TimeZone} {
+		if _, err := time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("invalid time zone: %w", err)
+		}
+	}
+
+	database, err := db.Open(databasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return &dbBackend{cfg: cfg, db: database}, nil
+}
+
+type dbBackend struct {
+	cfg *hrtclicker.Config
+	db  *db.SQLiteDB
+}
+
+func (b *dbBackend) regimen(t hrtclicker.HRTType) (hrtclicker.HRTConfig, error) {
+	regimen, ok := b.cfg.Regimen(t)
+	if !ok {
+		return hrtclicker.HRTConfig{}, fmt.Errorf("no regimen configured for type %q", t)
+	}
+	return regimen, nil
+}
+
+func (b *dbBackend) Location(t hrtclicker.HRTType) *time.Location {
+	regimen, ok := b.cfg.Regimen(t)
+	if !ok {
+		return time.Local
+	}
+	return regimen.Location()
+}
+
+func (b *dbBackend) Record(ctx context.Context, t hrtclicker.HRTType, at time.Time, notes string, tags db.Tags) (db.HRTHistory, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.HRTHistory{}, err
+	}
+
+	dose := db.HRTHistory{
+		DosageAt: at.UTC().Truncate(time.Second),
+		HRTType:  string(regimen.Type),
+		Notes:    notes,
+		Tags:     db.NewTags(tags...),
+		Zone:     regimen.Zone(),
+	}
+
+	if err := inventory.RecordDose(ctx, b.db, regimen, dose.DosageAt, dose.Notes, dose.Tags); err != nil {
+		if db.IsAlreadyExists(err) {
+			return db.HRTHistory{}, fmt.Errorf("a dose at %s already exists", dose.DosageAt)
+		}
+		return db.HRTHistory{}, err
+	}
+
+	return dose, nil
+}
+
+func (b *dbBackend) Undo(ctx context.Context, t hrtclicker.HRTType) (db.HRTHistory, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.HRTHistory{}, err
+	}
+
+	dose, err := b.db.DeleteLastDose(ctx, string(regimen.Type))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return db.HRTHistory{}, errors.New("no dose to delete")
+		}
+		return db.HRTHistory{}, err
+	}
+
+	return dose, nil
+}
+
+func (b *dbBackend) NextDose(ctx context.Context, t hrtclicker.HRTType) (schedule.NextDose, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return schedule.NextDose{}, err
+	}
+	return schedule.Next(ctx, b.db, regimen, time.Now())
+}
+
+func (b *dbBackend) History(ctx context.Context, t hrtclicker.HRTType, d time.Duration, limit int, tag string) ([]db.HRTHistory, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if d > 0 {
+		since = time.Now().Add(-d)
+	}
+
+	// SQLite treats a negative limit as no limit.
+	if limit <= 0 {
+		limit = -1
+	}
+
+	if tag = db.NormalizeTag(tag); tag != "" {
+		return b.db.TaggedHistoryPage(ctx, db.TaggedHistoryPageParams{
+			HRTType: string(regimen.Type),
+			Since:   since.UTC(),
+			Before:  db.EndOfTime,
+			Tag:     tag,
+			Limit:   int64(limit),
+		})
+	}
+
+	return b.db.HistoryPage(ctx, db.HistoryPageParams{
+		HRTType: string(regimen.Type),
+		Since:   since.UTC(),
+		Before:  db.EndOfTime,
+		Limit:   int64(limit),
+	})
+}
+
+func (b *dbBackend) Levels(ctx context.Context, t hrtclicker.HRTType, d time.Duration) ([]predict.TimeValue, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return levels.Between(ctx, b.db, regimen, now.Add(-d), now)
+}
+
+func (b *dbBackend) Snooze(ctx context.Context, t hrtclicker.HRTType, d time.Duration) (db.Snoozed, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.Snoozed{}, err
+	}
+	return notify.Snooze(ctx, b.db, regimen.Type, time.Now().Add(d))
+}
+
+func (b *dbBackend) Remove(ctx context.Context, t hrtclicker.HRTType, dosageAt, removedAt time.Time, reason string) (db.Removal, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.Removal{}, err
+	}
+
+	rm, err := removal.Add(ctx, b.db, regimen, dosageAt, removedAt, reason)
+	if err != nil {
+		switch {
+		case db.IsNotFound(err):
+			return db.Removal{}, errors.New("no such dose")
+		case db.IsAlreadyExists(err):
+			return db.Removal{}, errors.New("the dose was already removed")
+		}
+		return db.Removal{}, err
+	}
+
+	return rm, nil
+}
+
+func (b *dbBackend) Pause(ctx context.Context, t hrtclicker.HRTType, startedAt, endedAt time.Time, reason string) (db.Pause, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.Pause{}, err
+	}
+	return pause.Start(ctx, b.db, regimen.Type, startedAt, endedAt, reason)
+}
+
+func (b *dbBackend) Resume(ctx context.Context, t hrtclicker.HRTType) (db.Pause, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.Pause{}, err
+	}
+	return pause.Resume(ctx, b.db, regimen.Type, time.Now())
+}
+
+func (b *dbBackend) Skip(ctx context.Context, t hrtclicker.HRTType, dueAt time.Time, reason string) (db.Skip, error) {
+	if dueAt.IsZero() {
+		next, err := b.NextDose(ctx, t)
+		if err != nil {
+			return db.Skip{}, err
+		}
+		if next.NextDoseAt.IsZero() {
+			return db.Skip{}, missed.ErrNothingDue
+		}
+		dueAt = next.NextDoseAt
+	}
+
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return db.Skip{}, err
+	}
+	return missed.Skip(ctx, b.db, regimen, dueAt, time.Now(), reason)
+}
+
+func (b *dbBackend) NotifyTest(ctx context.Context) error {
+	return notify.SendTest(ctx, b.cfg)
+}
+
+func (b *dbBackend) Report(ctx context.Context, t hrtclicker.HRTType, from, to time.Time, w io.Writer) error {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return err
+	}
+
+	rep, err := report.Build(ctx, b.db, regimen, from, to)
+	if err != nil {
+		return err
+	}
+
+	tmpl := web.EmbeddedTemplates()
+	tmpl.Location = regimen.Location
+	return rep.Render(w, tmpl)
+}
+
+func (b *dbBackend) Export(ctx context.Context, format archive.Format, w io.Writer) error {
+	return archive.Export(ctx, b.db, b.cfg, format, w)
+}
+
+func (b *dbBackend) Import(ctx context.Context, r io.Reader, opts archive.ImportOptions) (archive.ImportResult, error) {
+	if opts.DefaultType == "" {
+		opts.DefaultType = b.cfg.HRT.Type
+	}
+	if opts.Location == nil {
+		opts.Location = b.Location(opts.DefaultType)
+	}
+	return archive.Import(ctx, b.db, r, opts)
+}
+
+func (b *dbBackend) AddJournalEntry(ctx context.Context, t hrtclicker.HRTType, at time.Time, mood int, notes string, tags []string) (journal.Entry, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return journal.Entry{}, err
+	}
+
+	return journal.Add(ctx, b.db, db.AddJournalEntryParams{
+		HRTType:   string(regimen.Type),
+		WrittenAt: at.UTC().Truncate(time.Second),
+		Mood:      sql.NullInt64{Int64: int64(mood), Valid: mood != 0},
+		Notes:     notes,
+		AddedAt:   time.Now().UTC().Truncate(time.Second),
+	}, tags)
+}
+
+func (b *dbBackend) Journal(ctx context.Context, t hrtclicker.HRTType, d time.Duration, tag string) ([]journal.Entry, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return nil, err
+	}
+	return journal.Between(ctx, b.db, regimen.Type, journalSince(d), db.EndOfTime, tag)
+}
+
+func (b *dbBackend) JournalAnalysis(ctx context.Context, t hrtclicker.HRTType, d time.Duration, tag string) (journal.Analysis, error) {
+	regimen, err := b.regimen(t)
+	if err != nil {
+		return journal.Analysis{}, err
+	}
+	return journal.Analyze(ctx, b.db, regimen, journalSince(d), db.EndOfTime, tag)
+}
+
+// journalSince returns the start of the journal range within the duration
+// until now, or the zero time for a zero duration.
+func journalSince(d time.Duration) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-d)
+}
+
+func (b *dbBackend) Changes(ctx context.Context) <-chan struct{} {
+	return b.db.Changes(ctx, time.Second)
+}
+
+func (b *dbBackend) Close() error {
+	return b.db.Close()
+}
+
+type apiBackend struct {
+	base   *url.URL
+	client *http.Client
+}
+
+// do sends a request to the API and decodes the JSON response into dst if dst
+// is not nil.
+func (b *apiBackend) do(ctx context.Context, method, path string, query url.Values, dst any) error {
+	var body io.Reader
+	var contentType string
+	if method != "GET" {
+		body = strings.NewReader(query.Encode())
+		contentType = "application/x-www-form-urlencoded"
+		query = nil
+	}
+
+	r, err := b.request(ctx, method, path, query, body, contentType)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if dst == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// request sends a request to the API with the query in the URL and the given
+// body. The caller must close the response body. Error responses are returned
+// as errors.
+func (b *apiBackend) request(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	u := b.base.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	r, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if r.StatusCode >= 400 {
+		defer r.Body.Close()
+		msg, _ := io.ReadAll(r.Body)
+		return nil, fmt.Errorf("server returned %s: %s", r.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return r, nil
+}
+
+func typeQuery(t hrtclicker.HRTType) url.Values {
+	q := url.Values{}
+	if t != "" {
+		q.Set("type", string(t))
+	}
+	return q
+}
+
+// Location returns the time zone of the machine, which can be set with $TZ.
+// The server doesn't tell the time zone of its regimens.
+func (b *apiBackend) Location(t hrtclicker.HRTType) *time.Location {
+	return time.Local
+}
+
+func (b *apiBackend) Record(ctx context.Context, t hrtclicker.HRTType, at time.Time, notes string, tags db.Tags) (db.HRTHistory, error) {
+	q := typeQuery(t)
+	q.Set("at", at.Format(time.RFC3339))
+	if notes != "" {
+		q.Set("notes", notes)
+	}
+	if len(tags) > 0 {
+		q.Set("tags", tags.String())
+	}
+
+	var dose db.HRTHistory
+	err := b.do(ctx, "POST", "/api/dosage/record", q, &dose)
+	return dose, err
+}
+
+func (b *apiBackend) Undo(ctx context.Context, t hrtclicker.HRTType) (db.HRTHistory, error) {
+	var dose db.HRTHistory
+	err := b.do(ctx, "POST", "/api/dosage/delete", typeQuery(t), &dose)
+	return dose, err
+}
+
+func (b *apiBackend) NextDose(ctx context.Context, t hrtclicker.HRTType) (schedule.NextDose, error) {
+	var next schedule.NextDose
+	err := b.do(ctx, "GET", "/api/dosage/next", typeQuery(t), &next)
+	return next, err
+}
+
+func (b *apiBackend) History(ctx context.Context, t hrtclicker.HRTType, d time.Duration, limit int, tag string) ([]db.HRTHistory, error) {
+	q := typeQuery(t)
+	if d > 0 {
+		q.Set("range", d.String())
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if tag != "" {
+		q.Set("tag", tag)
+	}
+
+	var doses []db.HRTHistory
+	if err := b.do(ctx, "GET", "/dosages.json", q, &doses); err != nil {
+		return nil, err
+	}
+
+	// dosages.json returns the oldest dose first.
+	slices.Reverse(doses)
+	return doses, nil
+}
+
+func (b *apiBackend) Levels(ctx context.Context, t hrtclicker.HRTType, d time.Duration) ([]predict.TimeValue, error) {
+	q := typeQuery(t)
+	q.Set("range", d.String())
+
+	var values []predict.TimeValue
+	err := b.do(ctx, "GET", "/api/levels", q, &values)
+	return values, err
+}
+
+func (b *apiBackend) Snooze(ctx context.Context, t hrtclicker.HRTType, d time.Duration) (db.Snoozed, error) {
+	q := typeQuery(t)
+	q.Set("duration", d.String())
+
+	var snooze db.Snoozed
+	err := b.do(ctx, "POST", "/api/dosage/snooze", q, &snooze)
+	return snooze, err
+}
+
+func (b *apiBackend) Remove(ctx context.Context, t hrtclicker.HRTType, dosageAt, removedAt time.Time, reason string) (db.Removal, error) {
+	q := typeQuery(t)
+	if !dosageAt.IsZero() {
+		q.Set("at", dosageAt.Format(time.RFC3339))
+	}
+	q.Set("removed_at", removedAt.Format(time.RFC3339))
+	q.Set("reason", reason)
+
+	var rm db.Removal
+	err := b.do(ctx, "POST", "/api/dosage/remove", q, &rm)
+	return rm, err
+}
+
+func (b *apiBackend) Pause(ctx context.Context, t hrtclicker.HRTType, startedAt, endedAt time.Time, reason string) (db.Pause, error) {
+	q := typeQuery(t)
+	q.Set("started_at", startedAt.Format(time.RFC3339))
+	if !endedAt.IsZero() {
+		q.Set("ended_at", endedAt.Format(time.RFC3339))
+	}
+	q.Set("reason", reason)
+
+	var p db.Pause
+	err := b.do(ctx, "POST", "/api/pauses/add", q, &p)
+	return p, err
+}
+
+func (b *apiBackend) Resume(ctx context.Context, t hrtclicker.HRTType) (db.Pause, error) {
+	var p db.Pause
+	err := b.do(ctx, "POST", "/api/pauses/resume", typeQuery(t), &p)
+	return p, err
+}
+
+func (b *apiBackend) Skip(ctx context.Context, t hrtclicker.HRTType, dueAt time.Time, reason string) (db.Skip, error) {
+	q := typeQuery(t)
+	if !dueAt.IsZero() {
+		q.Set("due_at", dueAt.Format(time.RFC3339))
+	}
+	q.Set("reason", reason)
+
+	var skip db.Skip
+	err := b.do(ctx, "POST", "/api/dosage/skip", q, &skip)
+	return skip, err
+}
+
+func (b *apiBackend) NotifyTest(ctx context.Context) error {
+	return b.do(ctx, "POST", "/api/notify/test", nil, nil)
+}
+
+func (b *apiBackend) Report(ctx context.Context, t hrtclicker.HRTType, from, to time.Time, w io.Writer) error {
+	q := typeQuery(t)
+	q.Set("from", from.Format(time.RFC3339))
+	q.Set("to", to.Format(time.RFC3339))
+
+	r, err := b.request(ctx, "GET", "/report", q, nil, "")
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	_, err = io.Copy(w, r.Body)
+	return err
+}
+
+func (b *apiBackend) Export(ctx context.Context, format archive.Format, w io.Writer) error {
+	q := url.Values{}
+	q.Set("format", string(format))
+
+	r, err := b.request(ctx, "GET", "/api/export", q, nil, "")
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	_, err = io.Copy(w, r.Body)
+	return err
+}
+
+func (b *apiBackend) Import(ctx context.Context, r io.Reader, opts archive.ImportOptions) (archive.ImportResult, error) {
+	q := typeQuery(opts.DefaultType)
+	q.Set("format", string(opts.Format))
+	q.Set("dry_run", strconv.FormatBool(opts.DryRun))
+	if opts.Location != nil {
+		q.Set("tz", opts.Location.String())
+	}
+
+	resp, err := b.request(ctx, "POST", "/api/import", q, r, opts.Format.ContentType())
+	if err != nil {
+		return archive.ImportResult{}, err
+	}
+	defer resp.Body.Close()
+
+	var result archive.ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return archive.ImportResult{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result, nil
+}
+
+func (b *apiBackend) AddJournalEntry(ctx context.Context, t hrtclicker.HRTType, at time.Time, mood int, notes string, tags []string) (journal.Entry, error) {
+	q := typeQuery(t)
+	q.Set("at", at.Format(time.RFC3339))
+	if mood != 0 {
+		q.Set("mood", strconv.Itoa(mood))
+	}
+	q.Set("notes", notes)
+	q.Set("tags", strings.Join(tags, ","))
+
+	var entry journal.Entry
+	err := b.do(ctx, "POST", "/api/journal/add", q, &entry)
+	return entry, err
+}
+
+// journalQuery returns the query of the journal endpoints.
+func journalQuery(t hrtclicker.HRTType, d time.Duration, tag string) url.Values {
+	q := typeQuery(t)
+	if d > 0 {
+		q.Set("from", journalSince(d).Format(time.RFC3339))
+	}
+	if tag != "" {
+		q.Set("tag", tag)
+	}
+	return q
+}
+
+func (b *apiBackend) Journal(ctx context.Context, t hrtclicker.HRTType, d time.Duration, tag string) ([]journal.Entry, error) {
+	var entries []journal.Entry
+	err := b.do(ctx, "GET", "/api/journal", journalQuery(t, d, tag), &entries)
+	return entries, err
+}
+
+func (b *apiBackend) JournalAnalysis(ctx context.Context, t hrtclicker.HRTType, d time.Duration, tag string) (journal.Analysis, error) {
+	var analysis journal.Analysis
+	err := b.do(ctx, "GET", "/api/journal/analysis", journalQuery(t, d, tag), &analysis)
+	return analysis, err
+}
+
+// eventsRetry is how long Changes waits before reconnecting to the event
+// stream after it ends or fails.
+const eventsRetry = 5 * time.Second
+
+// Changes follows the server's event stream at /api/events, and signals a
+// change for every event. It reconnects if the stream breaks, and signals a
+// change after reconnecting in case it missed events while disconnected.
+func (b *apiBackend) Changes(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	notify := func() {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A change is already pending.
+		}
+	}
+
+	go func() {
+		defer close(ch)
+
+		for connected := false; ; connected = true {
+			if connected {
+				notify()
+			}
+
+			if err := b.followEvents(ctx, notify); err != nil && ctx.Err() == nil {
+				slog.Debug(
+					"event stream failed, reconnecting",
+					"retry_in", eventsRetry,
+					"err", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(eventsRetry):
+			}
+		}
+	}()
+
+	return ch
+}
+
+// followEvents reads the server's event stream and calls onEvent for each
+// event until the stream ends or ctx is canceled.
+func (b *apiBackend) followEvents(ctx context.Context, onEvent func()) error {
+	r, err := b.request(ctx, "GET", "/api/events", nil, nil, "")
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	var hasEvent bool
+
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends an event. Comments, such as heartbeats,
+			// aren't events.
+			if hasEvent {
+				onEvent()
+			}
+			hasEvent = false
+		case strings.HasPrefix(line, "event:"), strings.HasPrefix(line, "data:"):
+			hasEvent = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return errors.New("event stream ended")
+}
+
+func (b *apiBackend) Close() error {
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAPIBackendChanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/events" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": heartbeat\n\n")
+		fmt.Fprint(w, "id: 1\nevent: dose-recorded\ndata: {}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	b := &apiBackend{base: u, client: srv.Client()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := b.Changes(ctx)
+
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change for event")
+	}
+
+	select {
+	case <-changes:
+		t.Fatal("change for heartbeat")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	for range changes {
+	}
+}
@@ -0,0 +1,595 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/notify"
+)
+
+// outputFlags are the flags shared by commands that print data.
+type outputFlags struct {
+	json    bool
+	regimen string
+}
+
+func (o *outputFlags) register(flags *flag.FlagSet) {
+	flags.BoolVar(&o.json, "json", false, "print the output as JSON")
+	flags.StringVar(&o.regimen, "regimen", "", "HRT type of the regimen, defaults to the configured one")
+}
+
+func (o *outputFlags) hrtType() hrtclicker.HRTType {
+	return hrtclicker.HRTType(o.regimen)
+}
+
+// print prints v as JSON if --json is given, otherwise it calls human.
+func (o *outputFlags) print(v any, human func()) error {
+	if o.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	human()
+	return nil
+}
+
+func record(ctx context.Context, args []string) error {
+	var out outputFlags
+	var at string
+	var notes string
+	var tags string
+
+	flags := newFlagSet("record", "")
+	flags.StringVar(&at, "at", "",
+		"when the dose was taken instead of now, as RFC 3339, \"2006-01-02 15:04\", "+
+			"\"15:04\" today or a duration ago such as \"2h30m\"")
+	flags.StringVar(&notes, "notes", "", "free-text notes about the dose")
+	flags.StringVar(&tags, "tags", "", "comma-separated tags, such as \"removed-early,new-brand\"")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	t := time.Now()
+	if at != "" {
+		t, err = parseTime(at, time.Now(), loc)
+		if err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+	}
+
+	dose, err := b.Record(ctx, out.hrtType(), t, strings.TrimSpace(notes), db.ParseTags(tags))
+	if err != nil {
+		return fmt.Errorf("failed to record dose: %w", err)
+	}
+
+	return out.print(dose, func() {
+		fmt.Printf("Recorded %s dose at %s.\n", dose.HRTType, formatTime(dose.DosageAt, loc))
+	})
+}
+
+func undo(ctx context.Context, args []string) error {
+	var out outputFlags
+
+	flags := newFlagSet("undo", "")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	dose, err := b.Undo(ctx, out.hrtType())
+	if err != nil {
+		return fmt.Errorf("failed to delete last dose: %w", err)
+	}
+
+	return out.print(dose, func() {
+		fmt.Printf("Deleted %s dose taken at %s.\n", dose.HRTType, formatTime(dose.DosageAt, loc))
+	})
+}
+
+func next(ctx context.Context, args []string) error {
+	var out outputFlags
+
+	flags := newFlagSet("next", "")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	next, err := b.NextDose(ctx, out.hrtType())
+	if err != nil {
+		return fmt.Errorf("failed to get next dose: %w", err)
+	}
+
+	return out.print(next, func() {
+		if p := next.Paused; p != nil {
+			if p.EndedAt.Valid {
+				fmt.Printf("Your %s regimen is paused until %s.\n", next.HRTType, formatTime(p.EndedAt.Time, loc))
+			} else {
+				fmt.Printf("Your %s regimen is paused until you resume it.\n", next.HRTType)
+			}
+			if next.NextDoseAt.IsZero() {
+				return
+			}
+		}
+
+		if next.NextDoseAt.IsZero() {
+			fmt.Println("Waiting for your first dose!")
+			return
+		}
+
+		verb := "is"
+		if next.NextDoseAt.Before(time.Now()) {
+			verb = "was"
+		}
+
+		fmt.Printf("Your next %s dose %s due %s (%s).\n",
+			next.HRTType, verb, formatRelative(next.NextDoseAt), formatTime(next.NextDoseAt, loc))
+		fmt.Printf("Your last dose was %s (%s).\n",
+			formatRelative(next.LastDoseAt), formatTime(next.LastDoseAt, loc))
+		if next.SnoozedUntil.After(time.Now()) {
+			fmt.Printf("The reminder is snoozed until %s.\n", formatTime(next.SnoozedUntil, loc))
+		}
+		if next.Advice != nil && next.Advice.Message != "" {
+			fmt.Println(next.Advice.Message)
+		}
+	})
+}
+
+func snooze(ctx context.Context, args []string) error {
+	var out outputFlags
+	var d time.Duration
+
+	flags := newFlagSet("snooze", "")
+	flags.DurationVar(&d, "for", notify.DefaultSnooze, "how long to snooze the reminder for")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	snooze, err := b.Snooze(ctx, out.hrtType(), d)
+	if err != nil {
+		return fmt.Errorf("failed to snooze: %w", err)
+	}
+
+	return out.print(snooze, func() {
+		fmt.Printf("Reminder snoozed until %s.\n", formatTime(snooze.SnoozedUntil, loc))
+	})
+}
+
+func skip(ctx context.Context, args []string) error {
+	var out outputFlags
+	var due string
+	var reason string
+
+	flags := newFlagSet("skip", "")
+	flags.StringVar(&due, "due", "",
+		"when the skipped dose was due, defaults to the next dose; "+
+			"as RFC 3339, \"2006-01-02 15:04\" or \"15:04\" today")
+	flags.StringVar(&reason, "reason", "", "why the dose is skipped, such as \"forgot\"")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	var dueAt time.Time
+	if due != "" {
+		dueAt, err = parseTime(due, time.Now(), loc)
+		if err != nil {
+			return fmt.Errorf("invalid --due: %w", err)
+		}
+	}
+
+	skip, err := b.Skip(ctx, out.hrtType(), dueAt, strings.TrimSpace(reason))
+	if err != nil {
+		return fmt.Errorf("failed to skip: %w", err)
+	}
+
+	return out.print(skip, func() {
+		fmt.Printf("Skipped the %s dose due %s.\n", skip.HRTType, formatTime(skip.DueAt, loc))
+	})
+}
+
+func remove(ctx context.Context, args []string) error {
+	var out outputFlags
+	var dose string
+	var at string
+	var reason string
+
+	flags := newFlagSet("remove", "")
+	flags.StringVar(&dose, "dose", "",
+		"when the removed dose was taken, defaults to the most recent dose; "+
+			"the last dose taken by then is used, in the same formats as --at")
+	flags.StringVar(&at, "at", "",
+		"when the patch was taken off instead of now, as RFC 3339, \"2006-01-02 15:04\", "+
+			"\"15:04\" today or a duration ago such as \"2h30m\"")
+	flags.StringVar(&reason, "reason", "", "why the patch was taken off, such as \"fell off\"")
+	out.register(flags)
+	flags.Parse(args)
+
+	now := time.Now()
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	removedAt := now
+	if at != "" {
+		removedAt, err = parseTime(at, now, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+	}
+
+	var dosageAt time.Time
+	if dose != "" {
+		t, err := parseTime(dose, now, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --dose: %w", err)
+		}
+
+		// The given time is only precise to the minute, so pick the exact
+		// dose from the history.
+		doses, err := b.History(ctx, out.hrtType(), 0, 0, "")
+		if err != nil {
+			return fmt.Errorf("failed to get doses: %w", err)
+		}
+		for _, d := range doses {
+			if d.DosageAt.Before(t.Add(time.Minute)) {
+				dosageAt = d.DosageAt
+				break
+			}
+		}
+		if dosageAt.IsZero() {
+			return fmt.Errorf("no dose taken by %s", formatTime(t, loc))
+		}
+	}
+
+	rm, err := b.Remove(ctx, out.hrtType(), dosageAt, removedAt, strings.TrimSpace(reason))
+	if err != nil {
+		return fmt.Errorf("failed to remove dose: %w", err)
+	}
+
+	return out.print(rm, func() {
+		fmt.Printf("Recorded the patch from %s as taken off at %s.\n",
+			formatTime(rm.DosageAt, loc), formatTime(rm.RemovedAt, loc))
+	})
+}
+
+func pauseCmd(ctx context.Context, args []string) error {
+	var out outputFlags
+	var at string
+	var until string
+	var reason string
+
+	flags := newFlagSet("pause", "")
+	flags.StringVar(&at, "at", "",
+		"when the pause starts instead of now, as RFC 3339, \"2006-01-02 15:04\", "+
+			"\"15:04\" today or a duration ago such as \"2h30m\"")
+	flags.StringVar(&until, "until", "",
+		"when the pause ends, as RFC 3339 or \"2006-01-02 15:04\"; "+
+			"without it, the pause lasts until the regimen is resumed")
+	flags.StringVar(&reason, "reason", "", "why the regimen is paused, such as \"surgery\"")
+	out.register(flags)
+	flags.Parse(args)
+
+	now := time.Now()
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	startedAt := now
+	if at != "" {
+		startedAt, err = parseTime(at, now, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+	}
+
+	var endedAt time.Time
+	if until != "" {
+		endedAt, err = parseTime(until, now, loc)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	p, err := b.Pause(ctx, out.hrtType(), startedAt, endedAt, strings.TrimSpace(reason))
+	if err != nil {
+		return fmt.Errorf("failed to pause: %w", err)
+	}
+
+	return out.print(p, func() {
+		if p.EndedAt.Valid {
+			fmt.Printf("Paused %s from %s until %s.\n",
+				p.HRTType, formatTime(p.StartedAt, loc), formatTime(p.EndedAt.Time, loc))
+		} else {
+			fmt.Printf("Paused %s from %s until resumed.\n", p.HRTType, formatTime(p.StartedAt, loc))
+		}
+	})
+}
+
+func resume(ctx context.Context, args []string) error {
+	var out outputFlags
+
+	flags := newFlagSet("resume", "")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	p, err := b.Resume(ctx, out.hrtType())
+	if err != nil {
+		return fmt.Errorf("failed to resume: %w", err)
+	}
+
+	return out.print(p, func() {
+		fmt.Printf("Resumed %s, paused since %s.\n", p.HRTType, formatTime(p.StartedAt, loc))
+	})
+}
+
+func history(ctx context.Context, args []string) error {
+	var out outputFlags
+	var d time.Duration
+	var limit int
+	var tag string
+
+	flags := newFlagSet("history", "")
+	flags.DurationVar(&d, "range", 0, "only list doses within this duration, such as 720h")
+	flags.IntVar(&limit, "limit", 0, "only list this many of the most recent doses")
+	flags.StringVar(&tag, "tag", "", "only list doses with this tag")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	doses, err := b.History(ctx, out.hrtType(), d, limit, tag)
+	if err != nil {
+		return fmt.Errorf("failed to get history: %w", err)
+	}
+
+	return out.print(doses, func() {
+		if len(doses) == 0 {
+			fmt.Println("No doses recorded.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		fmt.Fprintln(w, "WHEN\tTYPE\tAGO\tINTERVAL\tTAGS\tNOTES")
+		for i, dose := range doses {
+			interval := "-"
+			if i+1 < len(doses) {
+				interval = formatDuration(dose.DosageAt.Sub(doses[i+1].DosageAt))
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				formatTime(dose.DosageAt, loc), dose.HRTType, formatRelative(dose.DosageAt), interval,
+				orDash(dose.Tags.String()), orDash(dose.Notes))
+		}
+	})
+}
+
+func levelsCmd(ctx context.Context, args []string) error {
+	var out outputFlags
+	var d time.Duration
+
+	flags := newFlagSet("levels", "")
+	flags.DurationVar(&d, "range", 24*time.Hour, "predict the levels over this duration")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	values, err := b.Levels(ctx, out.hrtType(), d)
+	if err != nil {
+		return fmt.Errorf("failed to get levels: %w", err)
+	}
+
+	return out.print(values, func() {
+		if len(values) == 0 {
+			fmt.Println("No levels to predict, record a dose first.")
+			return
+		}
+
+		const barWidth = 40
+		var maxValue float64
+		for _, v := range values {
+			maxValue = max(maxValue, v.V)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		for _, v := range values {
+			bar := 0
+			if maxValue > 0 {
+				bar = int(v.V / maxValue * barWidth)
+			}
+			fmt.Fprintf(w, "%s\t%.1f pg/mL\t%s\n",
+				formatTime(v.T.Time(), loc), v.V, strings.Repeat("█", bar))
+		}
+
+		last := values[len(values)-1]
+		fmt.Fprintf(w, "\nEstimated level now:\t%.1f pg/mL\t\n", last.V)
+	})
+}
+
+func notifyTest(ctx context.Context, args []string) error {
+	flags := newFlagSet("notify-test", "")
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	if err := b.NotifyTest(ctx); err != nil {
+		return fmt.Errorf("failed to send test notification: %w", err)
+	}
+
+	fmt.Println("Notification sent, go check your phone!")
+	return nil
+}
+
+func configCmd(ctx context.Context, args []string) error {
+	flags := newFlagSet("config", "check")
+	flags.Parse(args)
+
+	switch flags.Arg(0) {
+	case "check":
+		return configCheck(flags.Args()[1:])
+	case "":
+		flags.Usage()
+		return errFailed
+	default:
+		return fmt.Errorf("unknown subcommand %q", flags.Arg(0))
+	}
+}
+
+func configCheck(args []string) error {
+	flags := newFlagSet("config", "check")
+	flags.Parse(args)
+
+	cfg, err := hrtclicker.ReadJSONConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s has problems:\n", configPath)
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "  - %s\n", line)
+		}
+		return errFailed
+	}
+
+	fmt.Printf("%s is valid.\n", configPath)
+	return nil
+}
+
+// parseTime parses the time given to the --at flag. now is used for times
+// that are relative to the current time.
+func parseTime(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, loc); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("15:04", s, loc); err == nil {
+		y, m, d := now.In(loc).Date()
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc), nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			d = -d
+		}
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, errors.New("unknown time format")
+}
+
+func formatTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("Mon 2006-01-02 15:04 MST")
+}
+
+func formatRelative(t time.Time) string {
+	d := time.Until(t)
+	if d < 0 {
+		return formatDuration(-d) + " ago"
+	}
+	return "in " + formatDuration(d)
+}
+
+// formatDuration formats the duration to the nearest minute, omitting the
+// seconds that time.Duration.String would print.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh%dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	// It is already Sunday in Berlin, the day summer time starts.
+	now := time.Date(2026, 3, 28, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-29T08:00:00Z", time.Date(2026, 3, 29, 8, 0, 0, 0, time.UTC)},
+		{"2026-03-29 08:00", time.Date(2026, 3, 29, 6, 0, 0, 0, time.UTC)},
+		{"08:00", time.Date(2026, 3, 29, 6, 0, 0, 0, time.UTC)},
+		{"00:30", time.Date(2026, 3, 28, 23, 30, 0, 0, time.UTC)},
+		{"2h", time.Date(2026, 3, 28, 21, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := parseTime(test.in, now, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("parseTime(%q) = %s, want %s", test.in, got.UTC(), test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/journal"
+)
+
+func journalCmd(ctx context.Context, args []string) error {
+	flags := newFlagSet("journal", "add|list|analyze")
+	flags.Parse(args)
+
+	switch flags.Arg(0) {
+	case "add":
+		return journalAdd(ctx, flags.Args()[1:])
+	case "list":
+		return journalList(ctx, flags.Args()[1:])
+	case "analyze":
+		return journalAnalyze(ctx, flags.Args()[1:])
+	case "":
+		flags.Usage()
+		return errFailed
+	default:
+		return fmt.Errorf("unknown subcommand %q", flags.Arg(0))
+	}
+}
+
+func journalAdd(ctx context.Context, args []string) error {
+	var out outputFlags
+	var at string
+	var mood int
+	var tags string
+
+	flags := newFlagSet("journal", "add [notes...]")
+	flags.StringVar(&at, "at", "",
+		"when the entry was written instead of now, in the same formats as record --at")
+	flags.IntVar(&mood, "mood", 0,
+		fmt.Sprintf("mood from %d (worst) to %d (best)", journal.MinMood, journal.MaxMood))
+	flags.StringVar(&tags, "tags", "", "comma-separated symptoms, such as \"hot flashes,fatigue\"")
+	out.register(flags)
+	flags.Parse(args)
+
+	if mood != 0 && (mood < journal.MinMood || mood > journal.MaxMood) {
+		return fmt.Errorf("invalid --mood: must be from %d to %d", journal.MinMood, journal.MaxMood)
+	}
+
+	notes := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	parsedTags := db.ParseTags(tags)
+	if mood == 0 && notes == "" && len(parsedTags) == 0 {
+		return errors.New("an entry needs --mood, --tags or notes")
+	}
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	t := time.Now()
+	if at != "" {
+		t, err = parseTime(at, time.Now(), loc)
+		if err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+	}
+
+	entry, err := b.AddJournalEntry(ctx, out.hrtType(), t, mood, notes, parsedTags)
+	if err != nil {
+		return fmt.Errorf("failed to add journal entry: %w", err)
+	}
+
+	return out.print(entry, func() {
+		fmt.Printf("Added journal entry at %s.\n", formatTime(entry.WrittenAt, loc))
+	})
+}
+
+func journalList(ctx context.Context, args []string) error {
+	var out outputFlags
+	var d time.Duration
+	var tag string
+
+	flags := newFlagSet("journal", "list")
+	flags.DurationVar(&d, "range", 0, "only list entries within this duration, such as 720h")
+	flags.StringVar(&tag, "tag", "", "only list entries with this tag")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	entries, err := b.Journal(ctx, out.hrtType(), d, tag)
+	if err != nil {
+		return fmt.Errorf("failed to get journal: %w", err)
+	}
+
+	return out.print(entries, func() {
+		if len(entries) == 0 {
+			fmt.Println("No journal entries.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		fmt.Fprintln(w, "WHEN\tMOOD\tTAGS\tNOTES")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				formatTime(entry.WrittenAt, loc), formatMood(entry.Mood.Int64, entry.Mood.Valid),
+				orDash(entry.Tags.String()), orDash(entry.Notes))
+		}
+	})
+}
+
+func journalAnalyze(ctx context.Context, args []string) error {
+	var out outputFlags
+	var d time.Duration
+	var tag string
+
+	flags := newFlagSet("journal", "analyze")
+	flags.DurationVar(&d, "range", 0, "only analyze entries within this duration, such as 720h")
+	flags.StringVar(&tag, "tag", "", "only analyze entries with this tag")
+	out.register(flags)
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	loc := b.Location(out.hrtType())
+
+	analysis, err := b.JournalAnalysis(ctx, out.hrtType(), d, tag)
+	if err != nil {
+		return fmt.Errorf("failed to analyze journal: %w", err)
+	}
+
+	return out.print(analysis, func() {
+		if len(analysis.Entries) == 0 {
+			fmt.Println("No journal entries.")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(w, "WHEN\tMOOD\tLEVEL\tSINCE DOSE\tTAGS")
+		for _, entry := range analysis.Entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				formatTime(entry.WrittenAt, loc), formatMood(entry.Mood.Int64, entry.Mood.Valid),
+				formatLevel(entry.Level), formatSinceDose(entry.LastDoseAt, entry.SinceDose),
+				orDash(entry.Tags.String()))
+		}
+		w.Flush()
+
+		if len(analysis.Tags) > 0 {
+			fmt.Println()
+			fmt.Fprintln(w, "TAG\tENTRIES\tMEAN MOOD\tMEAN LEVEL\tMEAN SINCE DOSE")
+			for _, s := range analysis.Tags {
+				mood := "-"
+				if s.MeanMood > 0 {
+					mood = fmt.Sprintf("%.1f", s.MeanMood)
+				}
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n",
+					s.Tag, s.Count, mood, formatLevel(s.MeanLevel), formatDuration(s.MeanSinceDose))
+			}
+			w.Flush()
+		}
+
+		if r := analysis.MoodLevelCorrelation; r != nil {
+			fmt.Printf("\nCorrelation between mood and level: %+.2f\n", *r)
+		}
+	})
+}
+
+func formatMood(mood int64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", mood, journal.MaxMood)
+}
+
+func formatLevel(level float64) string {
+	if level <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f pg/mL", level)
+}
+
+func formatSinceDose(lastDoseAt time.Time, d time.Duration) string {
+	if lastDoseAt.IsZero() {
+		return "-"
+	}
+	return formatDuration(d)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -2,123 +2,184 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"log/slog"
+	"fmt"
 	"os"
 	"os/signal"
-
-	"golang.org/x/sync/errgroup"
-	"libdb.so/hrtclicker"
-	"libdb.so/hrtclicker/db"
-	"libdb.so/hrtclicker/notify"
-	"libdb.so/hrtclicker/server"
-	"libdb.so/hrtclicker/web"
-	"libdb.so/hserve"
-	"libdb.so/tmplutil"
+	"slices"
 )
 
 var (
 	configPath   = "config.json"
 	httpAddress  = ":8375"
 	databasePath = "/tmp/hrtclicker.db"
+	serverURL    = os.Getenv("HRTCLICKER_SERVER")
 )
 
-func main() {
-	flag.StringVar(&configPath, "c", configPath, "path to the configuration file")
-	flag.StringVar(&httpAddress, "l", httpAddress, "address to listen on for HTTP requests")
-	flag.StringVar(&databasePath, "db", databasePath, "path to the SQLite database file")
-	flag.Parse()
+// registerGlobalFlags registers the flags that are shared by all commands.
+// They may be given either before or after the command name.
+func registerGlobalFlags(flags *flag.FlagSet) {
+	flags.StringVar(&configPath, "c", configPath, "path to the configuration file")
+	flags.StringVar(&httpAddress, "l", httpAddress, "address to listen on for HTTP requests")
+	flags.StringVar(&databasePath, "db", databasePath, "path to the SQLite database file")
+	flags.StringVar(&serverURL, "server", serverURL,
+		"URL of a running hrtclicker server to use instead of the database file, "+
+			"defaults to $HRTCLICKER_SERVER")
+}
 
-	if !run(context.Background()) {
-		os.Exit(1)
+// newFlagSet creates a flag set for the command with the given name and
+// arguments usage. The global flags are already registered.
+func newFlagSet(name, argsUsage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: hrt-clicker %s [flags] %s\n", name, argsUsage)
+		fmt.Fprintf(flags.Output(), "\n%s\n\nFlags:\n", commands[name].description)
+		flags.PrintDefaults()
 	}
+	registerGlobalFlags(flags)
+	return flags
 }
 
-func run(ctx context.Context) bool {
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
-	defer cancel()
+type command struct {
+	description string
+	run         func(ctx context.Context, args []string) error
+}
 
-	cfg, err := hrtclicker.ReadJSONConfigFile(configPath)
-	if err != nil {
-		slog.Error(
-			"failed to read config",
-			"config_path", configPath,
-			"err", err)
-		return false
+var commands map[string]command
+
+func init() {
+	// This is initialized in init to avoid an initialization cycle from
+	// newFlagSet referring to commands.
+	commands = map[string]command{
+		"serve": {
+			"Run the web server and the notification monitor. This is the default command.",
+			serve,
+		},
+		"record": {
+			"Record a dose.",
+			record,
+		},
+		"undo": {
+			"Delete the last recorded dose.",
+			undo,
+		},
+		"next": {
+			"Show when the next dose is due.",
+			next,
+		},
+		"history": {
+			"List the recorded doses.",
+			history,
+		},
+		"snooze": {
+			"Snooze the reminder for the next dose.",
+			snooze,
+		},
+		"skip": {
+			"Skip a missed dose, so the next one is due as if it was taken on time.",
+			skip,
+		},
+		"remove": {
+			"Record that a patch was taken off, such as because it fell off.",
+			remove,
+		},
+		"pause": {
+			"Pause the reminders of a regimen, such as before surgery.",
+			pauseCmd,
+		},
+		"resume": {
+			"Resume a paused regimen.",
+			resume,
+		},
+		"tui": {
+			"Show a live dashboard in the terminal.",
+			tui,
+		},
+		"levels": {
+			"Show the predicted hormone levels.",
+			levelsCmd,
+		},
+		"report": {
+			"Write a printable HTML report of the doses and adherence over a date range.",
+			reportCmd,
+		},
+		"export": {
+			"Export the doses as CSV or JSON Lines, or everything as a JSON archive.",
+			export,
+		},
+		"import": {
+			"Import doses from a CSV, JSON Lines or JSON archive file.",
+			importCmd,
+		},
+		"journal": {
+			"Add, list or analyze symptom and mood journal entries.",
+			journalCmd,
+		},
+		"notify-test": {
+			"Send a test notification.",
+			notifyTest,
+		},
+		"config": {
+			"Work with the configuration file. The only subcommand is check.",
+			configCmd,
+		},
 	}
+}
 
-	var tmpl *web.Templates
-	if s, err := os.Stat("web"); err == nil && s.IsDir() {
-		// We're running from the source directory. Use that directly.
-		fs := os.DirFS("web")
-		slog.Info("using local web templates")
-
-		tmpl, err = web.NewTemplates(fs)
-		if err != nil {
-			slog.Error(
-				"failed to create local templates",
-				"err", err)
-			return false
-		}
+// errFailed is returned by commands that have already reported their error.
+var errFailed = errors.New("command failed")
+
+func main() {
+	flag.Usage = usage
+	registerGlobalFlags(flag.CommandLine)
+	flag.Parse()
 
-		// Force templates to be reloaded on every request.
-		tmplutil.DebugMode = true
+	name := flag.Arg(0)
+	args := flag.Args()
+	if name == "" {
+		name = "serve"
 	} else {
-		slog.Debug("using embedded web templates")
-		tmpl = web.EmbeddedTemplates()
+		args = args[1:]
 	}
 
-	db, err := db.Open(databasePath)
-	if err != nil {
-		slog.Error(
-			"failed to open database",
-			"database_path", databasePath,
-			"err", err)
-		return false
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "hrt-clicker: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
 	}
-	defer db.Close()
-
-	errg, ctx := errgroup.WithContext(ctx)
-
-	errg.Go(func() error {
-		slog.Info(
-			"starting server",
-			"http_address", httpAddress)
-
-		server := server.New(server.Dependencies{
-			Logger:    slog.Default().With("component", "http"),
-			Database:  db,
-			Config:    cfg,
-			Templates: tmpl,
-		})
-
-		if err := hserve.ListenAndServe(ctx, httpAddress, server); err != nil {
-			slog.Error(
-				"failed to serve HTTP server",
-				"http_address", httpAddress,
-				"err", err)
-			return err
-		}
 
-		return nil
-	})
-
-	errg.Go(func() error {
-		monitor := notify.NewMonitor(notify.Dependencies{
-			Logger:   slog.Default().With("component", "monitor"),
-			Config:   cfg,
-			Database: db,
-		})
-
-		if err := monitor.Run(ctx); err != nil {
-			slog.Error(
-				"failed to run monitor",
-				"err", err)
-			return err
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := cmd.run(ctx, args); err != nil {
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintf(os.Stderr, "hrt-clicker %s: %v\n", name, err)
 		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintln(w, "Usage: hrt-clicker [flags] [command] [command flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
 
-		return nil
-	})
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].description)
+	}
 
-	return errg.Wait() == nil
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run hrt-clicker [command] -h for the command's flags.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	flag.PrintDefaults()
 }
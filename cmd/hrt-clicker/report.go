@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/report"
+)
+
+func reportCmd(ctx context.Context, args []string) error {
+	var regimen string
+	var from, to string
+	var output string
+
+	flags := newFlagSet("report", "")
+	flags.StringVar(&regimen, "regimen", "", "HRT type of the regimen, defaults to the configured one")
+	flags.StringVar(&from, "from", "", "first day of the report as 2006-01-02, defaults to 90 days before --to")
+	flags.StringVar(&to, "to", "", "last day of the report as 2006-01-02, defaults to now")
+	flags.StringVar(&output, "o", "", "file to write the HTML report to instead of stdout")
+	flags.Parse(args)
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	fromTime, toTime, err := report.ParseRange(from, to, time.Now(), b.Location(hrtclicker.HRTType(regimen)))
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		if err := b.Report(ctx, hrtclicker.HRTType(regimen), fromTime, toTime, os.Stdout); err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := b.Report(ctx, hrtclicker.HRTType(regimen), fromTime, toTime, f); err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Report written to %s.\n", output)
+	return nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/hooks"
+	"libdb.so/hrtclicker/notify"
+	"libdb.so/hrtclicker/server"
+	"libdb.so/hrtclicker/web"
+	"libdb.so/hrtclicker/webhooks"
+	"libdb.so/hserve"
+	"libdb.so/tmplutil"
+)
+
+func serve(ctx context.Context, args []string) error {
+	flags := newFlagSet("serve", "")
+	flags.Parse(args)
+
+	if !run(ctx) {
+		return errFailed
+	}
+	return nil
+}
+
+func run(ctx context.Context) bool {
+	cfg, err := hrtclicker.NewReloadableConfig(configPath)
+	if err != nil {
+		slog.Error(
+			"failed to read config",
+			"config_path", configPath,
+			"err", err)
+		return false
+	}
+
+	if err := cfg.Load().Validate(); err != nil {
+		slog.Warn(
+			"config has problems, run the config check command for details",
+			"config_path", configPath,
+			"err", err)
+	}
+
+	var tmpl *web.Templates
+	if s, err := os.Stat("web"); err == nil && s.IsDir() {
+		// We're running from the source directory. Use that directly.
+		fs := os.DirFS("web")
+		slog.Info("using local web templates")
+
+		tmpl, err = web.NewTemplates(fs)
+		if err != nil {
+			slog.Error(
+				"failed to create local templates",
+				"err", err)
+			return false
+		}
+
+		// Force templates to be reloaded on every request.
+		tmplutil.DebugMode = true
+	} else {
+		slog.Debug("using embedded web templates")
+		tmpl = web.EmbeddedTemplates()
+	}
+
+	// Show times in the time zone of the regimen, which a reload can change.
+	tmpl.Location = func() *time.Location { return cfg.Load().HRT.Location() }
+
+	db, err := db.Open(databasePath)
+	if err != nil {
+		slog.Error(
+			"failed to open database",
+			"database_path", databasePath,
+			"err", err)
+		return false
+	}
+	defer db.Close()
+
+	bus := &events.Bus{Logger: slog.Default().With("component", "events")}
+
+	errg, ctx := errgroup.WithContext(ctx)
+
+	errg.Go(func() error {
+		slog.Info(
+			"starting server",
+			"http_address", httpAddress)
+
+		server := server.New(server.Dependencies{
+			Logger:    slog.Default().With("component", "http"),
+			Database:  db,
+			Config:    cfg,
+			Templates: tmpl,
+			Events:    bus,
+		})
+
+		if err := hserve.ListenAndServe(ctx, httpAddress, server); err != nil {
+			slog.Error(
+				"failed to serve HTTP server",
+				"http_address", httpAddress,
+				"err", err)
+			return err
+		}
+
+		return nil
+	})
+
+	errg.Go(func() error {
+		monitor := notify.NewMonitor(notify.Dependencies{
+			Logger:   slog.Default().With("component", "monitor"),
+			Config:   cfg,
+			Database: db,
+			Events:   bus,
+		})
+
+		if err := monitor.Run(ctx); err != nil {
+			slog.Error(
+				"failed to run monitor",
+				"err", err)
+			return err
+		}
+
+		return nil
+	})
+
+	errg.Go(func() error {
+		dispatcher := webhooks.NewDispatcher(webhooks.Dependencies{
+			Logger:   slog.Default().With("component", "webhooks"),
+			Config:   cfg,
+			Database: db,
+			Events:   bus,
+		})
+
+		if err := dispatcher.Run(ctx); err != nil {
+			slog.Error(
+				"failed to run webhook dispatcher",
+				"err", err)
+			return err
+		}
+
+		return nil
+	})
+
+	errg.Go(func() error {
+		runner := hooks.NewRunner(hooks.Dependencies{
+			Logger:   slog.Default().With("component", "hooks"),
+			Config:   cfg,
+			Database: db,
+			Events:   bus,
+		})
+
+		if err := runner.Run(ctx); err != nil {
+			slog.Error(
+				"failed to run command hooks",
+				"err", err)
+			return err
+		}
+
+		return nil
+	})
+
+	errg.Go(func() error {
+		reloadConfigOnSignal(ctx, cfg, bus)
+		return nil
+	})
+
+	return errg.Wait() == nil
+}
+
+// reloadConfigOnSignal reloads the configuration every time SIGHUP is received
+// until ctx is canceled.
+func reloadConfigOnSignal(ctx context.Context, cfg *hrtclicker.ReloadableConfig, bus *events.Bus) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			newCfg, err := cfg.Reload()
+			if err != nil {
+				slog.Error(
+					"failed to reload config, keeping the old one",
+					"config_path", configPath,
+					"err", err)
+				continue
+			}
+
+			slog.Info(
+				"reloaded config",
+				"config_path", configPath)
+
+			bus.Publish(events.ConfigReloaded, newCfg.HRT)
+		}
+	}
+}
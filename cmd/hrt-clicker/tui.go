@@ -0,0 +1,397 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/notify"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/schedule"
+)
+
+const (
+	escAltScreen   = "\x1b[?1049h"
+	escMainScreen  = "\x1b[?1049l"
+	escHideCursor  = "\x1b[?25l"
+	escShowCursor  = "\x1b[?25h"
+	escClearScreen = "\x1b[H\x1b[2J"
+	escBold        = "\x1b[1m"
+	escDim         = "\x1b[2m"
+	escPink        = "\x1b[38;5;218m"
+	escBlue        = "\x1b[38;5;117m"
+	escReset       = "\x1b[0m"
+)
+
+// recentDoses is the number of doses listed on the dashboard.
+const recentDoses = 5
+
+func tui(ctx context.Context, args []string) error {
+	var regimen string
+	var snoozeFor time.Duration
+	var plotRange time.Duration
+
+	flags := newFlagSet("tui", "")
+	flags.StringVar(&regimen, "regimen", "", "HRT type of the regimen, defaults to the configured one")
+	flags.DurationVar(&snoozeFor, "snooze", notify.DefaultSnooze, "how long the snooze key snoozes the reminder for")
+	flags.DurationVar(&plotRange, "range", 7*24*time.Hour, "plot the levels over this duration")
+	flags.Parse(args)
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("stdin is not a terminal")
+	}
+
+	b, err := openBackend()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to make terminal raw: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	os.Stdout.WriteString(escAltScreen + escHideCursor)
+	defer os.Stdout.WriteString(escShowCursor + escMainScreen)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan byte)
+	go func() {
+		defer cancel()
+		var buf [64]byte
+		for {
+			n, err := os.Stdin.Read(buf[:])
+			if err != nil {
+				return
+			}
+			for _, key := range buf[:n] {
+				select {
+				case keys <- key:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	t := &dashboard{
+		backend:   b,
+		hrtType:   hrtclicker.HRTType(regimen),
+		loc:       b.Location(hrtclicker.HRTType(regimen)),
+		snoozeFor: snoozeFor,
+		plotRange: plotRange,
+	}
+	t.refresh(ctx)
+	t.draw()
+
+	changes := b.Changes(ctx)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// Redraw for the countdown. This also picks up terminal resizes.
+		case _, ok := <-changes:
+			if !ok {
+				// A closed channel would be ready on every iteration.
+				changes = nil
+				continue
+			}
+			t.refresh(ctx)
+		case key := <-keys:
+			if !t.handleKey(ctx, key) {
+				return nil
+			}
+		}
+		t.draw()
+	}
+}
+
+// dashboard is the state of the terminal dashboard.
+type dashboard struct {
+	backend   backend
+	hrtType   hrtclicker.HRTType
+	loc       *time.Location
+	snoozeFor time.Duration
+	plotRange time.Duration
+
+	next    schedule.NextDose
+	doses   []db.HRTHistory
+	levels  []predict.TimeValue
+	status  string
+	pending byte // key waiting for confirmation
+}
+
+func (t *dashboard) refresh(ctx context.Context) {
+	var errs []error
+	var err error
+
+	t.next, err = t.backend.NextDose(ctx, t.hrtType)
+	errs = append(errs, err)
+
+	// One more than the recent doses are shown, for the interval of the last.
+	t.doses, err = t.backend.History(ctx, t.hrtType, 0, recentDoses+1, "")
+	errs = append(errs, err)
+
+	t.levels, err = t.backend.Levels(ctx, t.hrtType, t.plotRange)
+	errs = append(errs, err)
+
+	if err := errors.Join(errs...); err != nil {
+		t.status = "Failed to refresh: " + err.Error()
+	}
+}
+
+// handleKey handles a key press. It returns false if the dashboard should
+// quit.
+func (t *dashboard) handleKey(ctx context.Context, key byte) bool {
+	pending := t.pending
+	t.pending = 0
+
+	switch key {
+	case 'q', 3, 4: // q, ^C, ^D
+		return false
+
+	case 'r':
+		if pending != 'r' {
+			t.pending = 'r'
+			t.status = "Press r again to record a dose now."
+			return true
+		}
+		dose, err := t.backend.Record(ctx, t.hrtType, time.Now(), "", nil)
+		if err != nil {
+			t.status = "Failed to record dose: " + err.Error()
+			return true
+		}
+		t.status = "Recorded dose at " + formatTime(dose.DosageAt, t.loc) + "."
+
+	case 'u':
+		if pending != 'u' {
+			t.pending = 'u'
+			t.status = "Press u again to delete your last dose."
+			return true
+		}
+		dose, err := t.backend.Undo(ctx, t.hrtType)
+		if err != nil {
+			t.status = "Failed to delete dose: " + err.Error()
+			return true
+		}
+		t.status = "Deleted dose taken at " + formatTime(dose.DosageAt, t.loc) + "."
+
+	case 's':
+		snooze, err := t.backend.Snooze(ctx, t.hrtType, t.snoozeFor)
+		if err != nil {
+			t.status = "Failed to snooze: " + err.Error()
+			return true
+		}
+		t.status = "Reminder snoozed until " + formatTime(snooze.SnoozedUntil, t.loc) + "."
+
+	case 'R', 12: // R, ^L
+		t.status = ""
+
+	default:
+		t.status = ""
+		return true
+	}
+
+	t.refresh(ctx)
+	return true
+}
+
+func (t *dashboard) draw() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	var lines []string
+	line := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	hrtType := t.next.HRTType
+	if hrtType == "" {
+		hrtType = t.hrtType
+	}
+	line(escBold+"hrtclicker"+escReset+escDim+" · %s"+escReset, hrtType)
+	line("")
+
+	now := time.Now()
+	switch {
+	case t.next.NextDoseAt.IsZero() && t.next.Paused != nil:
+		line("  No doses are due while paused.")
+		line("")
+	case t.next.NextDoseAt.IsZero():
+		line("  Waiting for your first dose!")
+		line("")
+	case t.next.NextDoseAt.Before(now):
+		line("  Your next dose was due "+escPink+escBold+"%s ago"+escReset, formatCountdown(now.Sub(t.next.NextDoseAt)))
+		line("  at %s", formatTime(t.next.NextDoseAt, t.loc))
+	default:
+		line("  Your next dose is due in "+escBlue+escBold+"%s"+escReset, formatCountdown(t.next.NextDoseAt.Sub(now)))
+		line("  at %s", formatTime(t.next.NextDoseAt, t.loc))
+	}
+	if p := t.next.Paused; p != nil {
+		line(escDim+"  Paused since %s"+escReset, formatTime(p.StartedAt, t.loc))
+	} else if t.next.SnoozedUntil.After(now) {
+		line(escDim+"  Reminder snoozed until %s"+escReset, formatTime(t.next.SnoozedUntil, t.loc))
+	} else {
+		line("")
+	}
+	line("")
+
+	line(escBold + "Recent doses" + escReset)
+	recent := t.doses[:min(recentDoses, len(t.doses))]
+	if len(recent) == 0 {
+		line(escDim + "  none yet" + escReset)
+	}
+	for i, dose := range recent {
+		interval := ""
+		if i+1 < len(t.doses) {
+			interval = "  after " + formatDuration(dose.DosageAt.Sub(t.doses[i+1].DosageAt))
+		}
+		line("  %s  %-12s"+escDim+"%s"+escReset,
+			formatTime(dose.DosageAt, t.loc), formatRelative(dose.DosageAt), interval)
+	}
+	line("")
+
+	const footerHeight = 3
+	plotHeight := height - len(lines) - footerHeight - 2
+	if plotHeight >= 3 && len(t.levels) > 0 {
+		last := t.levels[len(t.levels)-1]
+		line(escBold+"Predicted levels"+escReset+escDim+" · now %.0f pg/mL"+escReset, last.V)
+		for _, l := range plotLevels(t.levels, width, plotHeight, t.loc) {
+			line(escPink+"%s"+escReset, l)
+		}
+	}
+
+	// Pad so that the footer is always at the bottom.
+	for len(lines) < height-footerHeight+1 {
+		line("")
+	}
+	line("%s", t.status)
+	line(escDim+"[r] record  [u] undo  [s] snooze %s  [q] quit"+escReset, formatDuration(t.snoozeFor))
+
+	var s strings.Builder
+	s.WriteString(escClearScreen)
+	for i, l := range lines[:min(len(lines), height)] {
+		if i > 0 {
+			s.WriteString("\r\n")
+		}
+		s.WriteString(truncate(l, width))
+	}
+	os.Stdout.WriteString(s.String())
+}
+
+// plotLevels plots the values as a chart of the given size in runes using
+// Unicode block elements. The Y axis is labeled on the left, and the X axis
+// in the given time zone.
+func plotLevels(values []predict.TimeValue, width, height int, loc *time.Location) []string {
+	const labelWidth = 5 // "1234┤"
+	plotWidth := width - labelWidth - 1
+	if plotWidth < 1 || height < 2 {
+		return nil
+	}
+	chartHeight := height - 1 // leave room for the X axis labels
+
+	// Average the values into one column each.
+	columns := make([]float64, plotWidth)
+	var maxValue float64
+	for c := range columns {
+		from := c * len(values) / plotWidth
+		to := max(from+1, (c+1)*len(values)/plotWidth)
+		to = min(to, len(values))
+		var sum float64
+		for _, v := range values[from:to] {
+			sum += v.V
+		}
+		columns[c] = sum / float64(to-from)
+		maxValue = max(maxValue, columns[c])
+	}
+
+	// Round the top of the chart up to a nice number.
+	top := math.Ceil(maxValue*1.1/50) * 50
+	if top == 0 {
+		top = 50
+	}
+
+	blocks := []rune(" ▁▂▃▄▅▆▇█")
+	lines := make([]string, 0, height)
+
+	for row := 0; row < chartHeight; row++ {
+		var s strings.Builder
+
+		switch row {
+		case 0:
+			fmt.Fprintf(&s, "%4.0f┤", top)
+		case chartHeight - 1:
+			fmt.Fprintf(&s, "%4d┤", 0)
+		case chartHeight / 2:
+			fmt.Fprintf(&s, "%4.0f┤", top/2)
+		default:
+			s.WriteString("    │")
+		}
+
+		bottom := (chartHeight - 1 - row) * 8
+		for _, v := range columns {
+			eighths := int(math.Round(v / top * float64(chartHeight*8)))
+			fill := min(max(eighths-bottom, 0), 8)
+			s.WriteRune(blocks[fill])
+		}
+
+		lines = append(lines, s.String())
+	}
+
+	from := values[0].T.Time().In(loc).Format("Jan 2 15:04")
+	to := "now"
+	padding := max(1, plotWidth-utf8.RuneCountInString(from)-len(to))
+	lines = append(lines, strings.Repeat(" ", labelWidth)+from+strings.Repeat(" ", padding)+to)
+
+	return lines
+}
+
+// formatCountdown formats the duration to the second.
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	s := d % time.Minute / time.Second
+	return fmt.Sprintf("%s %02ds", formatDuration(d.Truncate(time.Minute)), s)
+}
+
+// truncate truncates the string to the given width in runes, ignoring ANSI
+// escape sequences.
+func truncate(s string, width int) string {
+	var out strings.Builder
+	var n int
+	var escape bool
+	for _, r := range s {
+		switch {
+		case escape:
+			escape = r != 'm'
+		case r == '\x1b':
+			escape = true
+		default:
+			if n >= width {
+				continue
+			}
+			n++
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
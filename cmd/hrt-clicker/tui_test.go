@@ -0,0 +1,127 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker/predict"
+)
+
+func TestPlotLevels(t *testing.T) {
+	from := time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC)
+	levels := func(values ...float64) []predict.TimeValue {
+		levels := make([]predict.TimeValue, len(values))
+		for i, v := range values {
+			levels[i] = predict.TimeValue{T: predict.Timestamp(from.Add(time.Duration(i) * time.Hour).Unix()), V: v}
+		}
+		return levels
+	}
+	xAxis := "     Mar 28 00:00 now"
+
+	tests := []struct {
+		name          string
+		values        []predict.TimeValue
+		width, height int
+		want          []string
+	}{
+		{
+			// The top is rounded up to 50 from 10% above the highest value,
+			// and every row is 8 eighths of a block.
+			name:   "column per value",
+			values: levels(0, 15, 30, 45),
+			width:  10,
+			height: 4,
+			want: []string{
+				"  50┤   ▆",
+				"  25┤  ▆█",
+				"   0┤ ▇██",
+				xAxis,
+			},
+		},
+		{
+			name:   "values averaged into columns",
+			values: levels(0, 30, 15, 45, 45, 45, 40, 50),
+			width:  10,
+			height: 3,
+			want: []string{
+				"  50┤ ▂▆▆",
+				"   0┤▅███",
+				xAxis,
+			},
+		},
+		{
+			name:   "no values above zero",
+			values: levels(0, 0),
+			width:  8,
+			height: 3,
+			want: []string{
+				"  50┤  ",
+				"   0┤  ",
+				xAxis,
+			},
+		},
+		{
+			name:   "too narrow",
+			values: levels(10, 20),
+			width:  6,
+			height: 4,
+		},
+		{
+			name:   "too short",
+			values: levels(10, 20),
+			width:  10,
+			height: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := plotLevels(test.values, test.width, test.height, time.UTC)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("plotLevels() =\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{42 * time.Second, "0m 42s"},
+		{5*time.Minute + 3*time.Second, "5m 03s"},
+		{26*time.Hour + 4*time.Minute + 59*time.Second + 600*time.Millisecond, "1d2h5m 00s"},
+	}
+
+	for _, test := range tests {
+		if got := formatCountdown(test.d); got != test.want {
+			t.Errorf("formatCountdown(%s) = %q, want %q", test.d, got, test.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"short", "hello", 10, "hello"},
+		{"long", "hello", 3, "hel"},
+		{"runes", "▁▂▃▄▅", 2, "▁▂"},
+		// Escape sequences take no space and are kept so that the colors
+		// are reset at the end of the line.
+		{"escapes", escBold + "hello" + escReset, 3, escBold + "hel" + escReset},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := truncate(test.s, test.width); got != test.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", test.s, test.width, got, test.want)
+			}
+		})
+	}
+}
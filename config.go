@@ -2,10 +2,17 @@ package hrtclicker
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"slices"
+	"sync/atomic"
+	"text/template"
+	"time"
 
+	"libdb.so/hrtclicker/events"
 	"libdb.so/hrtclicker/internal/cfgtypes"
 )
 
@@ -13,17 +20,553 @@ type HRTConfig struct {
 	Type        HRTType           `json:"type"`
 	Interval    cfgtypes.Duration `json:"interval"`
 	Concurrence int               `json:"concurrence"`
+	// TimeZone is the IANA name of the time zone the regimen is kept in, such
+	// as "Europe/Berlin". Its days start at midnight there, and its times are
+	// shown there. Empty uses the time_zone of the configuration.
+	TimeZone string `json:"time_zone,omitempty"`
+	// OverdueAfter is how long after the next dose is due it is considered
+	// overdue. Zero disables the overdue event.
+	OverdueAfter cfgtypes.Duration `json:"overdue_after,omitempty"`
+	// OnTimeWindow is how far from its due time a dose may be taken and
+	// still count as on time. Zero uses DefaultOnTimeWindow.
+	OnTimeWindow cfgtypes.Duration `json:"on_time_window,omitempty"`
+	// LateWindow is how long after its due time a dose may still be taken
+	// late. A dose that isn't taken by then is missed. Zero means a dose is
+	// only missed once the dose after it is due.
+	LateWindow cfgtypes.Duration `json:"late_window,omitempty"`
+	// MissedDose is what to do about a dose that wasn't taken on time.
+	MissedDose MissedDosePolicy `json:"missed_dose,omitempty"`
+	// Target is the range of levels in pg/mL to aim for. It is shaded on the
+	// level charts if set.
+	Target LevelRange `json:"target"`
+	// DoseAmount is the number of units, such as patches, taken from the
+	// inventory for every recorded dose. Each dose is a single application,
+	// so with concurrent patches it is the amount of one patch, not of all
+	// the patches worn at once. Zero uses one unit.
+	DoseAmount float64 `json:"dose_amount,omitempty"`
+	// RefillDays is how many days before the inventory runs out the refill
+	// reminder is sent. Zero uses DefaultRefillDays.
+	RefillDays int `json:"refill_days,omitempty"`
+	// RenewDays is how many days before the current prescription expires the
+	// renewal reminder is sent. Zero uses DefaultRenewDays.
+	RenewDays int `json:"renew_days,omitempty"`
+	// LabReminder is how long before a lab appointment the reminder for it is
+	// sent. Zero uses DefaultLabReminder.
+	LabReminder cfgtypes.Duration `json:"lab_reminder,omitempty"`
+	// RemovalReminder is how long after a patch is taken off without a new
+	// one being put on the reminder to put one on is sent. Zero uses
+	// DefaultRemovalReminder.
+	RemovalReminder cfgtypes.Duration `json:"removal_reminder,omitempty"`
+	// TravelShift is the most the due time of a dose moves from the one
+	// before it when traveling across time zones. Zero uses
+	// DefaultTravelShift.
+	TravelShift cfgtypes.Duration `json:"travel_shift,omitempty"`
+	// Strength is the strength of a dose relative to the one the levels are
+	// predicted for, such as 0.5 for a patch of half the strength. Zero uses
+	// 1.
+	Strength float64 `json:"strength,omitempty"`
+	// Cycle makes the regimen cyclic, with doses only due on the active days
+	// of each cycle. It is nil if doses are due every day.
+	Cycle *Cycle `json:"cycle,omitempty"`
+	// Changes are the changes to the regimen over time, such as ramping up
+	// or tapering the dose, sorted by when they take effect. The regimen
+	// above is the one in effect before the first change.
+	Changes []RegimenChange `json:"changes,omitempty"`
+
+	// location is the time zone of the regimen, loaded by ReadJSONConfig.
+	location *time.Location
+}
+
+// Location returns the time zone of the regimen. It is the time zone of the
+// configuration if the regimen has none, and the time zone of the machine if
+// neither has one or it is invalid.
+func (c HRTConfig) Location() *time.Location {
+	if c.location != nil {
+		return c.location
+	}
+	if loc, err := loadLocation(c.TimeZone); err == nil && loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// Zone returns the IANA name of the time zone of the regimen, or an empty
+// string if it is the time zone of the machine, whose name isn't known.
+func (c HRTConfig) Zone() string {
+	if name := c.Location().String(); name != "Local" {
+		return name
+	}
+	return ""
+}
+
+// MissedDosePolicy is what to do about a dose that wasn't taken on time, as
+// advised by the prescriber or the package insert. Taking two doses at once to
+// make up for a missed one is never advised.
+type MissedDosePolicy struct {
+	// SkipWithin is how close to when the dose after it is due a missed dose
+	// is skipped instead of taken. Zero always takes a missed dose as soon as
+	// it's remembered.
+	SkipWithin cfgtypes.Duration `json:"skip_within,omitempty"`
+}
+
+// RegimenChange is a change to the regimen that takes effect at EffectiveAt.
+// Its fields that are set replace those of the regimen in effect before it.
+type RegimenChange struct {
+	EffectiveAt time.Time         `json:"effective_at"`
+	Interval    cfgtypes.Duration `json:"interval,omitempty"`
+	Concurrence int               `json:"concurrence,omitempty"`
+	DoseAmount  float64           `json:"dose_amount,omitempty"`
+	Strength    float64           `json:"strength,omitempty"`
+	// Note describes the change, such as "up to 100 µg patches".
+	Note string `json:"note,omitempty"`
+}
+
+// At returns the regimen in effect at the given time, with the changes that
+// took effect until then applied. The returned regimen has no changes, so it
+// must not be used for any other time.
+func (c HRTConfig) At(t time.Time) HRTConfig {
+	r := c
+	r.Changes = nil
+	for _, change := range c.Changes {
+		if change.EffectiveAt.After(t) {
+			break
+		}
+		if change.Interval > 0 {
+			r.Interval = change.Interval
+		}
+		if change.Concurrence > 0 {
+			r.Concurrence = change.Concurrence
+		}
+		if change.DoseAmount > 0 {
+			r.DoseAmount = change.DoseAmount
+		}
+		if change.Strength > 0 {
+			r.Strength = change.Strength
+		}
+	}
+	return r
+}
+
+// ChangesBetween returns the changes that take effect after from and until
+// to.
+func (c HRTConfig) ChangesBetween(from, to time.Time) []RegimenChange {
+	var changes []RegimenChange
+	for _, change := range c.Changes {
+		if change.EffectiveAt.After(from) && !change.EffectiveAt.After(to) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// DefaultOnTimeWindow is how far from its due time a dose counts as on time
+// if not configured.
+const DefaultOnTimeWindow = time.Hour
+
+// DefaultRefillDays is the number of days of supply left at which the refill
+// reminder is sent if not configured.
+const DefaultRefillDays = 14
+
+// DefaultRenewDays is the number of days before a prescription expires at
+// which the renewal reminder is sent.
+const DefaultRenewDays = 30
+
+// DefaultLabReminder is how long before a lab appointment the reminder for it
+// is sent if not configured.
+const DefaultLabReminder = 12 * time.Hour
+
+// DefaultRemovalReminder is how long after a patch is taken off without a new
+// one the reminder is sent if not configured.
+const DefaultRemovalReminder = 30 * time.Minute
+
+// DefaultTravelShift is the most the due time of a dose moves when traveling
+// across time zones if not configured.
+const DefaultTravelShift = 2 * time.Hour
+
+// DoseStrength returns the strength of a dose relative to the one the levels
+// are predicted for.
+func (c HRTConfig) DoseStrength() float64 {
+	if c.Strength > 0 {
+		return c.Strength
+	}
+	return 1
+}
+
+// OnTimeWithin returns how far from its due time a dose may be taken and
+// still count as on time.
+func (c HRTConfig) OnTimeWithin() time.Duration {
+	if c.OnTimeWindow > 0 {
+		return c.OnTimeWindow.AsDuration()
+	}
+	return DefaultOnTimeWindow
+}
+
+// MaxTravelShift returns the most the due time of a dose moves from the one
+// before it when traveling across time zones.
+func (c HRTConfig) MaxTravelShift() time.Duration {
+	if c.TravelShift > 0 {
+		return c.TravelShift.AsDuration()
+	}
+	return DefaultTravelShift
+}
+
+// UnitsPerDose returns the number of units taken from the inventory for every
+// dose.
+func (c HRTConfig) UnitsPerDose() float64 {
+	if c.DoseAmount > 0 {
+		return c.DoseAmount
+	}
+	return 1
+}
+
+// RefillBelow returns how long before the inventory runs out the refill
+// reminder is sent.
+func (c HRTConfig) RefillBelow() time.Duration {
+	days := c.RefillDays
+	if days <= 0 {
+		days = DefaultRefillDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// RenewBefore returns how long before the current prescription expires the
+// renewal reminder is sent.
+func (c HRTConfig) RenewBefore() time.Duration {
+	days := c.RenewDays
+	if days <= 0 {
+		days = DefaultRenewDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// LabReminderBefore returns how long before a lab appointment the reminder for
+// it is sent.
+func (c HRTConfig) LabReminderBefore() time.Duration {
+	if c.LabReminder > 0 {
+		return c.LabReminder.AsDuration()
+	}
+	return DefaultLabReminder
 }
 
+// RemovalReminderAfter returns how long after a patch is taken off without a
+// new one the reminder is sent.
+func (c HRTConfig) RemovalReminderAfter() time.Duration {
+	if c.RemovalReminder > 0 {
+		return c.RemovalReminder.AsDuration()
+	}
+	return DefaultRemovalReminder
+}
+
+// LevelRange is a range of hormone levels in pg/mL.
+type LevelRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// IsZero returns true if the range is not set.
+func (r LevelRange) IsZero() bool {
+	return r.Min == 0 && r.Max == 0
+}
+
+// NextDoseAt returns the time the next dose is due given the time of the last
+// dose. It is due after the interval of the regimen in effect when the last
+// dose was taken. For a cyclic regimen, a dose that would be due on a day
+// without doses is due at the same time of day on the next active day
+// instead.
+func (c HRTConfig) NextDoseAt(lastDose time.Time) time.Time {
+	due := lastDose.Add(c.At(lastDose).Interval.AsDuration())
+	if c.Cycle != nil {
+		due = c.Cycle.NextActive(due, c.Location())
+	}
+	return due
+}
+
+// WebhookConfig is the configuration for a single outgoing webhook.
+type WebhookConfig struct {
+	// URL is the URL to POST the event payloads to.
+	URL string `json:"url"`
+	// Secret is the key used to sign the payloads with HMAC-SHA256.
+	Secret string `json:"secret"`
+	// Events is the list of event types to send. An empty list sends all
+	// events.
+	Events []events.Type `json:"events,omitempty"`
+}
+
+// Wants returns true if the webhook should receive events of the given type.
+func (c WebhookConfig) Wants(t events.Type) bool {
+	return len(c.Events) == 0 || slices.Contains(c.Events, t)
+}
+
+// HookConfig is the configuration for a single command hook.
+type HookConfig struct {
+	// Command is the executable to run followed by its arguments. It is run
+	// directly, not through a shell.
+	Command []string `json:"command"`
+	// Events is the list of event types to run the command for. An empty list
+	// runs it for all events.
+	Events []events.Type `json:"events,omitempty"`
+	// Timeout is how long the command may run before it is killed. Zero uses
+	// DefaultHookTimeout.
+	Timeout cfgtypes.Duration `json:"timeout,omitempty"`
+}
+
+// DefaultHookTimeout is the timeout of a command hook that doesn't set one.
+const DefaultHookTimeout = 30 * time.Second
+
+// Wants returns true if the hook should run for events of the given type.
+func (c HookConfig) Wants(t events.Type) bool {
+	return len(c.Events) == 0 || slices.Contains(c.Events, t)
+}
+
+// HooksConfig is the configuration for the commands run on events.
+type HooksConfig struct {
+	// Concurrency is the maximum number of commands run at the same time.
+	// Zero uses DefaultHookConcurrency.
+	Concurrency int          `json:"concurrency,omitempty"`
+	Commands    []HookConfig `json:"commands,omitempty"`
+}
+
+// DefaultHookConcurrency is the number of command hooks run at the same time
+// if not configured.
+const DefaultHookConcurrency = 4
+
+// CalendarConfig is the configuration for the iCalendar feed.
+type CalendarConfig struct {
+	// Token is the secret that must be given in the feed URL. The feed is
+	// disabled if it is empty.
+	Token string `json:"token,omitempty"`
+	// Horizon is how far into the future doses are projected. Zero uses
+	// DefaultCalendarHorizon.
+	Horizon cfgtypes.Duration `json:"horizon,omitempty"`
+	// AlarmBefore is how long before a projected dose is due the calendar
+	// reminds about it.
+	AlarmBefore cfgtypes.Duration `json:"alarm_before,omitempty"`
+}
+
+// DefaultCalendarHorizon is the projection horizon of the calendar feed if not
+// configured.
+const DefaultCalendarHorizon = 30 * 24 * time.Hour
+
+// minCalendarTokenLength is the minimum length of the calendar token, since
+// it is the only thing protecting the feed.
+const minCalendarTokenLength = 16
+
 // Config contains the configuration for the hrtclicker application.
 // See config.json for an example configuration.
 type Config struct {
-	HRT    HRTConfig `json:"hrt"`
-	Gotify struct {
+	// TimeZone is the IANA name of the time zone times are shown in, such as
+	// "Europe/Berlin", unless the regimen has its own. Empty uses the time
+	// zone of the machine, which is usually UTC in a container.
+	TimeZone string    `json:"time_zone,omitempty"`
+	HRT      HRTConfig `json:"hrt"`
+	Gotify   struct {
 		Endpoint     string       `json:"endpoint"`
 		Token        string       `json:"token"`
 		Notification Notification `json:"notification"`
 	} `json:"gotify"`
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+	Hooks    HooksConfig     `json:"hooks,omitempty"`
+	Calendar CalendarConfig  `json:"calendar,omitempty"`
+}
+
+// Regimen returns the configured regimen for the given HRT type. An empty type
+// returns the default regimen. False is returned if the type is not
+// configured.
+func (c *Config) Regimen(t HRTType) (HRTConfig, bool) {
+	if t == "" || t == c.HRT.Type {
+		return c.HRT, true
+	}
+	return HRTConfig{}, false
+}
+
+// loadLocation loads the first of the named time zones that isn't empty. It
+// returns nil if they all are.
+func loadLocation(names ...string) (*time.Location, error) {
+	for _, name := range names {
+		if name != "" {
+			return time.LoadLocation(name)
+		}
+	}
+	return nil, nil
+}
+
+// Redacted returns a copy of the configuration with its secrets removed, such
+// as the Gotify token and the webhook secrets.
+func (c *Config) Redacted() *Config {
+	r := *c
+	r.Gotify.Token = ""
+	r.Calendar.Token = ""
+
+	r.Webhooks = slices.Clone(c.Webhooks)
+	for i := range r.Webhooks {
+		r.Webhooks[i].Secret = ""
+	}
+
+	return &r
+}
+
+// Validate checks the configuration for mistakes. All problems found are
+// joined into the returned error.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !c.HRT.Type.IsValid() {
+		errs = append(errs, fmt.Errorf("hrt.type: unknown type %q", c.HRT.Type))
+	}
+	if _, err := loadLocation(c.TimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("time_zone: %w", err))
+	}
+	if _, err := loadLocation(c.HRT.TimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("hrt.time_zone: %w", err))
+	}
+	if c.HRT.Interval <= 0 {
+		errs = append(errs, errors.New("hrt.interval: must be positive"))
+	}
+	if c.HRT.Concurrence < 0 {
+		errs = append(errs, errors.New("hrt.concurrence: must not be negative"))
+	}
+	if c.HRT.OverdueAfter < 0 {
+		errs = append(errs, errors.New("hrt.overdue_after: must not be negative"))
+	}
+	if c.HRT.OnTimeWindow < 0 {
+		errs = append(errs, errors.New("hrt.on_time_window: must not be negative"))
+	}
+	if c.HRT.LateWindow < 0 {
+		errs = append(errs, errors.New("hrt.late_window: must not be negative"))
+	} else if c.HRT.LateWindow > 0 && c.HRT.LateWindow.AsDuration() < c.HRT.OnTimeWithin() {
+		errs = append(errs, errors.New("hrt.late_window: must not be shorter than the on time window"))
+	}
+	if c.HRT.MissedDose.SkipWithin < 0 {
+		errs = append(errs, errors.New("hrt.missed_dose.skip_within: must not be negative"))
+	} else if c.HRT.Interval > 0 && c.HRT.MissedDose.SkipWithin >= c.HRT.Interval {
+		errs = append(errs, errors.New("hrt.missed_dose.skip_within: must be shorter than the interval"))
+	}
+	if !c.HRT.Target.IsZero() && (c.HRT.Target.Min < 0 || c.HRT.Target.Min >= c.HRT.Target.Max) {
+		errs = append(errs, errors.New("hrt.target: min must be at least 0 and less than max"))
+	}
+	if c.HRT.DoseAmount < 0 {
+		errs = append(errs, errors.New("hrt.dose_amount: must not be negative"))
+	}
+	if c.HRT.RefillDays < 0 {
+		errs = append(errs, errors.New("hrt.refill_days: must not be negative"))
+	}
+	if c.HRT.RenewDays < 0 {
+		errs = append(errs, errors.New("hrt.renew_days: must not be negative"))
+	}
+	if c.HRT.LabReminder < 0 {
+		errs = append(errs, errors.New("hrt.lab_reminder: must not be negative"))
+	}
+	if c.HRT.RemovalReminder < 0 {
+		errs = append(errs, errors.New("hrt.removal_reminder: must not be negative"))
+	}
+	if c.HRT.TravelShift < 0 {
+		errs = append(errs, errors.New("hrt.travel_shift: must not be negative"))
+	}
+	if c.HRT.Strength < 0 {
+		errs = append(errs, errors.New("hrt.strength: must not be negative"))
+	}
+	if cycle := c.HRT.Cycle; cycle != nil {
+		if cycle.Length <= 0 {
+			errs = append(errs, errors.New("hrt.cycle.length: must be positive"))
+		}
+		if cycle.Anchor.IsZero() {
+			errs = append(errs, errors.New("hrt.cycle.anchor: missing"))
+		}
+		if len(cycle.Active) == 0 {
+			errs = append(errs, errors.New("hrt.cycle.active: missing"))
+		}
+		for i, r := range cycle.Active {
+			if r.From < 1 || r.To < r.From || r.To > cycle.Length {
+				errs = append(errs, fmt.Errorf("hrt.cycle.active[%d]: must be days from 1 to the length of the cycle", i))
+			}
+		}
+	}
+	for i, change := range c.HRT.Changes {
+		if change.EffectiveAt.IsZero() {
+			errs = append(errs, fmt.Errorf("hrt.changes[%d].effective_at: missing", i))
+		} else if i > 0 && !change.EffectiveAt.After(c.HRT.Changes[i-1].EffectiveAt) {
+			errs = append(errs, fmt.Errorf("hrt.changes[%d].effective_at: must be after the previous change", i))
+		}
+		if change.Interval < 0 {
+			errs = append(errs, fmt.Errorf("hrt.changes[%d].interval: must not be negative", i))
+		} else if change.Interval > 0 && c.HRT.MissedDose.SkipWithin >= change.Interval {
+			// A cycle only ever delays the next dose, so the intervals are
+			// the shortest time between doses that skip_within must fit in.
+			errs = append(errs, fmt.Errorf("hrt.missed_dose.skip_within: must be shorter than the interval of hrt.changes[%d]", i))
+		}
+		if change.Concurrence < 0 {
+			errs = append(errs, fmt.Errorf("hrt.changes[%d].concurrence: must not be negative", i))
+		}
+		if change.DoseAmount < 0 {
+			errs = append(errs, fmt.Errorf("hrt.changes[%d].dose_amount: must not be negative", i))
+		}
+		if change.Strength < 0 {
+			errs = append(errs, fmt.Errorf("hrt.changes[%d].strength: must not be negative", i))
+		}
+	}
+
+	if c.Gotify.Endpoint == "" {
+		errs = append(errs, errors.New("gotify.endpoint: missing"))
+	} else if u, err := url.Parse(c.Gotify.Endpoint); err != nil {
+		errs = append(errs, fmt.Errorf("gotify.endpoint: %w", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		errs = append(errs, fmt.Errorf("gotify.endpoint: unsupported scheme %q", u.Scheme))
+	}
+	if c.Gotify.Token == "" {
+		errs = append(errs, errors.New("gotify.token: missing"))
+	}
+
+	if _, err := template.New("").Parse(c.Gotify.Notification.Title); err != nil {
+		errs = append(errs, fmt.Errorf("gotify.notification.title: %w", err))
+	}
+	if _, err := template.New("").Parse(c.Gotify.Notification.Message); err != nil {
+		errs = append(errs, fmt.Errorf("gotify.notification.message: %w", err))
+	}
+
+	for i, webhook := range c.Webhooks {
+		if u, err := url.Parse(webhook.URL); err != nil {
+			errs = append(errs, fmt.Errorf("webhooks[%d].url: %w", i, err))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			errs = append(errs, fmt.Errorf("webhooks[%d].url: unsupported scheme %q", i, u.Scheme))
+		}
+		if webhook.Secret == "" {
+			errs = append(errs, fmt.Errorf("webhooks[%d].secret: missing", i))
+		}
+		for _, t := range webhook.Events {
+			if !t.IsValid() {
+				errs = append(errs, fmt.Errorf("webhooks[%d].events: unknown event type %q", i, t))
+			}
+		}
+	}
+
+	if c.Hooks.Concurrency < 0 {
+		errs = append(errs, errors.New("hooks.concurrency: must not be negative"))
+	}
+	for i, hook := range c.Hooks.Commands {
+		if len(hook.Command) == 0 || hook.Command[0] == "" {
+			errs = append(errs, fmt.Errorf("hooks.commands[%d].command: missing", i))
+		}
+		if hook.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("hooks.commands[%d].timeout: must not be negative", i))
+		}
+		for _, t := range hook.Events {
+			if !t.IsValid() {
+				errs = append(errs, fmt.Errorf("hooks.commands[%d].events: unknown event type %q", i, t))
+			}
+		}
+	}
+
+	if c.Calendar.Token != "" && len(c.Calendar.Token) < minCalendarTokenLength {
+		errs = append(errs, fmt.Errorf("calendar.token: must be at least %d characters long", minCalendarTokenLength))
+	}
+	if c.Calendar.Horizon < 0 {
+		errs = append(errs, errors.New("calendar.horizon: must not be negative"))
+	}
+	if c.Calendar.AlarmBefore < 0 {
+		errs = append(errs, errors.New("calendar.alarm_before: must not be negative"))
+	}
+
+	return errors.Join(errs...)
 }
 
 // ReadJSONConfig reads a Config from the provided io.Reader in JSON format.
@@ -32,6 +575,9 @@ func ReadJSONConfig(r io.Reader) (*Config, error) {
 	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
 		return nil, err
 	}
+	// An invalid time zone is reported by Validate, and the time zone of the
+	// machine is used until it is fixed.
+	cfg.HRT.location, _ = loadLocation(cfg.HRT.TimeZone, cfg.TimeZone)
 	return &cfg, nil
 }
 
@@ -44,3 +590,49 @@ func ReadJSONConfigFile(path string) (*Config, error) {
 	defer f.Close()
 	return ReadJSONConfig(f)
 }
+
+// ReloadableConfig holds a Config read from a file that can be reloaded while
+// the application is running. It is safe for concurrent use.
+type ReloadableConfig struct {
+	path string
+	cfg  atomic.Pointer[Config]
+}
+
+// NewReloadableConfig reads the Config at the given path. Unlike Reload, the
+// configuration is not validated.
+func NewReloadableConfig(path string) (*ReloadableConfig, error) {
+	cfg, err := ReadJSONConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ReloadableConfig{path: path}
+	c.cfg.Store(cfg)
+	return c, nil
+}
+
+// Load returns the current Config. It must not be modified.
+func (c *ReloadableConfig) Load() *Config {
+	return c.cfg.Load()
+}
+
+// Reload reads the configuration file again and returns the new Config. If the
+// file cannot be read or the new configuration is invalid, the current Config
+// is kept and an error is returned.
+func (c *ReloadableConfig) Reload() (*Config, error) {
+	if c.path == "" {
+		return nil, errors.New("config was not read from a file")
+	}
+
+	cfg, err := ReadJSONConfigFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	c.cfg.Store(cfg)
+	return cfg, nil
+}
@@ -0,0 +1,401 @@
+package hrtclicker
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker/internal/cfgtypes"
+)
+
+// validConfig returns a configuration that passes validation.
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+
+	var cfg Config
+	if err := json.Unmarshal([]byte(`{
+		"hrt": {"type": "sublingual", "interval": "8h"},
+		"gotify": {"endpoint": "https://gotify.example.com", "token": "token"}
+	}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	return &cfg
+}
+
+func TestValidateMissedDose(t *testing.T) {
+	tests := []struct {
+		name         string
+		onTimeWindow time.Duration
+		lateWindow   time.Duration
+		skipWithin   time.Duration
+		changes      []RegimenChange
+		wantErr      string
+	}{
+		{
+			name:         "valid",
+			onTimeWindow: time.Hour,
+			lateWindow:   6 * time.Hour,
+			skipWithin:   7 * time.Hour,
+		},
+		{
+			name:         "late window shorter than the on time window",
+			onTimeWindow: 2 * time.Hour,
+			lateWindow:   time.Hour,
+			wantErr:      "hrt.late_window: must not be shorter than the on time window",
+		},
+		{
+			name:       "skip within as long as the interval",
+			skipWithin: 8 * time.Hour,
+			wantErr:    "hrt.missed_dose.skip_within: must be shorter than the interval",
+		},
+		{
+			name:       "negative skip within",
+			skipWithin: -time.Hour,
+			wantErr:    "hrt.missed_dose.skip_within: must not be negative",
+		},
+		{
+			name:       "skip within shorter than every changed interval",
+			skipWithin: 5 * time.Hour,
+			changes: []RegimenChange{
+				{EffectiveAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Interval: cfgtypes.Duration(6 * time.Hour)},
+				{EffectiveAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), DoseAmount: 2},
+			},
+		},
+		{
+			name:       "skip within longer than a changed interval",
+			skipWithin: 7 * time.Hour,
+			changes: []RegimenChange{
+				{EffectiveAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), DoseAmount: 2},
+				{EffectiveAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Interval: cfgtypes.Duration(6 * time.Hour)},
+			},
+			wantErr: "hrt.missed_dose.skip_within: must be shorter than the interval of hrt.changes[1]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.HRT.OnTimeWindow = cfgtypes.Duration(test.onTimeWindow)
+			cfg.HRT.LateWindow = cfgtypes.Duration(test.lateWindow)
+			cfg.HRT.MissedDose.SkipWithin = cfgtypes.Duration(test.skipWithin)
+			cfg.HRT.Changes = test.changes
+
+			err := cfg.Validate()
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want no error", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("Validate() = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCycle(t *testing.T) {
+	anchor := cfgtypes.Date{Year: 2026, Month: time.March, Day: 1}
+
+	tests := []struct {
+		name    string
+		cycle   Cycle
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			cycle: Cycle{Length: 28, Anchor: anchor, Active: []DayRange{{From: 1, To: 12}}},
+		},
+		{
+			name:    "without a length",
+			cycle:   Cycle{Anchor: anchor, Active: []DayRange{{From: 1, To: 1}}},
+			wantErr: "hrt.cycle.length: must be positive",
+		},
+		{
+			name:    "without an anchor",
+			cycle:   Cycle{Length: 28, Active: []DayRange{{From: 1, To: 12}}},
+			wantErr: "hrt.cycle.anchor: missing",
+		},
+		{
+			name:    "without active days",
+			cycle:   Cycle{Length: 28, Anchor: anchor},
+			wantErr: "hrt.cycle.active: missing",
+		},
+		{
+			name:    "active days past the length",
+			cycle:   Cycle{Length: 28, Anchor: anchor, Active: []DayRange{{From: 1, To: 12}, {From: 20, To: 30}}},
+			wantErr: "hrt.cycle.active[1]: must be days from 1 to the length of the cycle",
+		},
+		{
+			name:    "active days backwards",
+			cycle:   Cycle{Length: 28, Anchor: anchor, Active: []DayRange{{From: 12, To: 1}}},
+			wantErr: "hrt.cycle.active[0]: must be days from 1 to the length of the cycle",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.HRT.Cycle = &test.cycle
+
+			err := cfg.Validate()
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want no error", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("Validate() = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// titration is a regimen that doubles the dose after a week and then moves
+// from daily doses to doses every other day.
+var titration = HRTConfig{
+	Type:       TypeGel,
+	Interval:   cfgtypes.Duration(24 * time.Hour),
+	DoseAmount: 1,
+	Changes: []RegimenChange{
+		{EffectiveAt: time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), DoseAmount: 2, Note: "up to 2 pumps"},
+		{EffectiveAt: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), Interval: cfgtypes.Duration(48 * time.Hour), Strength: 1.5},
+	},
+}
+
+func TestHRTConfigAt(t *testing.T) {
+	tests := []struct {
+		name         string
+		at           time.Time
+		wantInterval time.Duration
+		wantAmount   float64
+		wantStrength float64
+	}{
+		{"before the changes", time.Date(2026, 3, 7, 23, 0, 0, 0, time.UTC), 24 * time.Hour, 1, 0},
+		{"when a change takes effect", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), 24 * time.Hour, 2, 0},
+		{"after every change", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 48 * time.Hour, 2, 1.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := titration.At(test.at)
+			if got.Interval.AsDuration() != test.wantInterval || got.DoseAmount != test.wantAmount || got.Strength != test.wantStrength {
+				t.Errorf("At(%s) = every %s, %v at %v, want every %s, %v at %v", test.at,
+					got.Interval.AsDuration(), got.DoseAmount, got.Strength, test.wantInterval, test.wantAmount, test.wantStrength)
+			}
+			if got.Changes != nil {
+				t.Errorf("At(%s) has changes %v", test.at, got.Changes)
+			}
+		})
+	}
+}
+
+func TestHRTConfigChangesBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{"none", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC), nil},
+		{"until a change", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), []string{"up to 2 pumps"}},
+		{"from a change", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), nil},
+		{"both", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), []string{"up to 2 pumps", ""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, change := range titration.ChangesBetween(test.from, test.to) {
+				got = append(got, change.Note)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("ChangesBetween() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNextDoseAtAcrossChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastDose time.Time
+		want     time.Time
+	}{
+		{
+			name:     "before the change",
+			lastDose: time.Date(2026, 3, 13, 8, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 3, 14, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			// The interval in effect at the last dose is kept until the
+			// next dose, even though the change takes effect before it.
+			name:     "change before the next dose",
+			lastDose: time.Date(2026, 3, 14, 8, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 3, 15, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "after the change",
+			lastDose: time.Date(2026, 3, 15, 8, 0, 0, 0, time.UTC),
+			want:     time.Date(2026, 3, 17, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := titration.NextDoseAt(test.lastDose); !got.Equal(test.want) {
+				t.Errorf("NextDoseAt(%s) = %s, want %s", test.lastDose, got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateChanges(t *testing.T) {
+	effectiveAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		changes []RegimenChange
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			changes: titration.Changes,
+		},
+		{
+			name:    "without a time",
+			changes: []RegimenChange{{DoseAmount: 2}},
+			wantErr: "hrt.changes[0].effective_at: missing",
+		},
+		{
+			name: "out of order",
+			changes: []RegimenChange{
+				{EffectiveAt: effectiveAt, DoseAmount: 2},
+				{EffectiveAt: effectiveAt, DoseAmount: 3},
+			},
+			wantErr: "hrt.changes[1].effective_at: must be after the previous change",
+		},
+		{
+			name:    "negative interval",
+			changes: []RegimenChange{{EffectiveAt: effectiveAt, Interval: cfgtypes.Duration(-time.Hour)}},
+			wantErr: "hrt.changes[0].interval: must not be negative",
+		},
+		{
+			name:    "negative dose amount",
+			changes: []RegimenChange{{EffectiveAt: effectiveAt, DoseAmount: -1}},
+			wantErr: "hrt.changes[0].dose_amount: must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.HRT.Changes = test.changes
+
+			err := cfg.Validate()
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want no error", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("Validate() = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestHRTConfigLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		wantZone string
+	}{
+		{
+			name:   "machine",
+			config: `{"hrt": {"type": "gel", "interval": "24h"}}`,
+		},
+		{
+			name:     "configuration",
+			config:   `{"time_zone": "Europe/Berlin", "hrt": {"type": "gel", "interval": "24h"}}`,
+			wantZone: "Europe/Berlin",
+		},
+		{
+			name: "regimen",
+			config: `{
+				"time_zone": "Europe/Berlin",
+				"hrt": {"type": "gel", "interval": "24h", "time_zone": "America/New_York"}
+			}`,
+			wantZone: "America/New_York",
+		},
+		{
+			name:   "invalid",
+			config: `{"time_zone": "Nowhere/Else", "hrt": {"type": "gel", "interval": "24h"}}`,
+		},
+	}
+
+	berlin(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := ReadJSONConfig(strings.NewReader(test.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The name of the machine's time zone depends on how it was set.
+			switch got := cfg.HRT.Zone(); {
+			case test.wantZone == "" && cfg.HRT.Location() != time.Local:
+				t.Errorf("Location() = %s, want time.Local", cfg.HRT.Location())
+			case test.wantZone != "" && got != test.wantZone:
+				t.Errorf("Zone() = %q, want %q", got, test.wantZone)
+			}
+		})
+	}
+}
+
+func TestNextDoseAtAcrossDST(t *testing.T) {
+	loc := berlin(t)
+
+	// The interval is elapsed time, so a daily dose moves by an hour on the
+	// clock when summer time starts or ends, unless it moves to the next
+	// active day of a cycle.
+	tests := []struct {
+		name     string
+		regimen  HRTConfig
+		lastDose time.Time
+		want     time.Time
+	}{
+		{
+			name:     "summer time starting",
+			regimen:  HRTConfig{Type: TypeGel, Interval: cfgtypes.Duration(24 * time.Hour), TimeZone: "Europe/Berlin"},
+			lastDose: time.Date(2026, 3, 28, 8, 0, 0, 0, loc),
+			want:     time.Date(2026, 3, 29, 9, 0, 0, 0, loc),
+		},
+		{
+			name:     "summer time ending",
+			regimen:  HRTConfig{Type: TypeGel, Interval: cfgtypes.Duration(24 * time.Hour), TimeZone: "Europe/Berlin"},
+			lastDose: time.Date(2026, 10, 24, 8, 0, 0, 0, loc),
+			want:     time.Date(2026, 10, 25, 7, 0, 0, 0, loc),
+		},
+		{
+			// The interval lands on Saturday 2026-03-28, a break, so the
+			// dose moves to the same time of day on Monday, after summer
+			// time started.
+			name: "cycle",
+			regimen: HRTConfig{
+				Type:     TypeGel,
+				Interval: cfgtypes.Duration(48 * time.Hour),
+				TimeZone: "Europe/Berlin",
+				Cycle: &Cycle{
+					Length: 7,
+					Anchor: cfgtypes.Date{Year: 2026, Month: time.March, Day: 23},
+					Active: []DayRange{{From: 1, To: 5}},
+				},
+			},
+			lastDose: time.Date(2026, 3, 26, 20, 0, 0, 0, loc),
+			want:     time.Date(2026, 3, 30, 20, 0, 0, 0, loc),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.regimen.NextDoseAt(test.lastDose.UTC()); !got.Equal(test.want) {
+				t.Errorf("NextDoseAt() = %s, want %s", got.In(loc), test.want)
+			}
+		})
+	}
+}
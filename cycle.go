@@ -0,0 +1,90 @@
+package hrtclicker
+
+import (
+	"time"
+
+	"libdb.so/hrtclicker/internal/cfgtypes"
+)
+
+// Cycle is a cycle of days that repeats from its anchor date, such as taking
+// progesterone on days 1 to 12 of a 28-day cycle. Doses are only due on the
+// active days of the cycle. Days start at midnight in the time zone of the
+// regimen, which is passed to the methods that need it.
+type Cycle struct {
+	// Length is the number of days in a cycle.
+	Length int `json:"length"`
+	// Anchor is a date that was the first day of a cycle.
+	Anchor cfgtypes.Date `json:"anchor"`
+	// Active are the ranges of days of the cycle that doses are due on.
+	Active []DayRange `json:"active"`
+}
+
+// DayRange is a range of days of a cycle, counting from 1. Both ends are
+// included.
+type DayRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Day returns the day of the cycle that t falls on in loc, counting from 1. A
+// cycle without a length is always on its first day.
+func (c Cycle) Day(t time.Time, loc *time.Location) int {
+	if c.Length <= 0 {
+		return 1
+	}
+
+	// Count the days between the dates in UTC, where every day is 24 hours
+	// long regardless of daylight saving time.
+	y, m, d := t.In(loc).Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	days := int(date.Sub(c.Anchor.In(time.UTC)) / (24 * time.Hour))
+
+	day := days % c.Length
+	if day < 0 {
+		day += c.Length
+	}
+	return day + 1
+}
+
+// IsActive returns true if doses are due on the given day of the cycle.
+func (c Cycle) IsActive(day int) bool {
+	for _, r := range c.Active {
+		if day >= r.From && day <= r.To {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAt returns true if t falls on an active day of the cycle in loc.
+func (c Cycle) ActiveAt(t time.Time, loc *time.Location) bool {
+	return c.IsActive(c.Day(t, loc))
+}
+
+// NextActive returns t if it falls on an active day of the cycle in loc, and
+// otherwise the same time of day in loc on the next active day. t is returned
+// as is if the cycle has no active days.
+func (c Cycle) NextActive(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	for i := 0; i < c.Length; i++ {
+		next := local.AddDate(0, 0, i)
+		if c.ActiveAt(next, loc) {
+			return next.In(t.Location())
+		}
+	}
+	return t
+}
+
+// NextActiveDay returns the start in loc of the first day after t that follows
+// a day without doses, which is when the current or next break of the cycle
+// ends. It returns the zero time if the cycle has no breaks or no active days.
+func (c Cycle) NextActiveDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	for i := 1; i <= c.Length; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, loc)
+		if c.ActiveAt(day, loc) && !c.ActiveAt(day.AddDate(0, 0, -1), loc) {
+			return day
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,222 @@
+package hrtclicker
+
+import (
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker/internal/cfgtypes"
+)
+
+// berlin switches to summer time on 2026-03-29 and back on 2026-10-25.
+func berlin(t *testing.T) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	return loc
+}
+
+func TestCycleDay(t *testing.T) {
+	loc := berlin(t)
+	cycle := Cycle{
+		Length: 28,
+		Anchor: cfgtypes.Date{Year: 2026, Month: time.March, Day: 1},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{"anchor", time.Date(2026, 3, 1, 0, 0, 0, 0, loc), 1},
+		{"last day", time.Date(2026, 3, 28, 23, 59, 0, 0, loc), 28},
+		{"before summer time", time.Date(2026, 3, 29, 1, 30, 0, 0, loc), 1},
+		{"after summer time", time.Date(2026, 3, 29, 23, 30, 0, 0, loc), 1},
+		{"day after summer time", time.Date(2026, 3, 30, 0, 30, 0, 0, loc), 2},
+		{"before winter time", time.Date(2026, 10, 25, 0, 30, 0, 0, loc), 15},
+		{"after winter time", time.Date(2026, 10, 25, 23, 30, 0, 0, loc), 15},
+		{"day after winter time", time.Date(2026, 10, 26, 0, 30, 0, 0, loc), 16},
+		{"before the anchor", time.Date(2026, 2, 28, 12, 0, 0, 0, loc), 28},
+		// Late in the evening in UTC is already the next day in Berlin.
+		{"in UTC", time.Date(2026, 3, 29, 23, 30, 0, 0, time.UTC), 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := cycle.Day(test.at.UTC(), loc); got != test.want {
+				t.Errorf("Day(%s) = %d, want %d", test.at, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCycleIsActive(t *testing.T) {
+	// Progesterone on days 1 to 12 and 20 to 21 of a 28-day cycle.
+	cycle := Cycle{
+		Length: 28,
+		Anchor: cfgtypes.Date{Year: 2026, Month: time.March, Day: 1},
+		Active: []DayRange{{From: 1, To: 12}, {From: 20, To: 21}},
+	}
+
+	tests := []struct {
+		day  int
+		want bool
+	}{
+		{1, true},
+		{12, true},
+		{13, false},
+		{19, false},
+		{20, true},
+		{21, true},
+		{28, false},
+	}
+
+	for _, test := range tests {
+		if got := cycle.IsActive(test.day); got != test.want {
+			t.Errorf("IsActive(%d) = %v, want %v", test.day, got, test.want)
+		}
+	}
+}
+
+func TestCycleActive(t *testing.T) {
+	cycle := Cycle{
+		Length: 7,
+		Anchor: cfgtypes.Date{Year: 2026, Month: time.March, Day: 2},
+		Active: []DayRange{{From: 1, To: 5}},
+	}
+
+	tests := []struct {
+		name          string
+		cycle         Cycle
+		at            time.Time
+		wantActive    bool
+		wantNext      time.Time
+		wantActiveDay time.Time
+	}{
+		{
+			name:          "active day",
+			cycle:         cycle,
+			at:            time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC),
+			wantActive:    true,
+			wantNext:      time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC),
+			wantActiveDay: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "last active day",
+			cycle:         cycle,
+			at:            time.Date(2026, 3, 6, 23, 0, 0, 0, time.UTC),
+			wantActive:    true,
+			wantNext:      time.Date(2026, 3, 6, 23, 0, 0, 0, time.UTC),
+			wantActiveDay: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "break",
+			cycle:         cycle,
+			at:            time.Date(2026, 3, 7, 8, 0, 0, 0, time.UTC),
+			wantNext:      time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC),
+			wantActiveDay: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "next cycle",
+			cycle:         cycle,
+			at:            time.Date(2026, 3, 22, 8, 0, 0, 0, time.UTC),
+			wantNext:      time.Date(2026, 3, 23, 8, 0, 0, 0, time.UTC),
+			wantActiveDay: time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "without breaks",
+			cycle: Cycle{
+				Length: 7,
+				Anchor: cycle.Anchor,
+				Active: []DayRange{{From: 1, To: 7}},
+			},
+			at:         time.Date(2026, 3, 7, 8, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2026, 3, 7, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "without active days",
+			cycle: Cycle{
+				Length: 7,
+				Anchor: cycle.Anchor,
+			},
+			at:       time.Date(2026, 3, 7, 8, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2026, 3, 7, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.cycle.ActiveAt(test.at, time.UTC); got != test.wantActive {
+				t.Errorf("ActiveAt(%s) = %v, want %v", test.at, got, test.wantActive)
+			}
+			if got := test.cycle.NextActive(test.at, time.UTC); !got.Equal(test.wantNext) {
+				t.Errorf("NextActive(%s) = %s, want %s", test.at, got, test.wantNext)
+			}
+			if got := test.cycle.NextActiveDay(test.at, time.UTC); !got.Equal(test.wantActiveDay) {
+				t.Errorf("NextActiveDay(%s) = %s, want %s", test.at, got, test.wantActiveDay)
+			}
+		})
+	}
+}
+
+func TestCycleNextActive(t *testing.T) {
+	loc := berlin(t)
+	// Doses are due every other day, starting on 2026-03-28.
+	cycle := Cycle{
+		Length: 2,
+		Anchor: cfgtypes.Date{Year: 2026, Month: time.March, Day: 28},
+		Active: []DayRange{{From: 1, To: 1}},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{
+			name: "active day",
+			at:   time.Date(2026, 3, 28, 8, 0, 0, 0, loc),
+			want: time.Date(2026, 3, 28, 8, 0, 0, 0, loc),
+		},
+		{
+			name: "across summer time",
+			at:   time.Date(2026, 3, 29, 8, 0, 0, 0, loc),
+			want: time.Date(2026, 3, 30, 8, 0, 0, 0, loc),
+		},
+		{
+			name: "across winter time",
+			at:   time.Date(2026, 10, 25, 8, 0, 0, 0, loc),
+			want: time.Date(2026, 10, 26, 8, 0, 0, 0, loc),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := cycle.NextActive(test.at.UTC(), loc)
+			if !got.Equal(test.want) {
+				t.Errorf("NextActive(%s) = %s, want %s", test.at, got.In(loc), test.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("NextActive returned a time in %s, want the location of its argument", got.Location())
+			}
+		})
+	}
+}
+
+func TestCycleNextActiveDay(t *testing.T) {
+	loc := berlin(t)
+	cycle := Cycle{
+		Length: 2,
+		Anchor: cfgtypes.Date{Year: 2026, Month: time.March, Day: 28},
+		Active: []DayRange{{From: 1, To: 1}},
+	}
+
+	got := cycle.NextActiveDay(time.Date(2026, 3, 29, 12, 0, 0, 0, loc), loc)
+	want := time.Date(2026, 3, 30, 0, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("NextActiveDay() = %s, want %s", got, want)
+	}
+}
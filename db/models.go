@@ -12,9 +12,157 @@ import (
 type HRTHistory struct {
 	DosageAt time.Time
 	HRTType  string
+	Notes    string
+	Tags     Tags
+	Zone     string
+}
+
+type JournalEntry struct {
+	ID        int64
+	HRTType   string
+	WrittenAt time.Time
+	Mood      sql.NullInt64
+	Notes     string
+	AddedAt   time.Time
+}
+
+type JournalTag struct {
+	EntryID int64
+	Tag     string
+}
+
+type LabAppointment struct {
+	ID          int64
+	HRTType     string
+	Target      string
+	ScheduledAt time.Time
+	Note        string
+	CreatedAt   time.Time
+}
+
+type LabNotified struct {
+	AppointmentID int64
+	NotifiedAt    sql.NullTime
+}
+
+type LabResult struct {
+	ID            int64
+	HRTType       string
+	DrawnAt       time.Time
+	Value         float64
+	AppointmentID sql.NullInt64
+	Note          string
+	AddedAt       time.Time
+}
+
+type MissedNotified struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
 }
 
 type Notified struct {
 	DosageAt   time.Time
 	NotifiedAt sql.NullTime
 }
+
+type OverdueNotified struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+type Pause struct {
+	ID        int64
+	HRTType   string
+	StartedAt time.Time
+	EndedAt   sql.NullTime
+	Reason    string
+	AddedAt   time.Time
+}
+
+type Prescription struct {
+	ID         int64
+	HRTType    string
+	Prescriber string
+	Medication string
+	Strength   string
+	Refills    int64
+	WrittenAt  time.Time
+	ExpiresAt  time.Time
+	AddedAt    time.Time
+}
+
+type PrescriptionNotified struct {
+	PrescriptionID int64
+	Reason         string
+	NotifiedAt     sql.NullTime
+}
+
+type RefillNotified struct {
+	StockID    int64
+	NotifiedAt sql.NullTime
+}
+
+type Removal struct {
+	DosageAt  time.Time
+	HRTType   string
+	RemovedAt time.Time
+	Reason    string
+	AddedAt   time.Time
+}
+
+type RemovalNotified struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+type Skip struct {
+	ID        int64
+	HRTType   string
+	DueAt     time.Time
+	SkippedAt time.Time
+	Reason    string
+}
+
+type Snoozed struct {
+	DosageAt     time.Time
+	SnoozedUntil time.Time
+}
+
+type Stock struct {
+	ID             int64
+	HRTType        string
+	Medication     string
+	Quantity       float64
+	Remaining      float64
+	Lot            string
+	ExpiresAt      sql.NullTime
+	AddedAt        time.Time
+	PrescriptionID sql.NullInt64
+}
+
+type StockUsage struct {
+	DosageAt time.Time
+	StockID  int64
+	Amount   float64
+}
+
+type Trip struct {
+	ID              int64
+	HRTType         string
+	DepartureZone   string
+	DestinationZone string
+	DepartsAt       time.Time
+	Note            string
+	AddedAt         time.Time
+}
+
+type WebhookDelivery struct {
+	ID          int64
+	EventID     string
+	EventType   string
+	Url         string
+	Attempt     int64
+	StatusCode  sql.NullInt64
+	Error       sql.NullString
+	AttemptedAt time.Time
+}
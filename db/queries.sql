@@ -1,14 +1,467 @@
 -- name: DosageHistory :many
 SELECT * FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC;
 
+-- name: DosageHistoryBetween :many
+SELECT * FROM hrt_history
+	WHERE hrt_type = sqlc.arg(hrt_type) AND dosage_at >= sqlc.arg(since) AND dosage_at < sqlc.arg(before)
+	ORDER BY dosage_at;
+
+-- name: LastDoses :many
+SELECT * FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC LIMIT ?;
+
 -- name: LastDose :one
 SELECT * FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC LIMIT 1;
 
+-- name: AllDoses :many
+SELECT * FROM hrt_history ORDER BY dosage_at;
+
+-- name: DoseAt :one
+SELECT * FROM hrt_history WHERE dosage_at = ?;
+
+-- name: DoseBefore :one
+SELECT * FROM hrt_history WHERE hrt_type = ? AND dosage_at < ? ORDER BY dosage_at DESC LIMIT 1;
+
+-- name: DoseTypes :many
+SELECT DISTINCT hrt_type FROM hrt_history ORDER BY hrt_type;
+
+-- name: HistoryPage :many
+SELECT * FROM hrt_history
+	WHERE hrt_type = sqlc.arg(hrt_type) AND dosage_at >= sqlc.arg(since) AND dosage_at < sqlc.arg(before)
+	ORDER BY dosage_at DESC LIMIT sqlc.arg(limit);
+
+-- name: HistoryPageAfter :many
+SELECT * FROM hrt_history
+	WHERE hrt_type = sqlc.arg(hrt_type) AND dosage_at > sqlc.arg(after) AND dosage_at < sqlc.arg(before)
+	ORDER BY dosage_at LIMIT sqlc.arg(limit);
+
+-- name: TaggedHistoryPage :many
+SELECT * FROM hrt_history
+	WHERE hrt_type = sqlc.arg(hrt_type) AND dosage_at >= sqlc.arg(since) AND dosage_at < sqlc.arg(before)
+	AND EXISTS (SELECT 1 FROM json_each(hrt_history.tags) WHERE json_each.value = sqlc.arg(tag))
+	ORDER BY dosage_at DESC LIMIT sqlc.arg(limit);
+
 -- name: RecordDosage :exec
-INSERT INTO hrt_history (hrt_type) VALUES (?);
+INSERT INTO hrt_history (dosage_at, hrt_type, notes, tags, zone) VALUES (?, ?, ?, ?, ?);
+
+-- name: DeleteLastDose :one
+DELETE FROM hrt_history WHERE dosage_at = (SELECT dosage_at FROM hrt_history WHERE hrt_history.hrt_type = ? ORDER BY dosage_at DESC LIMIT 1) RETURNING *;
 
--- name: DeleteLastDose :exec
-DELETE FROM hrt_history WHERE dosage_at = (SELECT dosage_at FROM hrt_history WHERE hrt_history.hrt_type = ? ORDER BY dosage_at DESC LIMIT 1);
+-- name: UpdateDose :one
+UPDATE hrt_history SET dosage_at = sqlc.arg(new_dosage_at), hrt_type = sqlc.arg(new_hrt_type),
+	notes = sqlc.arg(notes), tags = sqlc.arg(tags)
+	WHERE dosage_at = sqlc.arg(dosage_at) RETURNING *;
+
+-- name: DeleteDose :one
+DELETE FROM hrt_history WHERE dosage_at = ? RETURNING *;
 
 -- name: MarkNotified :exec
 INSERT INTO notified (dosage_at) VALUES (?);
+
+-- name: MarkOverdue :exec
+INSERT INTO overdue_notified (dosage_at) VALUES (?);
+
+-- name: Notifications :many
+SELECT * FROM notified ORDER BY dosage_at;
+
+-- name: ImportNotification :execrows
+INSERT INTO notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING;
+
+-- name: OverdueNotifications :many
+SELECT * FROM overdue_notified ORDER BY dosage_at;
+
+-- name: ImportOverdueNotification :execrows
+INSERT INTO overdue_notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING;
+
+-- name: Snooze :exec
+INSERT INTO snoozed (dosage_at, snoozed_until) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO UPDATE SET snoozed_until = excluded.snoozed_until;
+
+-- name: SnoozedUntil :one
+SELECT snoozed_until FROM snoozed WHERE dosage_at = ?;
+
+-- name: DeleteSnooze :exec
+DELETE FROM snoozed WHERE dosage_at = ?;
+
+-- name: Snoozes :many
+SELECT * FROM snoozed ORDER BY dosage_at;
+
+-- name: ImportSnooze :execrows
+INSERT INTO snoozed (dosage_at, snoozed_until) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING;
+
+-- name: LogWebhookDelivery :exec
+INSERT INTO webhook_deliveries (event_id, event_type, url, attempt, status_code, error, attempted_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: WebhookDeliveries :many
+SELECT * FROM webhook_deliveries ORDER BY attempted_at DESC, id DESC LIMIT ?;
+
+-- name: PruneWebhookDeliveries :exec
+DELETE FROM webhook_deliveries WHERE attempted_at < ?;
+
+-- name: AllWebhookDeliveries :many
+SELECT * FROM webhook_deliveries ORDER BY attempted_at, id;
+
+-- name: ImportWebhookDelivery :execrows
+INSERT INTO webhook_deliveries (event_id, event_type, url, attempt, status_code, error, attempted_at)
+	SELECT sqlc.arg(event_id), sqlc.arg(event_type), sqlc.arg(url), sqlc.arg(attempt), sqlc.arg(status_code), sqlc.arg(error), sqlc.arg(attempted_at)
+	WHERE NOT EXISTS (SELECT 1 FROM webhook_deliveries
+		WHERE event_id = sqlc.arg(event_id) AND url = sqlc.arg(url) AND attempt = sqlc.arg(attempt));
+
+-- name: AddStock :one
+INSERT INTO stock (hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: StockEntries :many
+SELECT * FROM stock WHERE hrt_type = ? ORDER BY added_at DESC, id DESC;
+
+-- name: AvailableStock :many
+SELECT * FROM stock WHERE hrt_type = ? AND remaining > 0
+	ORDER BY expires_at IS NULL, expires_at, added_at, id;
+
+-- name: UpdateStock :one
+UPDATE stock SET medication = ?, quantity = ?, remaining = ?, lot = ?, expires_at = ?
+	WHERE id = ? RETURNING *;
+
+-- name: DeleteStock :one
+DELETE FROM stock WHERE id = ? RETURNING *;
+
+-- name: UseStock :exec
+INSERT INTO stock_usage (dosage_at, stock_id, amount) VALUES (?, ?, ?);
+
+-- name: MarkRefillNotified :exec
+INSERT INTO refill_notified (stock_id) VALUES (?);
+
+-- name: AllStock :many
+SELECT * FROM stock ORDER BY id;
+
+-- name: SetStockRemaining :exec
+UPDATE stock SET remaining = ? WHERE id = ?;
+
+-- name: StockID :one
+SELECT id FROM stock WHERE hrt_type = ? AND medication = ? AND lot = ? AND added_at = ?
+	ORDER BY id LIMIT 1;
+
+-- name: AllStockUsage :many
+SELECT * FROM stock_usage ORDER BY dosage_at, stock_id;
+
+-- name: ImportStockUsage :execrows
+INSERT INTO stock_usage (dosage_at, stock_id, amount) VALUES (?, ?, ?)
+	ON CONFLICT (dosage_at, stock_id) DO NOTHING;
+
+-- name: RefillNotifications :many
+SELECT * FROM refill_notified ORDER BY stock_id;
+
+-- name: ImportRefillNotification :execrows
+INSERT INTO refill_notified (stock_id, notified_at) VALUES (?, ?)
+	ON CONFLICT (stock_id) DO NOTHING;
+
+-- name: AddPrescription :one
+INSERT INTO prescriptions (hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: Prescription :one
+SELECT * FROM prescriptions WHERE id = ?;
+
+-- name: Prescriptions :many
+SELECT
+	sqlc.embed(prescriptions),
+	(SELECT COUNT(*) FROM stock WHERE stock.prescription_id = prescriptions.id) AS fills,
+	(SELECT COUNT(DISTINCT stock_usage.dosage_at) FROM stock_usage
+		JOIN stock ON stock.id = stock_usage.stock_id
+		WHERE stock.prescription_id = prescriptions.id) AS doses
+FROM prescriptions WHERE hrt_type = ? ORDER BY written_at DESC, id DESC;
+
+-- name: UpdatePrescription :one
+UPDATE prescriptions SET prescriber = ?, medication = ?, strength = ?, refills = ?, written_at = ?, expires_at = ?
+	WHERE id = ? RETURNING *;
+
+-- name: DeletePrescription :one
+DELETE FROM prescriptions WHERE id = ? RETURNING *;
+
+-- name: PrescriptionFills :one
+SELECT COUNT(*) FROM stock WHERE prescription_id = ?;
+
+-- name: UseRefill :exec
+UPDATE prescriptions SET refills = refills - 1 WHERE id = ?;
+
+-- name: DoseSources :many
+SELECT stock_usage.dosage_at, stock_usage.amount, stock.id AS stock_id, stock.medication, stock.prescription_id
+	FROM stock_usage JOIN stock ON stock.id = stock_usage.stock_id
+	WHERE stock.hrt_type = sqlc.arg(hrt_type) AND stock_usage.dosage_at >= sqlc.arg(since) AND stock_usage.dosage_at < sqlc.arg(before)
+	ORDER BY stock_usage.dosage_at, stock.id;
+
+-- name: MarkPrescriptionNotified :exec
+INSERT INTO prescription_notified (prescription_id, reason) VALUES (?, ?);
+
+-- name: AllPrescriptions :many
+SELECT * FROM prescriptions ORDER BY id;
+
+-- name: PrescriptionID :one
+SELECT id FROM prescriptions
+	WHERE hrt_type = ? AND prescriber = ? AND medication = ? AND written_at = ? AND added_at = ?
+	ORDER BY id LIMIT 1;
+
+-- name: PrescriptionNotifications :many
+SELECT * FROM prescription_notified ORDER BY prescription_id, reason;
+
+-- name: ImportPrescriptionNotification :execrows
+INSERT INTO prescription_notified (prescription_id, reason, notified_at) VALUES (?, ?, ?)
+	ON CONFLICT (prescription_id, reason) DO NOTHING;
+
+-- name: AddLabAppointment :one
+INSERT INTO lab_appointments (hrt_type, target, scheduled_at, note, created_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING *;
+
+-- name: LabAppointment :one
+SELECT * FROM lab_appointments WHERE id = ?;
+
+-- name: LabAppointments :many
+SELECT * FROM lab_appointments WHERE hrt_type = ? ORDER BY scheduled_at DESC, id DESC;
+
+-- name: OpenLabAppointmentsBetween :many
+SELECT * FROM lab_appointments
+	WHERE hrt_type = sqlc.arg(hrt_type) AND scheduled_at >= sqlc.arg(since) AND scheduled_at < sqlc.arg(before)
+	AND NOT EXISTS (SELECT 1 FROM lab_results WHERE lab_results.appointment_id = lab_appointments.id)
+	ORDER BY scheduled_at;
+
+-- name: UpdateLabAppointment :one
+UPDATE lab_appointments SET target = ?, scheduled_at = ?, note = ? WHERE id = ? RETURNING *;
+
+-- name: DeleteLabAppointment :one
+DELETE FROM lab_appointments WHERE id = ? RETURNING *;
+
+-- name: MarkLabNotified :exec
+INSERT INTO lab_notified (appointment_id) VALUES (?);
+
+-- name: AddLabResult :one
+INSERT INTO lab_results (hrt_type, drawn_at, value, appointment_id, note, added_at)
+	VALUES (?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: LabResults :many
+SELECT * FROM lab_results WHERE hrt_type = ? ORDER BY drawn_at DESC, id DESC;
+
+-- name: LabResultsBetween :many
+SELECT * FROM lab_results
+	WHERE hrt_type = sqlc.arg(hrt_type) AND drawn_at >= sqlc.arg(since) AND drawn_at < sqlc.arg(before)
+	ORDER BY drawn_at;
+
+-- name: DeleteLabResult :one
+DELETE FROM lab_results WHERE id = ? RETURNING *;
+
+-- name: AllLabAppointments :many
+SELECT * FROM lab_appointments ORDER BY id;
+
+-- name: LabAppointmentID :one
+SELECT id FROM lab_appointments WHERE hrt_type = ? AND target = ? AND scheduled_at = ? AND created_at = ?
+	ORDER BY id LIMIT 1;
+
+-- name: AllLabResults :many
+SELECT * FROM lab_results ORDER BY id;
+
+-- name: ImportLabResult :execrows
+INSERT INTO lab_results (hrt_type, drawn_at, value, appointment_id, note, added_at)
+	SELECT sqlc.arg(hrt_type), sqlc.arg(drawn_at), sqlc.arg(value), sqlc.arg(appointment_id), sqlc.arg(note), sqlc.arg(added_at)
+	WHERE NOT EXISTS (SELECT 1 FROM lab_results
+		WHERE hrt_type = sqlc.arg(hrt_type) AND drawn_at = sqlc.arg(drawn_at) AND added_at = sqlc.arg(added_at))
+	ON CONFLICT DO NOTHING;
+
+-- name: LabNotifications :many
+SELECT * FROM lab_notified ORDER BY appointment_id;
+
+-- name: ImportLabNotification :execrows
+INSERT INTO lab_notified (appointment_id, notified_at) VALUES (?, ?)
+	ON CONFLICT (appointment_id) DO NOTHING;
+
+-- name: AddJournalEntry :one
+INSERT INTO journal_entries (hrt_type, written_at, mood, notes, added_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING *;
+
+-- name: JournalEntry :one
+SELECT * FROM journal_entries WHERE id = ?;
+
+-- name: JournalEntriesBetween :many
+SELECT * FROM journal_entries
+	WHERE hrt_type = sqlc.arg(hrt_type) AND written_at >= sqlc.arg(since) AND written_at < sqlc.arg(before)
+	ORDER BY written_at, id;
+
+-- name: UpdateJournalEntry :one
+UPDATE journal_entries SET written_at = ?, mood = ?, notes = ? WHERE id = ? RETURNING *;
+
+-- name: DeleteJournalEntry :one
+DELETE FROM journal_entries WHERE id = ? RETURNING *;
+
+-- name: AddJournalTag :exec
+INSERT INTO journal_tags (entry_id, tag) VALUES (?, ?);
+
+-- name: JournalEntryTags :many
+SELECT tag FROM journal_tags WHERE entry_id = ? ORDER BY tag;
+
+-- name: JournalTagsBetween :many
+SELECT journal_tags.* FROM journal_tags
+	JOIN journal_entries ON journal_entries.id = journal_tags.entry_id
+	WHERE journal_entries.hrt_type = sqlc.arg(hrt_type)
+	AND journal_entries.written_at >= sqlc.arg(since) AND journal_entries.written_at < sqlc.arg(before)
+	ORDER BY journal_tags.entry_id, journal_tags.tag;
+
+-- name: DeleteJournalTags :exec
+DELETE FROM journal_tags WHERE entry_id = ?;
+
+-- name: AllJournalEntries :many
+SELECT * FROM journal_entries ORDER BY id;
+
+-- name: JournalEntryID :one
+SELECT id FROM journal_entries WHERE hrt_type = ? AND written_at = ? AND added_at = ?
+	ORDER BY id LIMIT 1;
+
+-- name: AllJournalTags :many
+SELECT * FROM journal_tags ORDER BY entry_id, tag;
+
+-- name: ImportJournalTag :execrows
+INSERT INTO journal_tags (entry_id, tag) VALUES (?, ?)
+	ON CONFLICT (entry_id, tag) DO NOTHING;
+
+-- name: AddRemoval :one
+INSERT INTO removals (dosage_at, hrt_type, removed_at, reason, added_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING *;
+
+-- name: Removal :one
+SELECT * FROM removals WHERE dosage_at = ?;
+
+-- name: DeleteRemoval :one
+DELETE FROM removals WHERE dosage_at = ? RETURNING *;
+
+-- name: RemovalsBetween :many
+SELECT * FROM removals
+	WHERE hrt_type = sqlc.arg(hrt_type) AND dosage_at >= sqlc.arg(since) AND dosage_at < sqlc.arg(before)
+	ORDER BY dosage_at;
+
+-- name: UnnotifiedRemovalsBetween :many
+SELECT * FROM removals
+	WHERE hrt_type = sqlc.arg(hrt_type) AND removed_at >= sqlc.arg(since) AND removed_at < sqlc.arg(before)
+	AND NOT EXISTS (SELECT 1 FROM removal_notified WHERE removal_notified.dosage_at = removals.dosage_at)
+	ORDER BY removed_at;
+
+-- name: MarkRemovalNotified :exec
+INSERT INTO removal_notified (dosage_at) VALUES (?);
+
+-- name: AllRemovals :many
+SELECT * FROM removals ORDER BY dosage_at;
+
+-- name: ImportRemoval :execrows
+INSERT INTO removals (dosage_at, hrt_type, removed_at, reason, added_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING;
+
+-- name: RemovalNotifications :many
+SELECT * FROM removal_notified ORDER BY dosage_at;
+
+-- name: ImportRemovalNotification :execrows
+INSERT INTO removal_notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING;
+
+-- name: AddPause :one
+INSERT INTO pauses (hrt_type, started_at, ended_at, reason, added_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING *;
+
+-- name: Pause :one
+SELECT * FROM pauses WHERE id = ?;
+
+-- name: Pauses :many
+SELECT * FROM pauses WHERE hrt_type = ? ORDER BY started_at DESC, id DESC;
+
+-- name: PausesBetween :many
+SELECT * FROM pauses
+	WHERE hrt_type = sqlc.arg(hrt_type) AND started_at < sqlc.arg(before)
+	AND (ended_at IS NULL OR ended_at > sqlc.arg(since))
+	ORDER BY started_at;
+
+-- name: ActivePause :one
+SELECT * FROM pauses
+	WHERE hrt_type = sqlc.arg(hrt_type) AND started_at <= sqlc.arg(at)
+	AND (ended_at IS NULL OR ended_at > sqlc.arg(at))
+	ORDER BY started_at DESC LIMIT 1;
+
+-- name: EndPause :one
+UPDATE pauses SET ended_at = ? WHERE id = ? RETURNING *;
+
+-- name: DeletePause :one
+DELETE FROM pauses WHERE id = ? RETURNING *;
+
+-- name: AllPauses :many
+SELECT * FROM pauses ORDER BY id;
+
+-- name: ImportPause :execrows
+INSERT INTO pauses (hrt_type, started_at, ended_at, reason, added_at)
+	SELECT sqlc.arg(hrt_type), sqlc.arg(started_at), sqlc.arg(ended_at), sqlc.arg(reason), sqlc.arg(added_at)
+	WHERE NOT EXISTS (SELECT 1 FROM pauses
+		WHERE hrt_type = sqlc.arg(hrt_type) AND started_at = sqlc.arg(started_at) AND added_at = sqlc.arg(added_at));
+
+-- name: UnmarkNotifiedBefore :exec
+DELETE FROM notified WHERE dosage_at = ? AND datetime(notified_at) < datetime(?);
+
+-- name: UnmarkOverdueBefore :exec
+DELETE FROM overdue_notified WHERE dosage_at = ? AND datetime(notified_at) < datetime(?);
+
+-- name: MarkMissed :exec
+INSERT INTO missed_notified (dosage_at) VALUES (?);
+
+-- name: UnmarkMissedBefore :exec
+DELETE FROM missed_notified WHERE dosage_at = ? AND datetime(notified_at) < datetime(?);
+
+-- name: AddTrip :one
+INSERT INTO trips (hrt_type, departure_zone, destination_zone, departs_at, note, added_at)
+	VALUES (?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: Trip :one
+SELECT * FROM trips WHERE id = ?;
+
+-- name: Trips :many
+SELECT * FROM trips WHERE hrt_type = ? ORDER BY departs_at DESC, id DESC;
+
+-- name: TripBefore :one
+SELECT * FROM trips WHERE hrt_type = ? AND departs_at < ? ORDER BY departs_at DESC, id DESC LIMIT 1;
+
+-- name: DeleteTrip :one
+DELETE FROM trips WHERE id = ? RETURNING *;
+
+-- name: AllTrips :many
+SELECT * FROM trips ORDER BY id;
+
+-- name: ImportTrip :execrows
+INSERT INTO trips (hrt_type, departure_zone, destination_zone, departs_at, note, added_at)
+	SELECT sqlc.arg(hrt_type), sqlc.arg(departure_zone), sqlc.arg(destination_zone), sqlc.arg(departs_at), sqlc.arg(note), sqlc.arg(added_at)
+	WHERE NOT EXISTS (SELECT 1 FROM trips
+		WHERE hrt_type = sqlc.arg(hrt_type) AND departs_at = sqlc.arg(departs_at) AND added_at = sqlc.arg(added_at));
+
+-- name: AddSkip :one
+INSERT INTO skips (hrt_type, due_at, skipped_at, reason) VALUES (?, ?, ?, ?) RETURNING *;
+
+-- name: Skips :many
+SELECT * FROM skips WHERE hrt_type = ? ORDER BY due_at DESC;
+
+-- name: SkipsBetween :many
+SELECT * FROM skips
+	WHERE hrt_type = sqlc.arg(hrt_type) AND due_at >= sqlc.arg(since) AND due_at < sqlc.arg(before)
+	ORDER BY due_at;
+
+-- name: LastSkipAfter :one
+SELECT * FROM skips WHERE hrt_type = ? AND due_at > ? ORDER BY due_at DESC LIMIT 1;
+
+-- name: DeleteSkip :one
+DELETE FROM skips WHERE id = ? RETURNING *;
+
+-- name: AllSkips :many
+SELECT * FROM skips ORDER BY id;
+
+-- name: ImportSkip :execrows
+INSERT INTO skips (hrt_type, due_at, skipped_at, reason) VALUES (?, ?, ?, ?)
+	ON CONFLICT (hrt_type, due_at) DO NOTHING;
+
+-- name: MissedNotifications :many
+SELECT * FROM missed_notified ORDER BY dosage_at;
+
+-- name: ImportMissedNotification :execrows
+INSERT INTO missed_notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING;
@@ -7,24 +7,2923 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
-const deleteLastDose = `-- name: DeleteLastDose :exec
-DELETE FROM hrt_history WHERE dosage_at = (SELECT dosage_at FROM hrt_history WHERE hrt_history.hrt_type = ? ORDER BY dosage_at DESC LIMIT 1)
+const activePause = `-- name: ActivePause :one
+SELECT id, hrt_type, started_at, ended_at, reason, added_at FROM pauses
+	WHERE hrt_type = ?1 AND started_at <= ?2
+	AND (ended_at IS NULL OR ended_at > ?2)
+	ORDER BY started_at DESC LIMIT 1
 `
 
-func (q *Queries) DeleteLastDose(ctx context.Context, hrtType string) error {
-	_, err := q.db.ExecContext(ctx, deleteLastDose, hrtType)
+type ActivePauseParams struct {
+	HRTType string
+	At      time.Time
+}
+
+func (q *Queries) ActivePause(ctx context.Context, arg ActivePauseParams) (Pause, error) {
+	row := q.db.QueryRowContext(ctx, activePause, arg.HRTType, arg.At)
+	var i Pause
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const addJournalEntry = `-- name: AddJournalEntry :one
+INSERT INTO journal_entries (hrt_type, written_at, mood, notes, added_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING id, hrt_type, written_at, mood, notes, added_at
+`
+
+type AddJournalEntryParams struct {
+	HRTType   string
+	WrittenAt time.Time
+	Mood      sql.NullInt64
+	Notes     string
+	AddedAt   time.Time
+}
+
+func (q *Queries) AddJournalEntry(ctx context.Context, arg AddJournalEntryParams) (JournalEntry, error) {
+	row := q.db.QueryRowContext(ctx, addJournalEntry,
+		arg.HRTType,
+		arg.WrittenAt,
+		arg.Mood,
+		arg.Notes,
+		arg.AddedAt,
+	)
+	var i JournalEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.WrittenAt,
+		&i.Mood,
+		&i.Notes,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const addJournalTag = `-- name: AddJournalTag :exec
+INSERT INTO journal_tags (entry_id, tag) VALUES (?, ?)
+`
+
+type AddJournalTagParams struct {
+	EntryID int64
+	Tag     string
+}
+
+func (q *Queries) AddJournalTag(ctx context.Context, arg AddJournalTagParams) error {
+	_, err := q.db.ExecContext(ctx, addJournalTag, arg.EntryID, arg.Tag)
+	return err
+}
+
+const addLabAppointment = `-- name: AddLabAppointment :one
+INSERT INTO lab_appointments (hrt_type, target, scheduled_at, note, created_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING id, hrt_type, target, scheduled_at, note, created_at
+`
+
+type AddLabAppointmentParams struct {
+	HRTType     string
+	Target      string
+	ScheduledAt time.Time
+	Note        string
+	CreatedAt   time.Time
+}
+
+func (q *Queries) AddLabAppointment(ctx context.Context, arg AddLabAppointmentParams) (LabAppointment, error) {
+	row := q.db.QueryRowContext(ctx, addLabAppointment,
+		arg.HRTType,
+		arg.Target,
+		arg.ScheduledAt,
+		arg.Note,
+		arg.CreatedAt,
+	)
+	var i LabAppointment
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Target,
+		&i.ScheduledAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const addLabResult = `-- name: AddLabResult :one
+INSERT INTO lab_results (hrt_type, drawn_at, value, appointment_id, note, added_at)
+	VALUES (?, ?, ?, ?, ?, ?) RETURNING id, hrt_type, drawn_at, value, appointment_id, note, added_at
+`
+
+type AddLabResultParams struct {
+	HRTType       string
+	DrawnAt       time.Time
+	Value         float64
+	AppointmentID sql.NullInt64
+	Note          string
+	AddedAt       time.Time
+}
+
+func (q *Queries) AddLabResult(ctx context.Context, arg AddLabResultParams) (LabResult, error) {
+	row := q.db.QueryRowContext(ctx, addLabResult,
+		arg.HRTType,
+		arg.DrawnAt,
+		arg.Value,
+		arg.AppointmentID,
+		arg.Note,
+		arg.AddedAt,
+	)
+	var i LabResult
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DrawnAt,
+		&i.Value,
+		&i.AppointmentID,
+		&i.Note,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const addPause = `-- name: AddPause :one
+INSERT INTO pauses (hrt_type, started_at, ended_at, reason, added_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING id, hrt_type, started_at, ended_at, reason, added_at
+`
+
+type AddPauseParams struct {
+	HRTType   string
+	StartedAt time.Time
+	EndedAt   sql.NullTime
+	Reason    string
+	AddedAt   time.Time
+}
+
+func (q *Queries) AddPause(ctx context.Context, arg AddPauseParams) (Pause, error) {
+	row := q.db.QueryRowContext(ctx, addPause,
+		arg.HRTType,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.Reason,
+		arg.AddedAt,
+	)
+	var i Pause
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const addPrescription = `-- name: AddPrescription :one
+INSERT INTO prescriptions (hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at
+`
+
+type AddPrescriptionParams struct {
+	HRTType    string
+	Prescriber string
+	Medication string
+	Strength   string
+	Refills    int64
+	WrittenAt  time.Time
+	ExpiresAt  time.Time
+	AddedAt    time.Time
+}
+
+func (q *Queries) AddPrescription(ctx context.Context, arg AddPrescriptionParams) (Prescription, error) {
+	row := q.db.QueryRowContext(ctx, addPrescription,
+		arg.HRTType,
+		arg.Prescriber,
+		arg.Medication,
+		arg.Strength,
+		arg.Refills,
+		arg.WrittenAt,
+		arg.ExpiresAt,
+		arg.AddedAt,
+	)
+	var i Prescription
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Prescriber,
+		&i.Medication,
+		&i.Strength,
+		&i.Refills,
+		&i.WrittenAt,
+		&i.ExpiresAt,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const addRemoval = `-- name: AddRemoval :one
+INSERT INTO removals (dosage_at, hrt_type, removed_at, reason, added_at)
+	VALUES (?, ?, ?, ?, ?) RETURNING dosage_at, hrt_type, removed_at, reason, added_at
+`
+
+type AddRemovalParams struct {
+	DosageAt  time.Time
+	HRTType   string
+	RemovedAt time.Time
+	Reason    string
+	AddedAt   time.Time
+}
+
+func (q *Queries) AddRemoval(ctx context.Context, arg AddRemovalParams) (Removal, error) {
+	row := q.db.QueryRowContext(ctx, addRemoval,
+		arg.DosageAt,
+		arg.HRTType,
+		arg.RemovedAt,
+		arg.Reason,
+		arg.AddedAt,
+	)
+	var i Removal
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.RemovedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const addSkip = `-- name: AddSkip :one
+INSERT INTO skips (hrt_type, due_at, skipped_at, reason) VALUES (?, ?, ?, ?) RETURNING id, hrt_type, due_at, skipped_at, reason
+`
+
+type AddSkipParams struct {
+	HRTType   string
+	DueAt     time.Time
+	SkippedAt time.Time
+	Reason    string
+}
+
+func (q *Queries) AddSkip(ctx context.Context, arg AddSkipParams) (Skip, error) {
+	row := q.db.QueryRowContext(ctx, addSkip,
+		arg.HRTType,
+		arg.DueAt,
+		arg.SkippedAt,
+		arg.Reason,
+	)
+	var i Skip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DueAt,
+		&i.SkippedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const addStock = `-- name: AddStock :one
+INSERT INTO stock (hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id
+`
+
+type AddStockParams struct {
+	HRTType        string
+	Medication     string
+	Quantity       float64
+	Remaining      float64
+	Lot            string
+	ExpiresAt      sql.NullTime
+	AddedAt        time.Time
+	PrescriptionID sql.NullInt64
+}
+
+func (q *Queries) AddStock(ctx context.Context, arg AddStockParams) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, addStock,
+		arg.HRTType,
+		arg.Medication,
+		arg.Quantity,
+		arg.Remaining,
+		arg.Lot,
+		arg.ExpiresAt,
+		arg.AddedAt,
+		arg.PrescriptionID,
+	)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Medication,
+		&i.Quantity,
+		&i.Remaining,
+		&i.Lot,
+		&i.ExpiresAt,
+		&i.AddedAt,
+		&i.PrescriptionID,
+	)
+	return i, err
+}
+
+const addTrip = `-- name: AddTrip :one
+INSERT INTO trips (hrt_type, departure_zone, destination_zone, departs_at, note, added_at)
+	VALUES (?, ?, ?, ?, ?, ?) RETURNING id, hrt_type, departure_zone, destination_zone, departs_at, note, added_at
+`
+
+type AddTripParams struct {
+	HRTType         string
+	DepartureZone   string
+	DestinationZone string
+	DepartsAt       time.Time
+	Note            string
+	AddedAt         time.Time
+}
+
+func (q *Queries) AddTrip(ctx context.Context, arg AddTripParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, addTrip,
+		arg.HRTType,
+		arg.DepartureZone,
+		arg.DestinationZone,
+		arg.DepartsAt,
+		arg.Note,
+		arg.AddedAt,
+	)
+	var i Trip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DepartureZone,
+		&i.DestinationZone,
+		&i.DepartsAt,
+		&i.Note,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const allDoses = `-- name: AllDoses :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history ORDER BY dosage_at
+`
+
+func (q *Queries) AllDoses(ctx context.Context) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, allDoses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HRTHistory
+	for rows.Next() {
+		var i HRTHistory
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allJournalEntries = `-- name: AllJournalEntries :many
+SELECT id, hrt_type, written_at, mood, notes, added_at FROM journal_entries ORDER BY id
+`
+
+func (q *Queries) AllJournalEntries(ctx context.Context) ([]JournalEntry, error) {
+	rows, err := q.db.QueryContext(ctx, allJournalEntries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalEntry
+	for rows.Next() {
+		var i JournalEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.WrittenAt,
+			&i.Mood,
+			&i.Notes,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allJournalTags = `-- name: AllJournalTags :many
+SELECT entry_id, tag FROM journal_tags ORDER BY entry_id, tag
+`
+
+func (q *Queries) AllJournalTags(ctx context.Context) ([]JournalTag, error) {
+	rows, err := q.db.QueryContext(ctx, allJournalTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalTag
+	for rows.Next() {
+		var i JournalTag
+		if err := rows.Scan(
+			&i.EntryID,
+			&i.Tag,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allLabAppointments = `-- name: AllLabAppointments :many
+SELECT id, hrt_type, target, scheduled_at, note, created_at FROM lab_appointments ORDER BY id
+`
+
+func (q *Queries) AllLabAppointments(ctx context.Context) ([]LabAppointment, error) {
+	rows, err := q.db.QueryContext(ctx, allLabAppointments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabAppointment
+	for rows.Next() {
+		var i LabAppointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Target,
+			&i.ScheduledAt,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allLabResults = `-- name: AllLabResults :many
+SELECT id, hrt_type, drawn_at, value, appointment_id, note, added_at FROM lab_results ORDER BY id
+`
+
+func (q *Queries) AllLabResults(ctx context.Context) ([]LabResult, error) {
+	rows, err := q.db.QueryContext(ctx, allLabResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabResult
+	for rows.Next() {
+		var i LabResult
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DrawnAt,
+			&i.Value,
+			&i.AppointmentID,
+			&i.Note,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allPauses = `-- name: AllPauses :many
+SELECT id, hrt_type, started_at, ended_at, reason, added_at FROM pauses ORDER BY id
+`
+
+func (q *Queries) AllPauses(ctx context.Context) ([]Pause, error) {
+	rows, err := q.db.QueryContext(ctx, allPauses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Pause
+	for rows.Next() {
+		var i Pause
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Reason,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allPrescriptions = `-- name: AllPrescriptions :many
+SELECT id, hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at FROM prescriptions ORDER BY id
+`
+
+func (q *Queries) AllPrescriptions(ctx context.Context) ([]Prescription, error) {
+	rows, err := q.db.QueryContext(ctx, allPrescriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Prescription
+	for rows.Next() {
+		var i Prescription
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Prescriber,
+			&i.Medication,
+			&i.Strength,
+			&i.Refills,
+			&i.WrittenAt,
+			&i.ExpiresAt,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allRemovals = `-- name: AllRemovals :many
+SELECT dosage_at, hrt_type, removed_at, reason, added_at FROM removals ORDER BY dosage_at
+`
+
+func (q *Queries) AllRemovals(ctx context.Context) ([]Removal, error) {
+	rows, err := q.db.QueryContext(ctx, allRemovals)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Removal
+	for rows.Next() {
+		var i Removal
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.RemovedAt,
+			&i.Reason,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allSkips = `-- name: AllSkips :many
+SELECT id, hrt_type, due_at, skipped_at, reason FROM skips ORDER BY id
+`
+
+func (q *Queries) AllSkips(ctx context.Context) ([]Skip, error) {
+	rows, err := q.db.QueryContext(ctx, allSkips)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skip
+	for rows.Next() {
+		var i Skip
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DueAt,
+			&i.SkippedAt,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allStock = `-- name: AllStock :many
+SELECT id, hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id FROM stock ORDER BY id
+`
+
+func (q *Queries) AllStock(ctx context.Context) ([]Stock, error) {
+	rows, err := q.db.QueryContext(ctx, allStock)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Stock
+	for rows.Next() {
+		var i Stock
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Medication,
+			&i.Quantity,
+			&i.Remaining,
+			&i.Lot,
+			&i.ExpiresAt,
+			&i.AddedAt,
+			&i.PrescriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allStockUsage = `-- name: AllStockUsage :many
+SELECT dosage_at, stock_id, amount FROM stock_usage ORDER BY dosage_at, stock_id
+`
+
+func (q *Queries) AllStockUsage(ctx context.Context) ([]StockUsage, error) {
+	rows, err := q.db.QueryContext(ctx, allStockUsage)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StockUsage
+	for rows.Next() {
+		var i StockUsage
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.StockID,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allTrips = `-- name: AllTrips :many
+SELECT id, hrt_type, departure_zone, destination_zone, departs_at, note, added_at FROM trips ORDER BY id
+`
+
+func (q *Queries) AllTrips(ctx context.Context) ([]Trip, error) {
+	rows, err := q.db.QueryContext(ctx, allTrips)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trip
+	for rows.Next() {
+		var i Trip
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DepartureZone,
+			&i.DestinationZone,
+			&i.DepartsAt,
+			&i.Note,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const allWebhookDeliveries = `-- name: AllWebhookDeliveries :many
+SELECT id, event_id, event_type, url, attempt, status_code, error, attempted_at FROM webhook_deliveries ORDER BY attempted_at, id
+`
+
+func (q *Queries) AllWebhookDeliveries(ctx context.Context) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, allWebhookDeliveries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.Url,
+			&i.Attempt,
+			&i.StatusCode,
+			&i.Error,
+			&i.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const availableStock = `-- name: AvailableStock :many
+SELECT id, hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id FROM stock WHERE hrt_type = ? AND remaining > 0
+	ORDER BY expires_at IS NULL, expires_at, added_at, id
+`
+
+func (q *Queries) AvailableStock(ctx context.Context, hrtType string) ([]Stock, error) {
+	rows, err := q.db.QueryContext(ctx, availableStock, hrtType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Stock
+	for rows.Next() {
+		var i Stock
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Medication,
+			&i.Quantity,
+			&i.Remaining,
+			&i.Lot,
+			&i.ExpiresAt,
+			&i.AddedAt,
+			&i.PrescriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteDose = `-- name: DeleteDose :one
+DELETE FROM hrt_history WHERE dosage_at = ? RETURNING dosage_at, hrt_type, notes, tags, zone
+`
+
+func (q *Queries) DeleteDose(ctx context.Context, dosageAt time.Time) (HRTHistory, error) {
+	row := q.db.QueryRowContext(ctx, deleteDose, dosageAt)
+	var i HRTHistory
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.Notes,
+		&i.Tags,
+		&i.Zone,
+	)
+	return i, err
+}
+
+const deleteJournalEntry = `-- name: DeleteJournalEntry :one
+DELETE FROM journal_entries WHERE id = ? RETURNING id, hrt_type, written_at, mood, notes, added_at
+`
+
+func (q *Queries) DeleteJournalEntry(ctx context.Context, id int64) (JournalEntry, error) {
+	row := q.db.QueryRowContext(ctx, deleteJournalEntry, id)
+	var i JournalEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.WrittenAt,
+		&i.Mood,
+		&i.Notes,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const deleteJournalTags = `-- name: DeleteJournalTags :exec
+DELETE FROM journal_tags WHERE entry_id = ?
+`
+
+func (q *Queries) DeleteJournalTags(ctx context.Context, entryID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteJournalTags, entryID)
+	return err
+}
+
+const deleteLabAppointment = `-- name: DeleteLabAppointment :one
+DELETE FROM lab_appointments WHERE id = ? RETURNING id, hrt_type, target, scheduled_at, note, created_at
+`
+
+func (q *Queries) DeleteLabAppointment(ctx context.Context, id int64) (LabAppointment, error) {
+	row := q.db.QueryRowContext(ctx, deleteLabAppointment, id)
+	var i LabAppointment
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Target,
+		&i.ScheduledAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteLabResult = `-- name: DeleteLabResult :one
+DELETE FROM lab_results WHERE id = ? RETURNING id, hrt_type, drawn_at, value, appointment_id, note, added_at
+`
+
+func (q *Queries) DeleteLabResult(ctx context.Context, id int64) (LabResult, error) {
+	row := q.db.QueryRowContext(ctx, deleteLabResult, id)
+	var i LabResult
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DrawnAt,
+		&i.Value,
+		&i.AppointmentID,
+		&i.Note,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const deleteLastDose = `-- name: DeleteLastDose :one
+DELETE FROM hrt_history WHERE dosage_at = (SELECT dosage_at FROM hrt_history WHERE hrt_history.hrt_type = ? ORDER BY dosage_at DESC LIMIT 1) RETURNING dosage_at, hrt_type, notes, tags, zone
+`
+
+func (q *Queries) DeleteLastDose(ctx context.Context, hrtType string) (HRTHistory, error) {
+	row := q.db.QueryRowContext(ctx, deleteLastDose, hrtType)
+	var i HRTHistory
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.Notes,
+		&i.Tags,
+		&i.Zone,
+	)
+	return i, err
+}
+
+const deletePause = `-- name: DeletePause :one
+DELETE FROM pauses WHERE id = ? RETURNING id, hrt_type, started_at, ended_at, reason, added_at
+`
+
+func (q *Queries) DeletePause(ctx context.Context, id int64) (Pause, error) {
+	row := q.db.QueryRowContext(ctx, deletePause, id)
+	var i Pause
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const deletePrescription = `-- name: DeletePrescription :one
+DELETE FROM prescriptions WHERE id = ? RETURNING id, hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at
+`
+
+func (q *Queries) DeletePrescription(ctx context.Context, id int64) (Prescription, error) {
+	row := q.db.QueryRowContext(ctx, deletePrescription, id)
+	var i Prescription
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Prescriber,
+		&i.Medication,
+		&i.Strength,
+		&i.Refills,
+		&i.WrittenAt,
+		&i.ExpiresAt,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const deleteRemoval = `-- name: DeleteRemoval :one
+DELETE FROM removals WHERE dosage_at = ? RETURNING dosage_at, hrt_type, removed_at, reason, added_at
+`
+
+func (q *Queries) DeleteRemoval(ctx context.Context, dosageAt time.Time) (Removal, error) {
+	row := q.db.QueryRowContext(ctx, deleteRemoval, dosageAt)
+	var i Removal
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.RemovedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const deleteSkip = `-- name: DeleteSkip :one
+DELETE FROM skips WHERE id = ? RETURNING id, hrt_type, due_at, skipped_at, reason
+`
+
+func (q *Queries) DeleteSkip(ctx context.Context, id int64) (Skip, error) {
+	row := q.db.QueryRowContext(ctx, deleteSkip, id)
+	var i Skip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DueAt,
+		&i.SkippedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const deleteSnooze = `-- name: DeleteSnooze :exec
+DELETE FROM snoozed WHERE dosage_at = ?
+`
+
+func (q *Queries) DeleteSnooze(ctx context.Context, dosageAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteSnooze, dosageAt)
+	return err
+}
+
+const deleteStock = `-- name: DeleteStock :one
+DELETE FROM stock WHERE id = ? RETURNING id, hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id
+`
+
+func (q *Queries) DeleteStock(ctx context.Context, id int64) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, deleteStock, id)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Medication,
+		&i.Quantity,
+		&i.Remaining,
+		&i.Lot,
+		&i.ExpiresAt,
+		&i.AddedAt,
+		&i.PrescriptionID,
+	)
+	return i, err
+}
+
+const deleteTrip = `-- name: DeleteTrip :one
+DELETE FROM trips WHERE id = ? RETURNING id, hrt_type, departure_zone, destination_zone, departs_at, note, added_at
+`
+
+func (q *Queries) DeleteTrip(ctx context.Context, id int64) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, deleteTrip, id)
+	var i Trip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DepartureZone,
+		&i.DestinationZone,
+		&i.DepartsAt,
+		&i.Note,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const dosageHistory = `-- name: DosageHistory :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC
+`
+
+func (q *Queries) DosageHistory(ctx context.Context, hrtType string) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, dosageHistory, hrtType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HRTHistory
+	for rows.Next() {
+		var i HRTHistory
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dosageHistoryBetween = `-- name: DosageHistoryBetween :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history
+	WHERE hrt_type = ? AND dosage_at >= ? AND dosage_at < ?
+	ORDER BY dosage_at
+`
+
+type DosageHistoryBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) DosageHistoryBetween(ctx context.Context, arg DosageHistoryBetweenParams) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, dosageHistoryBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HRTHistory
+	for rows.Next() {
+		var i HRTHistory
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const doseAt = `-- name: DoseAt :one
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history WHERE dosage_at = ?
+`
+
+func (q *Queries) DoseAt(ctx context.Context, dosageAt time.Time) (HRTHistory, error) {
+	row := q.db.QueryRowContext(ctx, doseAt, dosageAt)
+	var i HRTHistory
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.Notes,
+		&i.Tags,
+		&i.Zone,
+	)
+	return i, err
+}
+
+const doseBefore = `-- name: DoseBefore :one
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history WHERE hrt_type = ? AND dosage_at < ? ORDER BY dosage_at DESC LIMIT 1
+`
+
+type DoseBeforeParams struct {
+	HRTType  string
+	DosageAt time.Time
+}
+
+func (q *Queries) DoseBefore(ctx context.Context, arg DoseBeforeParams) (HRTHistory, error) {
+	row := q.db.QueryRowContext(ctx, doseBefore, arg.HRTType, arg.DosageAt)
+	var i HRTHistory
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.Notes,
+		&i.Tags,
+		&i.Zone,
+	)
+	return i, err
+}
+
+const doseSources = `-- name: DoseSources :many
+SELECT stock_usage.dosage_at, stock_usage.amount, stock.id AS stock_id, stock.medication, stock.prescription_id
+	FROM stock_usage JOIN stock ON stock.id = stock_usage.stock_id
+	WHERE stock.hrt_type = ? AND stock_usage.dosage_at >= ? AND stock_usage.dosage_at < ?
+	ORDER BY stock_usage.dosage_at, stock.id
+`
+
+type DoseSourcesParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+type DoseSourcesRow struct {
+	DosageAt       time.Time
+	Amount         float64
+	StockID        int64
+	Medication     string
+	PrescriptionID sql.NullInt64
+}
+
+func (q *Queries) DoseSources(ctx context.Context, arg DoseSourcesParams) ([]DoseSourcesRow, error) {
+	rows, err := q.db.QueryContext(ctx, doseSources, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DoseSourcesRow
+	for rows.Next() {
+		var i DoseSourcesRow
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.Amount,
+			&i.StockID,
+			&i.Medication,
+			&i.PrescriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const doseTypes = `-- name: DoseTypes :many
+SELECT DISTINCT hrt_type FROM hrt_history ORDER BY hrt_type
+`
+
+func (q *Queries) DoseTypes(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, doseTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var hrt_type string
+		if err := rows.Scan(&hrt_type); err != nil {
+			return nil, err
+		}
+		items = append(items, hrt_type)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const endPause = `-- name: EndPause :one
+UPDATE pauses SET ended_at = ? WHERE id = ? RETURNING id, hrt_type, started_at, ended_at, reason, added_at
+`
+
+type EndPauseParams struct {
+	EndedAt sql.NullTime
+	ID      int64
+}
+
+func (q *Queries) EndPause(ctx context.Context, arg EndPauseParams) (Pause, error) {
+	row := q.db.QueryRowContext(ctx, endPause, arg.EndedAt, arg.ID)
+	var i Pause
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const historyPage = `-- name: HistoryPage :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history
+	WHERE hrt_type = ? AND dosage_at >= ? AND dosage_at < ?
+	ORDER BY dosage_at DESC LIMIT ?
+`
+
+type HistoryPageParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+	Limit   int64
+}
+
+func (q *Queries) HistoryPage(ctx context.Context, arg HistoryPageParams) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, historyPage,
+		arg.HRTType,
+		arg.Since,
+		arg.Before,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HRTHistory
+	for rows.Next() {
+		var i HRTHistory
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const historyPageAfter = `-- name: HistoryPageAfter :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history
+	WHERE hrt_type = ? AND dosage_at > ? AND dosage_at < ?
+	ORDER BY dosage_at LIMIT ?
+`
+
+type HistoryPageAfterParams struct {
+	HRTType string
+	After   time.Time
+	Before  time.Time
+	Limit   int64
+}
+
+func (q *Queries) HistoryPageAfter(ctx context.Context, arg HistoryPageAfterParams) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, historyPageAfter,
+		arg.HRTType,
+		arg.After,
+		arg.Before,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HRTHistory
+	for rows.Next() {
+		var i HRTHistory
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importJournalTag = `-- name: ImportJournalTag :execrows
+INSERT INTO journal_tags (entry_id, tag) VALUES (?, ?)
+	ON CONFLICT (entry_id, tag) DO NOTHING
+`
+
+type ImportJournalTagParams struct {
+	EntryID int64
+	Tag     string
+}
+
+func (q *Queries) ImportJournalTag(ctx context.Context, arg ImportJournalTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importJournalTag, arg.EntryID, arg.Tag)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importLabNotification = `-- name: ImportLabNotification :execrows
+INSERT INTO lab_notified (appointment_id, notified_at) VALUES (?, ?)
+	ON CONFLICT (appointment_id) DO NOTHING
+`
+
+type ImportLabNotificationParams struct {
+	AppointmentID int64
+	NotifiedAt    sql.NullTime
+}
+
+func (q *Queries) ImportLabNotification(ctx context.Context, arg ImportLabNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importLabNotification, arg.AppointmentID, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importLabResult = `-- name: ImportLabResult :execrows
+INSERT INTO lab_results (hrt_type, drawn_at, value, appointment_id, note, added_at)
+	SELECT ?1, ?2, ?3, ?4, ?5, ?6
+	WHERE NOT EXISTS (SELECT 1 FROM lab_results
+		WHERE hrt_type = ?1 AND drawn_at = ?2 AND added_at = ?6)
+	ON CONFLICT DO NOTHING
+`
+
+type ImportLabResultParams struct {
+	HRTType       string
+	DrawnAt       time.Time
+	Value         float64
+	AppointmentID sql.NullInt64
+	Note          string
+	AddedAt       time.Time
+}
+
+func (q *Queries) ImportLabResult(ctx context.Context, arg ImportLabResultParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importLabResult, arg.HRTType, arg.DrawnAt, arg.Value, arg.AppointmentID, arg.Note, arg.AddedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importMissedNotification = `-- name: ImportMissedNotification :execrows
+INSERT INTO missed_notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING
+`
+
+type ImportMissedNotificationParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) ImportMissedNotification(ctx context.Context, arg ImportMissedNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importMissedNotification, arg.DosageAt, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importNotification = `-- name: ImportNotification :execrows
+INSERT INTO notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING
+`
+
+type ImportNotificationParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) ImportNotification(ctx context.Context, arg ImportNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importNotification, arg.DosageAt, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importOverdueNotification = `-- name: ImportOverdueNotification :execrows
+INSERT INTO overdue_notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING
+`
+
+type ImportOverdueNotificationParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) ImportOverdueNotification(ctx context.Context, arg ImportOverdueNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importOverdueNotification, arg.DosageAt, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importPause = `-- name: ImportPause :execrows
+INSERT INTO pauses (hrt_type, started_at, ended_at, reason, added_at)
+	SELECT ?1, ?2, ?3, ?4, ?5
+	WHERE NOT EXISTS (SELECT 1 FROM pauses
+		WHERE hrt_type = ?1 AND started_at = ?2 AND added_at = ?5)
+`
+
+type ImportPauseParams struct {
+	HRTType   string
+	StartedAt time.Time
+	EndedAt   sql.NullTime
+	Reason    string
+	AddedAt   time.Time
+}
+
+func (q *Queries) ImportPause(ctx context.Context, arg ImportPauseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importPause, arg.HRTType, arg.StartedAt, arg.EndedAt, arg.Reason, arg.AddedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importPrescriptionNotification = `-- name: ImportPrescriptionNotification :execrows
+INSERT INTO prescription_notified (prescription_id, reason, notified_at) VALUES (?, ?, ?)
+	ON CONFLICT (prescription_id, reason) DO NOTHING
+`
+
+type ImportPrescriptionNotificationParams struct {
+	PrescriptionID int64
+	Reason         string
+	NotifiedAt     sql.NullTime
+}
+
+func (q *Queries) ImportPrescriptionNotification(ctx context.Context, arg ImportPrescriptionNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importPrescriptionNotification, arg.PrescriptionID, arg.Reason, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importRefillNotification = `-- name: ImportRefillNotification :execrows
+INSERT INTO refill_notified (stock_id, notified_at) VALUES (?, ?)
+	ON CONFLICT (stock_id) DO NOTHING
+`
+
+type ImportRefillNotificationParams struct {
+	StockID    int64
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) ImportRefillNotification(ctx context.Context, arg ImportRefillNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importRefillNotification, arg.StockID, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importRemoval = `-- name: ImportRemoval :execrows
+INSERT INTO removals (dosage_at, hrt_type, removed_at, reason, added_at) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING
+`
+
+type ImportRemovalParams struct {
+	DosageAt  time.Time
+	HRTType   string
+	RemovedAt time.Time
+	Reason    string
+	AddedAt   time.Time
+}
+
+func (q *Queries) ImportRemoval(ctx context.Context, arg ImportRemovalParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importRemoval, arg.DosageAt, arg.HRTType, arg.RemovedAt, arg.Reason, arg.AddedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importRemovalNotification = `-- name: ImportRemovalNotification :execrows
+INSERT INTO removal_notified (dosage_at, notified_at) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING
+`
+
+type ImportRemovalNotificationParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) ImportRemovalNotification(ctx context.Context, arg ImportRemovalNotificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importRemovalNotification, arg.DosageAt, arg.NotifiedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importSkip = `-- name: ImportSkip :execrows
+INSERT INTO skips (hrt_type, due_at, skipped_at, reason) VALUES (?, ?, ?, ?)
+	ON CONFLICT (hrt_type, due_at) DO NOTHING
+`
+
+type ImportSkipParams struct {
+	HRTType   string
+	DueAt     time.Time
+	SkippedAt time.Time
+	Reason    string
+}
+
+func (q *Queries) ImportSkip(ctx context.Context, arg ImportSkipParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importSkip, arg.HRTType, arg.DueAt, arg.SkippedAt, arg.Reason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importSnooze = `-- name: ImportSnooze :execrows
+INSERT INTO snoozed (dosage_at, snoozed_until) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO NOTHING
+`
+
+type ImportSnoozeParams struct {
+	DosageAt     time.Time
+	SnoozedUntil time.Time
+}
+
+func (q *Queries) ImportSnooze(ctx context.Context, arg ImportSnoozeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importSnooze, arg.DosageAt, arg.SnoozedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importStockUsage = `-- name: ImportStockUsage :execrows
+INSERT INTO stock_usage (dosage_at, stock_id, amount) VALUES (?, ?, ?)
+	ON CONFLICT (dosage_at, stock_id) DO NOTHING
+`
+
+type ImportStockUsageParams struct {
+	DosageAt time.Time
+	StockID  int64
+	Amount   float64
+}
+
+func (q *Queries) ImportStockUsage(ctx context.Context, arg ImportStockUsageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importStockUsage, arg.DosageAt, arg.StockID, arg.Amount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importTrip = `-- name: ImportTrip :execrows
+INSERT INTO trips (hrt_type, departure_zone, destination_zone, departs_at, note, added_at)
+	SELECT ?1, ?2, ?3, ?4, ?5, ?6
+	WHERE NOT EXISTS (SELECT 1 FROM trips
+		WHERE hrt_type = ?1 AND departs_at = ?4 AND added_at = ?6)
+`
+
+type ImportTripParams struct {
+	HRTType         string
+	DepartureZone   string
+	DestinationZone string
+	DepartsAt       time.Time
+	Note            string
+	AddedAt         time.Time
+}
+
+func (q *Queries) ImportTrip(ctx context.Context, arg ImportTripParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importTrip, arg.HRTType, arg.DepartureZone, arg.DestinationZone, arg.DepartsAt, arg.Note, arg.AddedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const importWebhookDelivery = `-- name: ImportWebhookDelivery :execrows
+INSERT INTO webhook_deliveries (event_id, event_type, url, attempt, status_code, error, attempted_at)
+	SELECT ?1, ?2, ?3, ?4, ?5, ?6, ?7
+	WHERE NOT EXISTS (SELECT 1 FROM webhook_deliveries
+		WHERE event_id = ?1 AND url = ?3 AND attempt = ?4)
+`
+
+type ImportWebhookDeliveryParams struct {
+	EventID     string
+	EventType   string
+	Url         string
+	Attempt     int64
+	StatusCode  sql.NullInt64
+	Error       sql.NullString
+	AttemptedAt time.Time
+}
+
+func (q *Queries) ImportWebhookDelivery(ctx context.Context, arg ImportWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, importWebhookDelivery, arg.EventID, arg.EventType, arg.Url, arg.Attempt, arg.StatusCode, arg.Error, arg.AttemptedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const journalEntriesBetween = `-- name: JournalEntriesBetween :many
+SELECT id, hrt_type, written_at, mood, notes, added_at FROM journal_entries
+	WHERE hrt_type = ? AND written_at >= ? AND written_at < ?
+	ORDER BY written_at, id
+`
+
+type JournalEntriesBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) JournalEntriesBetween(ctx context.Context, arg JournalEntriesBetweenParams) ([]JournalEntry, error) {
+	rows, err := q.db.QueryContext(ctx, journalEntriesBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalEntry
+	for rows.Next() {
+		var i JournalEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.WrittenAt,
+			&i.Mood,
+			&i.Notes,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const journalEntry = `-- name: JournalEntry :one
+SELECT id, hrt_type, written_at, mood, notes, added_at FROM journal_entries WHERE id = ?
+`
+
+func (q *Queries) JournalEntry(ctx context.Context, id int64) (JournalEntry, error) {
+	row := q.db.QueryRowContext(ctx, journalEntry, id)
+	var i JournalEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.WrittenAt,
+		&i.Mood,
+		&i.Notes,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const journalEntryID = `-- name: JournalEntryID :one
+SELECT id FROM journal_entries WHERE hrt_type = ? AND written_at = ? AND added_at = ?
+	ORDER BY id LIMIT 1
+`
+
+type JournalEntryIDParams struct {
+	HRTType   string
+	WrittenAt time.Time
+	AddedAt   time.Time
+}
+
+func (q *Queries) JournalEntryID(ctx context.Context, arg JournalEntryIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, journalEntryID, arg.HRTType, arg.WrittenAt, arg.AddedAt)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const journalEntryTags = `-- name: JournalEntryTags :many
+SELECT tag FROM journal_tags WHERE entry_id = ? ORDER BY tag
+`
+
+func (q *Queries) JournalEntryTags(ctx context.Context, entryID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, journalEntryTags, entryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const journalTagsBetween = `-- name: JournalTagsBetween :many
+SELECT journal_tags.entry_id, journal_tags.tag FROM journal_tags
+	JOIN journal_entries ON journal_entries.id = journal_tags.entry_id
+	WHERE journal_entries.hrt_type = ?
+	AND journal_entries.written_at >= ? AND journal_entries.written_at < ?
+	ORDER BY journal_tags.entry_id, journal_tags.tag
+`
+
+type JournalTagsBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) JournalTagsBetween(ctx context.Context, arg JournalTagsBetweenParams) ([]JournalTag, error) {
+	rows, err := q.db.QueryContext(ctx, journalTagsBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JournalTag
+	for rows.Next() {
+		var i JournalTag
+		if err := rows.Scan(
+			&i.EntryID,
+			&i.Tag,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const labAppointment = `-- name: LabAppointment :one
+SELECT id, hrt_type, target, scheduled_at, note, created_at FROM lab_appointments WHERE id = ?
+`
+
+func (q *Queries) LabAppointment(ctx context.Context, id int64) (LabAppointment, error) {
+	row := q.db.QueryRowContext(ctx, labAppointment, id)
+	var i LabAppointment
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Target,
+		&i.ScheduledAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const labAppointmentID = `-- name: LabAppointmentID :one
+SELECT id FROM lab_appointments WHERE hrt_type = ? AND target = ? AND scheduled_at = ? AND created_at = ?
+	ORDER BY id LIMIT 1
+`
+
+type LabAppointmentIDParams struct {
+	HRTType     string
+	Target      string
+	ScheduledAt time.Time
+	CreatedAt   time.Time
+}
+
+func (q *Queries) LabAppointmentID(ctx context.Context, arg LabAppointmentIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, labAppointmentID, arg.HRTType, arg.Target, arg.ScheduledAt, arg.CreatedAt)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const labAppointments = `-- name: LabAppointments :many
+SELECT id, hrt_type, target, scheduled_at, note, created_at FROM lab_appointments WHERE hrt_type = ? ORDER BY scheduled_at DESC, id DESC
+`
+
+func (q *Queries) LabAppointments(ctx context.Context, hrtType string) ([]LabAppointment, error) {
+	rows, err := q.db.QueryContext(ctx, labAppointments, hrtType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabAppointment
+	for rows.Next() {
+		var i LabAppointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Target,
+			&i.ScheduledAt,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const labNotifications = `-- name: LabNotifications :many
+SELECT appointment_id, notified_at FROM lab_notified ORDER BY appointment_id
+`
+
+func (q *Queries) LabNotifications(ctx context.Context) ([]LabNotified, error) {
+	rows, err := q.db.QueryContext(ctx, labNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabNotified
+	for rows.Next() {
+		var i LabNotified
+		if err := rows.Scan(
+			&i.AppointmentID,
+			&i.NotifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const labResults = `-- name: LabResults :many
+SELECT id, hrt_type, drawn_at, value, appointment_id, note, added_at FROM lab_results WHERE hrt_type = ? ORDER BY drawn_at DESC, id DESC
+`
+
+func (q *Queries) LabResults(ctx context.Context, hrtType string) ([]LabResult, error) {
+	rows, err := q.db.QueryContext(ctx, labResults, hrtType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabResult
+	for rows.Next() {
+		var i LabResult
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DrawnAt,
+			&i.Value,
+			&i.AppointmentID,
+			&i.Note,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const labResultsBetween = `-- name: LabResultsBetween :many
+SELECT id, hrt_type, drawn_at, value, appointment_id, note, added_at FROM lab_results
+	WHERE hrt_type = ? AND drawn_at >= ? AND drawn_at < ?
+	ORDER BY drawn_at
+`
+
+type LabResultsBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) LabResultsBetween(ctx context.Context, arg LabResultsBetweenParams) ([]LabResult, error) {
+	rows, err := q.db.QueryContext(ctx, labResultsBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabResult
+	for rows.Next() {
+		var i LabResult
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DrawnAt,
+			&i.Value,
+			&i.AppointmentID,
+			&i.Note,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lastDose = `-- name: LastDose :one
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC LIMIT 1
+`
+
+func (q *Queries) LastDose(ctx context.Context, hrtType string) (HRTHistory, error) {
+	row := q.db.QueryRowContext(ctx, lastDose, hrtType)
+	var i HRTHistory
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.Notes,
+		&i.Tags,
+		&i.Zone,
+	)
+	return i, err
+}
+
+const lastDoses = `-- name: LastDoses :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC LIMIT ?
+`
+
+type LastDosesParams struct {
+	HRTType string
+	Limit   int64
+}
+
+func (q *Queries) LastDoses(ctx context.Context, arg LastDosesParams) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, lastDoses, arg.HRTType, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HRTHistory
+	for rows.Next() {
+		var i HRTHistory
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lastSkipAfter = `-- name: LastSkipAfter :one
+SELECT id, hrt_type, due_at, skipped_at, reason FROM skips WHERE hrt_type = ? AND due_at > ? ORDER BY due_at DESC LIMIT 1
+`
+
+type LastSkipAfterParams struct {
+	HRTType string
+	DueAt   time.Time
+}
+
+func (q *Queries) LastSkipAfter(ctx context.Context, arg LastSkipAfterParams) (Skip, error) {
+	row := q.db.QueryRowContext(ctx, lastSkipAfter, arg.HRTType, arg.DueAt)
+	var i Skip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DueAt,
+		&i.SkippedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const logWebhookDelivery = `-- name: LogWebhookDelivery :exec
+INSERT INTO webhook_deliveries (event_id, event_type, url, attempt, status_code, error, attempted_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type LogWebhookDeliveryParams struct {
+	EventID     string
+	EventType   string
+	Url         string
+	Attempt     int64
+	StatusCode  sql.NullInt64
+	Error       sql.NullString
+	AttemptedAt time.Time
+}
+
+func (q *Queries) LogWebhookDelivery(ctx context.Context, arg LogWebhookDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, logWebhookDelivery,
+		arg.EventID,
+		arg.EventType,
+		arg.Url,
+		arg.Attempt,
+		arg.StatusCode,
+		arg.Error,
+		arg.AttemptedAt,
+	)
+	return err
+}
+
+const markLabNotified = `-- name: MarkLabNotified :exec
+INSERT INTO lab_notified (appointment_id) VALUES (?)
+`
+
+func (q *Queries) MarkLabNotified(ctx context.Context, appointmentID int64) error {
+	_, err := q.db.ExecContext(ctx, markLabNotified, appointmentID)
+	return err
+}
+
+const markMissed = `-- name: MarkMissed :exec
+INSERT INTO missed_notified (dosage_at) VALUES (?)
+`
+
+func (q *Queries) MarkMissed(ctx context.Context, dosageAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, markMissed, dosageAt)
+	return err
+}
+
+const markNotified = `-- name: MarkNotified :exec
+INSERT INTO notified (dosage_at) VALUES (?)
+`
+
+func (q *Queries) MarkNotified(ctx context.Context, dosageAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, markNotified, dosageAt)
+	return err
+}
+
+const markOverdue = `-- name: MarkOverdue :exec
+INSERT INTO overdue_notified (dosage_at) VALUES (?)
+`
+
+func (q *Queries) MarkOverdue(ctx context.Context, dosageAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, markOverdue, dosageAt)
+	return err
+}
+
+const markPrescriptionNotified = `-- name: MarkPrescriptionNotified :exec
+INSERT INTO prescription_notified (prescription_id, reason) VALUES (?, ?)
+`
+
+type MarkPrescriptionNotifiedParams struct {
+	PrescriptionID int64
+	Reason         string
+}
+
+func (q *Queries) MarkPrescriptionNotified(ctx context.Context, arg MarkPrescriptionNotifiedParams) error {
+	_, err := q.db.ExecContext(ctx, markPrescriptionNotified, arg.PrescriptionID, arg.Reason)
+	return err
+}
+
+const markRefillNotified = `-- name: MarkRefillNotified :exec
+INSERT INTO refill_notified (stock_id) VALUES (?)
+`
+
+func (q *Queries) MarkRefillNotified(ctx context.Context, stockID int64) error {
+	_, err := q.db.ExecContext(ctx, markRefillNotified, stockID)
+	return err
+}
+
+const markRemovalNotified = `-- name: MarkRemovalNotified :exec
+INSERT INTO removal_notified (dosage_at) VALUES (?)
+`
+
+func (q *Queries) MarkRemovalNotified(ctx context.Context, dosageAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, markRemovalNotified, dosageAt)
+	return err
+}
+
+const missedNotifications = `-- name: MissedNotifications :many
+SELECT dosage_at, notified_at FROM missed_notified ORDER BY dosage_at
+`
+
+func (q *Queries) MissedNotifications(ctx context.Context) ([]MissedNotified, error) {
+	rows, err := q.db.QueryContext(ctx, missedNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MissedNotified
+	for rows.Next() {
+		var i MissedNotified
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.NotifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const notifications = `-- name: Notifications :many
+SELECT dosage_at, notified_at FROM notified ORDER BY dosage_at
+`
+
+func (q *Queries) Notifications(ctx context.Context) ([]Notified, error) {
+	rows, err := q.db.QueryContext(ctx, notifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notified
+	for rows.Next() {
+		var i Notified
+		if err := rows.Scan(&i.DosageAt, &i.NotifiedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const openLabAppointmentsBetween = `-- name: OpenLabAppointmentsBetween :many
+SELECT id, hrt_type, target, scheduled_at, note, created_at FROM lab_appointments
+	WHERE hrt_type = ? AND scheduled_at >= ? AND scheduled_at < ?
+	AND NOT EXISTS (SELECT 1 FROM lab_results WHERE lab_results.appointment_id = lab_appointments.id)
+	ORDER BY scheduled_at
+`
+
+type OpenLabAppointmentsBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) OpenLabAppointmentsBetween(ctx context.Context, arg OpenLabAppointmentsBetweenParams) ([]LabAppointment, error) {
+	rows, err := q.db.QueryContext(ctx, openLabAppointmentsBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LabAppointment
+	for rows.Next() {
+		var i LabAppointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Target,
+			&i.ScheduledAt,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const overdueNotifications = `-- name: OverdueNotifications :many
+SELECT dosage_at, notified_at FROM overdue_notified ORDER BY dosage_at
+`
+
+func (q *Queries) OverdueNotifications(ctx context.Context) ([]OverdueNotified, error) {
+	rows, err := q.db.QueryContext(ctx, overdueNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OverdueNotified
+	for rows.Next() {
+		var i OverdueNotified
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.NotifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pause = `-- name: Pause :one
+SELECT id, hrt_type, started_at, ended_at, reason, added_at FROM pauses WHERE id = ?
+`
+
+func (q *Queries) Pause(ctx context.Context, id int64) (Pause, error) {
+	row := q.db.QueryRowContext(ctx, pause, id)
+	var i Pause
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const pauses = `-- name: Pauses :many
+SELECT id, hrt_type, started_at, ended_at, reason, added_at FROM pauses WHERE hrt_type = ? ORDER BY started_at DESC, id DESC
+`
+
+func (q *Queries) Pauses(ctx context.Context, hRTType string) ([]Pause, error) {
+	rows, err := q.db.QueryContext(ctx, pauses, hRTType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Pause
+	for rows.Next() {
+		var i Pause
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Reason,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pausesBetween = `-- name: PausesBetween :many
+SELECT id, hrt_type, started_at, ended_at, reason, added_at FROM pauses
+	WHERE hrt_type = ? AND started_at < ?
+	AND (ended_at IS NULL OR ended_at > ?)
+	ORDER BY started_at
+`
+
+type PausesBetweenParams struct {
+	HRTType string
+	Before  time.Time
+	Since   sql.NullTime
+}
+
+func (q *Queries) PausesBetween(ctx context.Context, arg PausesBetweenParams) ([]Pause, error) {
+	rows, err := q.db.QueryContext(ctx, pausesBetween, arg.HRTType, arg.Before, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Pause
+	for rows.Next() {
+		var i Pause
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Reason,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const prescription = `-- name: Prescription :one
+SELECT id, hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at FROM prescriptions WHERE id = ?
+`
+
+func (q *Queries) Prescription(ctx context.Context, id int64) (Prescription, error) {
+	row := q.db.QueryRowContext(ctx, prescription, id)
+	var i Prescription
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Prescriber,
+		&i.Medication,
+		&i.Strength,
+		&i.Refills,
+		&i.WrittenAt,
+		&i.ExpiresAt,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const prescriptionFills = `-- name: PrescriptionFills :one
+SELECT COUNT(*) FROM stock WHERE prescription_id = ?
+`
+
+func (q *Queries) PrescriptionFills(ctx context.Context, prescriptionID sql.NullInt64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, prescriptionFills, prescriptionID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const prescriptionID = `-- name: PrescriptionID :one
+SELECT id FROM prescriptions
+	WHERE hrt_type = ? AND prescriber = ? AND medication = ? AND written_at = ? AND added_at = ?
+	ORDER BY id LIMIT 1
+`
+
+type PrescriptionIDParams struct {
+	HRTType    string
+	Prescriber string
+	Medication string
+	WrittenAt  time.Time
+	AddedAt    time.Time
+}
+
+func (q *Queries) PrescriptionID(ctx context.Context, arg PrescriptionIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, prescriptionID, arg.HRTType, arg.Prescriber, arg.Medication, arg.WrittenAt, arg.AddedAt)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const prescriptionNotifications = `-- name: PrescriptionNotifications :many
+SELECT prescription_id, reason, notified_at FROM prescription_notified ORDER BY prescription_id, reason
+`
+
+func (q *Queries) PrescriptionNotifications(ctx context.Context) ([]PrescriptionNotified, error) {
+	rows, err := q.db.QueryContext(ctx, prescriptionNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrescriptionNotified
+	for rows.Next() {
+		var i PrescriptionNotified
+		if err := rows.Scan(
+			&i.PrescriptionID,
+			&i.Reason,
+			&i.NotifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const prescriptions = `-- name: Prescriptions :many
+SELECT
+	prescriptions.id, prescriptions.hrt_type, prescriptions.prescriber, prescriptions.medication, prescriptions.strength, prescriptions.refills, prescriptions.written_at, prescriptions.expires_at, prescriptions.added_at,
+	(SELECT COUNT(*) FROM stock WHERE stock.prescription_id = prescriptions.id) AS fills,
+	(SELECT COUNT(DISTINCT stock_usage.dosage_at) FROM stock_usage
+		JOIN stock ON stock.id = stock_usage.stock_id
+		WHERE stock.prescription_id = prescriptions.id) AS doses
+FROM prescriptions WHERE hrt_type = ? ORDER BY written_at DESC, id DESC
+`
+
+type PrescriptionsRow struct {
+	Prescription Prescription
+	Fills        int64
+	Doses        int64
+}
+
+func (q *Queries) Prescriptions(ctx context.Context, hrtType string) ([]PrescriptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, prescriptions, hrtType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrescriptionsRow
+	for rows.Next() {
+		var i PrescriptionsRow
+		if err := rows.Scan(
+			&i.Prescription.ID,
+			&i.Prescription.HRTType,
+			&i.Prescription.Prescriber,
+			&i.Prescription.Medication,
+			&i.Prescription.Strength,
+			&i.Prescription.Refills,
+			&i.Prescription.WrittenAt,
+			&i.Prescription.ExpiresAt,
+			&i.Prescription.AddedAt,
+			&i.Fills,
+			&i.Doses,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const pruneWebhookDeliveries = `-- name: PruneWebhookDeliveries :exec
+DELETE FROM webhook_deliveries WHERE attempted_at < ?
+`
+
+func (q *Queries) PruneWebhookDeliveries(ctx context.Context, attemptedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, pruneWebhookDeliveries, attemptedAt)
+	return err
+}
+
+const recordDosage = `-- name: RecordDosage :exec
+INSERT INTO hrt_history (dosage_at, hrt_type, notes, tags, zone) VALUES (?, ?, ?, ?, ?)
+`
+
+type RecordDosageParams struct {
+	DosageAt time.Time
+	HRTType  string
+	Notes    string
+	Tags     Tags
+	Zone     string
+}
+
+func (q *Queries) RecordDosage(ctx context.Context, arg RecordDosageParams) error {
+	_, err := q.db.ExecContext(ctx, recordDosage,
+		arg.DosageAt,
+		arg.HRTType,
+		arg.Notes,
+		arg.Tags,
+		arg.Zone,
+	)
+	return err
+}
+
+const refillNotifications = `-- name: RefillNotifications :many
+SELECT stock_id, notified_at FROM refill_notified ORDER BY stock_id
+`
+
+func (q *Queries) RefillNotifications(ctx context.Context) ([]RefillNotified, error) {
+	rows, err := q.db.QueryContext(ctx, refillNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefillNotified
+	for rows.Next() {
+		var i RefillNotified
+		if err := rows.Scan(
+			&i.StockID,
+			&i.NotifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removal = `-- name: Removal :one
+SELECT dosage_at, hrt_type, removed_at, reason, added_at FROM removals WHERE dosage_at = ?
+`
+
+func (q *Queries) Removal(ctx context.Context, dosageAt time.Time) (Removal, error) {
+	row := q.db.QueryRowContext(ctx, removal, dosageAt)
+	var i Removal
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.RemovedAt,
+		&i.Reason,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const removalNotifications = `-- name: RemovalNotifications :many
+SELECT dosage_at, notified_at FROM removal_notified ORDER BY dosage_at
+`
+
+func (q *Queries) RemovalNotifications(ctx context.Context) ([]RemovalNotified, error) {
+	rows, err := q.db.QueryContext(ctx, removalNotifications)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RemovalNotified
+	for rows.Next() {
+		var i RemovalNotified
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.NotifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removalsBetween = `-- name: RemovalsBetween :many
+SELECT dosage_at, hrt_type, removed_at, reason, added_at FROM removals
+	WHERE hrt_type = ? AND dosage_at >= ? AND dosage_at < ?
+	ORDER BY dosage_at
+`
+
+type RemovalsBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) RemovalsBetween(ctx context.Context, arg RemovalsBetweenParams) ([]Removal, error) {
+	rows, err := q.db.QueryContext(ctx, removalsBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Removal
+	for rows.Next() {
+		var i Removal
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.RemovedAt,
+			&i.Reason,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setStockRemaining = `-- name: SetStockRemaining :exec
+UPDATE stock SET remaining = ? WHERE id = ?
+`
+
+type SetStockRemainingParams struct {
+	Remaining float64
+	ID        int64
+}
+
+func (q *Queries) SetStockRemaining(ctx context.Context, arg SetStockRemainingParams) error {
+	_, err := q.db.ExecContext(ctx, setStockRemaining, arg.Remaining, arg.ID)
 	return err
 }
 
-const dosageHistory = `-- name: DosageHistory :many
-SELECT dosage_at, hrt_type FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC
+const skips = `-- name: Skips :many
+SELECT id, hrt_type, due_at, skipped_at, reason FROM skips WHERE hrt_type = ? ORDER BY due_at DESC
 `
 
-func (q *Queries) DosageHistory(ctx context.Context, hrtType string) ([]HRTHistory, error) {
-	rows, err := q.db.QueryContext(ctx, dosageHistory, hrtType)
+func (q *Queries) Skips(ctx context.Context, hRTType string) ([]Skip, error) {
+	rows, err := q.db.QueryContext(ctx, skips, hRTType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skip
+	for rows.Next() {
+		var i Skip
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DueAt,
+			&i.SkippedAt,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const skipsBetween = `-- name: SkipsBetween :many
+SELECT id, hrt_type, due_at, skipped_at, reason FROM skips
+	WHERE hrt_type = ? AND due_at >= ? AND due_at < ?
+	ORDER BY due_at
+`
+
+type SkipsBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) SkipsBetween(ctx context.Context, arg SkipsBetweenParams) ([]Skip, error) {
+	rows, err := q.db.QueryContext(ctx, skipsBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Skip
+	for rows.Next() {
+		var i Skip
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DueAt,
+			&i.SkippedAt,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const snooze = `-- name: Snooze :exec
+INSERT INTO snoozed (dosage_at, snoozed_until) VALUES (?, ?)
+	ON CONFLICT (dosage_at) DO UPDATE SET snoozed_until = excluded.snoozed_until
+`
+
+type SnoozeParams struct {
+	DosageAt     time.Time
+	SnoozedUntil time.Time
+}
+
+func (q *Queries) Snooze(ctx context.Context, arg SnoozeParams) error {
+	_, err := q.db.ExecContext(ctx, snooze, arg.DosageAt, arg.SnoozedUntil)
+	return err
+}
+
+const snoozedUntil = `-- name: SnoozedUntil :one
+SELECT snoozed_until FROM snoozed WHERE dosage_at = ?
+`
+
+func (q *Queries) SnoozedUntil(ctx context.Context, dosageAt time.Time) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, snoozedUntil, dosageAt)
+	var snoozed_until time.Time
+	err := row.Scan(&snoozed_until)
+	return snoozed_until, err
+}
+
+const snoozes = `-- name: Snoozes :many
+SELECT dosage_at, snoozed_until FROM snoozed ORDER BY dosage_at
+`
+
+func (q *Queries) Snoozes(ctx context.Context) ([]Snoozed, error) {
+	rows, err := q.db.QueryContext(ctx, snoozes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Snoozed
+	for rows.Next() {
+		var i Snoozed
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.SnoozedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const stockEntries = `-- name: StockEntries :many
+SELECT id, hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id FROM stock WHERE hrt_type = ? ORDER BY added_at DESC, id DESC
+`
+
+func (q *Queries) StockEntries(ctx context.Context, hrtType string) ([]Stock, error) {
+	rows, err := q.db.QueryContext(ctx, stockEntries, hrtType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Stock
+	for rows.Next() {
+		var i Stock
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.Medication,
+			&i.Quantity,
+			&i.Remaining,
+			&i.Lot,
+			&i.ExpiresAt,
+			&i.AddedAt,
+			&i.PrescriptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const stockID = `-- name: StockID :one
+SELECT id FROM stock WHERE hrt_type = ? AND medication = ? AND lot = ? AND added_at = ?
+	ORDER BY id LIMIT 1
+`
+
+type StockIDParams struct {
+	HRTType    string
+	Medication string
+	Lot        string
+	AddedAt    time.Time
+}
+
+func (q *Queries) StockID(ctx context.Context, arg StockIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, stockID, arg.HRTType, arg.Medication, arg.Lot, arg.AddedAt)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const taggedHistoryPage = `-- name: TaggedHistoryPage :many
+SELECT dosage_at, hrt_type, notes, tags, zone FROM hrt_history
+	WHERE hrt_type = ? AND dosage_at >= ? AND dosage_at < ?
+	AND EXISTS (SELECT 1 FROM json_each(hrt_history.tags) WHERE json_each.value = ?)
+	ORDER BY dosage_at DESC LIMIT ?
+`
+
+type TaggedHistoryPageParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+	Tag     string
+	Limit   int64
+}
+
+func (q *Queries) TaggedHistoryPage(ctx context.Context, arg TaggedHistoryPageParams) ([]HRTHistory, error) {
+	rows, err := q.db.QueryContext(ctx, taggedHistoryPage,
+		arg.HRTType,
+		arg.Since,
+		arg.Before,
+		arg.Tag,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +2931,13 @@ func (q *Queries) DosageHistory(ctx context.Context, hrtType string) ([]HRTHisto
 	var items []HRTHistory
 	for rows.Next() {
 		var i HRTHistory
-		if err := rows.Scan(&i.DosageAt, &i.HRTType); err != nil {
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.Notes,
+			&i.Tags,
+			&i.Zone,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -46,31 +2951,394 @@ func (q *Queries) DosageHistory(ctx context.Context, hrtType string) ([]HRTHisto
 	return items, nil
 }
 
-const lastDose = `-- name: LastDose :one
-SELECT dosage_at, hrt_type FROM hrt_history WHERE hrt_type = ? ORDER BY dosage_at DESC LIMIT 1
+const trip = `-- name: Trip :one
+SELECT id, hrt_type, departure_zone, destination_zone, departs_at, note, added_at FROM trips WHERE id = ?
 `
 
-func (q *Queries) LastDose(ctx context.Context, hrtType string) (HRTHistory, error) {
-	row := q.db.QueryRowContext(ctx, lastDose, hrtType)
+func (q *Queries) Trip(ctx context.Context, id int64) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, trip, id)
+	var i Trip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DepartureZone,
+		&i.DestinationZone,
+		&i.DepartsAt,
+		&i.Note,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const tripBefore = `-- name: TripBefore :one
+SELECT id, hrt_type, departure_zone, destination_zone, departs_at, note, added_at FROM trips WHERE hrt_type = ? AND departs_at < ? ORDER BY departs_at DESC, id DESC LIMIT 1
+`
+
+type TripBeforeParams struct {
+	HRTType   string
+	DepartsAt time.Time
+}
+
+func (q *Queries) TripBefore(ctx context.Context, arg TripBeforeParams) (Trip, error) {
+	row := q.db.QueryRowContext(ctx, tripBefore, arg.HRTType, arg.DepartsAt)
+	var i Trip
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.DepartureZone,
+		&i.DestinationZone,
+		&i.DepartsAt,
+		&i.Note,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const trips = `-- name: Trips :many
+SELECT id, hrt_type, departure_zone, destination_zone, departs_at, note, added_at FROM trips WHERE hrt_type = ? ORDER BY departs_at DESC, id DESC
+`
+
+func (q *Queries) Trips(ctx context.Context, hRTType string) ([]Trip, error) {
+	rows, err := q.db.QueryContext(ctx, trips, hRTType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trip
+	for rows.Next() {
+		var i Trip
+		if err := rows.Scan(
+			&i.ID,
+			&i.HRTType,
+			&i.DepartureZone,
+			&i.DestinationZone,
+			&i.DepartsAt,
+			&i.Note,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unmarkMissedBefore = `-- name: UnmarkMissedBefore :exec
+DELETE FROM missed_notified WHERE dosage_at = ? AND datetime(notified_at) < datetime(?)
+`
+
+type UnmarkMissedBeforeParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) UnmarkMissedBefore(ctx context.Context, arg UnmarkMissedBeforeParams) error {
+	_, err := q.db.ExecContext(ctx, unmarkMissedBefore, arg.DosageAt, arg.NotifiedAt)
+	return err
+}
+
+const unmarkNotifiedBefore = `-- name: UnmarkNotifiedBefore :exec
+DELETE FROM notified WHERE dosage_at = ? AND datetime(notified_at) < datetime(?)
+`
+
+type UnmarkNotifiedBeforeParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) UnmarkNotifiedBefore(ctx context.Context, arg UnmarkNotifiedBeforeParams) error {
+	_, err := q.db.ExecContext(ctx, unmarkNotifiedBefore, arg.DosageAt, arg.NotifiedAt)
+	return err
+}
+
+const unmarkOverdueBefore = `-- name: UnmarkOverdueBefore :exec
+DELETE FROM overdue_notified WHERE dosage_at = ? AND datetime(notified_at) < datetime(?)
+`
+
+type UnmarkOverdueBeforeParams struct {
+	DosageAt   time.Time
+	NotifiedAt sql.NullTime
+}
+
+func (q *Queries) UnmarkOverdueBefore(ctx context.Context, arg UnmarkOverdueBeforeParams) error {
+	_, err := q.db.ExecContext(ctx, unmarkOverdueBefore, arg.DosageAt, arg.NotifiedAt)
+	return err
+}
+
+const unnotifiedRemovalsBetween = `-- name: UnnotifiedRemovalsBetween :many
+SELECT dosage_at, hrt_type, removed_at, reason, added_at FROM removals
+	WHERE hrt_type = ? AND removed_at >= ? AND removed_at < ?
+	AND NOT EXISTS (SELECT 1 FROM removal_notified WHERE removal_notified.dosage_at = removals.dosage_at)
+	ORDER BY removed_at
+`
+
+type UnnotifiedRemovalsBetweenParams struct {
+	HRTType string
+	Since   time.Time
+	Before  time.Time
+}
+
+func (q *Queries) UnnotifiedRemovalsBetween(ctx context.Context, arg UnnotifiedRemovalsBetweenParams) ([]Removal, error) {
+	rows, err := q.db.QueryContext(ctx, unnotifiedRemovalsBetween, arg.HRTType, arg.Since, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Removal
+	for rows.Next() {
+		var i Removal
+		if err := rows.Scan(
+			&i.DosageAt,
+			&i.HRTType,
+			&i.RemovedAt,
+			&i.Reason,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateDose = `-- name: UpdateDose :one
+UPDATE hrt_history SET dosage_at = ?, hrt_type = ?, notes = ?, tags = ?
+	WHERE dosage_at = ? RETURNING dosage_at, hrt_type, notes, tags, zone
+`
+
+type UpdateDoseParams struct {
+	NewDosageAt time.Time
+	NewHRTType  string
+	Notes       string
+	Tags        Tags
+	DosageAt    time.Time
+}
+
+func (q *Queries) UpdateDose(ctx context.Context, arg UpdateDoseParams) (HRTHistory, error) {
+	row := q.db.QueryRowContext(ctx, updateDose,
+		arg.NewDosageAt,
+		arg.NewHRTType,
+		arg.Notes,
+		arg.Tags,
+		arg.DosageAt,
+	)
 	var i HRTHistory
-	err := row.Scan(&i.DosageAt, &i.HRTType)
+	err := row.Scan(
+		&i.DosageAt,
+		&i.HRTType,
+		&i.Notes,
+		&i.Tags,
+		&i.Zone,
+	)
 	return i, err
 }
 
-const markNotified = `-- name: MarkNotified :exec
-INSERT INTO notified (dosage_at) VALUES (?)
+const updateJournalEntry = `-- name: UpdateJournalEntry :one
+UPDATE journal_entries SET written_at = ?, mood = ?, notes = ? WHERE id = ? RETURNING id, hrt_type, written_at, mood, notes, added_at
 `
 
-func (q *Queries) MarkNotified(ctx context.Context, dosageAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, markNotified, dosageAt)
+type UpdateJournalEntryParams struct {
+	WrittenAt time.Time
+	Mood      sql.NullInt64
+	Notes     string
+	ID        int64
+}
+
+func (q *Queries) UpdateJournalEntry(ctx context.Context, arg UpdateJournalEntryParams) (JournalEntry, error) {
+	row := q.db.QueryRowContext(ctx, updateJournalEntry,
+		arg.WrittenAt,
+		arg.Mood,
+		arg.Notes,
+		arg.ID,
+	)
+	var i JournalEntry
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.WrittenAt,
+		&i.Mood,
+		&i.Notes,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const updateLabAppointment = `-- name: UpdateLabAppointment :one
+UPDATE lab_appointments SET target = ?, scheduled_at = ?, note = ? WHERE id = ? RETURNING id, hrt_type, target, scheduled_at, note, created_at
+`
+
+type UpdateLabAppointmentParams struct {
+	Target      string
+	ScheduledAt time.Time
+	Note        string
+	ID          int64
+}
+
+func (q *Queries) UpdateLabAppointment(ctx context.Context, arg UpdateLabAppointmentParams) (LabAppointment, error) {
+	row := q.db.QueryRowContext(ctx, updateLabAppointment,
+		arg.Target,
+		arg.ScheduledAt,
+		arg.Note,
+		arg.ID,
+	)
+	var i LabAppointment
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Target,
+		&i.ScheduledAt,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updatePrescription = `-- name: UpdatePrescription :one
+UPDATE prescriptions SET prescriber = ?, medication = ?, strength = ?, refills = ?, written_at = ?, expires_at = ?
+	WHERE id = ? RETURNING id, hrt_type, prescriber, medication, strength, refills, written_at, expires_at, added_at
+`
+
+type UpdatePrescriptionParams struct {
+	Prescriber string
+	Medication string
+	Strength   string
+	Refills    int64
+	WrittenAt  time.Time
+	ExpiresAt  time.Time
+	ID         int64
+}
+
+func (q *Queries) UpdatePrescription(ctx context.Context, arg UpdatePrescriptionParams) (Prescription, error) {
+	row := q.db.QueryRowContext(ctx, updatePrescription,
+		arg.Prescriber,
+		arg.Medication,
+		arg.Strength,
+		arg.Refills,
+		arg.WrittenAt,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	var i Prescription
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Prescriber,
+		&i.Medication,
+		&i.Strength,
+		&i.Refills,
+		&i.WrittenAt,
+		&i.ExpiresAt,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const updateStock = `-- name: UpdateStock :one
+UPDATE stock SET medication = ?, quantity = ?, remaining = ?, lot = ?, expires_at = ?
+	WHERE id = ? RETURNING id, hrt_type, medication, quantity, remaining, lot, expires_at, added_at, prescription_id
+`
+
+type UpdateStockParams struct {
+	Medication string
+	Quantity   float64
+	Remaining  float64
+	Lot        string
+	ExpiresAt  sql.NullTime
+	ID         int64
+}
+
+func (q *Queries) UpdateStock(ctx context.Context, arg UpdateStockParams) (Stock, error) {
+	row := q.db.QueryRowContext(ctx, updateStock,
+		arg.Medication,
+		arg.Quantity,
+		arg.Remaining,
+		arg.Lot,
+		arg.ExpiresAt,
+		arg.ID,
+	)
+	var i Stock
+	err := row.Scan(
+		&i.ID,
+		&i.HRTType,
+		&i.Medication,
+		&i.Quantity,
+		&i.Remaining,
+		&i.Lot,
+		&i.ExpiresAt,
+		&i.AddedAt,
+		&i.PrescriptionID,
+	)
+	return i, err
+}
+
+const useRefill = `-- name: UseRefill :exec
+UPDATE prescriptions SET refills = refills - 1 WHERE id = ?
+`
+
+func (q *Queries) UseRefill(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, useRefill, id)
 	return err
 }
 
-const recordDosage = `-- name: RecordDosage :exec
-INSERT INTO hrt_history (hrt_type) VALUES (?)
+const useStock = `-- name: UseStock :exec
+INSERT INTO stock_usage (dosage_at, stock_id, amount) VALUES (?, ?, ?)
 `
 
-func (q *Queries) RecordDosage(ctx context.Context, hrtType string) error {
-	_, err := q.db.ExecContext(ctx, recordDosage, hrtType)
+type UseStockParams struct {
+	DosageAt time.Time
+	StockID  int64
+	Amount   float64
+}
+
+func (q *Queries) UseStock(ctx context.Context, arg UseStockParams) error {
+	_, err := q.db.ExecContext(ctx, useStock, arg.DosageAt, arg.StockID, arg.Amount)
 	return err
 }
+
+const webhookDeliveries = `-- name: WebhookDeliveries :many
+SELECT id, event_id, event_type, url, attempt, status_code, error, attempted_at FROM webhook_deliveries ORDER BY attempted_at DESC, id DESC LIMIT ?
+`
+
+func (q *Queries) WebhookDeliveries(ctx context.Context, limit int64) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, webhookDeliveries, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.Url,
+			&i.Attempt,
+			&i.StatusCode,
+			&i.Error,
+			&i.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,3 +11,340 @@ CREATE TABLE notified (
 	dosage_at TIMESTAMP PRIMARY KEY,
 	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Timestamps used to be a mix of CURRENT_TIMESTAMP and Go's time.Time.String
+-- formats. Normalize them to the format written by the driver's sqlite time
+-- format so that they can be compared for equality.
+UPDATE hrt_history SET dosage_at = substr(dosage_at, 1, 19) || '+00:00';
+UPDATE notified SET dosage_at = substr(dosage_at, 1, 19) || '+00:00';
+
+--------------------------------- NEW VERSION ---------------------------------
+
+CREATE TABLE snoozed (
+	dosage_at TIMESTAMP PRIMARY KEY,
+	snoozed_until TIMESTAMP NOT NULL
+);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+CREATE TABLE webhook_deliveries (
+	id INTEGER PRIMARY KEY,
+	event_id TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	url TEXT NOT NULL,
+	attempt INTEGER NOT NULL,
+	-- status_code is NULL if no response was received.
+	status_code INTEGER,
+	-- error is NULL if the delivery succeeded.
+	error TEXT,
+	attempted_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX webhook_deliveries_attempted_at ON webhook_deliveries(attempted_at);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+CREATE TABLE overdue_notified (
+	dosage_at TIMESTAMP PRIMARY KEY,
+	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- Queries filter by type and then by time. The old index on just the type is
+-- covered by the prefix of the new one.
+CREATE INDEX hrt_history_hrt_type_dosage_at ON hrt_history(hrt_type, dosage_at);
+DROP INDEX hrt_history_hrt_type;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+CREATE TABLE stock (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	medication TEXT NOT NULL,
+	-- quantity is the number of units, such as patches, that were added.
+	quantity REAL NOT NULL,
+	-- remaining is the number of units left. Recording a dose takes from it.
+	remaining REAL NOT NULL,
+	lot TEXT NOT NULL DEFAULT '',
+	-- expires_at is the start of the expiry date, and the stock can be used
+	-- until the end of it. It is NULL if the expiry date is not known.
+	expires_at TIMESTAMP,
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX stock_hrt_type ON stock(hrt_type);
+
+-- stock_usage records how much of each stock entry a dose took, so that it can
+-- be put back if the dose is deleted.
+CREATE TABLE stock_usage (
+	dosage_at TIMESTAMP NOT NULL,
+	stock_id INTEGER NOT NULL,
+	amount REAL NOT NULL,
+	PRIMARY KEY (dosage_at, stock_id)
+);
+
+-- refill_notified records that the refill reminder was sent while the stock
+-- entry was the latest one added. Adding stock allows it to be sent again.
+CREATE TABLE refill_notified (
+	stock_id INTEGER PRIMARY KEY,
+	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Triggers are used instead of foreign keys, since those are only enforced on
+-- connections that turned them on.
+
+CREATE TRIGGER stock_usage_take AFTER INSERT ON stock_usage
+BEGIN
+	UPDATE stock SET remaining = remaining - new.amount WHERE id = new.stock_id;
+END;
+
+CREATE TRIGGER stock_usage_restore AFTER DELETE ON stock_usage
+BEGIN
+	UPDATE stock SET remaining = remaining + old.amount WHERE id = old.stock_id;
+END;
+
+CREATE TRIGGER hrt_history_delete_stock_usage AFTER DELETE ON hrt_history
+BEGIN
+	DELETE FROM stock_usage WHERE dosage_at = old.dosage_at;
+END;
+
+CREATE TRIGGER hrt_history_move_stock_usage AFTER UPDATE OF dosage_at ON hrt_history
+BEGIN
+	UPDATE stock_usage SET dosage_at = new.dosage_at WHERE dosage_at = old.dosage_at;
+END;
+
+CREATE TRIGGER stock_delete_usage AFTER DELETE ON stock
+BEGIN
+	DELETE FROM stock_usage WHERE stock_id = old.id;
+	DELETE FROM refill_notified WHERE stock_id = old.id;
+END;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+CREATE TABLE prescriptions (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	prescriber TEXT NOT NULL,
+	medication TEXT NOT NULL,
+	-- strength is free text, such as "100 mcg/day".
+	strength TEXT NOT NULL DEFAULT '',
+	-- refills is the number of refills left after the first fill.
+	refills INTEGER NOT NULL,
+	written_at TIMESTAMP NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX prescriptions_hrt_type ON prescriptions(hrt_type);
+
+-- prescription_id is the prescription the stock was filled from, or NULL.
+ALTER TABLE stock ADD COLUMN prescription_id INTEGER;
+
+CREATE INDEX stock_prescription_id ON stock(prescription_id);
+
+-- prescription_notified records the renewal reminders sent for a prescription.
+-- reason is why it needs renewing, such as "expiring".
+CREATE TABLE prescription_notified (
+	prescription_id INTEGER NOT NULL,
+	reason TEXT NOT NULL,
+	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (prescription_id, reason)
+);
+
+CREATE TRIGGER prescriptions_delete AFTER DELETE ON prescriptions
+BEGIN
+	UPDATE stock SET prescription_id = NULL WHERE prescription_id = old.id;
+	DELETE FROM prescription_notified WHERE prescription_id = old.id;
+END;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- lab_appointments are scheduled blood draws. target is the point of the dose
+-- cycle the draw is meant to catch, such as "trough".
+CREATE TABLE lab_appointments (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	target TEXT NOT NULL,
+	scheduled_at TIMESTAMP NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX lab_appointments_hrt_type_scheduled_at ON lab_appointments(hrt_type, scheduled_at);
+
+-- lab_results are measured levels in pg/mL. appointment_id is the appointment
+-- the blood was drawn at, or NULL.
+CREATE TABLE lab_results (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	drawn_at TIMESTAMP NOT NULL,
+	value REAL NOT NULL,
+	appointment_id INTEGER,
+	note TEXT NOT NULL DEFAULT '',
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX lab_results_hrt_type_drawn_at ON lab_results(hrt_type, drawn_at);
+CREATE UNIQUE INDEX lab_results_appointment_id ON lab_results(appointment_id);
+
+CREATE TABLE lab_notified (
+	appointment_id INTEGER PRIMARY KEY,
+	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER lab_appointments_delete AFTER DELETE ON lab_appointments
+BEGIN
+	UPDATE lab_results SET appointment_id = NULL WHERE appointment_id = old.id;
+	DELETE FROM lab_notified WHERE appointment_id = old.id;
+END;
+
+-- Remind again of rescheduled appointments.
+CREATE TRIGGER lab_appointments_reschedule AFTER UPDATE OF scheduled_at ON lab_appointments
+BEGIN
+	DELETE FROM lab_notified WHERE appointment_id = old.id;
+END;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- journal_entries are notes on how the user felt at written_at. mood is from 1
+-- (worst) to 5 (best), or NULL if it wasn't given.
+CREATE TABLE journal_entries (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	written_at TIMESTAMP NOT NULL,
+	mood INTEGER,
+	notes TEXT NOT NULL DEFAULT '',
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX journal_entries_hrt_type_written_at ON journal_entries(hrt_type, written_at);
+
+-- journal_tags are the symptoms of an entry, such as "hot flashes".
+CREATE TABLE journal_tags (
+	entry_id INTEGER NOT NULL,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (entry_id, tag)
+);
+
+CREATE INDEX journal_tags_tag ON journal_tags(tag);
+
+CREATE TRIGGER journal_entries_delete AFTER DELETE ON journal_entries
+BEGIN
+	DELETE FROM journal_tags WHERE entry_id = old.id;
+END;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- notes are free-form notes on the dose. tags are a JSON array of tags such as
+-- "removed-early", normalized and sorted.
+ALTER TABLE hrt_history ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE hrt_history ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- removals are applications, such as patches, that were taken off at
+-- removed_at, such as because they fell off. dosage_at is the dose of the
+-- application, and hrt_type is copied from it. reason is free text.
+CREATE TABLE removals (
+	dosage_at TIMESTAMP PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	removed_at TIMESTAMP NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX removals_hrt_type_removed_at ON removals(hrt_type, removed_at);
+
+CREATE TABLE removal_notified (
+	dosage_at TIMESTAMP PRIMARY KEY,
+	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER hrt_history_delete_removal AFTER DELETE ON hrt_history
+BEGIN
+	DELETE FROM removals WHERE dosage_at = old.dosage_at;
+END;
+
+CREATE TRIGGER hrt_history_move_removal AFTER UPDATE OF dosage_at, hrt_type ON hrt_history
+BEGIN
+	UPDATE removals SET dosage_at = new.dosage_at, hrt_type = new.hrt_type
+		WHERE dosage_at = old.dosage_at;
+END;
+
+CREATE TRIGGER removals_delete AFTER DELETE ON removals
+BEGIN
+	DELETE FROM removal_notified WHERE dosage_at = old.dosage_at;
+END;
+
+-- Remind again of removals that are moved.
+CREATE TRIGGER removals_move AFTER UPDATE ON removals
+BEGIN
+	DELETE FROM removal_notified WHERE dosage_at = old.dosage_at;
+END;
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- pauses are spans of time during which the regimen of hrt_type is paused,
+-- such as before surgery or during a planned break. ended_at is NULL while the
+-- pause has no end, otherwise it is when the pause ended or is planned to end.
+-- reason is free text.
+CREATE TABLE pauses (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	started_at TIMESTAMP NOT NULL,
+	ended_at TIMESTAMP,
+	reason TEXT NOT NULL DEFAULT '',
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX pauses_hrt_type_started_at ON pauses(hrt_type, started_at);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- trips are travels from departure_zone to destination_zone, both IANA time
+-- zone names, departing at departs_at. The due times of the doses of hrt_type
+-- after departs_at shift gradually to the same time of day at the
+-- destination. note is free text.
+CREATE TABLE trips (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	departure_zone TEXT NOT NULL,
+	destination_zone TEXT NOT NULL,
+	departs_at TIMESTAMP NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	added_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX trips_hrt_type_departs_at ON trips(hrt_type, departs_at);
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- zone is the IANA name of the time zone a dose was recorded in, or empty if
+-- it is not known. dosage_at is in UTC either way.
+ALTER TABLE hrt_history ADD COLUMN zone TEXT NOT NULL DEFAULT '';
+
+--------------------------------- NEW VERSION ---------------------------------
+
+-- skips are doses of hrt_type that were deliberately not taken, such as
+-- following the missed dose policy of the regimen. due_at is when the skipped
+-- dose was due, and the dose after it is due as if it had been taken then.
+-- reason is free text.
+CREATE TABLE skips (
+	id INTEGER PRIMARY KEY,
+	hrt_type TEXT NOT NULL,
+	due_at TIMESTAMP NOT NULL,
+	skipped_at TIMESTAMP NOT NULL,
+	reason TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX skips_hrt_type_due_at ON skips(hrt_type, due_at);
+
+-- missed_notified records that the missed dose reminder for the dose after the
+-- one at dosage_at was sent.
+CREATE TABLE missed_notified (
+	dosage_at TIMESTAMP PRIMARY KEY,
+	notified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"libdb.so/lazymigrate"
 	_ "modernc.org/sqlite"
@@ -23,6 +24,9 @@ PRAGMA journal_mode = WAL;
 PRAGMA strict = ON;
 `
 
+// EndOfTime is later than any dose. It ends ranges that are open at the end.
+var EndOfTime = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
 // SQLiteDB provides methods for interacting with the database.
 // For now, it just wraps around sqlc's Queries because I'm lazy.
 type SQLiteDB struct {
@@ -32,13 +36,24 @@ type SQLiteDB struct {
 
 // Open creates a new database at the given path.
 func Open(path string) (*SQLiteDB, error) {
-	db, err := sql.Open("sqlite", path)
+	// Always write timestamps in the same format so that they can be compared
+	// within SQL. Callers must still make sure to only ever pass UTC times.
+	db, err := sql.Open("sqlite", withTimeFormat(path))
 	if err != nil {
 		return nil, err
 	}
 	return newDatabase(db)
 }
 
+// withTimeFormat adds the time format parameter to the query string of the
+// given path, which may already have one, such as "file:hrt.db?mode=ro".
+func withTimeFormat(path string) string {
+	if strings.Contains(path, "?") {
+		return path + "&_time_format=sqlite"
+	}
+	return path + "?_time_format=sqlite"
+}
+
 func newDatabase(db *sql.DB) (*SQLiteDB, error) {
 	if _, err := db.Exec(pragma); err != nil {
 		return nil, err
@@ -75,6 +90,82 @@ func (db *SQLiteDB) Tx(f func(*Queries) error) error {
 	return tx.Commit()
 }
 
+// DosesBetween returns the doses of the given type taken from since until
+// before, oldest first. The last dose taken before since is included as well if
+// there is one, since the first dose in the range is compared against it.
+func (db *SQLiteDB) DosesBetween(ctx context.Context, hrtType string, since, before time.Time) ([]HRTHistory, error) {
+	var doses []HRTHistory
+
+	prev, err := db.DoseBefore(ctx, DoseBeforeParams{
+		HRTType:  hrtType,
+		DosageAt: since.UTC(),
+	})
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		doses = append(doses, prev)
+	}
+
+	between, err := db.DosageHistoryBetween(ctx, DosageHistoryBetweenParams{
+		HRTType: hrtType,
+		Since:   since.UTC(),
+		Before:  before.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(doses, between...), nil
+}
+
+// Changes returns a channel that receives a value every time the database is
+// changed by another connection, including ones from other processes. The
+// database is polled every interval until ctx is canceled, after which the
+// channel is closed.
+func (db *SQLiteDB) Changes(ctx context.Context, interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	go func() {
+		defer close(ch)
+
+		// data_version only changes for commits made by other connections, so
+		// we must hold onto the same connection.
+		conn, err := db.db.Conn(ctx)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := int64(-1)
+		for {
+			var version int64
+			if err := conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version); err != nil {
+				return
+			}
+
+			if last != -1 && version != last {
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+			last = version
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ch
+}
+
 func IsNotFound(err error) bool {
 	return errors.Is(err, sql.ErrNoRows)
 }
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWithTimeFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"hrtclicker.db", "hrtclicker.db?_time_format=sqlite"},
+		{":memory:", ":memory:?_time_format=sqlite"},
+		{"file:hrt.db?mode=ro", "file:hrt.db?mode=ro&_time_format=sqlite"},
+		{"file:hrt.db?cache=shared&mode=rwc", "file:hrt.db?cache=shared&mode=rwc&_time_format=sqlite"},
+	}
+
+	for _, test := range tests {
+		if got := withTimeFormat(test.path); got != test.want {
+			t.Errorf("withTimeFormat(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestOpenWithQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hrt.db")
+
+	database, err := Open("file:" + path + "?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	at := time.Date(2026, 3, 29, 1, 30, 0, 0, time.UTC)
+	if err := database.RecordDosage(context.Background(), RecordDosageParams{
+		DosageAt: at,
+		HRTType:  "patches",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dose, err := database.LastDose(context.Background(), "patches")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dose.DosageAt.Equal(at) {
+		t.Errorf("LastDose().DosageAt = %v, want %v", dose.DosageAt, at)
+	}
+}
+
+func TestDosesBetween(t *testing.T) {
+	ctx := context.Background()
+
+	database, err := Open(filepath.Join(t.TempDir(), "hrt.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	day := func(d int) time.Time {
+		return time.Date(2026, 3, d, 8, 0, 0, 0, time.UTC)
+	}
+	for _, dose := range []struct {
+		at      time.Time
+		hrtType string
+	}{
+		{day(1), "patches"},
+		{day(2), "gel"},
+		{day(4), "patches"},
+		{day(7), "patches"},
+		{day(10), "patches"},
+	} {
+		if err := database.RecordDosage(ctx, RecordDosageParams{
+			DosageAt: dose.at,
+			HRTType:  dose.hrtType,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		since, before time.Time
+		want          []time.Time
+	}{
+		{"with the dose before", day(5), day(10), []time.Time{day(4), day(7)}},
+		{"from the first dose", day(1), day(5), []time.Time{day(1), day(4)}},
+		{"open ended", day(8), EndOfTime, []time.Time{day(7), day(10)}},
+		{"nothing in the range", day(8), day(9), []time.Time{day(7)}},
+		{"before all doses", day(1).AddDate(0, -1, 0), day(1), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doses, err := database.DosesBetween(ctx, "patches", test.since, test.before)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []time.Time
+			for _, dose := range doses {
+				got = append(got, dose.DosageAt)
+			}
+			if !slices.EqualFunc(got, test.want, time.Time.Equal) {
+				t.Errorf("DosesBetween(%s, %s) = %v, want %v", test.since, test.before, got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnmarkBefore(t *testing.T) {
+	type markFunc func(*SQLiteDB, context.Context, time.Time) error
+	type unmarkFunc func(context.Context, time.Time, time.Time) error
+
+	tests := []struct {
+		name   string
+		mark   markFunc
+		unmark func(*SQLiteDB) unmarkFunc
+	}{
+		{
+			name: "notified",
+			mark: (*SQLiteDB).MarkNotified,
+			unmark: func(d *SQLiteDB) unmarkFunc {
+				return func(ctx context.Context, dosageAt, before time.Time) error {
+					return d.UnmarkNotifiedBefore(ctx, UnmarkNotifiedBeforeParams{
+						DosageAt:   dosageAt,
+						NotifiedAt: sql.NullTime{Time: before, Valid: true},
+					})
+				}
+			},
+		},
+		{
+			name: "overdue",
+			mark: (*SQLiteDB).MarkOverdue,
+			unmark: func(d *SQLiteDB) unmarkFunc {
+				return func(ctx context.Context, dosageAt, before time.Time) error {
+					return d.UnmarkOverdueBefore(ctx, UnmarkOverdueBeforeParams{
+						DosageAt:   dosageAt,
+						NotifiedAt: sql.NullTime{Time: before, Valid: true},
+					})
+				}
+			},
+		},
+		{
+			name: "missed",
+			mark: (*SQLiteDB).MarkMissed,
+			unmark: func(d *SQLiteDB) unmarkFunc {
+				return func(ctx context.Context, dosageAt, before time.Time) error {
+					return d.UnmarkMissedBefore(ctx, UnmarkMissedBeforeParams{
+						DosageAt:   dosageAt,
+						NotifiedAt: sql.NullTime{Time: before, Valid: true},
+					})
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			database, err := Open(filepath.Join(t.TempDir(), "hrt.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer database.Close()
+
+			dosageAt := time.Date(2026, 3, 29, 1, 30, 0, 0, time.UTC)
+			unmark := test.unmark(database)
+
+			// The mark is stored at the same second or after, so it isn't
+			// before this.
+			sameSecond := time.Now().UTC().Truncate(time.Second)
+			if err := test.mark(database, ctx, dosageAt); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, before := range []time.Time{sameSecond, sameSecond.Add(-time.Hour)} {
+				if err := unmark(ctx, dosageAt, before); err != nil {
+					t.Fatal(err)
+				}
+				if err := test.mark(database, ctx, dosageAt); err == nil || !IsAlreadyExists(err) {
+					t.Fatalf("mark after unmarking before %v: got %v, want it to still be marked", before, err)
+				}
+			}
+
+			if err := unmark(ctx, dosageAt, time.Now().UTC().Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			if err := test.mark(database, ctx, dosageAt); err != nil {
+				t.Fatalf("mark after unmarking before an hour from now: %v", err)
+			}
+		})
+	}
+}
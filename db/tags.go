@@ -0,0 +1,85 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Tags is a set of tags, such as "removed-early". It is stored as a JSON
+// array. Tags made with NewTags or ParseTags are normalized, sorted and
+// unique.
+type Tags []string
+
+// NormalizeTag lowercases the tag and collapses its whitespace, so that "Hot
+// Flashes" and "hot  flashes" are the same tag.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// NewTags returns the given tags normalized, sorted and deduplicated, with
+// empty tags dropped. It never returns nil, so that no tags are an empty list
+// in JSON.
+func NewTags(tags ...string) Tags {
+	normalized := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// ParseTags parses a comma-separated list of tags.
+func ParseTags(s string) Tags {
+	return NewTags(strings.Split(s, ",")...)
+}
+
+// Has returns true if the set has the given tag.
+func (t Tags) Has(tag string) bool {
+	return slices.Contains(t, NormalizeTag(tag))
+}
+
+// String returns the tags as a comma-separated list that ParseTags parses
+// back.
+func (t Tags) String() string {
+	return strings.Join(t, ", ")
+}
+
+// Value implements driver.Valuer.
+func (t Tags) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(t))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (t *Tags) Scan(src any) error {
+	var b []byte
+	switch src := src.(type) {
+	case nil:
+		*t = Tags{}
+		return nil
+	case string:
+		b = []byte(src)
+	case []byte:
+		b = src
+	default:
+		return fmt.Errorf("cannot scan %T into Tags", src)
+	}
+
+	var tags []string
+	if err := json.Unmarshal(b, &tags); err != nil {
+		return fmt.Errorf("invalid tags: %w", err)
+	}
+	*t = NewTags(tags...)
+	return nil
+}
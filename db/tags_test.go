@@ -0,0 +1,77 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Tags
+	}{
+		{"", Tags{}},
+		{"fatigue", Tags{"fatigue"}},
+		{"Hot  Flashes, fatigue,, hot flashes ", Tags{"fatigue", "hot flashes"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			if got := ParseTags(test.in); !slices.Equal(got, test.want) {
+				t.Errorf("ParseTags(%q) = %q, want %q", test.in, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTagsScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Tags
+		wantErr bool
+	}{
+		{"null", nil, Tags{}, false},
+		{"empty", "[]", Tags{}, false},
+		{"string", `["nausea","Fatigue"]`, Tags{"fatigue", "nausea"}, false},
+		{"bytes", []byte(`["fatigue"]`), Tags{"fatigue"}, false},
+		{"invalid", "fatigue", nil, true},
+		{"other type", 1, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got Tags
+			err := got.Scan(test.src)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Scan(%v) = %v, want an error: %v", test.src, err, test.wantErr)
+			}
+			if !test.wantErr && (got == nil || !slices.Equal(got, test.want)) {
+				t.Errorf("Scan(%v) = %#v, want %#v", test.src, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTagsValue(t *testing.T) {
+	tests := []struct {
+		name string
+		tags Tags
+		want string
+	}{
+		{"nil", nil, "[]"},
+		{"tags", NewTags("nausea", "fatigue"), `["fatigue","nausea"]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.tags.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("Value() = %v, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package events provides an in-process event bus for things happening in the
+// application, such as doses being recorded or notifications being sent.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Type is the type of an event.
+type Type string
+
+const (
+	// DoseRecorded is published when a dose is recorded. Its data is a
+	// db.HRTHistory.
+	DoseRecorded Type = "dose-recorded"
+	// DoseDeleted is published when a dose is deleted. Its data is a
+	// db.HRTHistory.
+	DoseDeleted Type = "dose-deleted"
+	// DoseUpdated is published when the time, type, notes or tags of a
+	// recorded dose are changed. Its data is a server.DoseUpdate.
+	DoseUpdated Type = "dose-updated"
+	// DoseRemoved is published when an application, such as a patch, is
+	// recorded as taken off. Its data is a db.Removal.
+	DoseRemoved Type = "dose-removed"
+	// NotificationSent is published when a reminder is sent. Its data is a
+	// notify.NotificationSentData.
+	NotificationSent Type = "notification-sent"
+	// ReminderDue is published when the next dose is due and the reminder
+	// should be sent, regardless of whether sending it succeeds. Its data is
+	// a hrtclicker.NotificationTemplateData.
+	ReminderDue Type = "reminder-due"
+	// DoseOverdue is published once the next dose is overdue by the
+	// regimen's overdue_after duration. Its data is a
+	// hrtclicker.NotificationTemplateData.
+	DoseOverdue Type = "dose-overdue"
+	// DoseMissed is published once the next dose is missed, which is after
+	// the regimen's late_window, or once it should be skipped following its
+	// missed_dose policy. Its data is a missed.Advice.
+	DoseMissed Type = "dose-missed"
+	// DoseSkipped is published when a dose is skipped on purpose. Its data is
+	// a db.Skip.
+	DoseSkipped Type = "dose-skipped"
+	// Snoozed is published when a reminder is snoozed. Its data is a
+	// db.Snoozed.
+	Snoozed Type = "snoozed"
+	// RefillDue is published once the inventory of a regimen runs out within
+	// its refill_days, regardless of whether sending the reminder succeeds.
+	// It is published again only after more stock is added. Its data is an
+	// inventory.Status.
+	RefillDue Type = "refill-due"
+	// PrescriptionRenewalDue is published once for each reason the current
+	// prescription of a regimen needs renewing: when it expires within its
+	// renew_days, and when it has no refills left. Its data is an
+	// inventory.Renewal.
+	PrescriptionRenewalDue Type = "prescription-renewal-due"
+	// LabReminderDue is published once for every lab appointment without a
+	// result when it is within its regimen's lab_reminder. It is published
+	// again if the appointment is rescheduled. Its data is a labs.Reminder.
+	LabReminderDue Type = "lab-reminder-due"
+	// ReplacementDue is published once for every removal that no new dose
+	// was taken after within its regimen's removal_reminder, regardless of
+	// whether sending the reminder succeeds. Its data is a db.Removal.
+	ReplacementDue Type = "replacement-due"
+	// RegimenPaused is published when a pause of a regimen is added. Its
+	// data is a db.Pause.
+	RegimenPaused Type = "regimen-paused"
+	// RegimenResumed is published when a paused regimen is resumed before the
+	// end of its pause. Its data is the ended db.Pause.
+	RegimenResumed Type = "regimen-resumed"
+	// ConfigReloaded is published when the configuration is reloaded. Its data
+	// is the new hrtclicker.HRTConfig.
+	ConfigReloaded Type = "config-reloaded"
+)
+
+// Types contains all event types.
+var Types = []Type{
+	DoseRecorded,
+	DoseDeleted,
+	DoseUpdated,
+	DoseRemoved,
+	NotificationSent,
+	ReminderDue,
+	DoseOverdue,
+	DoseMissed,
+	DoseSkipped,
+	Snoozed,
+	RefillDue,
+	PrescriptionRenewalDue,
+	LabReminderDue,
+	ReplacementDue,
+	RegimenPaused,
+	RegimenResumed,
+	ConfigReloaded,
+}
+
+// IsValid returns true if the event type is one of Types.
+func (t Type) IsValid() bool {
+	return slices.Contains(Types, t)
+}
+
+// Event is a single event published on a Bus.
+type Event struct {
+	// ID is a random ID unique to the event.
+	ID   string
+	Type Type
+	Time time.Time
+	Data any
+}
+
+// Bus is an in-process publish-subscribe event bus. The zero value is ready
+// to use, and publishing to a nil Bus does nothing.
+type Bus struct {
+	// Logger logs the events dropped for subscribers that fell behind. It
+	// defaults to slog.Default.
+	Logger *slog.Logger
+
+	mu   sync.Mutex
+	subs map[chan Event]*queue
+}
+
+// subscriberBuffer is the number of events buffered for each subscriber.
+// Events are dropped for subscribers of Subscribe that fall behind by more
+// than this.
+const subscriberBuffer = 16
+
+// Publish publishes an event to all subscribers. It never blocks.
+func (b *Bus) Publish(t Type, data any) {
+	if b == nil {
+		return
+	}
+
+	ev := Event{
+		ID:   newID(),
+		Type: t,
+		Time: time.Now(),
+		Data: data,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch, q := range b.subs {
+		if q != nil {
+			q.push(ev)
+			continue
+		}
+
+		select {
+		case ch <- ev:
+		default:
+			b.logger().Warn(
+				"dropped event for subscriber that fell behind",
+				"event_type", ev.Type,
+				"event_id", ev.ID)
+		}
+	}
+}
+
+func (b *Bus) logger() *slog.Logger {
+	if b.Logger != nil {
+		return b.Logger
+	}
+	return slog.Default()
+}
+
+// Subscribe subscribes to all events published after this call. Events are
+// dropped if the subscriber falls behind, so it suits subscribers that only
+// show the latest state, such as browsers. The returned channel is closed once
+// ctx is canceled.
+func (b *Bus) Subscribe(ctx context.Context) <-chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	b.subscribe(ctx, ch, nil)
+	return ch
+}
+
+// SubscribeAll is like Subscribe, but never drops events. They are queued for
+// as long as the subscriber falls behind, so it suits subscribers that must
+// act on every event, such as webhooks. The events still queued when ctx is
+// canceled are dropped.
+func (b *Bus) SubscribeAll(ctx context.Context) <-chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	q := &queue{wake: make(chan struct{}, 1)}
+	b.subscribe(ctx, ch, q)
+	go q.forward(ctx, ch)
+	return ch
+}
+
+func (b *Bus) subscribe(ctx context.Context, ch chan Event, q *queue) {
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[chan Event]*queue)
+	}
+	b.subs[ch] = q
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subs, ch)
+		if q == nil {
+			// The queue closes the channels of SubscribeAll once it stops
+			// forwarding to them.
+			close(ch)
+		}
+		b.mu.Unlock()
+	}()
+}
+
+// queue holds the events published to a subscriber of SubscribeAll that it
+// hasn't received yet.
+type queue struct {
+	mu     sync.Mutex
+	events []Event
+	wake   chan struct{}
+}
+
+func (q *queue) push(ev Event) {
+	q.mu.Lock()
+	q.events = append(q.events, ev)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// forward sends the queued events to ch in order until ctx is canceled, then
+// closes ch.
+func (q *queue) forward(ctx context.Context, ch chan<- Event) {
+	defer close(ch)
+
+	for {
+		q.mu.Lock()
+		evs := q.events
+		q.events = nil
+		q.mu.Unlock()
+
+		for _, ev := range evs {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- ev:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+	}
+}
+
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
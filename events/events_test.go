@@ -0,0 +1,98 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPublish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var bus Bus
+	ch := bus.Subscribe(ctx)
+
+	// The events past the buffer are dropped for a subscriber that isn't
+	// receiving them.
+	const n = subscriberBuffer * 2
+	for i := range n {
+		bus.Publish(DoseRecorded, i)
+	}
+
+	if got := len(ch); got != subscriberBuffer {
+		t.Fatalf("Subscribe buffered %d events, want %d", got, subscriberBuffer)
+	}
+	for i := range subscriberBuffer {
+		ev := <-ch
+		if ev.Type != DoseRecorded || ev.Data != i {
+			t.Errorf("event %d = %s with data %v", i, ev.Type, ev.Data)
+		}
+	}
+}
+
+func TestPublishNilBus(t *testing.T) {
+	var bus *Bus
+	bus.Publish(DoseRecorded, nil)
+}
+
+func TestSubscribeAllKeepsEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var bus Bus
+	all := bus.SubscribeAll(ctx)
+	latest := bus.Subscribe(ctx)
+
+	const n = subscriberBuffer * 4
+	for i := range n {
+		bus.Publish(DoseRecorded, i)
+	}
+
+	for i := range n {
+		select {
+		case ev := <-all:
+			if ev.Data != i {
+				t.Fatalf("event %d has data %v", i, ev.Data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+
+	if got := len(latest); got != subscriberBuffer {
+		t.Errorf("Subscribe buffered %d events, want %d", got, subscriberBuffer)
+	}
+}
+
+func TestSubscribeClosesOnCancel(t *testing.T) {
+	tests := []struct {
+		name      string
+		subscribe func(*Bus, context.Context) <-chan Event
+	}{
+		{"Subscribe", (*Bus).Subscribe},
+		{"SubscribeAll", (*Bus).SubscribeAll},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			var bus Bus
+			ch := test.subscribe(&bus, ctx)
+			cancel()
+
+			timeout := time.After(5 * time.Second)
+			for {
+				select {
+				case _, ok := <-ch:
+					if !ok {
+						return
+					}
+				case <-timeout:
+					t.Fatal("channel not closed after cancel")
+				}
+			}
+		})
+	}
+}
@@ -33,7 +33,7 @@
 					subPackages = [ "cmd/hrt-clicker" ];
 					src = self;
 
-					vendorHash = "sha256-B50gCBfISKDXYYR2Q9RMSeaPtM9c38PJNjFD9EWV0qQ=";
+					vendorHash = "sha256-xhhaspvlSU2tYnUhsw8MDV6Nye4oibMB+MhItbq9LNg=";
 
 					meta = with pkgs.stdenv.lib; {
 						description = "A simple HTTP request tester";
@@ -49,6 +49,14 @@
 						inherit pkgs system;
 					};
 				in {
+					# The package only builds and tests the command, so run the
+					# tests of every package, such as the check that the web
+					# assets load nothing from other origins.
+					go-test = self.packages.${system}.default.overrideAttrs (old: {
+						pname = "hrtclicker-tests";
+						subPackages = [ ];
+					});
+
 					default = makeTest {
 						name = "hrtclicker";
 						nodes.server = { ... }: {
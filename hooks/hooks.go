@@ -0,0 +1,271 @@
+// Package hooks runs the command hooks in the configuration for events from the
+// event bus.
+//
+// Each command is run directly without a shell. The events.Event is written to
+// its stdin as JSON, and the event is also described in environment variables:
+//
+//   - HRTCLICKER_EVENT is the event type, such as "dose-recorded".
+//   - HRTCLICKER_EVENT_ID and HRTCLICKER_EVENT_TIME are the event's ID and time.
+//   - Every top-level string, number or boolean field of the event data is
+//     set as HRTCLICKER_<FIELD>, so the DosageAt field of a "dose-recorded"
+//     event becomes HRTCLICKER_DOSAGE_AT.
+//   - HRTCLICKER_LAST_DOSE_AT and HRTCLICKER_NEXT_DOSE_AT are set from the
+//     database if the event data doesn't have them and a dose was recorded.
+//     The next dose follows pauses, trips and skipped doses, the same as the
+//     reminders.
+//
+// Hooks are run for every event, however far behind they fall.
+//
+// Times are formatted as RFC 3339. Anything the command writes to stderr is
+// logged.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/schedule"
+)
+
+const (
+	// envPrefix is the prefix of the environment variables set for commands.
+	envPrefix = "HRTCLICKER_"
+	// maxStderr is the maximum number of bytes of stderr that are logged.
+	maxStderr = 4096
+	// waitDelay is how long to wait for the command's output to be closed
+	// after it is killed.
+	waitDelay = time.Second
+)
+
+// Dependencies is a set of dependencies required by the Runner.
+type Dependencies struct {
+	Config   *hrtclicker.ReloadableConfig
+	Logger   *slog.Logger
+	Database *db.SQLiteDB
+	Events   *events.Bus
+}
+
+// Runner runs the configured command hooks for the events published on the
+// event bus.
+type Runner struct {
+	Dependencies
+}
+
+// NewRunner creates a new Runner.
+func NewRunner(deps Dependencies) *Runner {
+	return &Runner{Dependencies: deps}
+}
+
+// Run runs command hooks until ctx is canceled, which also kills the commands
+// that are still running. The concurrency limit is read from the configuration
+// once, so changing it requires a restart.
+func (r *Runner) Run(ctx context.Context) error {
+	concurrency := r.Config.Load().Hooks.Concurrency
+	if concurrency <= 0 {
+		concurrency = hrtclicker.DefaultHookConcurrency
+	}
+	sema := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for ev := range r.Events.SubscribeAll(ctx) {
+		var env []string
+
+		for _, hook := range r.Config.Load().Hooks.Commands {
+			if !hook.Wants(ev.Type) {
+				continue
+			}
+
+			if env == nil {
+				env = r.environment(ctx, ev)
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				select {
+				case <-ctx.Done():
+					return
+				case sema <- struct{}{}:
+					defer func() { <-sema }()
+				}
+
+				r.run(ctx, hook, ev, env)
+			}()
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (r *Runner) run(ctx context.Context, hook hrtclicker.HookConfig, ev events.Event, env []string) {
+	stdin, err := json.Marshal(ev)
+	if err != nil {
+		r.Logger.Error(
+			"failed to marshal hook input",
+			"event_type", ev.Type,
+			"err", err)
+		return
+	}
+
+	timeout := hook.Timeout.AsDuration()
+	if timeout <= 0 {
+		timeout = hrtclicker.DefaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stderr := &limitedBuffer{max: maxStderr}
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+
+	start := time.Now()
+	err = cmd.Run()
+	took := time.Since(start)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %v: %w", timeout, err)
+	}
+
+	if err != nil {
+		r.Logger.Error(
+			"command hook failed",
+			"command", hook.Command,
+			"event_type", ev.Type,
+			"event_id", ev.ID,
+			"took", took,
+			"stderr", stderr.String(),
+			"err", err)
+		return
+	}
+
+	r.Logger.Info(
+		"ran command hook",
+		"command", hook.Command,
+		"event_type", ev.Type,
+		"event_id", ev.ID,
+		"took", took,
+		"stderr", stderr.String())
+}
+
+// environment returns the environment of the commands run for the event. It
+// is the environment of this process with the event variables added.
+func (r *Runner) environment(ctx context.Context, ev events.Event) []string {
+	vars := map[string]string{
+		"EVENT":      string(ev.Type),
+		"EVENT_ID":   ev.ID,
+		"EVENT_TIME": ev.Time.Format(time.RFC3339),
+	}
+
+	// Flatten the event data by going through its JSON form, which also
+	// formats times the same way as the JSON on stdin.
+	var data map[string]any
+	if b, err := json.Marshal(ev.Data); err == nil {
+		json.Unmarshal(b, &data)
+	}
+	for k, v := range data {
+		switch v := v.(type) {
+		case string:
+			vars[envName(k)] = v
+		case float64, bool:
+			vars[envName(k)] = fmt.Sprint(v)
+		}
+	}
+
+	_, hasLast := vars["LAST_DOSE_AT"]
+	_, hasNext := vars["NEXT_DOSE_AT"]
+	if !hasLast || !hasNext {
+		cfg := r.Config.Load()
+
+		regimen, ok := cfg.Regimen(hrtclicker.HRTType(vars["HRT_TYPE"]))
+		if !ok {
+			regimen = cfg.HRT
+		}
+
+		next, err := schedule.Next(ctx, r.Database, regimen, time.Now())
+		if err != nil {
+			r.Logger.Warn(
+				"failed to get next dose for hook environment",
+				"err", err)
+		}
+		if !hasLast && !next.LastDoseAt.IsZero() {
+			vars["LAST_DOSE_AT"] = next.LastDoseAt.Format(time.RFC3339)
+		}
+		if !hasNext && !next.NextDoseAt.IsZero() {
+			vars["NEXT_DOSE_AT"] = next.NextDoseAt.Format(time.RFC3339)
+		}
+	}
+
+	env := os.Environ()
+	for k, v := range vars {
+		env = append(env, envPrefix+k+"="+v)
+	}
+	return env
+}
+
+// envName converts a field name such as "DosageAt", "HRTType" or
+// "overdue_after" to an environment variable name such as "DOSAGE_AT",
+// "HRT_TYPE" or "OVERDUE_AFTER".
+func envName(field string) string {
+	runes := []rune(field)
+
+	var s strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				s.WriteByte('_')
+			}
+		}
+		s.WriteRune(unicode.ToUpper(r))
+	}
+	return s.String()
+}
+
+// limitedBuffer is an io.Writer that keeps the first max bytes written to it
+// and discards the rest.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := b.max - b.buf.Len(); len(p) > room {
+		p = p[:max(room, 0)]
+		b.truncated = true
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+// String returns the written bytes with surrounding whitespace trimmed.
+func (b *limitedBuffer) String() string {
+	s := strings.TrimSpace(b.buf.String())
+	if b.truncated {
+		s += "…"
+	}
+	return s
+}
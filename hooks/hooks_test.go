@@ -0,0 +1,204 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/pause"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"DosageAt", "DOSAGE_AT"},
+		{"HRTType", "HRT_TYPE"},
+		{"overdue_after", "OVERDUE_AFTER"},
+		{"ID", "ID"},
+		{"Dose2At", "DOSE2_AT"},
+		{"nextDoseAt", "NEXT_DOSE_AT"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.field, func(t *testing.T) {
+			if got := envName(test.field); got != test.want {
+				t.Errorf("envName(%q) = %q, want %q", test.field, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	at := time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC)
+	ev := events.Event{
+		ID:   "0123",
+		Type: events.DoseRecorded,
+		Time: at,
+		Data: struct {
+			HRTType    string
+			DosageAt   time.Time
+			LastDoseAt time.Time
+			NextDoseAt time.Time
+			Count      int
+			Snoozed    bool
+			Tags       []string
+		}{"patches", at, at, at.Add(24 * time.Hour), 2, false, []string{"left"}},
+	}
+
+	r := NewRunner(Dependencies{Logger: slog.Default()})
+	env := r.environment(context.Background(), ev)
+
+	for _, want := range []string{
+		"HRTCLICKER_EVENT=dose-recorded",
+		"HRTCLICKER_EVENT_ID=0123",
+		"HRTCLICKER_EVENT_TIME=2026-03-28T08:00:00Z",
+		"HRTCLICKER_HRT_TYPE=patches",
+		"HRTCLICKER_DOSAGE_AT=2026-03-28T08:00:00Z",
+		"HRTCLICKER_LAST_DOSE_AT=2026-03-28T08:00:00Z",
+		"HRTCLICKER_NEXT_DOSE_AT=2026-03-29T08:00:00Z",
+		"HRTCLICKER_COUNT=2",
+		"HRTCLICKER_SNOOZED=false",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("environment is missing %s", want)
+		}
+	}
+	for _, v := range env {
+		if strings.HasPrefix(v, "HRTCLICKER_TAGS=") {
+			t.Errorf("environment has %s, want only scalar fields", v)
+		}
+	}
+	if len(env) < len(os.Environ()) {
+		t.Errorf("environment doesn't include the environment of the process")
+	}
+}
+
+func TestEnvironmentNextDose(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	// The next dose falls due in a pause, so it is due when the pause ends,
+	// the same as the reminders.
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := database.RecordDosage(ctx, db.RecordDosageParams{
+		DosageAt: now.Add(-time.Hour),
+		HRTType:  string(hrttest.Daily.Type),
+		Tags:     db.NewTags(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pause.Start(ctx, database, hrttest.Daily.Type, now.Add(time.Hour), now.Add(48*time.Hour), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRunner(Dependencies{
+		Config:   hrttest.Config(t, &hrtclicker.Config{HRT: hrttest.Daily}),
+		Logger:   slog.Default(),
+		Database: database,
+	})
+	env := r.environment(ctx, events.Event{
+		Type: events.Snoozed,
+		Data: map[string]string{"HRTType": string(hrttest.Daily.Type)},
+	})
+
+	for _, want := range []string{
+		"HRTCLICKER_LAST_DOSE_AT=" + now.Add(-time.Hour).Format(time.RFC3339),
+		"HRTCLICKER_NEXT_DOSE_AT=" + now.Add(48*time.Hour).Format(time.RFC3339),
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("environment is missing %s", want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	ev := events.Event{ID: "0123", Type: events.Snoozed, Data: map[string]string{"HRTType": "gel"}}
+
+	tests := []struct {
+		name    string
+		command []string
+		timeout time.Duration
+		// wantLog is in the log of the run.
+		wantLog string
+		// wantStdin is whether the event is written to the output file.
+		wantStdin bool
+	}{
+		{
+			name:      "event on stdin",
+			command:   []string{"sh", "-c", `cat > "$0"`},
+			wantLog:   "ran command hook",
+			wantStdin: true,
+		},
+		{
+			name:    "stderr logged",
+			command: []string{"sh", "-c", `echo "no luck" >&2; exit 1`},
+			wantLog: `stderr="no luck"`,
+		},
+		{
+			name:    "timeout",
+			command: []string{"sleep", "10"},
+			timeout: 100 * time.Millisecond,
+			wantLog: "timed out after 100ms",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "event.json")
+			command := test.command
+			if test.wantStdin {
+				command = append(slices.Clone(command), out)
+			}
+
+			var log bytes.Buffer
+			r := NewRunner(Dependencies{Logger: slog.New(slog.NewTextHandler(&log, nil))})
+			r.run(context.Background(), hrtclicker.HookConfig{
+				Command: command,
+				Timeout: cfgtypes.Duration(test.timeout),
+			}, ev, os.Environ())
+
+			if !strings.Contains(log.String(), test.wantLog) {
+				t.Errorf("log = %q, want it to contain %q", log.String(), test.wantLog)
+			}
+			if !test.wantStdin {
+				return
+			}
+
+			b, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got events.Event
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != ev.ID || got.Type != ev.Type {
+				t.Errorf("stdin = %s, want the event", b)
+			}
+		})
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 12}
+	b.Write([]byte("  failed: "))
+	b.Write([]byte("out of patches"))
+
+	if got, want := b.String(), "failed: ou…"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -13,6 +13,16 @@ const (
 	TypeInjection  HRTType = "injection"
 )
 
+// IsValid returns true if the HRT type is one of the supported types.
+func (t HRTType) IsValid() bool {
+	switch t {
+	case TypePatches, TypeGel, TypeSublingual, TypeInjection:
+		return true
+	default:
+		return false
+	}
+}
+
 // Notification is a type used to represent the notification message.
 // It copies Gotify's notification message format.
 type Notification struct {
@@ -22,7 +32,8 @@ type Notification struct {
 }
 
 // NotificationTemplateData is the data used for rendering the template of both
-// the notification title and message.
+// the notification title and message. The times are in the time zone of the
+// regimen, so the templates can format them as they are.
 type NotificationTemplateData struct {
 	LastDoseAt time.Time
 	NextDoseAt time.Time
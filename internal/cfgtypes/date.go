@@ -0,0 +1,46 @@
+package cfgtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date is a calendar date without a time zone, written as "2006-01-02" in
+// JSON. It is the same day wherever it is used.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// IsZero returns true if the date is not set.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns the start of the date in the given time zone.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return fmt.Errorf("failed to parse date: %w", err)
+	}
+	*d = Date{t.Year(), t.Month(), t.Day()}
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
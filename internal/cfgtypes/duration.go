@@ -25,3 +25,7 @@ func (d *Duration) UnmarshalJSON(data []byte) error {
 	*d = Duration(dur)
 	return nil
 }
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
@@ -0,0 +1,61 @@
+// Package hrttest provides helpers shared by the tests of other packages.
+package hrttest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+)
+
+// Daily is a regimen with a dose every day in UTC.
+var Daily = hrtclicker.HRTConfig{
+	Type:     hrtclicker.TypeSublingual,
+	Interval: cfgtypes.Duration(24 * time.Hour),
+	TimeZone: "UTC",
+}
+
+// Date returns the time on the given day of March 2026 in UTC.
+func Date(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+}
+
+// OpenDB opens a new database in a temporary directory that is closed when
+// the test finishes.
+func OpenDB(t *testing.T) *db.SQLiteDB {
+	t.Helper()
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "hrt.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// Config writes the configuration to a temporary file and loads it back, for
+// the packages that take a ReloadableConfig.
+func Config(t *testing.T, cfg *hrtclicker.Config) *hrtclicker.ReloadableConfig {
+	t.Helper()
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := hrtclicker.NewReloadableConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
@@ -0,0 +1,162 @@
+// Package ics writes iCalendar files as specified by RFC 5545. Only the parts
+// needed for a feed of events with alarms are implemented.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the MIME type of iCalendar files.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Calendar is a VCALENDAR object.
+type Calendar struct {
+	// ProdID identifies the product that created the calendar.
+	ProdID string
+	// Name is the display name of the calendar. It is optional.
+	Name string
+	// TimeZone is the IANA name of the time zone the calendar is meant to be
+	// shown in. It is optional, since the times themselves are in UTC.
+	TimeZone string
+	Events   []Event
+}
+
+// Event is a VEVENT component.
+type Event struct {
+	// UID uniquely and persistently identifies the event.
+	UID string
+	// Stamp is the time the event was last changed.
+	Stamp    time.Time
+	Start    time.Time
+	Duration time.Duration
+	Summary  string
+	// Description is optional.
+	Description string
+	// Alarms is optional.
+	Alarms []Alarm
+}
+
+// Alarm is a VALARM component that displays a reminder.
+type Alarm struct {
+	// Before is how long before the start of the event the alarm goes off.
+	Before      time.Duration
+	Description string
+}
+
+// Write writes the calendar to w.
+func (c Calendar) Write(w io.Writer) error {
+	b := bufio.NewWriter(w)
+
+	line := func(name, value string) {
+		writeLine(b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", c.ProdID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escapeText(c.Name))
+	}
+	if c.TimeZone != "" {
+		line("X-WR-TIMEZONE", escapeText(c.TimeZone))
+	}
+
+	for _, ev := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", ev.UID)
+		line("DTSTAMP", formatTime(ev.Stamp))
+		line("DTSTART", formatTime(ev.Start))
+		if ev.Duration > 0 {
+			line("DURATION", formatDuration(ev.Duration))
+		}
+		line("SUMMARY", escapeText(ev.Summary))
+		if ev.Description != "" {
+			line("DESCRIPTION", escapeText(ev.Description))
+		}
+		for _, alarm := range ev.Alarms {
+			line("BEGIN", "VALARM")
+			line("ACTION", "DISPLAY")
+			line("TRIGGER", "-"+formatDuration(alarm.Before))
+			line("DESCRIPTION", escapeText(alarm.Description))
+			line("END", "VALARM")
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.Flush()
+}
+
+// writeLine writes a content line, folding it so that no line is longer than
+// 75 octets without splitting UTF-8 sequences.
+func writeLine(w *bufio.Writer, s string) {
+	const maxLength = 75
+
+	limit := maxLength
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		w.WriteString(s[:i])
+		w.WriteString("\r\n ")
+		s = s[i:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLength - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// escapeText escapes a TEXT value.
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// formatTime formats t as a DATE-TIME value in UTC.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// formatDuration formats d as a DURATION value to the second. Negative
+// durations are formatted as their absolute value.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second).Abs()
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+
+	var s strings.Builder
+	s.WriteString("P")
+	if days > 0 {
+		fmt.Fprintf(&s, "%dD", days)
+	}
+	if d > 0 || days == 0 {
+		s.WriteString("T")
+		if h := d / time.Hour; h > 0 {
+			fmt.Fprintf(&s, "%dH", h)
+		}
+		if m := d % time.Hour / time.Minute; m > 0 {
+			fmt.Fprintf(&s, "%dM", m)
+		}
+		if sec := d % time.Minute / time.Second; sec > 0 || d == 0 {
+			fmt.Fprintf(&s, "%dS", sec)
+		}
+	}
+	return s.String()
+}
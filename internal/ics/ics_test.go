@@ -0,0 +1,174 @@
+package ics
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Take your dose", "Take your dose"},
+		{"gel, 2 pumps; left arm", `gel\, 2 pumps\; left arm`},
+		{`C:\doses`, `C:\\doses`},
+		{"line\nbreak", `line\nbreak`},
+		{"line\r\nbreak", `line\nbreak`},
+		{`\,`, `\\\,`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			if got := escapeText(test.in); got != test.want {
+				t.Errorf("escapeText(%q) = %q, want %q", test.in, got, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "short",
+			line: "SUMMARY:Dose",
+			want: "SUMMARY:Dose\r\n",
+		},
+		{
+			name: "75 octets",
+			line: strings.Repeat("a", 75),
+			want: strings.Repeat("a", 75) + "\r\n",
+		},
+		{
+			name: "76 octets",
+			line: strings.Repeat("a", 76),
+			want: strings.Repeat("a", 75) + "\r\n a\r\n",
+		},
+		{
+			// Continuation lines hold 74 octets after their leading space.
+			name: "three lines",
+			line: strings.Repeat("a", 75+74+1),
+			want: strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a\r\n",
+		},
+		{
+			name: "multibyte character at the limit",
+			line: strings.Repeat("a", 74) + "é",
+			want: strings.Repeat("a", 74) + "\r\n é\r\n",
+		},
+		{
+			name: "emoji at the limit",
+			line: strings.Repeat("a", 73) + "💊b",
+			want: strings.Repeat("a", 73) + "\r\n 💊b\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s strings.Builder
+			w := bufio.NewWriter(&s)
+			writeLine(w, test.line)
+			w.Flush()
+
+			got := s.String()
+			if got != test.want {
+				t.Errorf("writeLine() = %q, want %q", got, test.want)
+			}
+
+			for _, line := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("line %q is %d octets long", line, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %q splits a UTF-8 sequence", line)
+				}
+			}
+
+			// Unfolding gives back the original line.
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(got, "\r\n"), "\r\n ", ""); unfolded != test.line {
+				t.Errorf("unfolded line = %q, want %q", unfolded, test.line)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{15 * time.Minute, "PT15M"},
+		{-15 * time.Minute, "PT15M"},
+		{90 * time.Minute, "PT1H30M"},
+		{24 * time.Hour, "P1D"},
+		{3*24*time.Hour + 30*time.Second, "P3DT30S"},
+		{1500 * time.Millisecond, "PT2S"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.d.String(), func(t *testing.T) {
+			if got := formatDuration(test.d); got != test.want {
+				t.Errorf("formatDuration(%s) = %q, want %q", test.d, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCalendarWrite(t *testing.T) {
+	start := time.Date(2026, 3, 28, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	calendar := Calendar{
+		ProdID:   "-//hrtclicker//EN",
+		Name:     "Doses, estradiol",
+		TimeZone: "Europe/Berlin",
+		Events: []Event{{
+			UID:         "dose-1@hrtclicker",
+			Stamp:       start,
+			Start:       start,
+			Duration:    15 * time.Minute,
+			Summary:     "Take your dose",
+			Description: "gel; 2 pumps\nleft arm",
+			Alarms:      []Alarm{{Before: 10 * time.Minute, Description: "Dose in 10 minutes"}},
+		}},
+	}
+
+	var s strings.Builder
+	if err := calendar.Write(&s); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//hrtclicker//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Doses\, estradiol`,
+		"X-WR-TIMEZONE:Europe/Berlin",
+		"BEGIN:VEVENT",
+		"UID:dose-1@hrtclicker",
+		"DTSTAMP:20260328T080000Z",
+		"DTSTART:20260328T080000Z",
+		"DURATION:PT15M",
+		"SUMMARY:Take your dose",
+		`DESCRIPTION:gel\; 2 pumps\nleft arm`,
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT10M",
+		"DESCRIPTION:Dose in 10 minutes",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := s.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
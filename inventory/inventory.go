@@ -0,0 +1,260 @@
+// Package inventory tracks the supply of medication. Recording a dose takes its
+// amount out of the stock, and the time the stock runs out is projected from
+// the schedule of the regimen. Stock can be filled from prescriptions, which
+// keep track of their refills.
+package inventory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+// maxProjectedDoses is the most doses the projection goes through, in case
+// there is a lot of stock.
+const maxProjectedDoses = 10000
+
+// epsilon is the amount below which units are considered used up, since
+// fractional amounts are kept as floats.
+const epsilon = 1e-9
+
+// RecordDose records a dose of the regimen at the given time with the given
+// notes and tags in the time zone of the regimen, and takes it out of the
+// stock in the same transaction. The error of RecordDosage is returned as is,
+// so db.IsAlreadyExists can be used on it.
+func RecordDose(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, dosageAt time.Time, notes string, tags db.Tags) error {
+	return database.Tx(func(q *db.Queries) error {
+		if err := q.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: dosageAt,
+			HRTType:  string(regimen.Type),
+			Notes:    notes,
+			Tags:     db.NewTags(tags...),
+			Zone:     regimen.Zone(),
+		}); err != nil {
+			return err
+		}
+		return Use(ctx, q, regimen, dosageAt)
+	})
+}
+
+// Use takes the dose amount of the regimen out of the stock for the dose taken
+// at the given time, using the amount in effect at that time and starting with
+// the entries that expire first. Entries that expired before the dose are
+// skipped. If there is not enough stock, whatever
+// is left is taken.
+//
+// The dose must already be recorded, so that the stock is put back if it is
+// deleted.
+func Use(ctx context.Context, q *db.Queries, regimen hrtclicker.HRTConfig, dosageAt time.Time) error {
+	stock, err := q.AvailableStock(ctx, string(regimen.Type))
+	if err != nil {
+		return fmt.Errorf("failed to get stock: %w", err)
+	}
+
+	need := regimen.At(dosageAt).UnitsPerDose()
+	for _, entry := range stock {
+		if need < epsilon {
+			break
+		}
+		if expired(entry, dosageAt, regimen.Location()) {
+			continue
+		}
+
+		amount := min(need, entry.Remaining)
+		if err := q.UseStock(ctx, db.UseStockParams{
+			DosageAt: dosageAt,
+			StockID:  entry.ID,
+			Amount:   amount,
+		}); err != nil {
+			return fmt.Errorf("failed to take from stock %d: %w", entry.ID, err)
+		}
+		need -= amount
+	}
+
+	return nil
+}
+
+// Inventory is the stock of a regimen and its projection.
+type Inventory struct {
+	Status
+	// Stock is every stock entry of the regimen, most recently added first.
+	Stock []db.Stock
+	// Prescriptions is every prescription of the regimen, most recently
+	// written first.
+	Prescriptions []Prescription
+	// RenewBefore is how long before the current prescription expires the
+	// renewal reminder is sent.
+	RenewBefore time.Duration
+}
+
+// Prescription returns the prescription with the given ID, or nil if there is
+// none.
+func (inv Inventory) Prescription(id int64) *Prescription {
+	for i := range inv.Prescriptions {
+		if inv.Prescriptions[i].ID == id {
+			return &inv.Prescriptions[i]
+		}
+	}
+	return nil
+}
+
+// Status is the projected supply of a regimen.
+type Status struct {
+	HRTType hrtclicker.HRTType
+	// UnitsPerDose is the number of units taken for every dose under the
+	// regimen in effect now.
+	UnitsPerDose float64
+	// Remaining is the number of units left in stock that hasn't expired.
+	Remaining float64
+	// Doses is the number of doses that the remaining stock lasts for. Stock
+	// that expires before it would be used doesn't count.
+	Doses int
+	// RunsOutAt is the time the first dose that cannot be taken from the stock
+	// is due. It is zero if no stock was ever added.
+	RunsOutAt time.Time
+	// DaysLeft is the number of days until RunsOutAt. It is never negative.
+	DaysLeft float64
+	// RefillBelow is how long before RunsOutAt the refill reminder is sent.
+	RefillBelow time.Duration
+	// NeedsRefill is true if the stock runs out within RefillBelow.
+	NeedsRefill bool
+}
+
+// Tracked returns true if any stock was added, so that the supply is known.
+func (s Status) Tracked() bool {
+	return !s.RunsOutAt.IsZero()
+}
+
+// Load loads the stock and prescriptions of the regimen and projects when the
+// stock runs out, assuming every dose after the last one is taken when it is
+// due.
+func Load(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, now time.Time) (Inventory, error) {
+	stock, err := database.StockEntries(ctx, string(regimen.Type))
+	if err != nil {
+		return Inventory{}, fmt.Errorf("failed to get stock: %w", err)
+	}
+
+	prescriptions, err := Prescriptions(ctx, database, regimen.Type)
+	if err != nil {
+		return Inventory{}, err
+	}
+
+	nextDoseAt := now
+	lastDose, err := database.LastDose(ctx, string(regimen.Type))
+	if err != nil && !db.IsNotFound(err) {
+		return Inventory{}, fmt.Errorf("failed to get last dose: %w", err)
+	}
+	if err == nil {
+		nextDoseAt = regimen.NextDoseAt(lastDose.DosageAt)
+	}
+
+	return Inventory{
+		Status:        Project(regimen, stock, nextDoseAt, now),
+		Stock:         stock,
+		Prescriptions: prescriptions,
+		RenewBefore:   regimen.RenewBefore(),
+	}, nil
+}
+
+// Project projects the supply of the given stock entries of the regimen, with
+// the next dose due at the given time.
+func Project(regimen hrtclicker.HRTConfig, stock []db.Stock, nextDoseAt, now time.Time) Status {
+	status := Status{
+		HRTType:      regimen.Type,
+		UnitsPerDose: regimen.At(now).UnitsPerDose(),
+		RefillBelow:  regimen.RefillBelow(),
+	}
+	if len(stock) == 0 {
+		return status
+	}
+
+	// Take from copies of the entries in the same order that Use does.
+	var available []db.Stock
+	for _, entry := range stock {
+		if entry.Remaining < epsilon {
+			continue
+		}
+		if !expired(entry, now, regimen.Location()) {
+			status.Remaining += entry.Remaining
+		}
+		available = append(available, entry)
+	}
+	slices.SortFunc(available, compareExpiry)
+
+	due := nextDoseAt
+	for ; status.Doses < maxProjectedDoses; status.Doses++ {
+		if !take(available, due, regimen.At(due).UnitsPerDose(), regimen.Location()) {
+			break
+		}
+		due = regimen.NextDoseAt(due)
+	}
+
+	status.RunsOutAt = due
+	status.DaysLeft = max(0, due.Sub(now).Hours()/24)
+	status.NeedsRefill = due.Sub(now) < status.RefillBelow
+	return status
+}
+
+// take takes the amount out of the entries that haven't expired at the given
+// time in loc. False is returned without taking anything if there isn't
+// enough.
+func take(stock []db.Stock, at time.Time, amount float64, loc *time.Location) bool {
+	var usable float64
+	for _, entry := range stock {
+		if !expired(entry, at, loc) {
+			usable += entry.Remaining
+		}
+	}
+	if usable < amount-epsilon {
+		return false
+	}
+
+	for i := range stock {
+		if amount < epsilon {
+			break
+		}
+		if expired(stock[i], at, loc) {
+			continue
+		}
+		n := min(amount, stock[i].Remaining)
+		stock[i].Remaining -= n
+		amount -= n
+	}
+	return true
+}
+
+// compareExpiry orders stock entries so that the ones expiring first come
+// first and the ones without an expiry date come last, then by when they were
+// added. It is the same order as the AvailableStock query.
+func compareExpiry(a, b db.Stock) int {
+	var c int
+	switch {
+	case a.ExpiresAt.Valid && b.ExpiresAt.Valid:
+		c = a.ExpiresAt.Time.Compare(b.ExpiresAt.Time)
+	case a.ExpiresAt.Valid:
+		c = -1
+	case b.ExpiresAt.Valid:
+		c = 1
+	}
+	return cmp.Or(c, a.AddedAt.Compare(b.AddedAt), cmp.Compare(a.ID, b.ID))
+}
+
+// expired returns true if the stock entry has expired by the given time. Stock
+// can be used until the end of its expiry date in loc.
+func expired(entry db.Stock, t time.Time, loc *time.Location) bool {
+	if !entry.ExpiresAt.Valid {
+		return false
+	}
+	return !t.Before(endOfDay(entry.ExpiresAt.Time, loc))
+}
+
+// endOfDay returns the start of the day in loc after the one of t.
+func endOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,192 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+// expiresOn returns the expiry date of stock that can be used until the end
+// of the given day.
+func expiresOn(day int) sql.NullTime {
+	return sql.NullTime{Time: date(day, 0, 0), Valid: true}
+}
+
+func TestProject(t *testing.T) {
+	now := date(1, 0, 0)
+	nextDoseAt := date(1, 8, 0)
+
+	tests := []struct {
+		name    string
+		regimen func(hrtclicker.HRTConfig) hrtclicker.HRTConfig
+		stock   []db.Stock
+		want    Status
+	}{
+		{
+			name: "no stock",
+			want: Status{},
+		},
+		{
+			name:  "enough stock",
+			stock: []db.Stock{{ID: 1, Remaining: 5}},
+			want:  Status{Remaining: 5, Doses: 5, RunsOutAt: date(6, 8, 0), DaysLeft: 5 + 8.0/24},
+		},
+		{
+			name:  "running out",
+			stock: []db.Stock{{ID: 1, Remaining: 2}},
+			want:  Status{Remaining: 2, Doses: 2, RunsOutAt: date(3, 8, 0), DaysLeft: 2 + 8.0/24, NeedsRefill: true},
+		},
+		{
+			name:  "used up stock",
+			stock: []db.Stock{{ID: 1, Remaining: 0}},
+			want:  Status{Doses: 0, RunsOutAt: date(1, 8, 0), DaysLeft: 8.0 / 24, NeedsRefill: true},
+		},
+		{
+			name:  "expiring before it is used",
+			stock: []db.Stock{{ID: 1, Remaining: 10, ExpiresAt: expiresOn(3)}},
+			want:  Status{Remaining: 10, Doses: 3, RunsOutAt: date(4, 8, 0), DaysLeft: 3 + 8.0/24},
+		},
+		{
+			name:  "expired",
+			stock: []db.Stock{{ID: 1, Remaining: 10, ExpiresAt: expiresOn(-1)}},
+			want:  Status{Doses: 0, RunsOutAt: date(1, 8, 0), DaysLeft: 8.0 / 24, NeedsRefill: true},
+		},
+		{
+			name: "expiring stock used first",
+			stock: []db.Stock{
+				{ID: 1, Remaining: 2},
+				{ID: 2, Remaining: 2, ExpiresAt: expiresOn(2)},
+			},
+			want: Status{Remaining: 4, Doses: 4, RunsOutAt: date(5, 8, 0), DaysLeft: 4 + 8.0/24},
+		},
+		{
+			name: "fractional dose amount",
+			regimen: func(r hrtclicker.HRTConfig) hrtclicker.HRTConfig {
+				r.DoseAmount = 0.5
+				return r
+			},
+			stock: []db.Stock{{ID: 1, Remaining: 2}},
+			want:  Status{UnitsPerDose: 0.5, Remaining: 2, Doses: 4, RunsOutAt: date(5, 8, 0), DaysLeft: 4 + 8.0/24},
+		},
+		{
+			name: "dose amount changing",
+			regimen: func(r hrtclicker.HRTConfig) hrtclicker.HRTConfig {
+				r.Changes = []hrtclicker.RegimenChange{{EffectiveAt: date(3, 0, 0), DoseAmount: 2}}
+				return r
+			},
+			stock: []db.Stock{{ID: 1, Remaining: 6}},
+			want:  Status{Remaining: 6, Doses: 4, RunsOutAt: date(5, 8, 0), DaysLeft: 4 + 8.0/24},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The regimen takes one unit every day and is refilled 3 days
+			// before running out.
+			regimen := hrttest.Daily
+			regimen.RefillDays = 3
+			if test.regimen != nil {
+				regimen = test.regimen(regimen)
+			}
+
+			want := test.want
+			want.HRTType = regimen.Type
+			want.RefillBelow = 3 * 24 * time.Hour
+			if want.UnitsPerDose == 0 {
+				want.UnitsPerDose = 1
+			}
+
+			got := Project(regimen, test.stock, nextDoseAt, now)
+			if got != want {
+				t.Errorf("Project() = %+v, want %+v", got, want)
+			}
+			if got.Tracked() != (len(test.stock) > 0) {
+				t.Errorf("Tracked() = %v with %d stock entries", got.Tracked(), len(test.stock))
+			}
+		})
+	}
+}
+
+func TestRecordDose(t *testing.T) {
+	tests := []struct {
+		name       string
+		dosageAt   time.Time
+		doseAmount float64
+		// want is the remaining amount of each stock entry by its lot.
+		want map[string]float64
+	}{
+		{
+			name:     "expiring first",
+			dosageAt: date(1, 8, 0),
+			want:     map[string]float64{"expiring": 0, "lasting": 5, "expired": 5},
+		},
+		{
+			name:       "across entries",
+			dosageAt:   date(1, 8, 0),
+			doseAmount: 2,
+			want:       map[string]float64{"expiring": 0, "lasting": 4, "expired": 5},
+		},
+		{
+			name:     "skipping expired entries",
+			dosageAt: date(3, 8, 0),
+			want:     map[string]float64{"expiring": 1, "lasting": 4, "expired": 5},
+		},
+		{
+			name:       "not enough stock",
+			dosageAt:   date(3, 8, 0),
+			doseAmount: 10,
+			want:       map[string]float64{"expiring": 1, "lasting": 0, "expired": 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+
+			for i, entry := range []struct {
+				lot       string
+				quantity  float64
+				expiresAt sql.NullTime
+			}{
+				{"lasting", 5, sql.NullTime{}},
+				{"expiring", 1, expiresOn(2)},
+				{"expired", 5, expiresOn(-5)},
+			} {
+				if _, err := database.AddStock(ctx, db.AddStockParams{
+					HRTType:   string(hrttest.Daily.Type),
+					Quantity:  entry.quantity,
+					Remaining: entry.quantity,
+					Lot:       entry.lot,
+					ExpiresAt: entry.expiresAt,
+					AddedAt:   date(-10, i, 0),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			regimen := hrttest.Daily
+			regimen.DoseAmount = test.doseAmount
+			if err := RecordDose(ctx, database, regimen, test.dosageAt, "", nil); err != nil {
+				t.Fatal(err)
+			}
+
+			stock, err := database.StockEntries(ctx, string(hrttest.Daily.Type))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, entry := range stock {
+				if want := test.want[entry.Lot]; entry.Remaining != want {
+					t.Errorf("stock %q has %v remaining, want %v", entry.Lot, entry.Remaining, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+var (
+	// ErrPrescriptionExpired is returned when filling a prescription that has
+	// expired.
+	ErrPrescriptionExpired = errors.New("prescription has expired")
+	// ErrNoRefills is returned when filling a prescription that was already
+	// filled and has no refills left.
+	ErrNoRefills = errors.New("prescription has no refills left")
+	// ErrWrongType is returned when filling a prescription with stock of a
+	// different regimen.
+	ErrWrongType = errors.New("prescription is for a different type")
+)
+
+// Prescription is a prescription with how much of it was used.
+type Prescription struct {
+	db.Prescription
+	// Fills is the number of stock entries filled from the prescription,
+	// including the first fill.
+	Fills int64
+	// Doses is the number of doses that drew from stock filled from the
+	// prescription.
+	Doses int64
+}
+
+// Expired returns true if the prescription has expired by the given time. It
+// can be filled until the end of its expiry date in loc.
+func (p Prescription) Expired(t time.Time, loc *time.Location) bool {
+	return !t.Before(endOfDay(p.ExpiresAt, loc))
+}
+
+// Prescriptions returns every prescription of the given type, most recently
+// written first. The first one is the current prescription.
+func Prescriptions(ctx context.Context, database *db.SQLiteDB, hrtType hrtclicker.HRTType) ([]Prescription, error) {
+	rows, err := database.Prescriptions(ctx, string(hrtType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prescriptions: %w", err)
+	}
+
+	prescriptions := make([]Prescription, len(rows))
+	for i, row := range rows {
+		prescriptions[i] = Prescription{
+			Prescription: row.Prescription,
+			Fills:        row.Fills,
+			Doses:        row.Doses,
+		}
+	}
+	return prescriptions, nil
+}
+
+// AddStock adds a stock entry. If it was filled from a prescription, the
+// prescription must be of the same type and not have expired in loc when the
+// stock was added, and every fill after the first uses up one of its refills.
+func AddStock(ctx context.Context, database *db.SQLiteDB, loc *time.Location, arg db.AddStockParams) (db.Stock, error) {
+	var stock db.Stock
+	err := database.Tx(func(q *db.Queries) error {
+		if arg.PrescriptionID.Valid {
+			if err := fill(ctx, q, loc, arg); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		stock, err = q.AddStock(ctx, arg)
+		return err
+	})
+	return stock, err
+}
+
+// fill checks that the stock can be filled from its prescription and uses up a
+// refill if it is not the first fill.
+func fill(ctx context.Context, q *db.Queries, loc *time.Location, arg db.AddStockParams) error {
+	p, err := q.Prescription(ctx, arg.PrescriptionID.Int64)
+	if err != nil {
+		return fmt.Errorf("failed to get prescription %d: %w", arg.PrescriptionID.Int64, err)
+	}
+
+	if p.HRTType != arg.HRTType {
+		return ErrWrongType
+	}
+	if (Prescription{Prescription: p}).Expired(arg.AddedAt, loc) {
+		return ErrPrescriptionExpired
+	}
+
+	fills, err := q.PrescriptionFills(ctx, arg.PrescriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to count fills: %w", err)
+	}
+	if fills == 0 {
+		return nil
+	}
+
+	if p.Refills <= 0 {
+		return ErrNoRefills
+	}
+	if err := q.UseRefill(ctx, p.ID); err != nil {
+		return fmt.Errorf("failed to use refill: %w", err)
+	}
+	return nil
+}
+
+// RenewalReason is why a prescription needs renewing.
+type RenewalReason string
+
+const (
+	// RenewalExpiring means the prescription expires soon or has expired.
+	RenewalExpiring RenewalReason = "expiring"
+	// RenewalNoRefills means the prescription was filled and has no refills
+	// left.
+	RenewalNoRefills RenewalReason = "no-refills"
+)
+
+// Renewal is a reason to renew a prescription. It is the data of the
+// events.PrescriptionRenewalDue event.
+type Renewal struct {
+	Reason RenewalReason
+	Prescription
+}
+
+// RenewalsDue returns the reasons the current prescription of the regimen
+// needs renewing, which is the first of the given prescriptions. Older
+// prescriptions are assumed to have been replaced by it.
+func RenewalsDue(regimen hrtclicker.HRTConfig, prescriptions []Prescription, now time.Time) []Renewal {
+	if len(prescriptions) == 0 {
+		return nil
+	}
+	current := prescriptions[0]
+
+	var renewals []Renewal
+	if !now.Before(endOfDay(current.ExpiresAt, regimen.Location()).Add(-regimen.RenewBefore())) {
+		renewals = append(renewals, Renewal{RenewalExpiring, current})
+	}
+	if current.Fills > 0 && current.Refills <= 0 {
+		renewals = append(renewals, Renewal{RenewalNoRefills, current})
+	}
+	return renewals
+}
@@ -0,0 +1,240 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+func TestPrescriptionExpired(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	tests := []struct {
+		name    string
+		expires time.Time
+		at      time.Time
+		want    bool
+	}{
+		{
+			name:    "during the day summer time starts",
+			expires: time.Date(2026, 3, 29, 0, 0, 0, 0, loc),
+			at:      time.Date(2026, 3, 29, 23, 59, 0, 0, loc),
+		},
+		{
+			name:    "after the day summer time starts",
+			expires: time.Date(2026, 3, 29, 0, 0, 0, 0, loc),
+			at:      time.Date(2026, 3, 30, 0, 0, 0, 0, loc),
+			want:    true,
+		},
+		{
+			// The day is 25 hours long.
+			name:    "during the day winter time starts",
+			expires: time.Date(2026, 10, 25, 0, 0, 0, 0, loc),
+			at:      time.Date(2026, 10, 25, 23, 59, 0, 0, loc),
+		},
+		{
+			name:    "after the day winter time starts",
+			expires: time.Date(2026, 10, 25, 0, 0, 0, 0, loc),
+			at:      time.Date(2026, 10, 26, 0, 0, 0, 0, loc),
+			want:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := Prescription{Prescription: db.Prescription{ExpiresAt: test.expires.UTC()}}
+			if got := p.Expired(test.at.UTC(), loc); got != test.want {
+				t.Errorf("Expired(%s) = %v, want %v", test.at, got, test.want)
+			}
+		})
+	}
+}
+
+func TestAddStockFromPrescription(t *testing.T) {
+	tests := []struct {
+		name string
+		// fills is the number of times the prescription was filled before.
+		fills       int
+		hrtType     hrtclicker.HRTType
+		addedAt     time.Time
+		wantErr     error
+		wantRefills int64
+	}{
+		{
+			name:        "first fill",
+			addedAt:     date(5, 12, 0),
+			wantRefills: 1,
+		},
+		{
+			name:        "refill",
+			fills:       1,
+			addedAt:     date(5, 12, 0),
+			wantRefills: 0,
+		},
+		{
+			name:        "no refills left",
+			fills:       2,
+			addedAt:     date(5, 12, 0),
+			wantErr:     ErrNoRefills,
+			wantRefills: 0,
+		},
+		{
+			name:        "on the expiry date",
+			addedAt:     date(10, 23, 0),
+			wantRefills: 1,
+		},
+		{
+			name:        "expired",
+			addedAt:     date(11, 0, 0),
+			wantErr:     ErrPrescriptionExpired,
+			wantRefills: 1,
+		},
+		{
+			name:        "different type",
+			hrtType:     hrtclicker.TypeGel,
+			addedAt:     date(5, 12, 0),
+			wantErr:     ErrWrongType,
+			wantRefills: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+
+			p, err := database.AddPrescription(ctx, db.AddPrescriptionParams{
+				HRTType:   string(hrttest.Daily.Type),
+				Refills:   1,
+				WrittenAt: date(1, 0, 0),
+				ExpiresAt: date(10, 0, 0),
+				AddedAt:   date(1, 0, 0),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			arg := db.AddStockParams{
+				HRTType:        string(hrttest.Daily.Type),
+				Quantity:       30,
+				Remaining:      30,
+				AddedAt:        date(2, 0, 0),
+				PrescriptionID: sql.NullInt64{Int64: p.ID, Valid: true},
+			}
+			for range test.fills {
+				if _, err := AddStock(ctx, database, time.UTC, arg); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			arg.AddedAt = test.addedAt
+			if test.hrtType != "" {
+				arg.HRTType = string(test.hrtType)
+			}
+			if _, err := AddStock(ctx, database, time.UTC, arg); !errors.Is(err, test.wantErr) {
+				t.Errorf("AddStock() = %v, want %v", err, test.wantErr)
+			}
+
+			p, err = database.Prescription(ctx, p.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Refills != test.wantRefills {
+				t.Errorf("prescription has %d refills left, want %d", p.Refills, test.wantRefills)
+			}
+		})
+	}
+}
+
+func TestRenewalsDue(t *testing.T) {
+	regimen := hrttest.Daily
+	regimen.RenewDays = 7
+
+	// The prescription can be filled until the end of the 20th, so the
+	// renewal reminder is due from the start of the 14th.
+	prescription := func(fills, refills int64) []Prescription {
+		return []Prescription{{
+			Prescription: db.Prescription{ID: 1, Refills: refills, ExpiresAt: date(20, 0, 0)},
+			Fills:        fills,
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		prescriptions []Prescription
+		now           time.Time
+		want          []RenewalReason
+	}{
+		{
+			name: "no prescriptions",
+			now:  date(14, 0, 0),
+		},
+		{
+			name:          "not due",
+			prescriptions: prescription(1, 1),
+			now:           date(13, 23, 0),
+		},
+		{
+			name:          "expiring",
+			prescriptions: prescription(1, 1),
+			now:           date(14, 0, 0),
+			want:          []RenewalReason{RenewalExpiring},
+		},
+		{
+			name:          "expired",
+			prescriptions: prescription(1, 1),
+			now:           date(25, 0, 0),
+			want:          []RenewalReason{RenewalExpiring},
+		},
+		{
+			name:          "no refills left",
+			prescriptions: prescription(2, 0),
+			now:           date(5, 0, 0),
+			want:          []RenewalReason{RenewalNoRefills},
+		},
+		{
+			name:          "not filled yet",
+			prescriptions: prescription(0, 0),
+			now:           date(5, 0, 0),
+		},
+		{
+			name:          "expiring without refills",
+			prescriptions: prescription(2, 0),
+			now:           date(15, 0, 0),
+			want:          []RenewalReason{RenewalExpiring, RenewalNoRefills},
+		},
+		{
+			name: "older prescription",
+			prescriptions: append(prescription(0, 3), Prescription{
+				Prescription: db.Prescription{ID: 2, ExpiresAt: date(1, 0, 0)},
+				Fills:        1,
+			}),
+			now: date(5, 0, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []RenewalReason
+			for _, renewal := range RenewalsDue(regimen, test.prescriptions, test.now) {
+				if renewal.ID != 1 {
+					t.Errorf("renewal of prescription %d, want the current one", renewal.ID)
+				}
+				got = append(got, renewal.Reason)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("RenewalsDue() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package journal
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/stats"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+)
+
+// AnalyzedEntry is an entry with the predicted level and the last dose at the
+// time it was written.
+type AnalyzedEntry struct {
+	Entry
+	// Level is the predicted level when the entry was written. It is zero if
+	// the type has no predictor.
+	Level float64
+	// LastDoseAt is the time of the last dose before the entry was written,
+	// or zero if there was none.
+	LastDoseAt time.Time
+	// SinceDose is how long after LastDoseAt the entry was written.
+	SinceDose time.Duration
+}
+
+// TagSummary summarizes the entries with a tag.
+type TagSummary struct {
+	Tag   string
+	Count int
+	// MeanMood is the mean mood of the entries that have one, or zero if
+	// none do.
+	MeanMood float64
+	// MeanLevel is the mean predicted level of the entries, or zero if there
+	// are no predicted levels.
+	MeanLevel float64
+	// MeanSinceDose is the mean time since the last dose of the entries
+	// written after a dose.
+	MeanSinceDose time.Duration
+}
+
+// Analysis relates the journal entries to the predicted levels and the doses.
+type Analysis struct {
+	HRTType hrtclicker.HRTType
+	From    time.Time
+	To      time.Time
+	Entries []AnalyzedEntry
+	// Tags summarizes the entries of each tag, most common first.
+	Tags []TagSummary
+	// MoodLevelCorrelation is the correlation between the mood and the
+	// predicted level of the entries that have both, from -1 to 1. It is nil
+	// if there are too few of them.
+	MoodLevelCorrelation *float64
+}
+
+// Analyze analyzes the entries of the regimen written between from and to. If
+// tag is not empty, only the entries with that tag are analyzed.
+func Analyze(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time, tag string) (Analysis, error) {
+	analysis := Analysis{
+		HRTType: regimen.Type,
+		From:    from,
+		To:      to,
+		Entries: []AnalyzedEntry{},
+		Tags:    []TagSummary{},
+	}
+
+	entries, err := Between(ctx, database, regimen.Type, from, to, tag)
+	if err != nil {
+		return analysis, err
+	}
+	if len(entries) == 0 {
+		return analysis, nil
+	}
+
+	first := entries[0].WrittenAt
+	last := entries[len(entries)-1].WrittenAt.Add(time.Hour)
+
+	applications, err := applicationsBetween(ctx, database, regimen, first, last)
+	if err != nil {
+		return analysis, err
+	}
+
+	var levels []predict.TimeValue
+	if _, ok := predict.ForType(regimen.Type); ok {
+		lookback, _ := predict.Lookback(regimen.Type)
+		removals, err := removal.Between(ctx, database, regimen.Type, first.Add(-lookback), last)
+		if err != nil {
+			return analysis, err
+		}
+		levels, err = predict.Regimen(regimen, applications, removals, first, last)
+		if err != nil {
+			return analysis, err
+		}
+	}
+
+	for _, entry := range entries {
+		a := AnalyzedEntry{Entry: entry}
+		a.Level, _ = predict.At(levels, entry.WrittenAt)
+
+		// The number of doses up to and including the time of the entry.
+		n, found := slices.BinarySearchFunc(applications, entry.WrittenAt, time.Time.Compare)
+		if found {
+			n++
+		}
+		if n > 0 {
+			a.LastDoseAt = applications[n-1]
+			a.SinceDose = entry.WrittenAt.Sub(a.LastDoseAt)
+		}
+
+		analysis.Entries = append(analysis.Entries, a)
+	}
+
+	analysis.Tags = summarizeTags(analysis.Entries)
+
+	var moods, moodLevels []float64
+	for _, entry := range analysis.Entries {
+		if entry.Mood.Valid && entry.Level > 0 {
+			moods = append(moods, float64(entry.Mood.Int64))
+			moodLevels = append(moodLevels, entry.Level)
+		}
+	}
+	if r := stats.Correlation(moods, moodLevels); len(moods) >= 3 && !math.IsNaN(r) {
+		analysis.MoodLevelCorrelation = &r
+	}
+
+	return analysis, nil
+}
+
+// applicationsBetween returns the times of the doses that affect the levels
+// between from and to, oldest first. It includes the last dose before them,
+// so that every entry in the range has a previous dose if there is one.
+func applicationsBetween(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time) ([]time.Time, error) {
+	lookback, _ := predict.Lookback(regimen.Type)
+
+	history, err := database.DosesBetween(ctx, string(regimen.Type), from.Add(-lookback), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dosage history: %w", err)
+	}
+
+	applications := make([]time.Time, len(history))
+	for i, dose := range history {
+		applications[i] = dose.DosageAt
+	}
+	return applications, nil
+}
+
+func summarizeTags(entries []AnalyzedEntry) []TagSummary {
+	type tagValues struct {
+		count  int
+		moods  []float64
+		levels []float64
+		since  []float64
+	}
+
+	byTag := make(map[string]*tagValues)
+	for _, entry := range entries {
+		for _, tag := range entry.Tags {
+			v, ok := byTag[tag]
+			if !ok {
+				v = &tagValues{}
+				byTag[tag] = v
+			}
+			v.count++
+			if entry.Mood.Valid {
+				v.moods = append(v.moods, float64(entry.Mood.Int64))
+			}
+			if entry.Level > 0 {
+				v.levels = append(v.levels, entry.Level)
+			}
+			if !entry.LastDoseAt.IsZero() {
+				v.since = append(v.since, float64(entry.SinceDose))
+			}
+		}
+	}
+
+	summaries := make([]TagSummary, 0, len(byTag))
+	for tag, v := range byTag {
+		summaries = append(summaries, TagSummary{
+			Tag:           tag,
+			Count:         v.count,
+			MeanMood:      meanOrZero(v.moods),
+			MeanLevel:     meanOrZero(v.levels),
+			MeanSinceDose: time.Duration(meanOrZero(v.since)).Round(time.Minute),
+		})
+	}
+
+	slices.SortFunc(summaries, func(a, b TagSummary) int {
+		return cmp.Or(
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Tag, b.Tag),
+		)
+	})
+	return summaries
+}
+
+func meanOrZero(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	return stats.Mean(xs)
+}
@@ -0,0 +1,136 @@
+// Package journal keeps a journal of how the user feels, with a mood and the
+// symptoms they had as tags, and relates its entries to the predicted levels
+// and the doses.
+package journal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+const (
+	// MinMood is the worst mood.
+	MinMood = 1
+	// MaxMood is the best mood.
+	MaxMood = 5
+)
+
+// CommonTags are the tags that are offered on the quick entry form. Any other
+// tag can be used too.
+var CommonTags = []string{
+	"hot flashes",
+	"breast tenderness",
+	"fatigue",
+	"headache",
+	"nausea",
+	"mood swings",
+	"insomnia",
+	"low libido",
+}
+
+// Entry is a journal entry with its tags.
+type Entry struct {
+	db.JournalEntry
+	Tags db.Tags
+}
+
+// Get returns the entry with the given ID.
+func Get(ctx context.Context, database *db.SQLiteDB, id int64) (Entry, error) {
+	entry, err := database.JournalEntry(ctx, id)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	tags, err := database.JournalEntryTags(ctx, id)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	return Entry{entry, db.NewTags(tags...)}, nil
+}
+
+// Add adds an entry with the given tags.
+func Add(ctx context.Context, database *db.SQLiteDB, arg db.AddJournalEntryParams, tags db.Tags) (Entry, error) {
+	tags = db.NewTags(tags...)
+
+	var entry db.JournalEntry
+	err := database.Tx(func(q *db.Queries) error {
+		var err error
+		entry, err = q.AddJournalEntry(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return addTags(ctx, q, entry.ID, tags)
+	})
+	return Entry{entry, tags}, err
+}
+
+// Update updates an entry and replaces its tags.
+func Update(ctx context.Context, database *db.SQLiteDB, arg db.UpdateJournalEntryParams, tags db.Tags) (Entry, error) {
+	tags = db.NewTags(tags...)
+
+	var entry db.JournalEntry
+	err := database.Tx(func(q *db.Queries) error {
+		var err error
+		entry, err = q.UpdateJournalEntry(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if err := q.DeleteJournalTags(ctx, entry.ID); err != nil {
+			return fmt.Errorf("failed to delete tags: %w", err)
+		}
+		return addTags(ctx, q, entry.ID, tags)
+	})
+	return Entry{entry, tags}, err
+}
+
+func addTags(ctx context.Context, q *db.Queries, id int64, tags db.Tags) error {
+	for _, tag := range tags {
+		if err := q.AddJournalTag(ctx, db.AddJournalTagParams{EntryID: id, Tag: tag}); err != nil {
+			return fmt.Errorf("failed to add tag %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// Between returns the entries of the given type written between from and to,
+// oldest first. If tag is not empty, only the entries with that tag are
+// returned.
+func Between(ctx context.Context, database *db.SQLiteDB, hrtType hrtclicker.HRTType, from, to time.Time, tag string) ([]Entry, error) {
+	entries, err := database.JournalEntriesBetween(ctx, db.JournalEntriesBetweenParams{
+		HRTType: string(hrtType),
+		Since:   from.UTC(),
+		Before:  to.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal entries: %w", err)
+	}
+
+	tags, err := database.JournalTagsBetween(ctx, db.JournalTagsBetweenParams{
+		HRTType: string(hrtType),
+		Since:   from.UTC(),
+		Before:  to.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal tags: %w", err)
+	}
+
+	tagsOf := make(map[int64][]string)
+	for _, t := range tags {
+		tagsOf[t.EntryID] = append(tagsOf[t.EntryID], t.Tag)
+	}
+
+	out := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		e := Entry{entry, db.NewTags(tagsOf[entry.ID]...)}
+		if tag != "" && !e.Tags.Has(tag) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
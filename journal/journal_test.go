@@ -0,0 +1,160 @@
+package journal
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+// addEntry adds an entry of patches with the given mood, or none if it is
+// zero.
+func addEntry(t *testing.T, database *db.SQLiteDB, writtenAt time.Time, mood int64, tags ...string) Entry {
+	t.Helper()
+
+	entry, err := Add(context.Background(), database, db.AddJournalEntryParams{
+		HRTType:   string(hrtclicker.TypePatches),
+		WrittenAt: writtenAt,
+		Mood:      sql.NullInt64{Int64: mood, Valid: mood != 0},
+		AddedAt:   writtenAt,
+	}, tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestAddUpdate(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	entry := addEntry(t, database, date(1, 20, 0), 3, "Fatigue", "headache", "fatigue")
+	if !slices.Equal(entry.Tags, []string{"fatigue", "headache"}) {
+		t.Errorf("Add() tags = %q, want them normalized", entry.Tags)
+	}
+
+	updated, err := Update(ctx, database, db.UpdateJournalEntryParams{
+		ID:        entry.ID,
+		WrittenAt: date(1, 21, 0),
+		Notes:     "better",
+	}, []string{"insomnia"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Get(ctx, database, entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Tags, []string{"insomnia"}) || got.Notes != "better" || got.Mood.Valid {
+		t.Errorf("Get() after Update() = %+v, want %+v", got, updated)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	database := hrttest.OpenDB(t)
+	addEntry(t, database, date(1, 20, 0), 3, "fatigue")
+	addEntry(t, database, date(2, 20, 0), 4)
+	addEntry(t, database, date(3, 20, 0), 2, "fatigue", "headache")
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		tag      string
+		want     []time.Time
+	}{
+		{"all", date(1, 0, 0), db.EndOfTime, "", []time.Time{date(1, 20, 0), date(2, 20, 0), date(3, 20, 0)}},
+		{"range", date(2, 0, 0), date(3, 0, 0), "", []time.Time{date(2, 20, 0)}},
+		{"tag", date(1, 0, 0), db.EndOfTime, "Fatigue", []time.Time{date(1, 20, 0), date(3, 20, 0)}},
+		{"unused tag", date(1, 0, 0), db.EndOfTime, "nausea", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := Between(context.Background(), database, hrtclicker.TypePatches, test.from, test.to, test.tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []time.Time
+			for _, entry := range entries {
+				got = append(got, entry.WrittenAt)
+				if entry.Tags == nil {
+					t.Errorf("entry at %s has nil tags", entry.WrittenAt)
+				}
+			}
+			if !slices.EqualFunc(got, test.want, time.Time.Equal) {
+				t.Errorf("Between() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	regimen := hrtclicker.HRTConfig{
+		Type:        hrtclicker.TypePatches,
+		Interval:    cfgtypes.Duration(84 * time.Hour),
+		Concurrence: 1,
+	}
+	for _, dosageAt := range []time.Time{date(1, 8, 0), date(4, 20, 0)} {
+		if err := database.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: dosageAt,
+			HRTType:  string(regimen.Type),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addEntry(t, database, date(1, 6, 0), 0, "fatigue")
+	addEntry(t, database, date(2, 8, 0), 2, "fatigue", "headache")
+	addEntry(t, database, date(3, 8, 0), 3)
+	addEntry(t, database, date(5, 8, 0), 4, "headache")
+
+	analysis, err := Analyze(ctx, database, regimen, date(1, 0, 0), db.EndOfTime, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(analysis.Entries) != 4 {
+		t.Fatalf("Analyze() has %d entries, want 4", len(analysis.Entries))
+	}
+	first, second := analysis.Entries[0], analysis.Entries[1]
+	if !first.LastDoseAt.IsZero() || first.Level != 0 {
+		t.Errorf("entry before the first dose = %+v, want no dose and no level", first)
+	}
+	if !second.LastDoseAt.Equal(date(1, 8, 0)) || second.SinceDose != 24*time.Hour || second.Level <= 0 {
+		t.Errorf("entry after a dose = %+v, want a level a day after the dose", second)
+	}
+
+	wantTags := []TagSummary{
+		{Tag: "fatigue", Count: 2, MeanMood: 2},
+		{Tag: "headache", Count: 2, MeanMood: 3},
+	}
+	if len(analysis.Tags) != len(wantTags) {
+		t.Fatalf("Analyze() tags = %+v, want %+v", analysis.Tags, wantTags)
+	}
+	for i, want := range wantTags {
+		got := analysis.Tags[i]
+		if got.Tag != want.Tag || got.Count != want.Count || got.MeanMood != want.MeanMood {
+			t.Errorf("tag %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if analysis.Tags[0].MeanSinceDose != 24*time.Hour {
+		t.Errorf("fatigue MeanSinceDose = %s, want only the entry after a dose", analysis.Tags[0].MeanSinceDose)
+	}
+
+	if analysis.MoodLevelCorrelation == nil {
+		t.Error("Analyze() has no correlation with 3 entries with a mood and a level")
+	}
+}
@@ -0,0 +1,427 @@
+// Package labs schedules blood draws and keeps their results. Draws are meant
+// to catch a point of the dose cycle, such as the trough right before the next
+// dose, and the window for it is found from the dose schedule and the predicted
+// levels.
+package labs
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/chart"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+)
+
+// Target is the point of the dose cycle a draw is meant to catch.
+type Target string
+
+const (
+	// TargetTrough is the level right before the next dose, which is the
+	// lowest once the levels are steady.
+	TargetTrough Target = "trough"
+	// TargetPeak is the highest level after a dose.
+	TargetPeak Target = "peak"
+)
+
+// IsValid returns true if the target is one of the known targets.
+func (t Target) IsValid() bool {
+	return t == TargetTrough || t == TargetPeak
+}
+
+// ErrNoDoses is returned when finding draw windows before any doses were
+// recorded.
+var ErrNoDoses = errors.New("no doses recorded yet")
+
+const (
+	// windowTolerance is how far from the lowest or highest level of a dose
+	// interval the level may be within its draw window, relative to it.
+	windowTolerance = 0.05
+	// troughWindow is the draw window for a trough before the next dose for
+	// types that have no predictor.
+	troughWindow = 2 * time.Hour
+	// maxWindows is the most windows that are looked at, in case the
+	// interval is very short.
+	maxWindows = 1000
+)
+
+// Window is the time range within a dose interval in which blood should be
+// drawn for a target.
+type Window struct {
+	Target Target
+	// Start and End are the bounds of the window. A trough window always ends
+	// when the next dose is due.
+	Start time.Time
+	End   time.Time
+	// Ideal is the hour of the trough or peak within the window.
+	Ideal time.Time
+	// Level is the predicted level at Ideal. It is zero if the type has no
+	// predictor.
+	Level float64
+	// DoseAt is the time of the dose starting the interval the window is in.
+	DoseAt time.Time
+	// NextDoseAt is the time the dose ending the interval is due.
+	NextDoseAt time.Time
+}
+
+// Contains returns true if t is within the window.
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Windows returns the next n draw windows for the target that haven't ended by
+// after. The given applications are the doses that affect the levels from the
+// last one on, and every dose after them is assumed to be taken when due, or
+// right away if it is overdue. The applications are assumed to stay on until
+// they run out, since removed ones are replaced.
+func Windows(regimen hrtclicker.HRTConfig, applications []time.Time, after time.Time, target Target, n int) ([]Window, error) {
+	if len(applications) == 0 {
+		return nil, ErrNoDoses
+	}
+	if !target.IsValid() {
+		return nil, fmt.Errorf("unknown target %q", target)
+	}
+
+	_, hasPredictor := predict.ForType(regimen.Type)
+	if target == TargetPeak && !hasPredictor {
+		return nil, fmt.Errorf("the peak of %s cannot be predicted", regimen.Type)
+	}
+
+	last := slices.MaxFunc(applications, time.Time.Compare)
+	due := []time.Time{last}
+
+	next := regimen.NextDoseAt(last)
+	if next.Before(after) {
+		next = after.Truncate(time.Minute)
+	}
+	for len(due) <= min(n+1, maxWindows) {
+		due = append(due, next)
+		next = regimen.NextDoseAt(next)
+	}
+
+	var windows []Window
+	if hasPredictor {
+		levels, err := predict.Regimen(regimen, append(slices.Clone(applications), due[1:]...), nil, due[0], due[len(due)-1])
+		if err != nil {
+			return nil, err
+		}
+		for k := 1; k < len(due); k++ {
+			if w, ok := predictedWindow(levels, target, due[k-1], due[k]); ok {
+				windows = append(windows, w)
+			}
+		}
+	} else {
+		for k := 1; k < len(due); k++ {
+			windows = append(windows, Window{
+				Target:     target,
+				Start:      due[k].Add(-troughWindow),
+				End:        due[k],
+				Ideal:      due[k].Add(-troughWindow / 2),
+				DoseAt:     due[k-1],
+				NextDoseAt: due[k],
+			})
+		}
+	}
+
+	windows = slices.DeleteFunc(windows, func(w Window) bool { return !w.End.After(after) })
+	if len(windows) > n {
+		windows = windows[:n]
+	}
+	return windows, nil
+}
+
+// predictedWindow finds the window for the target within the dose interval
+// from doseAt until nextDoseAt using the predicted levels. The window is made
+// of the hours around the trough or peak level that are within windowTolerance
+// of it.
+func predictedWindow(levels []predict.TimeValue, target Target, doseAt, nextDoseAt time.Time) (Window, bool) {
+	first, _ := slices.BinarySearchFunc(levels, predict.Timestamp(doseAt.Unix()), compareTime)
+	end, _ := slices.BinarySearchFunc(levels, predict.Timestamp(nextDoseAt.Unix()), compareTime)
+	interval := levels[first:end]
+	if len(interval) == 0 {
+		return Window{}, false
+	}
+
+	// The trough is right before the next dose, even if the levels are still
+	// rising after a break. The peak is the highest level, and ties go to the
+	// earliest hour.
+	best := len(interval) - 1
+	if target == TargetPeak {
+		best = 0
+		for i, v := range interval {
+			if v.V > interval[best].V {
+				best = i
+			}
+		}
+	}
+	near := func(v float64) bool {
+		return math.Abs(v-interval[best].V) <= interval[best].V*windowTolerance
+	}
+
+	lo, hi := best, best
+	for lo > 0 && near(interval[lo-1].V) {
+		lo--
+	}
+	for hi < len(interval)-1 && near(interval[hi+1].V) {
+		hi++
+	}
+
+	w := Window{
+		Target:     target,
+		Start:      interval[lo].T.Time(),
+		End:        interval[hi].T.Time().Add(time.Hour),
+		Ideal:      interval[best].T.Time(),
+		Level:      interval[best].V,
+		DoseAt:     doseAt,
+		NextDoseAt: nextDoseAt,
+	}
+	if w.End.After(nextDoseAt) || target == TargetTrough {
+		w.End = nextDoseAt
+	}
+	return w, true
+}
+
+func compareTime(v predict.TimeValue, t predict.Timestamp) int {
+	return cmp.Compare(v.T, t)
+}
+
+// Appointment is a lab appointment with its draw window and result.
+type Appointment struct {
+	db.LabAppointment
+	// Window is the draw window of the dose interval the appointment is in.
+	// It is nil for past appointments and if it cannot be found.
+	Window *Window
+	// Result is the result of the draw, or nil if there is none yet.
+	Result *db.LabResult
+}
+
+// InWindow returns true if the appointment is within its draw window.
+func (a Appointment) InWindow() bool {
+	return a.Window != nil && a.Window.Contains(a.ScheduledAt)
+}
+
+// Result is a lab result with the level predicted at the time of the draw.
+type Result struct {
+	db.LabResult
+	// Target is the target of the appointment the result is linked to. It is
+	// empty if there is none.
+	Target Target
+	// Predicted is the predicted level at the time of the draw. It is zero if
+	// it isn't known.
+	Predicted float64
+}
+
+// Labs is the lab appointments and results of a regimen.
+type Labs struct {
+	HRTType hrtclicker.HRTType
+	// TroughWindows and PeakWindows are the upcoming draw windows for each
+	// target. They are empty if they cannot be found.
+	TroughWindows []Window
+	PeakWindows   []Window
+	// Appointments is every appointment, latest first.
+	Appointments []Appointment
+	// Results is every result, most recently drawn first.
+	Results []Result
+}
+
+// upcomingWindows is the number of upcoming windows loaded for each target.
+const upcomingWindows = 3
+
+// Load loads the lab appointments and results of the regimen, with the
+// windows of the upcoming appointments.
+func Load(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, now time.Time) (Labs, error) {
+	labs := Labs{HRTType: regimen.Type}
+
+	appointments, err := database.LabAppointments(ctx, string(regimen.Type))
+	if err != nil {
+		return labs, fmt.Errorf("failed to get lab appointments: %w", err)
+	}
+
+	results, err := database.LabResults(ctx, string(regimen.Type))
+	if err != nil {
+		return labs, fmt.Errorf("failed to get lab results: %w", err)
+	}
+
+	labs.Results, err = complete(ctx, database, regimen, results, appointments)
+	if err != nil {
+		return labs, err
+	}
+
+	lookback, _ := predict.Lookback(regimen.Type)
+	history, err := database.DosesBetween(ctx, string(regimen.Type), now.Add(-lookback), db.EndOfTime)
+	if err != nil {
+		return labs, fmt.Errorf("failed to get dosage history: %w", err)
+	}
+	applications := make([]time.Time, len(history))
+	for i, dose := range history {
+		applications[i] = dose.DosageAt
+	}
+
+	// Without doses or a predictor there are no windows to show, which is
+	// fine.
+	labs.TroughWindows, _ = Windows(regimen, applications, now, TargetTrough, upcomingWindows)
+	labs.PeakWindows, _ = Windows(regimen, applications, now, TargetPeak, upcomingWindows)
+
+	resultOf := make(map[int64]*db.LabResult)
+	for i, result := range results {
+		if result.AppointmentID.Valid {
+			resultOf[result.AppointmentID.Int64] = &results[i]
+		}
+	}
+
+	for _, appointment := range appointments {
+		a := Appointment{
+			LabAppointment: appointment,
+			Result:         resultOf[appointment.ID],
+		}
+		if appointment.ScheduledAt.After(now) {
+			a.Window = windowAt(regimen, applications, now, Target(appointment.Target), appointment.ScheduledAt)
+		}
+		labs.Appointments = append(labs.Appointments, a)
+	}
+
+	return labs, nil
+}
+
+// windowAt returns the window of the dose interval containing t, or nil if
+// there is none.
+func windowAt(regimen hrtclicker.HRTConfig, applications []time.Time, now time.Time, target Target, t time.Time) *Window {
+	interval := regimen.At(now).Interval.AsDuration()
+	if interval <= 0 {
+		return nil
+	}
+
+	n := min(int(t.Sub(now)/interval)+2, maxWindows)
+	windows, err := Windows(regimen, applications, now, target, n)
+	if err != nil {
+		return nil
+	}
+	for _, w := range windows {
+		if !t.Before(w.DoseAt) && t.Before(w.NextDoseAt) {
+			return &w
+		}
+	}
+	return nil
+}
+
+// ResultsBetween returns the lab results of the regimen drawn between from and
+// to, oldest first.
+func ResultsBetween(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time) ([]Result, error) {
+	results, err := database.LabResultsBetween(ctx, db.LabResultsBetweenParams{
+		HRTType: string(regimen.Type),
+		Since:   from.UTC(),
+		Before:  to.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lab results: %w", err)
+	}
+
+	var appointments []db.LabAppointment
+	for _, result := range results {
+		if !result.AppointmentID.Valid {
+			continue
+		}
+		appointment, err := database.LabAppointment(ctx, result.AppointmentID.Int64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lab appointment: %w", err)
+		}
+		appointments = append(appointments, appointment)
+	}
+
+	return complete(ctx, database, regimen, results, appointments)
+}
+
+// complete adds the targets of the appointments and the predicted levels to
+// the results.
+func complete(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, results []db.LabResult, appointments []db.LabAppointment) ([]Result, error) {
+	targets := make(map[int64]Target, len(appointments))
+	for _, appointment := range appointments {
+		targets[appointment.ID] = Target(appointment.Target)
+	}
+
+	completed := make([]Result, len(results))
+	for i, result := range results {
+		completed[i] = Result{LabResult: result}
+		if result.AppointmentID.Valid {
+			completed[i].Target = targets[result.AppointmentID.Int64]
+		}
+	}
+	if len(results) == 0 {
+		return completed, nil
+	}
+	if _, ok := predict.ForType(regimen.Type); !ok {
+		return completed, nil
+	}
+
+	from, to := results[0].DrawnAt, results[0].DrawnAt
+	for _, result := range results {
+		from = minTime(from, result.DrawnAt)
+		to = maxTime(to, result.DrawnAt)
+	}
+	to = to.Add(time.Hour)
+
+	lookback, _ := predict.Lookback(regimen.Type)
+	history, err := database.DosesBetween(ctx, string(regimen.Type), from.Add(-lookback), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dosage history: %w", err)
+	}
+	applications := make([]time.Time, len(history))
+	for i, dose := range history {
+		applications[i] = dose.DosageAt
+	}
+
+	removals, err := removal.Between(ctx, database, regimen.Type, from.Add(-lookback), to)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := predict.Regimen(regimen, applications, removals, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range completed {
+		completed[i].Predicted, _ = predict.At(levels, completed[i].DrawnAt)
+	}
+	return completed, nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// Points returns the results as points for the level charts.
+func Points(results []Result) []chart.Point {
+	points := make([]chart.Point, len(results))
+	for i, result := range results {
+		label := "Lab result"
+		switch result.Target {
+		case TargetTrough:
+			label = "Trough draw"
+		case TargetPeak:
+			label = "Peak draw"
+		}
+		points[i] = chart.Point{
+			Time:  result.DrawnAt,
+			Value: result.Value,
+			Label: label,
+		}
+	}
+	return points
+}
@@ -0,0 +1,251 @@
+package labs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/predict"
+)
+
+var date = hrttest.Date
+
+func TestWindows(t *testing.T) {
+	// Sublingual doses have no predictor, so the trough window is the two
+	// hours before each dose.
+	regimen := hrtclicker.HRTConfig{
+		Type:     hrtclicker.TypeSublingual,
+		Interval: cfgtypes.Duration(12 * time.Hour),
+	}
+	applications := []time.Time{date(1, 8, 0), date(0, 20, 0)}
+
+	window := func(doseAt, nextDoseAt time.Time) Window {
+		return Window{
+			Target:     TargetTrough,
+			Start:      nextDoseAt.Add(-2 * time.Hour),
+			End:        nextDoseAt,
+			Ideal:      nextDoseAt.Add(-time.Hour),
+			DoseAt:     doseAt,
+			NextDoseAt: nextDoseAt,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		applications []time.Time
+		target       Target
+		after        time.Time
+		n            int
+		want         []Window
+		wantErr      error
+	}{
+		{
+			name:         "upcoming",
+			applications: applications,
+			target:       TargetTrough,
+			after:        date(1, 9, 0),
+			n:            2,
+			want:         []Window{window(date(1, 8, 0), date(1, 20, 0)), window(date(1, 20, 0), date(2, 8, 0))},
+		},
+		{
+			name:         "within a window",
+			applications: applications,
+			target:       TargetTrough,
+			after:        date(1, 19, 0),
+			n:            1,
+			want:         []Window{window(date(1, 8, 0), date(1, 20, 0))},
+		},
+		{
+			// The overdue dose is assumed to be taken right away.
+			name:         "overdue",
+			applications: applications,
+			target:       TargetTrough,
+			after:        date(2, 0, 0),
+			n:            2,
+			want:         []Window{window(date(2, 0, 0), date(2, 12, 0)), window(date(2, 12, 0), date(3, 0, 0))},
+		},
+		{
+			name:    "no doses",
+			target:  TargetTrough,
+			after:   date(1, 9, 0),
+			n:       1,
+			wantErr: ErrNoDoses,
+		},
+		{
+			name:         "peak without a predictor",
+			applications: applications,
+			target:       TargetPeak,
+			after:        date(1, 9, 0),
+			n:            1,
+			wantErr:      errors.New("the peak of sublingual cannot be predicted"),
+		},
+		{
+			name:         "unknown target",
+			applications: applications,
+			target:       "nadir",
+			after:        date(1, 9, 0),
+			n:            1,
+			wantErr:      errors.New(`unknown target "nadir"`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Windows(regimen, test.applications, test.after, test.target, test.n)
+			switch {
+			case test.wantErr == nil && err != nil:
+				t.Fatalf("Windows() = %v", err)
+			case test.wantErr != nil && (err == nil || !errors.Is(err, test.wantErr) && err.Error() != test.wantErr.Error()):
+				t.Fatalf("Windows() = %v, want %v", err, test.wantErr)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Windows() =\n%+v\nwant\n%+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPredictedWindow(t *testing.T) {
+	hour := func(h int) time.Time { return date(1, h, 0) }
+
+	var levels []predict.TimeValue
+	for h, v := range []float64{10, 50, 100, 98, 60, 30, 20, 19.5, 19.2} {
+		levels = append(levels, predict.TimeValue{T: predict.Timestamp(hour(h).Unix()), V: v})
+	}
+
+	tests := []struct {
+		name       string
+		target     Target
+		doseAt     time.Time
+		nextDoseAt time.Time
+		want       Window
+		wantOK     bool
+	}{
+		{
+			// The trough window always ends at the next dose.
+			name:       "trough",
+			target:     TargetTrough,
+			doseAt:     hour(0),
+			nextDoseAt: hour(9),
+			want:       Window{Start: hour(6), End: hour(9), Ideal: hour(8), Level: 19.2},
+			wantOK:     true,
+		},
+		{
+			name:       "peak",
+			target:     TargetPeak,
+			doseAt:     hour(0),
+			nextDoseAt: hour(9),
+			want:       Window{Start: hour(2), End: hour(4), Ideal: hour(2), Level: 100},
+			wantOK:     true,
+		},
+		{
+			name:       "peak at the next dose",
+			target:     TargetPeak,
+			doseAt:     hour(0),
+			nextDoseAt: hour(3),
+			want:       Window{Start: hour(2), End: hour(3), Ideal: hour(2), Level: 100},
+			wantOK:     true,
+		},
+		{
+			name:       "outside the levels",
+			target:     TargetTrough,
+			doseAt:     hour(12),
+			nextDoseAt: hour(24),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want := test.want
+			if test.wantOK {
+				want.Target = test.target
+				want.DoseAt = test.doseAt
+				want.NextDoseAt = test.nextDoseAt
+			}
+
+			// The times of the levels are local.
+			got, ok := predictedWindow(levels, test.target, test.doseAt, test.nextDoseAt)
+			got.Start, got.End, got.Ideal = got.Start.UTC(), got.End.UTC(), got.Ideal.UTC()
+			if got != want || ok != test.wantOK {
+				t.Errorf("predictedWindow() = %+v, %v, want %+v, %v", got, ok, want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestReminderNotification(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	scheduledAt := time.Date(2026, 3, 28, 7, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		reminder Reminder
+		loc      *time.Location
+		want     hrtclicker.Notification
+	}{
+		{
+			name: "trough before the next dose",
+			reminder: Reminder{
+				LabAppointment: db.LabAppointment{HRTType: "patches", Target: "trough", ScheduledAt: scheduledAt},
+				NextDoseAt:     scheduledAt.Add(time.Hour),
+			},
+			loc: time.UTC,
+			want: hrtclicker.Notification{
+				Title:   "Lab draw Sat Mar 28 at 07:30",
+				Message: "Don't change your patch before your 07:30 draw.",
+			},
+		},
+		{
+			name: "trough after the next dose is due",
+			reminder: Reminder{
+				LabAppointment: db.LabAppointment{HRTType: "gel", Target: "trough", ScheduledAt: scheduledAt},
+				NextDoseAt:     scheduledAt.Add(-30 * time.Minute),
+			},
+			loc: time.UTC,
+			want: hrtclicker.Notification{
+				Title:   "Lab draw Sat Mar 28 at 07:30",
+				Message: "Don't apply your gel before your 07:30 draw. It's due at 07:00, so wait until after the draw.",
+			},
+		},
+		{
+			name: "peak with a note",
+			reminder: Reminder{
+				LabAppointment: db.LabAppointment{HRTType: "injection", Target: "peak", ScheduledAt: scheduledAt, Note: "Bring the lab order."},
+			},
+			loc: time.UTC,
+			want: hrtclicker.Notification{
+				Title:   "Lab draw Sat Mar 28 at 07:30",
+				Message: "Your peak draw is at 07:30. Bring the lab order.",
+			},
+		},
+		{
+			name: "time zone",
+			reminder: Reminder{
+				LabAppointment: db.LabAppointment{HRTType: "sublingual", Target: "trough", ScheduledAt: scheduledAt},
+			},
+			loc: berlin,
+			want: hrtclicker.Notification{
+				Title:   "Lab draw Sat Mar 28 at 08:30",
+				Message: "Don't take your next dose before your 08:30 draw.",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.reminder.Notification(test.loc)
+			if got.Title != test.want.Title || got.Message != test.want.Message {
+				t.Errorf("Notification() = %q: %q, want %q: %q", got.Title, got.Message, test.want.Title, test.want.Message)
+			}
+		})
+	}
+}
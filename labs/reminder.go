@@ -0,0 +1,59 @@
+package labs
+
+import (
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+// Reminder is a reminder of an upcoming lab appointment. It is the data of the
+// events.LabReminderDue event.
+type Reminder struct {
+	db.LabAppointment
+	// NextDoseAt is the time the next dose is due. It is zero if no doses were
+	// recorded yet or none is due, such as during a pause without an end.
+	NextDoseAt time.Time
+}
+
+// Notification returns the notification sent for the reminder, with the
+// times in the given time zone.
+func (r Reminder) Notification(loc *time.Location) hrtclicker.Notification {
+	at := r.ScheduledAt.In(loc)
+	action := doseAction(hrtclicker.HRTType(r.HRTType))
+
+	n := hrtclicker.Notification{
+		Title: fmt.Sprintf("Lab draw %s at %s", at.Format("Mon Jan 2"), at.Format("15:04")),
+	}
+
+	switch Target(r.Target) {
+	case TargetTrough:
+		n.Message = fmt.Sprintf("Don't %s before your %s draw.", action, at.Format("15:04"))
+		if !r.NextDoseAt.IsZero() && r.NextDoseAt.Before(r.ScheduledAt) {
+			n.Message += fmt.Sprintf(" It's due at %s, so wait until after the draw.",
+				r.NextDoseAt.In(loc).Format("15:04"))
+		}
+	default:
+		n.Message = fmt.Sprintf("Your %s draw is at %s.", r.Target, at.Format("15:04"))
+	}
+
+	if r.Note != "" {
+		n.Message += " " + r.Note
+	}
+	return n
+}
+
+// doseAction describes taking the next dose of the given type.
+func doseAction(t hrtclicker.HRTType) string {
+	switch t {
+	case hrtclicker.TypePatches:
+		return "change your patch"
+	case hrtclicker.TypeGel:
+		return "apply your gel"
+	case hrtclicker.TypeInjection:
+		return "take your injection"
+	default:
+		return "take your next dose"
+	}
+}
@@ -0,0 +1,48 @@
+// Package levels predicts the levels of a regimen from the doses and removals
+// recorded in the database. The server and the command line both use it, so
+// that they agree.
+package levels
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+)
+
+// Applications returns the times of the doses of the regimen that affect its
+// predicted levels between from and to, oldest first, and their removals.
+func Applications(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time) ([]time.Time, []predict.Removal, error) {
+	lookback, _ := predict.Lookback(regimen.Type)
+
+	history, err := database.DosesBetween(ctx, string(regimen.Type), from.Add(-lookback), to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dosage history: %w", err)
+	}
+
+	applications := make([]time.Time, len(history))
+	for i, dose := range history {
+		applications[i] = dose.DosageAt
+	}
+
+	removals, err := removal.Between(ctx, database, regimen.Type, from.Add(-lookback), to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return applications, removals, nil
+}
+
+// Between returns the predicted levels of the regimen between from and to. It
+// returns an error if they cannot be predicted for the regimen.
+func Between(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time) ([]predict.TimeValue, error) {
+	applications, removals, err := Applications(ctx, database, regimen, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return predict.Regimen(regimen, applications, removals, from, to)
+}
@@ -0,0 +1,97 @@
+package levels
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+)
+
+var patches = hrtclicker.HRTConfig{
+	Type:     hrtclicker.TypePatches,
+	Interval: cfgtypes.Duration(84 * time.Hour),
+}
+
+func recordDoses(t *testing.T, database *db.SQLiteDB, hrtType hrtclicker.HRTType, doses ...time.Time) {
+	t.Helper()
+
+	for _, dosageAt := range doses {
+		if err := database.RecordDosage(context.Background(), db.RecordDosageParams{
+			DosageAt: dosageAt,
+			HRTType:  string(hrtType),
+			Tags:     db.NewTags(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestApplications(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	from, to := hrttest.Date(20, 0, 0), hrttest.Date(22, 0, 0)
+	lookback, _ := predict.Lookback(patches.Type)
+	since := from.Add(-lookback)
+
+	// The dose before the lookback still counts, since it may not have worn
+	// off when the lookback starts.
+	recordDoses(t, database, patches.Type,
+		since.Add(-48*time.Hour),
+		since.Add(-2*time.Hour),
+		from.Add(-2*time.Hour),
+		from.Add(10*time.Hour),
+		to.Add(time.Hour),
+	)
+	recordDoses(t, database, hrtclicker.TypeGel, from)
+	if _, err := removal.Add(ctx, database, patches, from.Add(-2*time.Hour), from.Add(time.Hour), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	applications, removals, err := Applications(ctx, database, patches, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Time{since.Add(-2 * time.Hour), from.Add(-2 * time.Hour), from.Add(10 * time.Hour)}
+	if !slices.EqualFunc(applications, want, time.Time.Equal) {
+		t.Errorf("Applications() = %v, want %v", applications, want)
+	}
+	if len(removals) != 1 || !removals[0].AppliedAt.Equal(from.Add(-2*time.Hour)) || !removals[0].RemovedAt.Equal(from.Add(time.Hour)) {
+		t.Errorf("Applications() removals = %v, want the one of the dose before from", removals)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name       string
+		regimen    hrtclicker.HRTConfig
+		wantValues int
+		wantErr    bool
+	}{
+		{"patches", patches, 48, false},
+		{"no level model", hrttest.Daily, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			database := hrttest.OpenDB(t)
+			recordDoses(t, database, test.regimen.Type, hrttest.Date(19, 8, 0))
+
+			got, err := Between(context.Background(), database, test.regimen, hrttest.Date(20, 0, 0), hrttest.Date(22, 0, 0))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Between() = %v, want an error: %v", err, test.wantErr)
+			}
+			if len(got) != test.wantValues {
+				t.Errorf("Between() returned %d values, want %d", len(got), test.wantValues)
+			}
+		})
+	}
+}
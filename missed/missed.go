@@ -0,0 +1,187 @@
+// Package missed advises what to do about a dose that wasn't taken on time,
+// following the missed dose policy of the regimen, and keeps track of the
+// doses that are skipped on purpose. A skipped dose is not missed, and the
+// dose after it is due as if the skipped one was taken when it was due.
+package missed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+)
+
+var (
+	// ErrAlreadySkipped is returned when skipping a dose that is already
+	// skipped.
+	ErrAlreadySkipped = errors.New("dose is already skipped")
+	// ErrNothingDue is returned when skipping the next dose while none is
+	// due, such as before the first dose or during a pause without an end.
+	ErrNothingDue = errors.New("no dose is due")
+)
+
+// Phase is where a pending dose is relative to when it is due.
+type Phase string
+
+const (
+	// PhaseUpcoming is the phase of a dose that is not due yet.
+	PhaseUpcoming Phase = "upcoming"
+	// PhaseOnTime is the phase of a dose within the on time window.
+	PhaseOnTime Phase = "on-time"
+	// PhaseLate is the phase of a dose that is late, but within the late
+	// window if the regimen has one.
+	PhaseLate Phase = "late"
+	// PhaseMissed is the phase of a dose after the late window.
+	PhaseMissed Phase = "missed"
+)
+
+// Action is what to do about a pending dose.
+type Action string
+
+const (
+	// ActionWait means the dose is not due yet.
+	ActionWait Action = "wait"
+	// ActionTake means the dose should be taken now.
+	ActionTake Action = "take"
+	// ActionSkip means the dose should be skipped and the next one taken
+	// when it is due.
+	ActionSkip Action = "skip"
+)
+
+// Advice is what to do about the pending dose.
+type Advice struct {
+	HRTType hrtclicker.HRTType
+	Phase   Phase
+	Action  Action
+	// DueAt is when the pending dose is due.
+	DueAt time.Time
+	// NextDueAt is when the dose after it is due if it is skipped or taken
+	// on time. It is zero if none is due, such as during a pause without an
+	// end.
+	NextDueAt time.Time
+	// Message tells what to do in a sentence or two. It is empty if there is
+	// nothing to do yet.
+	Message string
+}
+
+// Advise returns what to do at now about the dose due at dueAt, following
+// the windows and the missed dose policy of the regimen. nextDueAt is when the
+// dose after it is due, as the schedule moves it, or zero if none is.
+func Advise(regimen hrtclicker.HRTConfig, dueAt, nextDueAt, now time.Time) Advice {
+	advice := Advice{
+		HRTType:   regimen.Type,
+		DueAt:     dueAt,
+		NextDueAt: nextDueAt,
+	}
+
+	lateness := now.Sub(dueAt)
+	switch late := regimen.LateWindow.AsDuration(); {
+	case lateness < -regimen.OnTimeWithin():
+		advice.Phase = PhaseUpcoming
+	case lateness <= regimen.OnTimeWithin():
+		advice.Phase = PhaseOnTime
+	case late > 0 && lateness > late:
+		advice.Phase = PhaseMissed
+	default:
+		advice.Phase = PhaseLate
+	}
+
+	// A dose is skipped rather than taken once the next one is due soon, but
+	// once that one is due as well, only it is taken.
+	untilNext := advice.NextDueAt.Sub(now)
+	skipWithin := regimen.MissedDose.SkipWithin.AsDuration()
+
+	switch {
+	case advice.Phase == PhaseUpcoming:
+		advice.Action = ActionWait
+	case advice.Phase == PhaseOnTime:
+		advice.Action = ActionTake
+		advice.Message = "Take your dose now."
+	case skipWithin > 0 && !advice.NextDueAt.IsZero() && untilNext > 0 && untilNext <= skipWithin:
+		advice.Action = ActionSkip
+		advice.Message = fmt.Sprintf(
+			"Skip the dose that was due %s and take the next one %s as usual. Don't take two doses to make up for it.",
+			formatDue(dueAt, regimen.Location()), formatDue(advice.NextDueAt, regimen.Location()))
+	case advice.Phase == PhaseMissed:
+		advice.Action = ActionTake
+		advice.Message = fmt.Sprintf(
+			"You missed the dose that was due %s. Take it as soon as you remember, but don't take two doses to make up for it.",
+			formatDue(dueAt, regimen.Location()))
+	default:
+		advice.Action = ActionTake
+		advice.Message = "Your dose is late. Take it now."
+	}
+
+	return advice
+}
+
+func formatDue(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("Mon 15:04")
+}
+
+// Skip records that the dose of the regimen due at dueAt is skipped on
+// purpose. It returns ErrAlreadySkipped if a dose due within half an interval
+// of it already is.
+func Skip(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, dueAt, now time.Time, reason string) (db.Skip, error) {
+	half := regimen.At(dueAt).Interval.AsDuration() / 2
+	skips, err := Between(ctx, database, regimen.Type, dueAt.Add(-half), dueAt.Add(half))
+	if err != nil {
+		return db.Skip{}, err
+	}
+	if len(skips) > 0 {
+		return db.Skip{}, ErrAlreadySkipped
+	}
+
+	skip, err := database.AddSkip(ctx, db.AddSkipParams{
+		HRTType:   string(regimen.Type),
+		DueAt:     dueAt.UTC().Truncate(time.Second),
+		SkippedAt: now.UTC().Truncate(time.Second),
+		Reason:    reason,
+	})
+	if err != nil {
+		if db.IsAlreadyExists(err) {
+			return db.Skip{}, ErrAlreadySkipped
+		}
+		return db.Skip{}, fmt.Errorf("failed to add skip: %w", err)
+	}
+	return skip, nil
+}
+
+// ScheduleFrom returns the time the dose after the one taken at lastDoseAt is
+// due from. It is the due time of the last dose skipped since, or lastDoseAt
+// if none was.
+func ScheduleFrom(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, lastDoseAt time.Time) (time.Time, error) {
+	skip, err := database.LastSkipAfter(ctx, db.LastSkipAfterParams{
+		HRTType: string(t),
+		DueAt:   lastDoseAt.UTC(),
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			return lastDoseAt, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get skip: %w", err)
+	}
+	return skip.DueAt, nil
+}
+
+// Between returns the due times of the doses of the regimen of the given type
+// skipped between from and to, for comparing doses against the regimen.
+func Between(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, from, to time.Time) ([]time.Time, error) {
+	skips, err := database.SkipsBetween(ctx, db.SkipsBetweenParams{
+		HRTType: string(t),
+		Since:   from.UTC(),
+		Before:  to.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skips: %w", err)
+	}
+
+	times := make([]time.Time, len(skips))
+	for i, skip := range skips {
+		times[i] = skip.DueAt
+	}
+	return times, nil
+}
@@ -0,0 +1,239 @@
+package missed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+// daily is a regimen with a dose every day, on time within an hour, missed
+// after 6 hours and skipped within 8 hours of the next one.
+var daily = hrtclicker.HRTConfig{
+	Type:         hrtclicker.TypeSublingual,
+	Interval:     cfgtypes.Duration(24 * time.Hour),
+	OnTimeWindow: cfgtypes.Duration(time.Hour),
+	LateWindow:   cfgtypes.Duration(6 * time.Hour),
+	TimeZone:     "UTC",
+	MissedDose:   hrtclicker.MissedDosePolicy{SkipWithin: cfgtypes.Duration(8 * time.Hour)},
+}
+
+func TestAdvise(t *testing.T) {
+	dueAt := date(2, 8, 0)
+
+	tests := []struct {
+		name       string
+		regimen    func(hrtclicker.HRTConfig) hrtclicker.HRTConfig
+		nextDueAt  time.Time
+		now        time.Time
+		wantPhase  Phase
+		wantAction Action
+	}{
+		{
+			name:       "upcoming",
+			now:        date(2, 6, 0),
+			wantPhase:  PhaseUpcoming,
+			wantAction: ActionWait,
+		},
+		{
+			name:       "early within the on time window",
+			now:        date(2, 7, 0),
+			wantPhase:  PhaseOnTime,
+			wantAction: ActionTake,
+		},
+		{
+			name:       "late",
+			now:        date(2, 11, 0),
+			wantPhase:  PhaseLate,
+			wantAction: ActionTake,
+		},
+		{
+			name:       "missed",
+			now:        date(2, 15, 0),
+			wantPhase:  PhaseMissed,
+			wantAction: ActionTake,
+		},
+		{
+			name:       "next dose due soon",
+			now:        date(3, 1, 0),
+			wantPhase:  PhaseMissed,
+			wantAction: ActionSkip,
+		},
+		{
+			name:       "next dose due as well",
+			now:        date(3, 9, 0),
+			wantPhase:  PhaseMissed,
+			wantAction: ActionTake,
+		},
+		{
+			name:       "next dose moved later",
+			nextDueAt:  date(3, 20, 0),
+			now:        date(3, 1, 0),
+			wantPhase:  PhaseMissed,
+			wantAction: ActionTake,
+		},
+		{
+			name: "without a late window",
+			regimen: func(r hrtclicker.HRTConfig) hrtclicker.HRTConfig {
+				r.LateWindow = 0
+				return r
+			},
+			now:        date(2, 15, 0),
+			wantPhase:  PhaseLate,
+			wantAction: ActionTake,
+		},
+		{
+			name: "never skipped",
+			regimen: func(r hrtclicker.HRTConfig) hrtclicker.HRTConfig {
+				r.MissedDose.SkipWithin = 0
+				return r
+			},
+			now:        date(3, 1, 0),
+			wantPhase:  PhaseMissed,
+			wantAction: ActionTake,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			regimen := daily
+			if test.regimen != nil {
+				regimen = test.regimen(regimen)
+			}
+
+			nextDueAt := test.nextDueAt
+			if nextDueAt.IsZero() {
+				nextDueAt = date(3, 8, 0)
+			}
+
+			advice := Advise(regimen, dueAt, nextDueAt, test.now)
+			if advice.Phase != test.wantPhase || advice.Action != test.wantAction {
+				t.Errorf("Advise() = %v, %v, want %v, %v", advice.Phase, advice.Action, test.wantPhase, test.wantAction)
+			}
+			if !advice.NextDueAt.Equal(nextDueAt) {
+				t.Errorf("Advise().NextDueAt = %s, want %s", advice.NextDueAt, nextDueAt)
+			}
+			if (advice.Message == "") != (test.wantAction == ActionWait) {
+				t.Errorf("Advise().Message = %q for %v", advice.Message, test.wantAction)
+			}
+		})
+	}
+}
+
+func TestAdviseTimeZone(t *testing.T) {
+	dueAt := time.Date(2026, 10, 24, 14, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 24, 20, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		timeZone string
+		want     string
+	}{
+		{"UTC", "due Sat 14:00 and take the next one Sat 22:00"},
+		{"America/New_York", "due Sat 10:00 and take the next one Sat 18:00"},
+		{"Europe/Berlin", "due Sat 16:00 and take the next one Sun 00:00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.timeZone, func(t *testing.T) {
+			if _, err := time.LoadLocation(test.timeZone); err != nil {
+				t.Skip("no time zone database:", err)
+			}
+
+			regimen := hrtclicker.HRTConfig{
+				Type:       hrtclicker.TypeSublingual,
+				Interval:   cfgtypes.Duration(8 * time.Hour),
+				TimeZone:   test.timeZone,
+				MissedDose: hrtclicker.MissedDosePolicy{SkipWithin: cfgtypes.Duration(2 * time.Hour)},
+			}
+
+			advice := Advise(regimen, dueAt, dueAt.Add(8*time.Hour), now)
+			if advice.Action != ActionSkip {
+				t.Fatalf("Advise().Action = %v, want %v", advice.Action, ActionSkip)
+			}
+			if !strings.Contains(advice.Message, test.want) {
+				t.Errorf("Advise().Message = %q, want it to contain %q", advice.Message, test.want)
+			}
+		})
+	}
+}
+
+func TestSkip(t *testing.T) {
+	tests := []struct {
+		name    string
+		dueAt   time.Time
+		wantErr error
+	}{
+		{"other dose", date(3, 8, 0), nil},
+		{"same dose", date(2, 8, 0), ErrAlreadySkipped},
+		{"within half an interval", date(2, 19, 0), ErrAlreadySkipped},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			if _, err := Skip(ctx, database, daily, date(2, 8, 0), date(2, 20, 0), ""); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Skip(ctx, database, daily, test.dueAt, date(2, 20, 0), "")
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Skip() = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		skips      []time.Time
+		lastDoseAt time.Time
+		want       time.Time
+	}{
+		{
+			name:       "nothing skipped",
+			lastDoseAt: date(1, 8, 0),
+			want:       date(1, 8, 0),
+		},
+		{
+			name:       "skipped since",
+			skips:      []time.Time{date(2, 8, 0), date(3, 8, 0)},
+			lastDoseAt: date(1, 8, 0),
+			want:       date(3, 8, 0),
+		},
+		{
+			name:       "skipped before",
+			skips:      []time.Time{date(2, 8, 0)},
+			lastDoseAt: date(3, 8, 0),
+			want:       date(3, 8, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			for _, dueAt := range test.skips {
+				if _, err := Skip(ctx, database, daily, dueAt, dueAt, ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := ScheduleFrom(ctx, database, daily.Type, test.lastDoseAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("ScheduleFrom() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
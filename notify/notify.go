@@ -2,6 +2,8 @@ package notify
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -10,14 +12,22 @@ import (
 
 	"libdb.so/hrtclicker"
 	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
 	"libdb.so/hrtclicker/internal/notifier"
+	"libdb.so/hrtclicker/inventory"
+	"libdb.so/hrtclicker/labs"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/removal"
+	"libdb.so/hrtclicker/schedule"
 )
 
 // Dependencies is a set of dependencies required by the Monitor.
 type Dependencies struct {
-	Config   *hrtclicker.Config
+	Config   *hrtclicker.ReloadableConfig
 	Logger   *slog.Logger
 	Database *db.SQLiteDB
+	Events   *events.Bus
 }
 
 // Monitor is responsible for monitoring the HRT clicker application.
@@ -25,8 +35,6 @@ type Dependencies struct {
 // the gotify service configured in the dependency configuration.
 type Monitor struct {
 	Dependencies
-	titleTmpl   *template.Template
-	messageTmpl *template.Template
 }
 
 func NewMonitor(deps Dependencies) *Monitor {
@@ -37,15 +45,11 @@ const tickInterval = time.Minute
 
 // Run starts the monitoring process for the HRT clicker application until the
 // context is canceled.
-func (m *Monitor) Run(ctx context.Context) (err error) {
-	m.titleTmpl, err = template.New("title").Parse(m.Config.Gotify.Notification.Title)
-	if err != nil {
-		return fmt.Errorf("failed to parse title template: %w", err)
-	}
-
-	m.messageTmpl, err = template.New("message").Parse(m.Config.Gotify.Notification.Message)
-	if err != nil {
-		return fmt.Errorf("failed to parse message template: %w", err)
+func (m *Monitor) Run(ctx context.Context) error {
+	// Parse the templates once to fail early. They are parsed again for every
+	// notification in case the configuration is reloaded.
+	if _, _, err := parseTemplates(m.Config.Load()); err != nil {
+		return err
 	}
 
 	// TODO: make this more optimized
@@ -57,7 +61,28 @@ func (m *Monitor) Run(ctx context.Context) (err error) {
 		case <-ctx.Done():
 			return ctx.Err()
 		case now := <-ticker.C:
-			lastDose, err := m.Database.LastDose(ctx, string(m.Config.HRT.Type))
+			cfg := m.Config.Load()
+
+			// Lab appointments are still reminded of while paused, since
+			// they were scheduled explicitly.
+			m.checkLabs(ctx, now, cfg)
+
+			_, err := pause.Active(ctx, m.Database, cfg.HRT.Type, now)
+			switch {
+			case err == nil:
+				continue
+			case !db.IsNotFound(err):
+				m.Logger.Error(
+					"failed to get pause",
+					"err", err)
+				continue
+			}
+
+			m.checkRefill(ctx, now, cfg)
+			m.checkPrescription(ctx, now, cfg)
+			m.checkRemovals(ctx, now, cfg)
+
+			lastDose, err := m.Database.LastDose(ctx, string(cfg.HRT.Type))
 			if err != nil {
 				m.Logger.Error(
 					"failed to get last dose",
@@ -65,36 +90,475 @@ func (m *Monitor) Run(ctx context.Context) (err error) {
 				continue
 			}
 
-			nextDose := lastDose.DosageAt.Add(time.Duration(m.Config.HRT.Interval))
-			if now.Before(nextDose) {
+			nextDose, due, err := pause.NextDoseAt(ctx, m.Database, cfg.HRT, lastDose.DosageAt, now)
+			if err != nil {
+				m.Logger.Error(
+					"failed to get next dose",
+					"err", err)
+				continue
+			}
+			if !due || now.Before(nextDose) {
 				continue
 			}
 
-			if err := m.Database.MarkNotified(ctx, lastDose.DosageAt); err != nil {
-				if !db.IsAlreadyExists(err) {
-					m.Logger.Warn(
-						"failed to mark dose as notified",
-						"dosage_at", lastDose.DosageAt,
-						"err", err)
-				}
+			if !nextDose.Equal(cfg.HRT.NextDoseAt(lastDose.DosageAt)) {
+				// The dose was moved by a pause or a trip, so remind of it
+				// again if the reminder was already sent before it was due.
+				m.rearm(ctx, lastDose.DosageAt, nextDose)
+			}
+
+			data := hrtclicker.NotificationTemplateData{
+				LastDoseAt: lastDose.DosageAt.In(cfg.HRT.Location()),
+				NextDoseAt: nextDose.In(cfg.HRT.Location()),
+				HRTType:    cfg.HRT.Type,
+			}
+
+			overdueAfter := cfg.HRT.OverdueAfter.AsDuration()
+			if overdueAfter > 0 && !now.Before(nextDose.Add(overdueAfter)) {
+				m.markOverdue(ctx, data)
+			}
+
+			// The dose is due, so there is advice about it.
+			advice, err := schedule.Advise(ctx, m.Database, cfg.HRT, nextDose, now)
+			if err != nil {
+				m.Logger.Error(
+					"failed to get missed dose advice",
+					"err", err)
+				continue
+			}
+			if advice.Phase == missed.PhaseMissed || advice.Action == missed.ActionSkip {
+				m.checkMissed(ctx, cfg, lastDose.DosageAt, *advice)
+			}
+
+			if !m.shouldNotify(ctx, now, lastDose.DosageAt) {
 				continue
 			}
 
+			m.Events.Publish(events.ReminderDue, data)
+
 			m.Logger.Debug(
 				"sending gotify notification",
-				"endpoint", m.Config.Gotify.Endpoint)
+				"endpoint", cfg.Gotify.Endpoint)
+
+			m.sendNotification(ctx, cfg, data, *advice)
+		}
+	}
+}
+
+// NotificationSentData is the data of an events.NotificationSent event.
+type NotificationSentData struct {
+	hrtclicker.NotificationTemplateData
+	Title   string
+	Message string
+}
+
+// shouldNotify returns true if the reminder for the dose after the given last
+// dose should be sent now. It marks the dose as notified, so it only returns
+// true once per dose unless the reminder was snoozed.
+func (m *Monitor) shouldNotify(ctx context.Context, now, lastDoseAt time.Time) bool {
+	snoozedUntil, err := m.Database.SnoozedUntil(ctx, lastDoseAt)
+	switch {
+	case err == nil:
+		if now.Before(snoozedUntil) {
+			return false
+		}
+
+		// The snooze is over, so remind again even if we already have.
+		if err := m.Database.DeleteSnooze(ctx, lastDoseAt); err != nil {
+			m.Logger.Warn(
+				"failed to delete expired snooze",
+				"dosage_at", lastDoseAt,
+				"err", err)
+			return false
+		}
+
+		if err := m.Database.MarkNotified(ctx, lastDoseAt); err != nil && !db.IsAlreadyExists(err) {
+			m.Logger.Warn(
+				"failed to mark dose as notified",
+				"dosage_at", lastDoseAt,
+				"err", err)
+		}
+
+		return true
+
+	case db.IsNotFound(err):
+		if err := m.Database.MarkNotified(ctx, lastDoseAt); err != nil {
+			if !db.IsAlreadyExists(err) {
+				m.Logger.Warn(
+					"failed to mark dose as notified",
+					"dosage_at", lastDoseAt,
+					"err", err)
+			}
+			return false
+		}
+		return true
+
+	default:
+		m.Logger.Error(
+			"failed to get snooze",
+			"dosage_at", lastDoseAt,
+			"err", err)
+		return false
+	}
+}
+
+// rearm forgets the reminder, the overdue event and the missed dose reminder
+// for the dose after the given last dose if they were sent before the given
+// time, so that they are sent again.
+func (m *Monitor) rearm(ctx context.Context, lastDoseAt, before time.Time) {
+	if err := m.Database.UnmarkNotifiedBefore(ctx, db.UnmarkNotifiedBeforeParams{
+		DosageAt:   lastDoseAt,
+		NotifiedAt: sql.NullTime{Time: before.UTC(), Valid: true},
+	}); err != nil {
+		m.Logger.Warn(
+			"failed to unmark dose as notified",
+			"dosage_at", lastDoseAt,
+			"err", err)
+	}
+
+	if err := m.Database.UnmarkOverdueBefore(ctx, db.UnmarkOverdueBeforeParams{
+		DosageAt:   lastDoseAt,
+		NotifiedAt: sql.NullTime{Time: before.UTC(), Valid: true},
+	}); err != nil {
+		m.Logger.Warn(
+			"failed to unmark dose as overdue",
+			"dosage_at", lastDoseAt,
+			"err", err)
+	}
 
-			m.sendNotification(ctx, hrtclicker.NotificationTemplateData{
-				LastDoseAt: lastDose.DosageAt,
-				NextDoseAt: nextDose,
-				HRTType:    m.Config.HRT.Type,
-			})
+	if err := m.Database.UnmarkMissedBefore(ctx, db.UnmarkMissedBeforeParams{
+		DosageAt:   lastDoseAt,
+		NotifiedAt: sql.NullTime{Time: before.UTC(), Valid: true},
+	}); err != nil {
+		m.Logger.Warn(
+			"failed to unmark dose as missed",
+			"dosage_at", lastDoseAt,
+			"err", err)
+	}
+}
+
+// markOverdue publishes an events.DoseOverdue event for the dose after the
+// given last dose, unless it was already published.
+func (m *Monitor) markOverdue(ctx context.Context, data hrtclicker.NotificationTemplateData) {
+	if err := m.Database.MarkOverdue(ctx, data.LastDoseAt.UTC()); err != nil {
+		if !db.IsAlreadyExists(err) {
+			m.Logger.Warn(
+				"failed to mark dose as overdue",
+				"dosage_at", data.LastDoseAt,
+				"err", err)
 		}
+		return
+	}
+
+	m.Logger.Info(
+		"dose is overdue",
+		"next_dose_at", data.NextDoseAt)
+
+	m.Events.Publish(events.DoseOverdue, data)
+}
+
+// checkMissed sends the missed dose reminder with the given advice for the dose
+// after the given last dose, unless it was already sent.
+func (m *Monitor) checkMissed(ctx context.Context, cfg *hrtclicker.Config, lastDoseAt time.Time, advice missed.Advice) {
+	if err := m.Database.MarkMissed(ctx, lastDoseAt); err != nil {
+		if !db.IsAlreadyExists(err) {
+			m.Logger.Warn(
+				"failed to mark dose as missed",
+				"dosage_at", lastDoseAt,
+				"err", err)
+		}
+		return
+	}
+
+	m.Logger.Info(
+		"dose is missed",
+		"due_at", advice.DueAt,
+		"action", advice.Action)
+
+	m.Events.Publish(events.DoseMissed, advice)
+
+	notification := hrtclicker.Notification{
+		Title:   fmt.Sprintf("You missed your %s dose", cfg.HRT.Type),
+		Message: withAdvice("", advice),
+		Extras:  cfg.Gotify.Notification.Extras,
+	}
+
+	if err := notifier.Notify(ctx, cfg.Gotify.Endpoint, cfg.Gotify.Token, notification); err != nil {
+		m.Logger.Error(
+			"failed to send missed dose notification",
+			"err", err)
+	}
+}
+
+// checkRefill sends the refill reminder if the inventory of the regimen runs
+// out soon. It is only sent once until more stock is added.
+func (m *Monitor) checkRefill(ctx context.Context, now time.Time, cfg *hrtclicker.Config) {
+	inv, err := inventory.Load(ctx, m.Database, cfg.HRT, now)
+	if err != nil {
+		m.Logger.Error(
+			"failed to load inventory",
+			"err", err)
+		return
+	}
+
+	if !inv.Tracked() || !inv.NeedsRefill {
+		return
+	}
+
+	var latest int64
+	for _, stock := range inv.Stock {
+		latest = max(latest, stock.ID)
+	}
+
+	if err := m.Database.MarkRefillNotified(ctx, latest); err != nil {
+		if !db.IsAlreadyExists(err) {
+			m.Logger.Warn(
+				"failed to mark refill as notified",
+				"stock_id", latest,
+				"err", err)
+		}
+		return
+	}
+
+	m.Logger.Info(
+		"inventory runs out soon",
+		"runs_out_at", inv.RunsOutAt)
+
+	m.Events.Publish(events.RefillDue, inv.Status)
+
+	notification := hrtclicker.Notification{
+		Title: fmt.Sprintf("Time to refill your %s", cfg.HRT.Type),
+		Message: fmt.Sprintf(
+			"You have %g units left, enough for %d more doses. They run out around %s.",
+			inv.Remaining, inv.Doses, inv.RunsOutAt.In(cfg.HRT.Location()).Format("Mon Jan 2")),
+		Extras: cfg.Gotify.Notification.Extras,
+	}
+
+	if err := notifier.Notify(ctx, cfg.Gotify.Endpoint, cfg.Gotify.Token, notification); err != nil {
+		m.Logger.Error(
+			"failed to send refill notification",
+			"err", err)
 	}
 }
 
-func (m *Monitor) sendNotification(ctx context.Context, data hrtclicker.NotificationTemplateData) {
-	title, err := renderStringTemplate(m.titleTmpl, data)
+// checkPrescription sends a renewal reminder for each reason the current
+// prescription of the regimen needs renewing. Each is only sent once per
+// prescription.
+func (m *Monitor) checkPrescription(ctx context.Context, now time.Time, cfg *hrtclicker.Config) {
+	prescriptions, err := inventory.Prescriptions(ctx, m.Database, cfg.HRT.Type)
+	if err != nil {
+		m.Logger.Error(
+			"failed to load prescriptions",
+			"err", err)
+		return
+	}
+
+	for _, renewal := range inventory.RenewalsDue(cfg.HRT, prescriptions, now) {
+		if err := m.Database.MarkPrescriptionNotified(ctx, db.MarkPrescriptionNotifiedParams{
+			PrescriptionID: renewal.ID,
+			Reason:         string(renewal.Reason),
+		}); err != nil {
+			if !db.IsAlreadyExists(err) {
+				m.Logger.Warn(
+					"failed to mark prescription as notified",
+					"prescription_id", renewal.ID,
+					"err", err)
+			}
+			continue
+		}
+
+		m.Logger.Info(
+			"prescription needs renewing",
+			"prescription_id", renewal.ID,
+			"reason", renewal.Reason)
+
+		m.Events.Publish(events.PrescriptionRenewalDue, renewal)
+
+		notification := hrtclicker.Notification{
+			Title:  fmt.Sprintf("Time to renew your %s prescription", cfg.HRT.Type),
+			Extras: cfg.Gotify.Notification.Extras,
+		}
+		switch renewal.Reason {
+		case inventory.RenewalExpiring:
+			notification.Message = fmt.Sprintf(
+				"Your prescription for %s from %s expires on %s.",
+				renewal.Medication, renewal.Prescriber, renewal.ExpiresAt.In(cfg.HRT.Location()).Format("Mon Jan 2"))
+		case inventory.RenewalNoRefills:
+			notification.Message = fmt.Sprintf(
+				"Your prescription for %s from %s has no refills left.",
+				renewal.Medication, renewal.Prescriber)
+		}
+
+		if err := notifier.Notify(ctx, cfg.Gotify.Endpoint, cfg.Gotify.Token, notification); err != nil {
+			m.Logger.Error(
+				"failed to send prescription notification",
+				"err", err)
+		}
+	}
+}
+
+// checkLabs sends a reminder for every lab appointment coming up within the
+// lab_reminder of the regimen. Each is only sent once unless the appointment
+// is rescheduled.
+func (m *Monitor) checkLabs(ctx context.Context, now time.Time, cfg *hrtclicker.Config) {
+	appointments, err := m.Database.OpenLabAppointmentsBetween(ctx, db.OpenLabAppointmentsBetweenParams{
+		HRTType: string(cfg.HRT.Type),
+		Since:   now.UTC(),
+		Before:  now.Add(cfg.HRT.LabReminderBefore()).UTC(),
+	})
+	if err != nil {
+		m.Logger.Error(
+			"failed to get lab appointments",
+			"err", err)
+		return
+	}
+	if len(appointments) == 0 {
+		return
+	}
+
+	var nextDoseAt time.Time
+	lastDose, err := m.Database.LastDose(ctx, string(cfg.HRT.Type))
+	if err != nil && !db.IsNotFound(err) {
+		m.Logger.Error(
+			"failed to get last dose",
+			"err", err)
+		return
+	}
+	if err == nil {
+		// The next dose is moved by pauses like its reminder, and none is
+		// due during a pause without an end.
+		next, due, err := pause.NextDoseAt(ctx, m.Database, cfg.HRT, lastDose.DosageAt, now)
+		if err != nil {
+			m.Logger.Error(
+				"failed to get next dose",
+				"err", err)
+			return
+		}
+		if due {
+			nextDoseAt = next
+		}
+	}
+
+	for _, appointment := range appointments {
+		if err := m.Database.MarkLabNotified(ctx, appointment.ID); err != nil {
+			if !db.IsAlreadyExists(err) {
+				m.Logger.Warn(
+					"failed to mark lab appointment as notified",
+					"appointment_id", appointment.ID,
+					"err", err)
+			}
+			continue
+		}
+
+		m.Logger.Info(
+			"lab appointment coming up",
+			"appointment_id", appointment.ID,
+			"scheduled_at", appointment.ScheduledAt)
+
+		reminder := labs.Reminder{
+			LabAppointment: appointment,
+			NextDoseAt:     nextDoseAt,
+		}
+		m.Events.Publish(events.LabReminderDue, reminder)
+
+		notification := reminder.Notification(cfg.HRT.Location())
+		notification.Extras = cfg.Gotify.Notification.Extras
+
+		if err := notifier.Notify(ctx, cfg.Gotify.Endpoint, cfg.Gotify.Token, notification); err != nil {
+			m.Logger.Error(
+				"failed to send lab notification",
+				"err", err)
+		}
+	}
+}
+
+// checkRemovals sends a reminder for every patch that was taken off without a
+// new one being put on within the removal_reminder of the regimen. Each is only
+// sent once unless the removal is changed.
+func (m *Monitor) checkRemovals(ctx context.Context, now time.Time, cfg *hrtclicker.Config) {
+	if !removal.Removable(cfg.HRT.Type) {
+		return
+	}
+
+	removals, err := removal.Unreplaced(ctx, m.Database, cfg.HRT, now)
+	if err != nil {
+		m.Logger.Error(
+			"failed to get unreplaced removals",
+			"err", err)
+		return
+	}
+
+	for _, r := range removals {
+		if err := m.Database.MarkRemovalNotified(ctx, r.DosageAt); err != nil {
+			if !db.IsAlreadyExists(err) {
+				m.Logger.Warn(
+					"failed to mark removal as notified",
+					"dosage_at", r.DosageAt,
+					"err", err)
+			}
+			continue
+		}
+
+		m.Logger.Info(
+			"patch was removed without a new one",
+			"dosage_at", r.DosageAt,
+			"removed_at", r.RemovedAt)
+
+		m.Events.Publish(events.ReplacementDue, r)
+
+		notification := removal.Notification(r, cfg.HRT.Location())
+		notification.Extras = cfg.Gotify.Notification.Extras
+
+		if err := notifier.Notify(ctx, cfg.Gotify.Endpoint, cfg.Gotify.Token, notification); err != nil {
+			m.Logger.Error(
+				"failed to send removal notification",
+				"err", err)
+		}
+	}
+}
+
+// DefaultSnooze is the default duration to snooze a reminder for.
+const DefaultSnooze = 30 * time.Minute
+
+// Snooze postpones the reminder for the next dose of the given type until the
+// given time. If the reminder was already sent, it is sent again once the
+// snooze is over.
+func Snooze(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, until time.Time) (db.Snoozed, error) {
+	lastDose, err := database.LastDose(ctx, string(t))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return db.Snoozed{}, errors.New("no dose has been recorded yet")
+		}
+		return db.Snoozed{}, fmt.Errorf("failed to get last dose: %w", err)
+	}
+
+	snooze := db.Snoozed{
+		DosageAt:     lastDose.DosageAt,
+		SnoozedUntil: until.UTC().Truncate(time.Second),
+	}
+
+	if err := database.Snooze(ctx, db.SnoozeParams{
+		DosageAt:     snooze.DosageAt,
+		SnoozedUntil: snooze.SnoozedUntil,
+	}); err != nil {
+		return db.Snoozed{}, fmt.Errorf("failed to snooze: %w", err)
+	}
+
+	return snooze, nil
+}
+
+// sendNotification sends the reminder for the dose in data, with the advice
+// about it if it is no longer on time.
+func (m *Monitor) sendNotification(ctx context.Context, cfg *hrtclicker.Config, data hrtclicker.NotificationTemplateData, advice missed.Advice) {
+	titleTmpl, messageTmpl, err := parseTemplates(cfg)
+	if err != nil {
+		m.Logger.Error(
+			"failed to parse notification templates",
+			"err", err)
+		return
+	}
+
+	title, err := renderStringTemplate(titleTmpl, data)
 	if err != nil {
 		m.Logger.Error(
 			"failed to render title template",
@@ -103,7 +567,7 @@ func (m *Monitor) sendNotification(ctx context.Context, data hrtclicker.Notifica
 		return
 	}
 
-	message, err := renderStringTemplate(m.messageTmpl, data)
+	message, err := renderStringTemplate(messageTmpl, data)
 	if err != nil {
 		m.Logger.Error(
 			"failed to render message template",
@@ -111,24 +575,77 @@ func (m *Monitor) sendNotification(ctx context.Context, data hrtclicker.Notifica
 			"err", err)
 		return
 	}
+	message = withAdvice(message, advice)
 
 	notification := hrtclicker.Notification{
 		Title:   title,
 		Message: message,
-		Extras:  m.Config.Gotify.Notification.Extras,
+		Extras:  cfg.Gotify.Notification.Extras,
 	}
 
 	err = notifier.Notify(
 		ctx,
-		m.Config.Gotify.Endpoint,
-		m.Config.Gotify.Token,
+		cfg.Gotify.Endpoint,
+		cfg.Gotify.Token,
 		notification,
 	)
 	if err != nil {
 		m.Logger.Error(
 			"failed to send notification",
 			"err", err)
+		return
+	}
+
+	m.Events.Publish(events.NotificationSent, NotificationSentData{
+		NotificationTemplateData: data,
+		Title:                    title,
+		Message:                  message,
+	})
+}
+
+// withAdvice adds what to do about a dose that is no longer on time, following
+// the missed dose policy, to the message of a reminder for it. The message is
+// returned as is while the dose is on time, since the reminder already says to
+// take it.
+func withAdvice(message string, advice missed.Advice) string {
+	switch {
+	case advice.Phase == missed.PhaseUpcoming, advice.Phase == missed.PhaseOnTime, advice.Message == "":
+		return message
+	case message == "":
+		return advice.Message
+	default:
+		return message + "\n\n" + advice.Message
+	}
+}
+
+func parseTemplates(cfg *hrtclicker.Config) (title, message *template.Template, err error) {
+	title, err = template.New("title").Parse(cfg.Gotify.Notification.Title)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse title template: %w", err)
+	}
+
+	message, err = template.New("message").Parse(cfg.Gotify.Notification.Message)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse message template: %w", err)
 	}
+
+	return title, message, nil
+}
+
+// SendTest sends a test notification using the Gotify configuration in cfg.
+func SendTest(ctx context.Context, cfg *hrtclicker.Config) error {
+	notification := hrtclicker.Notification{
+		Title:   "Test Notification",
+		Message: "hi cutie! <3",
+		Extras:  cfg.Gotify.Notification.Extras,
+	}
+
+	return notifier.Notify(
+		ctx,
+		cfg.Gotify.Endpoint,
+		cfg.Gotify.Token,
+		notification,
+	)
 }
 
 func renderStringTemplate(tmpl *template.Template, data any) (string, error) {
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/labs"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+)
+
+func TestWithAdvice(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		advice  missed.Advice
+		want    string
+	}{
+		{
+			name:    "on time",
+			message: "Time for your dose.",
+			advice:  missed.Advice{Phase: missed.PhaseOnTime, Action: missed.ActionTake, Message: "Take your dose now."},
+			want:    "Time for your dose.",
+		},
+		{
+			name:    "late",
+			message: "Time for your dose.",
+			advice:  missed.Advice{Phase: missed.PhaseLate, Action: missed.ActionTake, Message: "Your dose is late. Take it now."},
+			want:    "Time for your dose.\n\nYour dose is late. Take it now.",
+		},
+		{
+			name:    "skip",
+			message: "Time for your dose.",
+			advice:  missed.Advice{Phase: missed.PhaseMissed, Action: missed.ActionSkip, Message: "Skip the dose."},
+			want:    "Time for your dose.\n\nSkip the dose.",
+		},
+		{
+			name:   "missed without a reminder",
+			advice: missed.Advice{Phase: missed.PhaseMissed, Action: missed.ActionTake, Message: "You missed the dose."},
+			want:   "You missed the dose.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := withAdvice(test.message, test.advice); got != test.want {
+				t.Errorf("withAdvice() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckLabs(t *testing.T) {
+	tests := []struct {
+		name   string
+		pauses [][2]time.Time
+		want   time.Time
+	}{
+		{
+			name: "not paused",
+			want: hrttest.Date(2, 8, 0),
+		},
+		{
+			name:   "due during a pause",
+			pauses: [][2]time.Time{{hrttest.Date(2, 0, 0), hrttest.Date(2, 9, 0)}},
+			want:   hrttest.Date(2, 9, 0),
+		},
+		{
+			name:   "paused without an end",
+			pauses: [][2]time.Time{{hrttest.Date(2, 0, 0), {}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			gotify := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer gotify.Close()
+
+			cfg := &hrtclicker.Config{HRT: hrttest.Daily}
+			cfg.Gotify.Endpoint = gotify.URL
+
+			database := hrttest.OpenDB(t)
+			if err := database.RecordDosage(ctx, db.RecordDosageParams{
+				DosageAt: hrttest.Date(1, 8, 0),
+				HRTType:  string(hrttest.Daily.Type),
+				Tags:     db.NewTags(),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range test.pauses {
+				if _, err := pause.Start(ctx, database, hrttest.Daily.Type, p[0], p[1], ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := database.AddLabAppointment(ctx, db.AddLabAppointmentParams{
+				HRTType:     string(hrttest.Daily.Type),
+				Target:      string(labs.TargetTrough),
+				ScheduledAt: hrttest.Date(2, 10, 0),
+				CreatedAt:   hrttest.Date(1, 0, 0),
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			bus := new(events.Bus)
+			ch := bus.Subscribe(ctx)
+
+			m := NewMonitor(Dependencies{
+				Config:   hrttest.Config(t, cfg),
+				Logger:   slog.Default(),
+				Database: database,
+				Events:   bus,
+			})
+			m.checkLabs(ctx, hrttest.Date(2, 0, 0), cfg)
+
+			select {
+			case ev := <-ch:
+				reminder := ev.Data.(labs.Reminder)
+				if !reminder.NextDoseAt.Equal(test.want) {
+					t.Errorf("reminder NextDoseAt = %s, want %s", reminder.NextDoseAt, test.want)
+				}
+			default:
+				t.Fatal("no lab reminder was published")
+			}
+		})
+	}
+}
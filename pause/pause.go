@@ -0,0 +1,173 @@
+// Package pause keeps track of the spans of time a regimen is paused, such as
+// before surgery or during a planned break. While a regimen is paused, no
+// doses of it are due: its reminders are not sent, and its doses are not
+// counted as missed. A dose that falls due during a pause, or that is still
+// not taken when a pause starts, is due when the pause ends instead.
+package pause
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/adherence"
+	"libdb.so/hrtclicker/chart"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/travel"
+)
+
+var (
+	// ErrEndBeforeStart is returned when a pause would end before it starts.
+	ErrEndBeforeStart = errors.New("pause must end after it starts")
+	// ErrOverlaps is returned when a pause would overlap another pause of the
+	// same regimen.
+	ErrOverlaps = errors.New("pause overlaps another pause")
+	// ErrNotPaused is returned when resuming a regimen that is not paused.
+	ErrNotPaused = errors.New("regimen is not paused")
+)
+
+// Start pauses the regimen of the given type from startedAt until endedAt. A
+// zero endedAt pauses it until it is resumed.
+func Start(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, startedAt, endedAt time.Time, reason string) (db.Pause, error) {
+	startedAt = startedAt.UTC().Truncate(time.Second)
+	endedAt = endedAt.UTC().Truncate(time.Second)
+	if !endedAt.IsZero() && !endedAt.After(startedAt) {
+		return db.Pause{}, ErrEndBeforeStart
+	}
+
+	before := endedAt
+	if before.IsZero() {
+		before = db.EndOfTime
+	}
+
+	overlapping, err := database.PausesBetween(ctx, db.PausesBetweenParams{
+		HRTType: string(t),
+		Before:  before,
+		Since:   sql.NullTime{Time: startedAt, Valid: true},
+	})
+	if err != nil {
+		return db.Pause{}, fmt.Errorf("failed to get pauses: %w", err)
+	}
+	if len(overlapping) > 0 {
+		return db.Pause{}, ErrOverlaps
+	}
+
+	return database.AddPause(ctx, db.AddPauseParams{
+		HRTType:   string(t),
+		StartedAt: startedAt,
+		EndedAt:   sql.NullTime{Time: endedAt, Valid: !endedAt.IsZero()},
+		Reason:    reason,
+		AddedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+}
+
+// Resume ends the pause the regimen of the given type is in at now. It
+// returns ErrNotPaused if the regimen is not paused.
+func Resume(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, now time.Time) (db.Pause, error) {
+	active, err := database.ActivePause(ctx, db.ActivePauseParams{
+		HRTType: string(t),
+		At:      now.UTC(),
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			return db.Pause{}, ErrNotPaused
+		}
+		return db.Pause{}, fmt.Errorf("failed to get pause: %w", err)
+	}
+
+	return database.EndPause(ctx, db.EndPauseParams{
+		EndedAt: sql.NullTime{Time: now.UTC().Truncate(time.Second), Valid: true},
+		ID:      active.ID,
+	})
+}
+
+// Active returns the pause the regimen of the given type is in at now. It
+// returns a not found error if the regimen is not paused.
+func Active(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, now time.Time) (db.Pause, error) {
+	return database.ActivePause(ctx, db.ActivePauseParams{
+		HRTType: string(t),
+		At:      now.UTC(),
+	})
+}
+
+// Between returns the pauses of the regimen of the given type that overlap
+// the time between from and to, oldest first.
+func Between(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, from, to time.Time) ([]db.Pause, error) {
+	pauses, err := database.PausesBetween(ctx, db.PausesBetweenParams{
+		HRTType: string(t),
+		Before:  to.UTC(),
+		Since:   sql.NullTime{Time: from.UTC(), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pauses: %w", err)
+	}
+	return pauses, nil
+}
+
+// Spans converts the pauses for comparing doses against the regimen.
+func Spans(pauses []db.Pause) []adherence.Pause {
+	spans := make([]adherence.Pause, len(pauses))
+	for i, p := range pauses {
+		spans[i] = adherence.Pause{StartedAt: p.StartedAt}
+		if p.EndedAt.Valid {
+			spans[i].EndedAt = p.EndedAt.Time
+		}
+	}
+	return spans
+}
+
+// ChartSpans returns the pauses as shaded spans for the level charts.
+func ChartSpans(pauses []db.Pause) []chart.Span {
+	spans := make([]chart.Span, len(pauses))
+	for i, p := range pauses {
+		label := "Paused"
+		if p.Reason != "" {
+			label += ": " + p.Reason
+		}
+		spans[i] = chart.Span{
+			From:  p.StartedAt,
+			To:    p.EndedAt.Time,
+			Label: label,
+		}
+	}
+	return spans
+}
+
+// SpansBetween returns the pauses of the regimen of the given type that
+// overlap the time between from and to, for comparing doses against the
+// regimen.
+func SpansBetween(ctx context.Context, database *db.SQLiteDB, t hrtclicker.HRTType, from, to time.Time) ([]adherence.Pause, error) {
+	pauses, err := Between(ctx, database, t, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return Spans(pauses), nil
+}
+
+// NextDoseAt returns when the dose after the one taken at lastDoseAt is due at
+// now, following the doses skipped since and the trip it is shifted by, if
+// any. It is moved to the end of the pause it falls due in or that started
+// before now while it was due, if any. It returns false if it is due after a
+// pause that has no end.
+func NextDoseAt(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, lastDoseAt, now time.Time) (time.Time, bool, error) {
+	from, err := missed.ScheduleFrom(ctx, database, regimen.Type, lastDoseAt)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	due, err := travel.NextDoseAt(ctx, database, regimen, from)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	pauses, err := SpansBetween(ctx, database, regimen.Type, lastDoseAt, db.EndOfTime)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	due, ok := adherence.AfterPauses(pauses, due, now)
+	return due, ok, nil
+}
@@ -0,0 +1,204 @@
+package pause
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		hrtType   hrtclicker.HRTType
+		startedAt time.Time
+		endedAt   time.Time
+		wantErr   error
+	}{
+		{
+			name:      "before",
+			startedAt: date(1, 0, 0),
+			endedAt:   date(5, 0, 0),
+		},
+		{
+			name:      "after",
+			startedAt: date(10, 0, 0),
+		},
+		{
+			name:      "ending before it starts",
+			startedAt: date(3, 0, 0),
+			endedAt:   date(2, 0, 0),
+			wantErr:   ErrEndBeforeStart,
+		},
+		{
+			name:      "ending when it starts",
+			startedAt: date(3, 0, 0),
+			endedAt:   date(3, 0, 0),
+			wantErr:   ErrEndBeforeStart,
+		},
+		{
+			name:      "overlapping",
+			startedAt: date(8, 0, 0),
+			endedAt:   date(12, 0, 0),
+			wantErr:   ErrOverlaps,
+		},
+		{
+			name:      "without an end before another",
+			startedAt: date(1, 0, 0),
+			wantErr:   ErrOverlaps,
+		},
+		{
+			name:      "other regimen",
+			hrtType:   hrtclicker.TypeGel,
+			startedAt: date(8, 0, 0),
+			endedAt:   date(12, 0, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			if _, err := Start(ctx, database, hrttest.Daily.Type, date(5, 0, 0), date(10, 0, 0), "surgery"); err != nil {
+				t.Fatal(err)
+			}
+
+			hrtType := test.hrtType
+			if hrtType == "" {
+				hrtType = hrttest.Daily.Type
+			}
+			p, err := Start(ctx, database, hrtType, test.startedAt, test.endedAt, "")
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Start() = %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !p.StartedAt.Equal(test.startedAt) || p.EndedAt.Valid != !test.endedAt.IsZero() || !p.EndedAt.Time.Equal(test.endedAt) {
+				t.Errorf("Start() = %+v, want from %s until %s", p, test.startedAt, test.endedAt)
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	tests := []struct {
+		name    string
+		now     time.Time
+		wantErr error
+	}{
+		{"paused", date(6, 12, 0), nil},
+		{"before the pause", date(4, 0, 0), ErrNotPaused},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			if _, err := Start(ctx, database, hrttest.Daily.Type, date(5, 0, 0), time.Time{}, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := Resume(ctx, database, hrttest.Daily.Type, test.now)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Resume() = %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !p.EndedAt.Valid || !p.EndedAt.Time.Equal(test.now) {
+				t.Errorf("Resume() ended the pause at %v, want %s", p.EndedAt, test.now)
+			}
+			if _, err := Active(ctx, database, hrttest.Daily.Type, test.now); !db.IsNotFound(err) {
+				t.Errorf("Active() after resuming = %v, want not found", err)
+			}
+		})
+	}
+}
+
+func TestNextDoseAt(t *testing.T) {
+	lastDoseAt := date(1, 8, 0)
+
+	tests := []struct {
+		name   string
+		pauses [][2]time.Time
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "not paused",
+			now:    date(1, 12, 0),
+			want:   date(2, 8, 0),
+			wantOK: true,
+		},
+		{
+			name:   "due during a pause",
+			pauses: [][2]time.Time{{date(2, 0, 0), date(3, 0, 0)}},
+			now:    date(1, 12, 0),
+			want:   date(3, 0, 0),
+			wantOK: true,
+		},
+		{
+			name:   "pause starting while due",
+			pauses: [][2]time.Time{{date(2, 10, 0), date(3, 0, 0)}},
+			now:    date(2, 12, 0),
+			want:   date(3, 0, 0),
+			wantOK: true,
+		},
+		{
+			name:   "pause starting later",
+			pauses: [][2]time.Time{{date(2, 10, 0), date(3, 0, 0)}},
+			now:    date(2, 9, 0),
+			want:   date(2, 8, 0),
+			wantOK: true,
+		},
+		{
+			name:   "pause without an end",
+			pauses: [][2]time.Time{{date(2, 0, 0), {}}},
+			now:    date(1, 12, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			for _, p := range test.pauses {
+				if _, err := Start(ctx, database, hrttest.Daily.Type, p[0], p[1], ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, ok, err := NextDoseAt(ctx, database, hrttest.Daily, lastDoseAt, test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != test.wantOK || (ok && !got.Equal(test.want)) {
+				t.Errorf("NextDoseAt() = %s, %v, want %s, %v", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestChartSpans(t *testing.T) {
+	pauses := []db.Pause{
+		{StartedAt: date(1, 0, 0), Reason: "surgery"},
+		{StartedAt: date(5, 0, 0)},
+	}
+	pauses[0].EndedAt.Time, pauses[0].EndedAt.Valid = date(3, 0, 0), true
+
+	spans := ChartSpans(pauses)
+	if spans[0].Label != "Paused: surgery" || !spans[0].To.Equal(date(3, 0, 0)) {
+		t.Errorf("ChartSpans()[0] = %+v", spans[0])
+	}
+	if spans[1].Label != "Paused" || !spans[1].To.IsZero() {
+		t.Errorf("ChartSpans()[1] = %+v, want a span without an end", spans[1])
+	}
+}
@@ -0,0 +1,268 @@
+// Package predict predicts hormone levels from dosage history. It is a port of
+// the TypeScript predictor in web/static/hrtplotter/predict.ts.
+package predict
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/web/static/hrtplotter/values"
+)
+
+// Timestamp is the Unix time in seconds.
+type Timestamp int64
+
+// Time returns the timestamp as a time.Time.
+func (t Timestamp) Time() time.Time {
+	return time.Unix(int64(t), 0)
+}
+
+// TimeValue is a pair of a timestamp and a value. Its JSON representation is
+// the same as the TypeScript TimeValue type.
+type TimeValue struct {
+	T Timestamp `json:"t"`
+	V float64   `json:"v"`
+}
+
+// Options contains the options for predicting levels.
+type Options struct {
+	// Interval is the expected interval between applications.
+	Interval time.Duration
+	// Concurrence is the number of applications that are concurrently active;
+	// for patches, this is the normal number of patches on the skin at any
+	// point in time.
+	Concurrence int
+	// From is the time to start predicting from.
+	From time.Time
+	// To is the time to stop predicting at.
+	To time.Time
+	// Removals are the applications that were removed, such as patches that
+	// fell off. They stop contributing to the levels once removed.
+	Removals []Removal
+	// Strength scales the levels of each application. Zero uses 1.
+	Strength float64
+	// Changes are the changes to the options above for the applications from
+	// a given time on, sorted by that time.
+	Changes []Change
+}
+
+// Change replaces the interval, concurrence and strength of the applications
+// from Since on.
+type Change struct {
+	Since       time.Time
+	Interval    time.Duration
+	Concurrence int
+	Strength    float64
+}
+
+// at returns the interval, concurrence and strength of an application at t.
+func (o Options) at(t time.Time) Change {
+	c := Change{
+		Interval:    o.Interval,
+		Concurrence: o.Concurrence,
+		Strength:    o.Strength,
+	}
+	for _, change := range o.Changes {
+		if change.Since.After(t) {
+			break
+		}
+		c = change
+	}
+	if c.Strength <= 0 {
+		c.Strength = 1
+	}
+	return c
+}
+
+// Removal is an application that was removed at RemovedAt. AppliedAt is the
+// time of the application.
+type Removal struct {
+	AppliedAt time.Time
+	RemovedAt time.Time
+}
+
+// Predictor predicts the levels over time for the given application times.
+type Predictor interface {
+	Predict(applications []time.Time, opts Options) []TimeValue
+	// Lookback returns how long a single application affects the levels.
+	Lookback() time.Duration
+}
+
+// ForType returns the predictor for the given HRT type. False is returned if
+// the type has no known predictor.
+func ForType(t hrtclicker.HRTType) (Predictor, bool) {
+	switch t {
+	case hrtclicker.TypePatches:
+		return Patches, true
+	default:
+		return nil, false
+	}
+}
+
+// Lookback returns how long before the start of a prediction the applications
+// of the given type still affect the predicted levels. Applications older than
+// that can be left out. False is returned if the type has no known predictor.
+func Lookback(t hrtclicker.HRTType) (time.Duration, bool) {
+	predictor, ok := ForType(t)
+	if !ok {
+		return 0, false
+	}
+	return predictor.Lookback(), true
+}
+
+// Regimen predicts the levels between from and to of the given regimen using
+// the given application times and the removals of those applications. Each
+// application is predicted with the regimen in effect when it was applied.
+func Regimen(regimen hrtclicker.HRTConfig, applications []time.Time, removals []Removal, from, to time.Time) ([]TimeValue, error) {
+	predictor, ok := ForType(regimen.Type)
+	if !ok {
+		return nil, fmt.Errorf("no predictor for type %q", regimen.Type)
+	}
+
+	changes := make([]Change, len(regimen.Changes))
+	for i, change := range regimen.Changes {
+		r := regimen.At(change.EffectiveAt)
+		changes[i] = Change{
+			Since:       change.EffectiveAt,
+			Interval:    r.Interval.AsDuration(),
+			Concurrence: r.Concurrence,
+			Strength:    r.DoseStrength(),
+		}
+	}
+
+	return predictor.Predict(applications, Options{
+		Interval:    regimen.Interval.AsDuration(),
+		Concurrence: regimen.Concurrence,
+		From:        from,
+		To:          to,
+		Removals:    removals,
+		Strength:    regimen.DoseStrength(),
+		Changes:     changes,
+	}), nil
+}
+
+// Patches is the predictor for estrogen patches.
+var Patches = NewDiscreteHourlyPredictor(values.Patches, 1.0)
+
+// DiscreteHourlyPredictor is a Predictor that predicts the levels using a
+// discrete list of values. It specifically only supports hourly values and
+// will return hourly values.
+type DiscreteHourlyPredictor struct {
+	values []float64
+	scale  float64
+}
+
+var _ Predictor = (*DiscreteHourlyPredictor)(nil)
+
+// NewDiscreteHourlyPredictor creates a new DiscreteHourlyPredictor. The
+// hourlyValues are the levels of a single application for each hour since it
+// was applied.
+func NewDiscreteHourlyPredictor(hourlyValues []float64, scale float64) *DiscreteHourlyPredictor {
+	return &DiscreteHourlyPredictor{
+		values: hourlyValues,
+		scale:  scale,
+	}
+}
+
+func (p *DiscreteHourlyPredictor) f(h int) float64 {
+	if h < 0 || h >= len(p.values) {
+		return 0
+	}
+	return p.values[h] * p.scale
+}
+
+// Lookback implements Predictor.
+func (p *DiscreteHourlyPredictor) Lookback() time.Duration {
+	return time.Duration(len(p.values)) * time.Hour
+}
+
+// Predict implements Predictor.
+func (p *DiscreteHourlyPredictor) Predict(applications []time.Time, opts Options) []TimeValue {
+	if len(applications) == 0 || !opts.From.Before(opts.To) {
+		return nil
+	}
+
+	applications = sortedTimes(applications)
+
+	f1 := len(p.values)
+
+	prior := opts.From.Unix()
+	hours := int(math.Ceil(opts.To.Sub(opts.From).Hours()))
+	values := make([]float64, hours)
+
+	removedAt := make(map[int64]int64, len(opts.Removals))
+	for _, removal := range opts.Removals {
+		removedAt[removal.AppliedAt.Unix()] = removal.RemovedAt.Unix()
+	}
+
+	for i, t := range applications {
+		params := opts.at(t)
+		// f1short specifically is the f1 value but limited to the concurrence
+		// value. This value will only be used for patches older than the last
+		// few current patches.
+		f1short := f1
+		if params.Concurrence > 0 {
+			f1short = min(f1, int(params.Interval.Hours()*float64(params.Concurrence)))
+		}
+
+		// Calculate the hourly index for the current application time by
+		// subtracting it with the first time and rounding it.
+		hourStart := int(math.Floor(float64(t.Unix()-prior) / 3600))
+		hourEnd := hourStart + f1short
+		// If the patch index is near the end, then we should use f1, else we
+		// use f1short.
+		if len(applications)-i <= params.Concurrence {
+			hourEnd = hourStart + f1
+		}
+		// A removed application contributes until the last hour that starts
+		// before it was removed.
+		if removed, ok := removedAt[t.Unix()]; ok {
+			hourEnd = min(hourEnd, int(math.Ceil(float64(removed-prior)/3600)))
+		}
+
+		if hourEnd < 0 {
+			// The entire interval is before the start of the prediction
+			// period, so we ignore it.
+			continue
+		}
+
+		for h := max(0, hourStart); h < min(hours, hourEnd); h++ {
+			values[h] += p.f(h-hourStart) * params.Strength
+		}
+	}
+
+	timeValues := make([]TimeValue, len(values))
+	for i, v := range values {
+		timeValues[i] = TimeValue{
+			T: Timestamp(prior + int64(i)*3600),
+			V: v,
+		}
+	}
+	return timeValues
+}
+
+func sortedTimes(times []time.Time) []time.Time {
+	sorted := make([]time.Time, len(times))
+	copy(sorted, times)
+	slices.SortFunc(sorted, func(a, b time.Time) int { return a.Compare(b) })
+	return sorted
+}
+
+// At returns the value at the given time. The value of the hour containing the
+// time is returned. False is returned if the time is out of range.
+func At(values []TimeValue, t time.Time) (float64, bool) {
+	i, found := slices.BinarySearchFunc(values, Timestamp(t.Unix()), func(v TimeValue, t Timestamp) int {
+		return cmp.Compare(v.T, t)
+	})
+	if !found {
+		i--
+	}
+	if i < 0 || i >= len(values) || t.Unix()-int64(values[i].T) >= 3600 {
+		return 0, false
+	}
+	return values[i].V, true
+}
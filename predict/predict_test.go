@@ -0,0 +1,169 @@
+package predict
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+)
+
+// from is the start of the predictions in the tests.
+var from = time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC)
+
+func hours(h float64) time.Time {
+	return from.Add(time.Duration(h * float64(time.Hour)))
+}
+
+func TestDiscreteHourlyPredictorPredict(t *testing.T) {
+	predictor := NewDiscreteHourlyPredictor([]float64{4, 3, 2, 1}, 1)
+
+	tests := []struct {
+		name         string
+		applications []time.Time
+		opts         Options
+		want         []float64
+	}{
+		{
+			name:         "single application",
+			applications: []time.Time{hours(1)},
+			opts:         Options{Interval: 2 * time.Hour, Concurrence: 1},
+			want:         []float64{0, 4, 3, 2, 1, 0},
+		},
+		{
+			name:         "older application limited to the concurrence",
+			applications: []time.Time{hours(2), hours(0)},
+			opts:         Options{Interval: 2 * time.Hour, Concurrence: 1},
+			want:         []float64{4, 3, 4, 3, 2, 1},
+		},
+		{
+			name:         "without concurrence",
+			applications: []time.Time{hours(0), hours(2)},
+			opts:         Options{Interval: 2 * time.Hour},
+			want:         []float64{4, 3, 6, 4, 2, 1},
+		},
+		{
+			name:         "application before the start",
+			applications: []time.Time{hours(-3)},
+			opts:         Options{Interval: 24 * time.Hour},
+			want:         []float64{1, 0, 0, 0, 0, 0},
+		},
+		{
+			name:         "removed application",
+			applications: []time.Time{hours(1)},
+			opts: Options{
+				Interval:    24 * time.Hour,
+				Concurrence: 1,
+				Removals:    []Removal{{AppliedAt: hours(1), RemovedAt: hours(2.5)}},
+			},
+			want: []float64{0, 4, 3, 0, 0, 0},
+		},
+		{
+			name:         "strength",
+			applications: []time.Time{hours(1)},
+			opts:         Options{Interval: 24 * time.Hour, Strength: 2},
+			want:         []float64{0, 8, 6, 4, 2, 0},
+		},
+		{
+			name:         "change of strength",
+			applications: []time.Time{hours(0), hours(3)},
+			opts: Options{
+				Interval: 3 * time.Hour,
+				Changes:  []Change{{Since: hours(3), Interval: 3 * time.Hour, Strength: 0.5}},
+			},
+			want: []float64{4, 3, 2, 3, 1.5, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.opts.From = from
+			test.opts.To = hours(6)
+
+			got := predictor.Predict(test.applications, test.opts)
+			values := make([]float64, len(got))
+			for i, tv := range got {
+				if want := Timestamp(hours(float64(i)).Unix()); tv.T != want {
+					t.Errorf("Predict()[%d].T = %d, want %d", i, tv.T, want)
+				}
+				values[i] = tv.V
+			}
+			if !slices.Equal(values, test.want) {
+				t.Errorf("Predict() = %v, want %v", values, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscreteHourlyPredictorPredictEmpty(t *testing.T) {
+	predictor := NewDiscreteHourlyPredictor([]float64{4, 3, 2, 1}, 1)
+
+	tests := []struct {
+		name         string
+		applications []time.Time
+		to           time.Time
+	}{
+		{"no applications", nil, hours(6)},
+		{"empty range", []time.Time{hours(1)}, from},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := predictor.Predict(test.applications, Options{From: from, To: test.to})
+			if got != nil {
+				t.Errorf("Predict() = %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	values := []TimeValue{
+		{T: Timestamp(hours(0).Unix()), V: 1},
+		{T: Timestamp(hours(1).Unix()), V: 2},
+		{T: Timestamp(hours(2).Unix()), V: 3},
+	}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		want   float64
+		wantOK bool
+	}{
+		{"start of an hour", hours(1), 2, true},
+		{"within an hour", hours(1.5), 2, true},
+		{"last hour", hours(2.99), 3, true},
+		{"before the values", hours(-0.5), 0, false},
+		{"after the values", hours(3), 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := At(values, test.at)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("At(%s) = %v, %v, want %v, %v", test.at, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestForType(t *testing.T) {
+	tests := []struct {
+		hrtType hrtclicker.HRTType
+		want    bool
+	}{
+		{hrtclicker.TypePatches, true},
+		{hrtclicker.TypeSublingual, false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.hrtType), func(t *testing.T) {
+			if _, ok := ForType(test.hrtType); ok != test.want {
+				t.Errorf("ForType(%q) = %v, want %v", test.hrtType, ok, test.want)
+			}
+			if _, ok := Lookback(test.hrtType); ok != test.want {
+				t.Errorf("Lookback(%q) = %v, want %v", test.hrtType, ok, test.want)
+			}
+		})
+	}
+}
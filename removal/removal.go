@@ -0,0 +1,182 @@
+// Package removal keeps track of applications, such as patches, that were taken
+// off before they ran out, such as because they fell off. A removed
+// application stops contributing to the predicted levels, and a reminder is
+// sent if no new one was applied after it.
+package removal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/predict"
+)
+
+var (
+	// ErrNotRemovable is returned when removing a dose of a type that cannot
+	// be taken off.
+	ErrNotRemovable = errors.New("only patches can be removed")
+	// ErrWrongType is returned when removing a dose of a different regimen.
+	ErrWrongType = errors.New("dose is of a different type")
+	// ErrBeforeDose is returned when the removal is before the dose was
+	// taken.
+	ErrBeforeDose = errors.New("removal must not be before the dose")
+)
+
+const (
+	// replacementSlack is how long before a removal a dose still counts as
+	// its replacement, since the new patch is often put on right before the
+	// old one is taken off.
+	replacementSlack = 15 * time.Minute
+	// maxReminderAge is how long after a removal its reminder is still sent,
+	// so that removals recorded long after the fact are not reminded of.
+	maxReminderAge = 24 * time.Hour
+)
+
+// Removable returns true if the applications of the given type can be taken
+// off.
+func Removable(t hrtclicker.HRTType) bool {
+	return t == hrtclicker.TypePatches
+}
+
+// Add records that the application of the regimen taken at dosageAt was
+// removed at removedAt. A zero dosageAt removes the most recent application.
+func Add(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, dosageAt, removedAt time.Time, reason string) (db.Removal, error) {
+	if !Removable(regimen.Type) {
+		return db.Removal{}, ErrNotRemovable
+	}
+
+	var dose db.HRTHistory
+	var err error
+	if dosageAt.IsZero() {
+		dose, err = database.LastDose(ctx, string(regimen.Type))
+	} else {
+		dose, err = database.DoseAt(ctx, dosageAt.UTC())
+	}
+	if err != nil {
+		return db.Removal{}, err
+	}
+
+	if dose.HRTType != string(regimen.Type) {
+		return db.Removal{}, ErrWrongType
+	}
+
+	removedAt = removedAt.UTC().Truncate(time.Second)
+	if removedAt.Before(dose.DosageAt) {
+		return db.Removal{}, ErrBeforeDose
+	}
+
+	return database.AddRemoval(ctx, db.AddRemovalParams{
+		DosageAt:  dose.DosageAt,
+		HRTType:   dose.HRTType,
+		RemovedAt: removedAt,
+		Reason:    reason,
+		AddedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+}
+
+// Between returns the removals of the applications of the given type taken
+// between from and to, for predicting the levels.
+func Between(ctx context.Context, database *db.SQLiteDB, hrtType hrtclicker.HRTType, from, to time.Time) ([]predict.Removal, error) {
+	removals, err := database.RemovalsBetween(ctx, db.RemovalsBetweenParams{
+		HRTType: string(hrtType),
+		Since:   from.UTC(),
+		Before:  to.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get removals: %w", err)
+	}
+
+	predicted := make([]predict.Removal, len(removals))
+	for i, removal := range removals {
+		predicted[i] = predict.Removal{
+			AppliedAt: removal.DosageAt,
+			RemovedAt: removal.RemovedAt,
+		}
+	}
+	return predicted, nil
+}
+
+// Current returns the doses of the regimen whose applications are still on at
+// now, oldest first. These are the last few doses that make up the
+// concurrence of the regimen and were not removed.
+func Current(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, now time.Time) ([]db.HRTHistory, error) {
+	doses, err := database.LastDoses(ctx, db.LastDosesParams{
+		HRTType: string(regimen.Type),
+		Limit:   int64(max(regimen.At(now).Concurrence, 1)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last doses: %w", err)
+	}
+
+	current := make([]db.HRTHistory, 0, len(doses))
+	for i := len(doses) - 1; i >= 0; i-- {
+		if doses[i].DosageAt.After(now) {
+			continue
+		}
+		_, err := database.Removal(ctx, doses[i].DosageAt)
+		switch {
+		case err == nil:
+			continue
+		case !db.IsNotFound(err):
+			return nil, fmt.Errorf("failed to get removal: %w", err)
+		}
+		current = append(current, doses[i])
+	}
+	return current, nil
+}
+
+// Unreplaced returns the removals of the regimen that the reminder to apply a
+// new one is due for at now: the ones removed at least the regimen's
+// removal_reminder ago without a dose taken after them, and not reminded of
+// yet.
+func Unreplaced(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, now time.Time) ([]db.Removal, error) {
+	before := now.Add(-regimen.RemovalReminderAfter())
+
+	removals, err := database.UnnotifiedRemovalsBetween(ctx, db.UnnotifiedRemovalsBetweenParams{
+		HRTType: string(regimen.Type),
+		Since:   before.Add(-maxReminderAge).UTC(),
+		Before:  before.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get removals: %w", err)
+	}
+	if len(removals) == 0 {
+		return nil, nil
+	}
+
+	lastDose, err := database.LastDose(ctx, string(regimen.Type))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last dose: %w", err)
+	}
+
+	var unreplaced []db.Removal
+	for _, removal := range removals {
+		// The last dose is at least as recent as any dose that replaced
+		// the removal, so only it needs checking.
+		replaced := lastDose.DosageAt.After(removal.DosageAt) &&
+			!lastDose.DosageAt.Before(removal.RemovedAt.Add(-replacementSlack))
+		if !replaced {
+			unreplaced = append(unreplaced, removal)
+		}
+	}
+	return unreplaced, nil
+}
+
+// Notification returns the reminder sent when the given removal was not
+// replaced, with the times in the given time zone.
+func Notification(removal db.Removal, loc *time.Location) hrtclicker.Notification {
+	reason := ""
+	if removal.Reason != "" {
+		reason = fmt.Sprintf(" (%s)", removal.Reason)
+	}
+	return hrtclicker.Notification{
+		Title: "Time to put on a new patch",
+		Message: fmt.Sprintf(
+			"You took off your patch from %s at %s%s without putting on a new one.",
+			removal.DosageAt.In(loc).Format("Mon Jan 2"), removal.RemovedAt.In(loc).Format("15:04"), reason),
+	}
+}
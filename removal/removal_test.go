@@ -0,0 +1,276 @@
+package removal
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+// patches is a regimen of two patches on at a time, changed every day, which
+// is reminded of removals after an hour.
+var patches = hrtclicker.HRTConfig{
+	Type:            hrtclicker.TypePatches,
+	Interval:        cfgtypes.Duration(24 * time.Hour),
+	Concurrence:     2,
+	RemovalReminder: cfgtypes.Duration(time.Hour),
+}
+
+func recordDoses(t *testing.T, database *db.SQLiteDB, hrtType hrtclicker.HRTType, doses ...time.Time) {
+	t.Helper()
+
+	for _, dosageAt := range doses {
+		if err := database.RecordDosage(context.Background(), db.RecordDosageParams{
+			DosageAt: dosageAt,
+			HRTType:  string(hrtType),
+			Tags:     db.NewTags(),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name      string
+		regimen   hrtclicker.HRTConfig
+		dosageAt  time.Time
+		removedAt time.Time
+		want      time.Time
+		wantErr   error
+	}{
+		{
+			name:      "given dose",
+			regimen:   patches,
+			dosageAt:  date(1, 8, 0),
+			removedAt: date(1, 20, 0),
+			want:      date(1, 8, 0),
+		},
+		{
+			name:      "last dose",
+			regimen:   patches,
+			removedAt: date(2, 20, 0),
+			want:      date(2, 8, 0),
+		},
+		{
+			name:      "before the dose",
+			regimen:   patches,
+			dosageAt:  date(2, 8, 0),
+			removedAt: date(2, 7, 0),
+			wantErr:   ErrBeforeDose,
+		},
+		{
+			name:      "dose of another type",
+			regimen:   patches,
+			dosageAt:  date(1, 12, 0),
+			removedAt: date(1, 20, 0),
+			wantErr:   ErrWrongType,
+		},
+		{
+			name:      "not removable",
+			regimen:   hrtclicker.HRTConfig{Type: hrtclicker.TypeGel, Interval: cfgtypes.Duration(24 * time.Hour)},
+			dosageAt:  date(1, 12, 0),
+			removedAt: date(1, 20, 0),
+			wantErr:   ErrNotRemovable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			recordDoses(t, database, hrtclicker.TypePatches, date(1, 8, 0), date(2, 8, 0))
+			recordDoses(t, database, hrtclicker.TypeGel, date(1, 12, 0))
+
+			got, err := Add(ctx, database, test.regimen, test.dosageAt, test.removedAt, "fell off")
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("Add() = %v, want %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !got.DosageAt.Equal(test.want) || !got.RemovedAt.Equal(test.removedAt) {
+				t.Errorf("Add() removed the dose at %s at %s, want the one at %s at %s",
+					got.DosageAt, got.RemovedAt, test.want, test.removedAt)
+			}
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	tests := []struct {
+		name    string
+		removed []time.Time
+		now     time.Time
+		want    []time.Time
+	}{
+		{
+			name: "concurrent doses",
+			now:  date(3, 9, 0),
+			want: []time.Time{date(2, 8, 0), date(3, 8, 0)},
+		},
+		{
+			name:    "removed dose",
+			removed: []time.Time{date(2, 8, 0)},
+			now:     date(3, 9, 0),
+			want:    []time.Time{date(3, 8, 0)},
+		},
+		{
+			name: "dose after now",
+			now:  date(2, 9, 0),
+			want: []time.Time{date(2, 8, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			recordDoses(t, database, hrtclicker.TypePatches, date(1, 8, 0), date(2, 8, 0), date(3, 8, 0))
+			for _, dosageAt := range test.removed {
+				if _, err := Add(ctx, database, patches, dosageAt, dosageAt.Add(time.Hour), ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			current, err := Current(ctx, database, patches, test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []time.Time
+			for _, dose := range current {
+				got = append(got, dose.DosageAt)
+			}
+			if !slices.EqualFunc(got, test.want, time.Time.Equal) {
+				t.Errorf("Current() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnreplaced(t *testing.T) {
+	// The patch from the 1st is taken off at 20:00, so the reminder is due
+	// from 21:00.
+	removedAt := date(1, 20, 0)
+
+	tests := []struct {
+		name     string
+		doses    []time.Time
+		notified bool
+		now      time.Time
+		want     bool
+	}{
+		{
+			name: "too soon",
+			now:  date(1, 20, 30),
+		},
+		{
+			name: "due",
+			now:  date(1, 21, 30),
+			want: true,
+		},
+		{
+			name:  "replaced after",
+			doses: []time.Time{date(1, 20, 45)},
+			now:   date(1, 21, 30),
+		},
+		{
+			name:  "replaced right before",
+			doses: []time.Time{date(1, 19, 50)},
+			now:   date(1, 21, 30),
+		},
+		{
+			name:  "dose long before",
+			doses: []time.Time{date(1, 12, 0)},
+			now:   date(1, 21, 30),
+			want:  true,
+		},
+		{
+			name:     "already reminded",
+			notified: true,
+			now:      date(1, 21, 30),
+		},
+		{
+			name: "too old",
+			now:  date(3, 0, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			recordDoses(t, database, hrtclicker.TypePatches, date(1, 8, 0))
+			if _, err := Add(ctx, database, patches, date(1, 8, 0), removedAt, ""); err != nil {
+				t.Fatal(err)
+			}
+			recordDoses(t, database, hrtclicker.TypePatches, test.doses...)
+			if test.notified {
+				if err := database.MarkRemovalNotified(ctx, date(1, 8, 0)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			unreplaced, err := Unreplaced(ctx, database, patches, test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(unreplaced) == 1; got != test.want || len(unreplaced) > 1 {
+				t.Errorf("Unreplaced() = %v, want the removal: %v", unreplaced, test.want)
+			}
+		})
+	}
+}
+
+func TestNotification(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	tests := []struct {
+		name   string
+		reason string
+		loc    *time.Location
+		want   string
+	}{
+		{
+			name: "without a reason",
+			loc:  time.UTC,
+			want: "You took off your patch from Sun Mar 1 at 23:30 without putting on a new one.",
+		},
+		{
+			name:   "with a reason",
+			reason: "fell off",
+			loc:    time.UTC,
+			want:   "You took off your patch from Sun Mar 1 at 23:30 (fell off) without putting on a new one.",
+		},
+		{
+			// The removal is after midnight in Berlin.
+			name: "time zone",
+			loc:  berlin,
+			want: "You took off your patch from Sun Mar 1 at 00:30 without putting on a new one.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			removal := db.Removal{
+				DosageAt:  date(1, 8, 0),
+				RemovedAt: date(1, 23, 30),
+				Reason:    test.reason,
+			}
+			if got := Notification(removal, test.loc).Message; got != test.want {
+				t.Errorf("Notification().Message = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
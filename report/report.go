@@ -0,0 +1,265 @@
+// Package report gathers the data for the printable report of a regimen over
+// a date range. The report is rendered by the "report" page template.
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/adherence"
+	"libdb.so/hrtclicker/chart"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/inventory"
+	"libdb.so/hrtclicker/labs"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+	"libdb.so/hrtclicker/web"
+)
+
+// DefaultRange is the duration covered by a report if not given.
+const DefaultRange = 90 * 24 * time.Hour
+
+// Report is the data of a report.
+type Report struct {
+	GeneratedAt time.Time
+	From        time.Time
+	To          time.Time
+	// Regimen is the regimen in effect at the start of the range.
+	Regimen hrtclicker.HRTConfig
+	// Changes are the changes to the regimen that took effect within the
+	// range, oldest first.
+	Changes []hrtclicker.RegimenChange
+	// Doses are the doses taken within the range, oldest first.
+	Doses   []adherence.Dose
+	Summary adherence.Summary
+	// Levels are the predicted levels within the range. It is empty if the
+	// levels of the regimen cannot be predicted.
+	Levels []predict.TimeValue
+	// Prescriptions are the prescriptions in effect during the range, most
+	// recently written first.
+	Prescriptions []Prescription
+	// Labs are the lab results drawn within the range, oldest first.
+	Labs []labs.Result
+	// Pauses are the pauses of the regimen that overlap the range, oldest
+	// first.
+	Pauses []db.Pause
+}
+
+// Prescription is a prescription in effect during the range of a report.
+type Prescription struct {
+	inventory.Prescription
+	// RangeDoses is the number of doses within the range that drew from stock
+	// filled from the prescription.
+	RangeDoses int
+}
+
+// ParseRange parses the range of a report. from and to are dates as
+// "2006-01-02" in loc or RFC 3339 times, and to includes the whole day. An
+// empty to means now, and an empty from means DefaultRange before to.
+func ParseRange(from, to string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	toTime := now
+	if to != "" {
+		t, isDate, err := parseTime(to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		if isDate {
+			t = t.AddDate(0, 0, 1)
+		}
+		toTime = t
+	}
+
+	fromTime := toTime.Add(-DefaultRange)
+	if from != "" {
+		t, _, err := parseTime(from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		fromTime = t
+	}
+
+	if !fromTime.Before(toTime) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+
+	return fromTime, toTime, nil
+}
+
+func parseTime(s string, loc *time.Location) (t time.Time, isDate bool, err error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	return t, false, err
+}
+
+// Build gathers the report for the regimen between from and to.
+func Build(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time) (*Report, error) {
+	if !from.Before(to) {
+		return nil, errors.New("report range is empty")
+	}
+
+	// Include the doses before the range that still affect the levels in it,
+	// and the ones taken right at its end.
+	lookback, _ := predict.Lookback(regimen.Type)
+
+	history, err := database.DosesBetween(ctx, string(regimen.Type), from.Add(-lookback), to.Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dosage history: %w", err)
+	}
+
+	applications := make([]time.Time, len(history))
+	for i, dose := range history {
+		applications[i] = dose.DosageAt
+	}
+
+	// The pauses before the range move when the first doses in it were due.
+	pauses, err := pause.Between(ctx, database, regimen.Type, from.Add(-lookback), to)
+	if err != nil {
+		return nil, err
+	}
+
+	skips, err := missed.Between(ctx, database, regimen.Type, from.Add(-lookback), to)
+	if err != nil {
+		return nil, err
+	}
+
+	doses, summary := adherence.Analyze(regimen, applications, pause.Spans(pauses), skips, from, to)
+
+	removals, err := removal.Between(ctx, database, regimen.Type, from.Add(-lookback), to.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	var levels []predict.TimeValue
+	if _, ok := predict.ForType(regimen.Type); ok {
+		levels, err = predict.Regimen(regimen, applications, removals, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to predict levels: %w", err)
+		}
+	}
+
+	prescriptions, err := prescriptionsBetween(ctx, database, regimen, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := labs.ResultsBetween(ctx, database, regimen, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		GeneratedAt:   time.Now(),
+		From:          from,
+		To:            to,
+		Regimen:       regimen.At(from),
+		Changes:       regimen.ChangesBetween(from, to),
+		Doses:         doses,
+		Summary:       summary,
+		Levels:        levels,
+		Prescriptions: prescriptions,
+		Labs:          results,
+		Pauses:        inRange(pauses, from),
+	}, nil
+}
+
+// prescriptionsBetween returns the prescriptions of the regimen that were
+// written before to and hadn't expired by from, with the doses taken between
+// from and to that drew from them.
+func prescriptionsBetween(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, from, to time.Time) ([]Prescription, error) {
+	all, err := inventory.Prescriptions(ctx, database, regimen.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := database.DoseSources(ctx, db.DoseSourcesParams{
+		HRTType: string(regimen.Type),
+		Since:   from.UTC(),
+		Before:  to.Add(time.Second).UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dose sources: %w", err)
+	}
+
+	// A dose drawing from several stock entries of the same prescription only
+	// counts once.
+	type use struct {
+		prescriptionID int64
+		dosageAt       time.Time
+	}
+	uses := make(map[use]bool)
+	rangeDoses := make(map[int64]int)
+	for _, source := range sources {
+		u := use{source.PrescriptionID.Int64, source.DosageAt}
+		if !source.PrescriptionID.Valid || uses[u] {
+			continue
+		}
+		uses[u] = true
+		rangeDoses[u.prescriptionID]++
+	}
+
+	var prescriptions []Prescription
+	for _, p := range all {
+		if !p.WrittenAt.Before(to) || p.Expired(from, regimen.Location()) {
+			continue
+		}
+		prescriptions = append(prescriptions, Prescription{
+			Prescription: p,
+			RangeDoses:   rangeDoses[p.ID],
+		})
+	}
+	return prescriptions, nil
+}
+
+// inRange returns the pauses that haven't ended by from.
+func inRange(pauses []db.Pause, from time.Time) []db.Pause {
+	var overlapping []db.Pause
+	for _, p := range pauses {
+		if !p.EndedAt.Valid || p.EndedAt.Time.After(from) {
+			overlapping = append(overlapping, p)
+		}
+	}
+	return overlapping
+}
+
+// Render renders the report as a self-contained HTML page.
+func (r *Report) Render(w io.Writer, tmpl *web.Templates) error {
+	return tmpl.Execute(w, "report", r)
+}
+
+// LateDoses returns the doses that were taken late or after missed doses.
+func (r *Report) LateDoses() []adherence.Dose {
+	var late []adherence.Dose
+	for _, dose := range r.Doses {
+		if dose.Status == adherence.StatusLate || dose.Missed > 0 {
+			late = append(late, dose)
+		}
+	}
+	return late
+}
+
+// Chart renders the predicted levels with the doses and lab results as an
+// inline SVG.
+func (r *Report) Chart() template.HTML {
+	doses := make([]time.Time, len(r.Doses))
+	for i, dose := range r.Doses {
+		doses[i] = dose.DosageAt
+	}
+
+	return chart.Levels{
+		Values:   r.Levels,
+		Doses:    doses,
+		Points:   labs.Points(r.Labs),
+		Target:   r.Regimen.Target,
+		Spans:    pause.ChartSpans(r.Pauses),
+		Location: r.Regimen.Location(),
+	}.SVG()
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+func TestParseRange(t *testing.T) {
+	now := hrttest.Date(28, 12, 0)
+	// Dates are in the time zone of the regimen.
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	day := func(d int) time.Time {
+		return time.Date(2026, 3, d, 0, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "default",
+			wantFrom: now.Add(-DefaultRange),
+			wantTo:   now,
+		},
+		{
+			name:     "dates",
+			from:     "2026-03-01",
+			to:       "2026-03-14",
+			wantFrom: day(1),
+			wantTo:   day(15),
+		},
+		{
+			name:     "times",
+			from:     "2026-03-01T08:00:00Z",
+			to:       "2026-03-14T08:00:00Z",
+			wantFrom: hrttest.Date(1, 8, 0),
+			wantTo:   hrttest.Date(14, 8, 0),
+		},
+		{
+			name:     "only from",
+			from:     "2026-03-01",
+			wantFrom: day(1),
+			wantTo:   now,
+		},
+		{
+			name:    "from after to",
+			from:    "2026-03-14",
+			to:      "2026-03-01",
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			from:    "yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from, to, err := ParseRange(test.from, test.to, now, loc)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseRange() = %v, want an error: %v", err, test.wantErr)
+			}
+			if !from.Equal(test.wantFrom) || !to.Equal(test.wantTo) {
+				t.Errorf("ParseRange() = %s, %s, want %s, %s", from, to, test.wantFrom, test.wantTo)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	regimen := hrtclicker.HRTConfig{
+		Type:     hrtclicker.TypePatches,
+		Interval: cfgtypes.Duration(84 * time.Hour),
+	}
+	doses := []time.Time{
+		hrttest.Date(1, 8, 0),
+		hrttest.Date(4, 20, 0),
+		hrttest.Date(8, 14, 0),
+		hrttest.Date(19, 2, 0),
+	}
+	for _, dosageAt := range doses {
+		if err := database.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: dosageAt,
+			HRTType:  string(regimen.Type),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := Build(ctx, database, regimen, hrttest.Date(2, 0, 0), hrttest.Date(20, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Doses) != 3 {
+		t.Errorf("Build() has %d doses, want the 3 within the range", len(r.Doses))
+	}
+	if r.Summary.OnTime != 2 || r.Summary.Late != 1 || r.Summary.Missed != 2 {
+		t.Errorf("Build() summary = %+v, want 2 on time, 1 late and 2 missed", r.Summary)
+	}
+	if len(r.Levels) == 0 {
+		t.Error("Build() has no levels")
+	}
+
+	late := r.LateDoses()
+	if len(late) != 2 || !late[0].DosageAt.Equal(doses[2]) || !late[1].DosageAt.Equal(doses[3]) {
+		t.Errorf("LateDoses() = %+v, want the late dose and the one after a missed dose", late)
+	}
+
+	if _, err := Build(ctx, database, regimen, hrttest.Date(20, 0, 0), hrttest.Date(20, 0, 0)); err == nil {
+		t.Error("Build() with an empty range succeeded")
+	}
+}
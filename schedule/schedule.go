@@ -0,0 +1,173 @@
+// Package schedule tells when the next dose of a regimen is due and what to do
+// about it, following everything that moves it: the doses skipped since the
+// last one, trips, pauses and snoozed reminders. The server, the command line,
+// the monitor and the hooks all use it, so that they agree.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/adherence"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/travel"
+)
+
+// NextDose is the state of the next dose of a regimen. It is the response of
+// the /api/dosage/next endpoint.
+type NextDose struct {
+	HRTType hrtclicker.HRTType
+	// LastDoseAt is the time of the last dose. It is zero if no doses have
+	// been recorded yet.
+	LastDoseAt time.Time
+	// NextDoseAt is the time the next dose is due, which is the end of the
+	// pause it falls due in, if any. It is zero if no doses have been recorded
+	// yet or if it falls due in a pause without an end.
+	NextDoseAt time.Time
+	// SnoozedUntil is the time the reminder for the next dose is snoozed
+	// until. It is zero if the reminder is not snoozed.
+	SnoozedUntil time.Time
+	// Paused is the pause the regimen is in, or nil if it isn't paused.
+	Paused *db.Pause
+	// Advice is what to do about the next dose once it is due, following the
+	// missed dose policy of the regimen. It is nil if it isn't due yet.
+	Advice *missed.Advice
+}
+
+// Next returns the state of the next dose of the regimen at now.
+func Next(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, now time.Time) (NextDose, error) {
+	next := NextDose{HRTType: regimen.Type}
+
+	active, err := pause.Active(ctx, database, regimen.Type, now)
+	switch {
+	case err == nil:
+		next.Paused = &active
+	case !db.IsNotFound(err):
+		return NextDose{}, fmt.Errorf("failed to get pause: %w", err)
+	}
+
+	dose, err := database.LastDose(ctx, string(regimen.Type))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return next, nil
+		}
+		return NextDose{}, fmt.Errorf("failed to get last dose: %w", err)
+	}
+
+	next.LastDoseAt = dose.DosageAt
+	next.NextDoseAt, _, err = pause.NextDoseAt(ctx, database, regimen, dose.DosageAt, now)
+	if err != nil {
+		return NextDose{}, fmt.Errorf("failed to get next dose: %w", err)
+	}
+
+	next.SnoozedUntil, err = database.SnoozedUntil(ctx, dose.DosageAt)
+	if err != nil && !db.IsNotFound(err) {
+		return NextDose{}, fmt.Errorf("failed to get snooze: %w", err)
+	}
+
+	next.Advice, err = Advise(ctx, database, regimen, next.NextDoseAt, now)
+	if err != nil {
+		return NextDose{}, err
+	}
+	return next, nil
+}
+
+// maxProjected is the most doses that Project returns, in case the interval is
+// short compared to the time projected.
+const maxProjected = 1000
+
+// Project returns when the doses after the one taken at lastDoseAt are due
+// until the given time, assuming every dose is taken when it is due. The first
+// one is the next dose that Next returns, and the rest follow the same
+// schedule: regimen changes, cycles, trips and pauses.
+func Project(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, lastDoseAt, now, until time.Time) ([]time.Time, error) {
+	from, err := missed.ScheduleFrom(ctx, database, regimen.Type, lastDoseAt)
+	if err != nil {
+		return nil, err
+	}
+
+	pauses, err := pause.SpansBetween(ctx, database, regimen.Type, lastDoseAt, db.EndOfTime)
+	if err != nil {
+		return nil, err
+	}
+
+	trips, err := database.Trips(ctx, string(regimen.Type))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trips: %w", err)
+	}
+
+	trip, step, shifted, err := travel.Current(ctx, database, regimen, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var dues []time.Time
+	at, pending := from, now
+	for len(dues) < maxProjected {
+		due := regimen.NextDoseAt(at)
+		if shifted {
+			due = due.Add(trip.Steps[step])
+			step++
+		}
+
+		due, ok := adherence.AfterPauses(pauses, due, pending)
+		if !ok || due.After(until) {
+			break
+		}
+		dues = append(dues, due)
+		at, pending = due, due
+
+		// The dose after this one is shifted by the rest of the steps of the
+		// trip departed before it would be due, which is a new trip if it
+		// departed since.
+		row, ok := departedBefore(trips, regimen.NextDoseAt(at))
+		if ok && row.ID != trip.ID {
+			trip, err = travel.New(row, regimen.MaxTravelShift())
+			if err != nil {
+				return nil, err
+			}
+			step = 0
+		}
+		shifted = ok && step < len(trip.Steps)
+	}
+
+	return dues, nil
+}
+
+// departedBefore returns the last of the trips, sorted by their departure
+// newest first, that departed before t.
+func departedBefore(trips []db.Trip, t time.Time) (db.Trip, bool) {
+	for _, trip := range trips {
+		if trip.DepartsAt.Before(t) {
+			return trip, true
+		}
+	}
+	return db.Trip{}, false
+}
+
+// Advise returns what to do at now about the next dose due at dueAt, or nil
+// if it isn't due yet or no dose is due. The dose after it is due as if it was
+// skipped, following the same trips and pauses as the next dose.
+func Advise(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, dueAt, now time.Time) (*missed.Advice, error) {
+	if dueAt.IsZero() {
+		return nil, nil
+	}
+
+	nextDueAt, ok, err := pause.NextDoseAt(ctx, database, regimen, dueAt, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the dose after the next one: %w", err)
+	}
+	if !ok {
+		nextDueAt = time.Time{}
+	}
+
+	advice := missed.Advise(regimen, dueAt, nextDueAt, now)
+	if advice.Action == missed.ActionWait {
+		return nil, nil
+	}
+	return &advice, nil
+}
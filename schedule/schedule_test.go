@@ -0,0 +1,310 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+)
+
+var date = hrttest.Date
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name       string
+		doses      []time.Time
+		setup      func(context.Context, *db.SQLiteDB) error
+		now        time.Time
+		want       NextDose
+		wantPaused bool
+		wantPhase  missed.Phase
+	}{
+		{
+			name: "no doses",
+			now:  date(1, 8, 0),
+		},
+		{
+			name:  "upcoming",
+			doses: []time.Time{date(1, 8, 0)},
+			now:   date(1, 20, 0),
+			want:  NextDose{LastDoseAt: date(1, 8, 0), NextDoseAt: date(2, 8, 0)},
+		},
+		{
+			name:      "due",
+			doses:     []time.Time{date(1, 8, 0)},
+			now:       date(2, 8, 30),
+			want:      NextDose{LastDoseAt: date(1, 8, 0), NextDoseAt: date(2, 8, 0)},
+			wantPhase: missed.PhaseOnTime,
+		},
+		{
+			name:  "snoozed",
+			doses: []time.Time{date(1, 8, 0)},
+			setup: func(ctx context.Context, database *db.SQLiteDB) error {
+				return database.Snooze(ctx, db.SnoozeParams{DosageAt: date(1, 8, 0), SnoozedUntil: date(2, 9, 0)})
+			},
+			now:       date(2, 8, 30),
+			want:      NextDose{LastDoseAt: date(1, 8, 0), NextDoseAt: date(2, 8, 0), SnoozedUntil: date(2, 9, 0)},
+			wantPhase: missed.PhaseOnTime,
+		},
+		{
+			name:  "paused",
+			doses: []time.Time{date(1, 8, 0)},
+			setup: func(ctx context.Context, database *db.SQLiteDB) error {
+				_, err := pause.Start(ctx, database, hrttest.Daily.Type, date(2, 0, 0), date(3, 0, 0), "")
+				return err
+			},
+			now:        date(2, 1, 0),
+			want:       NextDose{LastDoseAt: date(1, 8, 0), NextDoseAt: date(3, 0, 0)},
+			wantPaused: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			for _, dosageAt := range test.doses {
+				if err := database.RecordDosage(ctx, db.RecordDosageParams{
+					DosageAt: dosageAt,
+					HRTType:  string(hrttest.Daily.Type),
+					Tags:     db.NewTags(),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.setup != nil {
+				if err := test.setup(ctx, database); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := Next(ctx, database, hrttest.Daily, test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.HRTType != hrttest.Daily.Type ||
+				!got.LastDoseAt.Equal(test.want.LastDoseAt) ||
+				!got.NextDoseAt.Equal(test.want.NextDoseAt) ||
+				!got.SnoozedUntil.Equal(test.want.SnoozedUntil) {
+				t.Errorf("Next() = %+v, want %+v", got, test.want)
+			}
+			if (got.Paused != nil) != test.wantPaused {
+				t.Errorf("Next().Paused = %v, want a pause: %v", got.Paused, test.wantPaused)
+			}
+
+			var gotPhase missed.Phase
+			if got.Advice != nil {
+				gotPhase = got.Advice.Phase
+			}
+			if gotPhase != test.wantPhase {
+				t.Errorf("Next().Advice phase = %q, want %q", gotPhase, test.wantPhase)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	regimen := hrtclicker.HRTConfig{
+		Type:        "patches",
+		Interval:    cfgtypes.Duration(24 * time.Hour),
+		TravelShift: cfgtypes.Duration(2 * time.Hour),
+	}
+	lastDoseAt := date(1, 8, 0)
+
+	tests := []struct {
+		name    string
+		regimen func(hrtclicker.HRTConfig) hrtclicker.HRTConfig
+		setup   func(context.Context, *db.SQLiteDB) error
+		until   time.Time
+		want    []time.Time
+	}{
+		{
+			name:  "every interval",
+			until: date(4, 8, 0),
+			want:  []time.Time{date(2, 8, 0), date(3, 8, 0), date(4, 8, 0)},
+		},
+		{
+			name: "regimen change",
+			regimen: func(r hrtclicker.HRTConfig) hrtclicker.HRTConfig {
+				r.Changes = []hrtclicker.RegimenChange{{
+					EffectiveAt: date(2, 12, 0),
+					Interval:    cfgtypes.Duration(12 * time.Hour),
+				}}
+				return r
+			},
+			until: date(4, 8, 0),
+			want:  []time.Time{date(2, 8, 0), date(3, 8, 0), date(3, 20, 0), date(4, 8, 0)},
+		},
+		{
+			name: "skipped dose",
+			setup: func(ctx context.Context, database *db.SQLiteDB) error {
+				_, err := database.AddSkip(ctx, db.AddSkipParams{
+					HRTType:   "patches",
+					DueAt:     date(2, 8, 0),
+					SkippedAt: date(2, 9, 0),
+				})
+				return err
+			},
+			until: date(4, 8, 0),
+			want:  []time.Time{date(3, 8, 0), date(4, 8, 0)},
+		},
+		{
+			name: "pause",
+			setup: func(ctx context.Context, database *db.SQLiteDB) error {
+				_, err := database.AddPause(ctx, db.AddPauseParams{
+					HRTType:   "patches",
+					StartedAt: date(2, 0, 0),
+					EndedAt:   sql.NullTime{Time: date(3, 12, 0), Valid: true},
+					AddedAt:   date(1, 9, 0),
+				})
+				return err
+			},
+			until: date(5, 8, 0),
+			want:  []time.Time{date(3, 12, 0), date(4, 12, 0)},
+		},
+		{
+			name: "pause without end",
+			setup: func(ctx context.Context, database *db.SQLiteDB) error {
+				_, err := database.AddPause(ctx, db.AddPauseParams{
+					HRTType:   "patches",
+					StartedAt: date(3, 0, 0),
+					AddedAt:   date(1, 9, 0),
+				})
+				return err
+			},
+			until: date(5, 8, 0),
+			want:  []time.Time{date(2, 8, 0)},
+		},
+		{
+			name:  "trip east in progress",
+			setup: addTrip(date(1, 20, 0)),
+			until: date(5, 8, 0),
+			want:  []time.Time{date(2, 6, 0), date(3, 4, 0), date(4, 2, 0), date(5, 2, 0)},
+		},
+		{
+			name:  "trip east departing later",
+			setup: addTrip(date(2, 20, 0)),
+			until: date(6, 8, 0),
+			want:  []time.Time{date(2, 8, 0), date(3, 6, 0), date(4, 4, 0), date(5, 2, 0), date(6, 2, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+
+			if err := database.RecordDosage(ctx, db.RecordDosageParams{
+				DosageAt: lastDoseAt,
+				HRTType:  "patches",
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if test.setup != nil {
+				if err := test.setup(ctx, database); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := regimen
+			if test.regimen != nil {
+				r = test.regimen(r)
+			}
+
+			got, err := Project(ctx, database, r, lastDoseAt, date(1, 9, 0), test.until)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(test.want) {
+				t.Fatalf("Project() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if !got[i].Equal(test.want[i]) {
+					t.Fatalf("Project() = %v, want %v", got, test.want)
+				}
+			}
+
+			next, err := Next(ctx, database, r, date(1, 9, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !next.NextDoseAt.Equal(got[0]) {
+				t.Errorf("Next().NextDoseAt = %v, want the first projected dose %v", next.NextDoseAt, got[0])
+			}
+		})
+	}
+}
+
+// addTrip adds a trip from New York to Berlin, which is 6 hours east in
+// early March.
+func addTrip(departsAt time.Time) func(context.Context, *db.SQLiteDB) error {
+	return func(ctx context.Context, database *db.SQLiteDB) error {
+		_, err := database.AddTrip(ctx, db.AddTripParams{
+			HRTType:         "patches",
+			DepartureZone:   "America/New_York",
+			DestinationZone: "Europe/Berlin",
+			DepartsAt:       departsAt,
+			AddedAt:         date(1, 9, 0),
+		})
+		return err
+	}
+}
+
+func TestAdvise(t *testing.T) {
+	// The dose due on the 2nd is missed, and the one after it is skipped
+	// within 8 hours of it.
+	regimen := hrttest.Daily
+	regimen.LateWindow = cfgtypes.Duration(6 * time.Hour)
+	regimen.MissedDose.SkipWithin = cfgtypes.Duration(8 * time.Hour)
+
+	tests := []struct {
+		name          string
+		pauses        [][2]time.Time
+		wantNextDueAt time.Time
+		wantAction    missed.Action
+	}{
+		{
+			name:          "skipped",
+			wantNextDueAt: date(3, 8, 0),
+			wantAction:    missed.ActionSkip,
+		},
+		{
+			name:          "next dose moved by a pause",
+			pauses:        [][2]time.Time{{date(3, 0, 0), date(3, 12, 0)}},
+			wantNextDueAt: date(3, 12, 0),
+			wantAction:    missed.ActionTake,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			for _, p := range test.pauses {
+				if _, err := pause.Start(ctx, database, regimen.Type, p[0], p[1], ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			advice, err := Advise(ctx, database, regimen, date(2, 8, 0), date(3, 1, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if advice == nil {
+				t.Fatal("Advise() = nil, want advice about the missed dose")
+			}
+			if !advice.NextDueAt.Equal(test.wantNextDueAt) || advice.Action != test.wantAction {
+				t.Errorf("Advise() = %s, %s, want %s, %s", advice.NextDueAt, advice.Action, test.wantNextDueAt, test.wantAction)
+			}
+		})
+	}
+}
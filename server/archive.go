@@ -0,0 +1,143 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/archive"
+)
+
+// maxImportSize is the maximum size of an imported file.
+const maxImportSize = 32 << 20 // 32 MiB
+
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	format := archive.FormatJSON
+	if r.FormValue("format") != "" {
+		var err error
+		format, err = archive.ParseFormat(r.FormValue("format"))
+		if err != nil {
+			write400Error(w, "invalid format", err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition",
+		mime.FormatMediaType("attachment", map[string]string{"filename": format.Filename(time.Now())}))
+
+	if err := archive.Export(r.Context(), s.Database, s.Config.Load(), format, w); err != nil {
+		s.Logger.Error(
+			"failed to export",
+			"format", format,
+			"err", err)
+		// The headers may have been sent already, but try anyway.
+		writeError(w, "failed to export", err)
+	}
+}
+
+// handleImport imports a file given either as the "file" field of a multipart
+// form or as the request body. The other parameters are given as form values
+// or in the query string:
+//
+//   - format is the format of the file. It defaults to the file name's
+//     extension for multipart forms.
+//   - type is the HRT type of doses without one.
+//   - tz is the IANA time zone of CSV times without one.
+//   - dry_run reports what would be imported without importing anything.
+//
+// The response is always an archive.ImportResult in JSON.
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var file io.Reader = r.Body
+	var filename string
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			write400Error(w, "failed to parse form", err)
+			return
+		}
+
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			write400Error(w, "missing file", err)
+			return
+		}
+		defer f.Close()
+
+		file = f
+		filename = header.Filename
+	} else {
+		// The body is the file, so only the query string has the form.
+		r.Form = r.URL.Query()
+	}
+
+	opts := archive.ImportOptions{
+		DefaultType: hrtclicker.HRTType(r.FormValue("type")),
+	}
+
+	var err error
+	switch {
+	case r.FormValue("format") != "":
+		opts.Format, err = archive.ParseFormat(r.FormValue("format"))
+	case filename != "":
+		opts.Format, err = archive.FormatFromFilename(filename)
+	default:
+		err = errors.New("missing format")
+	}
+	if err != nil {
+		write400Error(w, "invalid format", err)
+		return
+	}
+
+	if opts.DefaultType == "" {
+		opts.DefaultType = s.Config.Load().HRT.Type
+	}
+
+	if tz := r.FormValue("tz"); tz != "" {
+		opts.Location, err = time.LoadLocation(tz)
+		if err != nil {
+			write400Error(w, "invalid tz", err)
+			return
+		}
+	} else {
+		opts.Location = s.location(opts.DefaultType)
+	}
+
+	if v := r.FormValue("dry_run"); v != "" {
+		opts.DryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			write400Error(w, "invalid dry_run", err)
+			return
+		}
+	}
+
+	result, err := archive.Import(r.Context(), s.Database, file, opts)
+	if err != nil {
+		write400Error(w, "failed to import", err)
+		return
+	}
+
+	if !opts.DryRun {
+		s.Logger.Info(
+			"imported doses",
+			"format", opts.Format,
+			"added", len(result.Added),
+			"duplicates", result.Duplicates,
+			"conflicts", len(result.Conflicts),
+			"records_added", result.RecordsAdded)
+	}
+
+	writeJSON(w, result)
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/ics"
+	"libdb.so/hrtclicker/schedule"
+)
+
+const (
+	// calendarProdID is the PRODID of the calendar feed.
+	calendarProdID = "-//hrtclicker//hrtclicker//EN"
+	// calendarEventDuration is the duration of each event in the calendar
+	// feed, so that doses show up as more than a line.
+	calendarEventDuration = 15 * time.Minute
+	// calendarPast is how far back past doses are included in the calendar
+	// feed. The last dose before that is always included to project from.
+	calendarPast = 366 * 24 * time.Hour
+)
+
+// calendarURL returns the path of the calendar feed including its token, or an
+// empty string if the feed is disabled.
+func calendarURL(cfg *hrtclicker.Config) string {
+	if cfg.Calendar.Token == "" {
+		return ""
+	}
+	return "/calendar.ics?token=" + url.QueryEscape(cfg.Calendar.Token)
+}
+
+func (s *Server) getCalendar(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	cfg := s.Config.Load()
+	if cfg.Calendar.Token == "" {
+		http.Error(w, "calendar feed is disabled", http.StatusNotFound)
+		return
+	}
+
+	token := r.FormValue("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Calendar.Token)) != 1 {
+		http.Error(w, "invalid calendar token", http.StatusForbidden)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	now := time.Now()
+	doses, err := s.Database.DosesBetween(r.Context(), string(regimen.Type), now.Add(-calendarPast), db.EndOfTime)
+	if err != nil {
+		writeError(w, "failed to get dosage history", err)
+		return
+	}
+
+	horizon := cfg.Calendar.Horizon.AsDuration()
+	if horizon <= 0 {
+		horizon = hrtclicker.DefaultCalendarHorizon
+	}
+
+	var due []time.Time
+	if len(doses) > 0 {
+		due, err = schedule.Project(r.Context(), s.Database, regimen, doses[len(doses)-1].DosageAt, now, now.Add(horizon))
+		if err != nil {
+			writeError(w, "failed to project doses", err)
+			return
+		}
+	}
+
+	cal := doseCalendar(regimen, doses, due, cfg.Calendar.AlarmBefore.AsDuration())
+
+	w.Header().Set("Content-Type", ics.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="hrtclicker.ics"`)
+	cal.Write(w)
+}
+
+// doseCalendar creates a calendar of the given doses, sorted oldest first, and
+// the doses projected to be due after the last one.
+//
+// Past doses use the time they were taken at as their UID, so they are stable
+// across requests. Projected doses use the last dose and their position after
+// it, so they stay the same until a new dose is recorded.
+func doseCalendar(regimen hrtclicker.HRTConfig, doses []db.HRTHistory, due []time.Time, alarmBefore time.Duration) ics.Calendar {
+	cal := ics.Calendar{
+		ProdID:   calendarProdID,
+		Name:     fmt.Sprintf("HRT (%s)", regimen.Type),
+		TimeZone: regimen.Zone(),
+	}
+
+	for _, dose := range doses {
+		cal.Events = append(cal.Events, ics.Event{
+			UID:      fmt.Sprintf("dose-%s-%d@hrtclicker", regimen.Type, dose.DosageAt.Unix()),
+			Stamp:    dose.DosageAt,
+			Start:    dose.DosageAt,
+			Duration: calendarEventDuration,
+			Summary:  fmt.Sprintf("HRT dose taken (%s)", regimen.Type),
+		})
+	}
+
+	if len(doses) == 0 {
+		return cal
+	}
+
+	lastDose := doses[len(doses)-1].DosageAt
+	previous := lastDose
+	for i, at := range due {
+		cal.Events = append(cal.Events, ics.Event{
+			UID:         fmt.Sprintf("due-%s-%d-%d@hrtclicker", regimen.Type, lastDose.Unix(), i+1),
+			Stamp:       lastDose,
+			Start:       at,
+			Duration:    calendarEventDuration,
+			Summary:     fmt.Sprintf("HRT dose due (%s)", regimen.Type),
+			Description: projectedDescription(regimen, previous),
+			Alarms: []ics.Alarm{{
+				Before:      alarmBefore,
+				Description: fmt.Sprintf("Time for your next HRT dose (%s)", regimen.Type),
+			}},
+		})
+		previous = at
+	}
+
+	return cal
+}
+
+// projectedDescription describes how the dose due after the one at previous
+// was projected. Its interval is the one in effect at previous, the same as
+// HRTConfig.NextDoseAt uses.
+func projectedDescription(regimen hrtclicker.HRTConfig, previous time.Time) string {
+	every := regimen.At(previous).Interval.AsDuration().String()
+	if regimen.Cycle != nil {
+		every += " on the days of the cycle with doses"
+	}
+	return fmt.Sprintf(
+		"Projected from the last dose every %s, moved by skipped doses, trips and pauses, assuming every dose is taken on time.",
+		every)
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+)
+
+func TestDoseCalendar(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	regimen := hrtclicker.HRTConfig{
+		Type:     hrtclicker.TypeGel,
+		Interval: cfgtypes.Duration(12 * time.Hour),
+	}
+
+	type event struct {
+		UID   string
+		Start time.Time
+	}
+
+	tests := []struct {
+		name    string
+		regimen hrtclicker.HRTConfig
+		doses   []time.Time
+		due     []time.Time
+		want    []event
+	}{
+		{
+			name:    "taken and projected",
+			regimen: regimen,
+			doses:   []time.Time{at(1, 8), at(1, 20)},
+			due:     []time.Time{at(2, 8), at(2, 20), at(3, 8)},
+			want: []event{
+				{"dose-gel-1772352000@hrtclicker", at(1, 8)},
+				{"dose-gel-1772395200@hrtclicker", at(1, 20)},
+				{"due-gel-1772395200-1@hrtclicker", at(2, 8)},
+				{"due-gel-1772395200-2@hrtclicker", at(2, 20)},
+				{"due-gel-1772395200-3@hrtclicker", at(3, 8)},
+			},
+		},
+		{
+			name:    "nothing projected",
+			regimen: regimen,
+			doses:   []time.Time{at(1, 8), at(1, 20)},
+			want: []event{
+				{"dose-gel-1772352000@hrtclicker", at(1, 8)},
+				{"dose-gel-1772395200@hrtclicker", at(1, 20)},
+			},
+		},
+		{
+			name:    "no doses",
+			regimen: regimen,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doses := make([]db.HRTHistory, len(test.doses))
+			for i, dosageAt := range test.doses {
+				doses[i] = db.HRTHistory{DosageAt: dosageAt, HRTType: string(test.regimen.Type)}
+			}
+
+			cal := doseCalendar(test.regimen, doses, test.due, 10*time.Minute)
+
+			var got []event
+			for _, ev := range cal.Events {
+				got = append(got, event{ev.UID, ev.Start})
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("doseCalendar() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i].UID != test.want[i].UID || !got[i].Start.Equal(test.want[i].Start) {
+					t.Errorf("event %d = %v, want %v", i, got[i], test.want[i])
+				}
+			}
+
+			for _, ev := range cal.Events[len(test.doses):] {
+				if want := "Projected from the last dose every 12h0m0s, moved by skipped doses, trips and pauses, assuming every dose is taken on time."; ev.Description != want {
+					t.Errorf("projected event description = %q, want %q", ev.Description, want)
+				}
+				if len(ev.Alarms) != 1 || ev.Alarms[0].Before != 10*time.Minute {
+					t.Errorf("projected event alarms = %+v, want one 10 minutes before", ev.Alarms)
+				}
+			}
+		})
+	}
+}
+
+func TestDoseCalendarRegimenChange(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	// The interval changes to a day between the doses due on the 2nd, so only
+	// the dose after the one due at 20:00 is a day after it.
+	regimen := hrtclicker.HRTConfig{
+		Type:     hrtclicker.TypeGel,
+		Interval: cfgtypes.Duration(12 * time.Hour),
+		Changes: []hrtclicker.RegimenChange{
+			{EffectiveAt: at(2, 10), Interval: cfgtypes.Duration(24 * time.Hour)},
+		},
+	}
+	doses := []db.HRTHistory{{DosageAt: at(1, 20), HRTType: string(regimen.Type)}}
+	due := []time.Time{at(2, 8), at(2, 20), at(3, 20)}
+
+	cal := doseCalendar(regimen, doses, due, 10*time.Minute)
+
+	want := []string{"12h0m0s", "12h0m0s", "24h0m0s"}
+	for i, ev := range cal.Events[len(doses):] {
+		if !strings.HasPrefix(ev.Description, "Projected from the last dose every "+want[i]+",") {
+			t.Errorf("event due at %s has description %q, want every %s", ev.Start, ev.Description, want[i])
+		}
+	}
+}
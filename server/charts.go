@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/chart"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/labs"
+	"libdb.so/hrtclicker/levels"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/predict"
+)
+
+const (
+	// defaultChartRange is how far back the level charts go by default. It
+	// matches the range of the JavaScript chart.
+	defaultChartRange = 30 * 24 * time.Hour
+	// maxChartRange is the longest range a level chart may cover.
+	maxChartRange = 366 * 24 * time.Hour
+	// maxChartSize is the largest width or height of a chart in pixels.
+	maxChartSize = 4096
+	// minChartSize is the smallest width or height of a chart in pixels.
+	minChartSize = 100
+)
+
+// levelsChart creates the chart of the predicted levels of the regimen over
+// the given duration until now. The chart has no levels if they cannot be
+// predicted for the regimen.
+func levelsChart(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, d time.Duration) (chart.Levels, error) {
+	now := time.Now()
+
+	applications, removals, err := levels.Applications(ctx, database, regimen, now.Add(-d), now)
+	if err != nil {
+		return chart.Levels{}, err
+	}
+
+	// Without a model for the regimen, the chart says there are no levels to
+	// show.
+	var values []predict.TimeValue
+	if _, ok := predict.ForType(regimen.Type); ok {
+		values, err = predict.Regimen(regimen, applications, removals, now.Add(-d), now)
+		if err != nil {
+			return chart.Levels{}, fmt.Errorf("cannot predict levels: %w", err)
+		}
+	}
+
+	results, err := labs.ResultsBetween(ctx, database, regimen, now.Add(-d), now)
+	if err != nil {
+		return chart.Levels{}, err
+	}
+
+	pauses, err := pause.Between(ctx, database, regimen.Type, now.Add(-d), now)
+	if err != nil {
+		return chart.Levels{}, err
+	}
+
+	return chart.Levels{
+		Values:   values,
+		Doses:    applications,
+		Points:   labs.Points(results),
+		Target:   regimen.Target,
+		Spans:    pause.ChartSpans(pauses),
+		Location: regimen.Location(),
+	}, nil
+}
+
+func (s *Server) getLevelsChart(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	d := defaultChartRange
+	if r.FormValue("range") != "" {
+		d, err = time.ParseDuration(r.FormValue("range"))
+		if err != nil {
+			write400Error(w, "failed to parse range", err)
+			return
+		}
+		if d <= 0 || d > maxChartRange {
+			write400Error(w, "invalid range", fmt.Errorf("must be positive and at most %v", maxChartRange))
+			return
+		}
+	}
+
+	width, err := chartSize(r.FormValue("width"), chart.DefaultWidth)
+	if err != nil {
+		write400Error(w, "invalid width", err)
+		return
+	}
+
+	height, err := chartSize(r.FormValue("height"), chart.DefaultHeight)
+	if err != nil {
+		write400Error(w, "invalid height", err)
+		return
+	}
+
+	c, err := levelsChart(r.Context(), s.Database, regimen, d)
+	if err != nil {
+		writeError(w, "failed to create chart", err)
+		return
+	}
+	c.Width = width
+	c.Height = height
+
+	w.Header().Set("Content-Type", chart.ContentType)
+	c.WriteSVG(w)
+}
+
+// chartSize parses the width or height of a chart in pixels. An empty string
+// returns def.
+func chartSize(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if n < minChartSize || n > maxChartSize {
+		return 0, fmt.Errorf("must be between %d and %d pixels", minChartSize, maxChartSize)
+	}
+	return n, nil
+}
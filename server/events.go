@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// eventsHeartbeat is the interval between comments sent to keep idle
+// connections from being closed by proxies.
+const eventsHeartbeat = 30 * time.Second
+
+// handleEvents streams the events published on the event bus as Server-Sent
+// Events. The event name is the event type, and the data is the event data as
+// JSON.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	evs := s.Events.Subscribe(r.Context())
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case ev, ok := <-evs:
+			if !ok {
+				return
+			}
+
+			b, err := json.Marshal(ev.Data)
+			if err != nil {
+				s.Logger.Error(
+					"failed to marshal event data",
+					"event_type", ev.Type,
+					"err", err)
+				continue
+			}
+
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, b)
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker/events"
+)
+
+func TestHandleEvents(t *testing.T) {
+	s := &Server{Dependencies: Dependencies{
+		Logger: slog.Default(),
+		Events: new(events.Bus),
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(s.handleEvents))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	// The handler subscribes before it sends the headers, so the event can't
+	// be missed.
+	s.Events.Publish(events.DoseRecorded, map[string]string{"HRTType": "patches"})
+
+	var lines []string
+	body := bufio.NewScanner(resp.Body)
+	for len(lines) < 3 && body.Scan() {
+		if line := body.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	// The event starts with its random ID.
+	want := []string{"event: dose-recorded", `data: {"HRTType":"patches"}`}
+	if len(lines) < 3 || !strings.HasPrefix(lines[0], "id: ") || !slices.Equal(lines[1:], want) {
+		t.Errorf("stream = %q, want an ID followed by %q", lines, want)
+	}
+}
@@ -0,0 +1,709 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/adherence"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/removal"
+)
+
+const (
+	// historyPageSize is the number of doses on each page of the history.
+	historyPageSize = 25
+	// maxMonthDoses is the most doses shown in a month of the calendar view.
+	maxMonthDoses = 500
+)
+
+// historyStatuses are the statuses that the history can be filtered by.
+var historyStatuses = []adherence.Status{
+	adherence.StatusOnTime,
+	adherence.StatusEarly,
+	adherence.StatusLate,
+}
+
+// HistoryFilter is the filter of the doses on the history page. The exported
+// fields are the raw query values.
+type HistoryFilter struct {
+	Type string
+	// From and To are dates as "2006-01-02" in the time zone of the regimen.
+	// Both days are included.
+	From string
+	To   string
+	// Status only keeps the doses with this status if not empty.
+	Status adherence.Status
+	// Tag only keeps the doses with this tag if not empty.
+	Tag string
+	// View is either "list" or "month".
+	View string
+	// Month is the month shown in the month view as "2006-01".
+	Month string
+	// Before and After are the RFC 3339 times of the doses that the page of
+	// the list view continues from. At most one of them is set.
+	Before string
+	After  string
+
+	regimen    hrtclicker.HRTConfig
+	hasRegimen bool
+	// loc is the time zone of the regimen, or of the default one if the
+	// type has none.
+	loc    *time.Location
+	since  time.Time
+	until  time.Time
+	month  time.Time
+	before time.Time
+	after  time.Time
+}
+
+func parseHistoryFilter(r *http.Request, cfg *hrtclicker.Config) (HistoryFilter, error) {
+	f := HistoryFilter{
+		Type:   r.FormValue("type"),
+		From:   r.FormValue("from"),
+		To:     r.FormValue("to"),
+		Status: adherence.Status(r.FormValue("status")),
+		Tag:    db.NormalizeTag(r.FormValue("tag")),
+		View:   r.FormValue("view"),
+		Month:  r.FormValue("month"),
+		Before: r.FormValue("before"),
+		After:  r.FormValue("after"),
+		until:  db.EndOfTime,
+	}
+
+	if f.Type == "" {
+		f.Type = string(cfg.HRT.Type)
+	}
+	f.regimen, f.hasRegimen = cfg.Regimen(hrtclicker.HRTType(f.Type))
+	f.loc = cfg.HRT.Location()
+	if f.hasRegimen {
+		f.loc = f.regimen.Location()
+	}
+
+	var err error
+	if f.From != "" {
+		f.since, err = time.ParseInLocation("2006-01-02", f.From, f.loc)
+		if err != nil {
+			return f, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if f.To != "" {
+		f.until, err = time.ParseInLocation("2006-01-02", f.To, f.loc)
+		if err != nil {
+			return f, fmt.Errorf("invalid to: %w", err)
+		}
+		f.until = f.until.AddDate(0, 0, 1)
+	}
+	if !f.since.Before(f.until) {
+		return f, errors.New("from must not be after to")
+	}
+
+	if f.Status != "" {
+		if !slices.Contains(historyStatuses, f.Status) {
+			return f, fmt.Errorf("unknown status %q", f.Status)
+		}
+		if !f.hasRegimen {
+			return f, fmt.Errorf("cannot filter by status without a regimen for type %q", f.Type)
+		}
+	}
+
+	switch f.View {
+	case "", "list":
+		f.View = "list"
+	case "month":
+	default:
+		return f, fmt.Errorf("unknown view %q", f.View)
+	}
+
+	now := time.Now().In(f.loc)
+	f.month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, f.loc)
+	if f.Month != "" {
+		f.month, err = time.ParseInLocation("2006-01", f.Month, f.loc)
+		if err != nil {
+			return f, fmt.Errorf("invalid month: %w", err)
+		}
+	}
+
+	if f.Before != "" {
+		f.before, err = time.Parse(time.RFC3339, f.Before)
+		if err != nil {
+			return f, fmt.Errorf("invalid before: %w", err)
+		}
+	}
+	if f.After != "" {
+		f.after, err = time.Parse(time.RFC3339, f.After)
+		if err != nil {
+			return f, fmt.Errorf("invalid after: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// URL returns the URL of the history page with this filter and the given
+// query values changed. The values are given as pairs of keys and values, and
+// empty values are removed. Page cursors are always reset unless given.
+func (f HistoryFilter) URL(pairs ...string) string {
+	q := url.Values{}
+	for k, v := range map[string]string{
+		"type":   f.Type,
+		"from":   f.From,
+		"to":     f.To,
+		"status": string(f.Status),
+		"tag":    f.Tag,
+		"view":   f.View,
+		"month":  f.Month,
+	} {
+		if v != "" && !(k == "view" && v == "list") {
+			q.Set(k, v)
+		}
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			q.Del(pairs[i])
+		} else {
+			q.Set(pairs[i], pairs[i+1])
+		}
+	}
+
+	if len(q) == 0 {
+		return "/history#doses"
+	}
+	return "/history?" + q.Encode() + "#doses"
+}
+
+// HasRegimen returns true if the filtered type has a configured regimen, so
+// the doses can be compared against it.
+func (f HistoryFilter) HasRegimen() bool {
+	return f.hasRegimen
+}
+
+// matches returns true if the dose has the status and the tag of the filter.
+func (f HistoryFilter) matches(dose HistoryDose) bool {
+	return (f.Status == "" || dose.Status == f.Status) && (f.Tag == "" || dose.Tags.Has(f.Tag))
+}
+
+// Statuses returns the statuses that the history can be filtered by.
+func (f HistoryFilter) Statuses() []adherence.Status {
+	return historyStatuses
+}
+
+// HistoryDose is a dose in the history, compared against its regimen if it
+// has one.
+type HistoryDose struct {
+	HRTType string
+	Notes   string
+	Tags    db.Tags
+	// Zone is the IANA name of the time zone the dose was recorded in, or
+	// empty if it is not known.
+	Zone string
+	// Removal is when the application of the dose was taken off, or nil if
+	// it wasn't.
+	Removal *db.Removal
+	adherence.Dose
+
+	// shownIn is the time zone the page shows the dose in.
+	shownIn *time.Location
+}
+
+// Removable returns true if the application of the dose can be recorded as
+// taken off.
+func (d HistoryDose) Removable() bool {
+	return d.Removal == nil && removal.Removable(hrtclicker.HRTType(d.HRTType))
+}
+
+// HistoryPage is a page of the list view of the history.
+type HistoryPage struct {
+	// Doses are the doses on the page, newest first.
+	Doses []HistoryDose
+	// Newer and Older are the URLs of the pages next to this one, or empty if
+	// there are none.
+	Newer string
+	Older string
+}
+
+// MonthView is the calendar of a month of the history.
+type MonthView struct {
+	Month time.Time
+	// Weeks are the weeks that the month spans, from Monday to Sunday.
+	Weeks [][]MonthDay
+	// Previous and Next are the URLs of the months next to this one.
+	Previous string
+	Next     string
+}
+
+// MonthDay is a day of the month view.
+type MonthDay struct {
+	Date time.Time
+	// InMonth is false for the days of the previous and next months that fill
+	// the first and last weeks.
+	InMonth bool
+	// Doses are the doses taken on the day, oldest first.
+	Doses []HistoryDose
+}
+
+type historyData struct {
+	deps   Dependencies
+	ctx    context.Context
+	filter HistoryFilter
+	// redirect is the URL of the current page that forms return to.
+	redirect string
+}
+
+// Stats returns the adherence of the default regimen over the default
+// windows.
+func (d historyData) Stats() (Stats, error) {
+	return computeStats(d.ctx, d.deps.Database, d.deps.Config.Load().HRT, defaultStatsDays)
+}
+
+// HourBar is a bar of the time of day chart on the history page.
+type HourBar struct {
+	Hour  int
+	Count int
+	// Percent is the height of the bar relative to the tallest one.
+	Percent float64
+}
+
+// HourBars returns the bars of the time of day chart for the given window.
+func (d historyData) HourBars(w StatsWindow) []HourBar {
+	var most int
+	for _, n := range w.HourOfDay {
+		most = max(most, n)
+	}
+
+	bars := make([]HourBar, len(w.HourOfDay))
+	for hour, n := range w.HourOfDay {
+		bars[hour] = HourBar{Hour: hour, Count: n}
+		if most > 0 {
+			bars[hour].Percent = float64(n) / float64(most) * 100
+		}
+	}
+	return bars
+}
+
+// Filter returns the filter of the doses.
+func (d historyData) Filter() HistoryFilter {
+	return d.filter
+}
+
+// Redirect returns the URL of the current page.
+func (d historyData) Redirect() string {
+	return d.redirect
+}
+
+// Types returns the types of the recorded doses and the configured regimen.
+func (d historyData) Types() ([]string, error) {
+	types, err := d.deps.Database.DoseTypes(d.ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t := string(d.deps.Config.Load().HRT.Type); !slices.Contains(types, t) {
+		types = append(types, t)
+		slices.Sort(types)
+	}
+	return types, nil
+}
+
+// Page returns the page of the list view.
+func (d historyData) Page() (HistoryPage, error) {
+	f := d.filter
+	var page HistoryPage
+
+	if !f.after.IsZero() {
+		doses, more, err := d.newerDoses(f.after, historyPageSize)
+		if err != nil {
+			return page, err
+		}
+		page.Doses = doses
+		if more {
+			page.Newer = f.URL("after", rfc3339(doses[0].DosageAt))
+		}
+		if len(doses) > 0 {
+			page.Older = f.URL("before", rfc3339(doses[len(doses)-1].DosageAt))
+		} else {
+			page.Older = f.URL()
+		}
+		return page, nil
+	}
+
+	before := f.until
+	if !f.before.IsZero() && f.before.Before(before) {
+		before = f.before
+	}
+
+	doses, more, err := d.olderDoses(before, historyPageSize)
+	if err != nil {
+		return page, err
+	}
+	page.Doses = doses
+	if more {
+		page.Older = f.URL("before", rfc3339(doses[len(doses)-1].DosageAt))
+	}
+	if !f.before.IsZero() {
+		if len(doses) > 0 {
+			page.Newer = f.URL("after", rfc3339(doses[0].DosageAt))
+		} else {
+			page.Newer = f.URL()
+		}
+	}
+	return page, nil
+}
+
+// Month returns the month view.
+func (d historyData) Month() (MonthView, error) {
+	f := d.filter
+	start := f.month
+	end := start.AddDate(0, 1, 0)
+
+	view := MonthView{
+		Month:    start,
+		Previous: f.URL("month", start.AddDate(0, -1, 0).Format("2006-01")),
+		Next:     f.URL("month", end.Format("2006-01")),
+	}
+
+	since := start
+	if f.since.After(since) {
+		since = f.since
+	}
+	until := end
+	if f.until.Before(until) {
+		until = f.until
+	}
+
+	var doses []HistoryDose
+	if since.Before(until) {
+		rows, err := d.deps.Database.HistoryPage(d.ctx, db.HistoryPageParams{
+			HRTType: f.Type,
+			Since:   since.UTC(),
+			Before:  until.UTC(),
+			Limit:   maxMonthDoses,
+		})
+		if err != nil {
+			return view, fmt.Errorf("failed to get doses: %w", err)
+		}
+		doses, err = d.compareDesc(rows)
+		if err != nil {
+			return view, err
+		}
+	}
+
+	// Doses are newest first, so prepend them to keep each day in order.
+	byDay := make(map[string][]HistoryDose)
+	for _, dose := range doses {
+		if !f.matches(dose) {
+			continue
+		}
+		day := dose.DosageAt.In(f.loc).Format(time.DateOnly)
+		byDay[day] = append([]HistoryDose{dose}, byDay[day]...)
+	}
+
+	// Weeks start on Monday.
+	day := start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	for day.Before(end) {
+		week := make([]MonthDay, 7)
+		for i := range week {
+			week[i] = MonthDay{
+				Date:    day,
+				InMonth: day.Month() == start.Month(),
+				Doses:   byDay[day.Format(time.DateOnly)],
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+		view.Weeks = append(view.Weeks, week)
+	}
+
+	return view, nil
+}
+
+// olderDoses returns up to limit doses matching the filter from before the
+// given time, newest first. more is true if there are older ones.
+func (d historyData) olderDoses(before time.Time, limit int) (doses []HistoryDose, more bool, err error) {
+	f := d.filter
+	for {
+		rows, err := d.deps.Database.HistoryPage(d.ctx, db.HistoryPageParams{
+			HRTType: f.Type,
+			Since:   f.since.UTC(),
+			Before:  before.UTC(),
+			Limit:   int64(limit + 1),
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get doses: %w", err)
+		}
+		if len(rows) == 0 {
+			return doses, false, nil
+		}
+
+		compared, err := d.compareDesc(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, dose := range compared {
+			if !f.matches(dose) {
+				continue
+			}
+			if len(doses) == limit {
+				return doses, true, nil
+			}
+			doses = append(doses, dose)
+		}
+
+		if len(rows) <= limit {
+			return doses, false, nil
+		}
+		before = rows[len(rows)-1].DosageAt
+	}
+}
+
+// newerDoses returns up to limit doses matching the filter from after the
+// given time, newest first. more is true if there are newer ones.
+func (d historyData) newerDoses(after time.Time, limit int) (doses []HistoryDose, more bool, err error) {
+	f := d.filter
+	if after.Before(f.since) {
+		after = f.since.Add(-time.Second)
+	}
+
+	for {
+		rows, err := d.deps.Database.HistoryPageAfter(d.ctx, db.HistoryPageAfterParams{
+			HRTType: f.Type,
+			After:   after.UTC(),
+			Before:  f.until.UTC(),
+			Limit:   int64(limit + 1),
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get doses: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		// Compare them newest first like the other pages, and keep them in
+		// ascending order until the page is full.
+		slices.Reverse(rows)
+		compared, err := d.compareDesc(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		slices.Reverse(compared)
+
+		for _, dose := range compared {
+			if !f.matches(dose) {
+				continue
+			}
+			if len(doses) == limit {
+				more = true
+				break
+			}
+			doses = append(doses, dose)
+		}
+
+		if more || len(rows) <= limit {
+			break
+		}
+		after = rows[0].DosageAt
+	}
+
+	slices.Reverse(doses)
+	return doses, more, nil
+}
+
+// ZonedDosageAt returns the time the dose was taken in the time zone it was
+// recorded in. It returns the zero time if that is not known or is the time
+// zone of the page, since the dose is already shown in it.
+func (d HistoryDose) ZonedDosageAt() time.Time {
+	if d.Zone == "" || (d.shownIn != nil && d.Zone == d.shownIn.String()) {
+		return time.Time{}
+	}
+	loc, err := time.LoadLocation(d.Zone)
+	if err != nil {
+		return time.Time{}
+	}
+	return d.DosageAt.In(loc)
+}
+
+// compareDesc compares the doses, which are of the same type and sorted newest
+// first with no gaps between them, against the regimen of the filter.
+func (d historyData) compareDesc(rows []db.HRTHistory) ([]HistoryDose, error) {
+	doses := make([]HistoryDose, len(rows))
+	for i, row := range rows {
+		doses[i] = HistoryDose{
+			HRTType: row.HRTType,
+			Notes:   row.Notes,
+			Tags:    row.Tags,
+			Zone:    row.Zone,
+			Dose:    adherence.Dose{DosageAt: row.DosageAt},
+			shownIn: d.filter.loc,
+		}
+
+		rm, err := d.deps.Database.Removal(d.ctx, row.DosageAt)
+		switch {
+		case err == nil:
+			doses[i].Removal = &rm
+		case !db.IsNotFound(err):
+			return nil, fmt.Errorf("failed to get removal: %w", err)
+		}
+	}
+
+	if !d.filter.hasRegimen || len(rows) == 0 {
+		return doses, nil
+	}
+
+	last := rows[len(rows)-1]
+	prev, err := d.deps.Database.DoseBefore(d.ctx, db.DoseBeforeParams{
+		HRTType:  last.HRTType,
+		DosageAt: last.DosageAt,
+	})
+	if err != nil && !db.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the dose before: %w", err)
+	}
+
+	pauses, err := pause.SpansBetween(d.ctx, d.deps.Database, d.filter.regimen.Type, prev.DosageAt, rows[0].DosageAt.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	skips, err := missed.Between(d.ctx, d.deps.Database, d.filter.regimen.Type, prev.DosageAt, rows[0].DosageAt.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(rows) - 1; i >= 0; i-- {
+		doses[i].Dose = adherence.Compare(d.filter.regimen, pauses, skips, prev.DosageAt, rows[i].DosageAt)
+		prev = rows[i]
+	}
+	return doses, nil
+}
+
+func rfc3339(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	filter, err := parseHistoryFilter(r, s.Config.Load())
+	if err != nil {
+		write400Error(w, "invalid filter", err)
+		return
+	}
+
+	s.Templates.Execute(w, "history", historyData{
+		deps:     s.Dependencies,
+		ctx:      r.Context(),
+		filter:   filter,
+		redirect: r.URL.RequestURI(),
+	})
+}
+
+// DoseUpdate is the data of the events.DoseUpdated event.
+type DoseUpdate struct {
+	DosageAt         time.Time
+	HRTType          string
+	Notes            string
+	Tags             db.Tags
+	PreviousDosageAt time.Time
+	PreviousHRTType  string
+}
+
+func (s *Server) handleUpdateDosage(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, r.FormValue("at"))
+	if err != nil {
+		write400Error(w, "failed to parse at", err)
+		return
+	}
+	at = at.UTC()
+
+	dose, err := s.Database.DoseAt(r.Context(), at)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no dose at the given time", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to get dose", err)
+		return
+	}
+
+	update := DoseUpdate{
+		DosageAt:         dose.DosageAt,
+		HRTType:          dose.HRTType,
+		Notes:            dose.Notes,
+		Tags:             dose.Tags,
+		PreviousDosageAt: dose.DosageAt,
+		PreviousHRTType:  dose.HRTType,
+	}
+	if v := r.FormValue("new_at"); v != "" {
+		update.DosageAt, err = parseTimeIn(v, s.location(hrtclicker.HRTType(dose.HRTType)))
+		if err != nil {
+			write400Error(w, "failed to parse new_at", err)
+			return
+		}
+		update.DosageAt = update.DosageAt.UTC().Truncate(time.Second)
+	}
+	if v := r.FormValue("new_type"); v != "" {
+		if !hrtclicker.HRTType(v).IsValid() {
+			write400Error(w, "invalid new_type", fmt.Errorf("unknown type %q", v))
+			return
+		}
+		update.HRTType = v
+	}
+	// Notes and tags are only changed if they are given, even if empty.
+	if _, ok := r.Form["notes"]; ok {
+		update.Notes = strings.TrimSpace(r.FormValue("notes"))
+	}
+	if _, ok := r.Form["tags"]; ok || r.Form["tag"] != nil {
+		update.Tags = parseTags(r)
+	}
+
+	_, err = s.Database.UpdateDose(r.Context(), db.UpdateDoseParams{
+		NewDosageAt: update.DosageAt,
+		NewHRTType:  update.HRTType,
+		Notes:       update.Notes,
+		Tags:        update.Tags,
+		DosageAt:    update.PreviousDosageAt,
+	})
+	if err != nil {
+		if db.IsAlreadyExists(err) {
+			write400Error(w, "failed to update dose", fmt.Errorf("a dose at %s already exists", update.DosageAt))
+			return
+		}
+		writeError(w, "failed to update dose", err)
+		return
+	}
+
+	s.Events.Publish(events.DoseUpdated, update)
+
+	if wantsJSON(r) {
+		writeJSON(w, update)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+// parseTimeIn parses an RFC 3339 time or a time without a time zone, as sent
+// by datetime-local inputs, in the given time zone.
+func parseTimeIn(v string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", v, loc); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04", v, loc)
+}
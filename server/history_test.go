@@ -0,0 +1,465 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/adherence"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+func filterFor(t *testing.T, query string) HistoryFilter {
+	t.Helper()
+
+	r := httptest.NewRequest("GET", "/history?"+query, nil)
+	f, err := parseHistoryFilter(r, &hrtclicker.Config{HRT: hrttest.Daily})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestParseHistoryFilter(t *testing.T) {
+	// Dates are in the time zone of the regimen.
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    HistoryFilter
+		wantErr bool
+	}{
+		{
+			name:  "default",
+			query: "",
+			want: HistoryFilter{
+				Type:  "sublingual",
+				View:  "list",
+				until: db.EndOfTime,
+			},
+		},
+		{
+			name:  "dates including the last day",
+			query: "from=2026-03-01&to=2026-03-14",
+			want: HistoryFilter{
+				Type:  "sublingual",
+				From:  "2026-03-01",
+				To:    "2026-03-14",
+				View:  "list",
+				since: day(3, 1),
+				until: day(3, 15),
+			},
+		},
+		{
+			name:  "status and month",
+			query: "status=late&view=month&month=2026-02",
+			want: HistoryFilter{
+				Type:   "sublingual",
+				Status: adherence.StatusLate,
+				View:   "month",
+				Month:  "2026-02",
+				until:  db.EndOfTime,
+				month:  day(2, 1),
+			},
+		},
+		{
+			name:  "tag",
+			query: "tag=Hot++Flashes",
+			want: HistoryFilter{
+				Type:  "sublingual",
+				Tag:   "hot flashes",
+				View:  "list",
+				until: db.EndOfTime,
+			},
+		},
+		{
+			name:  "cursor",
+			query: "before=2026-03-14T08:00:00Z",
+			want: HistoryFilter{
+				Type:   "sublingual",
+				View:   "list",
+				Before: "2026-03-14T08:00:00Z",
+				until:  db.EndOfTime,
+				before: hrttest.Date(14, 8, 0),
+			},
+		},
+		{
+			name:  "other type",
+			query: "type=gel",
+			want: HistoryFilter{
+				Type:  "gel",
+				View:  "list",
+				until: db.EndOfTime,
+			},
+		},
+		{
+			name:    "from after to",
+			query:   "from=2026-03-14&to=2026-03-01",
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			query:   "from=March",
+			wantErr: true,
+		},
+		{
+			name:    "unknown status",
+			query:   "status=forgotten",
+			wantErr: true,
+		},
+		{
+			name:    "status without a regimen",
+			query:   "type=gel&status=late",
+			wantErr: true,
+		},
+		{
+			name:    "unknown view",
+			query:   "view=year",
+			wantErr: true,
+		},
+		{
+			name:    "invalid cursor",
+			query:   "after=yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/history?"+test.query, nil)
+			got, err := parseHistoryFilter(r, &hrtclicker.Config{HRT: hrttest.Daily})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseHistoryFilter() = %v, want an error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// Only compare the month if it was given, since it defaults to
+			// the current one.
+			if test.want.Month == "" {
+				got.month = time.Time{}
+			}
+			if got.hasRegimen != (got.Type == string(hrttest.Daily.Type)) {
+				t.Errorf("parseHistoryFilter() hasRegimen = %v for type %s", got.hasRegimen, got.Type)
+			}
+			got.regimen, got.hasRegimen = hrtclicker.HRTConfig{}, false
+			if got.loc != time.UTC {
+				t.Errorf("parseHistoryFilter() loc = %s, want UTC", got.loc)
+			}
+			got.loc = nil
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseHistoryFilter() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHistoryPages(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	// Every third dose of the month is late, and every fifth one is tagged.
+	var doses, late, tagged []time.Time
+	for day := 1; day <= 30; day++ {
+		hour := 8
+		if day%3 == 0 {
+			hour = 11
+		}
+		dosageAt := hrttest.Date(day, hour, 0)
+		doses = append(doses, dosageAt)
+		if hour != 8 {
+			late = append(late, dosageAt)
+		}
+		tags := db.NewTags()
+		if day%5 == 0 {
+			tags = db.NewTags("nausea")
+			tagged = append(tagged, dosageAt)
+		}
+		if err := database.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: dosageAt,
+			HRTType:  string(hrttest.Daily.Type),
+			Tags:     tags,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.Reverse(doses)
+	slices.Reverse(late)
+	slices.Reverse(tagged)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []time.Time
+	}{
+		{"all", "", doses},
+		{"late", "status=late", late},
+		{"range", "from=2026-03-11&to=2026-03-19&status=late", late[4:7]},
+		{"tag", "tag=Nausea", tagged},
+		{"late and tagged", "status=late&tag=nausea", []time.Time{hrttest.Date(30, 11, 0), hrttest.Date(15, 11, 0)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := historyData{
+				deps:   Dependencies{Database: database},
+				ctx:    ctx,
+				filter: filterFor(t, test.query),
+			}
+
+			// Page through all doses from the newest to the oldest in pages
+			// of 4, which is less than the doses between the late ones.
+			var pages [][]HistoryDose
+			before := d.filter.until
+			for {
+				page, more, err := d.olderDoses(before, 4)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pages = append(pages, page)
+				if !more {
+					break
+				}
+				before = page[len(page)-1].DosageAt
+			}
+
+			var got []time.Time
+			for _, page := range pages {
+				for _, dose := range page {
+					got = append(got, dose.DosageAt)
+				}
+			}
+			if !slices.EqualFunc(got, test.want, time.Time.Equal) {
+				t.Fatalf("older pages = %v, want %v", got, test.want)
+			}
+
+			// Page back to the newest from the last page.
+			for i := len(pages) - 1; i > 0; i-- {
+				after := pages[i][0].DosageAt
+				page, more, err := d.newerDoses(after, 4)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.EqualFunc(page, pages[i-1], sameDose) {
+					t.Errorf("newer page %d = %v, want %v", i-1, page, pages[i-1])
+				}
+				if more != (i > 1) {
+					t.Errorf("newer page %d has more: %v", i-1, more)
+				}
+			}
+		})
+	}
+}
+
+func sameDose(a, b HistoryDose) bool {
+	return a.DosageAt.Equal(b.DosageAt) && a.Status == b.Status && slices.Equal(a.Tags, b.Tags)
+}
+
+func TestHistoryPageCursors(t *testing.T) {
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+	for day := 1; day <= 3; day++ {
+		if err := database.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: hrttest.Date(day, 8, 0),
+			HRTType:  string(hrttest.Daily.Type),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantDoses int
+		wantNewer string
+		wantOlder string
+	}{
+		{
+			name:      "first page",
+			wantDoses: 3,
+		},
+		{
+			name:      "before a dose",
+			query:     "before=2026-03-03T08:00:00Z",
+			wantDoses: 2,
+			wantNewer: "/history?after=2026-03-02T08%3A00%3A00Z&type=sublingual#doses",
+		},
+		{
+			name:      "after a dose",
+			query:     "after=2026-03-01T08:00:00Z",
+			wantDoses: 2,
+			wantOlder: "/history?before=2026-03-02T08%3A00%3A00Z&type=sublingual#doses",
+		},
+		{
+			name:      "after the last dose",
+			query:     "after=2026-03-03T08:00:00Z",
+			wantOlder: "/history?type=sublingual#doses",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := historyData{
+				deps:   Dependencies{Database: database},
+				ctx:    ctx,
+				filter: filterFor(t, test.query),
+			}
+			page, err := d.Page()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Doses) != test.wantDoses || page.Newer != test.wantNewer || page.Older != test.wantOlder {
+				t.Errorf("Page() = %d doses, newer %q, older %q, want %d doses, newer %q, older %q",
+					len(page.Doses), page.Newer, page.Older, test.wantDoses, test.wantNewer, test.wantOlder)
+			}
+		})
+	}
+}
+
+func TestHistoryMonth(t *testing.T) {
+	regimen := hrttest.Daily
+	regimen.TimeZone = "America/New_York"
+	loc, err := time.LoadLocation(regimen.TimeZone)
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	ctx := context.Background()
+	database := hrttest.OpenDB(t)
+
+	// The doses are grouped by their day in the time zone of the regimen, not
+	// in UTC or the local time zone.
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 3, day, hour, 0, 0, 0, loc)
+	}
+	for _, dosageAt := range []time.Time{at(1, 8), at(1, 23), at(2, 0), at(31, 8)} {
+		if err := database.RecordDosage(ctx, db.RecordDosageParams{
+			DosageAt: dosageAt.UTC(),
+			HRTType:  string(regimen.Type),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/history?view=month&month=2026-03", nil)
+	filter, err := parseHistoryFilter(r, &hrtclicker.Config{HRT: regimen})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := historyData{
+		deps:   Dependencies{Database: database},
+		ctx:    ctx,
+		filter: filter,
+	}
+	view, err := d.Month()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// March 2026 starts on a Sunday and ends on a Tuesday.
+	if len(view.Weeks) != 6 {
+		t.Fatalf("Month() has %d weeks, want 6", len(view.Weeks))
+	}
+	if first := view.Weeks[0][0]; !first.Date.Equal(time.Date(2026, 2, 23, 0, 0, 0, 0, loc)) || first.InMonth {
+		t.Errorf("first day = %s, in month: %v, want Monday February 23", first.Date, first.InMonth)
+	}
+
+	days := make(map[int]int)
+	for _, week := range view.Weeks {
+		for _, day := range week {
+			if day.InMonth {
+				days[day.Date.Day()] = len(day.Doses)
+			} else if len(day.Doses) > 0 {
+				t.Errorf("%s outside of the month has doses", day.Date)
+			}
+		}
+	}
+	if days[1] != 2 || days[2] != 1 || days[31] != 1 {
+		t.Errorf("doses by day = %v, want 2 on the 1st and 1 on the 2nd and 31st", days)
+	}
+	if day := view.Weeks[0][6]; !day.Doses[0].DosageAt.Before(day.Doses[1].DosageAt) {
+		t.Errorf("doses on the 1st are not oldest first: %v", day.Doses)
+	}
+}
+
+func TestHourBars(t *testing.T) {
+	var w StatsWindow
+	w.HourOfDay = [24]int{8: 4, 9: 1, 20: 2}
+
+	bars := historyData{}.HourBars(w)
+	if len(bars) != 24 {
+		t.Fatalf("HourBars() returned %d bars, want 24", len(bars))
+	}
+
+	tests := []struct {
+		hour    int
+		count   int
+		percent float64
+	}{
+		{0, 0, 0},
+		{8, 4, 100},
+		{9, 1, 25},
+		{20, 2, 50},
+	}
+
+	for _, test := range tests {
+		bar := bars[test.hour]
+		if bar.Hour != test.hour || bar.Count != test.count || bar.Percent != test.percent {
+			t.Errorf("HourBars()[%d] = %+v, want %d doses at %v%%", test.hour, bar, test.count, test.percent)
+		}
+	}
+}
+
+func TestZonedDosageAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	// Summer time starts in Berlin on 2026-03-29, so the same time in UTC is
+	// an hour later there.
+	tests := []struct {
+		name     string
+		zone     string
+		dosageAt time.Time
+		want     string
+	}{
+		{"unknown zone", "", hrttest.Date(28, 7, 0), ""},
+		{"zone of the page", "UTC", hrttest.Date(28, 7, 0), ""},
+		{"invalid zone", "Nowhere/Special", hrttest.Date(28, 7, 0), ""},
+		{"winter time", "Europe/Berlin", hrttest.Date(28, 7, 0), "2026-03-28 08:00 CET"},
+		{"summer time", "Europe/Berlin", hrttest.Date(30, 7, 0), "2026-03-30 09:00 CEST"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dose := HistoryDose{
+				Zone:    test.zone,
+				Dose:    adherence.Dose{DosageAt: test.dosageAt},
+				shownIn: time.UTC,
+			}
+			got := dose.ZonedDosageAt()
+
+			var gotStr string
+			if !got.IsZero() {
+				gotStr = got.Format("2006-01-02 15:04 MST")
+				if got.Location().String() != berlin.String() {
+					t.Errorf("ZonedDosageAt() is in %s, want %s", got.Location(), berlin)
+				}
+			}
+			if gotStr != test.want {
+				t.Errorf("ZonedDosageAt() = %q, want %q", gotStr, test.want)
+			}
+		})
+	}
+}
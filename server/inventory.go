@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/inventory"
+)
+
+type inventoryData struct {
+	regimen hrtclicker.HRTConfig
+	deps    Dependencies
+	ctx     context.Context
+}
+
+func (d inventoryData) Inventory() (inventory.Inventory, error) {
+	return inventory.Load(d.ctx, d.deps.Database, d.regimen, time.Now())
+}
+
+func (s *Server) handleInventory(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	s.Templates.Execute(w, "inventory", inventoryData{
+		regimen: regimen,
+		deps:    s.Dependencies,
+		ctx:     r.Context(),
+	})
+}
+
+func (s *Server) getInventory(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	inv, err := inventory.Load(r.Context(), s.Database, regimen, time.Now())
+	if err != nil {
+		writeError(w, "failed to load inventory", err)
+		return
+	}
+
+	writeJSON(w, inv)
+}
+
+// stockForm is the stock entry fields of the add and update forms.
+type stockForm struct {
+	Medication string
+	Quantity   float64
+	Remaining  float64
+	Lot        string
+	ExpiresAt  sql.NullTime
+}
+
+// parseStockForm parses the stock entry fields. The remaining amount defaults
+// to the quantity. The expiry date is a date in loc, and the stock can be used
+// until the end of it.
+func parseStockForm(r *http.Request, loc *time.Location) (stockForm, error) {
+	f := stockForm{
+		Medication: strings.TrimSpace(r.FormValue("medication")),
+		Lot:        strings.TrimSpace(r.FormValue("lot")),
+	}
+	if f.Medication == "" {
+		return f, errors.New("medication: missing")
+	}
+
+	var err error
+
+	f.Quantity, err = strconv.ParseFloat(r.FormValue("quantity"), 64)
+	if err != nil || f.Quantity <= 0 {
+		return f, fmt.Errorf("quantity: %q is not a positive number", r.FormValue("quantity"))
+	}
+
+	f.Remaining = f.Quantity
+	if v := r.FormValue("remaining"); v != "" {
+		f.Remaining, err = strconv.ParseFloat(v, 64)
+		if err != nil || f.Remaining < 0 || f.Remaining > f.Quantity {
+			return f, fmt.Errorf("remaining: %q is not a number between 0 and the quantity", v)
+		}
+	}
+
+	if v := r.FormValue("expires"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return f, fmt.Errorf("expires: %w", err)
+		}
+		f.ExpiresAt = sql.NullTime{Time: t.UTC(), Valid: true}
+	}
+
+	return f, nil
+}
+
+func (s *Server) handleAddStock(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	f, err := parseStockForm(r, regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid stock", err)
+		return
+	}
+
+	var prescriptionID sql.NullInt64
+	if v := r.FormValue("prescription_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			write400Error(w, "invalid prescription_id", err)
+			return
+		}
+		prescriptionID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	stock, err := inventory.AddStock(r.Context(), s.Database, regimen.Location(), db.AddStockParams{
+		HRTType:        string(regimen.Type),
+		Medication:     f.Medication,
+		Quantity:       f.Quantity,
+		Remaining:      f.Remaining,
+		Lot:            f.Lot,
+		ExpiresAt:      f.ExpiresAt,
+		AddedAt:        time.Now().UTC().Truncate(time.Second),
+		PrescriptionID: prescriptionID,
+	})
+	if err != nil {
+		switch {
+		case db.IsNotFound(err):
+			write400Error(w, "no such prescription", err)
+		case errors.Is(err, inventory.ErrPrescriptionExpired),
+			errors.Is(err, inventory.ErrNoRefills),
+			errors.Is(err, inventory.ErrWrongType):
+			write400Error(w, "cannot fill prescription", err)
+		default:
+			writeError(w, "failed to add stock", err)
+		}
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, stock)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleUpdateStock(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	f, err := parseStockForm(r, s.location(hrtclicker.HRTType(r.FormValue("type"))))
+	if err != nil {
+		write400Error(w, "invalid stock", err)
+		return
+	}
+
+	stock, err := s.Database.UpdateStock(r.Context(), db.UpdateStockParams{
+		Medication: f.Medication,
+		Quantity:   f.Quantity,
+		Remaining:  f.Remaining,
+		Lot:        f.Lot,
+		ExpiresAt:  f.ExpiresAt,
+		ID:         id,
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such stock", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to update stock", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, stock)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeleteStock(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	stock, err := s.Database.DeleteStock(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such stock", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete stock", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, stock)
+		return
+	}
+
+	redirectBack(w, r)
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/journal"
+)
+
+// parseJournalRange parses the from, to and tag fields that filter the journal
+// entries, with dates in loc. Without them, every entry is included.
+func parseJournalRange(r *http.Request, loc *time.Location) (from, to time.Time, tag string, err error) {
+	to = db.EndOfTime
+
+	if r.FormValue("from") != "" {
+		from, err = parseRangeBound(r.FormValue("from"), false, loc)
+		if err != nil {
+			return from, to, tag, fmt.Errorf("from: %w", err)
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		to, err = parseRangeBound(r.FormValue("to"), true, loc)
+		if err != nil {
+			return from, to, tag, fmt.Errorf("to: %w", err)
+		}
+	}
+
+	return from, to, db.NormalizeTag(r.FormValue("tag")), nil
+}
+
+func (s *Server) getJournal(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	from, to, tag, err := parseJournalRange(r, regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid range", err)
+		return
+	}
+
+	entries, err := journal.Between(r.Context(), s.Database, regimen.Type, from, to, tag)
+	if err != nil {
+		writeError(w, "failed to get journal", err)
+		return
+	}
+
+	writeJSON(w, entries)
+}
+
+func (s *Server) getJournalAnalysis(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	from, to, tag, err := parseJournalRange(r, regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid range", err)
+		return
+	}
+
+	analysis, err := journal.Analyze(r.Context(), s.Database, regimen, from, to, tag)
+	if err != nil {
+		writeError(w, "failed to analyze journal", err)
+		return
+	}
+
+	writeJSON(w, analysis)
+}
+
+// journalForm is the fields of the journal entry add and update forms.
+type journalForm struct {
+	WrittenAt time.Time
+	Mood      sql.NullInt64
+	Notes     string
+	Tags      db.Tags
+}
+
+// parseJournalForm parses the entry fields. The time defaults to now. An entry must have at least a
+// mood, notes or a tag.
+func parseJournalForm(r *http.Request, loc *time.Location) (journalForm, error) {
+	f := journalForm{
+		WrittenAt: time.Now(),
+		Notes:     strings.TrimSpace(r.FormValue("notes")),
+		Tags:      parseTags(r),
+	}
+
+	if v := r.FormValue("at"); v != "" {
+		t, err := parseTimeIn(v, loc)
+		if err != nil {
+			return f, fmt.Errorf("at: %w", err)
+		}
+		f.WrittenAt = t
+	}
+	if f.WrittenAt.After(time.Now().Add(time.Minute)) {
+		return f, errors.New("at: must not be in the future")
+	}
+	f.WrittenAt = f.WrittenAt.UTC().Truncate(time.Second)
+
+	if v := r.FormValue("mood"); v != "" {
+		mood, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || mood < journal.MinMood || mood > journal.MaxMood {
+			return f, fmt.Errorf("mood: %q is not a number from %d to %d", v, journal.MinMood, journal.MaxMood)
+		}
+		f.Mood = sql.NullInt64{Int64: mood, Valid: true}
+	}
+
+	if !f.Mood.Valid && f.Notes == "" && len(f.Tags) == 0 {
+		return f, errors.New("entry needs a mood, notes or a tag")
+	}
+
+	return f, nil
+}
+
+func (s *Server) handleAddJournalEntry(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	f, err := parseJournalForm(r, regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid entry", err)
+		return
+	}
+
+	entry, err := journal.Add(r.Context(), s.Database, db.AddJournalEntryParams{
+		HRTType:   string(regimen.Type),
+		WrittenAt: f.WrittenAt,
+		Mood:      f.Mood,
+		Notes:     f.Notes,
+		AddedAt:   time.Now().UTC().Truncate(time.Second),
+	}, f.Tags)
+	if err != nil {
+		writeError(w, "failed to add entry", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, entry)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleUpdateJournalEntry(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	if r.FormValue("at") == "" {
+		write400Error(w, "invalid entry", errors.New("at: missing"))
+		return
+	}
+
+	f, err := parseJournalForm(r, s.location(hrtclicker.HRTType(r.FormValue("type"))))
+	if err != nil {
+		write400Error(w, "invalid entry", err)
+		return
+	}
+
+	entry, err := journal.Update(r.Context(), s.Database, db.UpdateJournalEntryParams{
+		WrittenAt: f.WrittenAt,
+		Mood:      f.Mood,
+		Notes:     f.Notes,
+		ID:        id,
+	}, f.Tags)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such entry", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to update entry", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, entry)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeleteJournalEntry(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	entry, err := s.Database.DeleteJournalEntry(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such entry", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete entry", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, entry)
+		return
+	}
+
+	redirectBack(w, r)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/internal/hrttest"
+	"libdb.so/hrtclicker/journal"
+	"libdb.so/hrtclicker/web"
+)
+
+func TestParseJournalForm(t *testing.T) {
+	// Times without a time zone are in the time zone of the regimen.
+	loc := time.FixedZone("UTC+2", 2*60*60)
+
+	tests := []struct {
+		name     string
+		form     string
+		wantMood int64
+		wantTags []string
+		wantAt   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "mood",
+			form:     "mood=4",
+			wantMood: 4,
+			wantTags: []string{},
+		},
+		{
+			name:     "tag fields and list",
+			form:     "tag=Fatigue&tag=headache&tags=nausea,+fatigue",
+			wantTags: []string{"fatigue", "headache", "nausea"},
+		},
+		{
+			name:     "time in the regimen's time zone",
+			form:     "mood=2&at=2026-03-01T20:00",
+			wantMood: 2,
+			wantTags: []string{},
+			wantAt:   hrttest.Date(1, 18, 0),
+		},
+		{
+			name:    "mood out of range",
+			form:    "mood=6",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			form:    "notes=+",
+			wantErr: true,
+		},
+		{
+			name:    "in the future",
+			form:    "mood=3&at=3000-01-01T00:00",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/journal/add", strings.NewReader(test.form))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.ParseForm()
+
+			f, err := parseJournalForm(r, loc)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseJournalForm() = %v, want an error: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if f.Mood.Int64 != test.wantMood || f.Mood.Valid != (test.wantMood != 0) {
+				t.Errorf("parseJournalForm() mood = %v, want %d", f.Mood, test.wantMood)
+			}
+			if !slices.Equal(f.Tags, test.wantTags) {
+				t.Errorf("parseJournalForm() tags = %q, want %q", f.Tags, test.wantTags)
+			}
+			if !test.wantAt.IsZero() && !f.WrittenAt.Equal(test.wantAt) {
+				t.Errorf("parseJournalForm() time = %s, want %s", f.WrittenAt, test.wantAt)
+			}
+		})
+	}
+}
+
+func TestJournalHandlers(t *testing.T) {
+	s := New(Dependencies{
+		Logger:    slog.Default(),
+		Database:  hrttest.OpenDB(t),
+		Config:    hrttest.Config(t, &hrtclicker.Config{HRT: hrttest.Daily}),
+		Templates: web.EmbeddedTemplates(),
+		Events:    new(events.Bus),
+	})
+
+	// do sends the form to the path and decodes the JSON response into v.
+	do := func(method, path string, form url.Values, v any) {
+		t.Helper()
+
+		r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s %s = %d: %s", method, path, w.Code, w.Body)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+
+	var added journal.Entry
+	do("POST", "/api/journal/add", url.Values{
+		"at":   {"2026-03-01T20:00:00Z"},
+		"mood": {"3"},
+		"tags": {"Fatigue"},
+	}, &added)
+	if added.HRTType != string(hrttest.Daily.Type) || !added.WrittenAt.Equal(hrttest.Date(1, 20, 0)) {
+		t.Errorf("added entry = %+v", added)
+	}
+
+	var updated journal.Entry
+	do("POST", "/api/journal/update", url.Values{
+		"id":    {strconv.FormatInt(added.ID, 10)},
+		"at":    {"2026-03-01T21:00:00Z"},
+		"notes": {"slept well"},
+	}, &updated)
+
+	var entries []journal.Entry
+	do("GET", "/api/journal?from=2026-02-28", nil, &entries)
+	if len(entries) != 1 || entries[0].Notes != "slept well" || len(entries[0].Tags) != 0 || !entries[0].WrittenAt.Equal(hrttest.Date(1, 21, 0)) {
+		t.Errorf("entries after the update = %+v", entries)
+	}
+
+	var deleted journal.Entry
+	do("POST", "/api/journal/delete", url.Values{"id": {strconv.FormatInt(added.ID, 10)}}, &deleted)
+
+	do("GET", "/api/journal", nil, &entries)
+	if len(entries) != 0 {
+		t.Errorf("entries after deleting = %+v, want none", entries)
+	}
+}
@@ -0,0 +1,421 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/labs"
+	"libdb.so/hrtclicker/levels"
+)
+
+// labLinkWindow is how far from an appointment a result may have been drawn
+// to be linked to it automatically.
+const labLinkWindow = 12 * time.Hour
+
+type labsData struct {
+	regimen hrtclicker.HRTConfig
+	deps    Dependencies
+	ctx     context.Context
+}
+
+func (d labsData) Labs() (labs.Labs, error) {
+	return labs.Load(d.ctx, d.deps.Database, d.regimen, time.Now())
+}
+
+func (s *Server) handleLabs(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	s.Templates.Execute(w, "labs", labsData{
+		regimen: regimen,
+		deps:    s.Dependencies,
+		ctx:     r.Context(),
+	})
+}
+
+func (s *Server) getLabs(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	l, err := labs.Load(r.Context(), s.Database, regimen, time.Now())
+	if err != nil {
+		writeError(w, "failed to load labs", err)
+		return
+	}
+
+	writeJSON(w, l)
+}
+
+func (s *Server) getLabWindows(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	target, err := parseLabTarget(r.FormValue("target"))
+	if err != nil {
+		write400Error(w, "invalid target", err)
+		return
+	}
+
+	n := 1
+	if v := r.FormValue("n"); v != "" {
+		n, err = strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 100 {
+			write400Error(w, "invalid n", fmt.Errorf("%q is not a number between 1 and 100", v))
+			return
+		}
+	}
+
+	windows, err := s.labWindows(r.Context(), regimen, target, n)
+	if err != nil {
+		if errors.Is(err, labs.ErrNoDoses) {
+			write400Error(w, "cannot find draw windows", err)
+			return
+		}
+		writeError(w, "failed to find draw windows", err)
+		return
+	}
+
+	writeJSON(w, windows)
+}
+
+// labWindows returns the next n draw windows for the target from now.
+func (s *Server) labWindows(ctx context.Context, regimen hrtclicker.HRTConfig, target labs.Target, n int) ([]labs.Window, error) {
+	now := time.Now()
+
+	applications, _, err := levels.Applications(ctx, s.Database, regimen, now, db.EndOfTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return labs.Windows(regimen, applications, now, target, n)
+}
+
+// parseLabTarget parses the target of a draw, which defaults to the trough.
+func parseLabTarget(v string) (labs.Target, error) {
+	if v == "" {
+		return labs.TargetTrough, nil
+	}
+	target := labs.Target(v)
+	if !target.IsValid() {
+		return "", fmt.Errorf("%q is not trough or peak", v)
+	}
+	return target, nil
+}
+
+// labAppointmentForm is the fields of the appointment add and update forms.
+type labAppointmentForm struct {
+	Target      labs.Target
+	ScheduledAt time.Time
+	Note        string
+}
+
+// parseLabAppointmentForm parses the appointment fields. An empty time
+// schedules the appointment at the ideal time of the next draw window for the
+// target.
+func (s *Server) parseLabAppointmentForm(r *http.Request, regimen hrtclicker.HRTConfig) (labAppointmentForm, error) {
+	f := labAppointmentForm{
+		Note: strings.TrimSpace(r.FormValue("note")),
+	}
+
+	var err error
+	f.Target, err = parseLabTarget(r.FormValue("target"))
+	if err != nil {
+		return f, fmt.Errorf("target: %w", err)
+	}
+
+	if v := r.FormValue("at"); v != "" {
+		f.ScheduledAt, err = parseTimeIn(v, regimen.Location())
+		if err != nil {
+			return f, fmt.Errorf("at: %w", err)
+		}
+	} else {
+		windows, err := s.labWindows(r.Context(), regimen, f.Target, 1)
+		if err != nil {
+			return f, fmt.Errorf("at: cannot pick a time: %w", err)
+		}
+		if len(windows) == 0 {
+			return f, errors.New("at: no upcoming draw window")
+		}
+		f.ScheduledAt = windows[0].Ideal
+	}
+
+	f.ScheduledAt = f.ScheduledAt.UTC().Truncate(time.Minute)
+	return f, nil
+}
+
+func (s *Server) handleAddLabAppointment(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	f, err := s.parseLabAppointmentForm(r, regimen)
+	if err != nil {
+		write400Error(w, "invalid appointment", err)
+		return
+	}
+
+	appointment, err := s.Database.AddLabAppointment(r.Context(), db.AddLabAppointmentParams{
+		HRTType:     string(regimen.Type),
+		Target:      string(f.Target),
+		ScheduledAt: f.ScheduledAt,
+		Note:        f.Note,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		writeError(w, "failed to add appointment", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, appointment)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleUpdateLabAppointment(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	f, err := s.parseLabAppointmentForm(r, regimen)
+	if err != nil {
+		write400Error(w, "invalid appointment", err)
+		return
+	}
+
+	appointment, err := s.Database.UpdateLabAppointment(r.Context(), db.UpdateLabAppointmentParams{
+		Target:      string(f.Target),
+		ScheduledAt: f.ScheduledAt,
+		Note:        f.Note,
+		ID:          id,
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such appointment", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to update appointment", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, appointment)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeleteLabAppointment(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	appointment, err := s.Database.DeleteLabAppointment(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such appointment", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete appointment", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, appointment)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+// handleAddLabResult adds a lab result. Without an appointment_id, the result
+// is linked to the closest appointment without a result within labLinkWindow
+// of the draw, if there is one.
+func (s *Server) handleAddLabResult(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	drawnAt, err := parseTimeIn(r.FormValue("drawn_at"), regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid drawn_at", err)
+		return
+	}
+	if drawnAt.After(time.Now().Add(time.Minute)) {
+		write400Error(w, "invalid drawn_at", errors.New("must not be in the future"))
+		return
+	}
+	drawnAt = drawnAt.UTC().Truncate(time.Minute)
+
+	value, err := strconv.ParseFloat(r.FormValue("value"), 64)
+	if err != nil || value < 0 {
+		write400Error(w, "invalid value", fmt.Errorf("%q is not a number of at least 0", r.FormValue("value")))
+		return
+	}
+
+	var appointmentID sql.NullInt64
+	if v := r.FormValue("appointment_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			write400Error(w, "invalid appointment_id", err)
+			return
+		}
+
+		appointment, err := s.Database.LabAppointment(r.Context(), id)
+		if err != nil {
+			if db.IsNotFound(err) {
+				write400Error(w, "invalid appointment_id", errors.New("no such appointment"))
+				return
+			}
+			writeError(w, "failed to get appointment", err)
+			return
+		}
+		if appointment.HRTType != string(regimen.Type) {
+			write400Error(w, "invalid appointment_id", errors.New("appointment is for a different type"))
+			return
+		}
+
+		appointmentID = sql.NullInt64{Int64: id, Valid: true}
+	} else {
+		appointments, err := s.Database.OpenLabAppointmentsBetween(r.Context(), db.OpenLabAppointmentsBetweenParams{
+			HRTType: string(regimen.Type),
+			Since:   drawnAt.Add(-labLinkWindow),
+			Before:  drawnAt.Add(labLinkWindow),
+		})
+		if err != nil {
+			writeError(w, "failed to get appointments", err)
+			return
+		}
+		var closest time.Duration
+		for _, appointment := range appointments {
+			d := appointment.ScheduledAt.Sub(drawnAt).Abs()
+			if !appointmentID.Valid || d < closest {
+				appointmentID = sql.NullInt64{Int64: appointment.ID, Valid: true}
+				closest = d
+			}
+		}
+	}
+
+	result, err := s.Database.AddLabResult(r.Context(), db.AddLabResultParams{
+		HRTType:       string(regimen.Type),
+		DrawnAt:       drawnAt,
+		Value:         value,
+		AppointmentID: appointmentID,
+		Note:          strings.TrimSpace(r.FormValue("note")),
+		AddedAt:       time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		if db.IsAlreadyExists(err) {
+			write400Error(w, "invalid appointment_id", errors.New("appointment already has a result"))
+			return
+		}
+		writeError(w, "failed to add result", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, result)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeleteLabResult(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	result, err := s.Database.DeleteLabResult(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such result", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete result", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, result)
+		return
+	}
+
+	redirectBack(w, r)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"libdb.so/hrtclicker/internal/stats"
+	"libdb.so/hrtclicker/predict"
+)
+
+// levelStatsWindows are the windows that the averages of the predicted levels
+// are shown for on the index page.
+var levelStatsWindows = []struct {
+	Name string
+	Days int
+}{
+	{"Daily", 1},
+	{"Weekly", 7},
+	{"Bi-weekly", 14},
+	{"Monthly", 30},
+}
+
+// levelStatsPercentiles are the upper percentiles shown with the averages. The
+// lower percentiles are 100 minus these.
+var levelStatsPercentiles = []int{95, 99}
+
+// LevelStats are the statistics of the predicted levels over the last ForDays
+// days. It is stored into the index page as JSON for the dosage details.
+type LevelStats struct {
+	Name    string
+	ForDays int
+	Mean    float64
+	StdDev  float64
+	// Quantiles maps percentiles, such as 95 for the 95th percentile, to the
+	// level below which that percentage of the levels lie.
+	Quantiles map[int]float64
+}
+
+// Percentiles returns the upper percentiles that are shown for the window.
+func (s LevelStats) Percentiles() []int {
+	return levelStatsPercentiles
+}
+
+// computeLevelStats computes the statistics of the given hourly levels for each
+// of levelStatsWindows, using the last values that fall within each window.
+// Windows with fewer than two values are left out.
+func computeLevelStats(values []predict.TimeValue) []LevelStats {
+	var out []LevelStats
+	for _, window := range levelStatsWindows {
+		n := min(len(values), window.Days*24)
+		if n < 2 {
+			continue
+		}
+
+		levels := make([]float64, n)
+		for i, v := range values[len(values)-n:] {
+			levels[i] = v.V
+		}
+
+		s := LevelStats{
+			Name:      window.Name,
+			ForDays:   window.Days,
+			Mean:      stats.Mean(levels),
+			StdDev:    stats.StdDev(levels),
+			Quantiles: make(map[int]float64, 2*len(levelStatsPercentiles)),
+		}
+		for _, p := range levelStatsPercentiles {
+			s.Quantiles[p] = stats.Quantile(levels, float64(p)/100)
+			s.Quantiles[100-p] = stats.Quantile(levels, float64(100-p)/100)
+		}
+		out = append(out, s)
+	}
+	return out
+}
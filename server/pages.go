@@ -2,23 +2,39 @@ package server
 
 import (
 	"context"
+	"html/template"
 	"net/http"
 	"time"
 
 	"libdb.so/hrtclicker"
 	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/inventory"
+	"libdb.so/hrtclicker/journal"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/removal"
+	"libdb.so/hrtclicker/schedule"
+	"libdb.so/hrtclicker/travel"
 )
 
+// recentDoses is the number of doses listed on the index page.
+const recentDoses = 5
+
 type indexData struct {
 	HRTType hrtclicker.HRTType
 	deps    Dependencies
 	ctx     context.Context
 }
 
+// NextDoseTime returns the time the next dose is due, following the trip it is
+// shifted by and moved to the end of the pause it falls due in, if any. It returns a zero time if no doses have been
+// recorded yet or if it falls due in a pause without an end.
 func (d indexData) NextDoseTime() (time.Time, error) {
 	s := d.deps
+	regimen := s.Config.Load().HRT
 
-	dose, err := s.Database.LastDose(d.ctx, string(s.Config.HRT.Type))
+	dose, err := s.Database.LastDose(d.ctx, string(regimen.Type))
 	if err != nil {
 		if db.IsNotFound(err) {
 			return time.Time{}, nil
@@ -26,20 +42,259 @@ func (d indexData) NextDoseTime() (time.Time, error) {
 		return time.Time{}, err
 	}
 
-	return dose.DosageAt.Add(time.Duration(s.Config.HRT.Interval)), nil
+	next, _, err := pause.NextDoseAt(d.ctx, s.Database, regimen, dose.DosageAt, time.Now())
+	return next, err
+}
+
+// Paused returns the pause the regimen is in, or nil if it isn't paused.
+func (d indexData) Paused() (*db.Pause, error) {
+	p, err := pause.Active(d.ctx, d.deps.Database, d.deps.Config.Load().HRT.Type, time.Now())
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// travelStatus is the trip the next dose is shifted by.
+type travelStatus struct {
+	travel.Trip
+	// Left is the number of doses left to shift, including the next one.
+	Left int
+}
+
+// Travel returns the trip the next dose is shifted by, or nil if it isn't.
+func (d indexData) Travel() (*travelStatus, error) {
+	regimen := d.deps.Config.Load().HRT
+
+	dose, err := d.deps.Database.LastDose(d.ctx, string(regimen.Type))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	trip, step, ok, err := travel.Current(d.ctx, d.deps.Database, regimen, dose.DosageAt)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &travelStatus{Trip: trip, Left: len(trip.Steps) - step}, nil
+}
+
+// cycleStatus is where today falls in the cycle of a cyclic regimen.
+type cycleStatus struct {
+	Day    int
+	Length int
+	// Active is true if doses are due today.
+	Active bool
+	// BreakEndsAt is the start of the first active day after the current
+	// or next break, or zero if the cycle has no breaks.
+	BreakEndsAt time.Time
+}
+
+// Cycle returns the current day of the cycle, or nil if the regimen is not
+// cyclic.
+func (d indexData) Cycle() *cycleStatus {
+	regimen := d.deps.Config.Load().HRT
+	cycle := regimen.Cycle
+	if cycle == nil {
+		return nil
+	}
+
+	now, loc := time.Now(), regimen.Location()
+	return &cycleStatus{
+		Day:         cycle.Day(now, loc),
+		Length:      cycle.Length,
+		Active:      cycle.ActiveAt(now, loc),
+		BreakEndsAt: cycle.NextActiveDay(now, loc),
+	}
 }
 
-func (d indexData) DosageHistory() ([]db.HRTHistory, error) {
-	return d.deps.Database.DosageHistory(d.ctx, string(d.deps.Config.HRT.Type))
+// Advice returns what to do about the next dose once it is late, or nil if it
+// isn't.
+func (d indexData) Advice() (*missed.Advice, error) {
+	next, err := d.NextDoseTime()
+	if err != nil {
+		return nil, err
+	}
+
+	advice, err := schedule.Advise(d.ctx, d.deps.Database, d.deps.Config.Load().HRT, next, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if advice == nil || advice.Phase == missed.PhaseOnTime {
+		return nil, nil
+	}
+	return advice, nil
+}
+
+// SnoozedUntil returns the time the reminder for the next dose is snoozed
+// until. It returns a zero time if the reminder is not snoozed.
+func (d indexData) SnoozedUntil() (time.Time, error) {
+	s := d.deps
+
+	dose, err := s.Database.LastDose(d.ctx, string(s.Config.Load().HRT.Type))
+	if err != nil {
+		if db.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	until, err := s.Database.SnoozedUntil(d.ctx, dose.DosageAt)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return until, nil
+}
+
+// RecentDoses returns the last few doses, newest first.
+func (d indexData) RecentDoses() ([]db.HRTHistory, error) {
+	return d.deps.Database.LastDoses(d.ctx, db.LastDosesParams{
+		HRTType: string(d.deps.Config.Load().HRT.Type),
+		Limit:   recentDoses,
+	})
+}
+
+// ChartDoses returns the doses that the JavaScript chart needs to predict the
+// levels over defaultChartRange, oldest first.
+func (d indexData) ChartDoses() ([]db.HRTHistory, error) {
+	regimen := d.deps.Config.Load().HRT
+	lookback, _ := predict.Lookback(regimen.Type)
+
+	now := time.Now()
+	return d.deps.Database.DosesBetween(d.ctx, string(regimen.Type), now.Add(-defaultChartRange-lookback), now)
+}
+
+// ChartRemovals returns the removals of the ChartDoses, so that the
+// JavaScript chart stops each removed application at its removal.
+func (d indexData) ChartRemovals() ([]db.Removal, error) {
+	regimen := d.deps.Config.Load().HRT
+	lookback, _ := predict.Lookback(regimen.Type)
+
+	now := time.Now()
+	return d.deps.Database.RemovalsBetween(d.ctx, db.RemovalsBetweenParams{
+		HRTType: string(regimen.Type),
+		Since:   now.Add(-defaultChartRange - lookback).UTC(),
+		Before:  now.UTC(),
+	})
+}
+
+// ChartPauses returns the pauses within defaultChartRange, so that the
+// JavaScript chart shades them.
+func (d indexData) ChartPauses() ([]db.Pause, error) {
+	now := time.Now()
+	return pause.Between(d.ctx, d.deps.Database, d.deps.Config.Load().HRT.Type, now.Add(-defaultChartRange), now)
+}
+
+// CurrentApplications returns the doses of the regimen whose applications are
+// still on, oldest first, if they can be taken off.
+func (d indexData) CurrentApplications() ([]db.HRTHistory, error) {
+	regimen := d.deps.Config.Load().HRT
+	if !removal.Removable(regimen.Type) {
+		return nil, nil
+	}
+	return removal.Current(d.ctx, d.deps.Database, regimen, time.Now())
 }
 
 func (d indexData) HRTConfig() hrtclicker.HRTConfig {
-	return d.deps.Config.HRT
+	return d.deps.Config.Load().HRT
+}
+
+// chartChange is the regimen in effect from Since on, as used by the chart
+// script to predict the levels.
+type chartChange struct {
+	Since         time.Time
+	IntervalHours float64
+	Concurrence   int
+	Strength      float64
+}
+
+// ChartChanges returns the regimen in effect after each of its changes.
+func (d indexData) ChartChanges() []chartChange {
+	regimen := d.deps.Config.Load().HRT
+	changes := make([]chartChange, len(regimen.Changes))
+	for i, change := range regimen.Changes {
+		r := regimen.At(change.EffectiveAt)
+		changes[i] = chartChange{
+			Since:         change.EffectiveAt,
+			IntervalHours: r.Interval.AsDuration().Hours(),
+			Concurrence:   r.Concurrence,
+			Strength:      r.DoseStrength(),
+		}
+	}
+	return changes
+}
+
+// UpcomingChange returns the next change to the regimen, or nil if none is
+// scheduled.
+func (d indexData) UpcomingChange() *hrtclicker.RegimenChange {
+	changes := d.deps.Config.Load().HRT.ChangesBetween(time.Now(), db.EndOfTime)
+	if len(changes) == 0 {
+		return nil
+	}
+	return &changes[0]
+}
+
+// Inventory returns the projected supply of the regimen.
+func (d indexData) Inventory() (inventory.Status, error) {
+	inv, err := inventory.Load(d.ctx, d.deps.Database, d.deps.Config.Load().HRT, time.Now())
+	return inv.Status, err
+}
+
+// JournalTags returns the tags offered on the journal quick entry form.
+func (d indexData) JournalTags() []string {
+	return journal.CommonTags
+}
+
+// CalendarURL returns the URL of the calendar feed, or an empty string if it
+// is disabled.
+func (d indexData) CalendarURL() string {
+	return calendarURL(d.deps.Config.Load())
+}
+
+// LevelsChart renders the chart of the predicted levels as an inline SVG. It
+// is shown until the JavaScript chart replaces it. An empty string is returned
+// if the levels of the regimen cannot be predicted.
+func (d indexData) LevelsChart() (template.HTML, error) {
+	regimen := d.deps.Config.Load().HRT
+	if _, ok := predict.ForType(regimen.Type); !ok {
+		return "", nil
+	}
+
+	c, err := levelsChart(d.ctx, d.deps.Database, regimen, defaultChartRange)
+	if err != nil {
+		return "", err
+	}
+	return c.SVG(), nil
+}
+
+// LevelStats returns the statistics of the predicted levels shown in the
+// dosage details. It returns nil if the levels of the regimen cannot be
+// predicted.
+func (d indexData) LevelStats() ([]LevelStats, error) {
+	regimen := d.deps.Config.Load().HRT
+	if _, ok := predict.ForType(regimen.Type); !ok {
+		return nil, nil
+	}
+
+	c, err := levelsChart(d.ctx, d.deps.Database, regimen, defaultChartRange)
+	if err != nil {
+		return nil, err
+	}
+	return computeLevelStats(c.Values), nil
 }
 
 func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 	s.Templates.Execute(w, "index", indexData{
-		HRTType: s.Config.HRT.Type,
+		HRTType: s.Config.Load().HRT.Type,
 		deps:    s.Dependencies,
 		ctx:     r.Context(),
 	})
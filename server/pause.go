@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/pause"
+)
+
+func (s *Server) getPauses(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	pauses, err := s.Database.Pauses(r.Context(), string(regimen.Type))
+	if err != nil {
+		writeError(w, "failed to get pauses", err)
+		return
+	}
+
+	writeJSON(w, pauses)
+}
+
+// handleAddPause pauses the regimen. The pause starts at started_at or now,
+// and lasts until ended_at or until the regimen is resumed.
+func (s *Server) handleAddPause(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	startedAt := time.Now()
+	if v := r.FormValue("started_at"); v != "" {
+		startedAt, err = parseTimeIn(v, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse started_at", err)
+			return
+		}
+	}
+
+	var endedAt time.Time
+	if v := r.FormValue("ended_at"); v != "" {
+		endedAt, err = parseTimeIn(v, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse ended_at", err)
+			return
+		}
+	}
+
+	p, err := pause.Start(r.Context(), s.Database, regimen.Type, startedAt, endedAt, strings.TrimSpace(r.FormValue("reason")))
+	if err != nil {
+		if errors.Is(err, pause.ErrEndBeforeStart) || errors.Is(err, pause.ErrOverlaps) {
+			write400Error(w, "failed to pause", err)
+			return
+		}
+		writeError(w, "failed to pause", err)
+		return
+	}
+
+	s.Events.Publish(events.RegimenPaused, p)
+
+	if wantsJSON(r) {
+		writeJSON(w, p)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+// handleResume ends the pause the regimen is in now.
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	p, err := pause.Resume(r.Context(), s.Database, regimen.Type, time.Now())
+	if err != nil {
+		if errors.Is(err, pause.ErrNotPaused) {
+			write400Error(w, "failed to resume", err)
+			return
+		}
+		writeError(w, "failed to resume", err)
+		return
+	}
+
+	s.Events.Publish(events.RegimenResumed, p)
+
+	if wantsJSON(r) {
+		writeJSON(w, p)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeletePause(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	p, err := s.Database.DeletePause(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such pause", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete pause", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, p)
+		return
+	}
+
+	redirectBack(w, r)
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/inventory"
+)
+
+func (s *Server) getPrescriptions(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	prescriptions, err := inventory.Prescriptions(r.Context(), s.Database, regimen.Type)
+	if err != nil {
+		writeError(w, "failed to get prescriptions", err)
+		return
+	}
+
+	writeJSON(w, prescriptions)
+}
+
+// prescriptionForm is the prescription fields of the add and update forms.
+type prescriptionForm struct {
+	Prescriber string
+	Medication string
+	Strength   string
+	Refills    int64
+	WrittenAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// parsePrescriptionForm parses the prescription fields. The dates are dates in
+// loc, and the written date defaults to today. Refills default to none.
+func parsePrescriptionForm(r *http.Request, loc *time.Location) (prescriptionForm, error) {
+	f := prescriptionForm{
+		Prescriber: strings.TrimSpace(r.FormValue("prescriber")),
+		Medication: strings.TrimSpace(r.FormValue("medication")),
+		Strength:   strings.TrimSpace(r.FormValue("strength")),
+	}
+	if f.Prescriber == "" {
+		return f, errors.New("prescriber: missing")
+	}
+	if f.Medication == "" {
+		return f, errors.New("medication: missing")
+	}
+
+	if v := r.FormValue("refills"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("refills: %q is not a number of at least 0", v)
+		}
+		f.Refills = n
+	}
+
+	y, m, d := time.Now().In(loc).Date()
+	written := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	if v := r.FormValue("written"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			return f, fmt.Errorf("written: %w", err)
+		}
+		written = t
+	}
+
+	v := r.FormValue("expires")
+	if v == "" {
+		return f, errors.New("expires: missing")
+	}
+	expires, err := time.ParseInLocation("2006-01-02", v, loc)
+	if err != nil {
+		return f, fmt.Errorf("expires: %w", err)
+	}
+	if expires.Before(written) {
+		return f, errors.New("expires: must not be before the written date")
+	}
+
+	f.WrittenAt = written.UTC()
+	f.ExpiresAt = expires.UTC()
+	return f, nil
+}
+
+func (s *Server) handleAddPrescription(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	f, err := parsePrescriptionForm(r, regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid prescription", err)
+		return
+	}
+
+	prescription, err := s.Database.AddPrescription(r.Context(), db.AddPrescriptionParams{
+		HRTType:    string(regimen.Type),
+		Prescriber: f.Prescriber,
+		Medication: f.Medication,
+		Strength:   f.Strength,
+		Refills:    f.Refills,
+		WrittenAt:  f.WrittenAt,
+		ExpiresAt:  f.ExpiresAt,
+		AddedAt:    time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		writeError(w, "failed to add prescription", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, prescription)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleUpdatePrescription(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	f, err := parsePrescriptionForm(r, s.location(hrtclicker.HRTType(r.FormValue("type"))))
+	if err != nil {
+		write400Error(w, "invalid prescription", err)
+		return
+	}
+
+	prescription, err := s.Database.UpdatePrescription(r.Context(), db.UpdatePrescriptionParams{
+		Prescriber: f.Prescriber,
+		Medication: f.Medication,
+		Strength:   f.Strength,
+		Refills:    f.Refills,
+		WrittenAt:  f.WrittenAt,
+		ExpiresAt:  f.ExpiresAt,
+		ID:         id,
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such prescription", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to update prescription", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, prescription)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeletePrescription(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	prescription, err := s.Database.DeletePrescription(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such prescription", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete prescription", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, prescription)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+// getDoseSources returns the stock and prescription each dose in the range
+// drew from, oldest first. A dose that drew from several stock entries appears
+// once for each of them.
+func (s *Server) getDoseSources(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	since := time.Time{}
+	before := db.EndOfTime
+
+	if r.FormValue("from") != "" {
+		since, err = parseRangeBound(r.FormValue("from"), false, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse from", err)
+			return
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		before, err = parseRangeBound(r.FormValue("to"), true, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse to", err)
+			return
+		}
+	}
+
+	sources, err := s.Database.DoseSources(r.Context(), db.DoseSourcesParams{
+		HRTType: string(regimen.Type),
+		Since:   since.UTC(),
+		Before:  before.UTC(),
+	})
+	if err != nil {
+		writeError(w, "failed to get dose sources", err)
+		return
+	}
+
+	writeJSON(w, sources)
+}
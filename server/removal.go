@@ -0,0 +1,157 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/removal"
+)
+
+func (s *Server) getRemovals(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	since := time.Time{}
+	before := db.EndOfTime
+
+	if r.FormValue("range") != "" {
+		d, err := time.ParseDuration(r.FormValue("range"))
+		if err != nil {
+			write400Error(w, "failed to parse range", err)
+			return
+		}
+		since = time.Now().Add(-d)
+	}
+
+	if r.FormValue("from") != "" {
+		since, err = parseRangeBound(r.FormValue("from"), false, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse from", err)
+			return
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		before, err = parseRangeBound(r.FormValue("to"), true, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse to", err)
+			return
+		}
+	}
+
+	removals, err := s.Database.RemovalsBetween(r.Context(), db.RemovalsBetweenParams{
+		HRTType: string(regimen.Type),
+		Since:   since.UTC(),
+		Before:  before.UTC(),
+	})
+	if err != nil {
+		writeError(w, "failed to get removals", err)
+		return
+	}
+
+	writeJSON(w, removals)
+}
+
+func (s *Server) handleRemoveDosage(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	// Without a dose, the most recent one is removed.
+	var dosageAt time.Time
+	if v := r.FormValue("at"); v != "" {
+		dosageAt, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			write400Error(w, "failed to parse at", err)
+			return
+		}
+	}
+
+	removedAt := time.Now()
+	if v := r.FormValue("removed_at"); v != "" {
+		removedAt, err = parseTimeIn(v, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse removed_at", err)
+			return
+		}
+	}
+	if removedAt.After(time.Now().Add(time.Minute)) {
+		write400Error(w, "invalid removed_at", errors.New("must not be in the future"))
+		return
+	}
+
+	rm, err := removal.Add(r.Context(), s.Database, regimen, dosageAt, removedAt, strings.TrimSpace(r.FormValue("reason")))
+	if err != nil {
+		switch {
+		case db.IsNotFound(err):
+			http.Error(w, "no such dose", http.StatusNotFound)
+		case db.IsAlreadyExists(err):
+			write400Error(w, "failed to remove dose", errors.New("the dose was already removed"))
+		case errors.Is(err, removal.ErrNotRemovable),
+			errors.Is(err, removal.ErrWrongType),
+			errors.Is(err, removal.ErrBeforeDose):
+			write400Error(w, "failed to remove dose", err)
+		default:
+			writeError(w, "failed to remove dose", err)
+		}
+		return
+	}
+
+	s.Events.Publish(events.DoseRemoved, rm)
+
+	if wantsJSON(r) {
+		writeJSON(w, rm)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleUnremoveDosage(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, r.FormValue("at"))
+	if err != nil {
+		write400Error(w, "failed to parse at", err)
+		return
+	}
+
+	rm, err := s.Database.DeleteRemoval(r.Context(), at.UTC())
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no removal of the given dose", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete removal", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, rm)
+		return
+	}
+
+	redirectBack(w, r)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"libdb.so/hrtclicker/report"
+)
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	from, to, err := report.ParseRange(r.FormValue("from"), r.FormValue("to"), time.Now(), regimen.Location())
+	if err != nil {
+		write400Error(w, "invalid range", err)
+		return
+	}
+
+	rep, err := report.Build(r.Context(), s.Database, regimen, from, to)
+	if err != nil {
+		writeError(w, "failed to build report", err)
+		return
+	}
+
+	// Render into a buffer first so that errors don't end up in a half-written
+	// page that looks complete when printed.
+	var b bytes.Buffer
+	if err := rep.Render(&b, s.Templates); err != nil {
+		writeError(w, "failed to render report", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.FormValue("download") != "" {
+		w.Header().Set("Content-Disposition",
+			`attachment; filename="hrtclicker-report-`+to.Format("2006-01-02")+`.html"`)
+	}
+	w.Write(b.Bytes())
+}
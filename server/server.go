@@ -7,21 +7,29 @@ import (
 	"log/slog"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"libdb.so/hrtclicker"
 	"libdb.so/hrtclicker/db"
-	"libdb.so/hrtclicker/internal/notifier"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/inventory"
+	"libdb.so/hrtclicker/levels"
+	"libdb.so/hrtclicker/notify"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/schedule"
 	"libdb.so/hrtclicker/web"
 )
 
 type Dependencies struct {
 	Logger    *slog.Logger
 	Database  *db.SQLiteDB
-	Config    *hrtclicker.Config
+	Config    *hrtclicker.ReloadableConfig
 	Templates *web.Templates
+	Events    *events.Bus
 }
 
 type Server struct {
@@ -38,11 +46,62 @@ func New(deps Dependencies) *Server {
 
 	r.Get("/", s.handleIndex)
 	r.Get("/dosages.json", s.getDosagesJSON)
+	r.Get("/history", s.handleHistory)
+	r.Get("/webhooks", s.handleWebhooks)
+	r.Get("/calendar.ics", s.getCalendar)
+	r.Get("/report", s.handleReport)
+	r.Get("/charts/levels.svg", s.getLevelsChart)
+	r.Get("/inventory", s.handleInventory)
+	r.Get("/labs", s.handleLabs)
+	r.Get("/travel", s.handleTravel)
 
 	r.Route("/api", func(r chi.Router) {
+		r.Get("/events", s.handleEvents)
 		r.Post("/notify/test", s.handleGotifyTest)
+		r.Get("/dosage/next", s.getNextDose)
 		r.Post("/dosage/record", s.handleRecordDosage)
 		r.Post("/dosage/delete", s.handleDeleteDosage)
+		r.Post("/dosage/update", s.handleUpdateDosage)
+		r.Post("/dosage/snooze", s.handleSnooze)
+		r.Post("/dosage/skip", s.handleSkip)
+		r.Get("/dosage/removals", s.getRemovals)
+		r.Post("/dosage/remove", s.handleRemoveDosage)
+		r.Post("/dosage/unremove", s.handleUnremoveDosage)
+		r.Get("/dosage/sources", s.getDoseSources)
+		r.Get("/skips", s.getSkips)
+		r.Post("/skips/delete", s.handleDeleteSkip)
+		r.Get("/pauses", s.getPauses)
+		r.Post("/pauses/add", s.handleAddPause)
+		r.Post("/pauses/resume", s.handleResume)
+		r.Post("/pauses/delete", s.handleDeletePause)
+		r.Get("/levels", s.getLevels)
+		r.Get("/stats", s.getStats)
+		r.Get("/inventory", s.getInventory)
+		r.Post("/inventory/add", s.handleAddStock)
+		r.Post("/inventory/update", s.handleUpdateStock)
+		r.Post("/inventory/delete", s.handleDeleteStock)
+		r.Get("/prescriptions", s.getPrescriptions)
+		r.Post("/prescriptions/add", s.handleAddPrescription)
+		r.Post("/prescriptions/update", s.handleUpdatePrescription)
+		r.Post("/prescriptions/delete", s.handleDeletePrescription)
+		r.Get("/labs", s.getLabs)
+		r.Get("/labs/windows", s.getLabWindows)
+		r.Post("/labs/appointments/add", s.handleAddLabAppointment)
+		r.Post("/labs/appointments/update", s.handleUpdateLabAppointment)
+		r.Post("/labs/appointments/delete", s.handleDeleteLabAppointment)
+		r.Post("/labs/results/add", s.handleAddLabResult)
+		r.Post("/labs/results/delete", s.handleDeleteLabResult)
+		r.Get("/trips", s.getTrips)
+		r.Post("/trips/add", s.handleAddTrip)
+		r.Post("/trips/delete", s.handleDeleteTrip)
+		r.Get("/journal", s.getJournal)
+		r.Get("/journal/analysis", s.getJournalAnalysis)
+		r.Post("/journal/add", s.handleAddJournalEntry)
+		r.Post("/journal/update", s.handleUpdateJournalEntry)
+		r.Post("/journal/delete", s.handleDeleteJournalEntry)
+		r.Get("/export", s.handleExport)
+		r.Post("/import", s.handleImport)
+		r.Get("/webhooks/schemas/{type}.json", s.getWebhookSchema)
 	})
 
 	r.Route("/static", func(r chi.Router) {
@@ -54,70 +113,313 @@ func New(deps Dependencies) *Server {
 	return s
 }
 
+// regimen returns the regimen requested using the "type" form value, falling
+// back to the default regimen. r.ParseForm must have been called.
+func (s *Server) regimen(r *http.Request) (hrtclicker.HRTConfig, error) {
+	t := hrtclicker.HRTType(r.FormValue("type"))
+	regimen, ok := s.Config.Load().Regimen(t)
+	if !ok {
+		return hrtclicker.HRTConfig{}, fmt.Errorf("no regimen configured for type %q", t)
+	}
+	return regimen, nil
+}
+
+// location returns the time zone of the regimen of the given type, or of the
+// default regimen if the type has none.
+func (s *Server) location(t hrtclicker.HRTType) *time.Location {
+	cfg := s.Config.Load()
+	if regimen, ok := cfg.Regimen(t); ok {
+		return regimen.Location()
+	}
+	return cfg.HRT.Location()
+}
+
 func (s *Server) getDosagesJSON(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseForm(); err != nil {
 		write400Error(w, "failed to parse form", err)
 		return
 	}
 
-	doses, err := s.Database.DosageHistory(r.Context(), string(s.Config.HRT.Type))
+	regimen, err := s.regimen(r)
 	if err != nil {
-		writeError(w, "failed to get dosage history", err)
+		write400Error(w, "invalid type", err)
 		return
 	}
 
+	since := time.Time{}
+	before := db.EndOfTime
+
 	if r.FormValue("range") != "" {
 		d, err := time.ParseDuration(r.FormValue("range"))
 		if err != nil {
 			write400Error(w, "failed to parse range", err)
 			return
 		}
+		since = time.Now().Add(-d)
+	}
 
-		after := time.Now().Add(-d)
-		i := slices.IndexFunc(doses, func(dose db.HRTHistory) bool {
-			return dose.DosageAt.Before(after)
-		})
-		if i != -1 {
-			doses = doses[:i]
+	if r.FormValue("from") != "" {
+		since, err = parseRangeBound(r.FormValue("from"), false, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse from", err)
+			return
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		before, err = parseRangeBound(r.FormValue("to"), true, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse to", err)
+			return
+		}
+	}
+
+	// SQLite treats a negative limit as no limit.
+	limit := int64(-1)
+	if r.FormValue("limit") != "" {
+		limit, err = strconv.ParseInt(r.FormValue("limit"), 10, 64)
+		if err != nil || limit <= 0 {
+			write400Error(w, "invalid limit", fmt.Errorf("%q is not a positive number", r.FormValue("limit")))
+			return
 		}
 	}
 
-	// Reverse the order so the most recent dose is first.
+	// Take the last doses within the range if limited, then reverse them so
+	// that the oldest dose is first.
+	var doses []db.HRTHistory
+	if tag := db.NormalizeTag(r.FormValue("tag")); tag != "" {
+		doses, err = s.Database.TaggedHistoryPage(r.Context(), db.TaggedHistoryPageParams{
+			HRTType: string(regimen.Type),
+			Since:   since.UTC(),
+			Before:  before.UTC(),
+			Tag:     tag,
+			Limit:   limit,
+		})
+	} else {
+		doses, err = s.Database.HistoryPage(r.Context(), db.HistoryPageParams{
+			HRTType: string(regimen.Type),
+			Since:   since.UTC(),
+			Before:  before.UTC(),
+			Limit:   limit,
+		})
+	}
+	if err != nil {
+		writeError(w, "failed to get dosage history", err)
+		return
+	}
 	slices.Reverse(doses)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(doses)
+	writeJSON(w, doses)
+}
+
+// parseTags parses the tags of a form. They are given either as a repeated tag
+// field, such as from checkboxes, or as a comma-separated tags field, or both.
+func parseTags(r *http.Request) db.Tags {
+	tags := append(slices.Clone(r.Form["tag"]), r.FormValue("tags"))
+	return db.ParseTags(strings.Join(tags, ","))
+}
+
+// parseRangeBound parses the from or to parameter of a range. Either an RFC
+// 3339 time or a date in loc is accepted. Dates given as the end of a range
+// include the whole day.
+func parseRangeBound(v string, end bool, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func (s *Server) getNextDose(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	next, err := schedule.Next(r.Context(), s.Database, regimen, time.Now())
+	if err != nil {
+		writeError(w, "failed to get next dose", err)
+		return
+	}
+
+	writeJSON(w, next)
+}
+
+func (s *Server) getLevels(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	d := 24 * time.Hour
+	if r.FormValue("range") != "" {
+		d, err = time.ParseDuration(r.FormValue("range"))
+		if err != nil {
+			write400Error(w, "failed to parse range", err)
+			return
+		}
+	}
+
+	if _, ok := predict.ForType(regimen.Type); !ok {
+		write400Error(w, "cannot predict levels", fmt.Errorf("no predictor for type %q", regimen.Type))
+		return
+	}
+
+	now := time.Now()
+
+	values, err := levels.Between(r.Context(), s.Database, regimen, now.Add(-d), now)
+	if err != nil {
+		writeError(w, "failed to predict levels", err)
+		return
+	}
+
+	writeJSON(w, values)
 }
 
 func (s *Server) handleRecordDosage(w http.ResponseWriter, r *http.Request) {
-	if err := s.Database.RecordDosage(r.Context(), string(s.Config.HRT.Type)); err != nil {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	dose := db.HRTHistory{
+		DosageAt: time.Now(),
+		HRTType:  string(regimen.Type),
+		Notes:    strings.TrimSpace(r.FormValue("notes")),
+		Tags:     parseTags(r),
+		Zone:     regimen.Zone(),
+	}
+	if at := r.FormValue("at"); at != "" {
+		dose.DosageAt, err = time.Parse(time.RFC3339, at)
+		if err != nil {
+			write400Error(w, "failed to parse at", err)
+			return
+		}
+	}
+	dose.DosageAt = dose.DosageAt.UTC().Truncate(time.Second)
+
+	if err := inventory.RecordDose(r.Context(), s.Database, regimen, dose.DosageAt, dose.Notes, dose.Tags); err != nil {
+		if db.IsAlreadyExists(err) {
+			write400Error(w, "failed to record dosage", fmt.Errorf("a dose at %s already exists", dose.DosageAt))
+			return
+		}
 		writeError(w, "failed to record dosage", err)
 		return
 	}
+
+	s.Events.Publish(events.DoseRecorded, dose)
+
+	if wantsJSON(r) {
+		writeJSON(w, dose)
+		return
+	}
+
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
 func (s *Server) handleDeleteDosage(w http.ResponseWriter, r *http.Request) {
-	if err := s.Database.DeleteLastDose(r.Context(), string(s.Config.HRT.Type)); err != nil {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	var dose db.HRTHistory
+	if at := r.FormValue("at"); at != "" {
+		var t time.Time
+		t, err = time.Parse(time.RFC3339, at)
+		if err != nil {
+			write400Error(w, "failed to parse at", err)
+			return
+		}
+		dose, err = s.Database.DeleteDose(r.Context(), t.UTC())
+	} else {
+		dose, err = s.Database.DeleteLastDose(r.Context(), string(regimen.Type))
+	}
+	if err != nil && !db.IsNotFound(err) {
 		writeError(w, "failed to delete dosage", err)
 		return
 	}
+	if err == nil {
+		s.Events.Publish(events.DoseDeleted, dose)
+	}
+
+	if wantsJSON(r) {
+		if err != nil {
+			http.Error(w, "no dose to delete", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, dose)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleSnooze(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	d := notify.DefaultSnooze
+	if r.FormValue("duration") != "" {
+		d, err = time.ParseDuration(r.FormValue("duration"))
+		if err != nil {
+			write400Error(w, "failed to parse duration", err)
+			return
+		}
+	}
+
+	snooze, err := notify.Snooze(r.Context(), s.Database, regimen.Type, time.Now().Add(d))
+	if err != nil {
+		writeError(w, "failed to snooze", err)
+		return
+	}
+
+	s.Events.Publish(events.Snoozed, snooze)
+
+	if wantsJSON(r) {
+		writeJSON(w, snooze)
+		return
+	}
+
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
 
 func (s *Server) handleGotifyTest(w http.ResponseWriter, r *http.Request) {
-	notification := hrtclicker.Notification{
-		Title:   "Test Notification",
-		Message: "hi cutie! <3",
-		Extras:  s.Config.Gotify.Notification.Extras,
-	}
-
-	if err := notifier.Notify(
-		r.Context(),
-		s.Config.Gotify.Endpoint,
-		s.Config.Gotify.Token,
-		notification,
-	); err != nil {
+	if err := notify.SendTest(r.Context(), s.Config.Load()); err != nil {
 		writeError(w, "failed to send test notification", err)
 		return
 	}
@@ -126,6 +428,28 @@ func (s *Server) handleGotifyTest(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, "notification sent, go check your phone!")
 }
 
+// wantsJSON returns true if the client prefers a JSON response over being
+// redirected back to the index page, which is what API clients such as the
+// hrt-clicker CLI do.
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// redirectBack redirects to the local path in the redirect form value, or to
+// the index page if there is none.
+func redirectBack(w http.ResponseWriter, r *http.Request) {
+	to := r.FormValue("redirect")
+	if !strings.HasPrefix(to, "/") || strings.HasPrefix(to, "//") || strings.HasPrefix(to, "/\\") {
+		to = "/"
+	}
+	http.Redirect(w, r, to, http.StatusSeeOther)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
 func writeError(w http.ResponseWriter, msg string, err error) {
 	http.Error(w, fmt.Sprintf("%s: %v", msg, err), http.StatusInternalServerError)
 }
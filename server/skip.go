@@ -0,0 +1,121 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/schedule"
+)
+
+func (s *Server) getSkips(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	skips, err := s.Database.Skips(r.Context(), string(regimen.Type))
+	if err != nil {
+		writeError(w, "failed to get skips", err)
+		return
+	}
+
+	writeJSON(w, skips)
+}
+
+// handleSkip skips the dose due at due_at, or the next dose if there is none,
+// so that the dose after it is due as if it was taken on time.
+func (s *Server) handleSkip(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	now := time.Now()
+
+	var dueAt time.Time
+	if v := r.FormValue("due_at"); v != "" {
+		dueAt, err = parseTimeIn(v, regimen.Location())
+		if err != nil {
+			write400Error(w, "failed to parse due_at", err)
+			return
+		}
+	} else {
+		next, err := schedule.Next(r.Context(), s.Database, regimen, now)
+		if err != nil {
+			writeError(w, "failed to get next dose", err)
+			return
+		}
+		if next.NextDoseAt.IsZero() {
+			write400Error(w, "failed to skip", missed.ErrNothingDue)
+			return
+		}
+		dueAt = next.NextDoseAt
+	}
+
+	skip, err := missed.Skip(r.Context(), s.Database, regimen, dueAt, now, strings.TrimSpace(r.FormValue("reason")))
+	if err != nil {
+		if errors.Is(err, missed.ErrAlreadySkipped) {
+			write400Error(w, "failed to skip", err)
+			return
+		}
+		writeError(w, "failed to skip", err)
+		return
+	}
+
+	s.Events.Publish(events.DoseSkipped, skip)
+
+	if wantsJSON(r) {
+		writeJSON(w, skip)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeleteSkip(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	skip, err := s.Database.DeleteSkip(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such skip", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete skip", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, skip)
+		return
+	}
+
+	redirectBack(w, r)
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/adherence"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/missed"
+	"libdb.so/hrtclicker/pause"
+)
+
+// defaultStatsDays are the windows in days that adherence is computed over by
+// default.
+var defaultStatsDays = []int{7, 30, 90}
+
+// Stats is the response of the /api/stats endpoint. Durations are in
+// nanoseconds.
+type Stats struct {
+	HRTType hrtclicker.HRTType
+	// Interval is the interval between doses of the regimen in effect now.
+	Interval time.Duration
+	// OnTimeWindow is how far from its due time a dose counts as on time.
+	OnTimeWindow time.Duration
+	// LateWindow is how long after its due time a dose counts as late rather
+	// than missed, or zero if it is until the next dose is due.
+	LateWindow time.Duration
+	// Windows summarizes adherence over each window ending now, in the order
+	// requested.
+	Windows []StatsWindow
+}
+
+// StatsWindow is the adherence summary over the last Days days.
+type StatsWindow struct {
+	Days int
+	adherence.Summary
+}
+
+// computeStats computes the adherence of the regimen over the last number of
+// days for each of the given days.
+func computeStats(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, days []int) (Stats, error) {
+	// Only the doses of the longest window are needed.
+	now := time.Now()
+	since := now
+	for _, d := range days {
+		if t := now.AddDate(0, 0, -d); t.Before(since) {
+			since = t
+		}
+	}
+
+	history, err := database.DosesBetween(ctx, string(regimen.Type), since, now)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to get dosage history: %w", err)
+	}
+
+	doses := make([]time.Time, len(history))
+	for i, dose := range history {
+		doses[i] = dose.DosageAt
+	}
+
+	pauses, err := pause.SpansBetween(ctx, database, regimen.Type, since, now)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	skips, err := missed.Between(ctx, database, regimen.Type, since, now)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		HRTType:      regimen.Type,
+		Interval:     regimen.At(now).Interval.AsDuration(),
+		OnTimeWindow: regimen.OnTimeWithin(),
+		LateWindow:   regimen.LateWindow.AsDuration(),
+		Windows:      make([]StatsWindow, len(days)),
+	}
+
+	for i, d := range days {
+		_, summary := adherence.Analyze(regimen, doses, pauses, skips, now.AddDate(0, 0, -d), now)
+		stats.Windows[i] = StatsWindow{Days: d, Summary: summary}
+	}
+
+	return stats, nil
+}
+
+func (s *Server) getStats(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	days := defaultStatsDays
+	if r.FormValue("days") != "" {
+		days = nil
+		for _, v := range strings.Split(r.FormValue("days"), ",") {
+			d, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil || d <= 0 {
+				write400Error(w, "invalid days", fmt.Errorf("%q is not a positive number", v))
+				return
+			}
+			days = append(days, d)
+		}
+	}
+
+	stats, err := computeStats(r.Context(), s.Database, regimen, days)
+	if err != nil {
+		writeError(w, "failed to compute stats", err)
+		return
+	}
+
+	writeJSON(w, stats)
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/levels"
+	"libdb.so/hrtclicker/predict"
+	"libdb.so/hrtclicker/travel"
+)
+
+// tripPlan is a trip with the doses planned for it from the last dose on.
+type tripPlan struct {
+	travel.Trip
+	// Plan is empty once the schedule has caught up with the destination.
+	Plan []travel.PlannedDose
+}
+
+// tripPlans returns the trips of the regimen, latest departure first, with
+// their plans.
+func tripPlans(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig) ([]tripPlan, error) {
+	rows, err := database.Trips(ctx, string(regimen.Type))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trips: %w", err)
+	}
+
+	lastDose, err := database.LastDose(ctx, string(regimen.Type))
+	if err != nil && !db.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get last dose: %w", err)
+	}
+
+	plans := make([]tripPlan, len(rows))
+	for i, row := range rows {
+		trip, err := travel.New(row, regimen.MaxTravelShift())
+		if err != nil {
+			return nil, err
+		}
+		plans[i] = tripPlan{Trip: trip}
+
+		if lastDose.DosageAt.IsZero() {
+			continue
+		}
+		plans[i].Plan, err = trip.Plan(ctx, database, regimen, lastDose.DosageAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return plans, nil
+}
+
+type travelData struct {
+	regimen hrtclicker.HRTConfig
+	deps    Dependencies
+	ctx     context.Context
+}
+
+func (d travelData) Regimen() hrtclicker.HRTConfig {
+	return d.regimen
+}
+
+func (d travelData) Trips() ([]tripPlan, error) {
+	return tripPlans(d.ctx, d.deps.Database, d.regimen)
+}
+
+// LocalZone returns the name of the time zone of the regimen, which is the
+// default departure zone, or an empty string if it is not known.
+func (d travelData) LocalZone() string {
+	return d.regimen.Zone()
+}
+
+// Preview renders the chart of the predicted levels following the plan of the
+// trip against keeping the time of day of the departure zone. An empty string
+// is returned if the levels of the regimen cannot be predicted.
+func (d travelData) Preview(trip tripPlan) (template.HTML, error) {
+	if _, ok := predict.ForType(d.regimen.Type); !ok || len(trip.Plan) == 0 {
+		return "", nil
+	}
+
+	applications, removals, err := levels.Applications(d.ctx, d.deps.Database, d.regimen, trip.Plan[0].DueAt, db.EndOfTime)
+	if err != nil {
+		return "", err
+	}
+
+	c, err := trip.Preview(d.regimen, applications, removals, trip.Plan)
+	if err != nil {
+		return "", err
+	}
+	return c.SVG(), nil
+}
+
+func (s *Server) handleTravel(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	s.Templates.Execute(w, "travel", travelData{
+		regimen: regimen,
+		deps:    s.Dependencies,
+		ctx:     r.Context(),
+	})
+}
+
+func (s *Server) getTrips(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	trips, err := tripPlans(r.Context(), s.Database, regimen)
+	if err != nil {
+		writeError(w, "failed to get trips", err)
+		return
+	}
+
+	writeJSON(w, trips)
+}
+
+// handleAddTrip adds a trip. departs_at is in the departure zone unless it
+// has an offset.
+func (s *Server) handleAddTrip(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	regimen, err := s.regimen(r)
+	if err != nil {
+		write400Error(w, "invalid type", err)
+		return
+	}
+
+	departure, err := time.LoadLocation(strings.TrimSpace(r.FormValue("departure_zone")))
+	if err != nil {
+		write400Error(w, "invalid departure_zone", err)
+		return
+	}
+	destination, err := time.LoadLocation(strings.TrimSpace(r.FormValue("destination_zone")))
+	if err != nil {
+		write400Error(w, "invalid destination_zone", err)
+		return
+	}
+
+	departsAt, err := parseTimeIn(r.FormValue("departs_at"), departure)
+	if err != nil {
+		write400Error(w, "failed to parse departs_at", err)
+		return
+	}
+
+	if travel.Shift(departure, destination, departsAt) == 0 {
+		write400Error(w, "invalid trip", travel.ErrSameTime)
+		return
+	}
+
+	row, err := s.Database.AddTrip(r.Context(), db.AddTripParams{
+		HRTType:         string(regimen.Type),
+		DepartureZone:   departure.String(),
+		DestinationZone: destination.String(),
+		DepartsAt:       departsAt.UTC().Truncate(time.Minute),
+		Note:            strings.TrimSpace(r.FormValue("note")),
+		AddedAt:         time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		writeError(w, "failed to add trip", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		trip, err := travel.New(row, regimen.MaxTravelShift())
+		if err != nil {
+			writeError(w, "failed to plan trip", err)
+			return
+		}
+		writeJSON(w, trip)
+		return
+	}
+
+	redirectBack(w, r)
+}
+
+func (s *Server) handleDeleteTrip(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		write400Error(w, "failed to parse form", err)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		write400Error(w, "invalid id", err)
+		return
+	}
+
+	trip, err := s.Database.DeleteTrip(r.Context(), id)
+	if err != nil {
+		if db.IsNotFound(err) {
+			http.Error(w, "no such trip", http.StatusNotFound)
+			return
+		}
+		writeError(w, "failed to delete trip", err)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, trip)
+		return
+	}
+
+	redirectBack(w, r)
+}
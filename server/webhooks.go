@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+	"libdb.so/hrtclicker/webhooks"
+)
+
+// webhookDeliveriesShown is the number of delivery logs shown on the webhooks
+// page.
+const webhookDeliveriesShown = 100
+
+type webhooksData struct {
+	deps Dependencies
+	ctx  context.Context
+}
+
+func (d webhooksData) Webhooks() []hrtclicker.WebhookConfig {
+	return d.deps.Config.Load().Webhooks
+}
+
+func (d webhooksData) EventTypes() []events.Type {
+	return events.Types
+}
+
+func (d webhooksData) Deliveries() ([]db.WebhookDelivery, error) {
+	return d.deps.Database.WebhookDeliveries(d.ctx, webhookDeliveriesShown)
+}
+
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.Templates.Execute(w, "webhooks", webhooksData{
+		deps: s.Dependencies,
+		ctx:  r.Context(),
+	})
+}
+
+func (s *Server) getWebhookSchema(w http.ResponseWriter, r *http.Request) {
+	schema, err := webhooks.Schema(events.Type(chi.URLParam(r, "type")))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(schema)
+}
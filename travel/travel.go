@@ -0,0 +1,256 @@
+// Package travel plans the dose schedule of trips across time zones. Keeping
+// the same time of day at the destination right away would move the next due
+// time by the whole time difference, which means either doubling up or going
+// without for half a day. Instead, the due time of each dose after departure
+// moves by at most the configured shift from the one before it, until the
+// doses are due at the same time of day at the destination as they were at
+// home.
+package travel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/chart"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/predict"
+)
+
+// ErrSameTime is returned when the zones of a trip have the same time at
+// departure, so the schedule doesn't need to shift.
+var ErrSameTime = errors.New("departure and destination zones have the same time")
+
+// maxPlanned is the most doses that are planned, in case the last dose was
+// long before departure.
+const maxPlanned = 100
+
+// Trip is a trip across time zones and how it shifts the schedule.
+type Trip struct {
+	db.Trip
+	// Departure and Destination are the time zones of the trip.
+	Departure   *time.Location `json:"-"`
+	Destination *time.Location `json:"-"`
+	// Shift is how far the due times move in total. It is negative when
+	// traveling east, since the same time of day comes earlier there.
+	Shift time.Duration
+	// Steps are how far the due time of each dose after departure moves from
+	// the schedule, in order.
+	Steps []time.Duration
+}
+
+// New returns the trip with the shift of its schedule. The due time of each
+// dose moves by at most maxShift.
+func New(trip db.Trip, maxShift time.Duration) (Trip, error) {
+	departure, err := time.LoadLocation(trip.DepartureZone)
+	if err != nil {
+		return Trip{}, fmt.Errorf("invalid departure zone: %w", err)
+	}
+	destination, err := time.LoadLocation(trip.DestinationZone)
+	if err != nil {
+		return Trip{}, fmt.Errorf("invalid destination zone: %w", err)
+	}
+
+	shift := Shift(departure, destination, trip.DepartsAt)
+	return Trip{
+		Trip:        trip,
+		Departure:   departure,
+		Destination: destination,
+		Shift:       shift,
+		Steps:       Steps(shift, maxShift),
+	}, nil
+}
+
+// Shift returns how far the due times move so that they are at the same time
+// of day at the destination as at departure. The shorter way around the clock
+// is taken, so it is never more than 12 hours either way.
+func Shift(departure, destination *time.Location, at time.Time) time.Duration {
+	_, from := at.In(departure).Zone()
+	_, to := at.In(destination).Zone()
+
+	shift := time.Duration(from-to) * time.Second
+	switch {
+	case shift > 12*time.Hour:
+		shift -= 24 * time.Hour
+	case shift <= -12*time.Hour:
+		shift += 24 * time.Hour
+	}
+	return shift
+}
+
+// Steps splits the shift into the fewest steps of at most maxShift each, as
+// equal as possible to the minute.
+func Steps(shift, maxShift time.Duration) []time.Duration {
+	if shift == 0 || maxShift <= 0 {
+		return nil
+	}
+
+	n := int((shift.Abs() + maxShift - 1) / maxShift)
+	steps := make([]time.Duration, n)
+	left := shift
+	for i := range steps {
+		steps[i] = (left / time.Duration(n-i)).Round(time.Minute)
+		left -= steps[i]
+	}
+	// The last step takes whatever is left from rounding.
+	steps[n-1] += left
+	return steps
+}
+
+// Current returns the trip the dose after the one taken at lastDoseAt is
+// shifted by, and the index of its step. False is returned if the dose is not
+// shifted.
+func Current(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, lastDoseAt time.Time) (Trip, int, bool, error) {
+	row, err := database.TripBefore(ctx, db.TripBeforeParams{
+		HRTType:   string(regimen.Type),
+		DepartsAt: regimen.NextDoseAt(lastDoseAt).UTC(),
+	})
+	if err != nil {
+		if db.IsNotFound(err) {
+			return Trip{}, 0, false, nil
+		}
+		return Trip{}, 0, false, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	trip, err := New(row, regimen.MaxTravelShift())
+	if err != nil {
+		return Trip{}, 0, false, err
+	}
+
+	step, err := trip.taken(ctx, database, regimen, lastDoseAt)
+	if err != nil {
+		return Trip{}, 0, false, err
+	}
+	return trip, step, step < len(trip.Steps), nil
+}
+
+// NextDoseAt returns when the dose after the one taken at lastDoseAt is due,
+// moved by the step of the trip it is shifted by, if any.
+func NextDoseAt(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, lastDoseAt time.Time) (time.Time, error) {
+	due := regimen.NextDoseAt(lastDoseAt)
+
+	trip, step, ok, err := Current(ctx, database, regimen, lastDoseAt)
+	if err != nil || !ok {
+		return due, err
+	}
+	return due.Add(trip.Steps[step]), nil
+}
+
+// taken returns the number of steps of the trip taken before the dose at
+// lastDoseAt, which are the doses before it that were due after departure.
+func (t Trip) taken(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, lastDoseAt time.Time) (int, error) {
+	doses, err := database.DosageHistoryBetween(ctx, db.DosageHistoryBetweenParams{
+		HRTType: t.HRTType,
+		Since:   t.DepartsAt,
+		Before:  lastDoseAt.UTC(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get doses: %w", err)
+	}
+	n := len(doses)
+
+	// The last dose before departure is the first one due after it.
+	before, err := database.DoseBefore(ctx, db.DoseBeforeParams{
+		HRTType:  t.HRTType,
+		DosageAt: t.DepartsAt,
+	})
+	if err != nil && !db.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to get dose before departure: %w", err)
+	}
+	if err == nil && before.DosageAt.Before(lastDoseAt) && regimen.NextDoseAt(before.DosageAt).After(t.DepartsAt) {
+		n++
+	}
+
+	return n, nil
+}
+
+// PlannedDose is a dose of the schedule of a trip.
+type PlannedDose struct {
+	// DueAt is when the dose is due following the trip.
+	DueAt time.Time
+	// HomeDueAt is when the dose would be due keeping the time of day of the
+	// departure zone.
+	HomeDueAt time.Time
+	// Step is how far DueAt moved from the schedule. It is zero for the doses
+	// due before departure.
+	Step time.Duration
+}
+
+// Plan returns the doses after the one taken at lastDoseAt until the schedule
+// has caught up with the destination, assuming every dose is taken when it is
+// due. The doses due before departure are included without moving them. It
+// returns nil once the schedule has caught up.
+func (t Trip) Plan(ctx context.Context, database *db.SQLiteDB, regimen hrtclicker.HRTConfig, lastDoseAt time.Time) ([]PlannedDose, error) {
+	var step int
+	if regimen.NextDoseAt(lastDoseAt).After(t.DepartsAt) {
+		var err error
+		step, err = t.taken(ctx, database, regimen, lastDoseAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var plan []PlannedDose
+	due, home := lastDoseAt, lastDoseAt
+	for step < len(t.Steps) && len(plan) < maxPlanned {
+		next := regimen.NextDoseAt(due)
+		home = regimen.NextDoseAt(home)
+
+		dose := PlannedDose{DueAt: next, HomeDueAt: home}
+		if next.After(t.DepartsAt) {
+			dose.Step = t.Steps[step]
+			dose.DueAt = next.Add(dose.Step)
+			step++
+		}
+
+		plan = append(plan, dose)
+		due = dose.DueAt
+	}
+
+	return plan, nil
+}
+
+// Preview returns the chart of the predicted levels following the plan, with
+// the levels keeping the time of day of the departure zone as the baseline.
+// The applications and removals are the doses that affect the levels at the
+// first planned dose. The chart ends one interval after the last planned dose.
+func (t Trip) Preview(regimen hrtclicker.HRTConfig, applications []time.Time, removals []predict.Removal, plan []PlannedDose) (chart.Levels, error) {
+	if len(plan) == 0 {
+		return chart.Levels{}, errors.New("nothing planned")
+	}
+
+	first, last := plan[0], plan[len(plan)-1]
+	from := first.DueAt
+	if first.HomeDueAt.Before(from) {
+		from = first.HomeDueAt
+	}
+	from = from.Add(-regimen.At(from).Interval.AsDuration())
+	to := regimen.NextDoseAt(last.DueAt)
+
+	planned := slices.Clone(applications)
+	home := slices.Clone(applications)
+	for _, dose := range plan {
+		planned = append(planned, dose.DueAt)
+		home = append(home, dose.HomeDueAt)
+	}
+
+	values, err := predict.Regimen(regimen, planned, removals, from, to)
+	if err != nil {
+		return chart.Levels{}, fmt.Errorf("failed to predict levels: %w", err)
+	}
+	baseline, err := predict.Regimen(regimen, home, removals, from, to)
+	if err != nil {
+		return chart.Levels{}, fmt.Errorf("failed to predict levels: %w", err)
+	}
+
+	return chart.Levels{
+		Values:   values,
+		Baseline: baseline,
+		Doses:    planned,
+		Target:   regimen.Target,
+		Location: t.Destination,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package travel
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/internal/cfgtypes"
+	"libdb.so/hrtclicker/internal/hrttest"
+)
+
+var date = hrttest.Date
+
+// daily is a regimen with a dose every day, shifted by at most 3 hours at a
+// time when traveling.
+var daily = hrtclicker.HRTConfig{
+	Type:        hrtclicker.TypeSublingual,
+	Interval:    cfgtypes.Duration(24 * time.Hour),
+	TimeZone:    "UTC",
+	TravelShift: cfgtypes.Duration(3 * time.Hour),
+}
+
+func TestShift(t *testing.T) {
+	zone := func(hours float64) *time.Location {
+		return time.FixedZone("", int(hours*60*60))
+	}
+
+	tests := []struct {
+		name                   string
+		departure, destination *time.Location
+		want                   time.Duration
+	}{
+		{"same time", zone(1), zone(1), 0},
+		{"east", zone(0), zone(9), -9 * time.Hour},
+		{"west", zone(9), zone(0), 9 * time.Hour},
+		{"half hour", zone(0), zone(5.5), -5*time.Hour - 30*time.Minute},
+		{"shorter way east", zone(-8), zone(9), 7 * time.Hour},
+		{"shorter way west", zone(9), zone(-8), -7 * time.Hour},
+		{"half way around", zone(-12), zone(0), 12 * time.Hour},
+		{"half way around the other way", zone(0), zone(-12), 12 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Shift(test.departure, test.destination, date(1, 0, 0)); got != test.want {
+				t.Errorf("Shift() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		name            string
+		shift, maxShift time.Duration
+		want            []time.Duration
+	}{
+		{"no shift", 0, 2 * time.Hour, nil},
+		{"no steps", 5 * time.Hour, 0, nil},
+		{"single step", time.Hour, 2 * time.Hour, []time.Duration{time.Hour}},
+		{"exact steps", -6 * time.Hour, 2 * time.Hour, []time.Duration{-2 * time.Hour, -2 * time.Hour, -2 * time.Hour}},
+		{"equal steps", 5 * time.Hour, 2 * time.Hour, []time.Duration{100 * time.Minute, 100 * time.Minute, 100 * time.Minute}},
+		{"rounded to the minute", 100 * time.Minute, 30 * time.Minute, []time.Duration{25 * time.Minute, 25 * time.Minute, 25 * time.Minute, 25 * time.Minute}},
+		{"rounded with the rest last", 10 * time.Minute, 3 * time.Minute, []time.Duration{3 * time.Minute, 2 * time.Minute, 3 * time.Minute, 2 * time.Minute}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Steps(test.shift, test.maxShift)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Steps(%s, %s) = %v, want %v", test.shift, test.maxShift, got, test.want)
+			}
+
+			var sum time.Duration
+			for _, step := range got {
+				sum += step
+				if step.Abs() > test.maxShift {
+					t.Errorf("Steps(%s, %s) has step %s over the max", test.shift, test.maxShift, step)
+				}
+			}
+			if got != nil && sum != test.shift {
+				t.Errorf("Steps(%s, %s) sum to %s", test.shift, test.maxShift, sum)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name                   string
+		departure, destination string
+		wantErr                bool
+	}{
+		{"valid zones", "UTC", "UTC", false},
+		{"invalid departure zone", "Nowhere/Special", "UTC", true},
+		{"invalid destination zone", "UTC", "Nowhere/Special", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(db.Trip{
+				DepartureZone:   test.departure,
+				DestinationZone: test.destination,
+				DepartsAt:       date(1, 0, 0),
+			}, 2*time.Hour)
+			if (err != nil) != test.wantErr {
+				t.Errorf("New() = %v, want an error: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextDoseAt(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skip("no time zone database:", err)
+	}
+
+	// Flying to Tokyo at noon on the 2nd moves the doses 9 hours earlier, in
+	// steps of 3 hours.
+	tests := []struct {
+		name  string
+		doses []time.Time
+		want  time.Time
+	}{
+		{
+			name:  "before departure",
+			doses: []time.Time{date(1, 8, 0)},
+			want:  date(2, 8, 0),
+		},
+		{
+			name:  "first dose after departure",
+			doses: []time.Time{date(1, 8, 0), date(2, 8, 0)},
+			want:  date(3, 5, 0),
+		},
+		{
+			name:  "second dose after departure",
+			doses: []time.Time{date(1, 8, 0), date(2, 8, 0), date(3, 5, 0)},
+			want:  date(4, 2, 0),
+		},
+		{
+			name:  "last step",
+			doses: []time.Time{date(1, 8, 0), date(2, 8, 0), date(3, 5, 0), date(4, 2, 0)},
+			want:  date(4, 23, 0),
+		},
+		{
+			name:  "caught up",
+			doses: []time.Time{date(1, 8, 0), date(2, 8, 0), date(3, 5, 0), date(4, 2, 0), date(4, 23, 0)},
+			want:  date(5, 23, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			database := hrttest.OpenDB(t)
+			if _, err := database.AddTrip(ctx, db.AddTripParams{
+				HRTType:         string(daily.Type),
+				DepartureZone:   "UTC",
+				DestinationZone: "Asia/Tokyo",
+				DepartsAt:       date(2, 12, 0),
+				AddedAt:         date(1, 0, 0),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			for _, dosageAt := range test.doses {
+				if err := database.RecordDosage(ctx, db.RecordDosageParams{
+					DosageAt: dosageAt,
+					HRTType:  string(daily.Type),
+					Tags:     db.NewTags(),
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := NextDoseAt(ctx, database, daily, test.doses[len(test.doses)-1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("NextDoseAt() = %s, want %s", got.UTC(), test.want)
+			}
+		})
+	}
+}
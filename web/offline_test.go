@@ -0,0 +1,165 @@
+package web
+
+import (
+	"bufio"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// The web assets must not load anything from an external origin, such as a
+// script from a CDN. The pages must keep working on a server without internet
+// access, and must not leak visits to third parties. Links that the user
+// follows, such as <a href>, are allowed.
+
+// origin matches an absolute or protocol-relative URL at the start of a
+// quoted or unquoted attribute or import. Quotes may be escaped, as in the
+// sources embedded in source maps.
+const origin = `\\?["']?\s*((?:https?:)?//[^"'\s)>\\]+)`
+
+var (
+	htmlLoads = regexp.MustCompile(
+		`(?i)<(?:script|link|img|iframe|source|audio|video|track|embed|object|image|use)\b[^>]*?\s(?:src|href|srcset|data|poster|xlink:href)\s*=\s*` + origin)
+	cssLoads = regexp.MustCompile(
+		`(?i)(?:url\(\s*|@import\s+)` + origin)
+	scriptLoads = regexp.MustCompile(
+		`(?:\bfrom\s*|\bimport\s*\(?\s*|\bfetch\(\s*|\bnew\s+(?:Worker|EventSource|WebSocket)\(\s*|@deno-types=)` + origin)
+)
+
+// offlineCheckers maps file extensions to the patterns of external loads in
+// them. HTML files can contain inline styles and scripts, so they are checked
+// with all patterns.
+var offlineCheckers = map[string][]*regexp.Regexp{
+	".html": {htmlLoads, cssLoads, scriptLoads},
+	".svg":  {htmlLoads, cssLoads},
+	".css":  {cssLoads},
+	".js":   {scriptLoads, cssLoads},
+	".mjs":  {scriptLoads, cssLoads},
+	".ts":   {scriptLoads, cssLoads},
+	".map":  {scriptLoads},
+}
+
+// externalLoads returns the external origins loaded by the file with the
+// given name and content.
+func externalLoads(name, content string) []string {
+	patterns := offlineCheckers[strings.ToLower(path.Ext(name))]
+
+	var found []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	// Minified bundles are a single long line.
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		for _, pattern := range patterns {
+			for _, m := range pattern.FindAllStringSubmatch(scanner.Text(), -1) {
+				found = append(found, m[1])
+			}
+		}
+	}
+	return found
+}
+
+func TestEmbeddedAssetsOffline(t *testing.T) {
+	err := fs.WalkDir(embedFS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, ok := offlineCheckers[strings.ToLower(path.Ext(name))]; !ok {
+			return nil
+		}
+
+		b, err := fs.ReadFile(embedFS, name)
+		if err != nil {
+			return err
+		}
+		for _, url := range externalLoads(name, string(b)) {
+			t.Errorf("%s loads from external origin %s, vendor it instead", name, url)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExternalLoads(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "script src",
+			file:    "page.html",
+			content: `<script src="https://cdn.example.com/a.js"></script>`,
+			want:    []string{"https://cdn.example.com/a.js"},
+		},
+		{
+			name:    "protocol-relative stylesheet",
+			file:    "page.html",
+			content: `<link rel="stylesheet" href="//fonts.example.com/css">`,
+			want:    []string{"//fonts.example.com/css"},
+		},
+		{
+			name:    "link followed by the user",
+			file:    "page.html",
+			content: `<a href="https://example.com">example</a>`,
+		},
+		{
+			name:    "local script",
+			file:    "page.html",
+			content: `<script src="/static/time.js"></script>`,
+		},
+		{
+			name:    "css import",
+			file:    "styles.css",
+			content: `@import "https://fonts.example.com/inter.css";`,
+			want:    []string{"https://fonts.example.com/inter.css"},
+		},
+		{
+			name:    "css url",
+			file:    "styles.css",
+			content: `body { background: url(http://example.com/bg.png); }`,
+			want:    []string{"http://example.com/bg.png"},
+		},
+		{
+			name:    "module import",
+			file:    "plot.ts",
+			content: `import * as charts from "https://cdn.example.com/charts.mjs";`,
+			want:    []string{"https://cdn.example.com/charts.mjs"},
+		},
+		{
+			name:    "fetch",
+			file:    "app.js",
+			content: `fetch("https://api.example.com/x")`,
+			want:    []string{"https://api.example.com/x"},
+		},
+		{
+			name:    "license comment",
+			file:    "app.js",
+			content: `// Licensed under Apache License 2.0 https://www.apache.org/licenses/LICENSE-2.0`,
+		},
+		{
+			name:    "escaped import in a source map",
+			file:    "app.js.map",
+			content: `{"sourcesContent": ["import x from \"https://cdn.example.com/x.ts\";"]}`,
+			want:    []string{"https://cdn.example.com/x.ts"},
+		},
+		{
+			name:    "unchecked extension",
+			file:    "values.json",
+			content: `import x from "https://cdn.example.com/x.ts"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := externalLoads(test.file, test.content)
+			if strings.Join(got, " ") != strings.Join(test.want, " ") {
+				t.Errorf("externalLoads() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
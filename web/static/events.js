@@ -0,0 +1,45 @@
+// Keep the page up to date with changes made from other devices using the
+// Server-Sent Events from /api/events.
+
+const refreshedSections = ["countdown", "recent-doses"];
+
+async function refreshSections() {
+  const resp = await fetch(location.pathname);
+  if (!resp.ok) {
+    console.error("cannot refresh page:", resp.status, resp.statusText);
+    return;
+  }
+
+  const doc = new DOMParser().parseFromString(await resp.text(), "text/html");
+  for (const id of refreshedSections) {
+    const current = document.getElementById(id);
+    const updated = doc.getElementById(id);
+    if (current && updated) {
+      current.replaceWith(updated);
+    }
+  }
+
+  if (typeof updateTimes == "function") {
+    updateTimes();
+  }
+}
+
+const events = new EventSource("/api/events");
+
+const refreshingEvents = [
+  "dose-recorded",
+  "dose-deleted",
+  "dose-updated",
+  "dose-removed",
+  "dose-skipped",
+  "snoozed",
+  "regimen-paused",
+  "regimen-resumed",
+];
+
+for (const type of refreshingEvents) {
+  events.addEventListener(type, () => refreshSections());
+}
+
+// The regimen may have changed, which affects everything on the page.
+events.addEventListener("config-reloaded", () => location.reload());
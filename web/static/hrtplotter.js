@@ -1,4 +1,4 @@
-function H(n){return n.reduce((t,i)=>t+i,0)/n.length}function _n(n,t){let i=t||H(n);return n.reduce((e,h)=>e+Math.pow(h-i,2),0)/n.length}function F(n,t,i){return Math.sqrt(i||_n(n,t))}function jh(n){let t=n.sort((s,e)=>s-e),i=Math.floor(t.length/2);return t.length%2===0?(t[i-1]+t[i])/2:t[i]}function Uh(n){let t=n.reduce((s,e)=>(s.set(e,(s.get(e)||0)+1),s),new Map),i=Math.max(...t.values());return[...t.keys()].find(s=>t.get(s)===i)}function qh(n){return Math.max(...n)-Math.min(...n)}function le(n,t,i,s){let e=n.length;if(e!==t.length)throw new Error("Arrays must be of the same length");let h=i||H(n),r=s||H(t),o=0;for(let l=0;l<e;l++)o+=(n[l]-h)*(t[l]-r);return o/e}function Gh(n,t,i,s){if(n.length!==t.length)throw new Error("The length of x and y should be the same");let e=i||F(n),h=s||F(t);return le(n,t)/(e*h)}function ir(n,t){let i=n.sort((r,o)=>r-o),s=t/100*(i.length-1),e=Math.floor(s),h=Math.ceil(s);return e===h?i[e]:i[e]+(i[h]-i[e])*(s-e)}function j(n,t){let i=n.sort((r,o)=>r-o),s=t*(i.length-1),e=Math.floor(s),h=Math.ceil(s);return e===h?i[e]:i[e]+(i[h]-i[e])*(s-e)}function hr(n,t,i){let s=n.length,e=t||H(n),h=i||F(n,t);return n.reduce((o,l)=>o+(l-e)**3,0)/s/h**3}function ae(n,t,i){let s=t*24,e=n.slice(-s).map(h=>h.v);return{mean:H(e),stdDev:F(e),quantiles:i.reduce((h,r)=>({...h,[r]:j(e,r)}),{})}}var xn={upColor:"#26a69a",downColor:"#ef5350",wickVisible:!0,borderVisible:!0,borderColor:"#378658",borderUpColor:"#26a69a",borderDownColor:"#ef5350",wickColor:"#737375",wickUpColor:"#26a69a",wickDownColor:"#ef5350"},yn={upColor:"#26a69a",downColor:"#ef5350",openVisible:!0,thinBars:!0},En={color:"#2196f3",lineStyle:0,lineWidth:3,lineType:0,lineVisible:!0,crosshairMarkerVisible:!0,crosshairMarkerRadius:4,crosshairMarkerBorderColor:"",crosshairMarkerBorderWidth:2,crosshairMarkerBackgroundColor:"",lastPriceAnimation:0,pointMarkersVisible:!1},Cn={topColor:"rgba( 46, 220, 135, 0.4)",bottomColor:"rgba( 40, 221, 100, 0)",invertFilledArea:!1,lineColor:"#33D778",lineStyle:0,lineWidth:3,lineType:0,lineVisible:!0,crosshairMarkerVisible:!0,crosshairMarkerRadius:4,crosshairMarkerBorderColor:"",crosshairMarkerBorderWidth:2,crosshairMarkerBackgroundColor:"",lastPriceAnimation:0,pointMarkersVisible:!1},kn={baseValue:{type:"price",price:0},topFillColor1:"rgba(38, 166, 154, 0.28)",topFillColor2:"rgba(38, 166, 154, 0.05)",topLineColor:"rgba(38, 166, 154, 1)",bottomFillColor1:"rgba(239, 83, 80, 0.05)",bottomFillColor2:"rgba(239, 83, 80, 0.28)",bottomLineColor:"rgba(239, 83, 80, 1)",lineWidth:3,lineStyle:0,lineType:0,lineVisible:!0,crosshairMarkerVisible:!0,crosshairMarkerRadius:4,crosshairMarkerBorderColor:"",crosshairMarkerBorderWidth:2,crosshairMarkerBackgroundColor:"",lastPriceAnimation:0,pointMarkersVisible:!1},zn={color:"#26a69a",base:0},Ye={color:"#2196f3"},Xe={title:"",visible:!0,lastValueVisible:!0,priceLineVisible:!0,priceLineSource:0,priceLineWidth:1,priceLineColor:"",priceLineStyle:2,baseLineVisible:!0,baseLineWidth:1,baseLineColor:"#B2B5BE",baseLineStyle:0,priceFormat:{type:"price",precision:2,minMove:.01}},gt,Ft;function Q(n,t){let i={0:[],1:[n.lineWidth,n.lineWidth],2:[2*n.lineWidth,2*n.lineWidth],3:[6*n.lineWidth,6*n.lineWidth],4:[n.lineWidth,4*n.lineWidth]}[t];n.setLineDash(i)}function Je(n,t,i,s){n.beginPath();let e=n.lineWidth%2?.5:0;n.moveTo(i,t+e),n.lineTo(s,t+e),n.stroke()}function I(n,t){if(!n)throw new Error("Assertion failed"+(t?": "+t:""))}function z(n){if(n===void 0)throw new Error("Value is undefined");return n}function v(n){if(n===null)throw new Error("Value is null");return n}function st(n){return v(z(n))}(function(n){n[n.Simple=0]="Simple",n[n.WithSteps=1]="WithSteps",n[n.Curved=2]="Curved"})(gt||(gt={})),function(n){n[n.Solid=0]="Solid",n[n.Dotted=1]="Dotted",n[n.Dashed=2]="Dashed",n[n.LargeDashed=3]="LargeDashed",n[n.SparseDotted=4]="SparseDotted"}(Ft||(Ft={}));var ue={khaki:"#f0e68c",azure:"#f0ffff",aliceblue:"#f0f8ff",ghostwhite:"#f8f8ff",gold:"#ffd700",goldenrod:"#daa520",gainsboro:"#dcdcdc",gray:"#808080",green:"#008000",honeydew:"#f0fff0",floralwhite:"#fffaf0",lightblue:"#add8e6",lightcoral:"#f08080",lemonchiffon:"#fffacd",hotpink:"#ff69b4",lightyellow:"#ffffe0",greenyellow:"#adff2f",lightgoldenrodyellow:"#fafad2",limegreen:"#32cd32",linen:"#faf0e6",lightcyan:"#e0ffff",magenta:"#f0f",maroon:"#800000",olive:"#808000",orange:"#ffa500",oldlace:"#fdf5e6",mediumblue:"#0000cd",transparent:"#0000",lime:"#0f0",lightpink:"#ffb6c1",mistyrose:"#ffe4e1",moccasin:"#ffe4b5",midnightblue:"#191970",orchid:"#da70d6",mediumorchid:"#ba55d3",mediumturquoise:"#48d1cc",orangered:"#ff4500",royalblue:"#4169e1",powderblue:"#b0e0e6",red:"#f00",coral:"#ff7f50",turquoise:"#40e0d0",white:"#fff",whitesmoke:"#f5f5f5",wheat:"#f5deb3",teal:"#008080",steelblue:"#4682b4",bisque:"#ffe4c4",aquamarine:"#7fffd4",aqua:"#0ff",sienna:"#a0522d",silver:"#c0c0c0",springgreen:"#00ff7f",antiquewhite:"#faebd7",burlywood:"#deb887",brown:"#a52a2a",beige:"#f5f5dc",chocolate:"#d2691e",chartreuse:"#7fff00",cornflowerblue:"#6495ed",cornsilk:"#fff8dc",crimson:"#dc143c",cadetblue:"#5f9ea0",tomato:"#ff6347",fuchsia:"#f0f",blue:"#00f",salmon:"#fa8072",blanchedalmond:"#ffebcd",slateblue:"#6a5acd",slategray:"#708090",thistle:"#d8bfd8",tan:"#d2b48c",cyan:"#0ff",darkblue:"#00008b",darkcyan:"#008b8b",darkgoldenrod:"#b8860b",darkgray:"#a9a9a9",blueviolet:"#8a2be2",black:"#000",darkmagenta:"#8b008b",darkslateblue:"#483d8b",darkkhaki:"#bdb76b",darkorchid:"#9932cc",darkorange:"#ff8c00",darkgreen:"#006400",darkred:"#8b0000",dodgerblue:"#1e90ff",darkslategray:"#2f4f4f",dimgray:"#696969",deepskyblue:"#00bfff",firebrick:"#b22222",forestgreen:"#228b22",indigo:"#4b0082",ivory:"#fffff0",lavenderblush:"#fff0f5",feldspar:"#d19275",indianred:"#cd5c5c",lightgreen:"#90ee90",lightgrey:"#d3d3d3",lightskyblue:"#87cefa",lightslategray:"#789",lightslateblue:"#8470ff",snow:"#fffafa",lightseagreen:"#20b2aa",lightsalmon:"#ffa07a",darksalmon:"#e9967a",darkviolet:"#9400d3",mediumpurple:"#9370d8",mediumaquamarine:"#66cdaa",skyblue:"#87ceeb",lavender:"#e6e6fa",lightsteelblue:"#b0c4de",mediumvioletred:"#c71585",mintcream:"#f5fffa",navajowhite:"#ffdead",navy:"#000080",olivedrab:"#6b8e23",palevioletred:"#d87093",violetred:"#d02090",yellow:"#ff0",yellowgreen:"#9acd32",lawngreen:"#7cfc00",pink:"#ffc0cb",paleturquoise:"#afeeee",palegoldenrod:"#eee8aa",darkolivegreen:"#556b2f",darkseagreen:"#8fbc8f",darkturquoise:"#00ced1",peachpuff:"#ffdab9",deeppink:"#ff1493",violet:"#ee82ee",palegreen:"#98fb98",mediumseagreen:"#3cb371",peru:"#cd853f",saddlebrown:"#8b4513",sandybrown:"#f4a460",rosybrown:"#bc8f8f",purple:"#800080",seagreen:"#2e8b57",seashell:"#fff5ee",papayawhip:"#ffefd5",mediumslateblue:"#7b68ee",plum:"#dda0dd",mediumspringgreen:"#00fa9a"};function L(n){return n<0?0:n>255?255:Math.round(n)||0}function Ke(n){return n<=0||n>0?n<0?0:n>1?1:Math.round(1e4*n)/1e4:0}var Tn=/^#([0-9a-f])([0-9a-f])([0-9a-f])([0-9a-f])?$/i,On=/^#([0-9a-f]{2})([0-9a-f]{2})([0-9a-f]{2})([0-9a-f]{2})?$/i,Ln=/^rgb\(\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*\)$/,Rn=/^rgba\(\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*,\s*(-?[\d]{0,10}(?:\.\d+)?)\s*\)$/;function jt(n){(n=n.toLowerCase())in ue&&(n=ue[n]);{let t=Rn.exec(n)||Ln.exec(n);if(t)return[L(parseInt(t[1],10)),L(parseInt(t[2],10)),L(parseInt(t[3],10)),Ke(t.length<5?1:parseFloat(t[4]))]}{let t=On.exec(n);if(t)return[L(parseInt(t[1],16)),L(parseInt(t[2],16)),L(parseInt(t[3],16)),1]}{let t=Tn.exec(n);if(t)return[L(17*parseInt(t[1],16)),L(17*parseInt(t[2],16)),L(17*parseInt(t[3],16)),1]}throw new Error(`Cannot parse color: ${n}`)}function oi(n){let t=jt(n);return{t:`rgb(${t[0]}, ${t[1]}, ${t[2]})`,i:(i=t,.199*i[0]+.687*i[1]+.114*i[2]>160?"black":"white")};var i}var x=class{constructor(){this.h=[]}l(t,i,s){let e={o:t,_:i,u:s===!0};this.h.push(e)}v(t){let i=this.h.findIndex(s=>t===s.o);i>-1&&this.h.splice(i,1)}p(t){this.h=this.h.filter(i=>i._!==t)}m(t,i,s){let e=[...this.h];this.h=this.h.filter(h=>!h.u),e.forEach(h=>h.o(t,i,s))}M(){return this.h.length>0}S(){this.h=[]}};function N(n,...t){for(let i of t)for(let s in i)i[s]!==void 0&&(typeof i[s]!="object"||n[s]===void 0||Array.isArray(i[s])?n[s]=i[s]:N(n[s],i[s]));return n}function P(n){return typeof n=="number"&&isFinite(n)}function wt(n){return typeof n=="number"&&n%1==0}function Tt(n){return typeof n=="string"}function Rt(n){return typeof n=="boolean"}function W(n){let t=n;if(!t||typeof t!="object")return t;let i,s,e;for(s in i=Array.isArray(t)?[]:{},t)t.hasOwnProperty(s)&&(e=t[s],i[s]=e&&typeof e=="object"?W(e):e);return i}function Nn(n){return n!==null}function _t(n){return n===null?void 0:n}var Gs="-apple-system, BlinkMacSystemFont, 'Trebuchet MS', Roboto, Ubuntu, sans-serif";function ht(n,t,i){return t===void 0&&(t=Gs),`${i=i!==void 0?`${i} `:""}${n}px ${t}`}var Bi=class{constructor(t){this.k={C:1,T:5,P:NaN,R:"",D:"",O:"",A:"",B:0,V:0,I:0,L:0,N:0},this.F=t}W(){let t=this.k,i=this.j(),s=this.H();return t.P===i&&t.D===s||(t.P=i,t.D=s,t.R=ht(i,s),t.L=2.5/12*i,t.B=t.L,t.V=i/12*t.T,t.I=i/12*t.T,t.N=0),t.O=this.$(),t.A=this.U(),this.k}$(){return this.F.W().layout.textColor}U(){return this.F.q()}j(){return this.F.W().layout.fontSize}H(){return this.F.W().layout.fontFamily}},St=class{constructor(){this.Y=[]}X(t){this.Y=t}K(t,i,s){this.Y.forEach(e=>{e.K(t,i,s)})}},T=class{K(t,i,s){t.useBitmapCoordinateSpace(e=>this.Z(e,i,s))}},Pi=class extends T{constructor(){super(...arguments),this.G=null}J(t){this.G=t}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){if(this.G===null||this.G.tt===null)return;let e=this.G.tt,h=this.G,r=Math.max(1,Math.floor(i))%2/2,o=l=>{t.beginPath();for(let a=e.to-1;a>=e.from;--a){let u=h.it[a],c=Math.round(u.nt*i)+r,d=u.st*s,f=l*s+r;t.moveTo(c,d),t.arc(c,d,f,0,2*Math.PI)}t.fill()};h.et>0&&(t.fillStyle=h.rt,o(h.ht+h.et)),t.fillStyle=h.lt,o(h.ht)}};function Bn(){return{it:[{nt:0,st:0,ot:0,_t:0}],lt:"",rt:"",ht:0,et:0,tt:null}}var Pn={from:0,to:1},Di=class{constructor(t,i){this.ut=new St,this.ct=[],this.dt=[],this.ft=!0,this.F=t,this.vt=i,this.ut.X(this.ct)}bt(t){let i=this.F.wt();i.length!==this.ct.length&&(this.dt=i.map(Bn),this.ct=this.dt.map(s=>{let e=new Pi;return e.J(s),e}),this.ut.X(this.ct)),this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.ut}Mt(){let t=this.vt.W().mode===2,i=this.F.wt(),s=this.vt.xt(),e=this.F.St();i.forEach((h,r)=>{var o;let l=this.dt[r],a=h.yt(s);if(t||a===null||!h.kt())return void(l.tt=null);let u=v(h.Ct());l.lt=a.Tt,l.ht=a.ht,l.et=a.Pt,l.it[0]._t=a._t,l.it[0].st=h.Dt().Rt(a._t,u.Ot),l.rt=(o=a.At)!==null&&o!==void 0?o:this.F.Bt(l.it[0].st/h.Dt().Vt()),l.it[0].ot=s,l.it[0].nt=e.zt(s),l.tt=Pn})}},Wi=class extends T{constructor(t){super(),this.Et=t}Z({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(this.Et===null)return;let h=this.Et.It.kt,r=this.Et.Lt.kt;if(!h&&!r)return;let o=Math.round(this.Et.nt*s),l=Math.round(this.Et.st*e);t.lineCap="butt",h&&o>=0&&(t.lineWidth=Math.floor(this.Et.It.et*s),t.strokeStyle=this.Et.It.O,t.fillStyle=this.Et.It.O,Q(t,this.Et.It.Nt),function(a,u,c,d){a.beginPath();let f=a.lineWidth%2?.5:0;a.moveTo(u+f,c),a.lineTo(u+f,d),a.stroke()}(t,o,0,i.height)),r&&l>=0&&(t.lineWidth=Math.floor(this.Et.Lt.et*e),t.strokeStyle=this.Et.Lt.O,t.fillStyle=this.Et.Lt.O,Q(t,this.Et.Lt.Nt),Je(t,l,0,i.width))}},Vi=class{constructor(t){this.ft=!0,this.Ft={It:{et:1,Nt:0,O:"",kt:!1},Lt:{et:1,Nt:0,O:"",kt:!1},nt:0,st:0},this.Wt=new Wi(this.Ft),this.jt=t}bt(){this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.Wt}Mt(){let t=this.jt.kt(),i=v(this.jt.Ht()),s=i.$t().W().crosshair,e=this.Ft;if(s.mode===2)return e.Lt.kt=!1,void(e.It.kt=!1);e.Lt.kt=t&&this.jt.Ut(i),e.It.kt=t&&this.jt.qt(),e.Lt.et=s.horzLine.width,e.Lt.Nt=s.horzLine.style,e.Lt.O=s.horzLine.color,e.It.et=s.vertLine.width,e.It.Nt=s.vertLine.style,e.It.O=s.vertLine.color,e.nt=this.jt.Yt(),e.st=this.jt.Xt()}};function Dn(n,t,i,s,e,h){n.fillRect(t+h,i,s-2*h,h),n.fillRect(t+h,i+e-h,s-2*h,h),n.fillRect(t,i,h,e),n.fillRect(t+s-h,i,h,e)}function li(n,t,i,s,e,h){n.save(),n.globalCompositeOperation="copy",n.fillStyle=h,n.fillRect(t,i,s,e),n.restore()}function ce(n,t){return n.map(i=>i===0?i:i+t)}function bi(n,t,i,s,e,h){n.beginPath(),n.lineTo(t+s-h[1],i),h[1]!==0&&n.arcTo(t+s,i,t+s,i+h[1],h[1]),n.lineTo(t+s,i+e-h[2]),h[2]!==0&&n.arcTo(t+s,i+e,t+s-h[2],i+e,h[2]),n.lineTo(t+h[3],i+e),h[3]!==0&&n.arcTo(t,i+e,t,i+e-h[3],h[3]),n.lineTo(t,i+h[0]),h[0]!==0&&n.arcTo(t,i,t+h[0],i,h[0])}function de(n,t,i,s,e,h,r=0,o=[0,0,0,0],l=""){if(n.save(),!r||!l||l===h)return bi(n,t,i,s,e,o),n.fillStyle=h,n.fill(),void n.restore();let a=r/2;h!=="transparent"&&(bi(n,t+r,i+r,s-2*r,e-2*r,ce(o,-r)),n.fillStyle=h,n.fill()),l!=="transparent"&&(bi(n,t+a,i+a,s-r,e-r,ce(o,-a)),n.lineWidth=r,n.strokeStyle=l,n.closePath(),n.stroke()),n.restore()}function Ge(n,t,i,s,e,h,r){n.save(),n.globalCompositeOperation="copy";let o=n.createLinearGradient(0,0,0,e);o.addColorStop(0,h),o.addColorStop(1,r),n.fillStyle=o,n.fillRect(t,i,s,e),n.restore()}var Ut=class{constructor(t,i){this.J(t,i)}J(t,i){this.Et=t,this.Kt=i}Vt(t,i){return this.Et.kt?t.P+t.L+t.B:0}K(t,i,s,e){if(!this.Et.kt||this.Et.Zt.length===0)return;let h=this.Et.O,r=this.Kt.t,o=t.useBitmapCoordinateSpace(l=>{let a=l.context;a.font=i.R;let u=this.Gt(l,i,s,e),c=u.Jt,d=(f,m)=>{u.Qt?de(a,c.ti,c.ii,c.ni,c.si,f,c.ei,[c.ht,0,0,c.ht],m):de(a,c.ri,c.ii,c.ni,c.si,f,c.ei,[0,c.ht,c.ht,0],m)};return d(r,"transparent"),this.Et.hi&&(a.fillStyle=h,a.fillRect(c.ri,c.li,c.ai-c.ri,c.oi)),d("transparent",r),this.Et._i&&(a.fillStyle=i.A,a.fillRect(u.Qt?c.ui-c.ei:0,c.ii,c.ei,c.ci-c.ii)),u});t.useMediaCoordinateSpace(({context:l})=>{let a=o.di;l.font=i.R,l.textAlign=o.Qt?"right":"left",l.textBaseline="middle",l.fillStyle=h,l.fillText(this.Et.Zt,a.fi,(a.ii+a.ci)/2+a.vi)})}Gt(t,i,s,e){var h;let{context:r,bitmapSize:o,mediaSize:l,horizontalPixelRatio:a,verticalPixelRatio:u}=t,c=this.Et.hi||!this.Et.pi?i.T:0,d=this.Et.mi?i.C:0,f=i.L+this.Kt.bi,m=i.B+this.Kt.wi,p=i.V,b=i.I,g=this.Et.Zt,w=i.P,M=s.gi(r,g),_=Math.ceil(s.Mi(r,g)),y=w+f+m,O=i.C+p+b+_+c,k=Math.max(1,Math.floor(u)),E=Math.round(y*u);E%2!=k%2&&(E+=1);let A=d>0?Math.max(1,Math.floor(d*a)):0,tt=Math.round(O*a),ne=Math.round(c*a),wn=(h=this.Kt.xi)!==null&&h!==void 0?h:this.Kt.Si,he=Math.round(wn*u)-Math.floor(.5*u),fi=Math.floor(he+k/2-E/2),re=fi+E,Lt=e==="right",oe=Lt?l.width-d:d,ut=Lt?o.width-A:A,mi,pi,vi;return Lt?(mi=ut-tt,pi=ut-ne,vi=oe-c-p-d):(mi=ut+tt,pi=ut+ne,vi=oe+c+p),{Qt:Lt,Jt:{ii:fi,li:he,ci:re,ni:tt,si:E,ht:2*a,ei:A,ti:mi,ri:ut,ai:pi,oi:k,ui:o.width},di:{ii:fi/u,ci:re/u,fi:vi,vi:M}}}},rt=class{constructor(t){this.yi={Si:0,t:"#000",wi:0,bi:0},this.ki={Zt:"",kt:!1,hi:!0,pi:!1,At:"",O:"#FFF",_i:!1,mi:!1},this.Ci={Zt:"",kt:!1,hi:!1,pi:!0,At:"",O:"#FFF",_i:!0,mi:!0},this.ft=!0,this.Ti=new(t||Ut)(this.ki,this.yi),this.Pi=new(t||Ut)(this.Ci,this.yi)}Zt(){return this.Ri(),this.ki.Zt}Si(){return this.Ri(),this.yi.Si}bt(){this.ft=!0}Vt(t,i=!1){return Math.max(this.Ti.Vt(t,i),this.Pi.Vt(t,i))}Di(){return this.yi.xi||0}Oi(t){this.yi.xi=t}Ai(){return this.Ri(),this.ki.kt||this.Ci.kt}Bi(){return this.Ri(),this.ki.kt}gt(t){return this.Ri(),this.ki.hi=this.ki.hi&&t.W().ticksVisible,this.Ci.hi=this.Ci.hi&&t.W().ticksVisible,this.Ti.J(this.ki,this.yi),this.Pi.J(this.Ci,this.yi),this.Ti}Vi(){return this.Ri(),this.Ti.J(this.ki,this.yi),this.Pi.J(this.Ci,this.yi),this.Pi}Ri(){this.ft&&(this.ki.hi=!0,this.Ci.hi=!1,this.zi(this.ki,this.Ci,this.yi))}},Ai=class extends rt{constructor(t,i,s){super(),this.jt=t,this.Ei=i,this.Ii=s}zi(t,i,s){if(t.kt=!1,this.jt.W().mode===2)return;let e=this.jt.W().horzLine;if(!e.labelVisible)return;let h=this.Ei.Ct();if(!this.jt.kt()||this.Ei.Li()||h===null)return;let r=oi(e.labelBackgroundColor);s.t=r.t,t.O=r.i;let o=2/12*this.Ei.P();s.bi=o,s.wi=o;let l=this.Ii(this.Ei);s.Si=l.Si,t.Zt=this.Ei.Ni(l._t,h),t.kt=!0}},Wn=/[1-9]/g,qt=class{constructor(){this.Et=null}J(t){this.Et=t}K(t,i){if(this.Et===null||this.Et.kt===!1||this.Et.Zt.length===0)return;let s=t.useMediaCoordinateSpace(({context:d})=>(d.font=i.R,Math.round(i.Fi.Mi(d,v(this.Et).Zt,Wn))));if(s<=0)return;let e=i.Wi,h=s+2*e,r=h/2,o=this.Et.ji,l=this.Et.Si,a=Math.floor(l-r)+.5;a<0?(l+=Math.abs(0-a),a=Math.floor(l-r)+.5):a+h>o&&(l-=Math.abs(o-(a+h)),a=Math.floor(l-r)+.5);let u=a+h,c=Math.ceil(0+i.C+i.T+i.L+i.P+i.B);t.useBitmapCoordinateSpace(({context:d,horizontalPixelRatio:f,verticalPixelRatio:m})=>{let p=v(this.Et);d.fillStyle=p.t;let b=Math.round(a*f),g=Math.round(0*m),w=Math.round(u*f),M=Math.round(c*m),_=Math.round(2*f);if(d.beginPath(),d.moveTo(b,g),d.lineTo(b,M-_),d.arcTo(b,M,b+_,M,_),d.lineTo(w-_,M),d.arcTo(w,M,w,M-_,_),d.lineTo(w,g),d.fill(),p.hi){let y=Math.round(p.Si*f),O=g,k=Math.round((O+i.T)*m);d.fillStyle=p.O;let E=Math.max(1,Math.floor(f)),A=Math.floor(.5*f);d.fillRect(y-A,O,E,k-O)}}),t.useMediaCoordinateSpace(({context:d})=>{let f=v(this.Et),m=0+i.C+i.T+i.L+i.P/2;d.font=i.R,d.textAlign="left",d.textBaseline="middle",d.fillStyle=f.O;let p=i.Fi.gi(d,"Apr0");d.translate(a+e,m+p),d.fillText(f.Zt,0,0)})}},Hi=class{constructor(t,i,s){this.ft=!0,this.Wt=new qt,this.Ft={kt:!1,t:"#4c525e",O:"white",Zt:"",ji:0,Si:NaN,hi:!0},this.vt=t,this.Hi=i,this.Ii=s}bt(){this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.Wt.J(this.Ft),this.Wt}Mt(){let t=this.Ft;if(t.kt=!1,this.vt.W().mode===2)return;let i=this.vt.W().vertLine;if(!i.labelVisible)return;let s=this.Hi.St();if(s.Li())return;t.ji=s.ji();let e=this.Ii();if(e===null)return;t.Si=e.Si;let h=s.$i(this.vt.xt());t.Zt=s.Ui(v(h)),t.kt=!0;let r=oi(i.labelBackgroundColor);t.t=r.t,t.O=r.i,t.hi=s.W().ticksVisible}},Mt=class{constructor(){this.qi=null,this.Yi=0}Xi(){return this.Yi}Ki(t){this.Yi=t}Dt(){return this.qi}Zi(t){this.qi=t}Gi(t){return[]}Ji(){return[]}kt(){return!0}},fe;(function(n){n[n.Normal=0]="Normal",n[n.Magnet=1]="Magnet",n[n.Hidden=2]="Hidden"})(fe||(fe={}));var $i=class extends Mt{constructor(t,i){super(),this.Qi=null,this.tn=NaN,this.nn=0,this.sn=!0,this.en=new Map,this.rn=!1,this.hn=NaN,this.ln=NaN,this.an=NaN,this.on=NaN,this.Hi=t,this._n=i,this.un=new Di(t,this),this.cn=((e,h)=>r=>{let o=h(),l=e();if(r===v(this.Qi).dn())return{_t:l,Si:o};{let a=v(r.Ct());return{_t:r.fn(o,a),Si:o}}})(()=>this.tn,()=>this.ln);let s=((e,h)=>()=>{let r=this.Hi.St().vn(e()),o=h();return r&&Number.isFinite(o)?{ot:r,Si:o}:null})(()=>this.nn,()=>this.Yt());this.pn=new Hi(this,t,s),this.mn=new Vi(this)}W(){return this._n}bn(t,i){this.an=t,this.on=i}wn(){this.an=NaN,this.on=NaN}gn(){return this.an}Mn(){return this.on}xn(t,i,s){this.rn||(this.rn=!0),this.sn=!0,this.Sn(t,i,s)}xt(){return this.nn}Yt(){return this.hn}Xt(){return this.ln}kt(){return this.sn}yn(){this.sn=!1,this.kn(),this.tn=NaN,this.hn=NaN,this.ln=NaN,this.Qi=null,this.wn()}Cn(t){return this.Qi!==null?[this.mn,this.un]:[]}Ut(t){return t===this.Qi&&this._n.horzLine.visible}qt(){return this._n.vertLine.visible}Tn(t,i){this.sn&&this.Qi===t||this.en.clear();let s=[];return this.Qi===t&&s.push(this.Pn(this.en,i,this.cn)),s}Ji(){return this.sn?[this.pn]:[]}Ht(){return this.Qi}Rn(){this.mn.bt(),this.en.forEach(t=>t.bt()),this.pn.bt(),this.un.bt()}Dn(t){return t&&!t.dn().Li()?t.dn():null}Sn(t,i,s){this.On(t,i,s)&&this.Rn()}On(t,i,s){let e=this.hn,h=this.ln,r=this.tn,o=this.nn,l=this.Qi,a=this.Dn(s);this.nn=t,this.hn=isNaN(t)?NaN:this.Hi.St().zt(t),this.Qi=s;let u=a!==null?a.Ct():null;return a!==null&&u!==null?(this.tn=i,this.ln=a.Rt(i,u)):(this.tn=NaN,this.ln=NaN),e!==this.hn||h!==this.ln||o!==this.nn||r!==this.tn||l!==this.Qi}kn(){let t=this.Hi.wt().map(s=>s.Bn().An()).filter(Nn),i=t.length===0?null:Math.max(...t);this.nn=i!==null?i:NaN}Pn(t,i,s){let e=t.get(i);return e===void 0&&(e=new Ai(this,i,s),t.set(i,e)),e}};function ai(n){return n==="left"||n==="right"}var C=class n{constructor(t){this.Vn=new Map,this.zn=[],this.En=t}In(t,i){let s=function(e,h){return e===void 0?h:{Ln:Math.max(e.Ln,h.Ln),Nn:e.Nn||h.Nn}}(this.Vn.get(t),i);this.Vn.set(t,s)}Fn(){return this.En}Wn(t){let i=this.Vn.get(t);return i===void 0?{Ln:this.En}:{Ln:Math.max(this.En,i.Ln),Nn:i.Nn}}jn(){this.Hn(),this.zn=[{$n:0}]}Un(t){this.Hn(),this.zn=[{$n:1,Ot:t}]}qn(t){this.Yn(),this.zn.push({$n:5,Ot:t})}Hn(){this.Yn(),this.zn.push({$n:6})}Xn(){this.Hn(),this.zn=[{$n:4}]}Kn(t){this.Hn(),this.zn.push({$n:2,Ot:t})}Zn(t){this.Hn(),this.zn.push({$n:3,Ot:t})}Gn(){return this.zn}Jn(t){for(let i of t.zn)this.Qn(i);this.En=Math.max(this.En,t.En),t.Vn.forEach((i,s)=>{this.In(s,i)})}static ts(){return new n(2)}static ns(){return new n(3)}Qn(t){switch(t.$n){case 0:this.jn();break;case 1:this.Un(t.Ot);break;case 2:this.Kn(t.Ot);break;case 3:this.Zn(t.Ot);break;case 4:this.Xn();break;case 5:this.qn(t.Ot);break;case 6:this.Yn()}}Yn(){let t=this.zn.findIndex(i=>i.$n===5);t!==-1&&this.zn.splice(t,1)}},me=".";function V(n,t){if(!P(n))return"n/a";if(!wt(t))throw new TypeError("invalid length");if(t<0||t>16)throw new TypeError("invalid length");return t===0?n.toString():("0000000000000000"+n.toString()).slice(-t)}var ot=class{constructor(t,i){if(i||(i=1),P(t)&&wt(t)||(t=100),t<0)throw new TypeError("invalid base");this.Ei=t,this.ss=i,this.es()}format(t){let i=t<0?"\u2212":"";return t=Math.abs(t),i+this.rs(t)}es(){if(this.hs=0,this.Ei>0&&this.ss>0){let t=this.Ei;for(;t>1;)t/=10,this.hs++}}rs(t){let i=this.Ei/this.ss,s=Math.floor(t),e="",h=this.hs!==void 0?this.hs:NaN;if(i>1){let r=+(Math.round(t*i)-s*i).toFixed(this.hs);r>=i&&(r-=i,s+=1),e=me+V(+r.toFixed(this.hs)*this.ss,h)}else s=Math.round(s*i)/i,h>0&&(e=me+V(0,h));return s.toFixed(0)+e}},Zt=class extends ot{constructor(t=100){super(t)}format(t){return`${super.format(t)}%`}},Ii=class{constructor(t){this.ls=t}format(t){let i="";return t<0&&(i="-",t=-t),t<995?i+this.os(t):t<999995?i+this.os(t/1e3)+"K":t<999999995?(t=1e3*Math.round(t/1e3),i+this.os(t/1e6)+"M"):(t=1e6*Math.round(t/1e6),i+this.os(t/1e9)+"B")}os(t){let i,s=Math.pow(10,this.ls);return i=(t=Math.round(t*s)/s)>=1e-15&&t<1?t.toFixed(this.ls).replace(/\.?0+$/,""):String(t),i.replace(/(\.[1-9]*)0+$/,(e,h)=>h)}};function tn(n,t,i,s,e,h,r){if(t.length===0||s.from>=t.length||s.to<=0)return;let{context:o,horizontalPixelRatio:l,verticalPixelRatio:a}=n,u=t[s.from],c=h(n,u),d=u;if(s.to-s.from<2){let f=e/2;o.beginPath();let m={nt:u.nt-f,st:u.st},p={nt:u.nt+f,st:u.st};o.moveTo(m.nt*l,m.st*a),o.lineTo(p.nt*l,p.st*a),r(n,c,m,p)}else{let f=(p,b)=>{r(n,c,d,b),o.beginPath(),c=p,d=b},m=d;o.beginPath(),o.moveTo(u.nt*l,u.st*a);for(let p=s.from+1;p<s.to;++p){m=t[p];let b=h(n,m);switch(i){case 0:o.lineTo(m.nt*l,m.st*a);break;case 1:o.lineTo(m.nt*l,t[p-1].st*a),b!==c&&(f(b,m),o.lineTo(m.nt*l,t[p-1].st*a)),o.lineTo(m.nt*l,m.st*a);break;case 2:{let[g,w]=Vn(t,p-1,p);o.bezierCurveTo(g.nt*l,g.st*a,w.nt*l,w.st*a,m.nt*l,m.st*a);break}}i!==1&&b!==c&&(f(b,m),o.moveTo(m.nt*l,m.st*a))}(d!==m||d===m&&i===1)&&r(n,c,d,m)}}var pe=6;function gi(n,t){return{nt:n.nt-t.nt,st:n.st-t.st}}function ve(n,t){return{nt:n.nt/t,st:n.st/t}}function Vn(n,t,i){let s=Math.max(0,t-1),e=Math.min(n.length-1,i+1);var h,r;return[(h=n[t],r=ve(gi(n[i],n[s]),pe),{nt:h.nt+r.nt,st:h.st+r.st}),gi(n[i],ve(gi(n[e],n[t]),pe))]}function An(n,t,i,s,e){let{context:h,horizontalPixelRatio:r,verticalPixelRatio:o}=t;h.lineTo(e.nt*r,n*o),h.lineTo(s.nt*r,n*o),h.closePath(),h.fillStyle=i,h.fill()}var Qt=class extends T{constructor(){super(...arguments),this.G=null}J(t){this.G=t}Z(t){var i;if(this.G===null)return;let{it:s,tt:e,_s:h,et:r,Nt:o,us:l}=this.G,a=(i=this.G.cs)!==null&&i!==void 0?i:this.G.ds?0:t.mediaSize.height;if(e===null)return;let u=t.context;u.lineCap="butt",u.lineJoin="round",u.lineWidth=r,Q(u,o),u.lineWidth=1,tn(t,s,l,e,h,this.fs.bind(this),An.bind(null,a))}};function Fi(n,t,i){return Math.min(Math.max(n,t),i)}function Nt(n,t,i){return t-n<=i}function sn(n){let t=Math.ceil(n);return t%2==0?t-1:t}var xt=class{vs(t,i){let s=this.ps,{bs:e,ws:h,gs:r,Ms:o,xs:l,cs:a}=i;if(this.Ss===void 0||s===void 0||s.bs!==e||s.ws!==h||s.gs!==r||s.Ms!==o||s.cs!==a||s.xs!==l){let u=t.context.createLinearGradient(0,0,0,l);if(u.addColorStop(0,e),a!=null){let c=Fi(a*t.verticalPixelRatio/l,0,1);u.addColorStop(c,h),u.addColorStop(c,r)}u.addColorStop(1,o),this.Ss=u,this.ps=i}return this.Ss}},ji=class extends Qt{constructor(){super(...arguments),this.ys=new xt}fs(t,i){return this.ys.vs(t,{bs:i.ks,ws:"",gs:"",Ms:i.Cs,xs:t.bitmapSize.height})}};function Hn(n,t){let i=n.context;i.strokeStyle=t,i.stroke()}var Yt=class extends T{constructor(){super(...arguments),this.G=null}J(t){this.G=t}Z(t){if(this.G===null)return;let{it:i,tt:s,_s:e,us:h,et:r,Nt:o,Ts:l}=this.G;if(s===null)return;let a=t.context;a.lineCap="butt",a.lineWidth=r*t.verticalPixelRatio,Q(a,o),a.lineJoin="round";let u=this.Ps.bind(this);h!==void 0&&tn(t,i,h,s,e,u,Hn),l&&function(c,d,f,m,p){let{horizontalPixelRatio:b,verticalPixelRatio:g,context:w}=c,M=null,_=Math.max(1,Math.floor(b))%2/2,y=f*g+_;for(let O=m.to-1;O>=m.from;--O){let k=d[O];if(k){let E=p(c,k);E!==M&&(w.beginPath(),M!==null&&w.fill(),w.fillStyle=E,M=E);let A=Math.round(k.nt*b)+_,tt=k.st*g;w.moveTo(A,tt),w.arc(A,tt,y,0,2*Math.PI)}}w.fill()}(t,i,l,s,u)}},Xt=class extends Yt{Ps(t,i){return i.lt}};function en(n,t,i,s,e=0,h=t.length){let r=h-e;for(;0<r;){let o=r>>1,l=e+o;s(t[l],i)===n?(e=l+1,r-=o+1):r=o}return e}var Ot=en.bind(null,!0),nn=en.bind(null,!1);function $n(n,t){return n.ot<t}function In(n,t){return t<n.ot}function hn(n,t,i){let s=t.Rs(),e=t.ui(),h=Ot(n,s,$n),r=nn(n,e,In);if(!i)return{from:h,to:r};let o=h,l=r;return h>0&&h<n.length&&n[h].ot>=s&&(o=h-1),r>0&&r<n.length&&n[r-1].ot<=e&&(l=r+1),{from:o,to:l}}var yt=class{constructor(t,i,s){this.Ds=!0,this.Os=!0,this.As=!0,this.Bs=[],this.Vs=null,this.zs=t,this.Es=i,this.Is=s}bt(t){this.Ds=!0,t==="data"&&(this.Os=!0),t==="options"&&(this.As=!0)}gt(){return this.zs.kt()?(this.Ls(),this.Vs===null?null:this.Ns):null}Fs(){this.Bs=this.Bs.map(t=>Object.assign(Object.assign({},t),this.zs.js().Ws(t.ot)))}Hs(){this.Vs=null}Ls(){this.Os&&(this.$s(),this.Os=!1),this.As&&(this.Fs(),this.As=!1),this.Ds&&(this.Us(),this.Ds=!1)}Us(){let t=this.zs.Dt(),i=this.Es.St();if(this.Hs(),i.Li()||t.Li())return;let s=i.qs();if(s===null||this.zs.Bn().Ys()===0)return;let e=this.zs.Ct();e!==null&&(this.Vs=hn(this.Bs,s,this.Is),this.Xs(t,i,e.Ot),this.Ks())}},lt=class extends yt{constructor(t,i){super(t,i,!0)}Xs(t,i,s){i.Zs(this.Bs,_t(this.Vs)),t.Gs(this.Bs,s,_t(this.Vs))}Js(t,i){return{ot:t,_t:i,nt:NaN,st:NaN}}$s(){let t=this.zs.js();this.Bs=this.zs.Bn().Qs().map(i=>{let s=i.Ot[3];return this.te(i.ie,s,t)})}},Ui=class extends lt{constructor(t,i){super(t,i),this.Ns=new St,this.ne=new ji,this.se=new Xt,this.Ns.X([this.ne,this.se])}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t=this.zs.W();this.ne.J({us:t.lineType,it:this.Bs,Nt:t.lineStyle,et:t.lineWidth,cs:null,ds:t.invertFilledArea,tt:this.Vs,_s:this.Es.St().ee()}),this.se.J({us:t.lineVisible?t.lineType:void 0,it:this.Bs,Nt:t.lineStyle,et:t.lineWidth,tt:this.Vs,_s:this.Es.St().ee(),Ts:t.pointMarkersVisible?t.pointMarkersRadius||t.lineWidth/2+2:void 0})}},qi=class extends T{constructor(){super(...arguments),this.Et=null,this.re=0,this.he=0}J(t){this.Et=t}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){if(this.Et===null||this.Et.Bn.length===0||this.Et.tt===null)return;this.re=this.le(i),this.re>=2&&Math.max(1,Math.floor(i))%2!=this.re%2&&this.re--,this.he=this.Et.ae?Math.min(this.re,Math.floor(i)):this.re;let e=null,h=this.he<=this.re&&this.Et.ee>=Math.floor(1.5*i);for(let r=this.Et.tt.from;r<this.Et.tt.to;++r){let o=this.Et.Bn[r];e!==o.oe&&(t.fillStyle=o.oe,e=o.oe);let l=Math.floor(.5*this.he),a=Math.round(o.nt*i),u=a-l,c=this.he,d=u+c-1,f=Math.min(o._e,o.ue),m=Math.max(o._e,o.ue),p=Math.round(f*s)-l,b=Math.round(m*s)+l,g=Math.max(b-p,this.he);t.fillRect(u,p,c,g);let w=Math.ceil(1.5*this.re);if(h){if(this.Et.ce){let O=a-w,k=Math.max(p,Math.round(o.de*s)-l),E=k+c-1;E>p+g-1&&(E=p+g-1,k=E-c+1),t.fillRect(O,k,u-O,E-k+1)}let M=a+w,_=Math.max(p,Math.round(o.fe*s)-l),y=_+c-1;y>p+g-1&&(y=p+g-1,_=y-c+1),t.fillRect(d+1,_,M-d,y-_+1)}}}le(t){let i=Math.floor(t);return Math.max(i,Math.floor(function(s,e){return Math.floor(.3*s*e)}(v(this.Et).ee,t)))}},Jt=class extends yt{constructor(t,i){super(t,i,!1)}Xs(t,i,s){i.Zs(this.Bs,_t(this.Vs)),t.ve(this.Bs,s,_t(this.Vs))}pe(t,i,s){return{ot:t,me:i.Ot[0],be:i.Ot[1],we:i.Ot[2],ge:i.Ot[3],nt:NaN,de:NaN,_e:NaN,ue:NaN,fe:NaN}}$s(){let t=this.zs.js();this.Bs=this.zs.Bn().Qs().map(i=>this.te(i.ie,i,t))}},Zi=class extends Jt{constructor(){super(...arguments),this.Ns=new qi}te(t,i,s){return Object.assign(Object.assign({},this.pe(t,i,s)),s.Ws(t))}Ks(){let t=this.zs.W();this.Ns.J({Bn:this.Bs,ee:this.Es.St().ee(),ce:t.openVisible,ae:t.thinBars,tt:this.Vs})}},Qi=class extends Qt{constructor(){super(...arguments),this.ys=new xt}fs(t,i){let s=this.G;return this.ys.vs(t,{bs:i.Me,ws:i.xe,gs:i.Se,Ms:i.ye,xs:t.bitmapSize.height,cs:s.cs})}},Yi=class extends Yt{constructor(){super(...arguments),this.ke=new xt}Ps(t,i){let s=this.G;return this.ke.vs(t,{bs:i.Ce,ws:i.Ce,gs:i.Te,Ms:i.Te,xs:t.bitmapSize.height,cs:s.cs})}},Xi=class extends lt{constructor(t,i){super(t,i),this.Ns=new St,this.Pe=new Qi,this.Re=new Yi,this.Ns.X([this.Pe,this.Re])}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t=this.zs.Ct();if(t===null)return;let i=this.zs.W(),s=this.zs.Dt().Rt(i.baseValue.price,t.Ot),e=this.Es.St().ee();this.Pe.J({it:this.Bs,et:i.lineWidth,Nt:i.lineStyle,us:i.lineType,cs:s,ds:!1,tt:this.Vs,_s:e}),this.Re.J({it:this.Bs,et:i.lineWidth,Nt:i.lineStyle,us:i.lineVisible?i.lineType:void 0,Ts:i.pointMarkersVisible?i.pointMarkersRadius||i.lineWidth/2+2:void 0,cs:s,tt:this.Vs,_s:e})}},Ji=class extends T{constructor(){super(...arguments),this.Et=null,this.re=0}J(t){this.Et=t}Z(t){if(this.Et===null||this.Et.Bn.length===0||this.Et.tt===null)return;let{horizontalPixelRatio:i}=t;this.re=function(h,r){if(h>=2.5&&h<=4)return Math.floor(3*r);let o=1-.2*Math.atan(Math.max(4,h)-4)/(.5*Math.PI),l=Math.floor(h*o*r),a=Math.floor(h*r),u=Math.min(l,a);return Math.max(Math.floor(r),u)}(this.Et.ee,i),this.re>=2&&Math.floor(i)%2!=this.re%2&&this.re--;let s=this.Et.Bn;this.Et.De&&this.Oe(t,s,this.Et.tt),this.Et._i&&this.Ae(t,s,this.Et.tt);let e=this.Be(i);(!this.Et._i||this.re>2*e)&&this.Ve(t,s,this.Et.tt)}Oe(t,i,s){if(this.Et===null)return;let{context:e,horizontalPixelRatio:h,verticalPixelRatio:r}=t,o="",l=Math.min(Math.floor(h),Math.floor(this.Et.ee*h));l=Math.max(Math.floor(h),Math.min(l,this.re));let a=Math.floor(.5*l),u=null;for(let c=s.from;c<s.to;c++){let d=i[c];d.ze!==o&&(e.fillStyle=d.ze,o=d.ze);let f=Math.round(Math.min(d.de,d.fe)*r),m=Math.round(Math.max(d.de,d.fe)*r),p=Math.round(d._e*r),b=Math.round(d.ue*r),g=Math.round(h*d.nt)-a,w=g+l-1;u!==null&&(g=Math.max(u+1,g),g=Math.min(g,w));let M=w-g+1;e.fillRect(g,p,M,f-p),e.fillRect(g,m+1,M,b-m),u=w}}Be(t){let i=Math.floor(1*t);this.re<=2*i&&(i=Math.floor(.5*(this.re-1)));let s=Math.max(Math.floor(t),i);return this.re<=2*s?Math.max(Math.floor(t),Math.floor(1*t)):s}Ae(t,i,s){if(this.Et===null)return;let{context:e,horizontalPixelRatio:h,verticalPixelRatio:r}=t,o="",l=this.Be(h),a=null;for(let u=s.from;u<s.to;u++){let c=i[u];c.Ee!==o&&(e.fillStyle=c.Ee,o=c.Ee);let d=Math.round(c.nt*h)-Math.floor(.5*this.re),f=d+this.re-1,m=Math.round(Math.min(c.de,c.fe)*r),p=Math.round(Math.max(c.de,c.fe)*r);if(a!==null&&(d=Math.max(a+1,d),d=Math.min(d,f)),this.Et.ee*h>2*l)Dn(e,d,m,f-d+1,p-m+1,l);else{let b=f-d+1;e.fillRect(d,m,b,p-m+1)}a=f}}Ve(t,i,s){if(this.Et===null)return;let{context:e,horizontalPixelRatio:h,verticalPixelRatio:r}=t,o="",l=this.Be(h);for(let a=s.from;a<s.to;a++){let u=i[a],c=Math.round(Math.min(u.de,u.fe)*r),d=Math.round(Math.max(u.de,u.fe)*r),f=Math.round(u.nt*h)-Math.floor(.5*this.re),m=f+this.re-1;if(u.oe!==o){let p=u.oe;e.fillStyle=p,o=p}this.Et._i&&(f+=l,c+=l,m-=l,d-=l),c>d||e.fillRect(f,c,m-f+1,d-c+1)}}},Ki=class extends Jt{constructor(){super(...arguments),this.Ns=new Ji}te(t,i,s){return Object.assign(Object.assign({},this.pe(t,i,s)),s.Ws(t))}Ks(){let t=this.zs.W();this.Ns.J({Bn:this.Bs,ee:this.Es.St().ee(),De:t.wickVisible,_i:t.borderVisible,tt:this.Vs})}},Gi=class{constructor(t,i){this.Ie=t,this.Ei=i}K(t,i,s){this.Ie.draw(t,this.Ei,i,s)}},dt=class extends yt{constructor(t,i,s){super(t,i,!1),this.mn=s,this.Ns=new Gi(this.mn.renderer(),e=>{let h=t.Ct();return h===null?null:t.Dt().Rt(e,h.Ot)})}Le(t){return this.mn.priceValueBuilder(t)}Ne(t){return this.mn.isWhitespace(t)}$s(){let t=this.zs.js();this.Bs=this.zs.Bn().Qs().map(i=>Object.assign(Object.assign({ot:i.ie,nt:NaN},t.Ws(i.ie)),{Fe:i.We}))}Xs(t,i){i.Zs(this.Bs,_t(this.Vs))}Ks(){this.mn.update({bars:this.Bs.map(Fn),barSpacing:this.Es.St().ee(),visibleRange:this.Vs},this.zs.W())}};function Fn(n){return{x:n.nt,time:n.ot,originalData:n.Fe,barColor:n.oe}}var ts=class extends T{constructor(){super(...arguments),this.Et=null,this.je=[]}J(t){this.Et=t,this.je=[]}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){if(this.Et===null||this.Et.it.length===0||this.Et.tt===null)return;this.je.length||this.He(i);let e=Math.max(1,Math.floor(s)),h=Math.round(this.Et.$e*s)-Math.floor(e/2),r=h+e;for(let o=this.Et.tt.from;o<this.Et.tt.to;o++){let l=this.Et.it[o],a=this.je[o-this.Et.tt.from],u=Math.round(l.st*s),c,d;t.fillStyle=l.oe,u<=h?(c=u,d=r):(c=h,d=u-Math.floor(e/2)+e),t.fillRect(a.Rs,c,a.ui-a.Rs+1,d-c)}}He(t){if(this.Et===null||this.Et.it.length===0||this.Et.tt===null)return void(this.je=[]);let i=Math.ceil(this.Et.ee*t)<=1?0:Math.max(1,Math.floor(t)),s=Math.round(this.Et.ee*t)-i;this.je=new Array(this.Et.tt.to-this.Et.tt.from);for(let h=this.Et.tt.from;h<this.Et.tt.to;h++){let r=this.Et.it[h],o=Math.round(r.nt*t),l,a;if(s%2){let u=(s-1)/2;l=o-u,a=o+u}else{let u=s/2;l=o-u,a=o+u-1}this.je[h-this.Et.tt.from]={Rs:l,ui:a,Ue:o,qe:r.nt*t,ot:r.ot}}for(let h=this.Et.tt.from+1;h<this.Et.tt.to;h++){let r=this.je[h-this.Et.tt.from],o=this.je[h-this.Et.tt.from-1];r.ot===o.ot+1&&r.Rs-o.ui!==i+1&&(o.Ue>o.qe?o.ui=r.Rs-i-1:r.Rs=o.ui+i+1)}let e=Math.ceil(this.Et.ee*t);for(let h=this.Et.tt.from;h<this.Et.tt.to;h++){let r=this.je[h-this.Et.tt.from];r.ui<r.Rs&&(r.ui=r.Rs);let o=r.ui-r.Rs+1;e=Math.min(o,e)}if(i>0&&e<4)for(let h=this.Et.tt.from;h<this.Et.tt.to;h++){let r=this.je[h-this.Et.tt.from];r.ui-r.Rs+1>e&&(r.Ue>r.qe?r.ui-=1:r.Rs+=1)}}},is=class extends lt{constructor(){super(...arguments),this.Ns=new ts}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t={it:this.Bs,ee:this.Es.St().ee(),tt:this.Vs,$e:this.zs.Dt().Rt(this.zs.W().base,v(this.zs.Ct()).Ot)};this.Ns.J(t)}},ss=class extends lt{constructor(){super(...arguments),this.Ns=new Xt}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t=this.zs.W(),i={it:this.Bs,Nt:t.lineStyle,us:t.lineVisible?t.lineType:void 0,et:t.lineWidth,Ts:t.pointMarkersVisible?t.pointMarkersRadius||t.lineWidth/2+2:void 0,tt:this.Vs,_s:this.Es.St().ee()};this.Ns.J(i)}},jn=/[2-9]/g,Y=class{constructor(t=50){this.Ye=0,this.Xe=1,this.Ke=1,this.Ze={},this.Ge=new Map,this.Je=t}Qe(){this.Ye=0,this.Ge.clear(),this.Xe=1,this.Ke=1,this.Ze={}}Mi(t,i,s){return this.tr(t,i,s).width}gi(t,i,s){let e=this.tr(t,i,s);return((e.actualBoundingBoxAscent||0)-(e.actualBoundingBoxDescent||0))/2}tr(t,i,s){let e=s||jn,h=String(i).replace(e,"0");if(this.Ge.has(h))return z(this.Ge.get(h)).ir;if(this.Ye===this.Je){let o=this.Ze[this.Ke];delete this.Ze[this.Ke],this.Ge.delete(o),this.Ke++,this.Ye--}t.save(),t.textBaseline="middle";let r=t.measureText(h);return t.restore(),r.width===0&&i.length||(this.Ge.set(h,{ir:r,nr:this.Xe}),this.Ze[this.Xe]=h,this.Ye++,this.Xe++),r}},es=class{constructor(t){this.sr=null,this.k=null,this.er="right",this.rr=t}hr(t,i,s){this.sr=t,this.k=i,this.er=s}K(t){this.k!==null&&this.sr!==null&&this.sr.K(t,this.k,this.rr,this.er)}},Kt=class{constructor(t,i,s){this.lr=t,this.rr=new Y(50),this.ar=i,this.F=s,this.j=-1,this.Wt=new es(this.rr)}gt(){let t=this.F._r(this.ar);if(t===null)return null;let i=t.ur(this.ar)?t.cr():this.ar.Dt();if(i===null)return null;let s=t.dr(i);if(s==="overlay")return null;let e=this.F.vr();return e.P!==this.j&&(this.j=e.P,this.rr.Qe()),this.Wt.hr(this.lr.Vi(),e,s),this.Wt}},ns=class extends T{constructor(){super(...arguments),this.Et=null}J(t){this.Et=t}pr(t,i){var s;if(!(!((s=this.Et)===null||s===void 0)&&s.kt))return null;let{st:e,et:h,mr:r}=this.Et;return i>=e-h-7&&i<=e+h+7?{br:this.Et,mr:r}:null}Z({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(this.Et===null||this.Et.kt===!1)return;let h=Math.round(this.Et.st*e);h<0||h>i.height||(t.lineCap="butt",t.strokeStyle=this.Et.O,t.lineWidth=Math.floor(this.Et.et*s),Q(t,this.Et.Nt),Je(t,h,0,i.width))}},Et=class{constructor(t){this.wr={st:0,O:"rgba(0, 0, 0, 0)",et:1,Nt:0,kt:!1},this.gr=new ns,this.ft=!0,this.zs=t,this.Es=t.$t(),this.gr.J(this.wr)}bt(){this.ft=!0}gt(){return this.zs.kt()?(this.ft&&(this.Mr(),this.ft=!1),this.gr):null}},hs=class extends Et{constructor(t){super(t)}Mr(){this.wr.kt=!1;let t=this.zs.Dt(),i=t.Sr().Sr;if(i!==2&&i!==3)return;let s=this.zs.W();if(!s.baseLineVisible||!this.zs.kt())return;let e=this.zs.Ct();e!==null&&(this.wr.kt=!0,this.wr.st=t.Rt(e.Ot,e.Ot),this.wr.O=s.baseLineColor,this.wr.et=s.baseLineWidth,this.wr.Nt=s.baseLineStyle)}},rs=class extends T{constructor(){super(...arguments),this.Et=null}J(t){this.Et=t}We(){return this.Et}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){let e=this.Et;if(e===null)return;let h=Math.max(1,Math.floor(i)),r=h%2/2,o=Math.round(e.qe.x*i)+r,l=e.qe.y*s;t.fillStyle=e.yr,t.beginPath();let a=Math.max(2,1.5*e.kr)*i;t.arc(o,l,a,0,2*Math.PI,!1),t.fill(),t.fillStyle=e.Cr,t.beginPath(),t.arc(o,l,e.ht*i,0,2*Math.PI,!1),t.fill(),t.lineWidth=h,t.strokeStyle=e.Tr,t.beginPath(),t.arc(o,l,e.ht*i+h/2,0,2*Math.PI,!1),t.stroke()}},Un=[{Pr:0,Rr:.25,Dr:4,Or:10,Ar:.25,Br:0,Vr:.4,zr:.8},{Pr:.25,Rr:.525,Dr:10,Or:14,Ar:0,Br:0,Vr:.8,zr:0},{Pr:.525,Rr:1,Dr:14,Or:14,Ar:0,Br:0,Vr:0,zr:0}];function be(n,t,i,s){return function(e,h){if(e==="transparent")return e;let r=jt(e),o=r[3];return`rgba(${r[0]}, ${r[1]}, ${r[2]}, ${h*o})`}(n,i+(s-i)*t)}function ge(n,t){let i=n%2600/2600,s;for(let l of Un)if(i>=l.Pr&&i<=l.Rr){s=l;break}I(s!==void 0,"Last price animation internal logic error");let e=(i-s.Pr)/(s.Rr-s.Pr);return{Cr:be(t,e,s.Ar,s.Br),Tr:be(t,e,s.Vr,s.zr),ht:(h=e,r=s.Dr,o=s.Or,r+(o-r)*h)};var h,r,o}var os=class{constructor(t){this.Wt=new rs,this.ft=!0,this.Er=!0,this.Ir=performance.now(),this.Lr=this.Ir-1,this.Nr=t}Fr(){this.Lr=this.Ir-1,this.bt()}Wr(){if(this.bt(),this.Nr.W().lastPriceAnimation===2){let t=performance.now(),i=this.Lr-t;if(i>0)return void(i<650&&(this.Lr+=2600));this.Ir=t,this.Lr=t+2600}}bt(){this.ft=!0}jr(){this.Er=!0}kt(){return this.Nr.W().lastPriceAnimation!==0}Hr(){switch(this.Nr.W().lastPriceAnimation){case 0:return!1;case 1:return!0;case 2:return performance.now()<=this.Lr}}gt(){return this.ft?(this.Mt(),this.ft=!1,this.Er=!1):this.Er&&(this.$r(),this.Er=!1),this.Wt}Mt(){this.Wt.J(null);let t=this.Nr.$t().St(),i=t.qs(),s=this.Nr.Ct();if(i===null||s===null)return;let e=this.Nr.Ur(!0);if(e.qr||!i.Yr(e.ie))return;let h={x:t.zt(e.ie),y:this.Nr.Dt().Rt(e._t,s.Ot)},r=e.O,o=this.Nr.W().lineWidth,l=ge(this.Xr(),r);this.Wt.J({yr:r,kr:o,Cr:l.Cr,Tr:l.Tr,ht:l.ht,qe:h})}$r(){let t=this.Wt.We();if(t!==null){let i=ge(this.Xr(),t.yr);t.Cr=i.Cr,t.Tr=i.Tr,t.ht=i.ht}}Xr(){return this.Hr()?performance.now()-this.Ir:2599}};function ft(n,t){return sn(Math.min(Math.max(n,12),30)*t)}function Ct(n,t){switch(n){case"arrowDown":case"arrowUp":return ft(t,1);case"circle":return ft(t,.8);case"square":return ft(t,.7)}}function rn(n){return function(t){let i=Math.ceil(t);return i%2!=0?i-1:i}(ft(n,1))}function we(n){return Math.max(ft(n,.1),3)}function on(n,t,i,s,e){let h=Ct("square",i),r=(h-1)/2,o=n-r,l=t-r;return s>=o&&s<=o+h&&e>=l&&e<=l+h}function _e(n,t,i,s){let e=(Ct("arrowUp",s)-1)/2*i.Kr,h=(sn(s/2)-1)/2*i.Kr;t.beginPath(),n?(t.moveTo(i.nt-e,i.st),t.lineTo(i.nt,i.st-e),t.lineTo(i.nt+e,i.st),t.lineTo(i.nt+h,i.st),t.lineTo(i.nt+h,i.st+e),t.lineTo(i.nt-h,i.st+e),t.lineTo(i.nt-h,i.st)):(t.moveTo(i.nt-e,i.st),t.lineTo(i.nt,i.st+e),t.lineTo(i.nt+e,i.st),t.lineTo(i.nt+h,i.st),t.lineTo(i.nt+h,i.st-e),t.lineTo(i.nt-h,i.st-e),t.lineTo(i.nt-h,i.st)),t.fill()}function qn(n,t,i,s,e,h){return on(t,i,s,e,h)}var ls=class extends T{constructor(){super(...arguments),this.Et=null,this.rr=new Y,this.j=-1,this.H="",this.Zr=""}J(t){this.Et=t}hr(t,i){this.j===t&&this.H===i||(this.j=t,this.H=i,this.Zr=ht(t,i),this.rr.Qe())}pr(t,i){if(this.Et===null||this.Et.tt===null)return null;for(let s=this.Et.tt.from;s<this.Et.tt.to;s++){let e=this.Et.it[s];if(Qn(e,t,i))return{br:e.Gr,mr:e.mr}}return null}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s},e,h){if(this.Et!==null&&this.Et.tt!==null){t.textBaseline="middle",t.font=this.Zr;for(let r=this.Et.tt.from;r<this.Et.tt.to;r++){let o=this.Et.it[r];o.Zt!==void 0&&(o.Zt.ji=this.rr.Mi(t,o.Zt.Jr),o.Zt.Vt=this.j,o.Zt.nt=o.nt-o.Zt.ji/2),Zn(o,t,i,s)}}}};function Zn(n,t,i,s){t.fillStyle=n.O,n.Zt!==void 0&&function(e,h,r,o,l,a){e.save(),e.scale(l,a),e.fillText(h,r,o),e.restore()}(t,n.Zt.Jr,n.Zt.nt,n.Zt.st,i,s),function(e,h,r){if(e.Ys!==0){switch(e.Qr){case"arrowDown":return void _e(!1,h,r,e.Ys);case"arrowUp":return void _e(!0,h,r,e.Ys);case"circle":return void function(o,l,a){let u=(Ct("circle",a)-1)/2;o.beginPath(),o.arc(l.nt,l.st,u*l.Kr,0,2*Math.PI,!1),o.fill()}(h,r,e.Ys);case"square":return void function(o,l,a){let u=Ct("square",a),c=(u-1)*l.Kr/2,d=l.nt-c,f=l.st-c;o.fillRect(d,f,u*l.Kr,u*l.Kr)}(h,r,e.Ys)}e.Qr}}(n,t,function(e,h,r){let o=Math.max(1,Math.floor(h))%2/2;return{nt:Math.round(e.nt*h)+o,st:e.st*r,Kr:h}}(n,i,s))}function Qn(n,t,i){return!(n.Zt===void 0||!function(s,e,h,r,o,l){let a=r/2;return o>=s&&o<=s+h&&l>=e-a&&l<=e+a}(n.Zt.nt,n.Zt.st,n.Zt.ji,n.Zt.Vt,t,i))||function(s,e,h){if(s.Ys===0)return!1;switch(s.Qr){case"arrowDown":case"arrowUp":return qn(0,s.nt,s.st,s.Ys,e,h);case"circle":return function(r,o,l,a,u){let c=2+Ct("circle",l)/2,d=r-a,f=o-u;return Math.sqrt(d*d+f*f)<=c}(s.nt,s.st,s.Ys,e,h);case"square":return on(s.nt,s.st,s.Ys,e,h)}}(n,t,i)}function Yn(n,t,i,s,e,h,r,o,l){let a=P(i)?i:i.ge,u=P(i)?i:i.be,c=P(i)?i:i.we,d=P(t.size)?Math.max(t.size,0):1,f=rn(o.ee())*d,m=f/2;switch(n.Ys=f,t.position){case"inBar":return n.st=r.Rt(a,l),void(n.Zt!==void 0&&(n.Zt.st=n.st+m+h+.6*e));case"aboveBar":return n.st=r.Rt(u,l)-m-s.th,n.Zt!==void 0&&(n.Zt.st=n.st-m-.6*e,s.th+=1.2*e),void(s.th+=f+h);case"belowBar":return n.st=r.Rt(c,l)+m+s.ih,n.Zt!==void 0&&(n.Zt.st=n.st+m+h+.6*e,s.ih+=1.2*e),void(s.ih+=f+h)}t.position}var as=class{constructor(t,i){this.ft=!0,this.nh=!0,this.sh=!0,this.eh=null,this.Wt=new ls,this.Nr=t,this.Hi=i,this.Et={it:[],tt:null}}bt(t){this.ft=!0,this.sh=!0,t==="data"&&(this.nh=!0)}gt(t){if(!this.Nr.kt())return null;this.ft&&this.rh();let i=this.Hi.W().layout;return this.Wt.hr(i.fontSize,i.fontFamily),this.Wt.J(this.Et),this.Wt}hh(){if(this.sh){if(this.Nr.lh().length>0){let t=this.Hi.St().ee(),i=we(t),s=1.5*rn(t)+2*i;this.eh={above:s,below:s}}else this.eh=null;this.sh=!1}return this.eh}rh(){let t=this.Nr.Dt(),i=this.Hi.St(),s=this.Nr.lh();this.nh&&(this.Et.it=s.map(u=>({ot:u.time,nt:0,st:0,Ys:0,Qr:u.shape,O:u.color,Gr:u.Gr,mr:u.id,Zt:void 0})),this.nh=!1);let e=this.Hi.W().layout;this.Et.tt=null;let h=i.qs();if(h===null)return;let r=this.Nr.Ct();if(r===null||this.Et.it.length===0)return;let o=NaN,l=we(i.ee()),a={th:l,ih:l};this.Et.tt=hn(this.Et.it,h,!0);for(let u=this.Et.tt.from;u<this.Et.tt.to;u++){let c=s[u];c.time!==o&&(a.th=l,a.ih=l,o=c.time);let d=this.Et.it[u];d.nt=i.zt(c.time),c.text!==void 0&&c.text.length>0&&(d.Zt={Jr:c.text,nt:0,st:0,ji:0,Vt:0});let f=this.Nr.ah(c.time);f!==null&&Yn(d,c,f,a,e.fontSize,l,t,i,r.Ot)}this.ft=!1}},us=class extends Et{constructor(t){super(t)}Mr(){let t=this.wr;t.kt=!1;let i=this.zs.W();if(!i.priceLineVisible||!this.zs.kt())return;let s=this.zs.Ur(i.priceLineSource===0);s.qr||(t.kt=!0,t.st=s.Si,t.O=this.zs.oh(s.O),t.et=i.priceLineWidth,t.Nt=i.priceLineStyle)}},cs=class extends rt{constructor(t){super(),this.jt=t}zi(t,i,s){t.kt=!1,i.kt=!1;let e=this.jt;if(!e.kt())return;let h=e.W(),r=h.lastValueVisible,o=e._h()!=="",l=h.seriesLastValueMode===0,a=e.Ur(!1);if(a.qr)return;r&&(t.Zt=this.uh(a,r,l),t.kt=t.Zt.length!==0),(o||l)&&(i.Zt=this.dh(a,r,o,l),i.kt=i.Zt.length>0);let u=e.oh(a.O),c=oi(u);s.t=c.t,s.Si=a.Si,i.At=e.$t().Bt(a.Si/e.Dt().Vt()),t.At=u,t.O=c.i,i.O=c.i}dh(t,i,s,e){let h="",r=this.jt._h();return s&&r.length!==0&&(h+=`${r} `),i&&e&&(h+=this.jt.Dt().fh()?t.ph:t.mh),h.trim()}uh(t,i,s){return i?s?this.jt.Dt().fh()?t.mh:t.ph:t.Zt:""}};function Se(n,t,i,s){let e=Number.isFinite(t),h=Number.isFinite(i);return e&&h?n(t,i):e||h?e?t:i:s}var R=class n{constructor(t,i){this.bh=t,this.wh=i}gh(t){return t!==null&&this.bh===t.bh&&this.wh===t.wh}Mh(){return new n(this.bh,this.wh)}xh(){return this.bh}Sh(){return this.wh}yh(){return this.wh-this.bh}Li(){return this.wh===this.bh||Number.isNaN(this.wh)||Number.isNaN(this.bh)}Jn(t){return t===null?this:new n(Se(Math.min,this.xh(),t.xh(),-1/0),Se(Math.max,this.Sh(),t.Sh(),1/0))}kh(t){if(!P(t)||this.wh-this.bh===0)return;let i=.5*(this.wh+this.bh),s=this.wh-i,e=this.bh-i;s*=t,e*=t,this.wh=i+s,this.bh=i+e}Ch(t){P(t)&&(this.wh+=t,this.bh+=t)}Th(){return{minValue:this.bh,maxValue:this.wh}}static Ph(t){return t===null?null:new n(t.minValue,t.maxValue)}},Gt=class n{constructor(t,i){this.Rh=t,this.Dh=i||null}Oh(){return this.Rh}Ah(){return this.Dh}Th(){return this.Rh===null?null:{priceRange:this.Rh.Th(),margins:this.Dh||void 0}}static Ph(t){return t===null?null:new n(R.Ph(t.priceRange),t.margins)}},ds=class extends Et{constructor(t,i){super(t),this.Bh=i}Mr(){let t=this.wr;t.kt=!1;let i=this.Bh.W();if(!this.zs.kt()||!i.lineVisible)return;let s=this.Bh.Vh();s!==null&&(t.kt=!0,t.st=s,t.O=i.color,t.et=i.lineWidth,t.Nt=i.lineStyle,t.mr=this.Bh.W().id)}},fs=class extends rt{constructor(t,i){super(),this.Nr=t,this.Bh=i}zi(t,i,s){t.kt=!1,i.kt=!1;let e=this.Bh.W(),h=e.axisLabelVisible,r=e.title!=="",o=this.Nr;if(!h||!o.kt())return;let l=this.Bh.Vh();if(l===null)return;r&&(i.Zt=e.title,i.kt=!0),i.At=o.$t().Bt(l/o.Dt().Vt()),t.Zt=this.zh(e.price),t.kt=!0;let a=oi(e.axisLabelColor||e.color);s.t=a.t;let u=e.axisLabelTextColor||a.i;t.O=u,i.O=u,s.Si=l}zh(t){let i=this.Nr.Ct();return i===null?"":this.Nr.Dt().Ni(t,i.Ot)}},ms=class{constructor(t,i){this.Nr=t,this._n=i,this.Eh=new ds(t,this),this.lr=new fs(t,this),this.Ih=new Kt(this.lr,t,t.$t())}Lh(t){N(this._n,t),this.bt(),this.Nr.$t().Nh()}W(){return this._n}Fh(){return this.Eh}Wh(){return this.Ih}jh(){return this.lr}bt(){this.Eh.bt(),this.lr.bt()}Vh(){let t=this.Nr,i=t.Dt();if(t.$t().St().Li()||i.Li())return null;let s=t.Ct();return s===null?null:i.Rt(this._n.price,s.Ot)}},ps=class extends Mt{constructor(t){super(),this.Hi=t}$t(){return this.Hi}},Xn={Bar:(n,t,i,s)=>{var e;let h=t.upColor,r=t.downColor,o=v(n(i,s)),l=st(o.Ot[0])<=st(o.Ot[3]);return{oe:(e=o.O)!==null&&e!==void 0?e:l?h:r}},Candlestick:(n,t,i,s)=>{var e,h,r;let o=t.upColor,l=t.downColor,a=t.borderUpColor,u=t.borderDownColor,c=t.wickUpColor,d=t.wickDownColor,f=v(n(i,s)),m=st(f.Ot[0])<=st(f.Ot[3]);return{oe:(e=f.O)!==null&&e!==void 0?e:m?o:l,Ee:(h=f.At)!==null&&h!==void 0?h:m?a:u,ze:(r=f.Hh)!==null&&r!==void 0?r:m?c:d}},Custom:(n,t,i,s)=>{var e;return{oe:(e=v(n(i,s)).O)!==null&&e!==void 0?e:t.color}},Area:(n,t,i,s)=>{var e,h,r,o;let l=v(n(i,s));return{oe:(e=l.lt)!==null&&e!==void 0?e:t.lineColor,lt:(h=l.lt)!==null&&h!==void 0?h:t.lineColor,ks:(r=l.ks)!==null&&r!==void 0?r:t.topColor,Cs:(o=l.Cs)!==null&&o!==void 0?o:t.bottomColor}},Baseline:(n,t,i,s)=>{var e,h,r,o,l,a;let u=v(n(i,s));return{oe:u.Ot[3]>=t.baseValue.price?t.topLineColor:t.bottomLineColor,Ce:(e=u.Ce)!==null&&e!==void 0?e:t.topLineColor,Te:(h=u.Te)!==null&&h!==void 0?h:t.bottomLineColor,Me:(r=u.Me)!==null&&r!==void 0?r:t.topFillColor1,xe:(o=u.xe)!==null&&o!==void 0?o:t.topFillColor2,Se:(l=u.Se)!==null&&l!==void 0?l:t.bottomFillColor1,ye:(a=u.ye)!==null&&a!==void 0?a:t.bottomFillColor2}},Line:(n,t,i,s)=>{var e,h;let r=v(n(i,s));return{oe:(e=r.O)!==null&&e!==void 0?e:t.color,lt:(h=r.O)!==null&&h!==void 0?h:t.color}},Histogram:(n,t,i,s)=>{var e;return{oe:(e=v(n(i,s)).O)!==null&&e!==void 0?e:t.color}}},vs=class{constructor(t){this.$h=(i,s)=>s!==void 0?s.Ot:this.Nr.Bn().Uh(i),this.Nr=t,this.qh=Xn[t.Yh()]}Ws(t,i){return this.qh(this.$h,this.Nr.W(),t,i)}},Me;(function(n){n[n.NearestLeft=-1]="NearestLeft",n[n.None=0]="None",n[n.NearestRight=1]="NearestRight"})(Me||(Me={}));var $=30,bs=class{constructor(){this.Xh=[],this.Kh=new Map,this.Zh=new Map}Gh(){return this.Ys()>0?this.Xh[this.Xh.length-1]:null}Jh(){return this.Ys()>0?this.Qh(0):null}An(){return this.Ys()>0?this.Qh(this.Xh.length-1):null}Ys(){return this.Xh.length}Li(){return this.Ys()===0}Yr(t){return this.tl(t,0)!==null}Uh(t){return this.il(t)}il(t,i=0){let s=this.tl(t,i);return s===null?null:Object.assign(Object.assign({},this.nl(s)),{ie:this.Qh(s)})}Qs(){return this.Xh}sl(t,i,s){if(this.Li())return null;let e=null;for(let h of s)e=Bt(e,this.el(t,i,h));return e}J(t){this.Zh.clear(),this.Kh.clear(),this.Xh=t}Qh(t){return this.Xh[t].ie}nl(t){return this.Xh[t]}tl(t,i){let s=this.rl(t);if(s===null&&i!==0)switch(i){case-1:return this.hl(t);case 1:return this.ll(t);default:throw new TypeError("Unknown search mode")}return s}hl(t){let i=this.al(t);return i>0&&(i-=1),i!==this.Xh.length&&this.Qh(i)<t?i:null}ll(t){let i=this.ol(t);return i!==this.Xh.length&&t<this.Qh(i)?i:null}rl(t){let i=this.al(t);return i===this.Xh.length||t<this.Xh[i].ie?null:i}al(t){return Ot(this.Xh,t,(i,s)=>i.ie<s)}ol(t){return nn(this.Xh,t,(i,s)=>i.ie>s)}_l(t,i,s){let e=null;for(let h=t;h<i;h++){let r=this.Xh[h].Ot[s];Number.isNaN(r)||(e===null?e={ul:r,cl:r}:(r<e.ul&&(e.ul=r),r>e.cl&&(e.cl=r)))}return e}el(t,i,s){if(this.Li())return null;let e=null,h=v(this.Jh()),r=v(this.An()),o=Math.max(t,h),l=Math.min(i,r),a=Math.ceil(o/$)*$,u=Math.max(a,Math.floor(l/$)*$);{let d=this.al(o),f=this.ol(Math.min(l,a,i));e=Bt(e,this._l(d,f,s))}let c=this.Kh.get(s);c===void 0&&(c=new Map,this.Kh.set(s,c));for(let d=Math.max(a+1,o);d<u;d+=$){let f=Math.floor(d/$),m=c.get(f);if(m===void 0){let p=this.al(f*$),b=this.ol((f+1)*$-1);m=this._l(p,b,s),c.set(f,m)}e=Bt(e,m)}{let d=this.al(u),f=this.ol(l);e=Bt(e,this._l(d,f,s))}return e}};function Bt(n,t){return n===null?t:t===null?n:{ul:Math.min(n.ul,t.ul),cl:Math.max(n.cl,t.cl)}}var gs=class{constructor(t){this.dl=t}K(t,i,s){this.dl.draw(t)}fl(t,i,s){var e,h;(h=(e=this.dl).drawBackground)===null||h===void 0||h.call(e,t)}},mt=class{constructor(t){this.Ge=null,this.mn=t}gt(){var t;let i=this.mn.renderer();if(i===null)return null;if(((t=this.Ge)===null||t===void 0?void 0:t.vl)===i)return this.Ge.pl;let s=new gs(i);return this.Ge={vl:i,pl:s},s}ml(){var t,i,s;return(s=(i=(t=this.mn).zOrder)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:"normal"}};function ln(n){var t,i,s,e,h;return{Zt:n.text(),Si:n.coordinate(),xi:(t=n.fixedCoordinate)===null||t===void 0?void 0:t.call(n),O:n.textColor(),t:n.backColor(),kt:(s=(i=n.visible)===null||i===void 0?void 0:i.call(n))===null||s===void 0||s,hi:(h=(e=n.tickVisible)===null||e===void 0?void 0:e.call(n))===null||h===void 0||h}}var ws=class{constructor(t,i){this.Wt=new qt,this.bl=t,this.wl=i}gt(){return this.Wt.J(Object.assign({ji:this.wl.ji()},ln(this.bl))),this.Wt}},_s=class extends rt{constructor(t,i){super(),this.bl=t,this.Ei=i}zi(t,i,s){let e=ln(this.bl);s.t=e.t,t.O=e.O;let h=2/12*this.Ei.P();s.bi=h,s.wi=h,s.Si=e.Si,s.xi=e.xi,t.Zt=e.Zt,t.kt=e.kt,t.hi=e.hi}},Ss=class{constructor(t,i){this.gl=null,this.Ml=null,this.xl=null,this.Sl=null,this.yl=null,this.kl=t,this.Nr=i}Cl(){return this.kl}Rn(){var t,i;(i=(t=this.kl).updateAllViews)===null||i===void 0||i.call(t)}Cn(){var t,i,s,e;let h=(s=(i=(t=this.kl).paneViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.gl)===null||e===void 0?void 0:e.vl)===h)return this.gl.pl;let r=h.map(o=>new mt(o));return this.gl={vl:h,pl:r},r}Ji(){var t,i,s,e;let h=(s=(i=(t=this.kl).timeAxisViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.Ml)===null||e===void 0?void 0:e.vl)===h)return this.Ml.pl;let r=this.Nr.$t().St(),o=h.map(l=>new ws(l,r));return this.Ml={vl:h,pl:o},o}Tn(){var t,i,s,e;let h=(s=(i=(t=this.kl).priceAxisViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.xl)===null||e===void 0?void 0:e.vl)===h)return this.xl.pl;let r=this.Nr.Dt(),o=h.map(l=>new _s(l,r));return this.xl={vl:h,pl:o},o}Tl(){var t,i,s,e;let h=(s=(i=(t=this.kl).priceAxisPaneViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.Sl)===null||e===void 0?void 0:e.vl)===h)return this.Sl.pl;let r=h.map(o=>new mt(o));return this.Sl={vl:h,pl:r},r}Pl(){var t,i,s,e;let h=(s=(i=(t=this.kl).timeAxisPaneViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.yl)===null||e===void 0?void 0:e.vl)===h)return this.yl.pl;let r=h.map(o=>new mt(o));return this.yl={vl:h,pl:r},r}Rl(t,i){var s,e,h;return(h=(e=(s=this.kl).autoscaleInfo)===null||e===void 0?void 0:e.call(s,t,i))!==null&&h!==void 0?h:null}pr(t,i){var s,e,h;return(h=(e=(s=this.kl).hitTest)===null||e===void 0?void 0:e.call(s,t,i))!==null&&h!==void 0?h:null}};function wi(n,t,i,s){n.forEach(e=>{t(e).forEach(h=>{h.ml()===i&&s.push(h)})})}function _i(n){return n.Cn()}function Jn(n){return n.Tl()}function Kn(n){return n.Pl()}var kt=class extends ps{constructor(t,i,s,e,h){super(t),this.Et=new bs,this.Eh=new us(this),this.Dl=[],this.Ol=new hs(this),this.Al=null,this.Bl=null,this.Vl=[],this.zl=[],this.El=null,this.Il=[],this._n=i,this.Ll=s;let r=new cs(this);this.en=[r],this.Ih=new Kt(r,this,t),s!=="Area"&&s!=="Line"&&s!=="Baseline"||(this.Al=new os(this)),this.Nl(),this.Fl(h)}S(){this.El!==null&&clearTimeout(this.El)}oh(t){return this._n.priceLineColor||t}Ur(t){let i={qr:!0},s=this.Dt();if(this.$t().St().Li()||s.Li()||this.Et.Li())return i;let e=this.$t().St().qs(),h=this.Ct();if(e===null||h===null)return i;let r,o;if(t){let c=this.Et.Gh();if(c===null)return i;r=c,o=c.ie}else{let c=this.Et.il(e.ui(),-1);if(c===null||(r=this.Et.Uh(c.ie),r===null))return i;o=c.ie}let l=r.Ot[3],a=this.js().Ws(o,{Ot:r}),u=s.Rt(l,h.Ot);return{qr:!1,_t:l,Zt:s.Ni(l,h.Ot),ph:s.Wl(l),mh:s.jl(l,h.Ot),O:a.oe,Si:u,ie:o}}js(){return this.Bl!==null||(this.Bl=new vs(this)),this.Bl}W(){return this._n}Lh(t){let i=t.priceScaleId;i!==void 0&&i!==this._n.priceScaleId&&this.$t().Hl(this,i),N(this._n,t),t.priceFormat!==void 0&&(this.Nl(),this.$t().$l()),this.$t().Ul(this),this.$t().ql(),this.mn.bt("options")}J(t,i){this.Et.J(t),this.Yl(),this.mn.bt("data"),this.un.bt("data"),this.Al!==null&&(i&&i.Xl?this.Al.Wr():t.length===0&&this.Al.Fr());let s=this.$t()._r(this);this.$t().Kl(s),this.$t().Ul(this),this.$t().ql(),this.$t().Nh()}Zl(t){this.Vl=t,this.Yl();let i=this.$t()._r(this);this.un.bt("data"),this.$t().Kl(i),this.$t().Ul(this),this.$t().ql(),this.$t().Nh()}Gl(){return this.Vl}lh(){return this.zl}Jl(t){let i=new ms(this,t);return this.Dl.push(i),this.$t().Ul(this),i}Ql(t){let i=this.Dl.indexOf(t);i!==-1&&this.Dl.splice(i,1),this.$t().Ul(this)}Yh(){return this.Ll}Ct(){let t=this.ta();return t===null?null:{Ot:t.Ot[3],ia:t.ot}}ta(){let t=this.$t().St().qs();if(t===null)return null;let i=t.Rs();return this.Et.il(i,1)}Bn(){return this.Et}ah(t){let i=this.Et.Uh(t);return i===null?null:this.Ll==="Bar"||this.Ll==="Candlestick"||this.Ll==="Custom"?{me:i.Ot[0],be:i.Ot[1],we:i.Ot[2],ge:i.Ot[3]}:i.Ot[3]}na(t){let i=[];wi(this.Il,_i,"top",i);let s=this.Al;return s!==null&&s.kt()&&(this.El===null&&s.Hr()&&(this.El=setTimeout(()=>{this.El=null,this.$t().sa()},0)),s.jr(),i.push(s)),i}Cn(){let t=[];this.ea()||t.push(this.Ol),t.push(this.mn,this.Eh,this.un);let i=this.Dl.map(s=>s.Fh());return t.push(...i),wi(this.Il,_i,"normal",t),t}ra(){return this.ha(_i,"bottom")}la(t){return this.ha(Jn,t)}aa(t){return this.ha(Kn,t)}oa(t,i){return this.Il.map(s=>s.pr(t,i)).filter(s=>s!==null)}Gi(t){return[this.Ih,...this.Dl.map(i=>i.Wh())]}Tn(t,i){if(i!==this.qi&&!this.ea())return[];let s=[...this.en];for(let e of this.Dl)s.push(e.jh());return this.Il.forEach(e=>{s.push(...e.Tn())}),s}Ji(){let t=[];return this.Il.forEach(i=>{t.push(...i.Ji())}),t}Rl(t,i){if(this._n.autoscaleInfoProvider!==void 0){let s=this._n.autoscaleInfoProvider(()=>{let e=this._a(t,i);return e===null?null:e.Th()});return Gt.Ph(s)}return this._a(t,i)}ua(){return this._n.priceFormat.minMove}ca(){return this.da}Rn(){var t;this.mn.bt(),this.un.bt();for(let i of this.en)i.bt();for(let i of this.Dl)i.bt();this.Eh.bt(),this.Ol.bt(),(t=this.Al)===null||t===void 0||t.bt(),this.Il.forEach(i=>i.Rn())}Dt(){return v(super.Dt())}yt(t){if(!((this.Ll==="Line"||this.Ll==="Area"||this.Ll==="Baseline")&&this._n.crosshairMarkerVisible))return null;let i=this.Et.Uh(t);return i===null?null:{_t:i.Ot[3],ht:this.fa(),At:this.va(),Pt:this.pa(),Tt:this.ma(t)}}_h(){return this._n.title}kt(){return this._n.visible}ba(t){this.Il.push(new Ss(t,this))}wa(t){this.Il=this.Il.filter(i=>i.Cl()!==t)}ga(){if(this.mn instanceof dt)return t=>this.mn.Le(t)}Ma(){if(this.mn instanceof dt)return t=>this.mn.Ne(t)}ea(){return!ai(this.Dt().xa())}_a(t,i){if(!wt(t)||!wt(i)||this.Et.Li())return null;let s=this.Ll==="Line"||this.Ll==="Area"||this.Ll==="Baseline"||this.Ll==="Histogram"?[3]:[2,1],e=this.Et.sl(t,i,s),h=e!==null?new R(e.ul,e.cl):null;if(this.Yh()==="Histogram"){let o=this._n.base,l=new R(o,o);h=h!==null?h.Jn(l):l}let r=this.un.hh();return this.Il.forEach(o=>{let l=o.Rl(t,i);if(l?.priceRange){let f=new R(l.priceRange.minValue,l.priceRange.maxValue);h=h!==null?h.Jn(f):f}var a,u,c,d;l?.margins&&(a=r,u=l.margins,r={above:Math.max((c=a?.above)!==null&&c!==void 0?c:0,u.above),below:Math.max((d=a?.below)!==null&&d!==void 0?d:0,u.below)})}),new Gt(h,r)}fa(){switch(this.Ll){case"Line":case"Area":case"Baseline":return this._n.crosshairMarkerRadius}return 0}va(){switch(this.Ll){case"Line":case"Area":case"Baseline":{let t=this._n.crosshairMarkerBorderColor;if(t.length!==0)return t}}return null}pa(){switch(this.Ll){case"Line":case"Area":case"Baseline":return this._n.crosshairMarkerBorderWidth}return 0}ma(t){switch(this.Ll){case"Line":case"Area":case"Baseline":{let i=this._n.crosshairMarkerBackgroundColor;if(i.length!==0)return i}}return this.js().Ws(t).oe}Nl(){switch(this._n.priceFormat.type){case"custom":this.da={format:this._n.priceFormat.formatter};break;case"volume":this.da=new Ii(this._n.priceFormat.precision);break;case"percent":this.da=new Zt(this._n.priceFormat.precision);break;default:{let t=Math.pow(10,this._n.priceFormat.precision);this.da=new ot(t,this._n.priceFormat.minMove*t)}}this.qi!==null&&this.qi.Sa()}Yl(){let t=this.$t().St();if(!t.ya()||this.Et.Li())return void(this.zl=[]);let i=v(this.Et.Jh());this.zl=this.Vl.map((s,e)=>{let h=v(t.ka(s.time,!0)),r=h<i?1:-1;return{time:v(this.Et.il(h,r)).ie,position:s.position,shape:s.shape,color:s.color,id:s.id,Gr:e,text:s.text,size:s.size,originalTime:s.originalTime}})}Fl(t){switch(this.un=new as(this,this.$t()),this.Ll){case"Bar":this.mn=new Zi(this,this.$t());break;case"Candlestick":this.mn=new Ki(this,this.$t());break;case"Line":this.mn=new ss(this,this.$t());break;case"Custom":this.mn=new dt(this,this.$t(),z(t));break;case"Area":this.mn=new Ui(this,this.$t());break;case"Baseline":this.mn=new Xi(this,this.$t());break;case"Histogram":this.mn=new is(this,this.$t());break;default:throw Error("Unknown chart style assigned: "+this.Ll)}}ha(t,i){let s=[];return wi(this.Il,t,i,s),s}},Ms=class{constructor(t){this._n=t}Ca(t,i,s){let e=t;if(this._n.mode===0)return e;let h=s.dn(),r=h.Ct();if(r===null)return e;let o=h.Rt(t,r),l=s.Ta().filter(u=>u instanceof kt).reduce((u,c)=>{if(s.ur(c)||!c.kt())return u;let d=c.Dt(),f=c.Bn();if(d.Li()||!f.Yr(i))return u;let m=f.Uh(i);if(m===null)return u;let p=st(c.Ct());return u.concat([d.Rt(m.Ot[3],p.Ot)])},[]);if(l.length===0)return e;l.sort((u,c)=>Math.abs(u-o)-Math.abs(c-o));let a=l[0];return e=h.fn(a,r),e}},xs=class extends T{constructor(){super(...arguments),this.Et=null}J(t){this.Et=t}Z({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(this.Et===null)return;let h=Math.max(1,Math.floor(s));t.lineWidth=h,function(r,o){r.save(),r.lineWidth%2&&r.translate(.5,.5),o(),r.restore()}(t,()=>{let r=v(this.Et);if(r.Pa){t.strokeStyle=r.Ra,Q(t,r.Da),t.beginPath();for(let o of r.Oa){let l=Math.round(o.Aa*s);t.moveTo(l,-h),t.lineTo(l,i.height+h)}t.stroke()}if(r.Ba){t.strokeStyle=r.Va,Q(t,r.za),t.beginPath();for(let o of r.Ea){let l=Math.round(o.Aa*e);t.moveTo(-h,l),t.lineTo(i.width+h,l)}t.stroke()}})}},ys=class{constructor(t){this.Wt=new xs,this.ft=!0,this.Qi=t}bt(){this.ft=!0}gt(){if(this.ft){let t=this.Qi.$t().W().grid,i={Ba:t.horzLines.visible,Pa:t.vertLines.visible,Va:t.horzLines.color,Ra:t.vertLines.color,za:t.horzLines.style,Da:t.vertLines.style,Ea:this.Qi.dn().Ia(),Oa:(this.Qi.$t().St().Ia()||[]).map(s=>({Aa:s.coord}))};this.Wt.J(i),this.ft=!1}return this.Wt}},Es=class{constructor(t){this.mn=new ys(t)}Fh(){return this.mn}},Si={La:4,Na:1e-4};function et(n,t){let i=100*(n-t)/t;return t<0?-i:i}function Gn(n,t){let i=et(n.xh(),t),s=et(n.Sh(),t);return new R(i,s)}function pt(n,t){let i=100*(n-t)/t+100;return t<0?-i:i}function th(n,t){let i=pt(n.xh(),t),s=pt(n.Sh(),t);return new R(i,s)}function ti(n,t){let i=Math.abs(n);if(i<1e-15)return 0;let s=Math.log10(i+t.Na)+t.La;return n<0?-s:s}function vt(n,t){let i=Math.abs(n);if(i<1e-15)return 0;let s=Math.pow(10,i-t.La)-t.Na;return n<0?-s:s}function ct(n,t){if(n===null)return null;let i=ti(n.xh(),t),s=ti(n.Sh(),t);return new R(i,s)}function Pt(n,t){if(n===null)return null;let i=vt(n.xh(),t),s=vt(n.Sh(),t);return new R(i,s)}function Mi(n){if(n===null)return Si;let t=Math.abs(n.Sh()-n.xh());if(t>=1||t<1e-15)return Si;let i=Math.ceil(Math.abs(Math.log10(t))),s=Si.La+i;return{La:s,Na:1/Math.pow(10,s)}}var bt=class{constructor(t,i){if(this.Fa=t,this.Wa=i,function(s){if(s<0)return!1;for(let e=s;e>1;e/=10)if(e%10!=0)return!1;return!0}(this.Fa))this.ja=[2,2.5,2];else{this.ja=[];for(let s=this.Fa;s!==1;){if(s%2==0)this.ja.push(2),s/=2;else{if(s%5!=0)throw new Error("unexpected base");this.ja.push(2,2.5),s/=5}if(this.ja.length>100)throw new Error("something wrong with base")}}}Ha(t,i,s){let e=this.Fa===0?0:1/this.Fa,h=Math.pow(10,Math.max(0,Math.ceil(Math.log10(t-i)))),r=0,o=this.Wa[0];for(;;){let c=Nt(h,e,1e-14)&&h>e+1e-14,d=Nt(h,s*o,1e-14),f=Nt(h,1,1e-14);if(!(c&&d&&f))break;h/=o,o=this.Wa[++r%this.Wa.length]}if(h<=e+1e-14&&(h=e),h=Math.max(1,h),this.ja.length>0&&(l=h,a=1,u=1e-14,Math.abs(l-a)<u))for(r=0,o=this.ja[0];Nt(h,s*o,1e-14)&&h>e+1e-14;)h/=o,o=this.ja[++r%this.ja.length];var l,a,u;return h}},ii=class{constructor(t,i,s,e){this.$a=[],this.Ei=t,this.Fa=i,this.Ua=s,this.qa=e}Ha(t,i){if(t<i)throw new Error("high < low");let s=this.Ei.Vt(),e=(t-i)*this.Ya()/s,h=new bt(this.Fa,[2,2.5,2]),r=new bt(this.Fa,[2,2,2.5]),o=new bt(this.Fa,[2.5,2,2]),l=[];return l.push(h.Ha(t,i,e),r.Ha(t,i,e),o.Ha(t,i,e)),function(a){if(a.length<1)throw Error("array is empty");let u=a[0];for(let c=1;c<a.length;++c)a[c]<u&&(u=a[c]);return u}(l)}Xa(){let t=this.Ei,i=t.Ct();if(i===null)return void(this.$a=[]);let s=t.Vt(),e=this.Ua(s-1,i),h=this.Ua(0,i),r=this.Ei.W().entireTextOnly?this.Ka()/2:0,o=r,l=s-1-r,a=Math.max(e,h),u=Math.min(e,h);if(a===u)return void(this.$a=[]);let c=this.Ha(a,u),d=a%c;d+=d<0?c:0;let f=a>=u?1:-1,m=null,p=0;for(let b=a-d;b>u;b-=c){let g=this.qa(b,i,!0);m!==null&&Math.abs(g-m)<this.Ya()||g<o||g>l||(p<this.$a.length?(this.$a[p].Aa=g,this.$a[p].Za=t.Ga(b)):this.$a.push({Aa:g,Za:t.Ga(b)}),p++,m=g,t.Ja()&&(c=this.Ha(b*f,u)))}this.$a.length=p}Ia(){return this.$a}Ka(){return this.Ei.P()}Ya(){return Math.ceil(2.5*this.Ka())}};function an(n){return n.slice().sort((t,i)=>v(t.Xi())-v(i.Xi()))}var xe;(function(n){n[n.Normal=0]="Normal",n[n.Logarithmic=1]="Logarithmic",n[n.Percentage=2]="Percentage",n[n.IndexedTo100=3]="IndexedTo100"})(xe||(xe={}));var ye=new Zt,Ee=new ot(100,1),Cs=class{constructor(t,i,s,e){this.Qa=0,this.io=null,this.Rh=null,this.no=null,this.so={eo:!1,ro:null},this.ho=0,this.lo=0,this.ao=new x,this.oo=new x,this._o=[],this.uo=null,this.co=null,this.do=null,this.fo=null,this.da=Ee,this.vo=Mi(null),this.po=t,this._n=i,this.mo=s,this.bo=e,this.wo=new ii(this,100,this.Mo.bind(this),this.xo.bind(this))}xa(){return this.po}W(){return this._n}Lh(t){if(N(this._n,t),this.Sa(),t.mode!==void 0&&this.So({Sr:t.mode}),t.scaleMargins!==void 0){let i=z(t.scaleMargins.top),s=z(t.scaleMargins.bottom);if(i<0||i>1)throw new Error(`Invalid top margin - expect value between 0 and 1, given=${i}`);if(s<0||s>1)throw new Error(`Invalid bottom margin - expect value between 0 and 1, given=${s}`);if(i+s>1)throw new Error(`Invalid margins - sum of margins must be less than 1, given=${i+s}`);this.yo(),this.co=null}}ko(){return this._n.autoScale}Ja(){return this._n.mode===1}fh(){return this._n.mode===2}Co(){return this._n.mode===3}Sr(){return{Nn:this._n.autoScale,To:this._n.invertScale,Sr:this._n.mode}}So(t){let i=this.Sr(),s=null;t.Nn!==void 0&&(this._n.autoScale=t.Nn),t.Sr!==void 0&&(this._n.mode=t.Sr,t.Sr!==2&&t.Sr!==3||(this._n.autoScale=!0),this.so.eo=!1),i.Sr===1&&t.Sr!==i.Sr&&(function(h,r){if(h===null)return!1;let o=vt(h.xh(),r),l=vt(h.Sh(),r);return isFinite(o)&&isFinite(l)}(this.Rh,this.vo)?(s=Pt(this.Rh,this.vo),s!==null&&this.Po(s)):this._n.autoScale=!0),t.Sr===1&&t.Sr!==i.Sr&&(s=ct(this.Rh,this.vo),s!==null&&this.Po(s));let e=i.Sr!==this._n.mode;e&&(i.Sr===2||this.fh())&&this.Sa(),e&&(i.Sr===3||this.Co())&&this.Sa(),t.To!==void 0&&i.To!==t.To&&(this._n.invertScale=t.To,this.Ro()),this.oo.m(i,this.Sr())}Do(){return this.oo}P(){return this.mo.fontSize}Vt(){return this.Qa}Oo(t){this.Qa!==t&&(this.Qa=t,this.yo(),this.co=null)}Ao(){if(this.io)return this.io;let t=this.Vt()-this.Bo()-this.Vo();return this.io=t,t}Oh(){return this.zo(),this.Rh}Po(t,i){let s=this.Rh;(i||s===null&&t!==null||s!==null&&!s.gh(t))&&(this.co=null,this.Rh=t)}Li(){return this.zo(),this.Qa===0||!this.Rh||this.Rh.Li()}Eo(t){return this.To()?t:this.Vt()-1-t}Rt(t,i){return this.fh()?t=et(t,i):this.Co()&&(t=pt(t,i)),this.xo(t,i)}Gs(t,i,s){this.zo();let e=this.Vo(),h=v(this.Oh()),r=h.xh(),o=h.Sh(),l=this.Ao()-1,a=this.To(),u=l/(o-r),c=s===void 0?0:s.from,d=s===void 0?t.length:s.to,f=this.Io();for(let m=c;m<d;m++){let p=t[m],b=p._t;if(isNaN(b))continue;let g=b;f!==null&&(g=f(p._t,i));let w=e+u*(g-r),M=a?w:this.Qa-1-w;p.st=M}}ve(t,i,s){this.zo();let e=this.Vo(),h=v(this.Oh()),r=h.xh(),o=h.Sh(),l=this.Ao()-1,a=this.To(),u=l/(o-r),c=s===void 0?0:s.from,d=s===void 0?t.length:s.to,f=this.Io();for(let m=c;m<d;m++){let p=t[m],b=p.me,g=p.be,w=p.we,M=p.ge;f!==null&&(b=f(p.me,i),g=f(p.be,i),w=f(p.we,i),M=f(p.ge,i));let _=e+u*(b-r),y=a?_:this.Qa-1-_;p.de=y,_=e+u*(g-r),y=a?_:this.Qa-1-_,p._e=y,_=e+u*(w-r),y=a?_:this.Qa-1-_,p.ue=y,_=e+u*(M-r),y=a?_:this.Qa-1-_,p.fe=y}}fn(t,i){let s=this.Mo(t,i);return this.Lo(s,i)}Lo(t,i){let s=t;return this.fh()?s=function(e,h){return h<0&&(e=-e),e/100*h+h}(s,i):this.Co()&&(s=function(e,h){return e-=100,h<0&&(e=-e),e/100*h+h}(s,i)),s}Ta(){return this._o}No(){if(this.uo)return this.uo;let t=[];for(let i=0;i<this._o.length;i++){let s=this._o[i];s.Xi()===null&&s.Ki(i+1),t.push(s)}return t=an(t),this.uo=t,this.uo}Fo(t){this._o.indexOf(t)===-1&&(this._o.push(t),this.Sa(),this.Wo())}jo(t){let i=this._o.indexOf(t);if(i===-1)throw new Error("source is not attached to scale");this._o.splice(i,1),this._o.length===0&&(this.So({Nn:!0}),this.Po(null)),this.Sa(),this.Wo()}Ct(){let t=null;for(let i of this._o){let s=i.Ct();s!==null&&(t===null||s.ia<t.ia)&&(t=s)}return t===null?null:t.Ot}To(){return this._n.invertScale}Ia(){let t=this.Ct()===null;if(this.co!==null&&(t||this.co.Ho===t))return this.co.Ia;this.wo.Xa();let i=this.wo.Ia();return this.co={Ia:i,Ho:t},this.ao.m(),i}$o(){return this.ao}Uo(t){this.fh()||this.Co()||this.do===null&&this.no===null&&(this.Li()||(this.do=this.Qa-t,this.no=v(this.Oh()).Mh()))}qo(t){if(this.fh()||this.Co()||this.do===null)return;this.So({Nn:!1}),(t=this.Qa-t)<0&&(t=0);let i=(this.do+.2*(this.Qa-1))/(t+.2*(this.Qa-1)),s=v(this.no).Mh();i=Math.max(i,.1),s.kh(i),this.Po(s)}Yo(){this.fh()||this.Co()||(this.do=null,this.no=null)}Xo(t){this.ko()||this.fo===null&&this.no===null&&(this.Li()||(this.fo=t,this.no=v(this.Oh()).Mh()))}Ko(t){if(this.ko()||this.fo===null)return;let i=v(this.Oh()).yh()/(this.Ao()-1),s=t-this.fo;this.To()&&(s*=-1);let e=s*i,h=v(this.no).Mh();h.Ch(e),this.Po(h,!0),this.co=null}Zo(){this.ko()||this.fo!==null&&(this.fo=null,this.no=null)}ca(){return this.da||this.Sa(),this.da}Ni(t,i){switch(this._n.mode){case 2:return this.Go(et(t,i));case 3:return this.ca().format(pt(t,i));default:return this.zh(t)}}Ga(t){switch(this._n.mode){case 2:return this.Go(t);case 3:return this.ca().format(t);default:return this.zh(t)}}Wl(t){return this.zh(t,v(this.Jo()).ca())}jl(t,i){return t=et(t,i),this.Go(t,ye)}Qo(){return this._o}t_(t){this.so={ro:t,eo:!1}}Rn(){this._o.forEach(t=>t.Rn())}Sa(){this.co=null;let t=this.Jo(),i=100;t!==null&&(i=Math.round(1/t.ua())),this.da=Ee,this.fh()?(this.da=ye,i=100):this.Co()?(this.da=new ot(100,1),i=100):t!==null&&(this.da=t.ca()),this.wo=new ii(this,i,this.Mo.bind(this),this.xo.bind(this)),this.wo.Xa()}Wo(){this.uo=null}Jo(){return this._o[0]||null}Bo(){return this.To()?this._n.scaleMargins.bottom*this.Vt()+this.lo:this._n.scaleMargins.top*this.Vt()+this.ho}Vo(){return this.To()?this._n.scaleMargins.top*this.Vt()+this.ho:this._n.scaleMargins.bottom*this.Vt()+this.lo}zo(){this.so.eo||(this.so.eo=!0,this.i_())}yo(){this.io=null}xo(t,i){if(this.zo(),this.Li())return 0;t=this.Ja()&&t?ti(t,this.vo):t;let s=v(this.Oh()),e=this.Vo()+(this.Ao()-1)*(t-s.xh())/s.yh();return this.Eo(e)}Mo(t,i){if(this.zo(),this.Li())return 0;let s=this.Eo(t),e=v(this.Oh()),h=e.xh()+e.yh()*((s-this.Vo())/(this.Ao()-1));return this.Ja()?vt(h,this.vo):h}Ro(){this.co=null,this.wo.Xa()}i_(){let t=this.so.ro;if(t===null)return;let i=null,s=this.Qo(),e=0,h=0;for(let l of s){if(!l.kt())continue;let a=l.Ct();if(a===null)continue;let u=l.Rl(t.Rs(),t.ui()),c=u&&u.Oh();if(c!==null){switch(this._n.mode){case 1:c=ct(c,this.vo);break;case 2:c=Gn(c,a.Ot);break;case 3:c=th(c,a.Ot)}if(i=i===null?c:i.Jn(v(c)),u!==null){let d=u.Ah();d!==null&&(e=Math.max(e,d.above),h=Math.max(e,d.below))}}}if(e===this.ho&&h===this.lo||(this.ho=e,this.lo=h,this.co=null,this.yo()),i!==null){if(i.xh()===i.Sh()){let l=this.Jo(),a=5*(l===null||this.fh()||this.Co()?1:l.ua());this.Ja()&&(i=Pt(i,this.vo)),i=new R(i.xh()-a,i.Sh()+a),this.Ja()&&(i=ct(i,this.vo))}if(this.Ja()){let l=Pt(i,this.vo),a=Mi(l);if(r=a,o=this.vo,r.La!==o.La||r.Na!==o.Na){let u=this.no!==null?Pt(this.no,this.vo):null;this.vo=a,i=ct(l,a),u!==null&&(this.no=ct(u,a))}}this.Po(i)}else this.Rh===null&&(this.Po(new R(-.5,.5)),this.vo=Mi(null));var r,o;this.so.eo=!0}Io(){return this.fh()?et:this.Co()?pt:this.Ja()?t=>ti(t,this.vo):null}n_(t,i,s){return i===void 0?(s===void 0&&(s=this.ca()),s.format(t)):i(t)}zh(t,i){return this.n_(t,this.bo.priceFormatter,i)}Go(t,i){return this.n_(t,this.bo.percentageFormatter,i)}},ks=class{constructor(t,i){this._o=[],this.s_=new Map,this.Qa=0,this.e_=0,this.r_=1e3,this.uo=null,this.h_=new x,this.wl=t,this.Hi=i,this.l_=new Es(this);let s=i.W();this.a_=this.o_("left",s.leftPriceScale),this.__=this.o_("right",s.rightPriceScale),this.a_.Do().l(this.u_.bind(this,this.a_),this),this.__.Do().l(this.u_.bind(this,this.__),this),this.c_(s)}c_(t){if(t.leftPriceScale&&this.a_.Lh(t.leftPriceScale),t.rightPriceScale&&this.__.Lh(t.rightPriceScale),t.localization&&(this.a_.Sa(),this.__.Sa()),t.overlayPriceScales){let i=Array.from(this.s_.values());for(let s of i){let e=v(s[0].Dt());e.Lh(t.overlayPriceScales),t.localization&&e.Sa()}}}d_(t){switch(t){case"left":return this.a_;case"right":return this.__}return this.s_.has(t)?z(this.s_.get(t))[0].Dt():null}S(){this.$t().f_().p(this),this.a_.Do().p(this),this.__.Do().p(this),this._o.forEach(t=>{t.S&&t.S()}),this.h_.m()}v_(){return this.r_}p_(t){this.r_=t}$t(){return this.Hi}ji(){return this.e_}Vt(){return this.Qa}m_(t){this.e_=t,this.b_()}Oo(t){this.Qa=t,this.a_.Oo(t),this.__.Oo(t),this._o.forEach(i=>{if(this.ur(i)){let s=i.Dt();s!==null&&s.Oo(t)}}),this.b_()}Ta(){return this._o}ur(t){let i=t.Dt();return i===null||this.a_!==i&&this.__!==i}Fo(t,i,s){let e=s!==void 0?s:this.g_().w_+1;this.M_(t,i,e)}jo(t){let i=this._o.indexOf(t);I(i!==-1,"removeDataSource: invalid data source"),this._o.splice(i,1);let s=v(t.Dt()).xa();if(this.s_.has(s)){let h=z(this.s_.get(s)),r=h.indexOf(t);r!==-1&&(h.splice(r,1),h.length===0&&this.s_.delete(s))}let e=t.Dt();e&&e.Ta().indexOf(t)>=0&&e.jo(t),e!==null&&(e.Wo(),this.x_(e)),this.uo=null}dr(t){return t===this.a_?"left":t===this.__?"right":"overlay"}S_(){return this.a_}y_(){return this.__}k_(t,i){t.Uo(i)}C_(t,i){t.qo(i),this.b_()}T_(t){t.Yo()}P_(t,i){t.Xo(i)}R_(t,i){t.Ko(i),this.b_()}D_(t){t.Zo()}b_(){this._o.forEach(t=>{t.Rn()})}dn(){let t=null;return this.Hi.W().rightPriceScale.visible&&this.__.Ta().length!==0?t=this.__:this.Hi.W().leftPriceScale.visible&&this.a_.Ta().length!==0?t=this.a_:this._o.length!==0&&(t=this._o[0].Dt()),t===null&&(t=this.__),t}cr(){let t=null;return this.Hi.W().rightPriceScale.visible?t=this.__:this.Hi.W().leftPriceScale.visible&&(t=this.a_),t}x_(t){t!==null&&t.ko()&&this.O_(t)}A_(t){let i=this.wl.qs();t.So({Nn:!0}),i!==null&&t.t_(i),this.b_()}B_(){this.O_(this.a_),this.O_(this.__)}V_(){this.x_(this.a_),this.x_(this.__),this._o.forEach(t=>{this.ur(t)&&this.x_(t.Dt())}),this.b_(),this.Hi.Nh()}No(){return this.uo===null&&(this.uo=an(this._o)),this.uo}z_(){return this.h_}E_(){return this.l_}O_(t){let i=t.Qo();if(i&&i.length>0&&!this.wl.Li()){let s=this.wl.qs();s!==null&&t.t_(s)}t.Rn()}g_(){let t=this.No();if(t.length===0)return{I_:0,w_:0};let i=0,s=0;for(let e=0;e<t.length;e++){let h=t[e].Xi();h!==null&&(h<i&&(i=h),h>s&&(s=h))}return{I_:i,w_:s}}M_(t,i,s){let e=this.d_(i);if(e===null&&(e=this.o_(i,this.Hi.W().overlayPriceScales)),this._o.push(t),!ai(i)){let h=this.s_.get(i)||[];h.push(t),this.s_.set(i,h)}e.Fo(t),t.Zi(e),t.Ki(s),this.x_(e),this.uo=null}u_(t,i,s){i.Sr!==s.Sr&&this.O_(t)}o_(t,i){let s=Object.assign({visible:!0,autoScale:!0},W(i)),e=new Cs(t,s,this.Hi.W().layout,this.Hi.W().localization);return e.Oo(this.Vt()),e}},zs=class{constructor(t,i,s=50){this.Ye=0,this.Xe=1,this.Ke=1,this.Ge=new Map,this.Ze=new Map,this.L_=t,this.N_=i,this.Je=s}F_(t){let i=t.time,s=this.N_.cacheKey(i),e=this.Ge.get(s);if(e!==void 0)return e.W_;if(this.Ye===this.Je){let r=this.Ze.get(this.Ke);this.Ze.delete(this.Ke),this.Ge.delete(z(r)),this.Ke++,this.Ye--}let h=this.L_(t);return this.Ge.set(s,{W_:h,nr:this.Xe}),this.Ze.set(this.Xe,s),this.Ye++,this.Xe++,h}},q=class{constructor(t,i){I(t<=i,"right should be >= left"),this.j_=t,this.H_=i}Rs(){return this.j_}ui(){return this.H_}U_(){return this.H_-this.j_+1}Yr(t){return this.j_<=t&&t<=this.H_}gh(t){return this.j_===t.Rs()&&this.H_===t.ui()}};function Ce(n,t){return n===null||t===null?n===t:n.gh(t)}var Ts=class{constructor(){this.q_=new Map,this.Ge=null,this.Y_=!1}X_(t){this.Y_=t,this.Ge=null}K_(t,i){this.Z_(i),this.Ge=null;for(let s=i;s<t.length;++s){let e=t[s],h=this.q_.get(e.timeWeight);h===void 0&&(h=[],this.q_.set(e.timeWeight,h)),h.push({index:s,time:e.time,weight:e.timeWeight,originalTime:e.originalTime})}}G_(t,i){let s=Math.ceil(i/t);return this.Ge!==null&&this.Ge.J_===s||(this.Ge={Ia:this.Q_(s),J_:s}),this.Ge.Ia}Z_(t){if(t===0)return void this.q_.clear();let i=[];this.q_.forEach((s,e)=>{t<=s[0].index?i.push(e):s.splice(Ot(s,t,h=>h.index<t),1/0)});for(let s of i)this.q_.delete(s)}Q_(t){let i=[];for(let s of Array.from(this.q_.keys()).sort((e,h)=>h-e)){if(!this.q_.get(s))continue;let e=i;i=[];let h=e.length,r=0,o=z(this.q_.get(s)),l=o.length,a=1/0,u=-1/0;for(let c=0;c<l;c++){let d=o[c],f=d.index;for(;r<h;){let m=e[r],p=m.index;if(!(p<f)){a=p;break}r++,i.push(m),u=p,a=1/0}if(a-f>=t&&f-u>=t)i.push(d),u=f;else if(this.Y_)return e}for(;r<h;r++)i.push(e[r])}return i}},nt=class n{constructor(t){this.tu=t}iu(){return this.tu===null?null:new q(Math.floor(this.tu.Rs()),Math.ceil(this.tu.ui()))}nu(){return this.tu}static su(){return new n(null)}};function ih(n,t){return n.weight>t.weight?n:t}var Os=class{constructor(t,i,s,e){this.e_=0,this.eu=null,this.ru=[],this.fo=null,this.do=null,this.hu=new Ts,this.lu=new Map,this.au=nt.su(),this.ou=!0,this._u=new x,this.uu=new x,this.cu=new x,this.du=null,this.fu=null,this.vu=[],this._n=i,this.bo=s,this.pu=i.rightOffset,this.mu=i.barSpacing,this.Hi=t,this.N_=e,this.bu(),this.hu.X_(i.uniformDistribution)}W(){return this._n}wu(t){N(this.bo,t),this.gu(),this.bu()}Lh(t,i){var s;N(this._n,t),this._n.fixLeftEdge&&this.Mu(),this._n.fixRightEdge&&this.xu(),t.barSpacing!==void 0&&this.Hi.Kn(t.barSpacing),t.rightOffset!==void 0&&this.Hi.Zn(t.rightOffset),t.minBarSpacing!==void 0&&this.Hi.Kn((s=t.barSpacing)!==null&&s!==void 0?s:this.mu),this.gu(),this.bu(),this.cu.m()}vn(t){var i,s;return(s=(i=this.ru[t])===null||i===void 0?void 0:i.time)!==null&&s!==void 0?s:null}$i(t){var i;return(i=this.ru[t])!==null&&i!==void 0?i:null}ka(t,i){if(this.ru.length<1)return null;if(this.N_.key(t)>this.N_.key(this.ru[this.ru.length-1].time))return i?this.ru.length-1:null;let s=Ot(this.ru,this.N_.key(t),(e,h)=>this.N_.key(e.time)<h);return this.N_.key(t)<this.N_.key(this.ru[s].time)?i?s:null:s}Li(){return this.e_===0||this.ru.length===0||this.eu===null}ya(){return this.ru.length>0}qs(){return this.Su(),this.au.iu()}yu(){return this.Su(),this.au.nu()}ku(){let t=this.qs();if(t===null)return null;let i={from:t.Rs(),to:t.ui()};return this.Cu(i)}Cu(t){let i=Math.round(t.from),s=Math.round(t.to),e=v(this.Tu()),h=v(this.Pu());return{from:v(this.$i(Math.max(e,i))),to:v(this.$i(Math.min(h,s)))}}Ru(t){return{from:v(this.ka(t.from,!0)),to:v(this.ka(t.to,!0))}}ji(){return this.e_}m_(t){if(!isFinite(t)||t<=0||this.e_===t)return;let i=this.yu(),s=this.e_;if(this.e_=t,this.ou=!0,this._n.lockVisibleTimeRangeOnResize&&s!==0){let e=this.mu*t/s;this.mu=e}if(this._n.fixLeftEdge&&i!==null&&i.Rs()<=0){let e=s-t;this.pu-=Math.round(e/this.mu)+1,this.ou=!0}this.Du(),this.Ou()}zt(t){if(this.Li()||!wt(t))return 0;let i=this.Au()+this.pu-t;return this.e_-(i+.5)*this.mu-1}Zs(t,i){let s=this.Au(),e=i===void 0?0:i.from,h=i===void 0?t.length:i.to;for(let r=e;r<h;r++){let o=t[r].ot,l=s+this.pu-o,a=this.e_-(l+.5)*this.mu-1;t[r].nt=a}}Bu(t){return Math.ceil(this.Vu(t))}Zn(t){this.ou=!0,this.pu=t,this.Ou(),this.Hi.zu(),this.Hi.Nh()}ee(){return this.mu}Kn(t){this.Eu(t),this.Ou(),this.Hi.zu(),this.Hi.Nh()}Iu(){return this.pu}Ia(){if(this.Li())return null;if(this.fu!==null)return this.fu;let t=this.mu,i=5*(this.Hi.W().layout.fontSize+4)/8*(this._n.tickMarkMaxCharacterLength||8),s=Math.round(i/t),e=v(this.qs()),h=Math.max(e.Rs(),e.Rs()-s),r=Math.max(e.ui(),e.ui()-s),o=this.hu.G_(t,i),l=this.Tu()+s,a=this.Pu()-s,u=this.Lu(),c=this._n.fixLeftEdge||u,d=this._n.fixRightEdge||u,f=0;for(let m of o){if(!(h<=m.index&&m.index<=r))continue;let p;f<this.vu.length?(p=this.vu[f],p.coord=this.zt(m.index),p.label=this.Nu(m),p.weight=m.weight):(p={needAlignCoordinate:!1,coord:this.zt(m.index),label:this.Nu(m),weight:m.weight},this.vu.push(p)),this.mu>i/2&&!u?p.needAlignCoordinate=!1:p.needAlignCoordinate=c&&m.index<=l||d&&m.index>=a,f++}return this.vu.length=f,this.fu=this.vu,this.vu}Fu(){this.ou=!0,this.Kn(this._n.barSpacing),this.Zn(this._n.rightOffset)}Wu(t){this.ou=!0,this.eu=t,this.Ou(),this.Mu()}ju(t,i){let s=this.Vu(t),e=this.ee(),h=e+i*(e/10);this.Kn(h),this._n.rightBarStaysOnScroll||this.Zn(this.Iu()+(s-this.Vu(t)))}Uo(t){this.fo&&this.Zo(),this.do===null&&this.du===null&&(this.Li()||(this.do=t,this.Hu()))}qo(t){if(this.du===null)return;let i=Fi(this.e_-t,0,this.e_),s=Fi(this.e_-v(this.do),0,this.e_);i!==0&&s!==0&&this.Kn(this.du.ee*i/s)}Yo(){this.do!==null&&(this.do=null,this.$u())}Xo(t){this.fo===null&&this.du===null&&(this.Li()||(this.fo=t,this.Hu()))}Ko(t){if(this.fo===null)return;let i=(this.fo-t)/this.ee();this.pu=v(this.du).Iu+i,this.ou=!0,this.Ou()}Zo(){this.fo!==null&&(this.fo=null,this.$u())}Uu(){this.qu(this._n.rightOffset)}qu(t,i=400){if(!isFinite(t))throw new RangeError("offset is required and must be finite number");if(!isFinite(i)||i<=0)throw new RangeError("animationDuration (optional) must be finite positive number");let s=this.pu,e=performance.now();this.Hi.qn({Yu:h=>(h-e)/i>=1,Xu:h=>{let r=(h-e)/i;return r>=1?t:s+(t-s)*r}})}bt(t,i){this.ou=!0,this.ru=t,this.hu.K_(t,i),this.Ou()}Ku(){return this._u}Zu(){return this.uu}Gu(){return this.cu}Au(){return this.eu||0}Ju(t){let i=t.U_();this.Eu(this.e_/i),this.pu=t.ui()-this.Au(),this.Ou(),this.ou=!0,this.Hi.zu(),this.Hi.Nh()}Qu(){let t=this.Tu(),i=this.Pu();t!==null&&i!==null&&this.Ju(new q(t,i+this._n.rightOffset))}tc(t){let i=new q(t.from,t.to);this.Ju(i)}Ui(t){return this.bo.timeFormatter!==void 0?this.bo.timeFormatter(t.originalTime):this.N_.formatHorzItem(t.time)}Lu(){let{handleScroll:t,handleScale:i}=this.Hi.W();return!(t.horzTouchDrag||t.mouseWheel||t.pressedMouseMove||t.vertTouchDrag||i.axisDoubleClickReset.time||i.axisPressedMouseMove.time||i.mouseWheel||i.pinch)}Tu(){return this.ru.length===0?null:0}Pu(){return this.ru.length===0?null:this.ru.length-1}ic(t){return(this.e_-1-t)/this.mu}Vu(t){let i=this.ic(t),s=this.Au()+this.pu-i;return Math.round(1e6*s)/1e6}Eu(t){let i=this.mu;this.mu=t,this.Du(),i!==this.mu&&(this.ou=!0,this.nc())}Su(){if(!this.ou)return;if(this.ou=!1,this.Li())return void this.sc(nt.su());let t=this.Au(),i=this.e_/this.mu,s=this.pu+t,e=new q(s-i+1,s);this.sc(new nt(e))}Du(){let t=this.ec();if(this.mu<t&&(this.mu=t,this.ou=!0),this.e_!==0){let i=.5*this.e_;this.mu>i&&(this.mu=i,this.ou=!0)}}ec(){return this._n.fixLeftEdge&&this._n.fixRightEdge&&this.ru.length!==0?this.e_/this.ru.length:this._n.minBarSpacing}Ou(){let t=this.rc();this.pu>t&&(this.pu=t,this.ou=!0);let i=this.hc();i!==null&&this.pu<i&&(this.pu=i,this.ou=!0)}hc(){let t=this.Tu(),i=this.eu;return t===null||i===null?null:t-i-1+(this._n.fixLeftEdge?this.e_/this.mu:Math.min(2,this.ru.length))}rc(){return this._n.fixRightEdge?0:this.e_/this.mu-Math.min(2,this.ru.length)}Hu(){this.du={ee:this.ee(),Iu:this.Iu()}}$u(){this.du=null}Nu(t){let i=this.lu.get(t.weight);return i===void 0&&(i=new zs(s=>this.lc(s),this.N_),this.lu.set(t.weight,i)),i.F_(t)}lc(t){return this.N_.formatTickmark(t,this.bo)}sc(t){let i=this.au;this.au=t,Ce(i.iu(),this.au.iu())||this._u.m(),Ce(i.nu(),this.au.nu())||this.uu.m(),this.nc()}nc(){this.fu=null}gu(){this.nc(),this.lu.clear()}bu(){this.N_.updateFormatter(this.bo)}Mu(){if(!this._n.fixLeftEdge)return;let t=this.Tu();if(t===null)return;let i=this.qs();if(i===null)return;let s=i.Rs()-t;if(s<0){let e=this.pu-s-1;this.Zn(e)}this.Du()}xu(){this.Ou(),this.Du()}},Ls=class{K(t,i,s){t.useMediaCoordinateSpace(e=>this.Z(e,i,s))}fl(t,i,s){t.useMediaCoordinateSpace(e=>this.ac(e,i,s))}ac(t,i,s){}},Rs=class extends Ls{constructor(t){super(),this.oc=new Map,this.Et=t}Z(t){}ac(t){if(!this.Et.kt)return;let{context:i,mediaSize:s}=t,e=0;for(let r of this.Et._c){if(r.Zt.length===0)continue;i.font=r.R;let o=this.uc(i,r.Zt);o>s.width?r.ju=s.width/o:r.ju=1,e+=r.cc*r.ju}let h=0;switch(this.Et.dc){case"top":h=0;break;case"center":h=Math.max((s.height-e)/2,0);break;case"bottom":h=Math.max(s.height-e,0)}i.fillStyle=this.Et.O;for(let r of this.Et._c){i.save();let o=0;switch(this.Et.fc){case"left":i.textAlign="left",o=r.cc/2;break;case"center":i.textAlign="center",o=s.width/2;break;case"right":i.textAlign="right",o=s.width-1-r.cc/2}i.translate(o,h),i.textBaseline="top",i.font=r.R,i.scale(r.ju,r.ju),i.fillText(r.Zt,0,r.vc),i.restore(),h+=r.cc*r.ju}}uc(t,i){let s=this.mc(t.font),e=s.get(i);return e===void 0&&(e=t.measureText(i).width,s.set(i,e)),e}mc(t){let i=this.oc.get(t);return i===void 0&&(i=new Map,this.oc.set(t,i)),i}},Ns=class{constructor(t){this.ft=!0,this.Ft={kt:!1,O:"",_c:[],dc:"center",fc:"center"},this.Wt=new Rs(this.Ft),this.jt=t}bt(){this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.Wt}Mt(){let t=this.jt.W(),i=this.Ft;i.kt=t.visible,i.kt&&(i.O=t.color,i.fc=t.horzAlign,i.dc=t.vertAlign,i._c=[{Zt:t.text,R:ht(t.fontSize,t.fontFamily,t.fontStyle),cc:1.2*t.fontSize,vc:0,ju:0}])}},Bs=class extends Mt{constructor(t,i){super(),this._n=i,this.mn=new Ns(this)}Tn(){return[]}Cn(){return[this.mn]}W(){return this._n}Rn(){this.mn.bt()}},ke,ze,Te,si,Oe;(function(n){n[n.OnTouchEnd=0]="OnTouchEnd",n[n.OnNextTap=1]="OnNextTap"})(ke||(ke={}));var Ps=class{constructor(t,i,s){this.bc=[],this.wc=[],this.e_=0,this.gc=null,this.Mc=new x,this.xc=new x,this.Sc=null,this.yc=t,this._n=i,this.N_=s,this.kc=new Bi(this),this.wl=new Os(this,i.timeScale,this._n.localization,s),this.vt=new $i(this,i.crosshair),this.Cc=new Ms(i.crosshair),this.Tc=new Bs(this,i.watermark),this.Pc(),this.bc[0].p_(2e3),this.Rc=this.Dc(0),this.Oc=this.Dc(1)}$l(){this.Ac(C.ns())}Nh(){this.Ac(C.ts())}sa(){this.Ac(new C(1))}Ul(t){let i=this.Bc(t);this.Ac(i)}Vc(){return this.gc}zc(t){let i=this.gc;this.gc=t,i!==null&&this.Ul(i.Ec),t!==null&&this.Ul(t.Ec)}W(){return this._n}Lh(t){N(this._n,t),this.bc.forEach(i=>i.c_(t)),t.timeScale!==void 0&&this.wl.Lh(t.timeScale),t.localization!==void 0&&this.wl.wu(t.localization),(t.leftPriceScale||t.rightPriceScale)&&this.Mc.m(),this.Rc=this.Dc(0),this.Oc=this.Dc(1),this.$l()}Ic(t,i){if(t==="left")return void this.Lh({leftPriceScale:i});if(t==="right")return void this.Lh({rightPriceScale:i});let s=this.Lc(t);s!==null&&(s.Dt.Lh(i),this.Mc.m())}Lc(t){for(let i of this.bc){let s=i.d_(t);if(s!==null)return{Ht:i,Dt:s}}return null}St(){return this.wl}Nc(){return this.bc}Fc(){return this.Tc}Wc(){return this.vt}jc(){return this.xc}Hc(t,i){t.Oo(i),this.zu()}m_(t){this.e_=t,this.wl.m_(this.e_),this.bc.forEach(i=>i.m_(t)),this.zu()}Pc(t){let i=new ks(this.wl,this);t!==void 0?this.bc.splice(t,0,i):this.bc.push(i);let s=t===void 0?this.bc.length-1:t,e=C.ns();return e.In(s,{Ln:0,Nn:!0}),this.Ac(e),i}k_(t,i,s){t.k_(i,s)}C_(t,i,s){t.C_(i,s),this.ql(),this.Ac(this.$c(t,2))}T_(t,i){t.T_(i),this.Ac(this.$c(t,2))}P_(t,i,s){i.ko()||t.P_(i,s)}R_(t,i,s){i.ko()||(t.R_(i,s),this.ql(),this.Ac(this.$c(t,2)))}D_(t,i){i.ko()||(t.D_(i),this.Ac(this.$c(t,2)))}A_(t,i){t.A_(i),this.Ac(this.$c(t,2))}Uc(t){this.wl.Uo(t)}qc(t,i){let s=this.St();if(s.Li()||i===0)return;let e=s.ji();t=Math.max(1,Math.min(t,e)),s.ju(t,i),this.zu()}Yc(t){this.Xc(0),this.Kc(t),this.Zc()}Gc(t){this.wl.qo(t),this.zu()}Jc(){this.wl.Yo(),this.Nh()}Xc(t){this.wl.Xo(t)}Kc(t){this.wl.Ko(t),this.zu()}Zc(){this.wl.Zo(),this.Nh()}wt(){return this.wc}Qc(t,i,s,e,h){this.vt.bn(t,i);let r=NaN,o=this.wl.Bu(t),l=this.wl.qs();l!==null&&(o=Math.min(Math.max(l.Rs(),o),l.ui()));let a=e.dn(),u=a.Ct();u!==null&&(r=a.fn(i,u)),r=this.Cc.Ca(r,o,e),this.vt.xn(o,r,e),this.sa(),h||this.xc.m(this.vt.xt(),{x:t,y:i},s)}td(t,i,s){let e=s.dn(),h=e.Ct(),r=e.Rt(t,v(h)),o=this.wl.ka(i,!0),l=this.wl.zt(v(o));this.Qc(l,r,null,s,!0)}nd(t){this.Wc().yn(),this.sa(),t||this.xc.m(null,null,null)}ql(){let t=this.vt.Ht();if(t!==null){let i=this.vt.gn(),s=this.vt.Mn();this.Qc(i,s,null,t)}this.vt.Rn()}sd(t,i,s){let e=this.wl.vn(0);i!==void 0&&s!==void 0&&this.wl.bt(i,s);let h=this.wl.vn(0),r=this.wl.Au(),o=this.wl.qs();if(o!==null&&e!==null&&h!==null){let l=o.Yr(r),a=this.N_.key(e)>this.N_.key(h),u=t!==null&&t>r&&!a,c=this.wl.W().allowShiftVisibleRangeOnWhitespaceReplacement,d=l&&(s!==void 0||c)&&this.wl.W().shiftVisibleRangeOnNewBar;if(u&&!d){let f=t-r;this.wl.Zn(this.wl.Iu()-f)}}this.wl.Wu(t)}Kl(t){t!==null&&t.V_()}_r(t){let i=this.bc.find(s=>s.No().includes(t));return i===void 0?null:i}zu(){this.Tc.Rn(),this.bc.forEach(t=>t.V_()),this.ql()}S(){this.bc.forEach(t=>t.S()),this.bc.length=0,this._n.localization.priceFormatter=void 0,this._n.localization.percentageFormatter=void 0,this._n.localization.timeFormatter=void 0}ed(){return this.kc}vr(){return this.kc.W()}f_(){return this.Mc}rd(t,i,s){let e=this.bc[0],h=this.hd(i,t,e,s);return this.wc.push(h),this.wc.length===1?this.$l():this.Nh(),h}ld(t){let i=this._r(t),s=this.wc.indexOf(t);I(s!==-1,"Series not found"),this.wc.splice(s,1),v(i).jo(t),t.S&&t.S()}Hl(t,i){let s=v(this._r(t));s.jo(t);let e=this.Lc(i);if(e===null){let h=t.Xi();s.Fo(t,i,h)}else{let h=e.Ht===s?t.Xi():void 0;e.Ht.Fo(t,i,h)}}Qu(){let t=C.ts();t.jn(),this.Ac(t)}ad(t){let i=C.ts();i.Un(t),this.Ac(i)}Xn(){let t=C.ts();t.Xn(),this.Ac(t)}Kn(t){let i=C.ts();i.Kn(t),this.Ac(i)}Zn(t){let i=C.ts();i.Zn(t),this.Ac(i)}qn(t){let i=C.ts();i.qn(t),this.Ac(i)}Hn(){let t=C.ts();t.Hn(),this.Ac(t)}od(){return this._n.rightPriceScale.visible?"right":"left"}_d(){return this.Oc}q(){return this.Rc}Bt(t){let i=this.Oc,s=this.Rc;if(i===s)return i;if(t=Math.max(0,Math.min(100,Math.round(100*t))),this.Sc===null||this.Sc.ks!==s||this.Sc.Cs!==i)this.Sc={ks:s,Cs:i,ud:new Map};else{let h=this.Sc.ud.get(t);if(h!==void 0)return h}let e=function(h,r,o){let[l,a,u,c]=jt(h),[d,f,m,p]=jt(r),b=[L(l+o*(d-l)),L(a+o*(f-a)),L(u+o*(m-u)),Ke(c+o*(p-c))];return`rgba(${b[0]}, ${b[1]}, ${b[2]}, ${b[3]})`}(s,i,t/100);return this.Sc.ud.set(t,e),e}$c(t,i){let s=new C(i);if(t!==null){let e=this.bc.indexOf(t);s.In(e,{Ln:i})}return s}Bc(t,i){return i===void 0&&(i=2),this.$c(this._r(t),i)}Ac(t){this.yc&&this.yc(t),this.bc.forEach(i=>i.E_().Fh().bt())}hd(t,i,s,e){let h=new kt(this,t,i,s,e),r=t.priceScaleId!==void 0?t.priceScaleId:this.od();return s.Fo(h,r),ai(r)||h.Lh(t),h}Dc(t){let i=this._n.layout;return i.background.type==="gradient"?t===0?i.background.topColor:i.background.bottomColor:i.background.color}};function Ds(n){return!P(n)&&!Tt(n)}function un(n){return P(n)}(function(n){n[n.Disabled=0]="Disabled",n[n.Continuous=1]="Continuous",n[n.OnDataUpdate=2]="OnDataUpdate"})(ze||(ze={})),function(n){n[n.LastBar=0]="LastBar",n[n.LastVisible=1]="LastVisible"}(Te||(Te={})),function(n){n.Solid="solid",n.VerticalGradient="gradient"}(si||(si={})),function(n){n[n.Year=0]="Year",n[n.Month=1]="Month",n[n.DayOfMonth=2]="DayOfMonth",n[n.Time=3]="Time",n[n.TimeWithSeconds=4]="TimeWithSeconds"}(Oe||(Oe={}));var Le=n=>n.getUTCFullYear();function sh(n,t,i){return t.replace(/yyyy/g,(s=>V(Le(s),4))(n)).replace(/yy/g,(s=>V(Le(s)%100,2))(n)).replace(/MMMM/g,((s,e)=>new Date(s.getUTCFullYear(),s.getUTCMonth(),1).toLocaleString(e,{month:"long"}))(n,i)).replace(/MMM/g,((s,e)=>new Date(s.getUTCFullYear(),s.getUTCMonth(),1).toLocaleString(e,{month:"short"}))(n,i)).replace(/MM/g,(s=>V((e=>e.getUTCMonth()+1)(s),2))(n)).replace(/dd/g,(s=>V((e=>e.getUTCDate())(s),2))(n))}var ei=class{constructor(t="yyyy-MM-dd",i="default"){this.dd=t,this.fd=i}F_(t){return sh(t,this.dd,this.fd)}},Ws=class{constructor(t){this.vd=t||"%h:%m:%s"}F_(t){return this.vd.replace("%h",V(t.getUTCHours(),2)).replace("%m",V(t.getUTCMinutes(),2)).replace("%s",V(t.getUTCSeconds(),2))}},eh={pd:"yyyy-MM-dd",md:"%h:%m:%s",bd:" ",wd:"default"},Vs=class{constructor(t={}){let i=Object.assign(Object.assign({},eh),t);this.gd=new ei(i.pd,i.wd),this.Md=new Ws(i.md),this.xd=i.bd}F_(t){return`${this.gd.F_(t)}${this.xd}${this.Md.F_(t)}`}};function Dt(n){return 60*n*60*1e3}function xi(n){return 60*n*1e3}var Wt=[{Sd:(Re=1,1e3*Re),yd:10},{Sd:xi(1),yd:20},{Sd:xi(5),yd:21},{Sd:xi(30),yd:22},{Sd:Dt(1),yd:30},{Sd:Dt(3),yd:31},{Sd:Dt(6),yd:32},{Sd:Dt(12),yd:33}],Re;function Ne(n,t){if(n.getUTCFullYear()!==t.getUTCFullYear())return 70;if(n.getUTCMonth()!==t.getUTCMonth())return 60;if(n.getUTCDate()!==t.getUTCDate())return 50;for(let i=Wt.length-1;i>=0;--i)if(Math.floor(t.getTime()/Wt[i].Sd)!==Math.floor(n.getTime()/Wt[i].Sd))return Wt[i].yd;return 0}function yi(n){let t=n;if(Tt(n)&&(t=te(n)),!Ds(t))throw new Error("time must be of type BusinessDay");let i=new Date(Date.UTC(t.year,t.month-1,t.day,0,0,0,0));return{kd:Math.round(i.getTime()/1e3),Cd:t}}function Be(n){if(!un(n))throw new Error("time must be of type isUTCTimestamp");return{kd:n}}function te(n){let t=new Date(n);if(isNaN(t.getTime()))throw new Error(`Invalid date string=${n}, expected format=yyyy-mm-dd`);return{day:t.getUTCDate(),month:t.getUTCMonth()+1,year:t.getUTCFullYear()}}function Pe(n){Tt(n.time)&&(n.time=te(n.time))}var ni=class{options(){return this._n}setOptions(t){this._n=t,this.updateFormatter(t.localization)}preprocessData(t){Array.isArray(t)?function(i){i.forEach(Pe)}(t):Pe(t)}createConverterToInternalObj(t){return v(function(i){return i.length===0?null:Ds(i[0].time)||Tt(i[0].time)?yi:Be}(t))}key(t){return typeof t=="object"&&"kd"in t?t.kd:this.key(this.convertHorzItemToInternal(t))}cacheKey(t){let i=t;return i.Cd===void 0?new Date(1e3*i.kd).getTime():new Date(Date.UTC(i.Cd.year,i.Cd.month-1,i.Cd.day)).getTime()}convertHorzItemToInternal(t){return un(i=t)?Be(i):Ds(i)?yi(i):yi(te(i));var i}updateFormatter(t){if(!this._n)return;let i=t.dateFormat;this._n.timeScale.timeVisible?this.Td=new Vs({pd:i,md:this._n.timeScale.secondsVisible?"%h:%m:%s":"%h:%m",bd:"   ",wd:t.locale}):this.Td=new ei(i,t.locale)}formatHorzItem(t){let i=t;return this.Td.F_(new Date(1e3*i.kd))}formatTickmark(t,i){let s=function(h,r,o){switch(h){case 0:case 10:return r?o?4:3:2;case 20:case 21:case 22:case 30:case 31:case 32:case 33:return r?3:2;case 50:return 2;case 60:return 1;case 70:return 0}}(t.weight,this._n.timeScale.timeVisible,this._n.timeScale.secondsVisible),e=this._n.timeScale;if(e.tickMarkFormatter!==void 0){let h=e.tickMarkFormatter(t.originalTime,s,i.locale);if(h!==null)return h}return function(h,r,o){let l={};switch(r){case 0:l.year="numeric";break;case 1:l.month="short";break;case 2:l.day="numeric";break;case 3:l.hour12=!1,l.hour="2-digit",l.minute="2-digit";break;case 4:l.hour12=!1,l.hour="2-digit",l.minute="2-digit",l.second="2-digit"}let a=h.Cd===void 0?new Date(1e3*h.kd):new Date(Date.UTC(h.Cd.year,h.Cd.month-1,h.Cd.day));return new Date(a.getUTCFullYear(),a.getUTCMonth(),a.getUTCDate(),a.getUTCHours(),a.getUTCMinutes(),a.getUTCSeconds(),a.getUTCMilliseconds()).toLocaleString(o,l)}(t.time,s,i.locale)}maxTickMarkWeight(t){let i=t.reduce(ih,t[0]).weight;return i>30&&i<50&&(i=30),i}fillWeightsForPoints(t,i){(function(s,e=0){if(s.length===0)return;let h=e===0?null:s[e-1].time.kd,r=h!==null?new Date(1e3*h):null,o=0;for(let l=e;l<s.length;++l){let a=s[l],u=new Date(1e3*a.time.kd);r!==null&&(a.timeWeight=Ne(u,r)),o+=a.time.kd-(h||a.time.kd),h=a.time.kd,r=u}if(e===0&&s.length>1){let l=Math.ceil(o/(s.length-1)),a=new Date(1e3*(s[0].time.kd-l));s[0].timeWeight=Ne(new Date(1e3*s[0].time.kd),a)}})(t,i)}static Pd(t){return N({localization:{dateFormat:"dd MMM 'yy"}},t??{})}};function S(n){var t=n.width,i=n.height;if(t<0)throw new Error("Negative width is not allowed for Size");if(i<0)throw new Error("Negative height is not allowed for Size");return{width:t,height:i}}function Z(n,t){return n.width===t.width&&n.height===t.height}var nh=function(){function n(t){var i=this;this._resolutionListener=function(){return i._onResolutionChanged()},this._resolutionMediaQueryList=null,this._observers=[],this._window=t,this._installResolutionListener()}return n.prototype.dispose=function(){this._uninstallResolutionListener(),this._window=null},Object.defineProperty(n.prototype,"value",{get:function(){return this._window.devicePixelRatio},enumerable:!1,configurable:!0}),n.prototype.subscribe=function(t){var i=this,s={next:t};return this._observers.push(s),{unsubscribe:function(){i._observers=i._observers.filter(function(e){return e!==s})}}},n.prototype._installResolutionListener=function(){if(this._resolutionMediaQueryList!==null)throw new Error("Resolution listener is already installed");var t=this._window.devicePixelRatio;this._resolutionMediaQueryList=this._window.matchMedia("all and (resolution: ".concat(t,"dppx)")),this._resolutionMediaQueryList.addListener(this._resolutionListener)},n.prototype._uninstallResolutionListener=function(){this._resolutionMediaQueryList!==null&&(this._resolutionMediaQueryList.removeListener(this._resolutionListener),this._resolutionMediaQueryList=null)},n.prototype._reinstallResolutionListener=function(){this._uninstallResolutionListener(),this._installResolutionListener()},n.prototype._onResolutionChanged=function(){var t=this;this._observers.forEach(function(i){return i.next(t._window.devicePixelRatio)}),this._reinstallResolutionListener()},n}(),hh=function(){function n(t,i,s){var e;this._canvasElement=null,this._bitmapSizeChangedListeners=[],this._suggestedBitmapSize=null,this._suggestedBitmapSizeChangedListeners=[],this._devicePixelRatioObservable=null,this._canvasElementResizeObserver=null,this._canvasElement=t,this._canvasElementClientSize=S({width:this._canvasElement.clientWidth,height:this._canvasElement.clientHeight}),this._transformBitmapSize=i??function(h){return h},this._allowResizeObserver=(e=s?.allowResizeObserver)===null||e===void 0||e,this._chooseAndInitObserver()}return n.prototype.dispose=function(){var t,i;if(this._canvasElement===null)throw new Error("Object is disposed");(t=this._canvasElementResizeObserver)===null||t===void 0||t.disconnect(),this._canvasElementResizeObserver=null,(i=this._devicePixelRatioObservable)===null||i===void 0||i.dispose(),this._devicePixelRatioObservable=null,this._suggestedBitmapSizeChangedListeners.length=0,this._bitmapSizeChangedListeners.length=0,this._canvasElement=null},Object.defineProperty(n.prototype,"canvasElement",{get:function(){if(this._canvasElement===null)throw new Error("Object is disposed");return this._canvasElement},enumerable:!1,configurable:!0}),Object.defineProperty(n.prototype,"canvasElementClientSize",{get:function(){return this._canvasElementClientSize},enumerable:!1,configurable:!0}),Object.defineProperty(n.prototype,"bitmapSize",{get:function(){return S({width:this.canvasElement.width,height:this.canvasElement.height})},enumerable:!1,configurable:!0}),n.prototype.resizeCanvasElement=function(t){this._canvasElementClientSize=S(t),this.canvasElement.style.width="".concat(this._canvasElementClientSize.width,"px"),this.canvasElement.style.height="".concat(this._canvasElementClientSize.height,"px"),this._invalidateBitmapSize()},n.prototype.subscribeBitmapSizeChanged=function(t){this._bitmapSizeChangedListeners.push(t)},n.prototype.unsubscribeBitmapSizeChanged=function(t){this._bitmapSizeChangedListeners=this._bitmapSizeChangedListeners.filter(function(i){return i!==t})},Object.defineProperty(n.prototype,"suggestedBitmapSize",{get:function(){return this._suggestedBitmapSize},enumerable:!1,configurable:!0}),n.prototype.subscribeSuggestedBitmapSizeChanged=function(t){this._suggestedBitmapSizeChangedListeners.push(t)},n.prototype.unsubscribeSuggestedBitmapSizeChanged=function(t){this._suggestedBitmapSizeChangedListeners=this._suggestedBitmapSizeChangedListeners.filter(function(i){return i!==t})},n.prototype.applySuggestedBitmapSize=function(){if(this._suggestedBitmapSize!==null){var t=this._suggestedBitmapSize;this._suggestedBitmapSize=null,this._resizeBitmap(t),this._emitSuggestedBitmapSizeChanged(t,this._suggestedBitmapSize)}},n.prototype._resizeBitmap=function(t){var i=this.bitmapSize;Z(i,t)||(this.canvasElement.width=t.width,this.canvasElement.height=t.height,this._emitBitmapSizeChanged(i,t))},n.prototype._emitBitmapSizeChanged=function(t,i){var s=this;this._bitmapSizeChangedListeners.forEach(function(e){return e.call(s,t,i)})},n.prototype._suggestNewBitmapSize=function(t){var i=this._suggestedBitmapSize,s=S(this._transformBitmapSize(t,this._canvasElementClientSize)),e=Z(this.bitmapSize,s)?null:s;i===null&&e===null||i!==null&&e!==null&&Z(i,e)||(this._suggestedBitmapSize=e,this._emitSuggestedBitmapSizeChanged(i,e))},n.prototype._emitSuggestedBitmapSizeChanged=function(t,i){var s=this;this._suggestedBitmapSizeChangedListeners.forEach(function(e){return e.call(s,t,i)})},n.prototype._chooseAndInitObserver=function(){var t=this;this._allowResizeObserver?new Promise(function(i){var s=new ResizeObserver(function(e){i(e.every(function(h){return"devicePixelContentBoxSize"in h})),s.disconnect()});s.observe(document.body,{box:"device-pixel-content-box"})}).catch(function(){return!1}).then(function(i){return i?t._initResizeObserver():t._initDevicePixelRatioObservable()}):this._initDevicePixelRatioObservable()},n.prototype._initDevicePixelRatioObservable=function(){var t=this;if(this._canvasElement!==null){var i=De(this._canvasElement);if(i===null)throw new Error("No window is associated with the canvas");this._devicePixelRatioObservable=function(s){return new nh(s)}(i),this._devicePixelRatioObservable.subscribe(function(){return t._invalidateBitmapSize()}),this._invalidateBitmapSize()}},n.prototype._invalidateBitmapSize=function(){var t,i;if(this._canvasElement!==null){var s=De(this._canvasElement);if(s!==null){var e=(i=(t=this._devicePixelRatioObservable)===null||t===void 0?void 0:t.value)!==null&&i!==void 0?i:s.devicePixelRatio,h=this._canvasElement.getClientRects(),r=h[0]!==void 0?function(o,l){return S({width:Math.round(o.left*l+o.width*l)-Math.round(o.left*l),height:Math.round(o.top*l+o.height*l)-Math.round(o.top*l)})}(h[0],e):S({width:this._canvasElementClientSize.width*e,height:this._canvasElementClientSize.height*e});this._suggestNewBitmapSize(r)}}},n.prototype._initResizeObserver=function(){var t=this;this._canvasElement!==null&&(this._canvasElementResizeObserver=new ResizeObserver(function(i){var s=i.find(function(r){return r.target===t._canvasElement});if(s&&s.devicePixelContentBoxSize&&s.devicePixelContentBoxSize[0]){var e=s.devicePixelContentBoxSize[0],h=S({width:e.inlineSize,height:e.blockSize});t._suggestNewBitmapSize(h)}}),this._canvasElementResizeObserver.observe(this._canvasElement,{box:"device-pixel-content-box"}))},n}();function De(n){return n.ownerDocument.defaultView}var rh=function(){function n(t,i,s){if(i.width===0||i.height===0)throw new TypeError("Rendering target could only be created on a media with positive width and height");if(this._mediaSize=i,s.width===0||s.height===0)throw new TypeError("Rendering target could only be created using a bitmap with positive integer width and height");this._bitmapSize=s,this._context=t}return n.prototype.useMediaCoordinateSpace=function(t){try{return this._context.save(),this._context.setTransform(1,0,0,1,0,0),this._context.scale(this._horizontalPixelRatio,this._verticalPixelRatio),t({context:this._context,mediaSize:this._mediaSize})}finally{this._context.restore()}},n.prototype.useBitmapCoordinateSpace=function(t){try{return this._context.save(),this._context.setTransform(1,0,0,1,0,0),t({context:this._context,mediaSize:this._mediaSize,bitmapSize:this._bitmapSize,horizontalPixelRatio:this._horizontalPixelRatio,verticalPixelRatio:this._verticalPixelRatio})}finally{this._context.restore()}},Object.defineProperty(n.prototype,"_horizontalPixelRatio",{get:function(){return this._bitmapSize.width/this._mediaSize.width},enumerable:!1,configurable:!0}),Object.defineProperty(n.prototype,"_verticalPixelRatio",{get:function(){return this._bitmapSize.height/this._mediaSize.height},enumerable:!1,configurable:!0}),n}();function X(n,t){var i=n.canvasElementClientSize;if(i.width===0||i.height===0)return null;var s=n.bitmapSize;if(s.width===0||s.height===0)return null;var e=n.canvasElement.getContext("2d",t);return e===null?null:new rh(e,i,s)}var at=typeof window<"u";function We(){return!!at&&window.navigator.userAgent.toLowerCase().indexOf("firefox")>-1}function Ei(){return!!at&&/iPhone|iPad|iPod/.test(window.navigator.platform)}function As(n){return n+n%2}function Ci(n,t){return n.Rd-t.Rd}function ki(n,t,i){let s=(n.Rd-t.Rd)/(n.ot-t.ot);return Math.sign(s)*Math.min(Math.abs(s),i)}var Hs=class{constructor(t,i,s,e){this.Dd=null,this.Od=null,this.Ad=null,this.Bd=null,this.Vd=null,this.zd=0,this.Ed=0,this.Id=t,this.Ld=i,this.Nd=s,this.ss=e}Fd(t,i){if(this.Dd!==null){if(this.Dd.ot===i)return void(this.Dd.Rd=t);if(Math.abs(this.Dd.Rd-t)<this.ss)return}this.Bd=this.Ad,this.Ad=this.Od,this.Od=this.Dd,this.Dd={ot:i,Rd:t}}Pr(t,i){if(this.Dd===null||this.Od===null||i-this.Dd.ot>50)return;let s=0,e=ki(this.Dd,this.Od,this.Ld),h=Ci(this.Dd,this.Od),r=[e],o=[h];if(s+=h,this.Ad!==null){let a=ki(this.Od,this.Ad,this.Ld);if(Math.sign(a)===Math.sign(e)){let u=Ci(this.Od,this.Ad);if(r.push(a),o.push(u),s+=u,this.Bd!==null){let c=ki(this.Ad,this.Bd,this.Ld);if(Math.sign(c)===Math.sign(e)){let d=Ci(this.Ad,this.Bd);r.push(c),o.push(d),s+=d}}}}let l=0;for(let a=0;a<r.length;++a)l+=o[a]/s*r[a];Math.abs(l)<this.Id||(this.Vd={Rd:t,ot:i},this.Ed=l,this.zd=function(a,u){let c=Math.log(u);return Math.log(1*c/-a)/c}(Math.abs(l),this.Nd))}Xu(t){let i=v(this.Vd),s=t-i.ot;return i.Rd+this.Ed*(Math.pow(this.Nd,s)-1)/Math.log(this.Nd)}Yu(t){return this.Vd===null||this.Wd(t)===this.zd}Wd(t){let i=t-v(this.Vd).ot;return Math.min(i,this.zd)}};function J(n,t){let i=v(n.ownerDocument).createElement("canvas");n.appendChild(i);let s=function(e,h){if(h.type==="device-pixel-content-box")return new hh(e,h.transform,h.options);throw new Error("Unsupported binding target")}(i,{type:"device-pixel-content-box",options:{allowResizeObserver:!1},transform:(e,h)=>({width:Math.max(e.width,h.width),height:Math.max(e.height,h.height)})});return s.resizeCanvasElement(t),s}function K(n){var t;n.width=1,n.height=1,(t=n.getContext("2d"))===null||t===void 0||t.clearRect(0,0,1,1)}function $s(n,t,i,s){n.fl&&n.fl(t,i,s)}function It(n,t,i,s){n.K(t,i,s)}function Is(n,t,i,s){let e=n(i,s);for(let h of e){let r=h.gt();r!==null&&t(r)}}function oh(n){at&&window.chrome!==void 0&&n.addEventListener("mousedown",t=>{if(t.button===1)return t.preventDefault(),!1})}var zt=class{constructor(t,i,s){this.jd=0,this.Hd=null,this.$d={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY},this.Ud=0,this.qd=null,this.Yd={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY},this.Xd=null,this.Kd=!1,this.Zd=null,this.Gd=null,this.Jd=!1,this.Qd=!1,this.tf=!1,this.if=null,this.nf=null,this.sf=null,this.ef=null,this.rf=null,this.hf=null,this.lf=null,this.af=0,this._f=!1,this.uf=!1,this.cf=!1,this.df=0,this.ff=null,this.vf=!Ei(),this.pf=e=>{this.mf(e)},this.bf=e=>{if(this.wf(e)){let h=this.gf(e);if(++this.Ud,this.qd&&this.Ud>1){let{Mf:r}=this.xf(B(e),this.Yd);r<30&&!this.tf&&this.Sf(h,this.kf.yf),this.Cf()}}else{let h=this.gf(e);if(++this.jd,this.Hd&&this.jd>1){let{Mf:r}=this.xf(B(e),this.$d);r<5&&!this.Qd&&this.Tf(h,this.kf.Pf),this.Rf()}}},this.Df=t,this.kf=i,this._n=s,this.Of()}S(){this.if!==null&&(this.if(),this.if=null),this.nf!==null&&(this.nf(),this.nf=null),this.ef!==null&&(this.ef(),this.ef=null),this.rf!==null&&(this.rf(),this.rf=null),this.hf!==null&&(this.hf(),this.hf=null),this.sf!==null&&(this.sf(),this.sf=null),this.Af(),this.Rf()}Bf(t){this.ef&&this.ef();let i=this.Vf.bind(this);if(this.ef=()=>{this.Df.removeEventListener("mousemove",i)},this.Df.addEventListener("mousemove",i),this.wf(t))return;let s=this.gf(t);this.Tf(s,this.kf.zf),this.vf=!0}Rf(){this.Hd!==null&&clearTimeout(this.Hd),this.jd=0,this.Hd=null,this.$d={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY}}Cf(){this.qd!==null&&clearTimeout(this.qd),this.Ud=0,this.qd=null,this.Yd={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY}}Vf(t){if(this.cf||this.Gd!==null||this.wf(t))return;let i=this.gf(t);this.Tf(i,this.kf.Ef),this.vf=!0}If(t){let i=zi(t.changedTouches,v(this.ff));if(i===null||(this.df=Vt(t),this.lf!==null)||this.uf)return;this._f=!0;let s=this.xf(B(i),v(this.Gd)),{Lf:e,Nf:h,Mf:r}=s;if(this.Jd||!(r<5)){if(!this.Jd){let o=.5*e,l=h>=o&&!this._n.Ff(),a=o>h&&!this._n.Wf();l||a||(this.uf=!0),this.Jd=!0,this.tf=!0,this.Af(),this.Cf()}if(!this.uf){let o=this.gf(t,i);this.Sf(o,this.kf.jf),it(t)}}}Hf(t){if(t.button!==0)return;let i=this.xf(B(t),v(this.Zd)),{Mf:s}=i;if(s>=5&&(this.Qd=!0,this.Rf()),this.Qd){let e=this.gf(t);this.Tf(e,this.kf.$f)}}xf(t,i){let s=Math.abs(i.nt-t.nt),e=Math.abs(i.st-t.st);return{Lf:s,Nf:e,Mf:s+e}}Uf(t){let i=zi(t.changedTouches,v(this.ff));if(i===null&&t.touches.length===0&&(i=t.changedTouches[0]),i===null)return;this.ff=null,this.df=Vt(t),this.Af(),this.Gd=null,this.hf&&(this.hf(),this.hf=null);let s=this.gf(t,i);if(this.Sf(s,this.kf.qf),++this.Ud,this.qd&&this.Ud>1){let{Mf:e}=this.xf(B(i),this.Yd);e<30&&!this.tf&&this.Sf(s,this.kf.yf),this.Cf()}else this.tf||(this.Sf(s,this.kf.Yf),this.kf.Yf&&it(t));this.Ud===0&&it(t),t.touches.length===0&&this.Kd&&(this.Kd=!1,it(t))}mf(t){if(t.button!==0)return;let i=this.gf(t);if(this.Zd=null,this.cf=!1,this.rf&&(this.rf(),this.rf=null),We()&&this.Df.ownerDocument.documentElement.removeEventListener("mouseleave",this.pf),!this.wf(t))if(this.Tf(i,this.kf.Xf),++this.jd,this.Hd&&this.jd>1){let{Mf:s}=this.xf(B(t),this.$d);s<5&&!this.Qd&&this.Tf(i,this.kf.Pf),this.Rf()}else this.Qd||this.Tf(i,this.kf.Kf)}Af(){this.Xd!==null&&(clearTimeout(this.Xd),this.Xd=null)}Zf(t){if(this.ff!==null)return;let i=t.changedTouches[0];this.ff=i.identifier,this.df=Vt(t);let s=this.Df.ownerDocument.documentElement;this.tf=!1,this.Jd=!1,this.uf=!1,this.Gd=B(i),this.hf&&(this.hf(),this.hf=null);{let h=this.If.bind(this),r=this.Uf.bind(this);this.hf=()=>{s.removeEventListener("touchmove",h),s.removeEventListener("touchend",r)},s.addEventListener("touchmove",h,{passive:!1}),s.addEventListener("touchend",r,{passive:!1}),this.Af(),this.Xd=setTimeout(this.Gf.bind(this,t),240)}let e=this.gf(t,i);this.Sf(e,this.kf.Jf),this.qd||(this.Ud=0,this.qd=setTimeout(this.Cf.bind(this),500),this.Yd=B(i))}Qf(t){if(t.button!==0)return;let i=this.Df.ownerDocument.documentElement;We()&&i.addEventListener("mouseleave",this.pf),this.Qd=!1,this.Zd=B(t),this.rf&&(this.rf(),this.rf=null);{let e=this.Hf.bind(this),h=this.mf.bind(this);this.rf=()=>{i.removeEventListener("mousemove",e),i.removeEventListener("mouseup",h)},i.addEventListener("mousemove",e),i.addEventListener("mouseup",h)}if(this.cf=!0,this.wf(t))return;let s=this.gf(t);this.Tf(s,this.kf.tv),this.Hd||(this.jd=0,this.Hd=setTimeout(this.Rf.bind(this),500),this.$d=B(t))}Of(){this.Df.addEventListener("mouseenter",this.Bf.bind(this)),this.Df.addEventListener("touchcancel",this.Af.bind(this));{let t=this.Df.ownerDocument,i=s=>{this.kf.iv&&(s.composed&&this.Df.contains(s.composedPath()[0])||s.target&&this.Df.contains(s.target)||this.kf.iv())};this.nf=()=>{t.removeEventListener("touchstart",i)},this.if=()=>{t.removeEventListener("mousedown",i)},t.addEventListener("mousedown",i),t.addEventListener("touchstart",i,{passive:!0})}Ei()&&(this.sf=()=>{this.Df.removeEventListener("dblclick",this.bf)},this.Df.addEventListener("dblclick",this.bf)),this.Df.addEventListener("mouseleave",this.nv.bind(this)),this.Df.addEventListener("touchstart",this.Zf.bind(this),{passive:!0}),oh(this.Df),this.Df.addEventListener("mousedown",this.Qf.bind(this)),this.sv(),this.Df.addEventListener("touchmove",()=>{},{passive:!1})}sv(){this.kf.ev===void 0&&this.kf.rv===void 0&&this.kf.hv===void 0||(this.Df.addEventListener("touchstart",t=>this.lv(t.touches),{passive:!0}),this.Df.addEventListener("touchmove",t=>{if(t.touches.length===2&&this.lf!==null&&this.kf.rv!==void 0){let i=Ve(t.touches[0],t.touches[1])/this.af;this.kf.rv(this.lf,i),it(t)}},{passive:!1}),this.Df.addEventListener("touchend",t=>{this.lv(t.touches)}))}lv(t){t.length===1&&(this._f=!1),t.length!==2||this._f||this.Kd?this.av():this.ov(t)}ov(t){let i=this.Df.getBoundingClientRect()||{left:0,top:0};this.lf={nt:(t[0].clientX-i.left+(t[1].clientX-i.left))/2,st:(t[0].clientY-i.top+(t[1].clientY-i.top))/2},this.af=Ve(t[0],t[1]),this.kf.ev!==void 0&&this.kf.ev(),this.Af()}av(){this.lf!==null&&(this.lf=null,this.kf.hv!==void 0&&this.kf.hv())}nv(t){if(this.ef&&this.ef(),this.wf(t)||!this.vf)return;let i=this.gf(t);this.Tf(i,this.kf._v),this.vf=!Ei()}Gf(t){let i=zi(t.touches,v(this.ff));if(i===null)return;let s=this.gf(t,i);this.Sf(s,this.kf.uv),this.tf=!0,this.Kd=!0}wf(t){return t.sourceCapabilities&&t.sourceCapabilities.firesTouchEvents!==void 0?t.sourceCapabilities.firesTouchEvents:Vt(t)<this.df+500}Sf(t,i){i&&i.call(this.kf,t)}Tf(t,i){i&&i.call(this.kf,t)}gf(t,i){let s=i||t,e=this.Df.getBoundingClientRect()||{left:0,top:0};return{clientX:s.clientX,clientY:s.clientY,pageX:s.pageX,pageY:s.pageY,screenX:s.screenX,screenY:s.screenY,localX:s.clientX-e.left,localY:s.clientY-e.top,ctrlKey:t.ctrlKey,altKey:t.altKey,shiftKey:t.shiftKey,metaKey:t.metaKey,cv:!t.type.startsWith("mouse")&&t.type!=="contextmenu"&&t.type!=="click",dv:t.type,fv:s.target,vv:t.view,pv:()=>{t.type!=="touchstart"&&it(t)}}}};function Ve(n,t){let i=n.clientX-t.clientX,s=n.clientY-t.clientY;return Math.sqrt(i*i+s*s)}function it(n){n.cancelable&&n.preventDefault()}function B(n){return{nt:n.pageX,st:n.pageY}}function Vt(n){return n.timeStamp||performance.now()}function zi(n,t){for(let i=0;i<n.length;++i)if(n[i].identifier===t)return n[i];return null}function At(n){return{Ec:n.Ec,mv:{mr:n.bv.externalId},wv:n.bv.cursorStyle}}function lh(n,t,i){for(let s of n){let e=s.gt();if(e!==null&&e.pr){let h=e.pr(t,i);if(h!==null)return{vv:s,mv:h}}}return null}function Ti(n,t){return i=>{var s,e,h,r;return((e=(s=i.Dt())===null||s===void 0?void 0:s.xa())!==null&&e!==void 0?e:"")!==t?[]:(r=(h=i.la)===null||h===void 0?void 0:h.call(i,n))!==null&&r!==void 0?r:[]}}var hi=class{constructor(t,i,s,e){this.Ei=null,this.gv=null,this.Mv=!1,this.xv=new Y(200),this.Zr=null,this.Sv=0,this.yv=!1,this.kv=()=>{this.yv||this.Qi.Cv().$t().Nh()},this.Tv=()=>{this.yv||this.Qi.Cv().$t().Nh()},this.Qi=t,this._n=i,this.mo=i.layout,this.kc=s,this.Pv=e==="left",this.Rv=Ti("normal",e),this.Dv=Ti("top",e),this.Ov=Ti("bottom",e),this.Av=document.createElement("div"),this.Av.style.height="100%",this.Av.style.overflow="hidden",this.Av.style.width="25px",this.Av.style.left="0",this.Av.style.position="relative",this.Bv=J(this.Av,S({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv);let h=this.Bv.canvasElement;h.style.position="absolute",h.style.zIndex="1",h.style.left="0",h.style.top="0",this.Vv=J(this.Av,S({width:16,height:16})),this.Vv.subscribeSuggestedBitmapSizeChanged(this.Tv);let r=this.Vv.canvasElement;r.style.position="absolute",r.style.zIndex="2",r.style.left="0",r.style.top="0";let o={tv:this.zv.bind(this),Jf:this.zv.bind(this),$f:this.Ev.bind(this),jf:this.Ev.bind(this),iv:this.Iv.bind(this),Xf:this.Lv.bind(this),qf:this.Lv.bind(this),Pf:this.Nv.bind(this),yf:this.Nv.bind(this),zf:this.Fv.bind(this),_v:this.Wv.bind(this)};this.jv=new zt(this.Vv.canvasElement,o,{Ff:()=>!this._n.handleScroll.vertTouchDrag,Wf:()=>!0})}S(){this.jv.S(),this.Vv.unsubscribeSuggestedBitmapSizeChanged(this.Tv),K(this.Vv.canvasElement),this.Vv.dispose(),this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),K(this.Bv.canvasElement),this.Bv.dispose(),this.Ei!==null&&this.Ei.$o().p(this),this.Ei=null}Hv(){return this.Av}P(){return this.mo.fontSize}$v(){let t=this.kc.W();return this.Zr!==t.R&&(this.xv.Qe(),this.Zr=t.R),t}Uv(){if(this.Ei===null)return 0;let t=0,i=this.$v(),s=v(this.Bv.canvasElement.getContext("2d"));s.save();let e=this.Ei.Ia();s.font=this.qv(),e.length>0&&(t=Math.max(this.xv.Mi(s,e[0].Za),this.xv.Mi(s,e[e.length-1].Za)));let h=this.Yv();for(let l=h.length;l--;){let a=this.xv.Mi(s,h[l].Zt());a>t&&(t=a)}let r=this.Ei.Ct();if(r!==null&&this.gv!==null){let l=this.Ei.fn(1,r),a=this.Ei.fn(this.gv.height-2,r);t=Math.max(t,this.xv.Mi(s,this.Ei.Ni(Math.floor(Math.min(l,a))+.11111111111111,r)),this.xv.Mi(s,this.Ei.Ni(Math.ceil(Math.max(l,a))-.11111111111111,r)))}s.restore();let o=t||34;return As(Math.ceil(i.C+i.T+i.V+i.I+5+o))}Xv(t){this.gv!==null&&Z(this.gv,t)||(this.gv=t,this.yv=!0,this.Bv.resizeCanvasElement(t),this.Vv.resizeCanvasElement(t),this.yv=!1,this.Av.style.width=`${t.width}px`,this.Av.style.height=`${t.height}px`)}Kv(){return v(this.gv).width}Zi(t){this.Ei!==t&&(this.Ei!==null&&this.Ei.$o().p(this),this.Ei=t,t.$o().l(this.ao.bind(this),this))}Dt(){return this.Ei}Qe(){let t=this.Qi.Zv();this.Qi.Cv().$t().A_(t,v(this.Dt()))}Gv(t){if(this.gv===null)return;if(t!==1){this.Jv(),this.Bv.applySuggestedBitmapSize();let s=X(this.Bv);s!==null&&(s.useBitmapCoordinateSpace(e=>{this.Qv(e),this.Ae(e)}),this.Qi.tp(s,this.Ov),this.ip(s),this.Qi.tp(s,this.Rv),this.np(s))}this.Vv.applySuggestedBitmapSize();let i=X(this.Vv);i!==null&&(i.useBitmapCoordinateSpace(({context:s,bitmapSize:e})=>{s.clearRect(0,0,e.width,e.height)}),this.sp(i),this.Qi.tp(i,this.Dv))}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}bt(){var t;(t=this.Ei)===null||t===void 0||t.Ia()}zv(t){if(this.Ei===null||this.Ei.Li()||!this._n.handleScale.axisPressedMouseMove.price)return;let i=this.Qi.Cv().$t(),s=this.Qi.Zv();this.Mv=!0,i.k_(s,this.Ei,t.localY)}Ev(t){if(this.Ei===null||!this._n.handleScale.axisPressedMouseMove.price)return;let i=this.Qi.Cv().$t(),s=this.Qi.Zv(),e=this.Ei;i.C_(s,e,t.localY)}Iv(){if(this.Ei===null||!this._n.handleScale.axisPressedMouseMove.price)return;let t=this.Qi.Cv().$t(),i=this.Qi.Zv(),s=this.Ei;this.Mv&&(this.Mv=!1,t.T_(i,s))}Lv(t){if(this.Ei===null||!this._n.handleScale.axisPressedMouseMove.price)return;let i=this.Qi.Cv().$t(),s=this.Qi.Zv();this.Mv=!1,i.T_(s,this.Ei)}Nv(t){this._n.handleScale.axisDoubleClickReset.price&&this.Qe()}Fv(t){this.Ei!==null&&(!this.Qi.Cv().$t().W().handleScale.axisPressedMouseMove.price||this.Ei.fh()||this.Ei.Co()||this.hp(1))}Wv(t){this.hp(0)}Yv(){let t=[],i=this.Ei===null?void 0:this.Ei;return(s=>{for(let e=0;e<s.length;++e){let h=s[e].Tn(this.Qi.Zv(),i);for(let r=0;r<h.length;r++)t.push(h[r])}})(this.Qi.Zv().No()),t}Qv({context:t,bitmapSize:i}){let{width:s,height:e}=i,h=this.Qi.Zv().$t(),r=h.q(),o=h._d();r===o?li(t,0,0,s,e,r):Ge(t,0,0,s,e,r,o)}Ae({context:t,bitmapSize:i,horizontalPixelRatio:s}){if(this.gv===null||this.Ei===null||!this.Ei.W().borderVisible)return;t.fillStyle=this.Ei.W().borderColor;let e=Math.max(1,Math.floor(this.$v().C*s)),h;h=this.Pv?i.width-e:0,t.fillRect(h,0,e,i.height)}ip(t){if(this.gv===null||this.Ei===null)return;let i=this.Ei.Ia(),s=this.Ei.W(),e=this.$v(),h=this.Pv?this.gv.width-e.T:0;s.borderVisible&&s.ticksVisible&&t.useBitmapCoordinateSpace(({context:r,horizontalPixelRatio:o,verticalPixelRatio:l})=>{r.fillStyle=s.borderColor;let a=Math.max(1,Math.floor(l)),u=Math.floor(.5*l),c=Math.round(e.T*o);r.beginPath();for(let d of i)r.rect(Math.floor(h*o),Math.round(d.Aa*l)-u,c,a);r.fill()}),t.useMediaCoordinateSpace(({context:r})=>{var o;r.font=this.qv(),r.fillStyle=(o=s.textColor)!==null&&o!==void 0?o:this.mo.textColor,r.textAlign=this.Pv?"right":"left",r.textBaseline="middle";let l=this.Pv?Math.round(h-e.V):Math.round(h+e.T+e.V),a=i.map(u=>this.xv.gi(r,u.Za));for(let u=i.length;u--;){let c=i[u];r.fillText(c.Za,l,c.Aa+a[u])}})}Jv(){if(this.gv===null||this.Ei===null)return;let t=this.gv.height/2,i=[],s=this.Ei.No().slice(),e=this.Qi.Zv(),h=this.$v();this.Ei===e.cr()&&this.Qi.Zv().No().forEach(l=>{e.ur(l)&&s.push(l)});let r=this.Ei.Ta()[0],o=this.Ei;s.forEach(l=>{let a=l.Tn(e,o);a.forEach(u=>{u.Oi(null),u.Ai()&&i.push(u)}),r===l&&a.length>0&&(t=a[0].Si())}),i.forEach(l=>l.Oi(l.Si())),this.Ei.W().alignLabels&&this.lp(i,h,t)}lp(t,i,s){if(this.gv===null)return;let e=t.filter(r=>r.Si()<=s),h=t.filter(r=>r.Si()>s);e.sort((r,o)=>o.Si()-r.Si()),e.length&&h.length&&h.push(e[0]),h.sort((r,o)=>r.Si()-o.Si());for(let r of t){let o=Math.floor(r.Vt(i)/2),l=r.Si();l>-o&&l<o&&r.Oi(o),l>this.gv.height-o&&l<this.gv.height+o&&r.Oi(this.gv.height-o)}for(let r=1;r<e.length;r++){let o=e[r],l=e[r-1],a=l.Vt(i,!1),u=o.Si(),c=l.Di();u>c-a&&o.Oi(c-a)}for(let r=1;r<h.length;r++){let o=h[r],l=h[r-1],a=l.Vt(i,!0),u=o.Si(),c=l.Di();u<c+a&&o.Oi(c+a)}}np(t){if(this.gv===null)return;let i=this.Yv(),s=this.$v(),e=this.Pv?"right":"left";i.forEach(h=>{h.Bi()&&h.gt(v(this.Ei)).K(t,s,this.xv,e)})}sp(t){if(this.gv===null||this.Ei===null)return;let i=this.Qi.Cv().$t(),s=[],e=this.Qi.Zv(),h=i.Wc().Tn(e,this.Ei);h.length&&s.push(h);let r=this.$v(),o=this.Pv?"right":"left";s.forEach(l=>{l.forEach(a=>{a.gt(v(this.Ei)).K(t,r,this.xv,o)})})}hp(t){this.Av.style.cursor=t===1?"ns-resize":"default"}ao(){let t=this.Uv();this.Sv<t&&this.Qi.Cv().$t().$l(),this.Sv=t}qv(){return ht(this.mo.fontSize,this.mo.fontFamily)}};function ah(n,t){var i,s;return(s=(i=n.ra)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}function Ht(n,t){var i,s;return(s=(i=n.Cn)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}function uh(n,t){var i,s;return(s=(i=n.Gi)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}function ch(n,t){var i,s;return(s=(i=n.na)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}var Fs=class n{constructor(t,i){this.gv=S({width:0,height:0}),this.ap=null,this.op=null,this._p=null,this.up=!1,this.cp=new x,this.dp=new x,this.fp=0,this.vp=!1,this.pp=null,this.mp=!1,this.bp=null,this.wp=null,this.yv=!1,this.kv=()=>{this.yv||this.gp===null||this.Hi().Nh()},this.Tv=()=>{this.yv||this.gp===null||this.Hi().Nh()},this.Mp=t,this.gp=i,this.gp.z_().l(this.xp.bind(this),this,!0),this.Sp=document.createElement("td"),this.Sp.style.padding="0",this.Sp.style.position="relative";let s=document.createElement("div");s.style.width="100%",s.style.height="100%",s.style.position="relative",s.style.overflow="hidden",this.yp=document.createElement("td"),this.yp.style.padding="0",this.kp=document.createElement("td"),this.kp.style.padding="0",this.Sp.appendChild(s),this.Bv=J(s,S({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv);let e=this.Bv.canvasElement;e.style.position="absolute",e.style.zIndex="1",e.style.left="0",e.style.top="0",this.Vv=J(s,S({width:16,height:16})),this.Vv.subscribeSuggestedBitmapSizeChanged(this.Tv);let h=this.Vv.canvasElement;h.style.position="absolute",h.style.zIndex="2",h.style.left="0",h.style.top="0",this.Cp=document.createElement("tr"),this.Cp.appendChild(this.yp),this.Cp.appendChild(this.Sp),this.Cp.appendChild(this.kp),this.Tp(),this.jv=new zt(this.Vv.canvasElement,this,{Ff:()=>this.pp===null&&!this.Mp.W().handleScroll.vertTouchDrag,Wf:()=>this.pp===null&&!this.Mp.W().handleScroll.horzTouchDrag})}S(){this.ap!==null&&this.ap.S(),this.op!==null&&this.op.S(),this.Vv.unsubscribeSuggestedBitmapSizeChanged(this.Tv),K(this.Vv.canvasElement),this.Vv.dispose(),this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),K(this.Bv.canvasElement),this.Bv.dispose(),this.gp!==null&&this.gp.z_().p(this),this.jv.S()}Zv(){return v(this.gp)}Pp(t){this.gp!==null&&this.gp.z_().p(this),this.gp=t,this.gp!==null&&this.gp.z_().l(n.prototype.xp.bind(this),this,!0),this.Tp()}Cv(){return this.Mp}Hv(){return this.Cp}Tp(){if(this.gp!==null&&(this.Rp(),this.Hi().wt().length!==0)){if(this.ap!==null){let t=this.gp.S_();this.ap.Zi(v(t))}if(this.op!==null){let t=this.gp.y_();this.op.Zi(v(t))}}}Dp(){this.ap!==null&&this.ap.bt(),this.op!==null&&this.op.bt()}v_(){return this.gp!==null?this.gp.v_():0}p_(t){this.gp&&this.gp.p_(t)}zf(t){if(!this.gp)return;this.Op();let i=t.localX,s=t.localY;this.Ap(i,s,t)}tv(t){this.Op(),this.Bp(),this.Ap(t.localX,t.localY,t)}Ef(t){var i;if(!this.gp)return;this.Op();let s=t.localX,e=t.localY;this.Ap(s,e,t);let h=this.pr(s,e);this.Mp.Vp((i=h?.wv)!==null&&i!==void 0?i:null),this.Hi().zc(h&&{Ec:h.Ec,mv:h.mv})}Kf(t){this.gp!==null&&(this.Op(),this.zp(t))}Pf(t){this.gp!==null&&this.Ep(this.dp,t)}yf(t){this.Pf(t)}$f(t){this.Op(),this.Ip(t),this.Ap(t.localX,t.localY,t)}Xf(t){this.gp!==null&&(this.Op(),this.vp=!1,this.Lp(t))}Yf(t){this.gp!==null&&this.zp(t)}uv(t){if(this.vp=!0,this.pp===null){let i={x:t.localX,y:t.localY};this.Np(i,i,t)}}_v(t){this.gp!==null&&(this.Op(),this.gp.$t().zc(null),this.Fp())}Wp(){return this.cp}jp(){return this.dp}ev(){this.fp=1,this.Hi().Hn()}rv(t,i){if(!this.Mp.W().handleScale.pinch)return;let s=5*(i-this.fp);this.fp=i,this.Hi().qc(t.nt,s)}Jf(t){this.vp=!1,this.mp=this.pp!==null,this.Bp();let i=this.Hi().Wc();this.pp!==null&&i.kt()&&(this.bp={x:i.Yt(),y:i.Xt()},this.pp={x:t.localX,y:t.localY})}jf(t){if(this.gp===null)return;let i=t.localX,s=t.localY;if(this.pp===null)this.Ip(t);else{this.mp=!1;let e=v(this.bp),h=e.x+(i-this.pp.x),r=e.y+(s-this.pp.y);this.Ap(h,r,t)}}qf(t){this.Cv().W().trackingMode.exitMode===0&&(this.mp=!0),this.Hp(),this.Lp(t)}pr(t,i){let s=this.gp;return s===null?null:function(e,h,r){let o=e.No(),l=function(a,u,c){var d,f;let m,p;for(let w of a){let M=(f=(d=w.oa)===null||d===void 0?void 0:d.call(w,u,c))!==null&&f!==void 0?f:[];for(let _ of M)b=_.zOrder,(!(g=m?.zOrder)||b==="top"&&g!=="top"||b==="normal"&&g==="bottom")&&(m=_,p=w)}var b,g;return m&&p?{bv:m,Ec:p}:null}(o,h,r);if(l?.bv.zOrder==="top")return At(l);for(let a of o){if(l&&l.Ec===a&&l.bv.zOrder!=="bottom"&&!l.bv.isBackground)return At(l);let u=lh(a.Cn(e),h,r);if(u!==null)return{Ec:a,vv:u.vv,mv:u.mv};if(l&&l.Ec===a&&l.bv.zOrder!=="bottom"&&l.bv.isBackground)return At(l)}return l?.bv?At(l):null}(s,t,i)}$p(t,i){v(i==="left"?this.ap:this.op).Xv(S({width:t,height:this.gv.height}))}Up(){return this.gv}Xv(t){Z(this.gv,t)||(this.gv=t,this.yv=!0,this.Bv.resizeCanvasElement(t),this.Vv.resizeCanvasElement(t),this.yv=!1,this.Sp.style.width=t.width+"px",this.Sp.style.height=t.height+"px")}qp(){let t=v(this.gp);t.x_(t.S_()),t.x_(t.y_());for(let i of t.Ta())if(t.ur(i)){let s=i.Dt();s!==null&&t.x_(s),i.Rn()}}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}Gv(t){if(t===0||this.gp===null)return;if(t>1&&this.qp(),this.ap!==null&&this.ap.Gv(t),this.op!==null&&this.op.Gv(t),t!==1){this.Bv.applySuggestedBitmapSize();let s=X(this.Bv);s!==null&&(s.useBitmapCoordinateSpace(e=>{this.Qv(e)}),this.gp&&(this.Yp(s,ah),this.Xp(s),this.Kp(s),this.Yp(s,Ht),this.Yp(s,uh)))}this.Vv.applySuggestedBitmapSize();let i=X(this.Vv);i!==null&&(i.useBitmapCoordinateSpace(({context:s,bitmapSize:e})=>{s.clearRect(0,0,e.width,e.height)}),this.Zp(i),this.Yp(i,ch))}Gp(){return this.ap}Jp(){return this.op}tp(t,i){this.Yp(t,i)}xp(){this.gp!==null&&this.gp.z_().p(this),this.gp=null}zp(t){this.Ep(this.cp,t)}Ep(t,i){let s=i.localX,e=i.localY;t.M()&&t.m(this.Hi().St().Bu(s),{x:s,y:e},i)}Qv({context:t,bitmapSize:i}){let{width:s,height:e}=i,h=this.Hi(),r=h.q(),o=h._d();r===o?li(t,0,0,s,e,o):Ge(t,0,0,s,e,r,o)}Xp(t){let i=v(this.gp).E_().Fh().gt();i!==null&&i.K(t,!1)}Kp(t){let i=this.Hi().Fc();this.Qp(t,Ht,$s,i),this.Qp(t,Ht,It,i)}Zp(t){this.Qp(t,Ht,It,this.Hi().Wc())}Yp(t,i){let s=v(this.gp).No();for(let e of s)this.Qp(t,i,$s,e);for(let e of s)this.Qp(t,i,It,e)}Qp(t,i,s,e){let h=v(this.gp),r=h.$t().Vc(),o=r!==null&&r.Ec===e,l=r!==null&&o&&r.mv!==void 0?r.mv.br:void 0;Is(i,a=>s(a,t,o,l),e,h)}Rp(){if(this.gp===null)return;let t=this.Mp,i=this.gp.S_().W().visible,s=this.gp.y_().W().visible;i||this.ap===null||(this.yp.removeChild(this.ap.Hv()),this.ap.S(),this.ap=null),s||this.op===null||(this.kp.removeChild(this.op.Hv()),this.op.S(),this.op=null);let e=t.$t().ed();i&&this.ap===null&&(this.ap=new hi(this,t.W(),e,"left"),this.yp.appendChild(this.ap.Hv())),s&&this.op===null&&(this.op=new hi(this,t.W(),e,"right"),this.kp.appendChild(this.op.Hv()))}tm(t){return t.cv&&this.vp||this.pp!==null}im(t){return Math.max(0,Math.min(t,this.gv.width-1))}nm(t){return Math.max(0,Math.min(t,this.gv.height-1))}Ap(t,i,s){this.Hi().Qc(this.im(t),this.nm(i),s,v(this.gp))}Fp(){this.Hi().nd()}Hp(){this.mp&&(this.pp=null,this.Fp())}Np(t,i,s){this.pp=t,this.mp=!1,this.Ap(i.x,i.y,s);let e=this.Hi().Wc();this.bp={x:e.Yt(),y:e.Xt()}}Hi(){return this.Mp.$t()}Lp(t){if(!this.up)return;let i=this.Hi(),s=this.Zv();if(i.D_(s,s.dn()),this._p=null,this.up=!1,i.Zc(),this.wp!==null){let e=performance.now(),h=i.St();this.wp.Pr(h.Iu(),e),this.wp.Yu(e)||i.qn(this.wp)}}Op(){this.pp=null}Bp(){if(this.gp){if(this.Hi().Hn(),document.activeElement!==document.body&&document.activeElement!==document.documentElement)v(document.activeElement).blur();else{let t=document.getSelection();t!==null&&t.removeAllRanges()}!this.gp.dn().Li()&&this.Hi().St().Li()}}Ip(t){if(this.gp===null)return;let i=this.Hi(),s=i.St();if(s.Li())return;let e=this.Mp.W(),h=e.handleScroll,r=e.kineticScroll;if((!h.pressedMouseMove||t.cv)&&(!h.horzTouchDrag&&!h.vertTouchDrag||!t.cv))return;let o=this.gp.dn(),l=performance.now();if(this._p!==null||this.tm(t)||(this._p={x:t.clientX,y:t.clientY,kd:l,sm:t.localX,rm:t.localY}),this._p!==null&&!this.up&&(this._p.x!==t.clientX||this._p.y!==t.clientY)){if(t.cv&&r.touch||!t.cv&&r.mouse){let a=s.ee();this.wp=new Hs(.2/a,7/a,.997,15/a),this.wp.Fd(s.Iu(),this._p.kd)}else this.wp=null;o.Li()||i.P_(this.gp,o,t.localY),i.Xc(t.localX),this.up=!0}this.up&&(o.Li()||i.R_(this.gp,o,t.localY),i.Kc(t.localX),this.wp!==null&&this.wp.Fd(s.Iu(),l))}},ri=class{constructor(t,i,s,e,h){this.ft=!0,this.gv=S({width:0,height:0}),this.kv=()=>this.Gv(3),this.Pv=t==="left",this.kc=s.ed,this._n=i,this.hm=e,this.lm=h,this.Av=document.createElement("div"),this.Av.style.width="25px",this.Av.style.height="100%",this.Av.style.overflow="hidden",this.Bv=J(this.Av,S({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv)}S(){this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),K(this.Bv.canvasElement),this.Bv.dispose()}Hv(){return this.Av}Up(){return this.gv}Xv(t){Z(this.gv,t)||(this.gv=t,this.Bv.resizeCanvasElement(t),this.Av.style.width=`${t.width}px`,this.Av.style.height=`${t.height}px`,this.ft=!0)}Gv(t){if(t<3&&!this.ft||this.gv.width===0||this.gv.height===0)return;this.ft=!1,this.Bv.applySuggestedBitmapSize();let i=X(this.Bv);i!==null&&i.useBitmapCoordinateSpace(s=>{this.Qv(s),this.Ae(s)})}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}Ae({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(!this.hm())return;t.fillStyle=this._n.timeScale.borderColor;let h=Math.floor(this.kc.W().C*s),r=Math.floor(this.kc.W().C*e),o=this.Pv?i.width-h:0;t.fillRect(o,0,h,r)}Qv({context:t,bitmapSize:i}){li(t,0,0,i.width,i.height,this.lm())}};function ie(n){return t=>{var i,s;return(s=(i=t.aa)===null||i===void 0?void 0:i.call(t,n))!==null&&s!==void 0?s:[]}}var dh=ie("normal"),fh=ie("top"),mh=ie("bottom"),js=class{constructor(t,i){this.am=null,this.om=null,this.k=null,this._m=!1,this.gv=S({width:0,height:0}),this.um=new x,this.xv=new Y(5),this.yv=!1,this.kv=()=>{this.yv||this.Mp.$t().Nh()},this.Tv=()=>{this.yv||this.Mp.$t().Nh()},this.Mp=t,this.N_=i,this._n=t.W().layout,this.dm=document.createElement("tr"),this.fm=document.createElement("td"),this.fm.style.padding="0",this.vm=document.createElement("td"),this.vm.style.padding="0",this.Av=document.createElement("td"),this.Av.style.height="25px",this.Av.style.padding="0",this.pm=document.createElement("div"),this.pm.style.width="100%",this.pm.style.height="100%",this.pm.style.position="relative",this.pm.style.overflow="hidden",this.Av.appendChild(this.pm),this.Bv=J(this.pm,S({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv);let s=this.Bv.canvasElement;s.style.position="absolute",s.style.zIndex="1",s.style.left="0",s.style.top="0",this.Vv=J(this.pm,S({width:16,height:16})),this.Vv.subscribeSuggestedBitmapSizeChanged(this.Tv);let e=this.Vv.canvasElement;e.style.position="absolute",e.style.zIndex="2",e.style.left="0",e.style.top="0",this.dm.appendChild(this.fm),this.dm.appendChild(this.Av),this.dm.appendChild(this.vm),this.bm(),this.Mp.$t().f_().l(this.bm.bind(this),this),this.jv=new zt(this.Vv.canvasElement,this,{Ff:()=>!0,Wf:()=>!this.Mp.W().handleScroll.horzTouchDrag})}S(){this.jv.S(),this.am!==null&&this.am.S(),this.om!==null&&this.om.S(),this.Vv.unsubscribeSuggestedBitmapSizeChanged(this.Tv),K(this.Vv.canvasElement),this.Vv.dispose(),this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),K(this.Bv.canvasElement),this.Bv.dispose()}Hv(){return this.dm}wm(){return this.am}gm(){return this.om}tv(t){if(this._m)return;this._m=!0;let i=this.Mp.$t();!i.St().Li()&&this.Mp.W().handleScale.axisPressedMouseMove.time&&i.Uc(t.localX)}Jf(t){this.tv(t)}iv(){let t=this.Mp.$t();!t.St().Li()&&this._m&&(this._m=!1,this.Mp.W().handleScale.axisPressedMouseMove.time&&t.Jc())}$f(t){let i=this.Mp.$t();!i.St().Li()&&this.Mp.W().handleScale.axisPressedMouseMove.time&&i.Gc(t.localX)}jf(t){this.$f(t)}Xf(){this._m=!1;let t=this.Mp.$t();t.St().Li()&&!this.Mp.W().handleScale.axisPressedMouseMove.time||t.Jc()}qf(){this.Xf()}Pf(){this.Mp.W().handleScale.axisDoubleClickReset.time&&this.Mp.$t().Xn()}yf(){this.Pf()}zf(){this.Mp.$t().W().handleScale.axisPressedMouseMove.time&&this.hp(1)}_v(){this.hp(0)}Up(){return this.gv}Mm(){return this.um}xm(t,i,s){Z(this.gv,t)||(this.gv=t,this.yv=!0,this.Bv.resizeCanvasElement(t),this.Vv.resizeCanvasElement(t),this.yv=!1,this.Av.style.width=`${t.width}px`,this.Av.style.height=`${t.height}px`,this.um.m(t)),this.am!==null&&this.am.Xv(S({width:i,height:t.height})),this.om!==null&&this.om.Xv(S({width:s,height:t.height}))}Sm(){let t=this.ym();return Math.ceil(t.C+t.T+t.P+t.L+t.B+t.km)}bt(){this.Mp.$t().St().Ia()}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}Gv(t){if(t===0)return;if(t!==1){this.Bv.applySuggestedBitmapSize();let s=X(this.Bv);s!==null&&(s.useBitmapCoordinateSpace(e=>{this.Qv(e),this.Ae(e),this.Cm(s,mh)}),this.ip(s),this.Cm(s,dh)),this.am!==null&&this.am.Gv(t),this.om!==null&&this.om.Gv(t)}this.Vv.applySuggestedBitmapSize();let i=X(this.Vv);i!==null&&(i.useBitmapCoordinateSpace(({context:s,bitmapSize:e})=>{s.clearRect(0,0,e.width,e.height)}),this.Tm([...this.Mp.$t().wt(),this.Mp.$t().Wc()],i),this.Cm(i,fh))}Cm(t,i){let s=this.Mp.$t().wt();for(let e of s)Is(i,h=>$s(h,t,!1,void 0),e,void 0);for(let e of s)Is(i,h=>It(h,t,!1,void 0),e,void 0)}Qv({context:t,bitmapSize:i}){li(t,0,0,i.width,i.height,this.Mp.$t()._d())}Ae({context:t,bitmapSize:i,verticalPixelRatio:s}){if(this.Mp.W().timeScale.borderVisible){t.fillStyle=this.Pm();let e=Math.max(1,Math.floor(this.ym().C*s));t.fillRect(0,0,i.width,e)}}ip(t){let i=this.Mp.$t().St(),s=i.Ia();if(!s||s.length===0)return;let e=this.N_.maxTickMarkWeight(s),h=this.ym(),r=i.W();r.borderVisible&&r.ticksVisible&&t.useBitmapCoordinateSpace(({context:o,horizontalPixelRatio:l,verticalPixelRatio:a})=>{o.strokeStyle=this.Pm(),o.fillStyle=this.Pm();let u=Math.max(1,Math.floor(l)),c=Math.floor(.5*l);o.beginPath();let d=Math.round(h.T*a);for(let f=s.length;f--;){let m=Math.round(s[f].coord*l);o.rect(m-c,0,u,d)}o.fill()}),t.useMediaCoordinateSpace(({context:o})=>{let l=h.C+h.T+h.L+h.P/2;o.textAlign="center",o.textBaseline="middle",o.fillStyle=this.$(),o.font=this.qv();for(let a of s)if(a.weight<e){let u=a.needAlignCoordinate?this.Rm(o,a.coord,a.label):a.coord;o.fillText(a.label,u,l)}this.Mp.W().timeScale.allowBoldLabels&&(o.font=this.Dm());for(let a of s)if(a.weight>=e){let u=a.needAlignCoordinate?this.Rm(o,a.coord,a.label):a.coord;o.fillText(a.label,u,l)}})}Rm(t,i,s){let e=this.xv.Mi(t,s),h=e/2,r=Math.floor(i-h)+.5;return r<0?i+=Math.abs(0-r):r+e>this.gv.width&&(i-=Math.abs(this.gv.width-(r+e))),i}Tm(t,i){let s=this.ym();for(let e of t)for(let h of e.Ji())h.gt().K(i,s)}Pm(){return this.Mp.W().timeScale.borderColor}$(){return this._n.textColor}j(){return this._n.fontSize}qv(){return ht(this.j(),this._n.fontFamily)}Dm(){return ht(this.j(),this._n.fontFamily,"bold")}ym(){this.k===null&&(this.k={C:1,N:NaN,L:NaN,B:NaN,Wi:NaN,T:5,P:NaN,R:"",Fi:new Y,km:0});let t=this.k,i=this.qv();if(t.R!==i){let s=this.j();t.P=s,t.R=i,t.L=3*s/12,t.B=3*s/12,t.Wi=9*s/12,t.N=0,t.km=4*s/12,t.Fi.Qe()}return this.k}hp(t){this.Av.style.cursor=t===1?"ew-resize":"default"}bm(){let t=this.Mp.$t(),i=t.W();i.leftPriceScale.visible||this.am===null||(this.fm.removeChild(this.am.Hv()),this.am.S(),this.am=null),i.rightPriceScale.visible||this.om===null||(this.vm.removeChild(this.om.Hv()),this.om.S(),this.om=null);let s={ed:this.Mp.$t().ed()},e=()=>i.leftPriceScale.borderVisible&&t.St().W().borderVisible,h=()=>t._d();i.leftPriceScale.visible&&this.am===null&&(this.am=new ri("left",i,s,e,h),this.fm.appendChild(this.am.Hv())),i.rightPriceScale.visible&&this.om===null&&(this.om=new ri("right",i,s,e,h),this.vm.appendChild(this.om.Hv()))}},ph=!!at&&!!navigator.userAgentData&&navigator.userAgentData.brands.some(n=>n.brand.includes("Chromium"))&&!!at&&(!((Oi=navigator?.userAgentData)===null||Oi===void 0)&&Oi.platform?navigator.userAgentData.platform==="Windows":navigator.userAgent.toLowerCase().indexOf("win")>=0),Oi,Us=class{constructor(t,i,s){var e;this.Om=[],this.Am=0,this.Qa=0,this.e_=0,this.Bm=0,this.Vm=0,this.zm=null,this.Em=!1,this.cp=new x,this.dp=new x,this.xc=new x,this.Im=null,this.Lm=null,this.Nm=t,this._n=i,this.N_=s,this.dm=document.createElement("div"),this.dm.classList.add("tv-lightweight-charts"),this.dm.style.overflow="hidden",this.dm.style.direction="ltr",this.dm.style.width="100%",this.dm.style.height="100%",(e=this.dm).style.userSelect="none",e.style.webkitUserSelect="none",e.style.msUserSelect="none",e.style.MozUserSelect="none",e.style.webkitTapHighlightColor="transparent",this.Fm=document.createElement("table"),this.Fm.setAttribute("cellspacing","0"),this.dm.appendChild(this.Fm),this.Wm=this.jm.bind(this),Li(this._n)&&this.Hm(!0),this.Hi=new Ps(this.yc.bind(this),this._n,s),this.$t().jc().l(this.$m.bind(this),this),this.Um=new js(this,this.N_),this.Fm.appendChild(this.Um.Hv());let h=i.autoSize&&this.qm(),r=this._n.width,o=this._n.height;if(h||r===0||o===0){let l=t.getBoundingClientRect();r=r||l.width,o=o||l.height}this.Ym(r,o),this.Xm(),t.appendChild(this.dm),this.Km(),this.Hi.St().Gu().l(this.Hi.$l.bind(this.Hi),this),this.Hi.f_().l(this.Hi.$l.bind(this.Hi),this)}$t(){return this.Hi}W(){return this._n}Zm(){return this.Om}Gm(){return this.Um}S(){this.Hm(!1),this.Am!==0&&window.cancelAnimationFrame(this.Am),this.Hi.jc().p(this),this.Hi.St().Gu().p(this),this.Hi.f_().p(this),this.Hi.S();for(let t of this.Om)this.Fm.removeChild(t.Hv()),t.Wp().p(this),t.jp().p(this),t.S();this.Om=[],v(this.Um).S(),this.dm.parentElement!==null&&this.dm.parentElement.removeChild(this.dm),this.xc.S(),this.cp.S(),this.dp.S(),this.Jm()}Ym(t,i,s=!1){if(this.Qa===i&&this.e_===t)return;let e=function(o){let l=Math.floor(o.width),a=Math.floor(o.height);return S({width:l-l%2,height:a-a%2})}(S({width:t,height:i}));this.Qa=e.height,this.e_=e.width;let h=this.Qa+"px",r=this.e_+"px";v(this.dm).style.height=h,v(this.dm).style.width=r,this.Fm.style.height=h,this.Fm.style.width=r,s?this.Qm(C.ns(),performance.now()):this.Hi.$l()}Gv(t){t===void 0&&(t=C.ns());for(let i=0;i<this.Om.length;i++)this.Om[i].Gv(t.Wn(i).Ln);this._n.timeScale.visible&&this.Um.Gv(t.Fn())}Lh(t){let i=Li(this._n);this.Hi.Lh(t);let s=Li(this._n);s!==i&&this.Hm(s),this.Km(),this.tb(t)}Wp(){return this.cp}jp(){return this.dp}jc(){return this.xc}ib(){this.zm!==null&&(this.Qm(this.zm,performance.now()),this.zm=null);let t=this.nb(null),i=document.createElement("canvas");i.width=t.width,i.height=t.height;let s=v(i.getContext("2d"));return this.nb(s),i}sb(t){return t==="left"&&!this.eb()||t==="right"&&!this.rb()||this.Om.length===0?0:v(t==="left"?this.Om[0].Gp():this.Om[0].Jp()).Kv()}hb(){return this._n.autoSize&&this.Im!==null}lb(){return this.dm}Vp(t){this.Lm=t,this.Lm?this.lb().style.setProperty("cursor",t):this.lb().style.removeProperty("cursor")}ab(){return this.Lm}ob(){return z(this.Om[0]).Up()}tb(t){(t.autoSize!==void 0||!this.Im||t.width===void 0&&t.height===void 0)&&(t.autoSize&&!this.Im&&this.qm(),t.autoSize===!1&&this.Im!==null&&this.Jm(),t.autoSize||t.width===void 0&&t.height===void 0||this.Ym(t.width||this.e_,t.height||this.Qa))}nb(t){let i=0,s=0,e=this.Om[0],h=(o,l)=>{let a=0;for(let u=0;u<this.Om.length;u++){let c=this.Om[u],d=v(o==="left"?c.Gp():c.Jp()),f=d.ep();t!==null&&d.rp(t,l,a),a+=f.height}};this.eb()&&(h("left",0),i+=v(e.Gp()).ep().width);for(let o=0;o<this.Om.length;o++){let l=this.Om[o],a=l.ep();t!==null&&l.rp(t,i,s),s+=a.height}i+=e.ep().width,this.rb()&&(h("right",i),i+=v(e.Jp()).ep().width);let r=(o,l,a)=>{v(o==="left"?this.Um.wm():this.Um.gm()).rp(v(t),l,a)};if(this._n.timeScale.visible){let o=this.Um.ep();if(t!==null){let l=0;this.eb()&&(r("left",l,s),l=v(e.Gp()).ep().width),this.Um.rp(t,l,s),l+=o.width,this.rb()&&r("right",l,s)}s+=o.height}return S({width:i,height:s})}_b(){let t=0,i=0,s=0;for(let m of this.Om)this.eb()&&(i=Math.max(i,v(m.Gp()).Uv(),this._n.leftPriceScale.minimumWidth)),this.rb()&&(s=Math.max(s,v(m.Jp()).Uv(),this._n.rightPriceScale.minimumWidth)),t+=m.v_();i=As(i),s=As(s);let e=this.e_,h=this.Qa,r=Math.max(e-i-s,0),o=this._n.timeScale.visible,l=o?Math.max(this.Um.Sm(),this._n.timeScale.minimumHeight):0;var a;l=(a=l)+a%2;let u=0+l,c=h<u?0:h-u,d=c/t,f=0;for(let m=0;m<this.Om.length;++m){let p=this.Om[m];p.Pp(this.Hi.Nc()[m]);let b=0,g=0;g=m===this.Om.length-1?c-f:Math.round(p.v_()*d),b=Math.max(g,2),f+=b,p.Xv(S({width:r,height:b})),this.eb()&&p.$p(i,"left"),this.rb()&&p.$p(s,"right"),p.Zv()&&this.Hi.Hc(p.Zv(),b)}this.Um.xm(S({width:o?r:0,height:l}),o?i:0,o?s:0),this.Hi.m_(r),this.Bm!==i&&(this.Bm=i),this.Vm!==s&&(this.Vm=s)}Hm(t){t?this.dm.addEventListener("wheel",this.Wm,{passive:!1}):this.dm.removeEventListener("wheel",this.Wm)}ub(t){switch(t.deltaMode){case t.DOM_DELTA_PAGE:return 120;case t.DOM_DELTA_LINE:return 32}return ph?1/window.devicePixelRatio:1}jm(t){if(!(t.deltaX!==0&&this._n.handleScroll.mouseWheel||t.deltaY!==0&&this._n.handleScale.mouseWheel))return;let i=this.ub(t),s=i*t.deltaX/100,e=-i*t.deltaY/100;if(t.cancelable&&t.preventDefault(),e!==0&&this._n.handleScale.mouseWheel){let h=Math.sign(e)*Math.min(1,Math.abs(e)),r=t.clientX-this.dm.getBoundingClientRect().left;this.$t().qc(r,h)}s!==0&&this._n.handleScroll.mouseWheel&&this.$t().Yc(-80*s)}Qm(t,i){var s;let e=t.Fn();e===3&&this.cb(),e!==3&&e!==2||(this.fb(t),this.vb(t,i),this.Um.bt(),this.Om.forEach(h=>{h.Dp()}),((s=this.zm)===null||s===void 0?void 0:s.Fn())===3&&(this.zm.Jn(t),this.cb(),this.fb(this.zm),this.vb(this.zm,i),t=this.zm,this.zm=null)),this.Gv(t)}vb(t,i){for(let s of t.Gn())this.Qn(s,i)}fb(t){let i=this.Hi.Nc();for(let s=0;s<i.length;s++)t.Wn(s).Nn&&i[s].B_()}Qn(t,i){let s=this.Hi.St();switch(t.$n){case 0:s.Qu();break;case 1:s.tc(t.Ot);break;case 2:s.Kn(t.Ot);break;case 3:s.Zn(t.Ot);break;case 4:s.Fu();break;case 5:t.Ot.Yu(i)||s.Zn(t.Ot.Xu(i))}}yc(t){this.zm!==null?this.zm.Jn(t):this.zm=t,this.Em||(this.Em=!0,this.Am=window.requestAnimationFrame(i=>{if(this.Em=!1,this.Am=0,this.zm!==null){let s=this.zm;this.zm=null,this.Qm(s,i);for(let e of s.Gn())if(e.$n===5&&!e.Ot.Yu(i)){this.$t().qn(e.Ot);break}}}))}cb(){this.Xm()}Xm(){let t=this.Hi.Nc(),i=t.length,s=this.Om.length;for(let e=i;e<s;e++){let h=z(this.Om.pop());this.Fm.removeChild(h.Hv()),h.Wp().p(this),h.jp().p(this),h.S()}for(let e=s;e<i;e++){let h=new Fs(this,t[e]);h.Wp().l(this.pb.bind(this),this),h.jp().l(this.mb.bind(this),this),this.Om.push(h),this.Fm.insertBefore(h.Hv(),this.Um.Hv())}for(let e=0;e<i;e++){let h=t[e],r=this.Om[e];r.Zv()!==h?r.Pp(h):r.Tp()}this.Km(),this._b()}bb(t,i,s){var e;let h=new Map;t!==null&&this.Hi.wt().forEach(u=>{let c=u.Bn().il(t);c!==null&&h.set(u,c)});let r;if(t!==null){let u=(e=this.Hi.St().$i(t))===null||e===void 0?void 0:e.originalTime;u!==void 0&&(r=u)}let o=this.$t().Vc(),l=o!==null&&o.Ec instanceof kt?o.Ec:void 0,a=o!==null&&o.mv!==void 0?o.mv.mr:void 0;return{wb:r,ie:t??void 0,gb:i??void 0,Mb:l,xb:h,Sb:a,yb:s??void 0}}pb(t,i,s){this.cp.m(()=>this.bb(t,i,s))}mb(t,i,s){this.dp.m(()=>this.bb(t,i,s))}$m(t,i,s){this.xc.m(()=>this.bb(t,i,s))}Km(){let t=this._n.timeScale.visible?"":"none";this.Um.Hv().style.display=t}eb(){return this.Om[0].Zv().S_().W().visible}rb(){return this.Om[0].Zv().y_().W().visible}qm(){return"ResizeObserver"in window&&(this.Im=new ResizeObserver(t=>{let i=t.find(s=>s.target===this.Nm);i&&this.Ym(i.contentRect.width,i.contentRect.height)}),this.Im.observe(this.Nm,{box:"border-box"}),!0)}Jm(){this.Im!==null&&this.Im.disconnect(),this.Im=null}};function Li(n){return!!(n.handleScroll.mouseWheel||n.handleScale.mouseWheel)}function cn(n,t){var i={};for(var s in n)Object.prototype.hasOwnProperty.call(n,s)&&t.indexOf(s)<0&&(i[s]=n[s]);if(n!=null&&typeof Object.getOwnPropertySymbols=="function"){var e=0;for(s=Object.getOwnPropertySymbols(n);e<s.length;e++)t.indexOf(s[e])<0&&Object.prototype.propertyIsEnumerable.call(n,s[e])&&(i[s[e]]=n[s[e]])}return i}function Ae(n,t,i,s){let e=i.value,h={ie:t,ot:n,Ot:[e,e,e,e],wb:s};return i.color!==void 0&&(h.O=i.color),h}function vh(n,t,i,s){let e=i.value,h={ie:t,ot:n,Ot:[e,e,e,e],wb:s};return i.lineColor!==void 0&&(h.lt=i.lineColor),i.topColor!==void 0&&(h.ks=i.topColor),i.bottomColor!==void 0&&(h.Cs=i.bottomColor),h}function bh(n,t,i,s){let e=i.value,h={ie:t,ot:n,Ot:[e,e,e,e],wb:s};return i.topLineColor!==void 0&&(h.Ce=i.topLineColor),i.bottomLineColor!==void 0&&(h.Te=i.bottomLineColor),i.topFillColor1!==void 0&&(h.Me=i.topFillColor1),i.topFillColor2!==void 0&&(h.xe=i.topFillColor2),i.bottomFillColor1!==void 0&&(h.Se=i.bottomFillColor1),i.bottomFillColor2!==void 0&&(h.ye=i.bottomFillColor2),h}function gh(n,t,i,s){let e={ie:t,ot:n,Ot:[i.open,i.high,i.low,i.close],wb:s};return i.color!==void 0&&(e.O=i.color),e}function wh(n,t,i,s){let e={ie:t,ot:n,Ot:[i.open,i.high,i.low,i.close],wb:s};return i.color!==void 0&&(e.O=i.color),i.borderColor!==void 0&&(e.At=i.borderColor),i.wickColor!==void 0&&(e.Hh=i.wickColor),e}function _h(n,t,i,s,e){let h=z(e)(i),r=Math.max(...h),o=Math.min(...h),l=h[h.length-1],a=[l,r,o,l],u=i,{time:c,color:d}=u;return{ie:t,ot:n,Ot:a,wb:s,We:cn(u,["time","color"]),O:d}}function $t(n){return n.Ot!==void 0}function He(n,t){return t.customValues!==void 0&&(n.kb=t.customValues),n}function U(n){return(t,i,s,e,h,r)=>function(o,l){return l?l(o):(a=o).open===void 0&&a.value===void 0;var a}(s,r)?He({ot:t,ie:i,wb:e},s):He(n(t,i,s,e,h),s)}function $e(n){return{Candlestick:U(wh),Bar:U(gh),Area:U(vh),Baseline:U(bh),Histogram:U(Ae),Line:U(Ae),Custom:U(_h)}[n]}function Ie(n){return{ie:0,Cb:new Map,ia:n}}function Fe(n,t){if(n!==void 0&&n.length!==0)return{Tb:t.key(n[0].ot),Pb:t.key(n[n.length-1].ot)}}function je(n){let t;return n.forEach(i=>{t===void 0&&(t=i.wb)}),z(t)}var qs=class{constructor(t){this.Rb=new Map,this.Db=new Map,this.Ob=new Map,this.Ab=[],this.N_=t}S(){this.Rb.clear(),this.Db.clear(),this.Ob.clear(),this.Ab=[]}Bb(t,i){let s=this.Rb.size!==0,e=!1,h=this.Db.get(t);if(h!==void 0)if(this.Db.size===1)s=!1,e=!0,this.Rb.clear();else for(let l of this.Ab)l.pointData.Cb.delete(t)&&(e=!0);let r=[];if(i.length!==0){let l=i.map(f=>f.time),a=this.N_.createConverterToInternalObj(i),u=$e(t.Yh()),c=t.ga(),d=t.Ma();r=i.map((f,m)=>{let p=a(f.time),b=this.N_.key(p),g=this.Rb.get(b);g===void 0&&(g=Ie(p),this.Rb.set(b,g),e=!0);let w=u(p,g.ie,f,l[m],c,d);return g.Cb.set(t,w),w})}s&&this.Vb(),this.zb(t,r);let o=-1;if(e){let l=[];this.Rb.forEach(a=>{l.push({timeWeight:0,time:a.ia,pointData:a,originalTime:je(a.Cb)})}),l.sort((a,u)=>this.N_.key(a.time)-this.N_.key(u.time)),o=this.Eb(l)}return this.Ib(t,o,function(l,a,u){let c=Fe(l,u),d=Fe(a,u);if(c!==void 0&&d!==void 0)return{Xl:c.Pb>=d.Pb&&c.Tb>=d.Tb}}(this.Db.get(t),h,this.N_))}ld(t){return this.Bb(t,[])}Lb(t,i){let s=i;(function(p){p.wb===void 0&&(p.wb=p.time)})(s),this.N_.preprocessData(i);let e=this.N_.createConverterToInternalObj([i])(i.time),h=this.Ob.get(t);if(h!==void 0&&this.N_.key(e)<this.N_.key(h))throw new Error(`Cannot update oldest data, last time=${h}, new time=${e}`);let r=this.Rb.get(this.N_.key(e)),o=r===void 0;r===void 0&&(r=Ie(e),this.Rb.set(this.N_.key(e),r));let l=$e(t.Yh()),a=t.ga(),u=t.Ma(),c=l(e,r.ie,i,s.wb,a,u);r.Cb.set(t,c),this.Nb(t,c);let d={Xl:$t(c)};if(!o)return this.Ib(t,-1,d);let f={timeWeight:0,time:r.ia,pointData:r,originalTime:je(r.Cb)},m=Ot(this.Ab,this.N_.key(f.time),(p,b)=>this.N_.key(p.time)<b);this.Ab.splice(m,0,f);for(let p=m;p<this.Ab.length;++p)Ri(this.Ab[p].pointData,p);return this.N_.fillWeightsForPoints(this.Ab,m),this.Ib(t,m,d)}Nb(t,i){let s=this.Db.get(t);s===void 0&&(s=[],this.Db.set(t,s));let e=s.length!==0?s[s.length-1]:null;e===null||this.N_.key(i.ot)>this.N_.key(e.ot)?$t(i)&&s.push(i):$t(i)?s[s.length-1]=i:s.splice(-1,1),this.Ob.set(t,i.ot)}zb(t,i){i.length!==0?(this.Db.set(t,i.filter($t)),this.Ob.set(t,i[i.length-1].ot)):(this.Db.delete(t),this.Ob.delete(t))}Vb(){for(let t of this.Ab)t.pointData.Cb.size===0&&this.Rb.delete(this.N_.key(t.time))}Eb(t){let i=-1;for(let s=0;s<this.Ab.length&&s<t.length;++s){let e=this.Ab[s],h=t[s];if(this.N_.key(e.time)!==this.N_.key(h.time)){i=s;break}h.timeWeight=e.timeWeight,Ri(h.pointData,s)}if(i===-1&&this.Ab.length!==t.length&&(i=Math.min(this.Ab.length,t.length)),i===-1)return-1;for(let s=i;s<t.length;++s)Ri(t[s].pointData,s);return this.N_.fillWeightsForPoints(t,i),this.Ab=t,i}Fb(){if(this.Db.size===0)return null;let t=0;return this.Db.forEach(i=>{i.length!==0&&(t=Math.max(t,i[i.length-1].ie))}),t}Ib(t,i,s){let e={Wb:new Map,St:{Au:this.Fb()}};if(i!==-1)this.Db.forEach((h,r)=>{e.Wb.set(r,{We:h,jb:r===t?s:void 0})}),this.Db.has(t)||e.Wb.set(t,{We:[],jb:s}),e.St.Hb=this.Ab,e.St.$b=i;else{let h=this.Db.get(t);e.Wb.set(t,{We:h||[],jb:s})}return e}};function Ri(n,t){n.ie=t,n.Cb.forEach(i=>{i.ie=t})}function se(n){let t={value:n.Ot[3],time:n.wb};return n.kb!==void 0&&(t.customValues=n.kb),t}function Ue(n){let t=se(n);return n.O!==void 0&&(t.color=n.O),t}function Sh(n){let t=se(n);return n.lt!==void 0&&(t.lineColor=n.lt),n.ks!==void 0&&(t.topColor=n.ks),n.Cs!==void 0&&(t.bottomColor=n.Cs),t}function Mh(n){let t=se(n);return n.Ce!==void 0&&(t.topLineColor=n.Ce),n.Te!==void 0&&(t.bottomLineColor=n.Te),n.Me!==void 0&&(t.topFillColor1=n.Me),n.xe!==void 0&&(t.topFillColor2=n.xe),n.Se!==void 0&&(t.bottomFillColor1=n.Se),n.ye!==void 0&&(t.bottomFillColor2=n.ye),t}function dn(n){let t={open:n.Ot[0],high:n.Ot[1],low:n.Ot[2],close:n.Ot[3],time:n.wb};return n.kb!==void 0&&(t.customValues=n.kb),t}function xh(n){let t=dn(n);return n.O!==void 0&&(t.color=n.O),t}function yh(n){let t=dn(n),{O:i,At:s,Hh:e}=n;return i!==void 0&&(t.color=i),s!==void 0&&(t.borderColor=s),e!==void 0&&(t.wickColor=e),t}function Zs(n){return{Area:Sh,Line:Ue,Baseline:Mh,Histogram:Ue,Bar:xh,Candlestick:yh,Custom:Eh}[n]}function Eh(n){let t=n.wb;return Object.assign(Object.assign({},n.We),{time:t})}var Ch={vertLine:{color:"#9598A1",width:1,style:3,visible:!0,labelVisible:!0,labelBackgroundColor:"#131722"},horzLine:{color:"#9598A1",width:1,style:3,visible:!0,labelVisible:!0,labelBackgroundColor:"#131722"},mode:1},kh={vertLines:{color:"#D6DCDE",style:0,visible:!0},horzLines:{color:"#D6DCDE",style:0,visible:!0}},zh={background:{type:"solid",color:"#FFFFFF"},textColor:"#191919",fontSize:12,fontFamily:Gs},Ni={autoScale:!0,mode:0,invertScale:!1,alignLabels:!0,borderVisible:!0,borderColor:"#2B2B43",entireTextOnly:!1,visible:!1,ticksVisible:!1,scaleMargins:{bottom:.1,top:.2},minimumWidth:0},Th={rightOffset:0,barSpacing:6,minBarSpacing:.5,fixLeftEdge:!1,fixRightEdge:!1,lockVisibleTimeRangeOnResize:!1,rightBarStaysOnScroll:!1,borderVisible:!0,borderColor:"#2B2B43",visible:!0,timeVisible:!1,secondsVisible:!0,shiftVisibleRangeOnNewBar:!0,allowShiftVisibleRangeOnWhitespaceReplacement:!1,ticksVisible:!1,uniformDistribution:!1,minimumHeight:0,allowBoldLabels:!0},Oh={color:"rgba(0, 0, 0, 0)",visible:!1,fontSize:48,fontFamily:Gs,fontStyle:"",text:"",horzAlign:"center",vertAlign:"center"};function qe(){return{width:0,height:0,autoSize:!1,layout:zh,crosshair:Ch,grid:kh,overlayPriceScales:Object.assign({},Ni),leftPriceScale:Object.assign(Object.assign({},Ni),{visible:!1}),rightPriceScale:Object.assign(Object.assign({},Ni),{visible:!0}),timeScale:Th,watermark:Oh,localization:{locale:at?navigator.language:"",dateFormat:"dd MMM 'yy"},handleScroll:{mouseWheel:!0,pressedMouseMove:!0,horzTouchDrag:!0,vertTouchDrag:!0},handleScale:{axisPressedMouseMove:{time:!0,price:!0},axisDoubleClickReset:{time:!0,price:!0},mouseWheel:!0,pinch:!0},kineticScroll:{mouse:!1,touch:!0},trackingMode:{exitMode:1}}}var Qs=class{constructor(t,i){this.Ub=t,this.qb=i}applyOptions(t){this.Ub.$t().Ic(this.qb,t)}options(){return this.Ei().W()}width(){return ai(this.qb)?this.Ub.sb(this.qb):0}Ei(){return v(this.Ub.$t().Lc(this.qb)).Dt}};function Ze(n,t,i){let s=cn(n,["time","originalTime"]),e=Object.assign({time:t},s);return i!==void 0&&(e.originalTime=i),e}var Lh={color:"#FF0000",price:0,lineStyle:2,lineWidth:1,lineVisible:!0,axisLabelVisible:!0,title:"",axisLabelColor:"",axisLabelTextColor:""},Ys=class{constructor(t){this.Bh=t}applyOptions(t){this.Bh.Lh(t)}options(){return this.Bh.W()}Yb(){return this.Bh}},Xs=class{constructor(t,i,s,e,h){this.Xb=new x,this.zs=t,this.Kb=i,this.Zb=s,this.N_=h,this.Gb=e}S(){this.Xb.S()}priceFormatter(){return this.zs.ca()}priceToCoordinate(t){let i=this.zs.Ct();return i===null?null:this.zs.Dt().Rt(t,i.Ot)}coordinateToPrice(t){let i=this.zs.Ct();return i===null?null:this.zs.Dt().fn(t,i.Ot)}barsInLogicalRange(t){if(t===null)return null;let i=new nt(new q(t.from,t.to)).iu(),s=this.zs.Bn();if(s.Li())return null;let e=s.il(i.Rs(),1),h=s.il(i.ui(),-1),r=v(s.Jh()),o=v(s.An());if(e!==null&&h!==null&&e.ie>h.ie)return{barsBefore:t.from-r,barsAfter:o-t.to};let l={barsBefore:e===null||e.ie===r?t.from-r:e.ie-r,barsAfter:h===null||h.ie===o?o-t.to:o-h.ie};return e!==null&&h!==null&&(l.from=e.wb,l.to=h.wb),l}setData(t){this.N_,this.zs.Yh(),this.Kb.Jb(this.zs,t),this.Qb("full")}update(t){this.zs.Yh(),this.Kb.tw(this.zs,t),this.Qb("update")}dataByIndex(t,i){let s=this.zs.Bn().il(t,i);return s===null?null:Zs(this.seriesType())(s)}data(){let t=Zs(this.seriesType());return this.zs.Bn().Qs().map(i=>t(i))}subscribeDataChanged(t){this.Xb.l(t)}unsubscribeDataChanged(t){this.Xb.v(t)}setMarkers(t){this.N_;let i=t.map(s=>Ze(s,this.N_.convertHorzItemToInternal(s.time),s.time));this.zs.Zl(i)}markers(){return this.zs.Gl().map(t=>Ze(t,t.originalTime,void 0))}applyOptions(t){this.zs.Lh(t)}options(){return W(this.zs.W())}priceScale(){return this.Zb.priceScale(this.zs.Dt().xa())}createPriceLine(t){let i=N(W(Lh),t),s=this.zs.Jl(i);return new Ys(s)}removePriceLine(t){this.zs.Ql(t.Yb())}seriesType(){return this.zs.Yh()}attachPrimitive(t){this.zs.ba(t),t.attached&&t.attached({chart:this.Gb,series:this,requestUpdate:()=>this.zs.$t().$l()})}detachPrimitive(t){this.zs.wa(t),t.detached&&t.detached()}Qb(t){this.Xb.M()&&this.Xb.m(t)}},Js=class{constructor(t,i,s){this.iw=new x,this.uu=new x,this.um=new x,this.Hi=t,this.wl=t.St(),this.Um=i,this.wl.Ku().l(this.nw.bind(this)),this.wl.Zu().l(this.sw.bind(this)),this.Um.Mm().l(this.ew.bind(this)),this.N_=s}S(){this.wl.Ku().p(this),this.wl.Zu().p(this),this.Um.Mm().p(this),this.iw.S(),this.uu.S(),this.um.S()}scrollPosition(){return this.wl.Iu()}scrollToPosition(t,i){i?this.wl.qu(t,1e3):this.Hi.Zn(t)}scrollToRealTime(){this.wl.Uu()}getVisibleRange(){let t=this.wl.ku();return t===null?null:{from:t.from.originalTime,to:t.to.originalTime}}setVisibleRange(t){let i={from:this.N_.convertHorzItemToInternal(t.from),to:this.N_.convertHorzItemToInternal(t.to)},s=this.wl.Ru(i);this.Hi.ad(s)}getVisibleLogicalRange(){let t=this.wl.yu();return t===null?null:{from:t.Rs(),to:t.ui()}}setVisibleLogicalRange(t){I(t.from<=t.to,"The from index cannot be after the to index."),this.Hi.ad(t)}resetTimeScale(){this.Hi.Xn()}fitContent(){this.Hi.Qu()}logicalToCoordinate(t){let i=this.Hi.St();return i.Li()?null:i.zt(t)}coordinateToLogical(t){return this.wl.Li()?null:this.wl.Bu(t)}timeToCoordinate(t){let i=this.N_.convertHorzItemToInternal(t),s=this.wl.ka(i,!1);return s===null?null:this.wl.zt(s)}coordinateToTime(t){let i=this.Hi.St(),s=i.Bu(t),e=i.$i(s);return e===null?null:e.originalTime}width(){return this.Um.Up().width}height(){return this.Um.Up().height}subscribeVisibleTimeRangeChange(t){this.iw.l(t)}unsubscribeVisibleTimeRangeChange(t){this.iw.v(t)}subscribeVisibleLogicalRangeChange(t){this.uu.l(t)}unsubscribeVisibleLogicalRangeChange(t){this.uu.v(t)}subscribeSizeChange(t){this.um.l(t)}unsubscribeSizeChange(t){this.um.v(t)}applyOptions(t){this.wl.Lh(t)}options(){return Object.assign(Object.assign({},W(this.wl.W())),{barSpacing:this.wl.ee()})}nw(){this.iw.M()&&this.iw.m(this.getVisibleRange())}sw(){this.uu.M()&&this.uu.m(this.getVisibleLogicalRange())}ew(t){this.um.m(t.width,t.height)}};function Rh(n){if(n===void 0||n.type==="custom")return;let t=n;t.minMove!==void 0&&t.precision===void 0&&(t.precision=function(i){if(i>=1)return 0;let s=0;for(;s<8;s++){let e=Math.round(i);if(Math.abs(e-i)<1e-8)return s;i*=10}return s}(t.minMove))}function Qe(n){return function(t){if(Rt(t.handleScale)){let s=t.handleScale;t.handleScale={axisDoubleClickReset:{time:s,price:s},axisPressedMouseMove:{time:s,price:s},mouseWheel:s,pinch:s}}else if(t.handleScale!==void 0){let{axisPressedMouseMove:s,axisDoubleClickReset:e}=t.handleScale;Rt(s)&&(t.handleScale.axisPressedMouseMove={time:s,price:s}),Rt(e)&&(t.handleScale.axisDoubleClickReset={time:e,price:e})}let i=t.handleScroll;Rt(i)&&(t.handleScroll={horzTouchDrag:i,vertTouchDrag:i,mouseWheel:i,pressedMouseMove:i})}(n),n}var Ks=class{constructor(t,i,s){this.rw=new Map,this.hw=new Map,this.lw=new x,this.aw=new x,this.ow=new x,this._w=new qs(i);let e=s===void 0?W(qe()):N(W(qe()),Qe(s));this.N_=i,this.Ub=new Us(t,e,i),this.Ub.Wp().l(r=>{this.lw.M()&&this.lw.m(this.uw(r()))},this),this.Ub.jp().l(r=>{this.aw.M()&&this.aw.m(this.uw(r()))},this),this.Ub.jc().l(r=>{this.ow.M()&&this.ow.m(this.uw(r()))},this);let h=this.Ub.$t();this.cw=new Js(h,this.Ub.Gm(),this.N_)}remove(){this.Ub.Wp().p(this),this.Ub.jp().p(this),this.Ub.jc().p(this),this.cw.S(),this.Ub.S(),this.rw.clear(),this.hw.clear(),this.lw.S(),this.aw.S(),this.ow.S(),this._w.S()}resize(t,i,s){this.autoSizeActive()||this.Ub.Ym(t,i,s)}addCustomSeries(t,i){let s=st(t),e=Object.assign(Object.assign({},Ye),s.defaultOptions());return this.dw("Custom",e,i,s)}addAreaSeries(t){return this.dw("Area",Cn,t)}addBaselineSeries(t){return this.dw("Baseline",kn,t)}addBarSeries(t){return this.dw("Bar",yn,t)}addCandlestickSeries(t={}){return function(i){i.borderColor!==void 0&&(i.borderUpColor=i.borderColor,i.borderDownColor=i.borderColor),i.wickColor!==void 0&&(i.wickUpColor=i.wickColor,i.wickDownColor=i.wickColor)}(t),this.dw("Candlestick",xn,t)}addHistogramSeries(t){return this.dw("Histogram",zn,t)}addLineSeries(t){return this.dw("Line",En,t)}removeSeries(t){let i=z(this.rw.get(t)),s=this._w.ld(i);this.Ub.$t().ld(i),this.fw(s),this.rw.delete(t),this.hw.delete(i)}Jb(t,i){this.fw(this._w.Bb(t,i))}tw(t,i){this.fw(this._w.Lb(t,i))}subscribeClick(t){this.lw.l(t)}unsubscribeClick(t){this.lw.v(t)}subscribeCrosshairMove(t){this.ow.l(t)}unsubscribeCrosshairMove(t){this.ow.v(t)}subscribeDblClick(t){this.aw.l(t)}unsubscribeDblClick(t){this.aw.v(t)}priceScale(t){return new Qs(this.Ub,t)}timeScale(){return this.cw}applyOptions(t){this.Ub.Lh(Qe(t))}options(){return this.Ub.W()}takeScreenshot(){return this.Ub.ib()}autoSizeActive(){return this.Ub.hb()}chartElement(){return this.Ub.lb()}paneSize(){let t=this.Ub.ob();return{height:t.height,width:t.width}}setCrosshairPosition(t,i,s){let e=this.rw.get(s);if(e===void 0)return;let h=this.Ub.$t()._r(e);h!==null&&this.Ub.$t().td(t,i,h)}clearCrosshairPosition(){this.Ub.$t().nd(!0)}dw(t,i,s={},e){Rh(s.priceFormat);let h=N(W(Xe),W(i),s),r=this.Ub.$t().rd(t,h,e),o=new Xs(r,this,this,this,this.N_);return this.rw.set(o,r),this.hw.set(r,o),o}fw(t){let i=this.Ub.$t();i.sd(t.St.Au,t.St.Hb,t.St.$b),t.Wb.forEach((s,e)=>e.J(s.We,s.jb)),i.zu()}pw(t){return z(this.hw.get(t))}uw(t){let i=new Map;t.xb.forEach((e,h)=>{let r=h.Yh(),o=Zs(r)(e);if(r!=="Custom")I(function(l){return l.open!==void 0||l.value!==void 0}(o));else{let l=h.Ma();I(!l||l(o)===!1)}i.set(this.pw(h),o)});let s=t.Mb===void 0?void 0:this.pw(t.Mb);return{time:t.wb,logical:t.ie,point:t.gb,hoveredSeries:s,hoveredObjectId:t.Sb,seriesData:i,sourceEvent:t.yb}}};function Nh(n,t,i){let s;if(Tt(n)){let h=document.getElementById(n);I(h!==null,`Cannot find element in DOM with id=${n}`),s=h}else s=n;let e=new Ks(s,t,i);return t.setOptions(e.options()),e}function fn(n,t){return Nh(n,new ni,ni.Pd(t))}var mr=Object.assign(Object.assign({},Xe),Ye);var G=globalThis.getComputedStyle(document.body),D={muted:G.getPropertyValue("--b2"),pink:G.getPropertyValue("--pink"),blue:G.getPropertyValue("--blue"),pinkText:G.getPropertyValue("--pink-text"),blueText:G.getPropertyValue("--blue-text")},Ph={autoSize:!0,handleScale:!1,handleScroll:!1,grid:{vertLines:{visible:!1},horzLines:{visible:!0,color:D.muted}},layout:{background:{type:si.Solid,color:"transparent"},textColor:G.color,fontFamily:G.fontFamily},rightPriceScale:{scaleMargins:{top:0,bottom:0}},timeScale:{lockVisibleTimeRangeOnResize:!0,borderVisible:!1,timeVisible:!0,secondsVisible:!1,fixLeftEdge:!0,fixRightEdge:!0}},ui={baseLineVisible:!1,priceLineVisible:!1,autoscaleInfoProvider:n=>{let t=n();return t!=null&&(t.priceRange.minValue=0,t.priceRange.maxValue*=1.1),t}};function pn(n,t){return fn(Dh(n),{...Ph,...t})}var ci=class n{static numericalKeys=["mean","open","close"];chart;lineSeries;numericalSeries;constructor(t,i={}){this.chart=pn(t,i),this.numericalSeries={},n.numericalKeys.forEach(s=>{this.numericalSeries[s]=this.chart.addLineSeries({...ui,color:D.pinkText,lineType:gt.Curved,lineWidth:1,lineStyle:Ft.Dashed,priceFormat:{type:"custom",formatter:e=>e.toFixed(0)+" pg/mL"}})}),this.lineSeries=this.chart.addLineSeries({...ui,color:D.pink,lineType:gt.Curved,pointMarkersVisible:!1,crosshairMarkerVisible:!1,priceFormat:{type:"custom",formatter:s=>s.toFixed(0)+" pg/mL"}})}update(t,i={}){if(this.lineSeries.setData(t.map(s=>({time:s.t,value:s.v}))),i.quantile&&i.bucketDays){let{quantile:s,bucketDays:e}=i,h=e*24,r=Array.from({length:Math.ceil(t.length/h)},(l,a)=>{let u=a*h,c=Math.min((a+1)*h,t.length),d=t.slice(u,c).map(g=>g.v),f=j(d,s),m=j(d,1-s),p=Math.min(...d),b=Math.max(...d);return{time:t[c-1].t,color:D.blue,wickColor:D.blue,borderColor:D.blue,open:m,close:f,high:b,low:p}});i.onlyLastBucket&&(r=[{...r[r.length-1],time:r[r.length-2].time},{...r[r.length-1]}]);let o=l=>r.map(a=>({time:a.time,value:l(a)}));this.numericalSeries.mean.setData(o(l=>(l.open+l.close)/2)),this.numericalSeries.open.setData(o(l=>l.open)),this.numericalSeries.close.setData(o(l=>l.close))}else Object.values(this.numericalSeries).forEach(s=>s.setData([]));this.chart.timeScale().fitContent()}},mn=class{constructor(t,i,s={}){this.candlestickOpts=i;this.chart=pn(t,s),this.candlestickSeries=this.chart.addCandlestickSeries({...ui,priceFormat:{type:"custom",formatter:h=>h.toFixed(0)+" pg/mL"}});let e={...ui,color:D.blue};this.numericalSeries={mean:this.chart.addLineSeries(e),open:this.chart.addLineSeries(e),close:this.chart.addLineSeries(e)}}chart;candlestickSeries;numericalSeries;update(t){let{quantile:i,quantizeDays:s}=this.candlestickOpts,e=s*24,h=Array.from({length:Math.ceil(t.length/e)},(r,o)=>{let l=o*e,a=Math.min((o+1)*e,t.length),u=(a-l)/2,c=t.slice(l,a).map(b=>b.v),d=j(c,i),f=j(c,1-i),m=Math.min(...c),p=Math.max(...c);return{time:t[l+u].t,color:D.blue,wickColor:D.blue,borderColor:D.blue,open:f,close:d,high:p,low:m}});this.candlestickSeries.setData(h),this.numericalSeries.mean.setData(h.map(r=>({time:r.time,value:(r.open+r.close)/2}))),this.chart.timeScale().fitContent()}};function Dh(n){if(typeof n=="string"){let t=document.querySelector(n);if(!t)throw new Error(`Element not found: ${n}`);return t}return n}var di=class{f;f_0;f_1;constructor(t,i){this.f=s=>(t[s]??0)*i,this.f_0=0,this.f_1=t.length}predict(t,i){if(t.length===0)return Promise.resolve([]);t.sort((c,d)=>c-d);let s=this.f_0,e=this.f_1,h=i.concurrence>0?Math.min(this.f_1,i.intervalHours*i.concurrence):this.f_1;console.debug("predictor:",{f_0:s,f_1:e,f_1short:h});let r=Date.now()/1e3,o=r-i.hoursAgo*3600;console.debug("predictor: from",new Date(o*1e3).toISOString(),"to",new Date(r*1e3).toISOString());let l=Math.ceil((r-o)/3600);console.debug("predictor: hours",l);let a=Array(l).fill(0);console.debug("values",a);for(let[c,d]of t.entries()){let f=Math.floor((d-o)/3600)+s,m=f+(t.length-c<=i.concurrence?e:h);if(console.debug("for",d,"going from",f,"to",m,"of",a.length,"hours"),m<0)continue;let p=0;f<0&&(p=-f,f=0,m=Math.min(a.length,m-f)),f=Math.max(0,f),m=Math.min(a.length,m),console.debug("for",d,"total hours taken is",m-f);for(let b=f;b<m;b++)a[b]+=this.f(b-f+p)}console.debug("values",a);let u=a.map((c,d)=>({t:o+d*3600,v:c}));return Promise.resolve(u)}};var vn=[0,8.5,17,25.5,34,47.7,59.6,71.4,83.3,88.6,93.8,99,102.7,103.2,103.8,104.3,104.8,105.3,105.9,106.4,106.9,107.4,108,108.5,108.1,108.1,108.1,108.1,108.1,108.1,109.2,110.3,111.4,112.5,113.6,114.7,114.4,111.9,109.4,106.9,104.4,101.9,99.4,96.9,94.4,91.9,89.4,86.9,84.9,84.4,84,83.6,83.2,82.8,82.4,81.9,81.5,81.1,80.7,80.3,79.9,78.3,76.8,75.3,73.7,72.2,70.7,69.2,67.6,66.1,64.6,63.1,61.5,61.4,61.3,61.1,61,60.8,60.7,60.6,60.4,60.3,60.1,60,59.9,59.4,59,58.6,58.2,57.8,57.4,56.9,56.5,56.1,55.7,55.3,54.9,54.4,54,53.6,53.2,52.8,52.4,51.9,51.5,51.1,50.7,50.3,49.9,49.4,49,48.6,48.2,47.8,47.4,46.9,46.5,46.1,45.7,45.3,44.9,44.4,44,43.6,43.2,42.8,42.4,41.9,41.5,41.1,40.7,40.3,39.9,39.4,39,38.6,38.2,37.8,37.4,36.9,36.5,36.1,35.7,35.3,34.9,34.4,34,33.6,33.2,32.8,32.4,31.9,31.5,31.1,30.7,30.3,29.9,29.4,29,28.6,28.2,27.8,27.4,26.9,26.5,26.1,25.7,25.3,24.9];var Ah=vn,Hh=1,bn=new di(Ah,Hh);async function $h(){let n;switch(window.config.Type){case"patches":{let e=window.dosageHistory.filter(h=>h.HRTType=="patches").map(h=>Date.parse(h.DosageAt)/1e3);n=await bn.predict(e,{intervalHours:window.config.IntervalHours,concurrence:window.config.Concurrence,hoursAgo:24*30});break}default:{console.log("not drawing chart due to unknown type",window.config.Type);return}}let t=Fh(document.body,"#dosage-stats"),i=[];gn(t,"[data-for-days]").forEach(e=>{let h=e.dataset.name,r=parseInt(e.dataset.forDays),o=gn(e,"span[data-quantile]").map(a=>a.dataset.quantile).map(a=>parseFloat(a)/100),l=ae(n,r,o);i.push({name:h,forDays:r,...l}),ee(e,"span[data-value='mean']",l.mean),ee(e,"span[data-value='stddev']",l.stdDev);for(let[a,u]of Object.entries(l.quantiles))ee(e,`span[data-value='quantile'][data-quantile='${parseFloat(a)*100}']`,u)}),new ci("#history-line").update(n,{quantile:.95,bucketDays:window.config.IntervalHours*window.config.Concurrence/24,onlyLastBucket:!0});let s=document.getElementById("dosage-details");s.style.display="block"}function Ih(n){return n.toFixed(1)}function gn(n,t){return[...n.querySelectorAll(t)].filter(i=>i instanceof HTMLElement)}function Fh(n,t){let i=n.querySelector(t);return(!i||!(i instanceof HTMLElement))&&(console.error("Element not found",t),alert("Cannot render completely. Check console.")),i}function ee(n,t,i){let s=n.querySelector(t);if(!s){console.error("Element not found",t),alert("Cannot render completely. Check console.");return}let e="";switch(typeof i){case"string":e=i;break;case"number":e=Ih(i);break;default:e=i.toString();break}s.textContent=e}await $h();export{di as DiscreteHourlyPredictor,mn as EstrogenCandlesticks,ci as EstrogenLine,H as average,ae as calculate,D as colors,Gh as correlation,le as covariance,jh as median,Uh as mode,bn as patchesPredictor,ir as percentile,j as quartile,qh as range,hr as skewness,F as stdDev,_n as variance};
+function lt(n,t){if(n.length==0||t<0||t>1)return NaN;let i=[...n].sort((h,r)=>h-r),s=t*(i.length-1),e=Math.floor(s);return e+1>=i.length?i[i.length-1]:i[e]+(s-e)*(i[e+1]-i[e])}var gn={upColor:"#26a69a",downColor:"#ef5350",wickVisible:!0,borderVisible:!0,borderColor:"#378658",borderUpColor:"#26a69a",borderDownColor:"#ef5350",wickColor:"#737375",wickUpColor:"#26a69a",wickDownColor:"#ef5350"},wn={upColor:"#26a69a",downColor:"#ef5350",openVisible:!0,thinBars:!0},_n={color:"#2196f3",lineStyle:0,lineWidth:3,lineType:0,lineVisible:!0,crosshairMarkerVisible:!0,crosshairMarkerRadius:4,crosshairMarkerBorderColor:"",crosshairMarkerBorderWidth:2,crosshairMarkerBackgroundColor:"",lastPriceAnimation:0,pointMarkersVisible:!1},Sn={topColor:"rgba( 46, 220, 135, 0.4)",bottomColor:"rgba( 40, 221, 100, 0)",invertFilledArea:!1,lineColor:"#33D778",lineStyle:0,lineWidth:3,lineType:0,lineVisible:!0,crosshairMarkerVisible:!0,crosshairMarkerRadius:4,crosshairMarkerBorderColor:"",crosshairMarkerBorderWidth:2,crosshairMarkerBackgroundColor:"",lastPriceAnimation:0,pointMarkersVisible:!1},Mn={baseValue:{type:"price",price:0},topFillColor1:"rgba(38, 166, 154, 0.28)",topFillColor2:"rgba(38, 166, 154, 0.05)",topLineColor:"rgba(38, 166, 154, 1)",bottomFillColor1:"rgba(239, 83, 80, 0.05)",bottomFillColor2:"rgba(239, 83, 80, 0.28)",bottomLineColor:"rgba(239, 83, 80, 1)",lineWidth:3,lineStyle:0,lineType:0,lineVisible:!0,crosshairMarkerVisible:!0,crosshairMarkerRadius:4,crosshairMarkerBorderColor:"",crosshairMarkerBorderWidth:2,crosshairMarkerBackgroundColor:"",lastPriceAnimation:0,pointMarkersVisible:!1},yn={color:"#26a69a",base:0},Ye={color:"#2196f3"},Qe={title:"",visible:!0,lastValueVisible:!0,priceLineVisible:!0,priceLineSource:0,priceLineWidth:1,priceLineColor:"",priceLineStyle:2,baseLineVisible:!0,baseLineWidth:1,baseLineColor:"#B2B5BE",baseLineStyle:0,priceFormat:{type:"price",precision:2,minMove:.01}},vt,$t;function U(n,t){let i={0:[],1:[n.lineWidth,n.lineWidth],2:[2*n.lineWidth,2*n.lineWidth],3:[6*n.lineWidth,6*n.lineWidth],4:[n.lineWidth,4*n.lineWidth]}[t];n.setLineDash(i)}function qe(n,t,i,s){n.beginPath();let e=n.lineWidth%2?.5:0;n.moveTo(i,t+e),n.lineTo(s,t+e),n.stroke()}function I(n,t){if(!n)throw new Error("Assertion failed"+(t?": "+t:""))}function z(n){if(n===void 0)throw new Error("Value is undefined");return n}function v(n){if(n===null)throw new Error("Value is null");return n}function G(n){return v(z(n))}(function(n){n[n.Simple=0]="Simple",n[n.WithSteps=1]="WithSteps",n[n.Curved=2]="Curved"})(vt||(vt={})),function(n){n[n.Solid=0]="Solid",n[n.Dotted=1]="Dotted",n[n.Dashed=2]="Dashed",n[n.LargeDashed=3]="LargeDashed",n[n.SparseDotted=4]="SparseDotted"}($t||($t={}));var le={khaki:"#f0e68c",azure:"#f0ffff",aliceblue:"#f0f8ff",ghostwhite:"#f8f8ff",gold:"#ffd700",goldenrod:"#daa520",gainsboro:"#dcdcdc",gray:"#808080",green:"#008000",honeydew:"#f0fff0",floralwhite:"#fffaf0",lightblue:"#add8e6",lightcoral:"#f08080",lemonchiffon:"#fffacd",hotpink:"#ff69b4",lightyellow:"#ffffe0",greenyellow:"#adff2f",lightgoldenrodyellow:"#fafad2",limegreen:"#32cd32",linen:"#faf0e6",lightcyan:"#e0ffff",magenta:"#f0f",maroon:"#800000",olive:"#808000",orange:"#ffa500",oldlace:"#fdf5e6",mediumblue:"#0000cd",transparent:"#0000",lime:"#0f0",lightpink:"#ffb6c1",mistyrose:"#ffe4e1",moccasin:"#ffe4b5",midnightblue:"#191970",orchid:"#da70d6",mediumorchid:"#ba55d3",mediumturquoise:"#48d1cc",orangered:"#ff4500",royalblue:"#4169e1",powderblue:"#b0e0e6",red:"#f00",coral:"#ff7f50",turquoise:"#40e0d0",white:"#fff",whitesmoke:"#f5f5f5",wheat:"#f5deb3",teal:"#008080",steelblue:"#4682b4",bisque:"#ffe4c4",aquamarine:"#7fffd4",aqua:"#0ff",sienna:"#a0522d",silver:"#c0c0c0",springgreen:"#00ff7f",antiquewhite:"#faebd7",burlywood:"#deb887",brown:"#a52a2a",beige:"#f5f5dc",chocolate:"#d2691e",chartreuse:"#7fff00",cornflowerblue:"#6495ed",cornsilk:"#fff8dc",crimson:"#dc143c",cadetblue:"#5f9ea0",tomato:"#ff6347",fuchsia:"#f0f",blue:"#00f",salmon:"#fa8072",blanchedalmond:"#ffebcd",slateblue:"#6a5acd",slategray:"#708090",thistle:"#d8bfd8",tan:"#d2b48c",cyan:"#0ff",darkblue:"#00008b",darkcyan:"#008b8b",darkgoldenrod:"#b8860b",darkgray:"#a9a9a9",blueviolet:"#8a2be2",black:"#000",darkmagenta:"#8b008b",darkslateblue:"#483d8b",darkkhaki:"#bdb76b",darkorchid:"#9932cc",darkorange:"#ff8c00",darkgreen:"#006400",darkred:"#8b0000",dodgerblue:"#1e90ff",darkslategray:"#2f4f4f",dimgray:"#696969",deepskyblue:"#00bfff",firebrick:"#b22222",forestgreen:"#228b22",indigo:"#4b0082",ivory:"#fffff0",lavenderblush:"#fff0f5",feldspar:"#d19275",indianred:"#cd5c5c",lightgreen:"#90ee90",lightgrey:"#d3d3d3",lightskyblue:"#87cefa",lightslategray:"#789",lightslateblue:"#8470ff",snow:"#fffafa",lightseagreen:"#20b2aa",lightsalmon:"#ffa07a",darksalmon:"#e9967a",darkviolet:"#9400d3",mediumpurple:"#9370d8",mediumaquamarine:"#66cdaa",skyblue:"#87ceeb",lavender:"#e6e6fa",lightsteelblue:"#b0c4de",mediumvioletred:"#c71585",mintcream:"#f5fffa",navajowhite:"#ffdead",navy:"#000080",olivedrab:"#6b8e23",palevioletred:"#d87093",violetred:"#d02090",yellow:"#ff0",yellowgreen:"#9acd32",lawngreen:"#7cfc00",pink:"#ffc0cb",paleturquoise:"#afeeee",palegoldenrod:"#eee8aa",darkolivegreen:"#556b2f",darkseagreen:"#8fbc8f",darkturquoise:"#00ced1",peachpuff:"#ffdab9",deeppink:"#ff1493",violet:"#ee82ee",palegreen:"#98fb98",mediumseagreen:"#3cb371",peru:"#cd853f",saddlebrown:"#8b4513",sandybrown:"#f4a460",rosybrown:"#bc8f8f",purple:"#800080",seagreen:"#2e8b57",seashell:"#fff5ee",papayawhip:"#ffefd5",mediumslateblue:"#7b68ee",plum:"#dda0dd",mediumspringgreen:"#00fa9a"};function L(n){return n<0?0:n>255?255:Math.round(n)||0}function Xe(n){return n<=0||n>0?n<0?0:n>1?1:Math.round(1e4*n)/1e4:0}var xn=/^#([0-9a-f])([0-9a-f])([0-9a-f])([0-9a-f])?$/i,En=/^#([0-9a-f]{2})([0-9a-f]{2})([0-9a-f]{2})([0-9a-f]{2})?$/i,Cn=/^rgb\(\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*\)$/,kn=/^rgba\(\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*,\s*(-?\d{1,10})\s*,\s*(-?[\d]{0,10}(?:\.\d+)?)\s*\)$/;function Ft(n){(n=n.toLowerCase())in le&&(n=le[n]);{let t=kn.exec(n)||Cn.exec(n);if(t)return[L(parseInt(t[1],10)),L(parseInt(t[2],10)),L(parseInt(t[3],10)),Xe(t.length<5?1:parseFloat(t[4]))]}{let t=En.exec(n);if(t)return[L(parseInt(t[1],16)),L(parseInt(t[2],16)),L(parseInt(t[3],16)),1]}{let t=xn.exec(n);if(t)return[L(17*parseInt(t[1],16)),L(17*parseInt(t[2],16)),L(17*parseInt(t[3],16)),1]}throw new Error(`Cannot parse color: ${n}`)}function ri(n){let t=Ft(n);return{t:`rgb(${t[0]}, ${t[1]}, ${t[2]})`,i:(i=t,.199*i[0]+.687*i[1]+.114*i[2]>160?"black":"white")};var i}var y=class{constructor(){this.h=[]}l(t,i,s){let e={o:t,_:i,u:s===!0};this.h.push(e)}v(t){let i=this.h.findIndex(s=>t===s.o);i>-1&&this.h.splice(i,1)}p(t){this.h=this.h.filter(i=>i._!==t)}m(t,i,s){let e=[...this.h];this.h=this.h.filter(h=>!h.u),e.forEach(h=>h.o(t,i,s))}M(){return this.h.length>0}S(){this.h=[]}};function N(n,...t){for(let i of t)for(let s in i)i[s]!==void 0&&(typeof i[s]!="object"||n[s]===void 0||Array.isArray(i[s])?n[s]=i[s]:N(n[s],i[s]));return n}function D(n){return typeof n=="number"&&isFinite(n)}function bt(n){return typeof n=="number"&&n%1==0}function kt(n){return typeof n=="string"}function Lt(n){return typeof n=="boolean"}function A(n){let t=n;if(!t||typeof t!="object")return t;let i,s,e;for(s in i=Array.isArray(t)?[]:{},t)t.hasOwnProperty(s)&&(e=t[s],i[s]=e&&typeof e=="object"?A(e):e);return i}function zn(n){return n!==null}function gt(n){return n===null?void 0:n}var Ks="-apple-system, BlinkMacSystemFont, 'Trebuchet MS', Roboto, Ubuntu, sans-serif";function st(n,t,i){return t===void 0&&(t=Ks),`${i=i!==void 0?`${i} `:""}${n}px ${t}`}var Ni=class{constructor(t){this.k={C:1,T:5,P:NaN,R:"",D:"",O:"",A:"",B:0,V:0,I:0,L:0,N:0},this.F=t}W(){let t=this.k,i=this.j(),s=this.H();return t.P===i&&t.D===s||(t.P=i,t.D=s,t.R=st(i,s),t.L=2.5/12*i,t.B=t.L,t.V=i/12*t.T,t.I=i/12*t.T,t.N=0),t.O=this.$(),t.A=this.U(),this.k}$(){return this.F.W().layout.textColor}U(){return this.F.q()}j(){return this.F.W().layout.fontSize}H(){return this.F.W().layout.fontFamily}},wt=class{constructor(){this.Y=[]}X(t){this.Y=t}K(t,i,s){this.Y.forEach(e=>{e.K(t,i,s)})}},T=class{K(t,i,s){t.useBitmapCoordinateSpace(e=>this.Z(e,i,s))}},Bi=class extends T{constructor(){super(...arguments),this.G=null}J(t){this.G=t}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){if(this.G===null||this.G.tt===null)return;let e=this.G.tt,h=this.G,r=Math.max(1,Math.floor(i))%2/2,o=l=>{t.beginPath();for(let a=e.to-1;a>=e.from;--a){let u=h.it[a],c=Math.round(u.nt*i)+r,d=u.st*s,f=l*s+r;t.moveTo(c,d),t.arc(c,d,f,0,2*Math.PI)}t.fill()};h.et>0&&(t.fillStyle=h.rt,o(h.ht+h.et)),t.fillStyle=h.lt,o(h.ht)}};function Tn(){return{it:[{nt:0,st:0,ot:0,_t:0}],lt:"",rt:"",ht:0,et:0,tt:null}}var On={from:0,to:1},Pi=class{constructor(t,i){this.ut=new wt,this.ct=[],this.dt=[],this.ft=!0,this.F=t,this.vt=i,this.ut.X(this.ct)}bt(t){let i=this.F.wt();i.length!==this.ct.length&&(this.dt=i.map(Tn),this.ct=this.dt.map(s=>{let e=new Bi;return e.J(s),e}),this.ut.X(this.ct)),this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.ut}Mt(){let t=this.vt.W().mode===2,i=this.F.wt(),s=this.vt.xt(),e=this.F.St();i.forEach((h,r)=>{var o;let l=this.dt[r],a=h.yt(s);if(t||a===null||!h.kt())return void(l.tt=null);let u=v(h.Ct());l.lt=a.Tt,l.ht=a.ht,l.et=a.Pt,l.it[0]._t=a._t,l.it[0].st=h.Dt().Rt(a._t,u.Ot),l.rt=(o=a.At)!==null&&o!==void 0?o:this.F.Bt(l.it[0].st/h.Dt().Vt()),l.it[0].ot=s,l.it[0].nt=e.zt(s),l.tt=On})}},Di=class extends T{constructor(t){super(),this.Et=t}Z({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(this.Et===null)return;let h=this.Et.It.kt,r=this.Et.Lt.kt;if(!h&&!r)return;let o=Math.round(this.Et.nt*s),l=Math.round(this.Et.st*e);t.lineCap="butt",h&&o>=0&&(t.lineWidth=Math.floor(this.Et.It.et*s),t.strokeStyle=this.Et.It.O,t.fillStyle=this.Et.It.O,U(t,this.Et.It.Nt),function(a,u,c,d){a.beginPath();let f=a.lineWidth%2?.5:0;a.moveTo(u+f,c),a.lineTo(u+f,d),a.stroke()}(t,o,0,i.height)),r&&l>=0&&(t.lineWidth=Math.floor(this.Et.Lt.et*e),t.strokeStyle=this.Et.Lt.O,t.fillStyle=this.Et.Lt.O,U(t,this.Et.Lt.Nt),qe(t,l,0,i.width))}},Ai=class{constructor(t){this.ft=!0,this.Ft={It:{et:1,Nt:0,O:"",kt:!1},Lt:{et:1,Nt:0,O:"",kt:!1},nt:0,st:0},this.Wt=new Di(this.Ft),this.jt=t}bt(){this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.Wt}Mt(){let t=this.jt.kt(),i=v(this.jt.Ht()),s=i.$t().W().crosshair,e=this.Ft;if(s.mode===2)return e.Lt.kt=!1,void(e.It.kt=!1);e.Lt.kt=t&&this.jt.Ut(i),e.It.kt=t&&this.jt.qt(),e.Lt.et=s.horzLine.width,e.Lt.Nt=s.horzLine.style,e.Lt.O=s.horzLine.color,e.It.et=s.vertLine.width,e.It.Nt=s.vertLine.style,e.It.O=s.vertLine.color,e.nt=this.jt.Yt(),e.st=this.jt.Xt()}};function Ln(n,t,i,s,e,h){n.fillRect(t+h,i,s-2*h,h),n.fillRect(t+h,i+e-h,s-2*h,h),n.fillRect(t,i,h,e),n.fillRect(t+s-h,i,h,e)}function oi(n,t,i,s,e,h){n.save(),n.globalCompositeOperation="copy",n.fillStyle=h,n.fillRect(t,i,s,e),n.restore()}function ae(n,t){return n.map(i=>i===0?i:i+t)}function vi(n,t,i,s,e,h){n.beginPath(),n.lineTo(t+s-h[1],i),h[1]!==0&&n.arcTo(t+s,i,t+s,i+h[1],h[1]),n.lineTo(t+s,i+e-h[2]),h[2]!==0&&n.arcTo(t+s,i+e,t+s-h[2],i+e,h[2]),n.lineTo(t+h[3],i+e),h[3]!==0&&n.arcTo(t,i+e,t,i+e-h[3],h[3]),n.lineTo(t,i+h[0]),h[0]!==0&&n.arcTo(t,i,t+h[0],i,h[0])}function ue(n,t,i,s,e,h,r=0,o=[0,0,0,0],l=""){if(n.save(),!r||!l||l===h)return vi(n,t,i,s,e,o),n.fillStyle=h,n.fill(),void n.restore();let a=r/2;h!=="transparent"&&(vi(n,t+r,i+r,s-2*r,e-2*r,ae(o,-r)),n.fillStyle=h,n.fill()),l!=="transparent"&&(vi(n,t+a,i+a,s-r,e-r,ae(o,-a)),n.lineWidth=r,n.strokeStyle=l,n.closePath(),n.stroke()),n.restore()}function Je(n,t,i,s,e,h,r){n.save(),n.globalCompositeOperation="copy";let o=n.createLinearGradient(0,0,0,e);o.addColorStop(0,h),o.addColorStop(1,r),n.fillStyle=o,n.fillRect(t,i,s,e),n.restore()}var jt=class{constructor(t,i){this.J(t,i)}J(t,i){this.Et=t,this.Kt=i}Vt(t,i){return this.Et.kt?t.P+t.L+t.B:0}K(t,i,s,e){if(!this.Et.kt||this.Et.Zt.length===0)return;let h=this.Et.O,r=this.Kt.t,o=t.useBitmapCoordinateSpace(l=>{let a=l.context;a.font=i.R;let u=this.Gt(l,i,s,e),c=u.Jt,d=(f,p)=>{u.Qt?ue(a,c.ti,c.ii,c.ni,c.si,f,c.ei,[c.ht,0,0,c.ht],p):ue(a,c.ri,c.ii,c.ni,c.si,f,c.ei,[0,c.ht,c.ht,0],p)};return d(r,"transparent"),this.Et.hi&&(a.fillStyle=h,a.fillRect(c.ri,c.li,c.ai-c.ri,c.oi)),d("transparent",r),this.Et._i&&(a.fillStyle=i.A,a.fillRect(u.Qt?c.ui-c.ei:0,c.ii,c.ei,c.ci-c.ii)),u});t.useMediaCoordinateSpace(({context:l})=>{let a=o.di;l.font=i.R,l.textAlign=o.Qt?"right":"left",l.textBaseline="middle",l.fillStyle=h,l.fillText(this.Et.Zt,a.fi,(a.ii+a.ci)/2+a.vi)})}Gt(t,i,s,e){var h;let{context:r,bitmapSize:o,mediaSize:l,horizontalPixelRatio:a,verticalPixelRatio:u}=t,c=this.Et.hi||!this.Et.pi?i.T:0,d=this.Et.mi?i.C:0,f=i.L+this.Kt.bi,p=i.B+this.Kt.wi,m=i.V,b=i.I,g=this.Et.Zt,w=i.P,S=s.gi(r,g),_=Math.ceil(s.Mi(r,g)),x=w+f+p,O=i.C+m+b+_+c,k=Math.max(1,Math.floor(u)),E=Math.round(x*u);E%2!=k%2&&(E+=1);let V=d>0?Math.max(1,Math.floor(d*a)):0,J=Math.round(O*a),ne=Math.round(c*a),vn=(h=this.Kt.xi)!==null&&h!==void 0?h:this.Kt.Si,he=Math.round(vn*u)-Math.floor(.5*u),di=Math.floor(he+k/2-E/2),re=di+E,Ot=e==="right",oe=Ot?l.width-d:d,ot=Ot?o.width-V:V,fi,mi,pi;return Ot?(fi=ot-J,mi=ot-ne,pi=oe-c-m-d):(fi=ot+J,mi=ot+ne,pi=oe+c+m),{Qt:Ot,Jt:{ii:di,li:he,ci:re,ni:J,si:E,ht:2*a,ei:V,ti:fi,ri:ot,ai:mi,oi:k,ui:o.width},di:{ii:di/u,ci:re/u,fi:pi,vi:S}}}},et=class{constructor(t){this.yi={Si:0,t:"#000",wi:0,bi:0},this.ki={Zt:"",kt:!1,hi:!0,pi:!1,At:"",O:"#FFF",_i:!1,mi:!1},this.Ci={Zt:"",kt:!1,hi:!1,pi:!0,At:"",O:"#FFF",_i:!0,mi:!0},this.ft=!0,this.Ti=new(t||jt)(this.ki,this.yi),this.Pi=new(t||jt)(this.Ci,this.yi)}Zt(){return this.Ri(),this.ki.Zt}Si(){return this.Ri(),this.yi.Si}bt(){this.ft=!0}Vt(t,i=!1){return Math.max(this.Ti.Vt(t,i),this.Pi.Vt(t,i))}Di(){return this.yi.xi||0}Oi(t){this.yi.xi=t}Ai(){return this.Ri(),this.ki.kt||this.Ci.kt}Bi(){return this.Ri(),this.ki.kt}gt(t){return this.Ri(),this.ki.hi=this.ki.hi&&t.W().ticksVisible,this.Ci.hi=this.Ci.hi&&t.W().ticksVisible,this.Ti.J(this.ki,this.yi),this.Pi.J(this.Ci,this.yi),this.Ti}Vi(){return this.Ri(),this.Ti.J(this.ki,this.yi),this.Pi.J(this.Ci,this.yi),this.Pi}Ri(){this.ft&&(this.ki.hi=!0,this.Ci.hi=!1,this.zi(this.ki,this.Ci,this.yi))}},Wi=class extends et{constructor(t,i,s){super(),this.jt=t,this.Ei=i,this.Ii=s}zi(t,i,s){if(t.kt=!1,this.jt.W().mode===2)return;let e=this.jt.W().horzLine;if(!e.labelVisible)return;let h=this.Ei.Ct();if(!this.jt.kt()||this.Ei.Li()||h===null)return;let r=ri(e.labelBackgroundColor);s.t=r.t,t.O=r.i;let o=2/12*this.Ei.P();s.bi=o,s.wi=o;let l=this.Ii(this.Ei);s.Si=l.Si,t.Zt=this.Ei.Ni(l._t,h),t.kt=!0}},Rn=/[1-9]/g,Ut=class{constructor(){this.Et=null}J(t){this.Et=t}K(t,i){if(this.Et===null||this.Et.kt===!1||this.Et.Zt.length===0)return;let s=t.useMediaCoordinateSpace(({context:d})=>(d.font=i.R,Math.round(i.Fi.Mi(d,v(this.Et).Zt,Rn))));if(s<=0)return;let e=i.Wi,h=s+2*e,r=h/2,o=this.Et.ji,l=this.Et.Si,a=Math.floor(l-r)+.5;a<0?(l+=Math.abs(0-a),a=Math.floor(l-r)+.5):a+h>o&&(l-=Math.abs(o-(a+h)),a=Math.floor(l-r)+.5);let u=a+h,c=Math.ceil(0+i.C+i.T+i.L+i.P+i.B);t.useBitmapCoordinateSpace(({context:d,horizontalPixelRatio:f,verticalPixelRatio:p})=>{let m=v(this.Et);d.fillStyle=m.t;let b=Math.round(a*f),g=Math.round(0*p),w=Math.round(u*f),S=Math.round(c*p),_=Math.round(2*f);if(d.beginPath(),d.moveTo(b,g),d.lineTo(b,S-_),d.arcTo(b,S,b+_,S,_),d.lineTo(w-_,S),d.arcTo(w,S,w,S-_,_),d.lineTo(w,g),d.fill(),m.hi){let x=Math.round(m.Si*f),O=g,k=Math.round((O+i.T)*p);d.fillStyle=m.O;let E=Math.max(1,Math.floor(f)),V=Math.floor(.5*f);d.fillRect(x-V,O,E,k-O)}}),t.useMediaCoordinateSpace(({context:d})=>{let f=v(this.Et),p=0+i.C+i.T+i.L+i.P/2;d.font=i.R,d.textAlign="left",d.textBaseline="middle",d.fillStyle=f.O;let m=i.Fi.gi(d,"Apr0");d.translate(a+e,p+m),d.fillText(f.Zt,0,0)})}},Vi=class{constructor(t,i,s){this.ft=!0,this.Wt=new Ut,this.Ft={kt:!1,t:"#4c525e",O:"white",Zt:"",ji:0,Si:NaN,hi:!0},this.vt=t,this.Hi=i,this.Ii=s}bt(){this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.Wt.J(this.Ft),this.Wt}Mt(){let t=this.Ft;if(t.kt=!1,this.vt.W().mode===2)return;let i=this.vt.W().vertLine;if(!i.labelVisible)return;let s=this.Hi.St();if(s.Li())return;t.ji=s.ji();let e=this.Ii();if(e===null)return;t.Si=e.Si;let h=s.$i(this.vt.xt());t.Zt=s.Ui(v(h)),t.kt=!0;let r=ri(i.labelBackgroundColor);t.t=r.t,t.O=r.i,t.hi=s.W().ticksVisible}},_t=class{constructor(){this.qi=null,this.Yi=0}Xi(){return this.Yi}Ki(t){this.Yi=t}Dt(){return this.qi}Zi(t){this.qi=t}Gi(t){return[]}Ji(){return[]}kt(){return!0}},ce;(function(n){n[n.Normal=0]="Normal",n[n.Magnet=1]="Magnet",n[n.Hidden=2]="Hidden"})(ce||(ce={}));var Hi=class extends _t{constructor(t,i){super(),this.Qi=null,this.tn=NaN,this.nn=0,this.sn=!0,this.en=new Map,this.rn=!1,this.hn=NaN,this.ln=NaN,this.an=NaN,this.on=NaN,this.Hi=t,this._n=i,this.un=new Pi(t,this),this.cn=((e,h)=>r=>{let o=h(),l=e();if(r===v(this.Qi).dn())return{_t:l,Si:o};{let a=v(r.Ct());return{_t:r.fn(o,a),Si:o}}})(()=>this.tn,()=>this.ln);let s=((e,h)=>()=>{let r=this.Hi.St().vn(e()),o=h();return r&&Number.isFinite(o)?{ot:r,Si:o}:null})(()=>this.nn,()=>this.Yt());this.pn=new Vi(this,t,s),this.mn=new Ai(this)}W(){return this._n}bn(t,i){this.an=t,this.on=i}wn(){this.an=NaN,this.on=NaN}gn(){return this.an}Mn(){return this.on}xn(t,i,s){this.rn||(this.rn=!0),this.sn=!0,this.Sn(t,i,s)}xt(){return this.nn}Yt(){return this.hn}Xt(){return this.ln}kt(){return this.sn}yn(){this.sn=!1,this.kn(),this.tn=NaN,this.hn=NaN,this.ln=NaN,this.Qi=null,this.wn()}Cn(t){return this.Qi!==null?[this.mn,this.un]:[]}Ut(t){return t===this.Qi&&this._n.horzLine.visible}qt(){return this._n.vertLine.visible}Tn(t,i){this.sn&&this.Qi===t||this.en.clear();let s=[];return this.Qi===t&&s.push(this.Pn(this.en,i,this.cn)),s}Ji(){return this.sn?[this.pn]:[]}Ht(){return this.Qi}Rn(){this.mn.bt(),this.en.forEach(t=>t.bt()),this.pn.bt(),this.un.bt()}Dn(t){return t&&!t.dn().Li()?t.dn():null}Sn(t,i,s){this.On(t,i,s)&&this.Rn()}On(t,i,s){let e=this.hn,h=this.ln,r=this.tn,o=this.nn,l=this.Qi,a=this.Dn(s);this.nn=t,this.hn=isNaN(t)?NaN:this.Hi.St().zt(t),this.Qi=s;let u=a!==null?a.Ct():null;return a!==null&&u!==null?(this.tn=i,this.ln=a.Rt(i,u)):(this.tn=NaN,this.ln=NaN),e!==this.hn||h!==this.ln||o!==this.nn||r!==this.tn||l!==this.Qi}kn(){let t=this.Hi.wt().map(s=>s.Bn().An()).filter(zn),i=t.length===0?null:Math.max(...t);this.nn=i!==null?i:NaN}Pn(t,i,s){let e=t.get(i);return e===void 0&&(e=new Wi(this,i,s),t.set(i,e)),e}};function li(n){return n==="left"||n==="right"}var C=class n{constructor(t){this.Vn=new Map,this.zn=[],this.En=t}In(t,i){let s=function(e,h){return e===void 0?h:{Ln:Math.max(e.Ln,h.Ln),Nn:e.Nn||h.Nn}}(this.Vn.get(t),i);this.Vn.set(t,s)}Fn(){return this.En}Wn(t){let i=this.Vn.get(t);return i===void 0?{Ln:this.En}:{Ln:Math.max(this.En,i.Ln),Nn:i.Nn}}jn(){this.Hn(),this.zn=[{$n:0}]}Un(t){this.Hn(),this.zn=[{$n:1,Ot:t}]}qn(t){this.Yn(),this.zn.push({$n:5,Ot:t})}Hn(){this.Yn(),this.zn.push({$n:6})}Xn(){this.Hn(),this.zn=[{$n:4}]}Kn(t){this.Hn(),this.zn.push({$n:2,Ot:t})}Zn(t){this.Hn(),this.zn.push({$n:3,Ot:t})}Gn(){return this.zn}Jn(t){for(let i of t.zn)this.Qn(i);this.En=Math.max(this.En,t.En),t.Vn.forEach((i,s)=>{this.In(s,i)})}static ts(){return new n(2)}static ns(){return new n(3)}Qn(t){switch(t.$n){case 0:this.jn();break;case 1:this.Un(t.Ot);break;case 2:this.Kn(t.Ot);break;case 3:this.Zn(t.Ot);break;case 4:this.Xn();break;case 5:this.qn(t.Ot);break;case 6:this.Yn()}}Yn(){let t=this.zn.findIndex(i=>i.$n===5);t!==-1&&this.zn.splice(t,1)}},de=".";function W(n,t){if(!D(n))return"n/a";if(!bt(t))throw new TypeError("invalid length");if(t<0||t>16)throw new TypeError("invalid length");return t===0?n.toString():("0000000000000000"+n.toString()).slice(-t)}var nt=class{constructor(t,i){if(i||(i=1),D(t)&&bt(t)||(t=100),t<0)throw new TypeError("invalid base");this.Ei=t,this.ss=i,this.es()}format(t){let i=t<0?"\u2212":"";return t=Math.abs(t),i+this.rs(t)}es(){if(this.hs=0,this.Ei>0&&this.ss>0){let t=this.Ei;for(;t>1;)t/=10,this.hs++}}rs(t){let i=this.Ei/this.ss,s=Math.floor(t),e="",h=this.hs!==void 0?this.hs:NaN;if(i>1){let r=+(Math.round(t*i)-s*i).toFixed(this.hs);r>=i&&(r-=i,s+=1),e=de+W(+r.toFixed(this.hs)*this.ss,h)}else s=Math.round(s*i)/i,h>0&&(e=de+W(0,h));return s.toFixed(0)+e}},Zt=class extends nt{constructor(t=100){super(t)}format(t){return`${super.format(t)}%`}},Ii=class{constructor(t){this.ls=t}format(t){let i="";return t<0&&(i="-",t=-t),t<995?i+this.os(t):t<999995?i+this.os(t/1e3)+"K":t<999999995?(t=1e3*Math.round(t/1e3),i+this.os(t/1e6)+"M"):(t=1e6*Math.round(t/1e6),i+this.os(t/1e9)+"B")}os(t){let i,s=Math.pow(10,this.ls);return i=(t=Math.round(t*s)/s)>=1e-15&&t<1?t.toFixed(this.ls).replace(/\.?0+$/,""):String(t),i.replace(/(\.[1-9]*)0+$/,(e,h)=>h)}};function Ke(n,t,i,s,e,h,r){if(t.length===0||s.from>=t.length||s.to<=0)return;let{context:o,horizontalPixelRatio:l,verticalPixelRatio:a}=n,u=t[s.from],c=h(n,u),d=u;if(s.to-s.from<2){let f=e/2;o.beginPath();let p={nt:u.nt-f,st:u.st},m={nt:u.nt+f,st:u.st};o.moveTo(p.nt*l,p.st*a),o.lineTo(m.nt*l,m.st*a),r(n,c,p,m)}else{let f=(m,b)=>{r(n,c,d,b),o.beginPath(),c=m,d=b},p=d;o.beginPath(),o.moveTo(u.nt*l,u.st*a);for(let m=s.from+1;m<s.to;++m){p=t[m];let b=h(n,p);switch(i){case 0:o.lineTo(p.nt*l,p.st*a);break;case 1:o.lineTo(p.nt*l,t[m-1].st*a),b!==c&&(f(b,p),o.lineTo(p.nt*l,t[m-1].st*a)),o.lineTo(p.nt*l,p.st*a);break;case 2:{let[g,w]=Nn(t,m-1,m);o.bezierCurveTo(g.nt*l,g.st*a,w.nt*l,w.st*a,p.nt*l,p.st*a);break}}i!==1&&b!==c&&(f(b,p),o.moveTo(p.nt*l,p.st*a))}(d!==p||d===p&&i===1)&&r(n,c,d,p)}}var fe=6;function bi(n,t){return{nt:n.nt-t.nt,st:n.st-t.st}}function me(n,t){return{nt:n.nt/t,st:n.st/t}}function Nn(n,t,i){let s=Math.max(0,t-1),e=Math.min(n.length-1,i+1);var h,r;return[(h=n[t],r=me(bi(n[i],n[s]),fe),{nt:h.nt+r.nt,st:h.st+r.st}),bi(n[i],me(bi(n[e],n[t]),fe))]}function Bn(n,t,i,s,e){let{context:h,horizontalPixelRatio:r,verticalPixelRatio:o}=t;h.lineTo(e.nt*r,n*o),h.lineTo(s.nt*r,n*o),h.closePath(),h.fillStyle=i,h.fill()}var Yt=class extends T{constructor(){super(...arguments),this.G=null}J(t){this.G=t}Z(t){var i;if(this.G===null)return;let{it:s,tt:e,_s:h,et:r,Nt:o,us:l}=this.G,a=(i=this.G.cs)!==null&&i!==void 0?i:this.G.ds?0:t.mediaSize.height;if(e===null)return;let u=t.context;u.lineCap="butt",u.lineJoin="round",u.lineWidth=r,U(u,o),u.lineWidth=1,Ke(t,s,l,e,h,this.fs.bind(this),Bn.bind(null,a))}};function $i(n,t,i){return Math.min(Math.max(n,t),i)}function Rt(n,t,i){return t-n<=i}function Ge(n){let t=Math.ceil(n);return t%2==0?t-1:t}var St=class{vs(t,i){let s=this.ps,{bs:e,ws:h,gs:r,Ms:o,xs:l,cs:a}=i;if(this.Ss===void 0||s===void 0||s.bs!==e||s.ws!==h||s.gs!==r||s.Ms!==o||s.cs!==a||s.xs!==l){let u=t.context.createLinearGradient(0,0,0,l);if(u.addColorStop(0,e),a!=null){let c=$i(a*t.verticalPixelRatio/l,0,1);u.addColorStop(c,h),u.addColorStop(c,r)}u.addColorStop(1,o),this.Ss=u,this.ps=i}return this.Ss}},Fi=class extends Yt{constructor(){super(...arguments),this.ys=new St}fs(t,i){return this.ys.vs(t,{bs:i.ks,ws:"",gs:"",Ms:i.Cs,xs:t.bitmapSize.height})}};function Pn(n,t){let i=n.context;i.strokeStyle=t,i.stroke()}var Qt=class extends T{constructor(){super(...arguments),this.G=null}J(t){this.G=t}Z(t){if(this.G===null)return;let{it:i,tt:s,_s:e,us:h,et:r,Nt:o,Ts:l}=this.G;if(s===null)return;let a=t.context;a.lineCap="butt",a.lineWidth=r*t.verticalPixelRatio,U(a,o),a.lineJoin="round";let u=this.Ps.bind(this);h!==void 0&&Ke(t,i,h,s,e,u,Pn),l&&function(c,d,f,p,m){let{horizontalPixelRatio:b,verticalPixelRatio:g,context:w}=c,S=null,_=Math.max(1,Math.floor(b))%2/2,x=f*g+_;for(let O=p.to-1;O>=p.from;--O){let k=d[O];if(k){let E=m(c,k);E!==S&&(w.beginPath(),S!==null&&w.fill(),w.fillStyle=E,S=E);let V=Math.round(k.nt*b)+_,J=k.st*g;w.moveTo(V,J),w.arc(V,J,x,0,2*Math.PI)}}w.fill()}(t,i,l,s,u)}},qt=class extends Qt{Ps(t,i){return i.lt}};function tn(n,t,i,s,e=0,h=t.length){let r=h-e;for(;0<r;){let o=r>>1,l=e+o;s(t[l],i)===n?(e=l+1,r-=o+1):r=o}return e}var zt=tn.bind(null,!0),sn=tn.bind(null,!1);function Dn(n,t){return n.ot<t}function An(n,t){return t<n.ot}function en(n,t,i){let s=t.Rs(),e=t.ui(),h=zt(n,s,Dn),r=sn(n,e,An);if(!i)return{from:h,to:r};let o=h,l=r;return h>0&&h<n.length&&n[h].ot>=s&&(o=h-1),r>0&&r<n.length&&n[r-1].ot<=e&&(l=r+1),{from:o,to:l}}var Mt=class{constructor(t,i,s){this.Ds=!0,this.Os=!0,this.As=!0,this.Bs=[],this.Vs=null,this.zs=t,this.Es=i,this.Is=s}bt(t){this.Ds=!0,t==="data"&&(this.Os=!0),t==="options"&&(this.As=!0)}gt(){return this.zs.kt()?(this.Ls(),this.Vs===null?null:this.Ns):null}Fs(){this.Bs=this.Bs.map(t=>Object.assign(Object.assign({},t),this.zs.js().Ws(t.ot)))}Hs(){this.Vs=null}Ls(){this.Os&&(this.$s(),this.Os=!1),this.As&&(this.Fs(),this.As=!1),this.Ds&&(this.Us(),this.Ds=!1)}Us(){let t=this.zs.Dt(),i=this.Es.St();if(this.Hs(),i.Li()||t.Li())return;let s=i.qs();if(s===null||this.zs.Bn().Ys()===0)return;let e=this.zs.Ct();e!==null&&(this.Vs=en(this.Bs,s,this.Is),this.Xs(t,i,e.Ot),this.Ks())}},ht=class extends Mt{constructor(t,i){super(t,i,!0)}Xs(t,i,s){i.Zs(this.Bs,gt(this.Vs)),t.Gs(this.Bs,s,gt(this.Vs))}Js(t,i){return{ot:t,_t:i,nt:NaN,st:NaN}}$s(){let t=this.zs.js();this.Bs=this.zs.Bn().Qs().map(i=>{let s=i.Ot[3];return this.te(i.ie,s,t)})}},ji=class extends ht{constructor(t,i){super(t,i),this.Ns=new wt,this.ne=new Fi,this.se=new qt,this.Ns.X([this.ne,this.se])}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t=this.zs.W();this.ne.J({us:t.lineType,it:this.Bs,Nt:t.lineStyle,et:t.lineWidth,cs:null,ds:t.invertFilledArea,tt:this.Vs,_s:this.Es.St().ee()}),this.se.J({us:t.lineVisible?t.lineType:void 0,it:this.Bs,Nt:t.lineStyle,et:t.lineWidth,tt:this.Vs,_s:this.Es.St().ee(),Ts:t.pointMarkersVisible?t.pointMarkersRadius||t.lineWidth/2+2:void 0})}},Ui=class extends T{constructor(){super(...arguments),this.Et=null,this.re=0,this.he=0}J(t){this.Et=t}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){if(this.Et===null||this.Et.Bn.length===0||this.Et.tt===null)return;this.re=this.le(i),this.re>=2&&Math.max(1,Math.floor(i))%2!=this.re%2&&this.re--,this.he=this.Et.ae?Math.min(this.re,Math.floor(i)):this.re;let e=null,h=this.he<=this.re&&this.Et.ee>=Math.floor(1.5*i);for(let r=this.Et.tt.from;r<this.Et.tt.to;++r){let o=this.Et.Bn[r];e!==o.oe&&(t.fillStyle=o.oe,e=o.oe);let l=Math.floor(.5*this.he),a=Math.round(o.nt*i),u=a-l,c=this.he,d=u+c-1,f=Math.min(o._e,o.ue),p=Math.max(o._e,o.ue),m=Math.round(f*s)-l,b=Math.round(p*s)+l,g=Math.max(b-m,this.he);t.fillRect(u,m,c,g);let w=Math.ceil(1.5*this.re);if(h){if(this.Et.ce){let O=a-w,k=Math.max(m,Math.round(o.de*s)-l),E=k+c-1;E>m+g-1&&(E=m+g-1,k=E-c+1),t.fillRect(O,k,u-O,E-k+1)}let S=a+w,_=Math.max(m,Math.round(o.fe*s)-l),x=_+c-1;x>m+g-1&&(x=m+g-1,_=x-c+1),t.fillRect(d+1,_,S-d,x-_+1)}}}le(t){let i=Math.floor(t);return Math.max(i,Math.floor(function(s,e){return Math.floor(.3*s*e)}(v(this.Et).ee,t)))}},Xt=class extends Mt{constructor(t,i){super(t,i,!1)}Xs(t,i,s){i.Zs(this.Bs,gt(this.Vs)),t.ve(this.Bs,s,gt(this.Vs))}pe(t,i,s){return{ot:t,me:i.Ot[0],be:i.Ot[1],we:i.Ot[2],ge:i.Ot[3],nt:NaN,de:NaN,_e:NaN,ue:NaN,fe:NaN}}$s(){let t=this.zs.js();this.Bs=this.zs.Bn().Qs().map(i=>this.te(i.ie,i,t))}},Zi=class extends Xt{constructor(){super(...arguments),this.Ns=new Ui}te(t,i,s){return Object.assign(Object.assign({},this.pe(t,i,s)),s.Ws(t))}Ks(){let t=this.zs.W();this.Ns.J({Bn:this.Bs,ee:this.Es.St().ee(),ce:t.openVisible,ae:t.thinBars,tt:this.Vs})}},Yi=class extends Yt{constructor(){super(...arguments),this.ys=new St}fs(t,i){let s=this.G;return this.ys.vs(t,{bs:i.Me,ws:i.xe,gs:i.Se,Ms:i.ye,xs:t.bitmapSize.height,cs:s.cs})}},Qi=class extends Qt{constructor(){super(...arguments),this.ke=new St}Ps(t,i){let s=this.G;return this.ke.vs(t,{bs:i.Ce,ws:i.Ce,gs:i.Te,Ms:i.Te,xs:t.bitmapSize.height,cs:s.cs})}},qi=class extends ht{constructor(t,i){super(t,i),this.Ns=new wt,this.Pe=new Yi,this.Re=new Qi,this.Ns.X([this.Pe,this.Re])}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t=this.zs.Ct();if(t===null)return;let i=this.zs.W(),s=this.zs.Dt().Rt(i.baseValue.price,t.Ot),e=this.Es.St().ee();this.Pe.J({it:this.Bs,et:i.lineWidth,Nt:i.lineStyle,us:i.lineType,cs:s,ds:!1,tt:this.Vs,_s:e}),this.Re.J({it:this.Bs,et:i.lineWidth,Nt:i.lineStyle,us:i.lineVisible?i.lineType:void 0,Ts:i.pointMarkersVisible?i.pointMarkersRadius||i.lineWidth/2+2:void 0,cs:s,tt:this.Vs,_s:e})}},Xi=class extends T{constructor(){super(...arguments),this.Et=null,this.re=0}J(t){this.Et=t}Z(t){if(this.Et===null||this.Et.Bn.length===0||this.Et.tt===null)return;let{horizontalPixelRatio:i}=t;this.re=function(h,r){if(h>=2.5&&h<=4)return Math.floor(3*r);let o=1-.2*Math.atan(Math.max(4,h)-4)/(.5*Math.PI),l=Math.floor(h*o*r),a=Math.floor(h*r),u=Math.min(l,a);return Math.max(Math.floor(r),u)}(this.Et.ee,i),this.re>=2&&Math.floor(i)%2!=this.re%2&&this.re--;let s=this.Et.Bn;this.Et.De&&this.Oe(t,s,this.Et.tt),this.Et._i&&this.Ae(t,s,this.Et.tt);let e=this.Be(i);(!this.Et._i||this.re>2*e)&&this.Ve(t,s,this.Et.tt)}Oe(t,i,s){if(this.Et===null)return;let{context:e,horizontalPixelRatio:h,verticalPixelRatio:r}=t,o="",l=Math.min(Math.floor(h),Math.floor(this.Et.ee*h));l=Math.max(Math.floor(h),Math.min(l,this.re));let a=Math.floor(.5*l),u=null;for(let c=s.from;c<s.to;c++){let d=i[c];d.ze!==o&&(e.fillStyle=d.ze,o=d.ze);let f=Math.round(Math.min(d.de,d.fe)*r),p=Math.round(Math.max(d.de,d.fe)*r),m=Math.round(d._e*r),b=Math.round(d.ue*r),g=Math.round(h*d.nt)-a,w=g+l-1;u!==null&&(g=Math.max(u+1,g),g=Math.min(g,w));let S=w-g+1;e.fillRect(g,m,S,f-m),e.fillRect(g,p+1,S,b-p),u=w}}Be(t){let i=Math.floor(1*t);this.re<=2*i&&(i=Math.floor(.5*(this.re-1)));let s=Math.max(Math.floor(t),i);return this.re<=2*s?Math.max(Math.floor(t),Math.floor(1*t)):s}Ae(t,i,s){if(this.Et===null)return;let{context:e,horizontalPixelRatio:h,verticalPixelRatio:r}=t,o="",l=this.Be(h),a=null;for(let u=s.from;u<s.to;u++){let c=i[u];c.Ee!==o&&(e.fillStyle=c.Ee,o=c.Ee);let d=Math.round(c.nt*h)-Math.floor(.5*this.re),f=d+this.re-1,p=Math.round(Math.min(c.de,c.fe)*r),m=Math.round(Math.max(c.de,c.fe)*r);if(a!==null&&(d=Math.max(a+1,d),d=Math.min(d,f)),this.Et.ee*h>2*l)Ln(e,d,p,f-d+1,m-p+1,l);else{let b=f-d+1;e.fillRect(d,p,b,m-p+1)}a=f}}Ve(t,i,s){if(this.Et===null)return;let{context:e,horizontalPixelRatio:h,verticalPixelRatio:r}=t,o="",l=this.Be(h);for(let a=s.from;a<s.to;a++){let u=i[a],c=Math.round(Math.min(u.de,u.fe)*r),d=Math.round(Math.max(u.de,u.fe)*r),f=Math.round(u.nt*h)-Math.floor(.5*this.re),p=f+this.re-1;if(u.oe!==o){let m=u.oe;e.fillStyle=m,o=m}this.Et._i&&(f+=l,c+=l,p-=l,d-=l),c>d||e.fillRect(f,c,p-f+1,d-c+1)}}},Ji=class extends Xt{constructor(){super(...arguments),this.Ns=new Xi}te(t,i,s){return Object.assign(Object.assign({},this.pe(t,i,s)),s.Ws(t))}Ks(){let t=this.zs.W();this.Ns.J({Bn:this.Bs,ee:this.Es.St().ee(),De:t.wickVisible,_i:t.borderVisible,tt:this.Vs})}},Ki=class{constructor(t,i){this.Ie=t,this.Ei=i}K(t,i,s){this.Ie.draw(t,this.Ei,i,s)}},ut=class extends Mt{constructor(t,i,s){super(t,i,!1),this.mn=s,this.Ns=new Ki(this.mn.renderer(),e=>{let h=t.Ct();return h===null?null:t.Dt().Rt(e,h.Ot)})}Le(t){return this.mn.priceValueBuilder(t)}Ne(t){return this.mn.isWhitespace(t)}$s(){let t=this.zs.js();this.Bs=this.zs.Bn().Qs().map(i=>Object.assign(Object.assign({ot:i.ie,nt:NaN},t.Ws(i.ie)),{Fe:i.We}))}Xs(t,i){i.Zs(this.Bs,gt(this.Vs))}Ks(){this.mn.update({bars:this.Bs.map(Wn),barSpacing:this.Es.St().ee(),visibleRange:this.Vs},this.zs.W())}};function Wn(n){return{x:n.nt,time:n.ot,originalData:n.Fe,barColor:n.oe}}var Gi=class extends T{constructor(){super(...arguments),this.Et=null,this.je=[]}J(t){this.Et=t,this.je=[]}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){if(this.Et===null||this.Et.it.length===0||this.Et.tt===null)return;this.je.length||this.He(i);let e=Math.max(1,Math.floor(s)),h=Math.round(this.Et.$e*s)-Math.floor(e/2),r=h+e;for(let o=this.Et.tt.from;o<this.Et.tt.to;o++){let l=this.Et.it[o],a=this.je[o-this.Et.tt.from],u=Math.round(l.st*s),c,d;t.fillStyle=l.oe,u<=h?(c=u,d=r):(c=h,d=u-Math.floor(e/2)+e),t.fillRect(a.Rs,c,a.ui-a.Rs+1,d-c)}}He(t){if(this.Et===null||this.Et.it.length===0||this.Et.tt===null)return void(this.je=[]);let i=Math.ceil(this.Et.ee*t)<=1?0:Math.max(1,Math.floor(t)),s=Math.round(this.Et.ee*t)-i;this.je=new Array(this.Et.tt.to-this.Et.tt.from);for(let h=this.Et.tt.from;h<this.Et.tt.to;h++){let r=this.Et.it[h],o=Math.round(r.nt*t),l,a;if(s%2){let u=(s-1)/2;l=o-u,a=o+u}else{let u=s/2;l=o-u,a=o+u-1}this.je[h-this.Et.tt.from]={Rs:l,ui:a,Ue:o,qe:r.nt*t,ot:r.ot}}for(let h=this.Et.tt.from+1;h<this.Et.tt.to;h++){let r=this.je[h-this.Et.tt.from],o=this.je[h-this.Et.tt.from-1];r.ot===o.ot+1&&r.Rs-o.ui!==i+1&&(o.Ue>o.qe?o.ui=r.Rs-i-1:r.Rs=o.ui+i+1)}let e=Math.ceil(this.Et.ee*t);for(let h=this.Et.tt.from;h<this.Et.tt.to;h++){let r=this.je[h-this.Et.tt.from];r.ui<r.Rs&&(r.ui=r.Rs);let o=r.ui-r.Rs+1;e=Math.min(o,e)}if(i>0&&e<4)for(let h=this.Et.tt.from;h<this.Et.tt.to;h++){let r=this.je[h-this.Et.tt.from];r.ui-r.Rs+1>e&&(r.Ue>r.qe?r.ui-=1:r.Rs+=1)}}},ts=class extends ht{constructor(){super(...arguments),this.Ns=new Gi}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t={it:this.Bs,ee:this.Es.St().ee(),tt:this.Vs,$e:this.zs.Dt().Rt(this.zs.W().base,v(this.zs.Ct()).Ot)};this.Ns.J(t)}},is=class extends ht{constructor(){super(...arguments),this.Ns=new qt}te(t,i,s){return Object.assign(Object.assign({},this.Js(t,i)),s.Ws(t))}Ks(){let t=this.zs.W(),i={it:this.Bs,Nt:t.lineStyle,us:t.lineVisible?t.lineType:void 0,et:t.lineWidth,Ts:t.pointMarkersVisible?t.pointMarkersRadius||t.lineWidth/2+2:void 0,tt:this.Vs,_s:this.Es.St().ee()};this.Ns.J(i)}},Vn=/[2-9]/g,Z=class{constructor(t=50){this.Ye=0,this.Xe=1,this.Ke=1,this.Ze={},this.Ge=new Map,this.Je=t}Qe(){this.Ye=0,this.Ge.clear(),this.Xe=1,this.Ke=1,this.Ze={}}Mi(t,i,s){return this.tr(t,i,s).width}gi(t,i,s){let e=this.tr(t,i,s);return((e.actualBoundingBoxAscent||0)-(e.actualBoundingBoxDescent||0))/2}tr(t,i,s){let e=s||Vn,h=String(i).replace(e,"0");if(this.Ge.has(h))return z(this.Ge.get(h)).ir;if(this.Ye===this.Je){let o=this.Ze[this.Ke];delete this.Ze[this.Ke],this.Ge.delete(o),this.Ke++,this.Ye--}t.save(),t.textBaseline="middle";let r=t.measureText(h);return t.restore(),r.width===0&&i.length||(this.Ge.set(h,{ir:r,nr:this.Xe}),this.Ze[this.Xe]=h,this.Ye++,this.Xe++),r}},ss=class{constructor(t){this.sr=null,this.k=null,this.er="right",this.rr=t}hr(t,i,s){this.sr=t,this.k=i,this.er=s}K(t){this.k!==null&&this.sr!==null&&this.sr.K(t,this.k,this.rr,this.er)}},Jt=class{constructor(t,i,s){this.lr=t,this.rr=new Z(50),this.ar=i,this.F=s,this.j=-1,this.Wt=new ss(this.rr)}gt(){let t=this.F._r(this.ar);if(t===null)return null;let i=t.ur(this.ar)?t.cr():this.ar.Dt();if(i===null)return null;let s=t.dr(i);if(s==="overlay")return null;let e=this.F.vr();return e.P!==this.j&&(this.j=e.P,this.rr.Qe()),this.Wt.hr(this.lr.Vi(),e,s),this.Wt}},es=class extends T{constructor(){super(...arguments),this.Et=null}J(t){this.Et=t}pr(t,i){var s;if(!(!((s=this.Et)===null||s===void 0)&&s.kt))return null;let{st:e,et:h,mr:r}=this.Et;return i>=e-h-7&&i<=e+h+7?{br:this.Et,mr:r}:null}Z({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(this.Et===null||this.Et.kt===!1)return;let h=Math.round(this.Et.st*e);h<0||h>i.height||(t.lineCap="butt",t.strokeStyle=this.Et.O,t.lineWidth=Math.floor(this.Et.et*s),U(t,this.Et.Nt),qe(t,h,0,i.width))}},yt=class{constructor(t){this.wr={st:0,O:"rgba(0, 0, 0, 0)",et:1,Nt:0,kt:!1},this.gr=new es,this.ft=!0,this.zs=t,this.Es=t.$t(),this.gr.J(this.wr)}bt(){this.ft=!0}gt(){return this.zs.kt()?(this.ft&&(this.Mr(),this.ft=!1),this.gr):null}},ns=class extends yt{constructor(t){super(t)}Mr(){this.wr.kt=!1;let t=this.zs.Dt(),i=t.Sr().Sr;if(i!==2&&i!==3)return;let s=this.zs.W();if(!s.baseLineVisible||!this.zs.kt())return;let e=this.zs.Ct();e!==null&&(this.wr.kt=!0,this.wr.st=t.Rt(e.Ot,e.Ot),this.wr.O=s.baseLineColor,this.wr.et=s.baseLineWidth,this.wr.Nt=s.baseLineStyle)}},hs=class extends T{constructor(){super(...arguments),this.Et=null}J(t){this.Et=t}We(){return this.Et}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s}){let e=this.Et;if(e===null)return;let h=Math.max(1,Math.floor(i)),r=h%2/2,o=Math.round(e.qe.x*i)+r,l=e.qe.y*s;t.fillStyle=e.yr,t.beginPath();let a=Math.max(2,1.5*e.kr)*i;t.arc(o,l,a,0,2*Math.PI,!1),t.fill(),t.fillStyle=e.Cr,t.beginPath(),t.arc(o,l,e.ht*i,0,2*Math.PI,!1),t.fill(),t.lineWidth=h,t.strokeStyle=e.Tr,t.beginPath(),t.arc(o,l,e.ht*i+h/2,0,2*Math.PI,!1),t.stroke()}},Hn=[{Pr:0,Rr:.25,Dr:4,Or:10,Ar:.25,Br:0,Vr:.4,zr:.8},{Pr:.25,Rr:.525,Dr:10,Or:14,Ar:0,Br:0,Vr:.8,zr:0},{Pr:.525,Rr:1,Dr:14,Or:14,Ar:0,Br:0,Vr:0,zr:0}];function pe(n,t,i,s){return function(e,h){if(e==="transparent")return e;let r=Ft(e),o=r[3];return`rgba(${r[0]}, ${r[1]}, ${r[2]}, ${h*o})`}(n,i+(s-i)*t)}function ve(n,t){let i=n%2600/2600,s;for(let l of Hn)if(i>=l.Pr&&i<=l.Rr){s=l;break}I(s!==void 0,"Last price animation internal logic error");let e=(i-s.Pr)/(s.Rr-s.Pr);return{Cr:pe(t,e,s.Ar,s.Br),Tr:pe(t,e,s.Vr,s.zr),ht:(h=e,r=s.Dr,o=s.Or,r+(o-r)*h)};var h,r,o}var rs=class{constructor(t){this.Wt=new hs,this.ft=!0,this.Er=!0,this.Ir=performance.now(),this.Lr=this.Ir-1,this.Nr=t}Fr(){this.Lr=this.Ir-1,this.bt()}Wr(){if(this.bt(),this.Nr.W().lastPriceAnimation===2){let t=performance.now(),i=this.Lr-t;if(i>0)return void(i<650&&(this.Lr+=2600));this.Ir=t,this.Lr=t+2600}}bt(){this.ft=!0}jr(){this.Er=!0}kt(){return this.Nr.W().lastPriceAnimation!==0}Hr(){switch(this.Nr.W().lastPriceAnimation){case 0:return!1;case 1:return!0;case 2:return performance.now()<=this.Lr}}gt(){return this.ft?(this.Mt(),this.ft=!1,this.Er=!1):this.Er&&(this.$r(),this.Er=!1),this.Wt}Mt(){this.Wt.J(null);let t=this.Nr.$t().St(),i=t.qs(),s=this.Nr.Ct();if(i===null||s===null)return;let e=this.Nr.Ur(!0);if(e.qr||!i.Yr(e.ie))return;let h={x:t.zt(e.ie),y:this.Nr.Dt().Rt(e._t,s.Ot)},r=e.O,o=this.Nr.W().lineWidth,l=ve(this.Xr(),r);this.Wt.J({yr:r,kr:o,Cr:l.Cr,Tr:l.Tr,ht:l.ht,qe:h})}$r(){let t=this.Wt.We();if(t!==null){let i=ve(this.Xr(),t.yr);t.Cr=i.Cr,t.Tr=i.Tr,t.ht=i.ht}}Xr(){return this.Hr()?performance.now()-this.Ir:2599}};function ct(n,t){return Ge(Math.min(Math.max(n,12),30)*t)}function xt(n,t){switch(n){case"arrowDown":case"arrowUp":return ct(t,1);case"circle":return ct(t,.8);case"square":return ct(t,.7)}}function nn(n){return function(t){let i=Math.ceil(t);return i%2!=0?i-1:i}(ct(n,1))}function be(n){return Math.max(ct(n,.1),3)}function hn(n,t,i,s,e){let h=xt("square",i),r=(h-1)/2,o=n-r,l=t-r;return s>=o&&s<=o+h&&e>=l&&e<=l+h}function ge(n,t,i,s){let e=(xt("arrowUp",s)-1)/2*i.Kr,h=(Ge(s/2)-1)/2*i.Kr;t.beginPath(),n?(t.moveTo(i.nt-e,i.st),t.lineTo(i.nt,i.st-e),t.lineTo(i.nt+e,i.st),t.lineTo(i.nt+h,i.st),t.lineTo(i.nt+h,i.st+e),t.lineTo(i.nt-h,i.st+e),t.lineTo(i.nt-h,i.st)):(t.moveTo(i.nt-e,i.st),t.lineTo(i.nt,i.st+e),t.lineTo(i.nt+e,i.st),t.lineTo(i.nt+h,i.st),t.lineTo(i.nt+h,i.st-e),t.lineTo(i.nt-h,i.st-e),t.lineTo(i.nt-h,i.st)),t.fill()}function In(n,t,i,s,e,h){return hn(t,i,s,e,h)}var os=class extends T{constructor(){super(...arguments),this.Et=null,this.rr=new Z,this.j=-1,this.H="",this.Zr=""}J(t){this.Et=t}hr(t,i){this.j===t&&this.H===i||(this.j=t,this.H=i,this.Zr=st(t,i),this.rr.Qe())}pr(t,i){if(this.Et===null||this.Et.tt===null)return null;for(let s=this.Et.tt.from;s<this.Et.tt.to;s++){let e=this.Et.it[s];if(Fn(e,t,i))return{br:e.Gr,mr:e.mr}}return null}Z({context:t,horizontalPixelRatio:i,verticalPixelRatio:s},e,h){if(this.Et!==null&&this.Et.tt!==null){t.textBaseline="middle",t.font=this.Zr;for(let r=this.Et.tt.from;r<this.Et.tt.to;r++){let o=this.Et.it[r];o.Zt!==void 0&&(o.Zt.ji=this.rr.Mi(t,o.Zt.Jr),o.Zt.Vt=this.j,o.Zt.nt=o.nt-o.Zt.ji/2),$n(o,t,i,s)}}}};function $n(n,t,i,s){t.fillStyle=n.O,n.Zt!==void 0&&function(e,h,r,o,l,a){e.save(),e.scale(l,a),e.fillText(h,r,o),e.restore()}(t,n.Zt.Jr,n.Zt.nt,n.Zt.st,i,s),function(e,h,r){if(e.Ys!==0){switch(e.Qr){case"arrowDown":return void ge(!1,h,r,e.Ys);case"arrowUp":return void ge(!0,h,r,e.Ys);case"circle":return void function(o,l,a){let u=(xt("circle",a)-1)/2;o.beginPath(),o.arc(l.nt,l.st,u*l.Kr,0,2*Math.PI,!1),o.fill()}(h,r,e.Ys);case"square":return void function(o,l,a){let u=xt("square",a),c=(u-1)*l.Kr/2,d=l.nt-c,f=l.st-c;o.fillRect(d,f,u*l.Kr,u*l.Kr)}(h,r,e.Ys)}e.Qr}}(n,t,function(e,h,r){let o=Math.max(1,Math.floor(h))%2/2;return{nt:Math.round(e.nt*h)+o,st:e.st*r,Kr:h}}(n,i,s))}function Fn(n,t,i){return!(n.Zt===void 0||!function(s,e,h,r,o,l){let a=r/2;return o>=s&&o<=s+h&&l>=e-a&&l<=e+a}(n.Zt.nt,n.Zt.st,n.Zt.ji,n.Zt.Vt,t,i))||function(s,e,h){if(s.Ys===0)return!1;switch(s.Qr){case"arrowDown":case"arrowUp":return In(0,s.nt,s.st,s.Ys,e,h);case"circle":return function(r,o,l,a,u){let c=2+xt("circle",l)/2,d=r-a,f=o-u;return Math.sqrt(d*d+f*f)<=c}(s.nt,s.st,s.Ys,e,h);case"square":return hn(s.nt,s.st,s.Ys,e,h)}}(n,t,i)}function jn(n,t,i,s,e,h,r,o,l){let a=D(i)?i:i.ge,u=D(i)?i:i.be,c=D(i)?i:i.we,d=D(t.size)?Math.max(t.size,0):1,f=nn(o.ee())*d,p=f/2;switch(n.Ys=f,t.position){case"inBar":return n.st=r.Rt(a,l),void(n.Zt!==void 0&&(n.Zt.st=n.st+p+h+.6*e));case"aboveBar":return n.st=r.Rt(u,l)-p-s.th,n.Zt!==void 0&&(n.Zt.st=n.st-p-.6*e,s.th+=1.2*e),void(s.th+=f+h);case"belowBar":return n.st=r.Rt(c,l)+p+s.ih,n.Zt!==void 0&&(n.Zt.st=n.st+p+h+.6*e,s.ih+=1.2*e),void(s.ih+=f+h)}t.position}var ls=class{constructor(t,i){this.ft=!0,this.nh=!0,this.sh=!0,this.eh=null,this.Wt=new os,this.Nr=t,this.Hi=i,this.Et={it:[],tt:null}}bt(t){this.ft=!0,this.sh=!0,t==="data"&&(this.nh=!0)}gt(t){if(!this.Nr.kt())return null;this.ft&&this.rh();let i=this.Hi.W().layout;return this.Wt.hr(i.fontSize,i.fontFamily),this.Wt.J(this.Et),this.Wt}hh(){if(this.sh){if(this.Nr.lh().length>0){let t=this.Hi.St().ee(),i=be(t),s=1.5*nn(t)+2*i;this.eh={above:s,below:s}}else this.eh=null;this.sh=!1}return this.eh}rh(){let t=this.Nr.Dt(),i=this.Hi.St(),s=this.Nr.lh();this.nh&&(this.Et.it=s.map(u=>({ot:u.time,nt:0,st:0,Ys:0,Qr:u.shape,O:u.color,Gr:u.Gr,mr:u.id,Zt:void 0})),this.nh=!1);let e=this.Hi.W().layout;this.Et.tt=null;let h=i.qs();if(h===null)return;let r=this.Nr.Ct();if(r===null||this.Et.it.length===0)return;let o=NaN,l=be(i.ee()),a={th:l,ih:l};this.Et.tt=en(this.Et.it,h,!0);for(let u=this.Et.tt.from;u<this.Et.tt.to;u++){let c=s[u];c.time!==o&&(a.th=l,a.ih=l,o=c.time);let d=this.Et.it[u];d.nt=i.zt(c.time),c.text!==void 0&&c.text.length>0&&(d.Zt={Jr:c.text,nt:0,st:0,ji:0,Vt:0});let f=this.Nr.ah(c.time);f!==null&&jn(d,c,f,a,e.fontSize,l,t,i,r.Ot)}this.ft=!1}},as=class extends yt{constructor(t){super(t)}Mr(){let t=this.wr;t.kt=!1;let i=this.zs.W();if(!i.priceLineVisible||!this.zs.kt())return;let s=this.zs.Ur(i.priceLineSource===0);s.qr||(t.kt=!0,t.st=s.Si,t.O=this.zs.oh(s.O),t.et=i.priceLineWidth,t.Nt=i.priceLineStyle)}},us=class extends et{constructor(t){super(),this.jt=t}zi(t,i,s){t.kt=!1,i.kt=!1;let e=this.jt;if(!e.kt())return;let h=e.W(),r=h.lastValueVisible,o=e._h()!=="",l=h.seriesLastValueMode===0,a=e.Ur(!1);if(a.qr)return;r&&(t.Zt=this.uh(a,r,l),t.kt=t.Zt.length!==0),(o||l)&&(i.Zt=this.dh(a,r,o,l),i.kt=i.Zt.length>0);let u=e.oh(a.O),c=ri(u);s.t=c.t,s.Si=a.Si,i.At=e.$t().Bt(a.Si/e.Dt().Vt()),t.At=u,t.O=c.i,i.O=c.i}dh(t,i,s,e){let h="",r=this.jt._h();return s&&r.length!==0&&(h+=`${r} `),i&&e&&(h+=this.jt.Dt().fh()?t.ph:t.mh),h.trim()}uh(t,i,s){return i?s?this.jt.Dt().fh()?t.mh:t.ph:t.Zt:""}};function we(n,t,i,s){let e=Number.isFinite(t),h=Number.isFinite(i);return e&&h?n(t,i):e||h?e?t:i:s}var R=class n{constructor(t,i){this.bh=t,this.wh=i}gh(t){return t!==null&&this.bh===t.bh&&this.wh===t.wh}Mh(){return new n(this.bh,this.wh)}xh(){return this.bh}Sh(){return this.wh}yh(){return this.wh-this.bh}Li(){return this.wh===this.bh||Number.isNaN(this.wh)||Number.isNaN(this.bh)}Jn(t){return t===null?this:new n(we(Math.min,this.xh(),t.xh(),-1/0),we(Math.max,this.Sh(),t.Sh(),1/0))}kh(t){if(!D(t)||this.wh-this.bh===0)return;let i=.5*(this.wh+this.bh),s=this.wh-i,e=this.bh-i;s*=t,e*=t,this.wh=i+s,this.bh=i+e}Ch(t){D(t)&&(this.wh+=t,this.bh+=t)}Th(){return{minValue:this.bh,maxValue:this.wh}}static Ph(t){return t===null?null:new n(t.minValue,t.maxValue)}},Kt=class n{constructor(t,i){this.Rh=t,this.Dh=i||null}Oh(){return this.Rh}Ah(){return this.Dh}Th(){return this.Rh===null?null:{priceRange:this.Rh.Th(),margins:this.Dh||void 0}}static Ph(t){return t===null?null:new n(R.Ph(t.priceRange),t.margins)}},cs=class extends yt{constructor(t,i){super(t),this.Bh=i}Mr(){let t=this.wr;t.kt=!1;let i=this.Bh.W();if(!this.zs.kt()||!i.lineVisible)return;let s=this.Bh.Vh();s!==null&&(t.kt=!0,t.st=s,t.O=i.color,t.et=i.lineWidth,t.Nt=i.lineStyle,t.mr=this.Bh.W().id)}},ds=class extends et{constructor(t,i){super(),this.Nr=t,this.Bh=i}zi(t,i,s){t.kt=!1,i.kt=!1;let e=this.Bh.W(),h=e.axisLabelVisible,r=e.title!=="",o=this.Nr;if(!h||!o.kt())return;let l=this.Bh.Vh();if(l===null)return;r&&(i.Zt=e.title,i.kt=!0),i.At=o.$t().Bt(l/o.Dt().Vt()),t.Zt=this.zh(e.price),t.kt=!0;let a=ri(e.axisLabelColor||e.color);s.t=a.t;let u=e.axisLabelTextColor||a.i;t.O=u,i.O=u,s.Si=l}zh(t){let i=this.Nr.Ct();return i===null?"":this.Nr.Dt().Ni(t,i.Ot)}},fs=class{constructor(t,i){this.Nr=t,this._n=i,this.Eh=new cs(t,this),this.lr=new ds(t,this),this.Ih=new Jt(this.lr,t,t.$t())}Lh(t){N(this._n,t),this.bt(),this.Nr.$t().Nh()}W(){return this._n}Fh(){return this.Eh}Wh(){return this.Ih}jh(){return this.lr}bt(){this.Eh.bt(),this.lr.bt()}Vh(){let t=this.Nr,i=t.Dt();if(t.$t().St().Li()||i.Li())return null;let s=t.Ct();return s===null?null:i.Rt(this._n.price,s.Ot)}},ms=class extends _t{constructor(t){super(),this.Hi=t}$t(){return this.Hi}},Un={Bar:(n,t,i,s)=>{var e;let h=t.upColor,r=t.downColor,o=v(n(i,s)),l=G(o.Ot[0])<=G(o.Ot[3]);return{oe:(e=o.O)!==null&&e!==void 0?e:l?h:r}},Candlestick:(n,t,i,s)=>{var e,h,r;let o=t.upColor,l=t.downColor,a=t.borderUpColor,u=t.borderDownColor,c=t.wickUpColor,d=t.wickDownColor,f=v(n(i,s)),p=G(f.Ot[0])<=G(f.Ot[3]);return{oe:(e=f.O)!==null&&e!==void 0?e:p?o:l,Ee:(h=f.At)!==null&&h!==void 0?h:p?a:u,ze:(r=f.Hh)!==null&&r!==void 0?r:p?c:d}},Custom:(n,t,i,s)=>{var e;return{oe:(e=v(n(i,s)).O)!==null&&e!==void 0?e:t.color}},Area:(n,t,i,s)=>{var e,h,r,o;let l=v(n(i,s));return{oe:(e=l.lt)!==null&&e!==void 0?e:t.lineColor,lt:(h=l.lt)!==null&&h!==void 0?h:t.lineColor,ks:(r=l.ks)!==null&&r!==void 0?r:t.topColor,Cs:(o=l.Cs)!==null&&o!==void 0?o:t.bottomColor}},Baseline:(n,t,i,s)=>{var e,h,r,o,l,a;let u=v(n(i,s));return{oe:u.Ot[3]>=t.baseValue.price?t.topLineColor:t.bottomLineColor,Ce:(e=u.Ce)!==null&&e!==void 0?e:t.topLineColor,Te:(h=u.Te)!==null&&h!==void 0?h:t.bottomLineColor,Me:(r=u.Me)!==null&&r!==void 0?r:t.topFillColor1,xe:(o=u.xe)!==null&&o!==void 0?o:t.topFillColor2,Se:(l=u.Se)!==null&&l!==void 0?l:t.bottomFillColor1,ye:(a=u.ye)!==null&&a!==void 0?a:t.bottomFillColor2}},Line:(n,t,i,s)=>{var e,h;let r=v(n(i,s));return{oe:(e=r.O)!==null&&e!==void 0?e:t.color,lt:(h=r.O)!==null&&h!==void 0?h:t.color}},Histogram:(n,t,i,s)=>{var e;return{oe:(e=v(n(i,s)).O)!==null&&e!==void 0?e:t.color}}},ps=class{constructor(t){this.$h=(i,s)=>s!==void 0?s.Ot:this.Nr.Bn().Uh(i),this.Nr=t,this.qh=Un[t.Yh()]}Ws(t,i){return this.qh(this.$h,this.Nr.W(),t,i)}},_e;(function(n){n[n.NearestLeft=-1]="NearestLeft",n[n.None=0]="None",n[n.NearestRight=1]="NearestRight"})(_e||(_e={}));var H=30,vs=class{constructor(){this.Xh=[],this.Kh=new Map,this.Zh=new Map}Gh(){return this.Ys()>0?this.Xh[this.Xh.length-1]:null}Jh(){return this.Ys()>0?this.Qh(0):null}An(){return this.Ys()>0?this.Qh(this.Xh.length-1):null}Ys(){return this.Xh.length}Li(){return this.Ys()===0}Yr(t){return this.tl(t,0)!==null}Uh(t){return this.il(t)}il(t,i=0){let s=this.tl(t,i);return s===null?null:Object.assign(Object.assign({},this.nl(s)),{ie:this.Qh(s)})}Qs(){return this.Xh}sl(t,i,s){if(this.Li())return null;let e=null;for(let h of s)e=Nt(e,this.el(t,i,h));return e}J(t){this.Zh.clear(),this.Kh.clear(),this.Xh=t}Qh(t){return this.Xh[t].ie}nl(t){return this.Xh[t]}tl(t,i){let s=this.rl(t);if(s===null&&i!==0)switch(i){case-1:return this.hl(t);case 1:return this.ll(t);default:throw new TypeError("Unknown search mode")}return s}hl(t){let i=this.al(t);return i>0&&(i-=1),i!==this.Xh.length&&this.Qh(i)<t?i:null}ll(t){let i=this.ol(t);return i!==this.Xh.length&&t<this.Qh(i)?i:null}rl(t){let i=this.al(t);return i===this.Xh.length||t<this.Xh[i].ie?null:i}al(t){return zt(this.Xh,t,(i,s)=>i.ie<s)}ol(t){return sn(this.Xh,t,(i,s)=>i.ie>s)}_l(t,i,s){let e=null;for(let h=t;h<i;h++){let r=this.Xh[h].Ot[s];Number.isNaN(r)||(e===null?e={ul:r,cl:r}:(r<e.ul&&(e.ul=r),r>e.cl&&(e.cl=r)))}return e}el(t,i,s){if(this.Li())return null;let e=null,h=v(this.Jh()),r=v(this.An()),o=Math.max(t,h),l=Math.min(i,r),a=Math.ceil(o/H)*H,u=Math.max(a,Math.floor(l/H)*H);{let d=this.al(o),f=this.ol(Math.min(l,a,i));e=Nt(e,this._l(d,f,s))}let c=this.Kh.get(s);c===void 0&&(c=new Map,this.Kh.set(s,c));for(let d=Math.max(a+1,o);d<u;d+=H){let f=Math.floor(d/H),p=c.get(f);if(p===void 0){let m=this.al(f*H),b=this.ol((f+1)*H-1);p=this._l(m,b,s),c.set(f,p)}e=Nt(e,p)}{let d=this.al(u),f=this.ol(l);e=Nt(e,this._l(d,f,s))}return e}};function Nt(n,t){return n===null?t:t===null?n:{ul:Math.min(n.ul,t.ul),cl:Math.max(n.cl,t.cl)}}var bs=class{constructor(t){this.dl=t}K(t,i,s){this.dl.draw(t)}fl(t,i,s){var e,h;(h=(e=this.dl).drawBackground)===null||h===void 0||h.call(e,t)}},dt=class{constructor(t){this.Ge=null,this.mn=t}gt(){var t;let i=this.mn.renderer();if(i===null)return null;if(((t=this.Ge)===null||t===void 0?void 0:t.vl)===i)return this.Ge.pl;let s=new bs(i);return this.Ge={vl:i,pl:s},s}ml(){var t,i,s;return(s=(i=(t=this.mn).zOrder)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:"normal"}};function rn(n){var t,i,s,e,h;return{Zt:n.text(),Si:n.coordinate(),xi:(t=n.fixedCoordinate)===null||t===void 0?void 0:t.call(n),O:n.textColor(),t:n.backColor(),kt:(s=(i=n.visible)===null||i===void 0?void 0:i.call(n))===null||s===void 0||s,hi:(h=(e=n.tickVisible)===null||e===void 0?void 0:e.call(n))===null||h===void 0||h}}var gs=class{constructor(t,i){this.Wt=new Ut,this.bl=t,this.wl=i}gt(){return this.Wt.J(Object.assign({ji:this.wl.ji()},rn(this.bl))),this.Wt}},ws=class extends et{constructor(t,i){super(),this.bl=t,this.Ei=i}zi(t,i,s){let e=rn(this.bl);s.t=e.t,t.O=e.O;let h=2/12*this.Ei.P();s.bi=h,s.wi=h,s.Si=e.Si,s.xi=e.xi,t.Zt=e.Zt,t.kt=e.kt,t.hi=e.hi}},_s=class{constructor(t,i){this.gl=null,this.Ml=null,this.xl=null,this.Sl=null,this.yl=null,this.kl=t,this.Nr=i}Cl(){return this.kl}Rn(){var t,i;(i=(t=this.kl).updateAllViews)===null||i===void 0||i.call(t)}Cn(){var t,i,s,e;let h=(s=(i=(t=this.kl).paneViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.gl)===null||e===void 0?void 0:e.vl)===h)return this.gl.pl;let r=h.map(o=>new dt(o));return this.gl={vl:h,pl:r},r}Ji(){var t,i,s,e;let h=(s=(i=(t=this.kl).timeAxisViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.Ml)===null||e===void 0?void 0:e.vl)===h)return this.Ml.pl;let r=this.Nr.$t().St(),o=h.map(l=>new gs(l,r));return this.Ml={vl:h,pl:o},o}Tn(){var t,i,s,e;let h=(s=(i=(t=this.kl).priceAxisViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.xl)===null||e===void 0?void 0:e.vl)===h)return this.xl.pl;let r=this.Nr.Dt(),o=h.map(l=>new ws(l,r));return this.xl={vl:h,pl:o},o}Tl(){var t,i,s,e;let h=(s=(i=(t=this.kl).priceAxisPaneViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.Sl)===null||e===void 0?void 0:e.vl)===h)return this.Sl.pl;let r=h.map(o=>new dt(o));return this.Sl={vl:h,pl:r},r}Pl(){var t,i,s,e;let h=(s=(i=(t=this.kl).timeAxisPaneViews)===null||i===void 0?void 0:i.call(t))!==null&&s!==void 0?s:[];if(((e=this.yl)===null||e===void 0?void 0:e.vl)===h)return this.yl.pl;let r=h.map(o=>new dt(o));return this.yl={vl:h,pl:r},r}Rl(t,i){var s,e,h;return(h=(e=(s=this.kl).autoscaleInfo)===null||e===void 0?void 0:e.call(s,t,i))!==null&&h!==void 0?h:null}pr(t,i){var s,e,h;return(h=(e=(s=this.kl).hitTest)===null||e===void 0?void 0:e.call(s,t,i))!==null&&h!==void 0?h:null}};function gi(n,t,i,s){n.forEach(e=>{t(e).forEach(h=>{h.ml()===i&&s.push(h)})})}function wi(n){return n.Cn()}function Zn(n){return n.Tl()}function Yn(n){return n.Pl()}var Et=class extends ms{constructor(t,i,s,e,h){super(t),this.Et=new vs,this.Eh=new as(this),this.Dl=[],this.Ol=new ns(this),this.Al=null,this.Bl=null,this.Vl=[],this.zl=[],this.El=null,this.Il=[],this._n=i,this.Ll=s;let r=new us(this);this.en=[r],this.Ih=new Jt(r,this,t),s!=="Area"&&s!=="Line"&&s!=="Baseline"||(this.Al=new rs(this)),this.Nl(),this.Fl(h)}S(){this.El!==null&&clearTimeout(this.El)}oh(t){return this._n.priceLineColor||t}Ur(t){let i={qr:!0},s=this.Dt();if(this.$t().St().Li()||s.Li()||this.Et.Li())return i;let e=this.$t().St().qs(),h=this.Ct();if(e===null||h===null)return i;let r,o;if(t){let c=this.Et.Gh();if(c===null)return i;r=c,o=c.ie}else{let c=this.Et.il(e.ui(),-1);if(c===null||(r=this.Et.Uh(c.ie),r===null))return i;o=c.ie}let l=r.Ot[3],a=this.js().Ws(o,{Ot:r}),u=s.Rt(l,h.Ot);return{qr:!1,_t:l,Zt:s.Ni(l,h.Ot),ph:s.Wl(l),mh:s.jl(l,h.Ot),O:a.oe,Si:u,ie:o}}js(){return this.Bl!==null||(this.Bl=new ps(this)),this.Bl}W(){return this._n}Lh(t){let i=t.priceScaleId;i!==void 0&&i!==this._n.priceScaleId&&this.$t().Hl(this,i),N(this._n,t),t.priceFormat!==void 0&&(this.Nl(),this.$t().$l()),this.$t().Ul(this),this.$t().ql(),this.mn.bt("options")}J(t,i){this.Et.J(t),this.Yl(),this.mn.bt("data"),this.un.bt("data"),this.Al!==null&&(i&&i.Xl?this.Al.Wr():t.length===0&&this.Al.Fr());let s=this.$t()._r(this);this.$t().Kl(s),this.$t().Ul(this),this.$t().ql(),this.$t().Nh()}Zl(t){this.Vl=t,this.Yl();let i=this.$t()._r(this);this.un.bt("data"),this.$t().Kl(i),this.$t().Ul(this),this.$t().ql(),this.$t().Nh()}Gl(){return this.Vl}lh(){return this.zl}Jl(t){let i=new fs(this,t);return this.Dl.push(i),this.$t().Ul(this),i}Ql(t){let i=this.Dl.indexOf(t);i!==-1&&this.Dl.splice(i,1),this.$t().Ul(this)}Yh(){return this.Ll}Ct(){let t=this.ta();return t===null?null:{Ot:t.Ot[3],ia:t.ot}}ta(){let t=this.$t().St().qs();if(t===null)return null;let i=t.Rs();return this.Et.il(i,1)}Bn(){return this.Et}ah(t){let i=this.Et.Uh(t);return i===null?null:this.Ll==="Bar"||this.Ll==="Candlestick"||this.Ll==="Custom"?{me:i.Ot[0],be:i.Ot[1],we:i.Ot[2],ge:i.Ot[3]}:i.Ot[3]}na(t){let i=[];gi(this.Il,wi,"top",i);let s=this.Al;return s!==null&&s.kt()&&(this.El===null&&s.Hr()&&(this.El=setTimeout(()=>{this.El=null,this.$t().sa()},0)),s.jr(),i.push(s)),i}Cn(){let t=[];this.ea()||t.push(this.Ol),t.push(this.mn,this.Eh,this.un);let i=this.Dl.map(s=>s.Fh());return t.push(...i),gi(this.Il,wi,"normal",t),t}ra(){return this.ha(wi,"bottom")}la(t){return this.ha(Zn,t)}aa(t){return this.ha(Yn,t)}oa(t,i){return this.Il.map(s=>s.pr(t,i)).filter(s=>s!==null)}Gi(t){return[this.Ih,...this.Dl.map(i=>i.Wh())]}Tn(t,i){if(i!==this.qi&&!this.ea())return[];let s=[...this.en];for(let e of this.Dl)s.push(e.jh());return this.Il.forEach(e=>{s.push(...e.Tn())}),s}Ji(){let t=[];return this.Il.forEach(i=>{t.push(...i.Ji())}),t}Rl(t,i){if(this._n.autoscaleInfoProvider!==void 0){let s=this._n.autoscaleInfoProvider(()=>{let e=this._a(t,i);return e===null?null:e.Th()});return Kt.Ph(s)}return this._a(t,i)}ua(){return this._n.priceFormat.minMove}ca(){return this.da}Rn(){var t;this.mn.bt(),this.un.bt();for(let i of this.en)i.bt();for(let i of this.Dl)i.bt();this.Eh.bt(),this.Ol.bt(),(t=this.Al)===null||t===void 0||t.bt(),this.Il.forEach(i=>i.Rn())}Dt(){return v(super.Dt())}yt(t){if(!((this.Ll==="Line"||this.Ll==="Area"||this.Ll==="Baseline")&&this._n.crosshairMarkerVisible))return null;let i=this.Et.Uh(t);return i===null?null:{_t:i.Ot[3],ht:this.fa(),At:this.va(),Pt:this.pa(),Tt:this.ma(t)}}_h(){return this._n.title}kt(){return this._n.visible}ba(t){this.Il.push(new _s(t,this))}wa(t){this.Il=this.Il.filter(i=>i.Cl()!==t)}ga(){if(this.mn instanceof ut)return t=>this.mn.Le(t)}Ma(){if(this.mn instanceof ut)return t=>this.mn.Ne(t)}ea(){return!li(this.Dt().xa())}_a(t,i){if(!bt(t)||!bt(i)||this.Et.Li())return null;let s=this.Ll==="Line"||this.Ll==="Area"||this.Ll==="Baseline"||this.Ll==="Histogram"?[3]:[2,1],e=this.Et.sl(t,i,s),h=e!==null?new R(e.ul,e.cl):null;if(this.Yh()==="Histogram"){let o=this._n.base,l=new R(o,o);h=h!==null?h.Jn(l):l}let r=this.un.hh();return this.Il.forEach(o=>{let l=o.Rl(t,i);if(l?.priceRange){let f=new R(l.priceRange.minValue,l.priceRange.maxValue);h=h!==null?h.Jn(f):f}var a,u,c,d;l?.margins&&(a=r,u=l.margins,r={above:Math.max((c=a?.above)!==null&&c!==void 0?c:0,u.above),below:Math.max((d=a?.below)!==null&&d!==void 0?d:0,u.below)})}),new Kt(h,r)}fa(){switch(this.Ll){case"Line":case"Area":case"Baseline":return this._n.crosshairMarkerRadius}return 0}va(){switch(this.Ll){case"Line":case"Area":case"Baseline":{let t=this._n.crosshairMarkerBorderColor;if(t.length!==0)return t}}return null}pa(){switch(this.Ll){case"Line":case"Area":case"Baseline":return this._n.crosshairMarkerBorderWidth}return 0}ma(t){switch(this.Ll){case"Line":case"Area":case"Baseline":{let i=this._n.crosshairMarkerBackgroundColor;if(i.length!==0)return i}}return this.js().Ws(t).oe}Nl(){switch(this._n.priceFormat.type){case"custom":this.da={format:this._n.priceFormat.formatter};break;case"volume":this.da=new Ii(this._n.priceFormat.precision);break;case"percent":this.da=new Zt(this._n.priceFormat.precision);break;default:{let t=Math.pow(10,this._n.priceFormat.precision);this.da=new nt(t,this._n.priceFormat.minMove*t)}}this.qi!==null&&this.qi.Sa()}Yl(){let t=this.$t().St();if(!t.ya()||this.Et.Li())return void(this.zl=[]);let i=v(this.Et.Jh());this.zl=this.Vl.map((s,e)=>{let h=v(t.ka(s.time,!0)),r=h<i?1:-1;return{time:v(this.Et.il(h,r)).ie,position:s.position,shape:s.shape,color:s.color,id:s.id,Gr:e,text:s.text,size:s.size,originalTime:s.originalTime}})}Fl(t){switch(this.un=new ls(this,this.$t()),this.Ll){case"Bar":this.mn=new Zi(this,this.$t());break;case"Candlestick":this.mn=new Ji(this,this.$t());break;case"Line":this.mn=new is(this,this.$t());break;case"Custom":this.mn=new ut(this,this.$t(),z(t));break;case"Area":this.mn=new ji(this,this.$t());break;case"Baseline":this.mn=new qi(this,this.$t());break;case"Histogram":this.mn=new ts(this,this.$t());break;default:throw Error("Unknown chart style assigned: "+this.Ll)}}ha(t,i){let s=[];return gi(this.Il,t,i,s),s}},Ss=class{constructor(t){this._n=t}Ca(t,i,s){let e=t;if(this._n.mode===0)return e;let h=s.dn(),r=h.Ct();if(r===null)return e;let o=h.Rt(t,r),l=s.Ta().filter(u=>u instanceof Et).reduce((u,c)=>{if(s.ur(c)||!c.kt())return u;let d=c.Dt(),f=c.Bn();if(d.Li()||!f.Yr(i))return u;let p=f.Uh(i);if(p===null)return u;let m=G(c.Ct());return u.concat([d.Rt(p.Ot[3],m.Ot)])},[]);if(l.length===0)return e;l.sort((u,c)=>Math.abs(u-o)-Math.abs(c-o));let a=l[0];return e=h.fn(a,r),e}},Ms=class extends T{constructor(){super(...arguments),this.Et=null}J(t){this.Et=t}Z({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(this.Et===null)return;let h=Math.max(1,Math.floor(s));t.lineWidth=h,function(r,o){r.save(),r.lineWidth%2&&r.translate(.5,.5),o(),r.restore()}(t,()=>{let r=v(this.Et);if(r.Pa){t.strokeStyle=r.Ra,U(t,r.Da),t.beginPath();for(let o of r.Oa){let l=Math.round(o.Aa*s);t.moveTo(l,-h),t.lineTo(l,i.height+h)}t.stroke()}if(r.Ba){t.strokeStyle=r.Va,U(t,r.za),t.beginPath();for(let o of r.Ea){let l=Math.round(o.Aa*e);t.moveTo(-h,l),t.lineTo(i.width+h,l)}t.stroke()}})}},ys=class{constructor(t){this.Wt=new Ms,this.ft=!0,this.Qi=t}bt(){this.ft=!0}gt(){if(this.ft){let t=this.Qi.$t().W().grid,i={Ba:t.horzLines.visible,Pa:t.vertLines.visible,Va:t.horzLines.color,Ra:t.vertLines.color,za:t.horzLines.style,Da:t.vertLines.style,Ea:this.Qi.dn().Ia(),Oa:(this.Qi.$t().St().Ia()||[]).map(s=>({Aa:s.coord}))};this.Wt.J(i),this.ft=!1}return this.Wt}},xs=class{constructor(t){this.mn=new ys(t)}Fh(){return this.mn}},_i={La:4,Na:1e-4};function tt(n,t){let i=100*(n-t)/t;return t<0?-i:i}function Qn(n,t){let i=tt(n.xh(),t),s=tt(n.Sh(),t);return new R(i,s)}function ft(n,t){let i=100*(n-t)/t+100;return t<0?-i:i}function qn(n,t){let i=ft(n.xh(),t),s=ft(n.Sh(),t);return new R(i,s)}function Gt(n,t){let i=Math.abs(n);if(i<1e-15)return 0;let s=Math.log10(i+t.Na)+t.La;return n<0?-s:s}function mt(n,t){let i=Math.abs(n);if(i<1e-15)return 0;let s=Math.pow(10,i-t.La)-t.Na;return n<0?-s:s}function at(n,t){if(n===null)return null;let i=Gt(n.xh(),t),s=Gt(n.Sh(),t);return new R(i,s)}function Bt(n,t){if(n===null)return null;let i=mt(n.xh(),t),s=mt(n.Sh(),t);return new R(i,s)}function Si(n){if(n===null)return _i;let t=Math.abs(n.Sh()-n.xh());if(t>=1||t<1e-15)return _i;let i=Math.ceil(Math.abs(Math.log10(t))),s=_i.La+i;return{La:s,Na:1/Math.pow(10,s)}}var pt=class{constructor(t,i){if(this.Fa=t,this.Wa=i,function(s){if(s<0)return!1;for(let e=s;e>1;e/=10)if(e%10!=0)return!1;return!0}(this.Fa))this.ja=[2,2.5,2];else{this.ja=[];for(let s=this.Fa;s!==1;){if(s%2==0)this.ja.push(2),s/=2;else{if(s%5!=0)throw new Error("unexpected base");this.ja.push(2,2.5),s/=5}if(this.ja.length>100)throw new Error("something wrong with base")}}}Ha(t,i,s){let e=this.Fa===0?0:1/this.Fa,h=Math.pow(10,Math.max(0,Math.ceil(Math.log10(t-i)))),r=0,o=this.Wa[0];for(;;){let c=Rt(h,e,1e-14)&&h>e+1e-14,d=Rt(h,s*o,1e-14),f=Rt(h,1,1e-14);if(!(c&&d&&f))break;h/=o,o=this.Wa[++r%this.Wa.length]}if(h<=e+1e-14&&(h=e),h=Math.max(1,h),this.ja.length>0&&(l=h,a=1,u=1e-14,Math.abs(l-a)<u))for(r=0,o=this.ja[0];Rt(h,s*o,1e-14)&&h>e+1e-14;)h/=o,o=this.ja[++r%this.ja.length];var l,a,u;return h}},ti=class{constructor(t,i,s,e){this.$a=[],this.Ei=t,this.Fa=i,this.Ua=s,this.qa=e}Ha(t,i){if(t<i)throw new Error("high < low");let s=this.Ei.Vt(),e=(t-i)*this.Ya()/s,h=new pt(this.Fa,[2,2.5,2]),r=new pt(this.Fa,[2,2,2.5]),o=new pt(this.Fa,[2.5,2,2]),l=[];return l.push(h.Ha(t,i,e),r.Ha(t,i,e),o.Ha(t,i,e)),function(a){if(a.length<1)throw Error("array is empty");let u=a[0];for(let c=1;c<a.length;++c)a[c]<u&&(u=a[c]);return u}(l)}Xa(){let t=this.Ei,i=t.Ct();if(i===null)return void(this.$a=[]);let s=t.Vt(),e=this.Ua(s-1,i),h=this.Ua(0,i),r=this.Ei.W().entireTextOnly?this.Ka()/2:0,o=r,l=s-1-r,a=Math.max(e,h),u=Math.min(e,h);if(a===u)return void(this.$a=[]);let c=this.Ha(a,u),d=a%c;d+=d<0?c:0;let f=a>=u?1:-1,p=null,m=0;for(let b=a-d;b>u;b-=c){let g=this.qa(b,i,!0);p!==null&&Math.abs(g-p)<this.Ya()||g<o||g>l||(m<this.$a.length?(this.$a[m].Aa=g,this.$a[m].Za=t.Ga(b)):this.$a.push({Aa:g,Za:t.Ga(b)}),m++,p=g,t.Ja()&&(c=this.Ha(b*f,u)))}this.$a.length=m}Ia(){return this.$a}Ka(){return this.Ei.P()}Ya(){return Math.ceil(2.5*this.Ka())}};function on(n){return n.slice().sort((t,i)=>v(t.Xi())-v(i.Xi()))}var Se;(function(n){n[n.Normal=0]="Normal",n[n.Logarithmic=1]="Logarithmic",n[n.Percentage=2]="Percentage",n[n.IndexedTo100=3]="IndexedTo100"})(Se||(Se={}));var Me=new Zt,ye=new nt(100,1),Es=class{constructor(t,i,s,e){this.Qa=0,this.io=null,this.Rh=null,this.no=null,this.so={eo:!1,ro:null},this.ho=0,this.lo=0,this.ao=new y,this.oo=new y,this._o=[],this.uo=null,this.co=null,this.do=null,this.fo=null,this.da=ye,this.vo=Si(null),this.po=t,this._n=i,this.mo=s,this.bo=e,this.wo=new ti(this,100,this.Mo.bind(this),this.xo.bind(this))}xa(){return this.po}W(){return this._n}Lh(t){if(N(this._n,t),this.Sa(),t.mode!==void 0&&this.So({Sr:t.mode}),t.scaleMargins!==void 0){let i=z(t.scaleMargins.top),s=z(t.scaleMargins.bottom);if(i<0||i>1)throw new Error(`Invalid top margin - expect value between 0 and 1, given=${i}`);if(s<0||s>1)throw new Error(`Invalid bottom margin - expect value between 0 and 1, given=${s}`);if(i+s>1)throw new Error(`Invalid margins - sum of margins must be less than 1, given=${i+s}`);this.yo(),this.co=null}}ko(){return this._n.autoScale}Ja(){return this._n.mode===1}fh(){return this._n.mode===2}Co(){return this._n.mode===3}Sr(){return{Nn:this._n.autoScale,To:this._n.invertScale,Sr:this._n.mode}}So(t){let i=this.Sr(),s=null;t.Nn!==void 0&&(this._n.autoScale=t.Nn),t.Sr!==void 0&&(this._n.mode=t.Sr,t.Sr!==2&&t.Sr!==3||(this._n.autoScale=!0),this.so.eo=!1),i.Sr===1&&t.Sr!==i.Sr&&(function(h,r){if(h===null)return!1;let o=mt(h.xh(),r),l=mt(h.Sh(),r);return isFinite(o)&&isFinite(l)}(this.Rh,this.vo)?(s=Bt(this.Rh,this.vo),s!==null&&this.Po(s)):this._n.autoScale=!0),t.Sr===1&&t.Sr!==i.Sr&&(s=at(this.Rh,this.vo),s!==null&&this.Po(s));let e=i.Sr!==this._n.mode;e&&(i.Sr===2||this.fh())&&this.Sa(),e&&(i.Sr===3||this.Co())&&this.Sa(),t.To!==void 0&&i.To!==t.To&&(this._n.invertScale=t.To,this.Ro()),this.oo.m(i,this.Sr())}Do(){return this.oo}P(){return this.mo.fontSize}Vt(){return this.Qa}Oo(t){this.Qa!==t&&(this.Qa=t,this.yo(),this.co=null)}Ao(){if(this.io)return this.io;let t=this.Vt()-this.Bo()-this.Vo();return this.io=t,t}Oh(){return this.zo(),this.Rh}Po(t,i){let s=this.Rh;(i||s===null&&t!==null||s!==null&&!s.gh(t))&&(this.co=null,this.Rh=t)}Li(){return this.zo(),this.Qa===0||!this.Rh||this.Rh.Li()}Eo(t){return this.To()?t:this.Vt()-1-t}Rt(t,i){return this.fh()?t=tt(t,i):this.Co()&&(t=ft(t,i)),this.xo(t,i)}Gs(t,i,s){this.zo();let e=this.Vo(),h=v(this.Oh()),r=h.xh(),o=h.Sh(),l=this.Ao()-1,a=this.To(),u=l/(o-r),c=s===void 0?0:s.from,d=s===void 0?t.length:s.to,f=this.Io();for(let p=c;p<d;p++){let m=t[p],b=m._t;if(isNaN(b))continue;let g=b;f!==null&&(g=f(m._t,i));let w=e+u*(g-r),S=a?w:this.Qa-1-w;m.st=S}}ve(t,i,s){this.zo();let e=this.Vo(),h=v(this.Oh()),r=h.xh(),o=h.Sh(),l=this.Ao()-1,a=this.To(),u=l/(o-r),c=s===void 0?0:s.from,d=s===void 0?t.length:s.to,f=this.Io();for(let p=c;p<d;p++){let m=t[p],b=m.me,g=m.be,w=m.we,S=m.ge;f!==null&&(b=f(m.me,i),g=f(m.be,i),w=f(m.we,i),S=f(m.ge,i));let _=e+u*(b-r),x=a?_:this.Qa-1-_;m.de=x,_=e+u*(g-r),x=a?_:this.Qa-1-_,m._e=x,_=e+u*(w-r),x=a?_:this.Qa-1-_,m.ue=x,_=e+u*(S-r),x=a?_:this.Qa-1-_,m.fe=x}}fn(t,i){let s=this.Mo(t,i);return this.Lo(s,i)}Lo(t,i){let s=t;return this.fh()?s=function(e,h){return h<0&&(e=-e),e/100*h+h}(s,i):this.Co()&&(s=function(e,h){return e-=100,h<0&&(e=-e),e/100*h+h}(s,i)),s}Ta(){return this._o}No(){if(this.uo)return this.uo;let t=[];for(let i=0;i<this._o.length;i++){let s=this._o[i];s.Xi()===null&&s.Ki(i+1),t.push(s)}return t=on(t),this.uo=t,this.uo}Fo(t){this._o.indexOf(t)===-1&&(this._o.push(t),this.Sa(),this.Wo())}jo(t){let i=this._o.indexOf(t);if(i===-1)throw new Error("source is not attached to scale");this._o.splice(i,1),this._o.length===0&&(this.So({Nn:!0}),this.Po(null)),this.Sa(),this.Wo()}Ct(){let t=null;for(let i of this._o){let s=i.Ct();s!==null&&(t===null||s.ia<t.ia)&&(t=s)}return t===null?null:t.Ot}To(){return this._n.invertScale}Ia(){let t=this.Ct()===null;if(this.co!==null&&(t||this.co.Ho===t))return this.co.Ia;this.wo.Xa();let i=this.wo.Ia();return this.co={Ia:i,Ho:t},this.ao.m(),i}$o(){return this.ao}Uo(t){this.fh()||this.Co()||this.do===null&&this.no===null&&(this.Li()||(this.do=this.Qa-t,this.no=v(this.Oh()).Mh()))}qo(t){if(this.fh()||this.Co()||this.do===null)return;this.So({Nn:!1}),(t=this.Qa-t)<0&&(t=0);let i=(this.do+.2*(this.Qa-1))/(t+.2*(this.Qa-1)),s=v(this.no).Mh();i=Math.max(i,.1),s.kh(i),this.Po(s)}Yo(){this.fh()||this.Co()||(this.do=null,this.no=null)}Xo(t){this.ko()||this.fo===null&&this.no===null&&(this.Li()||(this.fo=t,this.no=v(this.Oh()).Mh()))}Ko(t){if(this.ko()||this.fo===null)return;let i=v(this.Oh()).yh()/(this.Ao()-1),s=t-this.fo;this.To()&&(s*=-1);let e=s*i,h=v(this.no).Mh();h.Ch(e),this.Po(h,!0),this.co=null}Zo(){this.ko()||this.fo!==null&&(this.fo=null,this.no=null)}ca(){return this.da||this.Sa(),this.da}Ni(t,i){switch(this._n.mode){case 2:return this.Go(tt(t,i));case 3:return this.ca().format(ft(t,i));default:return this.zh(t)}}Ga(t){switch(this._n.mode){case 2:return this.Go(t);case 3:return this.ca().format(t);default:return this.zh(t)}}Wl(t){return this.zh(t,v(this.Jo()).ca())}jl(t,i){return t=tt(t,i),this.Go(t,Me)}Qo(){return this._o}t_(t){this.so={ro:t,eo:!1}}Rn(){this._o.forEach(t=>t.Rn())}Sa(){this.co=null;let t=this.Jo(),i=100;t!==null&&(i=Math.round(1/t.ua())),this.da=ye,this.fh()?(this.da=Me,i=100):this.Co()?(this.da=new nt(100,1),i=100):t!==null&&(this.da=t.ca()),this.wo=new ti(this,i,this.Mo.bind(this),this.xo.bind(this)),this.wo.Xa()}Wo(){this.uo=null}Jo(){return this._o[0]||null}Bo(){return this.To()?this._n.scaleMargins.bottom*this.Vt()+this.lo:this._n.scaleMargins.top*this.Vt()+this.ho}Vo(){return this.To()?this._n.scaleMargins.top*this.Vt()+this.ho:this._n.scaleMargins.bottom*this.Vt()+this.lo}zo(){this.so.eo||(this.so.eo=!0,this.i_())}yo(){this.io=null}xo(t,i){if(this.zo(),this.Li())return 0;t=this.Ja()&&t?Gt(t,this.vo):t;let s=v(this.Oh()),e=this.Vo()+(this.Ao()-1)*(t-s.xh())/s.yh();return this.Eo(e)}Mo(t,i){if(this.zo(),this.Li())return 0;let s=this.Eo(t),e=v(this.Oh()),h=e.xh()+e.yh()*((s-this.Vo())/(this.Ao()-1));return this.Ja()?mt(h,this.vo):h}Ro(){this.co=null,this.wo.Xa()}i_(){let t=this.so.ro;if(t===null)return;let i=null,s=this.Qo(),e=0,h=0;for(let l of s){if(!l.kt())continue;let a=l.Ct();if(a===null)continue;let u=l.Rl(t.Rs(),t.ui()),c=u&&u.Oh();if(c!==null){switch(this._n.mode){case 1:c=at(c,this.vo);break;case 2:c=Qn(c,a.Ot);break;case 3:c=qn(c,a.Ot)}if(i=i===null?c:i.Jn(v(c)),u!==null){let d=u.Ah();d!==null&&(e=Math.max(e,d.above),h=Math.max(e,d.below))}}}if(e===this.ho&&h===this.lo||(this.ho=e,this.lo=h,this.co=null,this.yo()),i!==null){if(i.xh()===i.Sh()){let l=this.Jo(),a=5*(l===null||this.fh()||this.Co()?1:l.ua());this.Ja()&&(i=Bt(i,this.vo)),i=new R(i.xh()-a,i.Sh()+a),this.Ja()&&(i=at(i,this.vo))}if(this.Ja()){let l=Bt(i,this.vo),a=Si(l);if(r=a,o=this.vo,r.La!==o.La||r.Na!==o.Na){let u=this.no!==null?Bt(this.no,this.vo):null;this.vo=a,i=at(l,a),u!==null&&(this.no=at(u,a))}}this.Po(i)}else this.Rh===null&&(this.Po(new R(-.5,.5)),this.vo=Si(null));var r,o;this.so.eo=!0}Io(){return this.fh()?tt:this.Co()?ft:this.Ja()?t=>Gt(t,this.vo):null}n_(t,i,s){return i===void 0?(s===void 0&&(s=this.ca()),s.format(t)):i(t)}zh(t,i){return this.n_(t,this.bo.priceFormatter,i)}Go(t,i){return this.n_(t,this.bo.percentageFormatter,i)}},Cs=class{constructor(t,i){this._o=[],this.s_=new Map,this.Qa=0,this.e_=0,this.r_=1e3,this.uo=null,this.h_=new y,this.wl=t,this.Hi=i,this.l_=new xs(this);let s=i.W();this.a_=this.o_("left",s.leftPriceScale),this.__=this.o_("right",s.rightPriceScale),this.a_.Do().l(this.u_.bind(this,this.a_),this),this.__.Do().l(this.u_.bind(this,this.__),this),this.c_(s)}c_(t){if(t.leftPriceScale&&this.a_.Lh(t.leftPriceScale),t.rightPriceScale&&this.__.Lh(t.rightPriceScale),t.localization&&(this.a_.Sa(),this.__.Sa()),t.overlayPriceScales){let i=Array.from(this.s_.values());for(let s of i){let e=v(s[0].Dt());e.Lh(t.overlayPriceScales),t.localization&&e.Sa()}}}d_(t){switch(t){case"left":return this.a_;case"right":return this.__}return this.s_.has(t)?z(this.s_.get(t))[0].Dt():null}S(){this.$t().f_().p(this),this.a_.Do().p(this),this.__.Do().p(this),this._o.forEach(t=>{t.S&&t.S()}),this.h_.m()}v_(){return this.r_}p_(t){this.r_=t}$t(){return this.Hi}ji(){return this.e_}Vt(){return this.Qa}m_(t){this.e_=t,this.b_()}Oo(t){this.Qa=t,this.a_.Oo(t),this.__.Oo(t),this._o.forEach(i=>{if(this.ur(i)){let s=i.Dt();s!==null&&s.Oo(t)}}),this.b_()}Ta(){return this._o}ur(t){let i=t.Dt();return i===null||this.a_!==i&&this.__!==i}Fo(t,i,s){let e=s!==void 0?s:this.g_().w_+1;this.M_(t,i,e)}jo(t){let i=this._o.indexOf(t);I(i!==-1,"removeDataSource: invalid data source"),this._o.splice(i,1);let s=v(t.Dt()).xa();if(this.s_.has(s)){let h=z(this.s_.get(s)),r=h.indexOf(t);r!==-1&&(h.splice(r,1),h.length===0&&this.s_.delete(s))}let e=t.Dt();e&&e.Ta().indexOf(t)>=0&&e.jo(t),e!==null&&(e.Wo(),this.x_(e)),this.uo=null}dr(t){return t===this.a_?"left":t===this.__?"right":"overlay"}S_(){return this.a_}y_(){return this.__}k_(t,i){t.Uo(i)}C_(t,i){t.qo(i),this.b_()}T_(t){t.Yo()}P_(t,i){t.Xo(i)}R_(t,i){t.Ko(i),this.b_()}D_(t){t.Zo()}b_(){this._o.forEach(t=>{t.Rn()})}dn(){let t=null;return this.Hi.W().rightPriceScale.visible&&this.__.Ta().length!==0?t=this.__:this.Hi.W().leftPriceScale.visible&&this.a_.Ta().length!==0?t=this.a_:this._o.length!==0&&(t=this._o[0].Dt()),t===null&&(t=this.__),t}cr(){let t=null;return this.Hi.W().rightPriceScale.visible?t=this.__:this.Hi.W().leftPriceScale.visible&&(t=this.a_),t}x_(t){t!==null&&t.ko()&&this.O_(t)}A_(t){let i=this.wl.qs();t.So({Nn:!0}),i!==null&&t.t_(i),this.b_()}B_(){this.O_(this.a_),this.O_(this.__)}V_(){this.x_(this.a_),this.x_(this.__),this._o.forEach(t=>{this.ur(t)&&this.x_(t.Dt())}),this.b_(),this.Hi.Nh()}No(){return this.uo===null&&(this.uo=on(this._o)),this.uo}z_(){return this.h_}E_(){return this.l_}O_(t){let i=t.Qo();if(i&&i.length>0&&!this.wl.Li()){let s=this.wl.qs();s!==null&&t.t_(s)}t.Rn()}g_(){let t=this.No();if(t.length===0)return{I_:0,w_:0};let i=0,s=0;for(let e=0;e<t.length;e++){let h=t[e].Xi();h!==null&&(h<i&&(i=h),h>s&&(s=h))}return{I_:i,w_:s}}M_(t,i,s){let e=this.d_(i);if(e===null&&(e=this.o_(i,this.Hi.W().overlayPriceScales)),this._o.push(t),!li(i)){let h=this.s_.get(i)||[];h.push(t),this.s_.set(i,h)}e.Fo(t),t.Zi(e),t.Ki(s),this.x_(e),this.uo=null}u_(t,i,s){i.Sr!==s.Sr&&this.O_(t)}o_(t,i){let s=Object.assign({visible:!0,autoScale:!0},A(i)),e=new Es(t,s,this.Hi.W().layout,this.Hi.W().localization);return e.Oo(this.Vt()),e}},ks=class{constructor(t,i,s=50){this.Ye=0,this.Xe=1,this.Ke=1,this.Ge=new Map,this.Ze=new Map,this.L_=t,this.N_=i,this.Je=s}F_(t){let i=t.time,s=this.N_.cacheKey(i),e=this.Ge.get(s);if(e!==void 0)return e.W_;if(this.Ye===this.Je){let r=this.Ze.get(this.Ke);this.Ze.delete(this.Ke),this.Ge.delete(z(r)),this.Ke++,this.Ye--}let h=this.L_(t);return this.Ge.set(s,{W_:h,nr:this.Xe}),this.Ze.set(this.Xe,s),this.Ye++,this.Xe++,h}},F=class{constructor(t,i){I(t<=i,"right should be >= left"),this.j_=t,this.H_=i}Rs(){return this.j_}ui(){return this.H_}U_(){return this.H_-this.j_+1}Yr(t){return this.j_<=t&&t<=this.H_}gh(t){return this.j_===t.Rs()&&this.H_===t.ui()}};function xe(n,t){return n===null||t===null?n===t:n.gh(t)}var zs=class{constructor(){this.q_=new Map,this.Ge=null,this.Y_=!1}X_(t){this.Y_=t,this.Ge=null}K_(t,i){this.Z_(i),this.Ge=null;for(let s=i;s<t.length;++s){let e=t[s],h=this.q_.get(e.timeWeight);h===void 0&&(h=[],this.q_.set(e.timeWeight,h)),h.push({index:s,time:e.time,weight:e.timeWeight,originalTime:e.originalTime})}}G_(t,i){let s=Math.ceil(i/t);return this.Ge!==null&&this.Ge.J_===s||(this.Ge={Ia:this.Q_(s),J_:s}),this.Ge.Ia}Z_(t){if(t===0)return void this.q_.clear();let i=[];this.q_.forEach((s,e)=>{t<=s[0].index?i.push(e):s.splice(zt(s,t,h=>h.index<t),1/0)});for(let s of i)this.q_.delete(s)}Q_(t){let i=[];for(let s of Array.from(this.q_.keys()).sort((e,h)=>h-e)){if(!this.q_.get(s))continue;let e=i;i=[];let h=e.length,r=0,o=z(this.q_.get(s)),l=o.length,a=1/0,u=-1/0;for(let c=0;c<l;c++){let d=o[c],f=d.index;for(;r<h;){let p=e[r],m=p.index;if(!(m<f)){a=m;break}r++,i.push(p),u=m,a=1/0}if(a-f>=t&&f-u>=t)i.push(d),u=f;else if(this.Y_)return e}for(;r<h;r++)i.push(e[r])}return i}},it=class n{constructor(t){this.tu=t}iu(){return this.tu===null?null:new F(Math.floor(this.tu.Rs()),Math.ceil(this.tu.ui()))}nu(){return this.tu}static su(){return new n(null)}};function Xn(n,t){return n.weight>t.weight?n:t}var Ts=class{constructor(t,i,s,e){this.e_=0,this.eu=null,this.ru=[],this.fo=null,this.do=null,this.hu=new zs,this.lu=new Map,this.au=it.su(),this.ou=!0,this._u=new y,this.uu=new y,this.cu=new y,this.du=null,this.fu=null,this.vu=[],this._n=i,this.bo=s,this.pu=i.rightOffset,this.mu=i.barSpacing,this.Hi=t,this.N_=e,this.bu(),this.hu.X_(i.uniformDistribution)}W(){return this._n}wu(t){N(this.bo,t),this.gu(),this.bu()}Lh(t,i){var s;N(this._n,t),this._n.fixLeftEdge&&this.Mu(),this._n.fixRightEdge&&this.xu(),t.barSpacing!==void 0&&this.Hi.Kn(t.barSpacing),t.rightOffset!==void 0&&this.Hi.Zn(t.rightOffset),t.minBarSpacing!==void 0&&this.Hi.Kn((s=t.barSpacing)!==null&&s!==void 0?s:this.mu),this.gu(),this.bu(),this.cu.m()}vn(t){var i,s;return(s=(i=this.ru[t])===null||i===void 0?void 0:i.time)!==null&&s!==void 0?s:null}$i(t){var i;return(i=this.ru[t])!==null&&i!==void 0?i:null}ka(t,i){if(this.ru.length<1)return null;if(this.N_.key(t)>this.N_.key(this.ru[this.ru.length-1].time))return i?this.ru.length-1:null;let s=zt(this.ru,this.N_.key(t),(e,h)=>this.N_.key(e.time)<h);return this.N_.key(t)<this.N_.key(this.ru[s].time)?i?s:null:s}Li(){return this.e_===0||this.ru.length===0||this.eu===null}ya(){return this.ru.length>0}qs(){return this.Su(),this.au.iu()}yu(){return this.Su(),this.au.nu()}ku(){let t=this.qs();if(t===null)return null;let i={from:t.Rs(),to:t.ui()};return this.Cu(i)}Cu(t){let i=Math.round(t.from),s=Math.round(t.to),e=v(this.Tu()),h=v(this.Pu());return{from:v(this.$i(Math.max(e,i))),to:v(this.$i(Math.min(h,s)))}}Ru(t){return{from:v(this.ka(t.from,!0)),to:v(this.ka(t.to,!0))}}ji(){return this.e_}m_(t){if(!isFinite(t)||t<=0||this.e_===t)return;let i=this.yu(),s=this.e_;if(this.e_=t,this.ou=!0,this._n.lockVisibleTimeRangeOnResize&&s!==0){let e=this.mu*t/s;this.mu=e}if(this._n.fixLeftEdge&&i!==null&&i.Rs()<=0){let e=s-t;this.pu-=Math.round(e/this.mu)+1,this.ou=!0}this.Du(),this.Ou()}zt(t){if(this.Li()||!bt(t))return 0;let i=this.Au()+this.pu-t;return this.e_-(i+.5)*this.mu-1}Zs(t,i){let s=this.Au(),e=i===void 0?0:i.from,h=i===void 0?t.length:i.to;for(let r=e;r<h;r++){let o=t[r].ot,l=s+this.pu-o,a=this.e_-(l+.5)*this.mu-1;t[r].nt=a}}Bu(t){return Math.ceil(this.Vu(t))}Zn(t){this.ou=!0,this.pu=t,this.Ou(),this.Hi.zu(),this.Hi.Nh()}ee(){return this.mu}Kn(t){this.Eu(t),this.Ou(),this.Hi.zu(),this.Hi.Nh()}Iu(){return this.pu}Ia(){if(this.Li())return null;if(this.fu!==null)return this.fu;let t=this.mu,i=5*(this.Hi.W().layout.fontSize+4)/8*(this._n.tickMarkMaxCharacterLength||8),s=Math.round(i/t),e=v(this.qs()),h=Math.max(e.Rs(),e.Rs()-s),r=Math.max(e.ui(),e.ui()-s),o=this.hu.G_(t,i),l=this.Tu()+s,a=this.Pu()-s,u=this.Lu(),c=this._n.fixLeftEdge||u,d=this._n.fixRightEdge||u,f=0;for(let p of o){if(!(h<=p.index&&p.index<=r))continue;let m;f<this.vu.length?(m=this.vu[f],m.coord=this.zt(p.index),m.label=this.Nu(p),m.weight=p.weight):(m={needAlignCoordinate:!1,coord:this.zt(p.index),label:this.Nu(p),weight:p.weight},this.vu.push(m)),this.mu>i/2&&!u?m.needAlignCoordinate=!1:m.needAlignCoordinate=c&&p.index<=l||d&&p.index>=a,f++}return this.vu.length=f,this.fu=this.vu,this.vu}Fu(){this.ou=!0,this.Kn(this._n.barSpacing),this.Zn(this._n.rightOffset)}Wu(t){this.ou=!0,this.eu=t,this.Ou(),this.Mu()}ju(t,i){let s=this.Vu(t),e=this.ee(),h=e+i*(e/10);this.Kn(h),this._n.rightBarStaysOnScroll||this.Zn(this.Iu()+(s-this.Vu(t)))}Uo(t){this.fo&&this.Zo(),this.do===null&&this.du===null&&(this.Li()||(this.do=t,this.Hu()))}qo(t){if(this.du===null)return;let i=$i(this.e_-t,0,this.e_),s=$i(this.e_-v(this.do),0,this.e_);i!==0&&s!==0&&this.Kn(this.du.ee*i/s)}Yo(){this.do!==null&&(this.do=null,this.$u())}Xo(t){this.fo===null&&this.du===null&&(this.Li()||(this.fo=t,this.Hu()))}Ko(t){if(this.fo===null)return;let i=(this.fo-t)/this.ee();this.pu=v(this.du).Iu+i,this.ou=!0,this.Ou()}Zo(){this.fo!==null&&(this.fo=null,this.$u())}Uu(){this.qu(this._n.rightOffset)}qu(t,i=400){if(!isFinite(t))throw new RangeError("offset is required and must be finite number");if(!isFinite(i)||i<=0)throw new RangeError("animationDuration (optional) must be finite positive number");let s=this.pu,e=performance.now();this.Hi.qn({Yu:h=>(h-e)/i>=1,Xu:h=>{let r=(h-e)/i;return r>=1?t:s+(t-s)*r}})}bt(t,i){this.ou=!0,this.ru=t,this.hu.K_(t,i),this.Ou()}Ku(){return this._u}Zu(){return this.uu}Gu(){return this.cu}Au(){return this.eu||0}Ju(t){let i=t.U_();this.Eu(this.e_/i),this.pu=t.ui()-this.Au(),this.Ou(),this.ou=!0,this.Hi.zu(),this.Hi.Nh()}Qu(){let t=this.Tu(),i=this.Pu();t!==null&&i!==null&&this.Ju(new F(t,i+this._n.rightOffset))}tc(t){let i=new F(t.from,t.to);this.Ju(i)}Ui(t){return this.bo.timeFormatter!==void 0?this.bo.timeFormatter(t.originalTime):this.N_.formatHorzItem(t.time)}Lu(){let{handleScroll:t,handleScale:i}=this.Hi.W();return!(t.horzTouchDrag||t.mouseWheel||t.pressedMouseMove||t.vertTouchDrag||i.axisDoubleClickReset.time||i.axisPressedMouseMove.time||i.mouseWheel||i.pinch)}Tu(){return this.ru.length===0?null:0}Pu(){return this.ru.length===0?null:this.ru.length-1}ic(t){return(this.e_-1-t)/this.mu}Vu(t){let i=this.ic(t),s=this.Au()+this.pu-i;return Math.round(1e6*s)/1e6}Eu(t){let i=this.mu;this.mu=t,this.Du(),i!==this.mu&&(this.ou=!0,this.nc())}Su(){if(!this.ou)return;if(this.ou=!1,this.Li())return void this.sc(it.su());let t=this.Au(),i=this.e_/this.mu,s=this.pu+t,e=new F(s-i+1,s);this.sc(new it(e))}Du(){let t=this.ec();if(this.mu<t&&(this.mu=t,this.ou=!0),this.e_!==0){let i=.5*this.e_;this.mu>i&&(this.mu=i,this.ou=!0)}}ec(){return this._n.fixLeftEdge&&this._n.fixRightEdge&&this.ru.length!==0?this.e_/this.ru.length:this._n.minBarSpacing}Ou(){let t=this.rc();this.pu>t&&(this.pu=t,this.ou=!0);let i=this.hc();i!==null&&this.pu<i&&(this.pu=i,this.ou=!0)}hc(){let t=this.Tu(),i=this.eu;return t===null||i===null?null:t-i-1+(this._n.fixLeftEdge?this.e_/this.mu:Math.min(2,this.ru.length))}rc(){return this._n.fixRightEdge?0:this.e_/this.mu-Math.min(2,this.ru.length)}Hu(){this.du={ee:this.ee(),Iu:this.Iu()}}$u(){this.du=null}Nu(t){let i=this.lu.get(t.weight);return i===void 0&&(i=new ks(s=>this.lc(s),this.N_),this.lu.set(t.weight,i)),i.F_(t)}lc(t){return this.N_.formatTickmark(t,this.bo)}sc(t){let i=this.au;this.au=t,xe(i.iu(),this.au.iu())||this._u.m(),xe(i.nu(),this.au.nu())||this.uu.m(),this.nc()}nc(){this.fu=null}gu(){this.nc(),this.lu.clear()}bu(){this.N_.updateFormatter(this.bo)}Mu(){if(!this._n.fixLeftEdge)return;let t=this.Tu();if(t===null)return;let i=this.qs();if(i===null)return;let s=i.Rs()-t;if(s<0){let e=this.pu-s-1;this.Zn(e)}this.Du()}xu(){this.Ou(),this.Du()}},Os=class{K(t,i,s){t.useMediaCoordinateSpace(e=>this.Z(e,i,s))}fl(t,i,s){t.useMediaCoordinateSpace(e=>this.ac(e,i,s))}ac(t,i,s){}},Ls=class extends Os{constructor(t){super(),this.oc=new Map,this.Et=t}Z(t){}ac(t){if(!this.Et.kt)return;let{context:i,mediaSize:s}=t,e=0;for(let r of this.Et._c){if(r.Zt.length===0)continue;i.font=r.R;let o=this.uc(i,r.Zt);o>s.width?r.ju=s.width/o:r.ju=1,e+=r.cc*r.ju}let h=0;switch(this.Et.dc){case"top":h=0;break;case"center":h=Math.max((s.height-e)/2,0);break;case"bottom":h=Math.max(s.height-e,0)}i.fillStyle=this.Et.O;for(let r of this.Et._c){i.save();let o=0;switch(this.Et.fc){case"left":i.textAlign="left",o=r.cc/2;break;case"center":i.textAlign="center",o=s.width/2;break;case"right":i.textAlign="right",o=s.width-1-r.cc/2}i.translate(o,h),i.textBaseline="top",i.font=r.R,i.scale(r.ju,r.ju),i.fillText(r.Zt,0,r.vc),i.restore(),h+=r.cc*r.ju}}uc(t,i){let s=this.mc(t.font),e=s.get(i);return e===void 0&&(e=t.measureText(i).width,s.set(i,e)),e}mc(t){let i=this.oc.get(t);return i===void 0&&(i=new Map,this.oc.set(t,i)),i}},Rs=class{constructor(t){this.ft=!0,this.Ft={kt:!1,O:"",_c:[],dc:"center",fc:"center"},this.Wt=new Ls(this.Ft),this.jt=t}bt(){this.ft=!0}gt(){return this.ft&&(this.Mt(),this.ft=!1),this.Wt}Mt(){let t=this.jt.W(),i=this.Ft;i.kt=t.visible,i.kt&&(i.O=t.color,i.fc=t.horzAlign,i.dc=t.vertAlign,i._c=[{Zt:t.text,R:st(t.fontSize,t.fontFamily,t.fontStyle),cc:1.2*t.fontSize,vc:0,ju:0}])}},Ns=class extends _t{constructor(t,i){super(),this._n=i,this.mn=new Rs(this)}Tn(){return[]}Cn(){return[this.mn]}W(){return this._n}Rn(){this.mn.bt()}},Ee,Ce,ke,ii,ze;(function(n){n[n.OnTouchEnd=0]="OnTouchEnd",n[n.OnNextTap=1]="OnNextTap"})(Ee||(Ee={}));var Bs=class{constructor(t,i,s){this.bc=[],this.wc=[],this.e_=0,this.gc=null,this.Mc=new y,this.xc=new y,this.Sc=null,this.yc=t,this._n=i,this.N_=s,this.kc=new Ni(this),this.wl=new Ts(this,i.timeScale,this._n.localization,s),this.vt=new Hi(this,i.crosshair),this.Cc=new Ss(i.crosshair),this.Tc=new Ns(this,i.watermark),this.Pc(),this.bc[0].p_(2e3),this.Rc=this.Dc(0),this.Oc=this.Dc(1)}$l(){this.Ac(C.ns())}Nh(){this.Ac(C.ts())}sa(){this.Ac(new C(1))}Ul(t){let i=this.Bc(t);this.Ac(i)}Vc(){return this.gc}zc(t){let i=this.gc;this.gc=t,i!==null&&this.Ul(i.Ec),t!==null&&this.Ul(t.Ec)}W(){return this._n}Lh(t){N(this._n,t),this.bc.forEach(i=>i.c_(t)),t.timeScale!==void 0&&this.wl.Lh(t.timeScale),t.localization!==void 0&&this.wl.wu(t.localization),(t.leftPriceScale||t.rightPriceScale)&&this.Mc.m(),this.Rc=this.Dc(0),this.Oc=this.Dc(1),this.$l()}Ic(t,i){if(t==="left")return void this.Lh({leftPriceScale:i});if(t==="right")return void this.Lh({rightPriceScale:i});let s=this.Lc(t);s!==null&&(s.Dt.Lh(i),this.Mc.m())}Lc(t){for(let i of this.bc){let s=i.d_(t);if(s!==null)return{Ht:i,Dt:s}}return null}St(){return this.wl}Nc(){return this.bc}Fc(){return this.Tc}Wc(){return this.vt}jc(){return this.xc}Hc(t,i){t.Oo(i),this.zu()}m_(t){this.e_=t,this.wl.m_(this.e_),this.bc.forEach(i=>i.m_(t)),this.zu()}Pc(t){let i=new Cs(this.wl,this);t!==void 0?this.bc.splice(t,0,i):this.bc.push(i);let s=t===void 0?this.bc.length-1:t,e=C.ns();return e.In(s,{Ln:0,Nn:!0}),this.Ac(e),i}k_(t,i,s){t.k_(i,s)}C_(t,i,s){t.C_(i,s),this.ql(),this.Ac(this.$c(t,2))}T_(t,i){t.T_(i),this.Ac(this.$c(t,2))}P_(t,i,s){i.ko()||t.P_(i,s)}R_(t,i,s){i.ko()||(t.R_(i,s),this.ql(),this.Ac(this.$c(t,2)))}D_(t,i){i.ko()||(t.D_(i),this.Ac(this.$c(t,2)))}A_(t,i){t.A_(i),this.Ac(this.$c(t,2))}Uc(t){this.wl.Uo(t)}qc(t,i){let s=this.St();if(s.Li()||i===0)return;let e=s.ji();t=Math.max(1,Math.min(t,e)),s.ju(t,i),this.zu()}Yc(t){this.Xc(0),this.Kc(t),this.Zc()}Gc(t){this.wl.qo(t),this.zu()}Jc(){this.wl.Yo(),this.Nh()}Xc(t){this.wl.Xo(t)}Kc(t){this.wl.Ko(t),this.zu()}Zc(){this.wl.Zo(),this.Nh()}wt(){return this.wc}Qc(t,i,s,e,h){this.vt.bn(t,i);let r=NaN,o=this.wl.Bu(t),l=this.wl.qs();l!==null&&(o=Math.min(Math.max(l.Rs(),o),l.ui()));let a=e.dn(),u=a.Ct();u!==null&&(r=a.fn(i,u)),r=this.Cc.Ca(r,o,e),this.vt.xn(o,r,e),this.sa(),h||this.xc.m(this.vt.xt(),{x:t,y:i},s)}td(t,i,s){let e=s.dn(),h=e.Ct(),r=e.Rt(t,v(h)),o=this.wl.ka(i,!0),l=this.wl.zt(v(o));this.Qc(l,r,null,s,!0)}nd(t){this.Wc().yn(),this.sa(),t||this.xc.m(null,null,null)}ql(){let t=this.vt.Ht();if(t!==null){let i=this.vt.gn(),s=this.vt.Mn();this.Qc(i,s,null,t)}this.vt.Rn()}sd(t,i,s){let e=this.wl.vn(0);i!==void 0&&s!==void 0&&this.wl.bt(i,s);let h=this.wl.vn(0),r=this.wl.Au(),o=this.wl.qs();if(o!==null&&e!==null&&h!==null){let l=o.Yr(r),a=this.N_.key(e)>this.N_.key(h),u=t!==null&&t>r&&!a,c=this.wl.W().allowShiftVisibleRangeOnWhitespaceReplacement,d=l&&(s!==void 0||c)&&this.wl.W().shiftVisibleRangeOnNewBar;if(u&&!d){let f=t-r;this.wl.Zn(this.wl.Iu()-f)}}this.wl.Wu(t)}Kl(t){t!==null&&t.V_()}_r(t){let i=this.bc.find(s=>s.No().includes(t));return i===void 0?null:i}zu(){this.Tc.Rn(),this.bc.forEach(t=>t.V_()),this.ql()}S(){this.bc.forEach(t=>t.S()),this.bc.length=0,this._n.localization.priceFormatter=void 0,this._n.localization.percentageFormatter=void 0,this._n.localization.timeFormatter=void 0}ed(){return this.kc}vr(){return this.kc.W()}f_(){return this.Mc}rd(t,i,s){let e=this.bc[0],h=this.hd(i,t,e,s);return this.wc.push(h),this.wc.length===1?this.$l():this.Nh(),h}ld(t){let i=this._r(t),s=this.wc.indexOf(t);I(s!==-1,"Series not found"),this.wc.splice(s,1),v(i).jo(t),t.S&&t.S()}Hl(t,i){let s=v(this._r(t));s.jo(t);let e=this.Lc(i);if(e===null){let h=t.Xi();s.Fo(t,i,h)}else{let h=e.Ht===s?t.Xi():void 0;e.Ht.Fo(t,i,h)}}Qu(){let t=C.ts();t.jn(),this.Ac(t)}ad(t){let i=C.ts();i.Un(t),this.Ac(i)}Xn(){let t=C.ts();t.Xn(),this.Ac(t)}Kn(t){let i=C.ts();i.Kn(t),this.Ac(i)}Zn(t){let i=C.ts();i.Zn(t),this.Ac(i)}qn(t){let i=C.ts();i.qn(t),this.Ac(i)}Hn(){let t=C.ts();t.Hn(),this.Ac(t)}od(){return this._n.rightPriceScale.visible?"right":"left"}_d(){return this.Oc}q(){return this.Rc}Bt(t){let i=this.Oc,s=this.Rc;if(i===s)return i;if(t=Math.max(0,Math.min(100,Math.round(100*t))),this.Sc===null||this.Sc.ks!==s||this.Sc.Cs!==i)this.Sc={ks:s,Cs:i,ud:new Map};else{let h=this.Sc.ud.get(t);if(h!==void 0)return h}let e=function(h,r,o){let[l,a,u,c]=Ft(h),[d,f,p,m]=Ft(r),b=[L(l+o*(d-l)),L(a+o*(f-a)),L(u+o*(p-u)),Xe(c+o*(m-c))];return`rgba(${b[0]}, ${b[1]}, ${b[2]}, ${b[3]})`}(s,i,t/100);return this.Sc.ud.set(t,e),e}$c(t,i){let s=new C(i);if(t!==null){let e=this.bc.indexOf(t);s.In(e,{Ln:i})}return s}Bc(t,i){return i===void 0&&(i=2),this.$c(this._r(t),i)}Ac(t){this.yc&&this.yc(t),this.bc.forEach(i=>i.E_().Fh().bt())}hd(t,i,s,e){let h=new Et(this,t,i,s,e),r=t.priceScaleId!==void 0?t.priceScaleId:this.od();return s.Fo(h,r),li(r)||h.Lh(t),h}Dc(t){let i=this._n.layout;return i.background.type==="gradient"?t===0?i.background.topColor:i.background.bottomColor:i.background.color}};function Ps(n){return!D(n)&&!kt(n)}function ln(n){return D(n)}(function(n){n[n.Disabled=0]="Disabled",n[n.Continuous=1]="Continuous",n[n.OnDataUpdate=2]="OnDataUpdate"})(Ce||(Ce={})),function(n){n[n.LastBar=0]="LastBar",n[n.LastVisible=1]="LastVisible"}(ke||(ke={})),function(n){n.Solid="solid",n.VerticalGradient="gradient"}(ii||(ii={})),function(n){n[n.Year=0]="Year",n[n.Month=1]="Month",n[n.DayOfMonth=2]="DayOfMonth",n[n.Time=3]="Time",n[n.TimeWithSeconds=4]="TimeWithSeconds"}(ze||(ze={}));var Te=n=>n.getUTCFullYear();function Jn(n,t,i){return t.replace(/yyyy/g,(s=>W(Te(s),4))(n)).replace(/yy/g,(s=>W(Te(s)%100,2))(n)).replace(/MMMM/g,((s,e)=>new Date(s.getUTCFullYear(),s.getUTCMonth(),1).toLocaleString(e,{month:"long"}))(n,i)).replace(/MMM/g,((s,e)=>new Date(s.getUTCFullYear(),s.getUTCMonth(),1).toLocaleString(e,{month:"short"}))(n,i)).replace(/MM/g,(s=>W((e=>e.getUTCMonth()+1)(s),2))(n)).replace(/dd/g,(s=>W((e=>e.getUTCDate())(s),2))(n))}var si=class{constructor(t="yyyy-MM-dd",i="default"){this.dd=t,this.fd=i}F_(t){return Jn(t,this.dd,this.fd)}},Ds=class{constructor(t){this.vd=t||"%h:%m:%s"}F_(t){return this.vd.replace("%h",W(t.getUTCHours(),2)).replace("%m",W(t.getUTCMinutes(),2)).replace("%s",W(t.getUTCSeconds(),2))}},Kn={pd:"yyyy-MM-dd",md:"%h:%m:%s",bd:" ",wd:"default"},As=class{constructor(t={}){let i=Object.assign(Object.assign({},Kn),t);this.gd=new si(i.pd,i.wd),this.Md=new Ds(i.md),this.xd=i.bd}F_(t){return`${this.gd.F_(t)}${this.xd}${this.Md.F_(t)}`}};function Pt(n){return 60*n*60*1e3}function Mi(n){return 60*n*1e3}var Dt=[{Sd:(Oe=1,1e3*Oe),yd:10},{Sd:Mi(1),yd:20},{Sd:Mi(5),yd:21},{Sd:Mi(30),yd:22},{Sd:Pt(1),yd:30},{Sd:Pt(3),yd:31},{Sd:Pt(6),yd:32},{Sd:Pt(12),yd:33}],Oe;function Le(n,t){if(n.getUTCFullYear()!==t.getUTCFullYear())return 70;if(n.getUTCMonth()!==t.getUTCMonth())return 60;if(n.getUTCDate()!==t.getUTCDate())return 50;for(let i=Dt.length-1;i>=0;--i)if(Math.floor(t.getTime()/Dt[i].Sd)!==Math.floor(n.getTime()/Dt[i].Sd))return Dt[i].yd;return 0}function yi(n){let t=n;if(kt(n)&&(t=Gs(n)),!Ps(t))throw new Error("time must be of type BusinessDay");let i=new Date(Date.UTC(t.year,t.month-1,t.day,0,0,0,0));return{kd:Math.round(i.getTime()/1e3),Cd:t}}function Re(n){if(!ln(n))throw new Error("time must be of type isUTCTimestamp");return{kd:n}}function Gs(n){let t=new Date(n);if(isNaN(t.getTime()))throw new Error(`Invalid date string=${n}, expected format=yyyy-mm-dd`);return{day:t.getUTCDate(),month:t.getUTCMonth()+1,year:t.getUTCFullYear()}}function Ne(n){kt(n.time)&&(n.time=Gs(n.time))}var ei=class{options(){return this._n}setOptions(t){this._n=t,this.updateFormatter(t.localization)}preprocessData(t){Array.isArray(t)?function(i){i.forEach(Ne)}(t):Ne(t)}createConverterToInternalObj(t){return v(function(i){return i.length===0?null:Ps(i[0].time)||kt(i[0].time)?yi:Re}(t))}key(t){return typeof t=="object"&&"kd"in t?t.kd:this.key(this.convertHorzItemToInternal(t))}cacheKey(t){let i=t;return i.Cd===void 0?new Date(1e3*i.kd).getTime():new Date(Date.UTC(i.Cd.year,i.Cd.month-1,i.Cd.day)).getTime()}convertHorzItemToInternal(t){return ln(i=t)?Re(i):Ps(i)?yi(i):yi(Gs(i));var i}updateFormatter(t){if(!this._n)return;let i=t.dateFormat;this._n.timeScale.timeVisible?this.Td=new As({pd:i,md:this._n.timeScale.secondsVisible?"%h:%m:%s":"%h:%m",bd:"   ",wd:t.locale}):this.Td=new si(i,t.locale)}formatHorzItem(t){let i=t;return this.Td.F_(new Date(1e3*i.kd))}formatTickmark(t,i){let s=function(h,r,o){switch(h){case 0:case 10:return r?o?4:3:2;case 20:case 21:case 22:case 30:case 31:case 32:case 33:return r?3:2;case 50:return 2;case 60:return 1;case 70:return 0}}(t.weight,this._n.timeScale.timeVisible,this._n.timeScale.secondsVisible),e=this._n.timeScale;if(e.tickMarkFormatter!==void 0){let h=e.tickMarkFormatter(t.originalTime,s,i.locale);if(h!==null)return h}return function(h,r,o){let l={};switch(r){case 0:l.year="numeric";break;case 1:l.month="short";break;case 2:l.day="numeric";break;case 3:l.hour12=!1,l.hour="2-digit",l.minute="2-digit";break;case 4:l.hour12=!1,l.hour="2-digit",l.minute="2-digit",l.second="2-digit"}let a=h.Cd===void 0?new Date(1e3*h.kd):new Date(Date.UTC(h.Cd.year,h.Cd.month-1,h.Cd.day));return new Date(a.getUTCFullYear(),a.getUTCMonth(),a.getUTCDate(),a.getUTCHours(),a.getUTCMinutes(),a.getUTCSeconds(),a.getUTCMilliseconds()).toLocaleString(o,l)}(t.time,s,i.locale)}maxTickMarkWeight(t){let i=t.reduce(Xn,t[0]).weight;return i>30&&i<50&&(i=30),i}fillWeightsForPoints(t,i){(function(s,e=0){if(s.length===0)return;let h=e===0?null:s[e-1].time.kd,r=h!==null?new Date(1e3*h):null,o=0;for(let l=e;l<s.length;++l){let a=s[l],u=new Date(1e3*a.time.kd);r!==null&&(a.timeWeight=Le(u,r)),o+=a.time.kd-(h||a.time.kd),h=a.time.kd,r=u}if(e===0&&s.length>1){let l=Math.ceil(o/(s.length-1)),a=new Date(1e3*(s[0].time.kd-l));s[0].timeWeight=Le(new Date(1e3*s[0].time.kd),a)}})(t,i)}static Pd(t){return N({localization:{dateFormat:"dd MMM 'yy"}},t??{})}};function M(n){var t=n.width,i=n.height;if(t<0)throw new Error("Negative width is not allowed for Size");if(i<0)throw new Error("Negative height is not allowed for Size");return{width:t,height:i}}function j(n,t){return n.width===t.width&&n.height===t.height}var Gn=function(){function n(t){var i=this;this._resolutionListener=function(){return i._onResolutionChanged()},this._resolutionMediaQueryList=null,this._observers=[],this._window=t,this._installResolutionListener()}return n.prototype.dispose=function(){this._uninstallResolutionListener(),this._window=null},Object.defineProperty(n.prototype,"value",{get:function(){return this._window.devicePixelRatio},enumerable:!1,configurable:!0}),n.prototype.subscribe=function(t){var i=this,s={next:t};return this._observers.push(s),{unsubscribe:function(){i._observers=i._observers.filter(function(e){return e!==s})}}},n.prototype._installResolutionListener=function(){if(this._resolutionMediaQueryList!==null)throw new Error("Resolution listener is already installed");var t=this._window.devicePixelRatio;this._resolutionMediaQueryList=this._window.matchMedia("all and (resolution: ".concat(t,"dppx)")),this._resolutionMediaQueryList.addListener(this._resolutionListener)},n.prototype._uninstallResolutionListener=function(){this._resolutionMediaQueryList!==null&&(this._resolutionMediaQueryList.removeListener(this._resolutionListener),this._resolutionMediaQueryList=null)},n.prototype._reinstallResolutionListener=function(){this._uninstallResolutionListener(),this._installResolutionListener()},n.prototype._onResolutionChanged=function(){var t=this;this._observers.forEach(function(i){return i.next(t._window.devicePixelRatio)}),this._reinstallResolutionListener()},n}(),th=function(){function n(t,i,s){var e;this._canvasElement=null,this._bitmapSizeChangedListeners=[],this._suggestedBitmapSize=null,this._suggestedBitmapSizeChangedListeners=[],this._devicePixelRatioObservable=null,this._canvasElementResizeObserver=null,this._canvasElement=t,this._canvasElementClientSize=M({width:this._canvasElement.clientWidth,height:this._canvasElement.clientHeight}),this._transformBitmapSize=i??function(h){return h},this._allowResizeObserver=(e=s?.allowResizeObserver)===null||e===void 0||e,this._chooseAndInitObserver()}return n.prototype.dispose=function(){var t,i;if(this._canvasElement===null)throw new Error("Object is disposed");(t=this._canvasElementResizeObserver)===null||t===void 0||t.disconnect(),this._canvasElementResizeObserver=null,(i=this._devicePixelRatioObservable)===null||i===void 0||i.dispose(),this._devicePixelRatioObservable=null,this._suggestedBitmapSizeChangedListeners.length=0,this._bitmapSizeChangedListeners.length=0,this._canvasElement=null},Object.defineProperty(n.prototype,"canvasElement",{get:function(){if(this._canvasElement===null)throw new Error("Object is disposed");return this._canvasElement},enumerable:!1,configurable:!0}),Object.defineProperty(n.prototype,"canvasElementClientSize",{get:function(){return this._canvasElementClientSize},enumerable:!1,configurable:!0}),Object.defineProperty(n.prototype,"bitmapSize",{get:function(){return M({width:this.canvasElement.width,height:this.canvasElement.height})},enumerable:!1,configurable:!0}),n.prototype.resizeCanvasElement=function(t){this._canvasElementClientSize=M(t),this.canvasElement.style.width="".concat(this._canvasElementClientSize.width,"px"),this.canvasElement.style.height="".concat(this._canvasElementClientSize.height,"px"),this._invalidateBitmapSize()},n.prototype.subscribeBitmapSizeChanged=function(t){this._bitmapSizeChangedListeners.push(t)},n.prototype.unsubscribeBitmapSizeChanged=function(t){this._bitmapSizeChangedListeners=this._bitmapSizeChangedListeners.filter(function(i){return i!==t})},Object.defineProperty(n.prototype,"suggestedBitmapSize",{get:function(){return this._suggestedBitmapSize},enumerable:!1,configurable:!0}),n.prototype.subscribeSuggestedBitmapSizeChanged=function(t){this._suggestedBitmapSizeChangedListeners.push(t)},n.prototype.unsubscribeSuggestedBitmapSizeChanged=function(t){this._suggestedBitmapSizeChangedListeners=this._suggestedBitmapSizeChangedListeners.filter(function(i){return i!==t})},n.prototype.applySuggestedBitmapSize=function(){if(this._suggestedBitmapSize!==null){var t=this._suggestedBitmapSize;this._suggestedBitmapSize=null,this._resizeBitmap(t),this._emitSuggestedBitmapSizeChanged(t,this._suggestedBitmapSize)}},n.prototype._resizeBitmap=function(t){var i=this.bitmapSize;j(i,t)||(this.canvasElement.width=t.width,this.canvasElement.height=t.height,this._emitBitmapSizeChanged(i,t))},n.prototype._emitBitmapSizeChanged=function(t,i){var s=this;this._bitmapSizeChangedListeners.forEach(function(e){return e.call(s,t,i)})},n.prototype._suggestNewBitmapSize=function(t){var i=this._suggestedBitmapSize,s=M(this._transformBitmapSize(t,this._canvasElementClientSize)),e=j(this.bitmapSize,s)?null:s;i===null&&e===null||i!==null&&e!==null&&j(i,e)||(this._suggestedBitmapSize=e,this._emitSuggestedBitmapSizeChanged(i,e))},n.prototype._emitSuggestedBitmapSizeChanged=function(t,i){var s=this;this._suggestedBitmapSizeChangedListeners.forEach(function(e){return e.call(s,t,i)})},n.prototype._chooseAndInitObserver=function(){var t=this;this._allowResizeObserver?new Promise(function(i){var s=new ResizeObserver(function(e){i(e.every(function(h){return"devicePixelContentBoxSize"in h})),s.disconnect()});s.observe(document.body,{box:"device-pixel-content-box"})}).catch(function(){return!1}).then(function(i){return i?t._initResizeObserver():t._initDevicePixelRatioObservable()}):this._initDevicePixelRatioObservable()},n.prototype._initDevicePixelRatioObservable=function(){var t=this;if(this._canvasElement!==null){var i=Be(this._canvasElement);if(i===null)throw new Error("No window is associated with the canvas");this._devicePixelRatioObservable=function(s){return new Gn(s)}(i),this._devicePixelRatioObservable.subscribe(function(){return t._invalidateBitmapSize()}),this._invalidateBitmapSize()}},n.prototype._invalidateBitmapSize=function(){var t,i;if(this._canvasElement!==null){var s=Be(this._canvasElement);if(s!==null){var e=(i=(t=this._devicePixelRatioObservable)===null||t===void 0?void 0:t.value)!==null&&i!==void 0?i:s.devicePixelRatio,h=this._canvasElement.getClientRects(),r=h[0]!==void 0?function(o,l){return M({width:Math.round(o.left*l+o.width*l)-Math.round(o.left*l),height:Math.round(o.top*l+o.height*l)-Math.round(o.top*l)})}(h[0],e):M({width:this._canvasElementClientSize.width*e,height:this._canvasElementClientSize.height*e});this._suggestNewBitmapSize(r)}}},n.prototype._initResizeObserver=function(){var t=this;this._canvasElement!==null&&(this._canvasElementResizeObserver=new ResizeObserver(function(i){var s=i.find(function(r){return r.target===t._canvasElement});if(s&&s.devicePixelContentBoxSize&&s.devicePixelContentBoxSize[0]){var e=s.devicePixelContentBoxSize[0],h=M({width:e.inlineSize,height:e.blockSize});t._suggestNewBitmapSize(h)}}),this._canvasElementResizeObserver.observe(this._canvasElement,{box:"device-pixel-content-box"}))},n}();function Be(n){return n.ownerDocument.defaultView}var ih=function(){function n(t,i,s){if(i.width===0||i.height===0)throw new TypeError("Rendering target could only be created on a media with positive width and height");if(this._mediaSize=i,s.width===0||s.height===0)throw new TypeError("Rendering target could only be created using a bitmap with positive integer width and height");this._bitmapSize=s,this._context=t}return n.prototype.useMediaCoordinateSpace=function(t){try{return this._context.save(),this._context.setTransform(1,0,0,1,0,0),this._context.scale(this._horizontalPixelRatio,this._verticalPixelRatio),t({context:this._context,mediaSize:this._mediaSize})}finally{this._context.restore()}},n.prototype.useBitmapCoordinateSpace=function(t){try{return this._context.save(),this._context.setTransform(1,0,0,1,0,0),t({context:this._context,mediaSize:this._mediaSize,bitmapSize:this._bitmapSize,horizontalPixelRatio:this._horizontalPixelRatio,verticalPixelRatio:this._verticalPixelRatio})}finally{this._context.restore()}},Object.defineProperty(n.prototype,"_horizontalPixelRatio",{get:function(){return this._bitmapSize.width/this._mediaSize.width},enumerable:!1,configurable:!0}),Object.defineProperty(n.prototype,"_verticalPixelRatio",{get:function(){return this._bitmapSize.height/this._mediaSize.height},enumerable:!1,configurable:!0}),n}();function Y(n,t){var i=n.canvasElementClientSize;if(i.width===0||i.height===0)return null;var s=n.bitmapSize;if(s.width===0||s.height===0)return null;var e=n.canvasElement.getContext("2d",t);return e===null?null:new ih(e,i,s)}var rt=typeof window<"u";function Pe(){return!!rt&&window.navigator.userAgent.toLowerCase().indexOf("firefox")>-1}function xi(){return!!rt&&/iPhone|iPad|iPod/.test(window.navigator.platform)}function Ws(n){return n+n%2}function Ei(n,t){return n.Rd-t.Rd}function Ci(n,t,i){let s=(n.Rd-t.Rd)/(n.ot-t.ot);return Math.sign(s)*Math.min(Math.abs(s),i)}var Vs=class{constructor(t,i,s,e){this.Dd=null,this.Od=null,this.Ad=null,this.Bd=null,this.Vd=null,this.zd=0,this.Ed=0,this.Id=t,this.Ld=i,this.Nd=s,this.ss=e}Fd(t,i){if(this.Dd!==null){if(this.Dd.ot===i)return void(this.Dd.Rd=t);if(Math.abs(this.Dd.Rd-t)<this.ss)return}this.Bd=this.Ad,this.Ad=this.Od,this.Od=this.Dd,this.Dd={ot:i,Rd:t}}Pr(t,i){if(this.Dd===null||this.Od===null||i-this.Dd.ot>50)return;let s=0,e=Ci(this.Dd,this.Od,this.Ld),h=Ei(this.Dd,this.Od),r=[e],o=[h];if(s+=h,this.Ad!==null){let a=Ci(this.Od,this.Ad,this.Ld);if(Math.sign(a)===Math.sign(e)){let u=Ei(this.Od,this.Ad);if(r.push(a),o.push(u),s+=u,this.Bd!==null){let c=Ci(this.Ad,this.Bd,this.Ld);if(Math.sign(c)===Math.sign(e)){let d=Ei(this.Ad,this.Bd);r.push(c),o.push(d),s+=d}}}}let l=0;for(let a=0;a<r.length;++a)l+=o[a]/s*r[a];Math.abs(l)<this.Id||(this.Vd={Rd:t,ot:i},this.Ed=l,this.zd=function(a,u){let c=Math.log(u);return Math.log(1*c/-a)/c}(Math.abs(l),this.Nd))}Xu(t){let i=v(this.Vd),s=t-i.ot;return i.Rd+this.Ed*(Math.pow(this.Nd,s)-1)/Math.log(this.Nd)}Yu(t){return this.Vd===null||this.Wd(t)===this.zd}Wd(t){let i=t-v(this.Vd).ot;return Math.min(i,this.zd)}};function Q(n,t){let i=v(n.ownerDocument).createElement("canvas");n.appendChild(i);let s=function(e,h){if(h.type==="device-pixel-content-box")return new th(e,h.transform,h.options);throw new Error("Unsupported binding target")}(i,{type:"device-pixel-content-box",options:{allowResizeObserver:!1},transform:(e,h)=>({width:Math.max(e.width,h.width),height:Math.max(e.height,h.height)})});return s.resizeCanvasElement(t),s}function q(n){var t;n.width=1,n.height=1,(t=n.getContext("2d"))===null||t===void 0||t.clearRect(0,0,1,1)}function Hs(n,t,i,s){n.fl&&n.fl(t,i,s)}function It(n,t,i,s){n.K(t,i,s)}function Is(n,t,i,s){let e=n(i,s);for(let h of e){let r=h.gt();r!==null&&t(r)}}function sh(n){rt&&window.chrome!==void 0&&n.addEventListener("mousedown",t=>{if(t.button===1)return t.preventDefault(),!1})}var Ct=class{constructor(t,i,s){this.jd=0,this.Hd=null,this.$d={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY},this.Ud=0,this.qd=null,this.Yd={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY},this.Xd=null,this.Kd=!1,this.Zd=null,this.Gd=null,this.Jd=!1,this.Qd=!1,this.tf=!1,this.if=null,this.nf=null,this.sf=null,this.ef=null,this.rf=null,this.hf=null,this.lf=null,this.af=0,this._f=!1,this.uf=!1,this.cf=!1,this.df=0,this.ff=null,this.vf=!xi(),this.pf=e=>{this.mf(e)},this.bf=e=>{if(this.wf(e)){let h=this.gf(e);if(++this.Ud,this.qd&&this.Ud>1){let{Mf:r}=this.xf(P(e),this.Yd);r<30&&!this.tf&&this.Sf(h,this.kf.yf),this.Cf()}}else{let h=this.gf(e);if(++this.jd,this.Hd&&this.jd>1){let{Mf:r}=this.xf(P(e),this.$d);r<5&&!this.Qd&&this.Tf(h,this.kf.Pf),this.Rf()}}},this.Df=t,this.kf=i,this._n=s,this.Of()}S(){this.if!==null&&(this.if(),this.if=null),this.nf!==null&&(this.nf(),this.nf=null),this.ef!==null&&(this.ef(),this.ef=null),this.rf!==null&&(this.rf(),this.rf=null),this.hf!==null&&(this.hf(),this.hf=null),this.sf!==null&&(this.sf(),this.sf=null),this.Af(),this.Rf()}Bf(t){this.ef&&this.ef();let i=this.Vf.bind(this);if(this.ef=()=>{this.Df.removeEventListener("mousemove",i)},this.Df.addEventListener("mousemove",i),this.wf(t))return;let s=this.gf(t);this.Tf(s,this.kf.zf),this.vf=!0}Rf(){this.Hd!==null&&clearTimeout(this.Hd),this.jd=0,this.Hd=null,this.$d={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY}}Cf(){this.qd!==null&&clearTimeout(this.qd),this.Ud=0,this.qd=null,this.Yd={nt:Number.NEGATIVE_INFINITY,st:Number.POSITIVE_INFINITY}}Vf(t){if(this.cf||this.Gd!==null||this.wf(t))return;let i=this.gf(t);this.Tf(i,this.kf.Ef),this.vf=!0}If(t){let i=ki(t.changedTouches,v(this.ff));if(i===null||(this.df=At(t),this.lf!==null)||this.uf)return;this._f=!0;let s=this.xf(P(i),v(this.Gd)),{Lf:e,Nf:h,Mf:r}=s;if(this.Jd||!(r<5)){if(!this.Jd){let o=.5*e,l=h>=o&&!this._n.Ff(),a=o>h&&!this._n.Wf();l||a||(this.uf=!0),this.Jd=!0,this.tf=!0,this.Af(),this.Cf()}if(!this.uf){let o=this.gf(t,i);this.Sf(o,this.kf.jf),K(t)}}}Hf(t){if(t.button!==0)return;let i=this.xf(P(t),v(this.Zd)),{Mf:s}=i;if(s>=5&&(this.Qd=!0,this.Rf()),this.Qd){let e=this.gf(t);this.Tf(e,this.kf.$f)}}xf(t,i){let s=Math.abs(i.nt-t.nt),e=Math.abs(i.st-t.st);return{Lf:s,Nf:e,Mf:s+e}}Uf(t){let i=ki(t.changedTouches,v(this.ff));if(i===null&&t.touches.length===0&&(i=t.changedTouches[0]),i===null)return;this.ff=null,this.df=At(t),this.Af(),this.Gd=null,this.hf&&(this.hf(),this.hf=null);let s=this.gf(t,i);if(this.Sf(s,this.kf.qf),++this.Ud,this.qd&&this.Ud>1){let{Mf:e}=this.xf(P(i),this.Yd);e<30&&!this.tf&&this.Sf(s,this.kf.yf),this.Cf()}else this.tf||(this.Sf(s,this.kf.Yf),this.kf.Yf&&K(t));this.Ud===0&&K(t),t.touches.length===0&&this.Kd&&(this.Kd=!1,K(t))}mf(t){if(t.button!==0)return;let i=this.gf(t);if(this.Zd=null,this.cf=!1,this.rf&&(this.rf(),this.rf=null),Pe()&&this.Df.ownerDocument.documentElement.removeEventListener("mouseleave",this.pf),!this.wf(t))if(this.Tf(i,this.kf.Xf),++this.jd,this.Hd&&this.jd>1){let{Mf:s}=this.xf(P(t),this.$d);s<5&&!this.Qd&&this.Tf(i,this.kf.Pf),this.Rf()}else this.Qd||this.Tf(i,this.kf.Kf)}Af(){this.Xd!==null&&(clearTimeout(this.Xd),this.Xd=null)}Zf(t){if(this.ff!==null)return;let i=t.changedTouches[0];this.ff=i.identifier,this.df=At(t);let s=this.Df.ownerDocument.documentElement;this.tf=!1,this.Jd=!1,this.uf=!1,this.Gd=P(i),this.hf&&(this.hf(),this.hf=null);{let h=this.If.bind(this),r=this.Uf.bind(this);this.hf=()=>{s.removeEventListener("touchmove",h),s.removeEventListener("touchend",r)},s.addEventListener("touchmove",h,{passive:!1}),s.addEventListener("touchend",r,{passive:!1}),this.Af(),this.Xd=setTimeout(this.Gf.bind(this,t),240)}let e=this.gf(t,i);this.Sf(e,this.kf.Jf),this.qd||(this.Ud=0,this.qd=setTimeout(this.Cf.bind(this),500),this.Yd=P(i))}Qf(t){if(t.button!==0)return;let i=this.Df.ownerDocument.documentElement;Pe()&&i.addEventListener("mouseleave",this.pf),this.Qd=!1,this.Zd=P(t),this.rf&&(this.rf(),this.rf=null);{let e=this.Hf.bind(this),h=this.mf.bind(this);this.rf=()=>{i.removeEventListener("mousemove",e),i.removeEventListener("mouseup",h)},i.addEventListener("mousemove",e),i.addEventListener("mouseup",h)}if(this.cf=!0,this.wf(t))return;let s=this.gf(t);this.Tf(s,this.kf.tv),this.Hd||(this.jd=0,this.Hd=setTimeout(this.Rf.bind(this),500),this.$d=P(t))}Of(){this.Df.addEventListener("mouseenter",this.Bf.bind(this)),this.Df.addEventListener("touchcancel",this.Af.bind(this));{let t=this.Df.ownerDocument,i=s=>{this.kf.iv&&(s.composed&&this.Df.contains(s.composedPath()[0])||s.target&&this.Df.contains(s.target)||this.kf.iv())};this.nf=()=>{t.removeEventListener("touchstart",i)},this.if=()=>{t.removeEventListener("mousedown",i)},t.addEventListener("mousedown",i),t.addEventListener("touchstart",i,{passive:!0})}xi()&&(this.sf=()=>{this.Df.removeEventListener("dblclick",this.bf)},this.Df.addEventListener("dblclick",this.bf)),this.Df.addEventListener("mouseleave",this.nv.bind(this)),this.Df.addEventListener("touchstart",this.Zf.bind(this),{passive:!0}),sh(this.Df),this.Df.addEventListener("mousedown",this.Qf.bind(this)),this.sv(),this.Df.addEventListener("touchmove",()=>{},{passive:!1})}sv(){this.kf.ev===void 0&&this.kf.rv===void 0&&this.kf.hv===void 0||(this.Df.addEventListener("touchstart",t=>this.lv(t.touches),{passive:!0}),this.Df.addEventListener("touchmove",t=>{if(t.touches.length===2&&this.lf!==null&&this.kf.rv!==void 0){let i=De(t.touches[0],t.touches[1])/this.af;this.kf.rv(this.lf,i),K(t)}},{passive:!1}),this.Df.addEventListener("touchend",t=>{this.lv(t.touches)}))}lv(t){t.length===1&&(this._f=!1),t.length!==2||this._f||this.Kd?this.av():this.ov(t)}ov(t){let i=this.Df.getBoundingClientRect()||{left:0,top:0};this.lf={nt:(t[0].clientX-i.left+(t[1].clientX-i.left))/2,st:(t[0].clientY-i.top+(t[1].clientY-i.top))/2},this.af=De(t[0],t[1]),this.kf.ev!==void 0&&this.kf.ev(),this.Af()}av(){this.lf!==null&&(this.lf=null,this.kf.hv!==void 0&&this.kf.hv())}nv(t){if(this.ef&&this.ef(),this.wf(t)||!this.vf)return;let i=this.gf(t);this.Tf(i,this.kf._v),this.vf=!xi()}Gf(t){let i=ki(t.touches,v(this.ff));if(i===null)return;let s=this.gf(t,i);this.Sf(s,this.kf.uv),this.tf=!0,this.Kd=!0}wf(t){return t.sourceCapabilities&&t.sourceCapabilities.firesTouchEvents!==void 0?t.sourceCapabilities.firesTouchEvents:At(t)<this.df+500}Sf(t,i){i&&i.call(this.kf,t)}Tf(t,i){i&&i.call(this.kf,t)}gf(t,i){let s=i||t,e=this.Df.getBoundingClientRect()||{left:0,top:0};return{clientX:s.clientX,clientY:s.clientY,pageX:s.pageX,pageY:s.pageY,screenX:s.screenX,screenY:s.screenY,localX:s.clientX-e.left,localY:s.clientY-e.top,ctrlKey:t.ctrlKey,altKey:t.altKey,shiftKey:t.shiftKey,metaKey:t.metaKey,cv:!t.type.startsWith("mouse")&&t.type!=="contextmenu"&&t.type!=="click",dv:t.type,fv:s.target,vv:t.view,pv:()=>{t.type!=="touchstart"&&K(t)}}}};function De(n,t){let i=n.clientX-t.clientX,s=n.clientY-t.clientY;return Math.sqrt(i*i+s*s)}function K(n){n.cancelable&&n.preventDefault()}function P(n){return{nt:n.pageX,st:n.pageY}}function At(n){return n.timeStamp||performance.now()}function ki(n,t){for(let i=0;i<n.length;++i)if(n[i].identifier===t)return n[i];return null}function Wt(n){return{Ec:n.Ec,mv:{mr:n.bv.externalId},wv:n.bv.cursorStyle}}function eh(n,t,i){for(let s of n){let e=s.gt();if(e!==null&&e.pr){let h=e.pr(t,i);if(h!==null)return{vv:s,mv:h}}}return null}function zi(n,t){return i=>{var s,e,h,r;return((e=(s=i.Dt())===null||s===void 0?void 0:s.xa())!==null&&e!==void 0?e:"")!==t?[]:(r=(h=i.la)===null||h===void 0?void 0:h.call(i,n))!==null&&r!==void 0?r:[]}}var ni=class{constructor(t,i,s,e){this.Ei=null,this.gv=null,this.Mv=!1,this.xv=new Z(200),this.Zr=null,this.Sv=0,this.yv=!1,this.kv=()=>{this.yv||this.Qi.Cv().$t().Nh()},this.Tv=()=>{this.yv||this.Qi.Cv().$t().Nh()},this.Qi=t,this._n=i,this.mo=i.layout,this.kc=s,this.Pv=e==="left",this.Rv=zi("normal",e),this.Dv=zi("top",e),this.Ov=zi("bottom",e),this.Av=document.createElement("div"),this.Av.style.height="100%",this.Av.style.overflow="hidden",this.Av.style.width="25px",this.Av.style.left="0",this.Av.style.position="relative",this.Bv=Q(this.Av,M({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv);let h=this.Bv.canvasElement;h.style.position="absolute",h.style.zIndex="1",h.style.left="0",h.style.top="0",this.Vv=Q(this.Av,M({width:16,height:16})),this.Vv.subscribeSuggestedBitmapSizeChanged(this.Tv);let r=this.Vv.canvasElement;r.style.position="absolute",r.style.zIndex="2",r.style.left="0",r.style.top="0";let o={tv:this.zv.bind(this),Jf:this.zv.bind(this),$f:this.Ev.bind(this),jf:this.Ev.bind(this),iv:this.Iv.bind(this),Xf:this.Lv.bind(this),qf:this.Lv.bind(this),Pf:this.Nv.bind(this),yf:this.Nv.bind(this),zf:this.Fv.bind(this),_v:this.Wv.bind(this)};this.jv=new Ct(this.Vv.canvasElement,o,{Ff:()=>!this._n.handleScroll.vertTouchDrag,Wf:()=>!0})}S(){this.jv.S(),this.Vv.unsubscribeSuggestedBitmapSizeChanged(this.Tv),q(this.Vv.canvasElement),this.Vv.dispose(),this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),q(this.Bv.canvasElement),this.Bv.dispose(),this.Ei!==null&&this.Ei.$o().p(this),this.Ei=null}Hv(){return this.Av}P(){return this.mo.fontSize}$v(){let t=this.kc.W();return this.Zr!==t.R&&(this.xv.Qe(),this.Zr=t.R),t}Uv(){if(this.Ei===null)return 0;let t=0,i=this.$v(),s=v(this.Bv.canvasElement.getContext("2d"));s.save();let e=this.Ei.Ia();s.font=this.qv(),e.length>0&&(t=Math.max(this.xv.Mi(s,e[0].Za),this.xv.Mi(s,e[e.length-1].Za)));let h=this.Yv();for(let l=h.length;l--;){let a=this.xv.Mi(s,h[l].Zt());a>t&&(t=a)}let r=this.Ei.Ct();if(r!==null&&this.gv!==null){let l=this.Ei.fn(1,r),a=this.Ei.fn(this.gv.height-2,r);t=Math.max(t,this.xv.Mi(s,this.Ei.Ni(Math.floor(Math.min(l,a))+.11111111111111,r)),this.xv.Mi(s,this.Ei.Ni(Math.ceil(Math.max(l,a))-.11111111111111,r)))}s.restore();let o=t||34;return Ws(Math.ceil(i.C+i.T+i.V+i.I+5+o))}Xv(t){this.gv!==null&&j(this.gv,t)||(this.gv=t,this.yv=!0,this.Bv.resizeCanvasElement(t),this.Vv.resizeCanvasElement(t),this.yv=!1,this.Av.style.width=`${t.width}px`,this.Av.style.height=`${t.height}px`)}Kv(){return v(this.gv).width}Zi(t){this.Ei!==t&&(this.Ei!==null&&this.Ei.$o().p(this),this.Ei=t,t.$o().l(this.ao.bind(this),this))}Dt(){return this.Ei}Qe(){let t=this.Qi.Zv();this.Qi.Cv().$t().A_(t,v(this.Dt()))}Gv(t){if(this.gv===null)return;if(t!==1){this.Jv(),this.Bv.applySuggestedBitmapSize();let s=Y(this.Bv);s!==null&&(s.useBitmapCoordinateSpace(e=>{this.Qv(e),this.Ae(e)}),this.Qi.tp(s,this.Ov),this.ip(s),this.Qi.tp(s,this.Rv),this.np(s))}this.Vv.applySuggestedBitmapSize();let i=Y(this.Vv);i!==null&&(i.useBitmapCoordinateSpace(({context:s,bitmapSize:e})=>{s.clearRect(0,0,e.width,e.height)}),this.sp(i),this.Qi.tp(i,this.Dv))}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}bt(){var t;(t=this.Ei)===null||t===void 0||t.Ia()}zv(t){if(this.Ei===null||this.Ei.Li()||!this._n.handleScale.axisPressedMouseMove.price)return;let i=this.Qi.Cv().$t(),s=this.Qi.Zv();this.Mv=!0,i.k_(s,this.Ei,t.localY)}Ev(t){if(this.Ei===null||!this._n.handleScale.axisPressedMouseMove.price)return;let i=this.Qi.Cv().$t(),s=this.Qi.Zv(),e=this.Ei;i.C_(s,e,t.localY)}Iv(){if(this.Ei===null||!this._n.handleScale.axisPressedMouseMove.price)return;let t=this.Qi.Cv().$t(),i=this.Qi.Zv(),s=this.Ei;this.Mv&&(this.Mv=!1,t.T_(i,s))}Lv(t){if(this.Ei===null||!this._n.handleScale.axisPressedMouseMove.price)return;let i=this.Qi.Cv().$t(),s=this.Qi.Zv();this.Mv=!1,i.T_(s,this.Ei)}Nv(t){this._n.handleScale.axisDoubleClickReset.price&&this.Qe()}Fv(t){this.Ei!==null&&(!this.Qi.Cv().$t().W().handleScale.axisPressedMouseMove.price||this.Ei.fh()||this.Ei.Co()||this.hp(1))}Wv(t){this.hp(0)}Yv(){let t=[],i=this.Ei===null?void 0:this.Ei;return(s=>{for(let e=0;e<s.length;++e){let h=s[e].Tn(this.Qi.Zv(),i);for(let r=0;r<h.length;r++)t.push(h[r])}})(this.Qi.Zv().No()),t}Qv({context:t,bitmapSize:i}){let{width:s,height:e}=i,h=this.Qi.Zv().$t(),r=h.q(),o=h._d();r===o?oi(t,0,0,s,e,r):Je(t,0,0,s,e,r,o)}Ae({context:t,bitmapSize:i,horizontalPixelRatio:s}){if(this.gv===null||this.Ei===null||!this.Ei.W().borderVisible)return;t.fillStyle=this.Ei.W().borderColor;let e=Math.max(1,Math.floor(this.$v().C*s)),h;h=this.Pv?i.width-e:0,t.fillRect(h,0,e,i.height)}ip(t){if(this.gv===null||this.Ei===null)return;let i=this.Ei.Ia(),s=this.Ei.W(),e=this.$v(),h=this.Pv?this.gv.width-e.T:0;s.borderVisible&&s.ticksVisible&&t.useBitmapCoordinateSpace(({context:r,horizontalPixelRatio:o,verticalPixelRatio:l})=>{r.fillStyle=s.borderColor;let a=Math.max(1,Math.floor(l)),u=Math.floor(.5*l),c=Math.round(e.T*o);r.beginPath();for(let d of i)r.rect(Math.floor(h*o),Math.round(d.Aa*l)-u,c,a);r.fill()}),t.useMediaCoordinateSpace(({context:r})=>{var o;r.font=this.qv(),r.fillStyle=(o=s.textColor)!==null&&o!==void 0?o:this.mo.textColor,r.textAlign=this.Pv?"right":"left",r.textBaseline="middle";let l=this.Pv?Math.round(h-e.V):Math.round(h+e.T+e.V),a=i.map(u=>this.xv.gi(r,u.Za));for(let u=i.length;u--;){let c=i[u];r.fillText(c.Za,l,c.Aa+a[u])}})}Jv(){if(this.gv===null||this.Ei===null)return;let t=this.gv.height/2,i=[],s=this.Ei.No().slice(),e=this.Qi.Zv(),h=this.$v();this.Ei===e.cr()&&this.Qi.Zv().No().forEach(l=>{e.ur(l)&&s.push(l)});let r=this.Ei.Ta()[0],o=this.Ei;s.forEach(l=>{let a=l.Tn(e,o);a.forEach(u=>{u.Oi(null),u.Ai()&&i.push(u)}),r===l&&a.length>0&&(t=a[0].Si())}),i.forEach(l=>l.Oi(l.Si())),this.Ei.W().alignLabels&&this.lp(i,h,t)}lp(t,i,s){if(this.gv===null)return;let e=t.filter(r=>r.Si()<=s),h=t.filter(r=>r.Si()>s);e.sort((r,o)=>o.Si()-r.Si()),e.length&&h.length&&h.push(e[0]),h.sort((r,o)=>r.Si()-o.Si());for(let r of t){let o=Math.floor(r.Vt(i)/2),l=r.Si();l>-o&&l<o&&r.Oi(o),l>this.gv.height-o&&l<this.gv.height+o&&r.Oi(this.gv.height-o)}for(let r=1;r<e.length;r++){let o=e[r],l=e[r-1],a=l.Vt(i,!1),u=o.Si(),c=l.Di();u>c-a&&o.Oi(c-a)}for(let r=1;r<h.length;r++){let o=h[r],l=h[r-1],a=l.Vt(i,!0),u=o.Si(),c=l.Di();u<c+a&&o.Oi(c+a)}}np(t){if(this.gv===null)return;let i=this.Yv(),s=this.$v(),e=this.Pv?"right":"left";i.forEach(h=>{h.Bi()&&h.gt(v(this.Ei)).K(t,s,this.xv,e)})}sp(t){if(this.gv===null||this.Ei===null)return;let i=this.Qi.Cv().$t(),s=[],e=this.Qi.Zv(),h=i.Wc().Tn(e,this.Ei);h.length&&s.push(h);let r=this.$v(),o=this.Pv?"right":"left";s.forEach(l=>{l.forEach(a=>{a.gt(v(this.Ei)).K(t,r,this.xv,o)})})}hp(t){this.Av.style.cursor=t===1?"ns-resize":"default"}ao(){let t=this.Uv();this.Sv<t&&this.Qi.Cv().$t().$l(),this.Sv=t}qv(){return st(this.mo.fontSize,this.mo.fontFamily)}};function nh(n,t){var i,s;return(s=(i=n.ra)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}function Vt(n,t){var i,s;return(s=(i=n.Cn)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}function hh(n,t){var i,s;return(s=(i=n.Gi)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}function rh(n,t){var i,s;return(s=(i=n.na)===null||i===void 0?void 0:i.call(n,t))!==null&&s!==void 0?s:[]}var $s=class n{constructor(t,i){this.gv=M({width:0,height:0}),this.ap=null,this.op=null,this._p=null,this.up=!1,this.cp=new y,this.dp=new y,this.fp=0,this.vp=!1,this.pp=null,this.mp=!1,this.bp=null,this.wp=null,this.yv=!1,this.kv=()=>{this.yv||this.gp===null||this.Hi().Nh()},this.Tv=()=>{this.yv||this.gp===null||this.Hi().Nh()},this.Mp=t,this.gp=i,this.gp.z_().l(this.xp.bind(this),this,!0),this.Sp=document.createElement("td"),this.Sp.style.padding="0",this.Sp.style.position="relative";let s=document.createElement("div");s.style.width="100%",s.style.height="100%",s.style.position="relative",s.style.overflow="hidden",this.yp=document.createElement("td"),this.yp.style.padding="0",this.kp=document.createElement("td"),this.kp.style.padding="0",this.Sp.appendChild(s),this.Bv=Q(s,M({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv);let e=this.Bv.canvasElement;e.style.position="absolute",e.style.zIndex="1",e.style.left="0",e.style.top="0",this.Vv=Q(s,M({width:16,height:16})),this.Vv.subscribeSuggestedBitmapSizeChanged(this.Tv);let h=this.Vv.canvasElement;h.style.position="absolute",h.style.zIndex="2",h.style.left="0",h.style.top="0",this.Cp=document.createElement("tr"),this.Cp.appendChild(this.yp),this.Cp.appendChild(this.Sp),this.Cp.appendChild(this.kp),this.Tp(),this.jv=new Ct(this.Vv.canvasElement,this,{Ff:()=>this.pp===null&&!this.Mp.W().handleScroll.vertTouchDrag,Wf:()=>this.pp===null&&!this.Mp.W().handleScroll.horzTouchDrag})}S(){this.ap!==null&&this.ap.S(),this.op!==null&&this.op.S(),this.Vv.unsubscribeSuggestedBitmapSizeChanged(this.Tv),q(this.Vv.canvasElement),this.Vv.dispose(),this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),q(this.Bv.canvasElement),this.Bv.dispose(),this.gp!==null&&this.gp.z_().p(this),this.jv.S()}Zv(){return v(this.gp)}Pp(t){this.gp!==null&&this.gp.z_().p(this),this.gp=t,this.gp!==null&&this.gp.z_().l(n.prototype.xp.bind(this),this,!0),this.Tp()}Cv(){return this.Mp}Hv(){return this.Cp}Tp(){if(this.gp!==null&&(this.Rp(),this.Hi().wt().length!==0)){if(this.ap!==null){let t=this.gp.S_();this.ap.Zi(v(t))}if(this.op!==null){let t=this.gp.y_();this.op.Zi(v(t))}}}Dp(){this.ap!==null&&this.ap.bt(),this.op!==null&&this.op.bt()}v_(){return this.gp!==null?this.gp.v_():0}p_(t){this.gp&&this.gp.p_(t)}zf(t){if(!this.gp)return;this.Op();let i=t.localX,s=t.localY;this.Ap(i,s,t)}tv(t){this.Op(),this.Bp(),this.Ap(t.localX,t.localY,t)}Ef(t){var i;if(!this.gp)return;this.Op();let s=t.localX,e=t.localY;this.Ap(s,e,t);let h=this.pr(s,e);this.Mp.Vp((i=h?.wv)!==null&&i!==void 0?i:null),this.Hi().zc(h&&{Ec:h.Ec,mv:h.mv})}Kf(t){this.gp!==null&&(this.Op(),this.zp(t))}Pf(t){this.gp!==null&&this.Ep(this.dp,t)}yf(t){this.Pf(t)}$f(t){this.Op(),this.Ip(t),this.Ap(t.localX,t.localY,t)}Xf(t){this.gp!==null&&(this.Op(),this.vp=!1,this.Lp(t))}Yf(t){this.gp!==null&&this.zp(t)}uv(t){if(this.vp=!0,this.pp===null){let i={x:t.localX,y:t.localY};this.Np(i,i,t)}}_v(t){this.gp!==null&&(this.Op(),this.gp.$t().zc(null),this.Fp())}Wp(){return this.cp}jp(){return this.dp}ev(){this.fp=1,this.Hi().Hn()}rv(t,i){if(!this.Mp.W().handleScale.pinch)return;let s=5*(i-this.fp);this.fp=i,this.Hi().qc(t.nt,s)}Jf(t){this.vp=!1,this.mp=this.pp!==null,this.Bp();let i=this.Hi().Wc();this.pp!==null&&i.kt()&&(this.bp={x:i.Yt(),y:i.Xt()},this.pp={x:t.localX,y:t.localY})}jf(t){if(this.gp===null)return;let i=t.localX,s=t.localY;if(this.pp===null)this.Ip(t);else{this.mp=!1;let e=v(this.bp),h=e.x+(i-this.pp.x),r=e.y+(s-this.pp.y);this.Ap(h,r,t)}}qf(t){this.Cv().W().trackingMode.exitMode===0&&(this.mp=!0),this.Hp(),this.Lp(t)}pr(t,i){let s=this.gp;return s===null?null:function(e,h,r){let o=e.No(),l=function(a,u,c){var d,f;let p,m;for(let w of a){let S=(f=(d=w.oa)===null||d===void 0?void 0:d.call(w,u,c))!==null&&f!==void 0?f:[];for(let _ of S)b=_.zOrder,(!(g=p?.zOrder)||b==="top"&&g!=="top"||b==="normal"&&g==="bottom")&&(p=_,m=w)}var b,g;return p&&m?{bv:p,Ec:m}:null}(o,h,r);if(l?.bv.zOrder==="top")return Wt(l);for(let a of o){if(l&&l.Ec===a&&l.bv.zOrder!=="bottom"&&!l.bv.isBackground)return Wt(l);let u=eh(a.Cn(e),h,r);if(u!==null)return{Ec:a,vv:u.vv,mv:u.mv};if(l&&l.Ec===a&&l.bv.zOrder!=="bottom"&&l.bv.isBackground)return Wt(l)}return l?.bv?Wt(l):null}(s,t,i)}$p(t,i){v(i==="left"?this.ap:this.op).Xv(M({width:t,height:this.gv.height}))}Up(){return this.gv}Xv(t){j(this.gv,t)||(this.gv=t,this.yv=!0,this.Bv.resizeCanvasElement(t),this.Vv.resizeCanvasElement(t),this.yv=!1,this.Sp.style.width=t.width+"px",this.Sp.style.height=t.height+"px")}qp(){let t=v(this.gp);t.x_(t.S_()),t.x_(t.y_());for(let i of t.Ta())if(t.ur(i)){let s=i.Dt();s!==null&&t.x_(s),i.Rn()}}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}Gv(t){if(t===0||this.gp===null)return;if(t>1&&this.qp(),this.ap!==null&&this.ap.Gv(t),this.op!==null&&this.op.Gv(t),t!==1){this.Bv.applySuggestedBitmapSize();let s=Y(this.Bv);s!==null&&(s.useBitmapCoordinateSpace(e=>{this.Qv(e)}),this.gp&&(this.Yp(s,nh),this.Xp(s),this.Kp(s),this.Yp(s,Vt),this.Yp(s,hh)))}this.Vv.applySuggestedBitmapSize();let i=Y(this.Vv);i!==null&&(i.useBitmapCoordinateSpace(({context:s,bitmapSize:e})=>{s.clearRect(0,0,e.width,e.height)}),this.Zp(i),this.Yp(i,rh))}Gp(){return this.ap}Jp(){return this.op}tp(t,i){this.Yp(t,i)}xp(){this.gp!==null&&this.gp.z_().p(this),this.gp=null}zp(t){this.Ep(this.cp,t)}Ep(t,i){let s=i.localX,e=i.localY;t.M()&&t.m(this.Hi().St().Bu(s),{x:s,y:e},i)}Qv({context:t,bitmapSize:i}){let{width:s,height:e}=i,h=this.Hi(),r=h.q(),o=h._d();r===o?oi(t,0,0,s,e,o):Je(t,0,0,s,e,r,o)}Xp(t){let i=v(this.gp).E_().Fh().gt();i!==null&&i.K(t,!1)}Kp(t){let i=this.Hi().Fc();this.Qp(t,Vt,Hs,i),this.Qp(t,Vt,It,i)}Zp(t){this.Qp(t,Vt,It,this.Hi().Wc())}Yp(t,i){let s=v(this.gp).No();for(let e of s)this.Qp(t,i,Hs,e);for(let e of s)this.Qp(t,i,It,e)}Qp(t,i,s,e){let h=v(this.gp),r=h.$t().Vc(),o=r!==null&&r.Ec===e,l=r!==null&&o&&r.mv!==void 0?r.mv.br:void 0;Is(i,a=>s(a,t,o,l),e,h)}Rp(){if(this.gp===null)return;let t=this.Mp,i=this.gp.S_().W().visible,s=this.gp.y_().W().visible;i||this.ap===null||(this.yp.removeChild(this.ap.Hv()),this.ap.S(),this.ap=null),s||this.op===null||(this.kp.removeChild(this.op.Hv()),this.op.S(),this.op=null);let e=t.$t().ed();i&&this.ap===null&&(this.ap=new ni(this,t.W(),e,"left"),this.yp.appendChild(this.ap.Hv())),s&&this.op===null&&(this.op=new ni(this,t.W(),e,"right"),this.kp.appendChild(this.op.Hv()))}tm(t){return t.cv&&this.vp||this.pp!==null}im(t){return Math.max(0,Math.min(t,this.gv.width-1))}nm(t){return Math.max(0,Math.min(t,this.gv.height-1))}Ap(t,i,s){this.Hi().Qc(this.im(t),this.nm(i),s,v(this.gp))}Fp(){this.Hi().nd()}Hp(){this.mp&&(this.pp=null,this.Fp())}Np(t,i,s){this.pp=t,this.mp=!1,this.Ap(i.x,i.y,s);let e=this.Hi().Wc();this.bp={x:e.Yt(),y:e.Xt()}}Hi(){return this.Mp.$t()}Lp(t){if(!this.up)return;let i=this.Hi(),s=this.Zv();if(i.D_(s,s.dn()),this._p=null,this.up=!1,i.Zc(),this.wp!==null){let e=performance.now(),h=i.St();this.wp.Pr(h.Iu(),e),this.wp.Yu(e)||i.qn(this.wp)}}Op(){this.pp=null}Bp(){if(this.gp){if(this.Hi().Hn(),document.activeElement!==document.body&&document.activeElement!==document.documentElement)v(document.activeElement).blur();else{let t=document.getSelection();t!==null&&t.removeAllRanges()}!this.gp.dn().Li()&&this.Hi().St().Li()}}Ip(t){if(this.gp===null)return;let i=this.Hi(),s=i.St();if(s.Li())return;let e=this.Mp.W(),h=e.handleScroll,r=e.kineticScroll;if((!h.pressedMouseMove||t.cv)&&(!h.horzTouchDrag&&!h.vertTouchDrag||!t.cv))return;let o=this.gp.dn(),l=performance.now();if(this._p!==null||this.tm(t)||(this._p={x:t.clientX,y:t.clientY,kd:l,sm:t.localX,rm:t.localY}),this._p!==null&&!this.up&&(this._p.x!==t.clientX||this._p.y!==t.clientY)){if(t.cv&&r.touch||!t.cv&&r.mouse){let a=s.ee();this.wp=new Vs(.2/a,7/a,.997,15/a),this.wp.Fd(s.Iu(),this._p.kd)}else this.wp=null;o.Li()||i.P_(this.gp,o,t.localY),i.Xc(t.localX),this.up=!0}this.up&&(o.Li()||i.R_(this.gp,o,t.localY),i.Kc(t.localX),this.wp!==null&&this.wp.Fd(s.Iu(),l))}},hi=class{constructor(t,i,s,e,h){this.ft=!0,this.gv=M({width:0,height:0}),this.kv=()=>this.Gv(3),this.Pv=t==="left",this.kc=s.ed,this._n=i,this.hm=e,this.lm=h,this.Av=document.createElement("div"),this.Av.style.width="25px",this.Av.style.height="100%",this.Av.style.overflow="hidden",this.Bv=Q(this.Av,M({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv)}S(){this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),q(this.Bv.canvasElement),this.Bv.dispose()}Hv(){return this.Av}Up(){return this.gv}Xv(t){j(this.gv,t)||(this.gv=t,this.Bv.resizeCanvasElement(t),this.Av.style.width=`${t.width}px`,this.Av.style.height=`${t.height}px`,this.ft=!0)}Gv(t){if(t<3&&!this.ft||this.gv.width===0||this.gv.height===0)return;this.ft=!1,this.Bv.applySuggestedBitmapSize();let i=Y(this.Bv);i!==null&&i.useBitmapCoordinateSpace(s=>{this.Qv(s),this.Ae(s)})}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}Ae({context:t,bitmapSize:i,horizontalPixelRatio:s,verticalPixelRatio:e}){if(!this.hm())return;t.fillStyle=this._n.timeScale.borderColor;let h=Math.floor(this.kc.W().C*s),r=Math.floor(this.kc.W().C*e),o=this.Pv?i.width-h:0;t.fillRect(o,0,h,r)}Qv({context:t,bitmapSize:i}){oi(t,0,0,i.width,i.height,this.lm())}};function te(n){return t=>{var i,s;return(s=(i=t.aa)===null||i===void 0?void 0:i.call(t,n))!==null&&s!==void 0?s:[]}}var oh=te("normal"),lh=te("top"),ah=te("bottom"),Fs=class{constructor(t,i){this.am=null,this.om=null,this.k=null,this._m=!1,this.gv=M({width:0,height:0}),this.um=new y,this.xv=new Z(5),this.yv=!1,this.kv=()=>{this.yv||this.Mp.$t().Nh()},this.Tv=()=>{this.yv||this.Mp.$t().Nh()},this.Mp=t,this.N_=i,this._n=t.W().layout,this.dm=document.createElement("tr"),this.fm=document.createElement("td"),this.fm.style.padding="0",this.vm=document.createElement("td"),this.vm.style.padding="0",this.Av=document.createElement("td"),this.Av.style.height="25px",this.Av.style.padding="0",this.pm=document.createElement("div"),this.pm.style.width="100%",this.pm.style.height="100%",this.pm.style.position="relative",this.pm.style.overflow="hidden",this.Av.appendChild(this.pm),this.Bv=Q(this.pm,M({width:16,height:16})),this.Bv.subscribeSuggestedBitmapSizeChanged(this.kv);let s=this.Bv.canvasElement;s.style.position="absolute",s.style.zIndex="1",s.style.left="0",s.style.top="0",this.Vv=Q(this.pm,M({width:16,height:16})),this.Vv.subscribeSuggestedBitmapSizeChanged(this.Tv);let e=this.Vv.canvasElement;e.style.position="absolute",e.style.zIndex="2",e.style.left="0",e.style.top="0",this.dm.appendChild(this.fm),this.dm.appendChild(this.Av),this.dm.appendChild(this.vm),this.bm(),this.Mp.$t().f_().l(this.bm.bind(this),this),this.jv=new Ct(this.Vv.canvasElement,this,{Ff:()=>!0,Wf:()=>!this.Mp.W().handleScroll.horzTouchDrag})}S(){this.jv.S(),this.am!==null&&this.am.S(),this.om!==null&&this.om.S(),this.Vv.unsubscribeSuggestedBitmapSizeChanged(this.Tv),q(this.Vv.canvasElement),this.Vv.dispose(),this.Bv.unsubscribeSuggestedBitmapSizeChanged(this.kv),q(this.Bv.canvasElement),this.Bv.dispose()}Hv(){return this.dm}wm(){return this.am}gm(){return this.om}tv(t){if(this._m)return;this._m=!0;let i=this.Mp.$t();!i.St().Li()&&this.Mp.W().handleScale.axisPressedMouseMove.time&&i.Uc(t.localX)}Jf(t){this.tv(t)}iv(){let t=this.Mp.$t();!t.St().Li()&&this._m&&(this._m=!1,this.Mp.W().handleScale.axisPressedMouseMove.time&&t.Jc())}$f(t){let i=this.Mp.$t();!i.St().Li()&&this.Mp.W().handleScale.axisPressedMouseMove.time&&i.Gc(t.localX)}jf(t){this.$f(t)}Xf(){this._m=!1;let t=this.Mp.$t();t.St().Li()&&!this.Mp.W().handleScale.axisPressedMouseMove.time||t.Jc()}qf(){this.Xf()}Pf(){this.Mp.W().handleScale.axisDoubleClickReset.time&&this.Mp.$t().Xn()}yf(){this.Pf()}zf(){this.Mp.$t().W().handleScale.axisPressedMouseMove.time&&this.hp(1)}_v(){this.hp(0)}Up(){return this.gv}Mm(){return this.um}xm(t,i,s){j(this.gv,t)||(this.gv=t,this.yv=!0,this.Bv.resizeCanvasElement(t),this.Vv.resizeCanvasElement(t),this.yv=!1,this.Av.style.width=`${t.width}px`,this.Av.style.height=`${t.height}px`,this.um.m(t)),this.am!==null&&this.am.Xv(M({width:i,height:t.height})),this.om!==null&&this.om.Xv(M({width:s,height:t.height}))}Sm(){let t=this.ym();return Math.ceil(t.C+t.T+t.P+t.L+t.B+t.km)}bt(){this.Mp.$t().St().Ia()}ep(){return this.Bv.bitmapSize}rp(t,i,s){let e=this.ep();e.width>0&&e.height>0&&t.drawImage(this.Bv.canvasElement,i,s)}Gv(t){if(t===0)return;if(t!==1){this.Bv.applySuggestedBitmapSize();let s=Y(this.Bv);s!==null&&(s.useBitmapCoordinateSpace(e=>{this.Qv(e),this.Ae(e),this.Cm(s,ah)}),this.ip(s),this.Cm(s,oh)),this.am!==null&&this.am.Gv(t),this.om!==null&&this.om.Gv(t)}this.Vv.applySuggestedBitmapSize();let i=Y(this.Vv);i!==null&&(i.useBitmapCoordinateSpace(({context:s,bitmapSize:e})=>{s.clearRect(0,0,e.width,e.height)}),this.Tm([...this.Mp.$t().wt(),this.Mp.$t().Wc()],i),this.Cm(i,lh))}Cm(t,i){let s=this.Mp.$t().wt();for(let e of s)Is(i,h=>Hs(h,t,!1,void 0),e,void 0);for(let e of s)Is(i,h=>It(h,t,!1,void 0),e,void 0)}Qv({context:t,bitmapSize:i}){oi(t,0,0,i.width,i.height,this.Mp.$t()._d())}Ae({context:t,bitmapSize:i,verticalPixelRatio:s}){if(this.Mp.W().timeScale.borderVisible){t.fillStyle=this.Pm();let e=Math.max(1,Math.floor(this.ym().C*s));t.fillRect(0,0,i.width,e)}}ip(t){let i=this.Mp.$t().St(),s=i.Ia();if(!s||s.length===0)return;let e=this.N_.maxTickMarkWeight(s),h=this.ym(),r=i.W();r.borderVisible&&r.ticksVisible&&t.useBitmapCoordinateSpace(({context:o,horizontalPixelRatio:l,verticalPixelRatio:a})=>{o.strokeStyle=this.Pm(),o.fillStyle=this.Pm();let u=Math.max(1,Math.floor(l)),c=Math.floor(.5*l);o.beginPath();let d=Math.round(h.T*a);for(let f=s.length;f--;){let p=Math.round(s[f].coord*l);o.rect(p-c,0,u,d)}o.fill()}),t.useMediaCoordinateSpace(({context:o})=>{let l=h.C+h.T+h.L+h.P/2;o.textAlign="center",o.textBaseline="middle",o.fillStyle=this.$(),o.font=this.qv();for(let a of s)if(a.weight<e){let u=a.needAlignCoordinate?this.Rm(o,a.coord,a.label):a.coord;o.fillText(a.label,u,l)}this.Mp.W().timeScale.allowBoldLabels&&(o.font=this.Dm());for(let a of s)if(a.weight>=e){let u=a.needAlignCoordinate?this.Rm(o,a.coord,a.label):a.coord;o.fillText(a.label,u,l)}})}Rm(t,i,s){let e=this.xv.Mi(t,s),h=e/2,r=Math.floor(i-h)+.5;return r<0?i+=Math.abs(0-r):r+e>this.gv.width&&(i-=Math.abs(this.gv.width-(r+e))),i}Tm(t,i){let s=this.ym();for(let e of t)for(let h of e.Ji())h.gt().K(i,s)}Pm(){return this.Mp.W().timeScale.borderColor}$(){return this._n.textColor}j(){return this._n.fontSize}qv(){return st(this.j(),this._n.fontFamily)}Dm(){return st(this.j(),this._n.fontFamily,"bold")}ym(){this.k===null&&(this.k={C:1,N:NaN,L:NaN,B:NaN,Wi:NaN,T:5,P:NaN,R:"",Fi:new Z,km:0});let t=this.k,i=this.qv();if(t.R!==i){let s=this.j();t.P=s,t.R=i,t.L=3*s/12,t.B=3*s/12,t.Wi=9*s/12,t.N=0,t.km=4*s/12,t.Fi.Qe()}return this.k}hp(t){this.Av.style.cursor=t===1?"ew-resize":"default"}bm(){let t=this.Mp.$t(),i=t.W();i.leftPriceScale.visible||this.am===null||(this.fm.removeChild(this.am.Hv()),this.am.S(),this.am=null),i.rightPriceScale.visible||this.om===null||(this.vm.removeChild(this.om.Hv()),this.om.S(),this.om=null);let s={ed:this.Mp.$t().ed()},e=()=>i.leftPriceScale.borderVisible&&t.St().W().borderVisible,h=()=>t._d();i.leftPriceScale.visible&&this.am===null&&(this.am=new hi("left",i,s,e,h),this.fm.appendChild(this.am.Hv())),i.rightPriceScale.visible&&this.om===null&&(this.om=new hi("right",i,s,e,h),this.vm.appendChild(this.om.Hv()))}},uh=!!rt&&!!navigator.userAgentData&&navigator.userAgentData.brands.some(n=>n.brand.includes("Chromium"))&&!!rt&&(!((Ti=navigator?.userAgentData)===null||Ti===void 0)&&Ti.platform?navigator.userAgentData.platform==="Windows":navigator.userAgent.toLowerCase().indexOf("win")>=0),Ti,js=class{constructor(t,i,s){var e;this.Om=[],this.Am=0,this.Qa=0,this.e_=0,this.Bm=0,this.Vm=0,this.zm=null,this.Em=!1,this.cp=new y,this.dp=new y,this.xc=new y,this.Im=null,this.Lm=null,this.Nm=t,this._n=i,this.N_=s,this.dm=document.createElement("div"),this.dm.classList.add("tv-lightweight-charts"),this.dm.style.overflow="hidden",this.dm.style.direction="ltr",this.dm.style.width="100%",this.dm.style.height="100%",(e=this.dm).style.userSelect="none",e.style.webkitUserSelect="none",e.style.msUserSelect="none",e.style.MozUserSelect="none",e.style.webkitTapHighlightColor="transparent",this.Fm=document.createElement("table"),this.Fm.setAttribute("cellspacing","0"),this.dm.appendChild(this.Fm),this.Wm=this.jm.bind(this),Oi(this._n)&&this.Hm(!0),this.Hi=new Bs(this.yc.bind(this),this._n,s),this.$t().jc().l(this.$m.bind(this),this),this.Um=new Fs(this,this.N_),this.Fm.appendChild(this.Um.Hv());let h=i.autoSize&&this.qm(),r=this._n.width,o=this._n.height;if(h||r===0||o===0){let l=t.getBoundingClientRect();r=r||l.width,o=o||l.height}this.Ym(r,o),this.Xm(),t.appendChild(this.dm),this.Km(),this.Hi.St().Gu().l(this.Hi.$l.bind(this.Hi),this),this.Hi.f_().l(this.Hi.$l.bind(this.Hi),this)}$t(){return this.Hi}W(){return this._n}Zm(){return this.Om}Gm(){return this.Um}S(){this.Hm(!1),this.Am!==0&&window.cancelAnimationFrame(this.Am),this.Hi.jc().p(this),this.Hi.St().Gu().p(this),this.Hi.f_().p(this),this.Hi.S();for(let t of this.Om)this.Fm.removeChild(t.Hv()),t.Wp().p(this),t.jp().p(this),t.S();this.Om=[],v(this.Um).S(),this.dm.parentElement!==null&&this.dm.parentElement.removeChild(this.dm),this.xc.S(),this.cp.S(),this.dp.S(),this.Jm()}Ym(t,i,s=!1){if(this.Qa===i&&this.e_===t)return;let e=function(o){let l=Math.floor(o.width),a=Math.floor(o.height);return M({width:l-l%2,height:a-a%2})}(M({width:t,height:i}));this.Qa=e.height,this.e_=e.width;let h=this.Qa+"px",r=this.e_+"px";v(this.dm).style.height=h,v(this.dm).style.width=r,this.Fm.style.height=h,this.Fm.style.width=r,s?this.Qm(C.ns(),performance.now()):this.Hi.$l()}Gv(t){t===void 0&&(t=C.ns());for(let i=0;i<this.Om.length;i++)this.Om[i].Gv(t.Wn(i).Ln);this._n.timeScale.visible&&this.Um.Gv(t.Fn())}Lh(t){let i=Oi(this._n);this.Hi.Lh(t);let s=Oi(this._n);s!==i&&this.Hm(s),this.Km(),this.tb(t)}Wp(){return this.cp}jp(){return this.dp}jc(){return this.xc}ib(){this.zm!==null&&(this.Qm(this.zm,performance.now()),this.zm=null);let t=this.nb(null),i=document.createElement("canvas");i.width=t.width,i.height=t.height;let s=v(i.getContext("2d"));return this.nb(s),i}sb(t){return t==="left"&&!this.eb()||t==="right"&&!this.rb()||this.Om.length===0?0:v(t==="left"?this.Om[0].Gp():this.Om[0].Jp()).Kv()}hb(){return this._n.autoSize&&this.Im!==null}lb(){return this.dm}Vp(t){this.Lm=t,this.Lm?this.lb().style.setProperty("cursor",t):this.lb().style.removeProperty("cursor")}ab(){return this.Lm}ob(){return z(this.Om[0]).Up()}tb(t){(t.autoSize!==void 0||!this.Im||t.width===void 0&&t.height===void 0)&&(t.autoSize&&!this.Im&&this.qm(),t.autoSize===!1&&this.Im!==null&&this.Jm(),t.autoSize||t.width===void 0&&t.height===void 0||this.Ym(t.width||this.e_,t.height||this.Qa))}nb(t){let i=0,s=0,e=this.Om[0],h=(o,l)=>{let a=0;for(let u=0;u<this.Om.length;u++){let c=this.Om[u],d=v(o==="left"?c.Gp():c.Jp()),f=d.ep();t!==null&&d.rp(t,l,a),a+=f.height}};this.eb()&&(h("left",0),i+=v(e.Gp()).ep().width);for(let o=0;o<this.Om.length;o++){let l=this.Om[o],a=l.ep();t!==null&&l.rp(t,i,s),s+=a.height}i+=e.ep().width,this.rb()&&(h("right",i),i+=v(e.Jp()).ep().width);let r=(o,l,a)=>{v(o==="left"?this.Um.wm():this.Um.gm()).rp(v(t),l,a)};if(this._n.timeScale.visible){let o=this.Um.ep();if(t!==null){let l=0;this.eb()&&(r("left",l,s),l=v(e.Gp()).ep().width),this.Um.rp(t,l,s),l+=o.width,this.rb()&&r("right",l,s)}s+=o.height}return M({width:i,height:s})}_b(){let t=0,i=0,s=0;for(let p of this.Om)this.eb()&&(i=Math.max(i,v(p.Gp()).Uv(),this._n.leftPriceScale.minimumWidth)),this.rb()&&(s=Math.max(s,v(p.Jp()).Uv(),this._n.rightPriceScale.minimumWidth)),t+=p.v_();i=Ws(i),s=Ws(s);let e=this.e_,h=this.Qa,r=Math.max(e-i-s,0),o=this._n.timeScale.visible,l=o?Math.max(this.Um.Sm(),this._n.timeScale.minimumHeight):0;var a;l=(a=l)+a%2;let u=0+l,c=h<u?0:h-u,d=c/t,f=0;for(let p=0;p<this.Om.length;++p){let m=this.Om[p];m.Pp(this.Hi.Nc()[p]);let b=0,g=0;g=p===this.Om.length-1?c-f:Math.round(m.v_()*d),b=Math.max(g,2),f+=b,m.Xv(M({width:r,height:b})),this.eb()&&m.$p(i,"left"),this.rb()&&m.$p(s,"right"),m.Zv()&&this.Hi.Hc(m.Zv(),b)}this.Um.xm(M({width:o?r:0,height:l}),o?i:0,o?s:0),this.Hi.m_(r),this.Bm!==i&&(this.Bm=i),this.Vm!==s&&(this.Vm=s)}Hm(t){t?this.dm.addEventListener("wheel",this.Wm,{passive:!1}):this.dm.removeEventListener("wheel",this.Wm)}ub(t){switch(t.deltaMode){case t.DOM_DELTA_PAGE:return 120;case t.DOM_DELTA_LINE:return 32}return uh?1/window.devicePixelRatio:1}jm(t){if(!(t.deltaX!==0&&this._n.handleScroll.mouseWheel||t.deltaY!==0&&this._n.handleScale.mouseWheel))return;let i=this.ub(t),s=i*t.deltaX/100,e=-i*t.deltaY/100;if(t.cancelable&&t.preventDefault(),e!==0&&this._n.handleScale.mouseWheel){let h=Math.sign(e)*Math.min(1,Math.abs(e)),r=t.clientX-this.dm.getBoundingClientRect().left;this.$t().qc(r,h)}s!==0&&this._n.handleScroll.mouseWheel&&this.$t().Yc(-80*s)}Qm(t,i){var s;let e=t.Fn();e===3&&this.cb(),e!==3&&e!==2||(this.fb(t),this.vb(t,i),this.Um.bt(),this.Om.forEach(h=>{h.Dp()}),((s=this.zm)===null||s===void 0?void 0:s.Fn())===3&&(this.zm.Jn(t),this.cb(),this.fb(this.zm),this.vb(this.zm,i),t=this.zm,this.zm=null)),this.Gv(t)}vb(t,i){for(let s of t.Gn())this.Qn(s,i)}fb(t){let i=this.Hi.Nc();for(let s=0;s<i.length;s++)t.Wn(s).Nn&&i[s].B_()}Qn(t,i){let s=this.Hi.St();switch(t.$n){case 0:s.Qu();break;case 1:s.tc(t.Ot);break;case 2:s.Kn(t.Ot);break;case 3:s.Zn(t.Ot);break;case 4:s.Fu();break;case 5:t.Ot.Yu(i)||s.Zn(t.Ot.Xu(i))}}yc(t){this.zm!==null?this.zm.Jn(t):this.zm=t,this.Em||(this.Em=!0,this.Am=window.requestAnimationFrame(i=>{if(this.Em=!1,this.Am=0,this.zm!==null){let s=this.zm;this.zm=null,this.Qm(s,i);for(let e of s.Gn())if(e.$n===5&&!e.Ot.Yu(i)){this.$t().qn(e.Ot);break}}}))}cb(){this.Xm()}Xm(){let t=this.Hi.Nc(),i=t.length,s=this.Om.length;for(let e=i;e<s;e++){let h=z(this.Om.pop());this.Fm.removeChild(h.Hv()),h.Wp().p(this),h.jp().p(this),h.S()}for(let e=s;e<i;e++){let h=new $s(this,t[e]);h.Wp().l(this.pb.bind(this),this),h.jp().l(this.mb.bind(this),this),this.Om.push(h),this.Fm.insertBefore(h.Hv(),this.Um.Hv())}for(let e=0;e<i;e++){let h=t[e],r=this.Om[e];r.Zv()!==h?r.Pp(h):r.Tp()}this.Km(),this._b()}bb(t,i,s){var e;let h=new Map;t!==null&&this.Hi.wt().forEach(u=>{let c=u.Bn().il(t);c!==null&&h.set(u,c)});let r;if(t!==null){let u=(e=this.Hi.St().$i(t))===null||e===void 0?void 0:e.originalTime;u!==void 0&&(r=u)}let o=this.$t().Vc(),l=o!==null&&o.Ec instanceof Et?o.Ec:void 0,a=o!==null&&o.mv!==void 0?o.mv.mr:void 0;return{wb:r,ie:t??void 0,gb:i??void 0,Mb:l,xb:h,Sb:a,yb:s??void 0}}pb(t,i,s){this.cp.m(()=>this.bb(t,i,s))}mb(t,i,s){this.dp.m(()=>this.bb(t,i,s))}$m(t,i,s){this.xc.m(()=>this.bb(t,i,s))}Km(){let t=this._n.timeScale.visible?"":"none";this.Um.Hv().style.display=t}eb(){return this.Om[0].Zv().S_().W().visible}rb(){return this.Om[0].Zv().y_().W().visible}qm(){return"ResizeObserver"in window&&(this.Im=new ResizeObserver(t=>{let i=t.find(s=>s.target===this.Nm);i&&this.Ym(i.contentRect.width,i.contentRect.height)}),this.Im.observe(this.Nm,{box:"border-box"}),!0)}Jm(){this.Im!==null&&this.Im.disconnect(),this.Im=null}};function Oi(n){return!!(n.handleScroll.mouseWheel||n.handleScale.mouseWheel)}function an(n,t){var i={};for(var s in n)Object.prototype.hasOwnProperty.call(n,s)&&t.indexOf(s)<0&&(i[s]=n[s]);if(n!=null&&typeof Object.getOwnPropertySymbols=="function"){var e=0;for(s=Object.getOwnPropertySymbols(n);e<s.length;e++)t.indexOf(s[e])<0&&Object.prototype.propertyIsEnumerable.call(n,s[e])&&(i[s[e]]=n[s[e]])}return i}function Ae(n,t,i,s){let e=i.value,h={ie:t,ot:n,Ot:[e,e,e,e],wb:s};return i.color!==void 0&&(h.O=i.color),h}function ch(n,t,i,s){let e=i.value,h={ie:t,ot:n,Ot:[e,e,e,e],wb:s};return i.lineColor!==void 0&&(h.lt=i.lineColor),i.topColor!==void 0&&(h.ks=i.topColor),i.bottomColor!==void 0&&(h.Cs=i.bottomColor),h}function dh(n,t,i,s){let e=i.value,h={ie:t,ot:n,Ot:[e,e,e,e],wb:s};return i.topLineColor!==void 0&&(h.Ce=i.topLineColor),i.bottomLineColor!==void 0&&(h.Te=i.bottomLineColor),i.topFillColor1!==void 0&&(h.Me=i.topFillColor1),i.topFillColor2!==void 0&&(h.xe=i.topFillColor2),i.bottomFillColor1!==void 0&&(h.Se=i.bottomFillColor1),i.bottomFillColor2!==void 0&&(h.ye=i.bottomFillColor2),h}function fh(n,t,i,s){let e={ie:t,ot:n,Ot:[i.open,i.high,i.low,i.close],wb:s};return i.color!==void 0&&(e.O=i.color),e}function mh(n,t,i,s){let e={ie:t,ot:n,Ot:[i.open,i.high,i.low,i.close],wb:s};return i.color!==void 0&&(e.O=i.color),i.borderColor!==void 0&&(e.At=i.borderColor),i.wickColor!==void 0&&(e.Hh=i.wickColor),e}function ph(n,t,i,s,e){let h=z(e)(i),r=Math.max(...h),o=Math.min(...h),l=h[h.length-1],a=[l,r,o,l],u=i,{time:c,color:d}=u;return{ie:t,ot:n,Ot:a,wb:s,We:an(u,["time","color"]),O:d}}function Ht(n){return n.Ot!==void 0}function We(n,t){return t.customValues!==void 0&&(n.kb=t.customValues),n}function $(n){return(t,i,s,e,h,r)=>function(o,l){return l?l(o):(a=o).open===void 0&&a.value===void 0;var a}(s,r)?We({ot:t,ie:i,wb:e},s):We(n(t,i,s,e,h),s)}function Ve(n){return{Candlestick:$(mh),Bar:$(fh),Area:$(ch),Baseline:$(dh),Histogram:$(Ae),Line:$(Ae),Custom:$(ph)}[n]}function He(n){return{ie:0,Cb:new Map,ia:n}}function Ie(n,t){if(n!==void 0&&n.length!==0)return{Tb:t.key(n[0].ot),Pb:t.key(n[n.length-1].ot)}}function $e(n){let t;return n.forEach(i=>{t===void 0&&(t=i.wb)}),z(t)}var Us=class{constructor(t){this.Rb=new Map,this.Db=new Map,this.Ob=new Map,this.Ab=[],this.N_=t}S(){this.Rb.clear(),this.Db.clear(),this.Ob.clear(),this.Ab=[]}Bb(t,i){let s=this.Rb.size!==0,e=!1,h=this.Db.get(t);if(h!==void 0)if(this.Db.size===1)s=!1,e=!0,this.Rb.clear();else for(let l of this.Ab)l.pointData.Cb.delete(t)&&(e=!0);let r=[];if(i.length!==0){let l=i.map(f=>f.time),a=this.N_.createConverterToInternalObj(i),u=Ve(t.Yh()),c=t.ga(),d=t.Ma();r=i.map((f,p)=>{let m=a(f.time),b=this.N_.key(m),g=this.Rb.get(b);g===void 0&&(g=He(m),this.Rb.set(b,g),e=!0);let w=u(m,g.ie,f,l[p],c,d);return g.Cb.set(t,w),w})}s&&this.Vb(),this.zb(t,r);let o=-1;if(e){let l=[];this.Rb.forEach(a=>{l.push({timeWeight:0,time:a.ia,pointData:a,originalTime:$e(a.Cb)})}),l.sort((a,u)=>this.N_.key(a.time)-this.N_.key(u.time)),o=this.Eb(l)}return this.Ib(t,o,function(l,a,u){let c=Ie(l,u),d=Ie(a,u);if(c!==void 0&&d!==void 0)return{Xl:c.Pb>=d.Pb&&c.Tb>=d.Tb}}(this.Db.get(t),h,this.N_))}ld(t){return this.Bb(t,[])}Lb(t,i){let s=i;(function(m){m.wb===void 0&&(m.wb=m.time)})(s),this.N_.preprocessData(i);let e=this.N_.createConverterToInternalObj([i])(i.time),h=this.Ob.get(t);if(h!==void 0&&this.N_.key(e)<this.N_.key(h))throw new Error(`Cannot update oldest data, last time=${h}, new time=${e}`);let r=this.Rb.get(this.N_.key(e)),o=r===void 0;r===void 0&&(r=He(e),this.Rb.set(this.N_.key(e),r));let l=Ve(t.Yh()),a=t.ga(),u=t.Ma(),c=l(e,r.ie,i,s.wb,a,u);r.Cb.set(t,c),this.Nb(t,c);let d={Xl:Ht(c)};if(!o)return this.Ib(t,-1,d);let f={timeWeight:0,time:r.ia,pointData:r,originalTime:$e(r.Cb)},p=zt(this.Ab,this.N_.key(f.time),(m,b)=>this.N_.key(m.time)<b);this.Ab.splice(p,0,f);for(let m=p;m<this.Ab.length;++m)Li(this.Ab[m].pointData,m);return this.N_.fillWeightsForPoints(this.Ab,p),this.Ib(t,p,d)}Nb(t,i){let s=this.Db.get(t);s===void 0&&(s=[],this.Db.set(t,s));let e=s.length!==0?s[s.length-1]:null;e===null||this.N_.key(i.ot)>this.N_.key(e.ot)?Ht(i)&&s.push(i):Ht(i)?s[s.length-1]=i:s.splice(-1,1),this.Ob.set(t,i.ot)}zb(t,i){i.length!==0?(this.Db.set(t,i.filter(Ht)),this.Ob.set(t,i[i.length-1].ot)):(this.Db.delete(t),this.Ob.delete(t))}Vb(){for(let t of this.Ab)t.pointData.Cb.size===0&&this.Rb.delete(this.N_.key(t.time))}Eb(t){let i=-1;for(let s=0;s<this.Ab.length&&s<t.length;++s){let e=this.Ab[s],h=t[s];if(this.N_.key(e.time)!==this.N_.key(h.time)){i=s;break}h.timeWeight=e.timeWeight,Li(h.pointData,s)}if(i===-1&&this.Ab.length!==t.length&&(i=Math.min(this.Ab.length,t.length)),i===-1)return-1;for(let s=i;s<t.length;++s)Li(t[s].pointData,s);return this.N_.fillWeightsForPoints(t,i),this.Ab=t,i}Fb(){if(this.Db.size===0)return null;let t=0;return this.Db.forEach(i=>{i.length!==0&&(t=Math.max(t,i[i.length-1].ie))}),t}Ib(t,i,s){let e={Wb:new Map,St:{Au:this.Fb()}};if(i!==-1)this.Db.forEach((h,r)=>{e.Wb.set(r,{We:h,jb:r===t?s:void 0})}),this.Db.has(t)||e.Wb.set(t,{We:[],jb:s}),e.St.Hb=this.Ab,e.St.$b=i;else{let h=this.Db.get(t);e.Wb.set(t,{We:h||[],jb:s})}return e}};function Li(n,t){n.ie=t,n.Cb.forEach(i=>{i.ie=t})}function ie(n){let t={value:n.Ot[3],time:n.wb};return n.kb!==void 0&&(t.customValues=n.kb),t}function Fe(n){let t=ie(n);return n.O!==void 0&&(t.color=n.O),t}function vh(n){let t=ie(n);return n.lt!==void 0&&(t.lineColor=n.lt),n.ks!==void 0&&(t.topColor=n.ks),n.Cs!==void 0&&(t.bottomColor=n.Cs),t}function bh(n){let t=ie(n);return n.Ce!==void 0&&(t.topLineColor=n.Ce),n.Te!==void 0&&(t.bottomLineColor=n.Te),n.Me!==void 0&&(t.topFillColor1=n.Me),n.xe!==void 0&&(t.topFillColor2=n.xe),n.Se!==void 0&&(t.bottomFillColor1=n.Se),n.ye!==void 0&&(t.bottomFillColor2=n.ye),t}function un(n){let t={open:n.Ot[0],high:n.Ot[1],low:n.Ot[2],close:n.Ot[3],time:n.wb};return n.kb!==void 0&&(t.customValues=n.kb),t}function gh(n){let t=un(n);return n.O!==void 0&&(t.color=n.O),t}function wh(n){let t=un(n),{O:i,At:s,Hh:e}=n;return i!==void 0&&(t.color=i),s!==void 0&&(t.borderColor=s),e!==void 0&&(t.wickColor=e),t}function Zs(n){return{Area:vh,Line:Fe,Baseline:bh,Histogram:Fe,Bar:gh,Candlestick:wh,Custom:_h}[n]}function _h(n){let t=n.wb;return Object.assign(Object.assign({},n.We),{time:t})}var Sh={vertLine:{color:"#9598A1",width:1,style:3,visible:!0,labelVisible:!0,labelBackgroundColor:"#131722"},horzLine:{color:"#9598A1",width:1,style:3,visible:!0,labelVisible:!0,labelBackgroundColor:"#131722"},mode:1},Mh={vertLines:{color:"#D6DCDE",style:0,visible:!0},horzLines:{color:"#D6DCDE",style:0,visible:!0}},yh={background:{type:"solid",color:"#FFFFFF"},textColor:"#191919",fontSize:12,fontFamily:Ks},Ri={autoScale:!0,mode:0,invertScale:!1,alignLabels:!0,borderVisible:!0,borderColor:"#2B2B43",entireTextOnly:!1,visible:!1,ticksVisible:!1,scaleMargins:{bottom:.1,top:.2},minimumWidth:0},xh={rightOffset:0,barSpacing:6,minBarSpacing:.5,fixLeftEdge:!1,fixRightEdge:!1,lockVisibleTimeRangeOnResize:!1,rightBarStaysOnScroll:!1,borderVisible:!0,borderColor:"#2B2B43",visible:!0,timeVisible:!1,secondsVisible:!0,shiftVisibleRangeOnNewBar:!0,allowShiftVisibleRangeOnWhitespaceReplacement:!1,ticksVisible:!1,uniformDistribution:!1,minimumHeight:0,allowBoldLabels:!0},Eh={color:"rgba(0, 0, 0, 0)",visible:!1,fontSize:48,fontFamily:Ks,fontStyle:"",text:"",horzAlign:"center",vertAlign:"center"};function je(){return{width:0,height:0,autoSize:!1,layout:yh,crosshair:Sh,grid:Mh,overlayPriceScales:Object.assign({},Ri),leftPriceScale:Object.assign(Object.assign({},Ri),{visible:!1}),rightPriceScale:Object.assign(Object.assign({},Ri),{visible:!0}),timeScale:xh,watermark:Eh,localization:{locale:rt?navigator.language:"",dateFormat:"dd MMM 'yy"},handleScroll:{mouseWheel:!0,pressedMouseMove:!0,horzTouchDrag:!0,vertTouchDrag:!0},handleScale:{axisPressedMouseMove:{time:!0,price:!0},axisDoubleClickReset:{time:!0,price:!0},mouseWheel:!0,pinch:!0},kineticScroll:{mouse:!1,touch:!0},trackingMode:{exitMode:1}}}var Ys=class{constructor(t,i){this.Ub=t,this.qb=i}applyOptions(t){this.Ub.$t().Ic(this.qb,t)}options(){return this.Ei().W()}width(){return li(this.qb)?this.Ub.sb(this.qb):0}Ei(){return v(this.Ub.$t().Lc(this.qb)).Dt}};function Ue(n,t,i){let s=an(n,["time","originalTime"]),e=Object.assign({time:t},s);return i!==void 0&&(e.originalTime=i),e}var Ch={color:"#FF0000",price:0,lineStyle:2,lineWidth:1,lineVisible:!0,axisLabelVisible:!0,title:"",axisLabelColor:"",axisLabelTextColor:""},Qs=class{constructor(t){this.Bh=t}applyOptions(t){this.Bh.Lh(t)}options(){return this.Bh.W()}Yb(){return this.Bh}},qs=class{constructor(t,i,s,e,h){this.Xb=new y,this.zs=t,this.Kb=i,this.Zb=s,this.N_=h,this.Gb=e}S(){this.Xb.S()}priceFormatter(){return this.zs.ca()}priceToCoordinate(t){let i=this.zs.Ct();return i===null?null:this.zs.Dt().Rt(t,i.Ot)}coordinateToPrice(t){let i=this.zs.Ct();return i===null?null:this.zs.Dt().fn(t,i.Ot)}barsInLogicalRange(t){if(t===null)return null;let i=new it(new F(t.from,t.to)).iu(),s=this.zs.Bn();if(s.Li())return null;let e=s.il(i.Rs(),1),h=s.il(i.ui(),-1),r=v(s.Jh()),o=v(s.An());if(e!==null&&h!==null&&e.ie>h.ie)return{barsBefore:t.from-r,barsAfter:o-t.to};let l={barsBefore:e===null||e.ie===r?t.from-r:e.ie-r,barsAfter:h===null||h.ie===o?o-t.to:o-h.ie};return e!==null&&h!==null&&(l.from=e.wb,l.to=h.wb),l}setData(t){this.N_,this.zs.Yh(),this.Kb.Jb(this.zs,t),this.Qb("full")}update(t){this.zs.Yh(),this.Kb.tw(this.zs,t),this.Qb("update")}dataByIndex(t,i){let s=this.zs.Bn().il(t,i);return s===null?null:Zs(this.seriesType())(s)}data(){let t=Zs(this.seriesType());return this.zs.Bn().Qs().map(i=>t(i))}subscribeDataChanged(t){this.Xb.l(t)}unsubscribeDataChanged(t){this.Xb.v(t)}setMarkers(t){this.N_;let i=t.map(s=>Ue(s,this.N_.convertHorzItemToInternal(s.time),s.time));this.zs.Zl(i)}markers(){return this.zs.Gl().map(t=>Ue(t,t.originalTime,void 0))}applyOptions(t){this.zs.Lh(t)}options(){return A(this.zs.W())}priceScale(){return this.Zb.priceScale(this.zs.Dt().xa())}createPriceLine(t){let i=N(A(Ch),t),s=this.zs.Jl(i);return new Qs(s)}removePriceLine(t){this.zs.Ql(t.Yb())}seriesType(){return this.zs.Yh()}attachPrimitive(t){this.zs.ba(t),t.attached&&t.attached({chart:this.Gb,series:this,requestUpdate:()=>this.zs.$t().$l()})}detachPrimitive(t){this.zs.wa(t),t.detached&&t.detached()}Qb(t){this.Xb.M()&&this.Xb.m(t)}},Xs=class{constructor(t,i,s){this.iw=new y,this.uu=new y,this.um=new y,this.Hi=t,this.wl=t.St(),this.Um=i,this.wl.Ku().l(this.nw.bind(this)),this.wl.Zu().l(this.sw.bind(this)),this.Um.Mm().l(this.ew.bind(this)),this.N_=s}S(){this.wl.Ku().p(this),this.wl.Zu().p(this),this.Um.Mm().p(this),this.iw.S(),this.uu.S(),this.um.S()}scrollPosition(){return this.wl.Iu()}scrollToPosition(t,i){i?this.wl.qu(t,1e3):this.Hi.Zn(t)}scrollToRealTime(){this.wl.Uu()}getVisibleRange(){let t=this.wl.ku();return t===null?null:{from:t.from.originalTime,to:t.to.originalTime}}setVisibleRange(t){let i={from:this.N_.convertHorzItemToInternal(t.from),to:this.N_.convertHorzItemToInternal(t.to)},s=this.wl.Ru(i);this.Hi.ad(s)}getVisibleLogicalRange(){let t=this.wl.yu();return t===null?null:{from:t.Rs(),to:t.ui()}}setVisibleLogicalRange(t){I(t.from<=t.to,"The from index cannot be after the to index."),this.Hi.ad(t)}resetTimeScale(){this.Hi.Xn()}fitContent(){this.Hi.Qu()}logicalToCoordinate(t){let i=this.Hi.St();return i.Li()?null:i.zt(t)}coordinateToLogical(t){return this.wl.Li()?null:this.wl.Bu(t)}timeToCoordinate(t){let i=this.N_.convertHorzItemToInternal(t),s=this.wl.ka(i,!1);return s===null?null:this.wl.zt(s)}coordinateToTime(t){let i=this.Hi.St(),s=i.Bu(t),e=i.$i(s);return e===null?null:e.originalTime}width(){return this.Um.Up().width}height(){return this.Um.Up().height}subscribeVisibleTimeRangeChange(t){this.iw.l(t)}unsubscribeVisibleTimeRangeChange(t){this.iw.v(t)}subscribeVisibleLogicalRangeChange(t){this.uu.l(t)}unsubscribeVisibleLogicalRangeChange(t){this.uu.v(t)}subscribeSizeChange(t){this.um.l(t)}unsubscribeSizeChange(t){this.um.v(t)}applyOptions(t){this.wl.Lh(t)}options(){return Object.assign(Object.assign({},A(this.wl.W())),{barSpacing:this.wl.ee()})}nw(){this.iw.M()&&this.iw.m(this.getVisibleRange())}sw(){this.uu.M()&&this.uu.m(this.getVisibleLogicalRange())}ew(t){this.um.m(t.width,t.height)}};function kh(n){if(n===void 0||n.type==="custom")return;let t=n;t.minMove!==void 0&&t.precision===void 0&&(t.precision=function(i){if(i>=1)return 0;let s=0;for(;s<8;s++){let e=Math.round(i);if(Math.abs(e-i)<1e-8)return s;i*=10}return s}(t.minMove))}function Ze(n){return function(t){if(Lt(t.handleScale)){let s=t.handleScale;t.handleScale={axisDoubleClickReset:{time:s,price:s},axisPressedMouseMove:{time:s,price:s},mouseWheel:s,pinch:s}}else if(t.handleScale!==void 0){let{axisPressedMouseMove:s,axisDoubleClickReset:e}=t.handleScale;Lt(s)&&(t.handleScale.axisPressedMouseMove={time:s,price:s}),Lt(e)&&(t.handleScale.axisDoubleClickReset={time:e,price:e})}let i=t.handleScroll;Lt(i)&&(t.handleScroll={horzTouchDrag:i,vertTouchDrag:i,mouseWheel:i,pressedMouseMove:i})}(n),n}var Js=class{constructor(t,i,s){this.rw=new Map,this.hw=new Map,this.lw=new y,this.aw=new y,this.ow=new y,this._w=new Us(i);let e=s===void 0?A(je()):N(A(je()),Ze(s));this.N_=i,this.Ub=new js(t,e,i),this.Ub.Wp().l(r=>{this.lw.M()&&this.lw.m(this.uw(r()))},this),this.Ub.jp().l(r=>{this.aw.M()&&this.aw.m(this.uw(r()))},this),this.Ub.jc().l(r=>{this.ow.M()&&this.ow.m(this.uw(r()))},this);let h=this.Ub.$t();this.cw=new Xs(h,this.Ub.Gm(),this.N_)}remove(){this.Ub.Wp().p(this),this.Ub.jp().p(this),this.Ub.jc().p(this),this.cw.S(),this.Ub.S(),this.rw.clear(),this.hw.clear(),this.lw.S(),this.aw.S(),this.ow.S(),this._w.S()}resize(t,i,s){this.autoSizeActive()||this.Ub.Ym(t,i,s)}addCustomSeries(t,i){let s=G(t),e=Object.assign(Object.assign({},Ye),s.defaultOptions());return this.dw("Custom",e,i,s)}addAreaSeries(t){return this.dw("Area",Sn,t)}addBaselineSeries(t){return this.dw("Baseline",Mn,t)}addBarSeries(t){return this.dw("Bar",wn,t)}addCandlestickSeries(t={}){return function(i){i.borderColor!==void 0&&(i.borderUpColor=i.borderColor,i.borderDownColor=i.borderColor),i.wickColor!==void 0&&(i.wickUpColor=i.wickColor,i.wickDownColor=i.wickColor)}(t),this.dw("Candlestick",gn,t)}addHistogramSeries(t){return this.dw("Histogram",yn,t)}addLineSeries(t){return this.dw("Line",_n,t)}removeSeries(t){let i=z(this.rw.get(t)),s=this._w.ld(i);this.Ub.$t().ld(i),this.fw(s),this.rw.delete(t),this.hw.delete(i)}Jb(t,i){this.fw(this._w.Bb(t,i))}tw(t,i){this.fw(this._w.Lb(t,i))}subscribeClick(t){this.lw.l(t)}unsubscribeClick(t){this.lw.v(t)}subscribeCrosshairMove(t){this.ow.l(t)}unsubscribeCrosshairMove(t){this.ow.v(t)}subscribeDblClick(t){this.aw.l(t)}unsubscribeDblClick(t){this.aw.v(t)}priceScale(t){return new Ys(this.Ub,t)}timeScale(){return this.cw}applyOptions(t){this.Ub.Lh(Ze(t))}options(){return this.Ub.W()}takeScreenshot(){return this.Ub.ib()}autoSizeActive(){return this.Ub.hb()}chartElement(){return this.Ub.lb()}paneSize(){let t=this.Ub.ob();return{height:t.height,width:t.width}}setCrosshairPosition(t,i,s){let e=this.rw.get(s);if(e===void 0)return;let h=this.Ub.$t()._r(e);h!==null&&this.Ub.$t().td(t,i,h)}clearCrosshairPosition(){this.Ub.$t().nd(!0)}dw(t,i,s={},e){kh(s.priceFormat);let h=N(A(Qe),A(i),s),r=this.Ub.$t().rd(t,h,e),o=new qs(r,this,this,this,this.N_);return this.rw.set(o,r),this.hw.set(r,o),o}fw(t){let i=this.Ub.$t();i.sd(t.St.Au,t.St.Hb,t.St.$b),t.Wb.forEach((s,e)=>e.J(s.We,s.jb)),i.zu()}pw(t){return z(this.hw.get(t))}uw(t){let i=new Map;t.xb.forEach((e,h)=>{let r=h.Yh(),o=Zs(r)(e);if(r!=="Custom")I(function(l){return l.open!==void 0||l.value!==void 0}(o));else{let l=h.Ma();I(!l||l(o)===!1)}i.set(this.pw(h),o)});let s=t.Mb===void 0?void 0:this.pw(t.Mb);return{time:t.wb,logical:t.ie,point:t.gb,hoveredSeries:s,hoveredObjectId:t.Sb,seriesData:i,sourceEvent:t.yb}}};function zh(n,t,i){let s;if(kt(n)){let h=document.getElementById(n);I(h!==null,`Cannot find element in DOM with id=${n}`),s=h}else s=n;let e=new Js(s,t,i);return t.setOptions(e.options()),e}function cn(n,t){return zh(n,new ei,ei.Pd(t))}var Hh=Object.assign(Object.assign({},Qe),Ye);var X=globalThis.getComputedStyle(document.body),B={muted:X.getPropertyValue("--b2"),pink:X.getPropertyValue("--pink"),blue:X.getPropertyValue("--blue"),pinkText:X.getPropertyValue("--pink-text"),blueText:X.getPropertyValue("--blue-text")},Oh={autoSize:!0,handleScale:!1,handleScroll:!1,grid:{vertLines:{visible:!1},horzLines:{visible:!0,color:B.muted}},layout:{background:{type:ii.Solid,color:"transparent"},textColor:X.color,fontFamily:X.fontFamily},rightPriceScale:{scaleMargins:{top:0,bottom:0}},timeScale:{lockVisibleTimeRangeOnResize:!0,borderVisible:!1,timeVisible:!0,secondsVisible:!1,fixLeftEdge:!0,fixRightEdge:!0}},ai={baseLineVisible:!1,priceLineVisible:!1,autoscaleInfoProvider:n=>{let t=n();return t!=null&&(t.priceRange.minValue=0,t.priceRange.maxValue*=1.1),t}};function fn(n,t){return cn(Lh(n),{...Oh,...t})}var ui=class n{static numericalKeys=["mean","open","close"];chart;spanSeries;lineSeries;numericalSeries;constructor(t,i={}){this.chart=fn(t,i),this.spanSeries=this.chart.addHistogramSeries({priceScaleId:"spans",color:B.muted,priceLineVisible:!1,lastValueVisible:!1}),this.chart.priceScale("spans").applyOptions({visible:!1,scaleMargins:{top:0,bottom:0}}),this.numericalSeries={},n.numericalKeys.forEach(s=>{this.numericalSeries[s]=this.chart.addLineSeries({...ai,color:B.pinkText,lineType:vt.Curved,lineWidth:1,lineStyle:$t.Dashed,priceFormat:{type:"custom",formatter:e=>e.toFixed(0)+" pg/mL"}})}),this.lineSeries=this.chart.addLineSeries({...ai,color:B.pink,lineType:vt.Curved,pointMarkersVisible:!1,crosshairMarkerVisible:!1,priceFormat:{type:"custom",formatter:s=>s.toFixed(0)+" pg/mL"}})}update(t,i={}){let s=i.spans??[];if(this.spanSeries.setData(t.map(e=>s.some(h=>e.t>=h.from&&(h.to===void 0||e.t<h.to))?{time:e.t,value:1}:{time:e.t})),this.lineSeries.setData(t.map(e=>({time:e.t,value:e.v}))),i.quantile&&i.bucketDays){let{quantile:e,bucketDays:h}=i,r=h*24,o=Array.from({length:Math.ceil(t.length/r)},(a,u)=>{let c=u*r,d=Math.min((u+1)*r,t.length),f=t.slice(c,d).map(w=>w.v),p=lt(f,e),m=lt(f,1-e),b=Math.min(...f),g=Math.max(...f);return{time:t[d-1].t,color:B.blue,wickColor:B.blue,borderColor:B.blue,open:m,close:p,high:g,low:b}});i.onlyLastBucket&&(o=[{...o[o.length-1],time:o[o.length-2].time},{...o[o.length-1]}]);let l=a=>o.map(u=>({time:u.time,value:a(u)}));this.numericalSeries.mean.setData(l(a=>(a.open+a.close)/2)),this.numericalSeries.open.setData(l(a=>a.open)),this.numericalSeries.close.setData(l(a=>a.close))}else Object.values(this.numericalSeries).forEach(e=>e.setData([]));this.chart.timeScale().fitContent()}},dn=class{constructor(t,i,s={}){this.candlestickOpts=i;this.chart=fn(t,s),this.candlestickSeries=this.chart.addCandlestickSeries({...ai,priceFormat:{type:"custom",formatter:h=>h.toFixed(0)+" pg/mL"}});let e={...ai,color:B.blue};this.numericalSeries={mean:this.chart.addLineSeries(e),open:this.chart.addLineSeries(e),close:this.chart.addLineSeries(e)}}chart;candlestickSeries;numericalSeries;update(t){let{quantile:i,quantizeDays:s}=this.candlestickOpts,e=s*24,h=Array.from({length:Math.ceil(t.length/e)},(r,o)=>{let l=o*e,a=Math.min((o+1)*e,t.length),u=(a-l)/2,c=t.slice(l,a).map(b=>b.v),d=lt(c,i),f=lt(c,1-i),p=Math.min(...c),m=Math.max(...c);return{time:t[l+u].t,color:B.blue,wickColor:B.blue,borderColor:B.blue,open:f,close:d,high:m,low:p}});this.candlestickSeries.setData(h),this.numericalSeries.mean.setData(h.map(r=>({time:r.time,value:(r.open+r.close)/2}))),this.chart.timeScale().fitContent()}};function Lh(n){if(typeof n=="string"){let t=document.querySelector(n);if(!t)throw new Error(`Element not found: ${n}`);return t}return n}function se(n,t){let i={since:0,intervalHours:n.intervalHours,concurrence:n.concurrence,strength:n.strength??1};for(let s of n.changes??[]){if(s.since>t)break;i=s}return{...i,strength:i.strength||1}}var Tt=3600,ci=class{f;f_0;f_1;constructor(t,i){this.f=s=>(t[s]??0)*i,this.f_0=0,this.f_1=t.length}predict(t,i){if(t.length===0)return Promise.resolve([]);t.sort((c,d)=>c-d);let s=this.f_0,e=this.f_1;console.debug("predictor:",{f_0:s,f_1:e});let h=Date.now()/1e3,r=h-i.hoursAgo*Tt;console.debug("predictor: from",new Date(r*1e3).toISOString(),"to",new Date(h*1e3).toISOString());let o=Math.ceil((h-r)/Tt);console.debug("predictor: hours",o);let l=Array(o).fill(0);console.debug("values",l);let a=new Map((i.removals??[]).map(c=>[c.appliedAt,c.removedAt]));for(let[c,d]of t.entries()){let f=se(i,d),p=f.concurrence>0?Math.min(e,f.intervalHours*f.concurrence):e,m=Math.floor((d-r)/Tt)+s,b=m+(t.length-c<=f.concurrence?e:p),g=a.get(d);if(g!==void 0&&(b=Math.min(b,Math.ceil((g-r)/Tt))),console.debug("for",d,"going from",m,"to",b,"of",l.length,"hours"),b<0)continue;let w=0;m<0&&(w=-m,m=0,b=Math.min(l.length,b-m)),m=Math.max(0,m),b=Math.min(l.length,b),console.debug("for",d,"total hours taken is",b-m);for(let S=m;S<b;S++)l[S]+=this.f(S-m+w)*f.strength}console.debug("values",l);let u=l.map((c,d)=>({t:r+d*Tt,v:c}));return Promise.resolve(u)}};var mn=[0,8.5,17,25.5,34,47.7,59.6,71.4,83.3,88.6,93.8,99,102.7,103.2,103.8,104.3,104.8,105.3,105.9,106.4,106.9,107.4,108,108.5,108.1,108.1,108.1,108.1,108.1,108.1,109.2,110.3,111.4,112.5,113.6,114.7,114.4,111.9,109.4,106.9,104.4,101.9,99.4,96.9,94.4,91.9,89.4,86.9,84.9,84.4,84,83.6,83.2,82.8,82.4,81.9,81.5,81.1,80.7,80.3,79.9,78.3,76.8,75.3,73.7,72.2,70.7,69.2,67.6,66.1,64.6,63.1,61.5,61.4,61.3,61.1,61,60.8,60.7,60.6,60.4,60.3,60.1,60,59.9,59.4,59,58.6,58.2,57.8,57.4,56.9,56.5,56.1,55.7,55.3,54.9,54.4,54,53.6,53.2,52.8,52.4,51.9,51.5,51.1,50.7,50.3,49.9,49.4,49,48.6,48.2,47.8,47.4,46.9,46.5,46.1,45.7,45.3,44.9,44.4,44,43.6,43.2,42.8,42.4,41.9,41.5,41.1,40.7,40.3,39.9,39.4,39,38.6,38.2,37.8,37.4,36.9,36.5,36.1,35.7,35.3,34.9,34.4,34,33.6,33.2,32.8,32.4,31.9,31.5,31.1,30.7,30.3,29.9,29.4,29,28.6,28.2,27.8,27.4,26.9,26.5,26.1,25.7,25.3,24.9];var Bh=mn,Ph=1,pn=new ci(Bh,Ph);async function Dh(){let n,t=(window.config.Changes??[]).map(o=>({since:Date.parse(o.Since)/1e3,intervalHours:o.IntervalHours,concurrence:o.Concurrence,strength:o.Strength})),i={intervalHours:window.config.IntervalHours,concurrence:window.config.Concurrence,hoursAgo:24*30,strength:window.config.Strength,changes:t};switch(window.config.Type){case"patches":{let o=window.dosageHistory.filter(a=>a.HRTType=="patches").map(a=>Date.parse(a.DosageAt)/1e3),l=(window.doseRemovals??[]).map(a=>({appliedAt:Date.parse(a.DosageAt)/1e3,removedAt:Date.parse(a.RemovedAt)/1e3}));n=await pn.predict(o,{...i,removals:l});break}default:{console.log("not drawing chart due to unknown type",window.config.Type);return}}let s=Vh(document.body,"#dosage-stats");Wh(s,"[data-for-days]").forEach(o=>{let l=parseInt(o.dataset.forDays),a=(window.levelStats??[]).find(u=>u.ForDays==l);if(a){ee(o,"span[data-value='mean']",a.Mean),ee(o,"span[data-value='stddev']",a.StdDev);for(let[u,c]of Object.entries(a.Quantiles))ee(o,`span[data-value='quantile'][data-quantile='${u}']`,c)}});let e=(window.pauses??[]).map(o=>({from:Date.parse(o.StartedAt)/1e3,to:o.EndedAt.Valid?Date.parse(o.EndedAt.Time)/1e3:void 0})),h=se(i,Date.now()/1e3);new ui("#history-line").update(n,{quantile:.95,bucketDays:h.intervalHours*h.concurrence/24,onlyLastBucket:!0,spans:e});let r=document.getElementById("dosage-details");r.style.display="block"}function Ah(n){return n.toFixed(1)}function Wh(n,t){return[...n.querySelectorAll(t)].filter(i=>i instanceof HTMLElement)}function Vh(n,t){let i=n.querySelector(t);return(!i||!(i instanceof HTMLElement))&&(console.error("Element not found",t),alert("Cannot render completely. Check console.")),i}function ee(n,t,i){let s=n.querySelector(t);if(!s){console.error("Element not found",t),alert("Cannot render completely. Check console.");return}let e="";switch(typeof i){case"string":e=i;break;case"number":e=Ah(i);break;default:e=i.toString();break}s.textContent=e}await Dh();export{ci as DiscreteHourlyPredictor,dn as EstrogenCandlesticks,ui as EstrogenLine,B as colors,pn as patchesPredictor,lt as quantile,se as regimenAt};
 /*!
  * @license
  * TradingView Lightweight Charts™ v4.1.3
@@ -4,10 +4,9 @@ export * from "./predictors.ts";
 export * from "./statistics.ts";
 
 import * as plot from "./plot.ts";
-import { calculate } from "./statistics.ts";
 import { patchesPredictor } from "./predictors.ts";
-import { TimeValue } from "./predict.ts";
-import { ValueStatistic } from "./statistics.ts";
+import { Change, PredictOpts, regimenAt, TimeValue } from "./predict.ts";
+import { LevelStats } from "./statistics.ts";
 
 declare global {
   interface Window {
@@ -15,28 +14,59 @@ declare global {
       Type: "patches" | string;
       IntervalHours: number;
       Concurrence: number;
+      Strength: number;
+      Changes: {
+        Since: string;
+        IntervalHours: number;
+        Concurrence: number;
+        Strength: number;
+      }[] | null;
     };
     dosageHistory: {
       HRTType: string;
       DosageAt: string;
     }[];
+    doseRemovals: {
+      DosageAt: string;
+      RemovedAt: string;
+    }[] | null;
+    pauses: {
+      StartedAt: string;
+      EndedAt: { Time: string; Valid: boolean };
+    }[] | null;
+    levelStats: LevelStats[] | null;
   }
 }
 
 async function main() {
   let values: TimeValue[];
 
+  const changes: Change[] = (window.config.Changes ?? []).map((change) => ({
+    since: Date.parse(change.Since) / 1000,
+    intervalHours: change.IntervalHours,
+    concurrence: change.Concurrence,
+    strength: change.Strength,
+  }));
+  const regimen: PredictOpts = {
+    intervalHours: window.config.IntervalHours,
+    concurrence: window.config.Concurrence,
+    hoursAgo: 24 * 30, // 30 days or 1 month
+    strength: window.config.Strength,
+    changes,
+  };
+
   switch (window.config.Type) {
     case "patches": {
       const applicationTimes = window.dosageHistory
         .filter((dose) => dose.HRTType == "patches")
         .map((dose) => Date.parse(dose.DosageAt) / 1000);
 
-      values = await patchesPredictor.predict(applicationTimes, {
-        intervalHours: window.config.IntervalHours,
-        concurrence: window.config.Concurrence,
-        hoursAgo: 24 * 30, // 30 days or 1 month
-      });
+      const removals = (window.doseRemovals ?? []).map((removal) => ({
+        appliedAt: Date.parse(removal.DosageAt) / 1000,
+        removedAt: Date.parse(removal.RemovedAt) / 1000,
+      }));
+
+      values = await patchesPredictor.predict(applicationTimes, { ...regimen, removals });
       break;
     }
     default: {
@@ -46,30 +76,33 @@ async function main() {
   }
 
   const statsTable = querySelectorMust(document.body, "#dosage-stats");
-  const timeStats: ({ name: string; forDays: number } & ValueStatistic)[] = [];
 
   querySelectorAll(statsTable, "[data-for-days]").forEach((td) => {
-    const name = td.dataset.name;
     const forDays = parseInt(td.dataset.forDays);
+    const stats = (window.levelStats ?? []).find((stats) => stats.ForDays == forDays);
+    if (!stats) {
+      return;
+    }
 
-    const quantiles = querySelectorAll(td, "span[data-quantile]")
-      .map((span) => span.dataset.quantile)
-      .map((quantile) => parseFloat(quantile) / 100);
-
-    const stats = calculate(values, forDays, quantiles);
-    timeStats.push({ name, forDays, ...stats });
-
-    setElement(td, "span[data-value='mean']", stats.mean);
-    setElement(td, "span[data-value='stddev']", stats.stdDev);
-    for (const [q, value] of Object.entries(stats.quantiles)) {
-      setElement(td, `span[data-value='quantile'][data-quantile='${parseFloat(q) * 100}']`, value);
+    setElement(td, "span[data-value='mean']", stats.Mean);
+    setElement(td, "span[data-value='stddev']", stats.StdDev);
+    for (const [q, value] of Object.entries(stats.Quantiles)) {
+      setElement(td, `span[data-value='quantile'][data-quantile='${q}']`, value);
     }
   });
 
+  const spans = (window.pauses ?? []).map((pause) => ({
+    from: Date.parse(pause.StartedAt) / 1000,
+    to: pause.EndedAt.Valid ? Date.parse(pause.EndedAt.Time) / 1000 : undefined,
+  }));
+
+  const current = regimenAt(regimen, Date.now() / 1000);
+
   new plot.EstrogenLine("#history-line").update(values, {
     quantile: 0.95,
-    bucketDays: (window.config.IntervalHours * window.config.Concurrence) / 24,
+    bucketDays: (current.intervalHours * current.concurrence) / 24,
     onlyLastBucket: true,
+    spans,
   });
 
   // new plot.EstrogenCandlesticks(
@@ -1,8 +1,9 @@
 import { type TimeValue } from "./predict.ts";
 import * as statistics from "./statistics.ts";
 
-// @deno-types="https://cdn.jsdelivr.net/npm/lightweight-charts@4.1.3/dist/typings.d.ts"
-import * as charts from "https://cdn.jsdelivr.net/npm/lightweight-charts@4.1/dist/lightweight-charts.standalone.production.mjs";
+// Vendored so that the bundle can be built and used without network access.
+// The typings are not vendored, so the chart types are loose.
+import * as charts from "./vendor/lightweight-charts.standalone.production.mjs";
 
 export type UTCTimestamp = charts.UTCTimestamp;
 export type ChartOptions = charts.DeepPartial<charts.ChartOptions>;
@@ -74,10 +75,19 @@ export type CandlestickPlotOptions = {
   quantizeDays: number;
 };
 
+// Span is a span of time in Unix seconds that is shaded on a chart, such as a
+// pause of the regimen. A missing end means it lasts until the end of the
+// chart.
+export type Span = {
+  from: number;
+  to?: number;
+};
+
 export class EstrogenLine {
   static numericalKeys = ["mean", "open", "close"] as const;
 
   chart: charts.IChartApi;
+  spanSeries: charts.ISeriesApi<"Histogram", charts.Time>;
   lineSeries: charts.ISeriesApi<"Line", charts.Time>;
   numericalSeries: Record<
     typeof EstrogenLine.numericalKeys[number],
@@ -90,6 +100,19 @@ export class EstrogenLine {
   ) {
     this.chart = newChart(element, options);
 
+    // Spans are drawn as full-height bars on their own hidden price scale,
+    // behind the other series.
+    this.spanSeries = this.chart.addHistogramSeries({
+      priceScaleId: "spans",
+      color: colors.muted,
+      priceLineVisible: false,
+      lastValueVisible: false,
+    });
+    this.chart.priceScale("spans").applyOptions({
+      visible: false,
+      scaleMargins: { top: 0, bottom: 0 },
+    });
+
     this.numericalSeries = {} as typeof this.numericalSeries;
     EstrogenLine.numericalKeys.forEach((key) => {
       this.numericalSeries[key] = this.chart.addLineSeries({
@@ -122,7 +145,17 @@ export class EstrogenLine {
     quantile?: number;
     bucketDays?: number;
     onlyLastBucket?: boolean;
+    spans?: Span[];
   } = {}) {
+    const spans = options.spans ?? [];
+    this.spanSeries.setData(
+      values.map((t) =>
+        spans.some((span) => t.t >= span.from && (span.to === undefined || t.t < span.to))
+          ? { time: t.t as charts.UTCTimestamp, value: 1 }
+          : { time: t.t as charts.UTCTimestamp }
+      ),
+    );
+
     this.lineSeries.setData(
       values.map((t) => ({
         time: t.t as charts.UTCTimestamp,
@@ -141,8 +174,8 @@ export class EstrogenLine {
           const b = Math.min((i + 1) * bucketHours, values.length);
           const v = values.slice(a, b).map((t) => t.v);
 
-          const qhi = statistics.quartile(v, quantile);
-          const qlo = statistics.quartile(v, 1 - quantile);
+          const qhi = statistics.quantile(v, quantile);
+          const qlo = statistics.quantile(v, 1 - quantile);
           const min = Math.min(...v);
           const max = Math.max(...v);
 
@@ -231,8 +264,8 @@ export class EstrogenCandlesticks {
         const o = (b - a) / 2;
         const v = values.slice(a, b).map((t) => t.v);
 
-        const qhi = statistics.quartile(v, quantile);
-        const qlo = statistics.quartile(v, 1 - quantile);
+        const qhi = statistics.quantile(v, quantile);
+        const qlo = statistics.quantile(v, 1 - quantile);
         const min = Math.min(...v);
         const max = Math.max(...v);
 
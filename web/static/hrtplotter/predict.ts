@@ -16,6 +16,48 @@ export type PredictOpts = {
   concurrence: number;
   // hoursAgo is the number of hours ago to start predicting from.
   hoursAgo: number;
+  // removals are the applications that were removed, such as patches that fell
+  // off. They stop contributing to the levels once removed.
+  removals?: Removal[];
+  // strength scales the levels of each application. It defaults to 1.
+  strength?: number;
+  // changes are the changes to the options above for the applications from a
+  // given time on, sorted by that time.
+  changes?: Change[];
+};
+
+// Change replaces the interval, concurrence and strength of the applications
+// from since on.
+export type Change = {
+  since: Timestamp;
+  intervalHours: number;
+  concurrence: number;
+  strength: number;
+};
+
+// regimenAt returns the interval, concurrence and strength of an application
+// at t.
+export function regimenAt(opts: PredictOpts, t: Timestamp): Change {
+  let change: Change = {
+    since: 0,
+    intervalHours: opts.intervalHours,
+    concurrence: opts.concurrence,
+    strength: opts.strength ?? 1,
+  };
+  for (const c of opts.changes ?? []) {
+    if (c.since > t) {
+      break;
+    }
+    change = c;
+  }
+  return { ...change, strength: change.strength || 1 };
+}
+
+// Removal is an application that was removed at removedAt. appliedAt is the
+// time of the application.
+export type Removal = {
+  appliedAt: Timestamp;
+  removedAt: Timestamp;
 };
 
 export interface Predictor {
@@ -51,13 +93,7 @@ export class DiscreteHourlyPredictor implements Predictor {
 
     const f_0 = this.f_0;
     const f_1 = this.f_1;
-    // f_1short specifically is the f_1 value but limited to the concurrence
-    // value. This value will only be used for patches older than the last few
-    // current patches.
-    const f_1short = opts.concurrence > 0
-      ? Math.min(this.f_1, opts.intervalHours * opts.concurrence)
-      : this.f_1;
-    console.debug(`predictor:`, { f_0, f_1, f_1short });
+    console.debug(`predictor:`, { f_0, f_1 });
 
     const now = Date.now() / 1000;
     const prior = now - opts.hoursAgo * HOUR;
@@ -74,15 +110,31 @@ export class DiscreteHourlyPredictor implements Predictor {
     const values = Array<number>(hours).fill(0);
     console.debug("values", values);
 
+    const removedAt = new Map((opts.removals ?? []).map((r) => [r.appliedAt, r.removedAt]));
+
     for (const [i, t] of applicationTimes.entries()) {
+      const params = regimenAt(opts, t);
+      // f_1short specifically is the f_1 value but limited to the concurrence
+      // value. This value will only be used for patches older than the last
+      // few current patches.
+      const f_1short = params.concurrence > 0
+        ? Math.min(f_1, params.intervalHours * params.concurrence)
+        : f_1;
+
       // Calculate the hourly index for the current application time by
       // subtracting it with the first time and rounding it.
       let hourStart = Math.floor((t - prior) / HOUR) + f_0;
       let hourEnd = hourStart + (
         // If the patch index is near the end, then we should use f_1, else we
         // use f_1short.
-        applicationTimes.length - i <= opts.concurrence ? f_1 : f_1short
+        applicationTimes.length - i <= params.concurrence ? f_1 : f_1short
       );
+      // A removed application contributes until the last hour that starts
+      // before it was removed.
+      const removed = removedAt.get(t);
+      if (removed !== undefined) {
+        hourEnd = Math.min(hourEnd, Math.ceil((removed - prior) / HOUR));
+      }
       console.debug("for", t, "going from", hourStart, "to", hourEnd, "of", values.length, "hours");
 
       if (hourEnd < 0) {
@@ -102,7 +154,7 @@ export class DiscreteHourlyPredictor implements Predictor {
 
       console.debug("for", t, "total hours taken is", hourEnd - hourStart);
       for (let h = hourStart; h < hourEnd; h++) {
-        values[h] += this.f(h - hourStart + fOffset);
+        values[h] += this.f(h - hourStart + fOffset) * params.strength;
       }
     }
 
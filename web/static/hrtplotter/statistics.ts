@@ -1,38 +1,28 @@
-import * as statistics from "https://cdn.jsdelivr.net/gh/avalero/jsr-maths-statistics@master/mod.ts";
-export * from "https://cdn.jsdelivr.net/gh/avalero/jsr-maths-statistics@master/mod.ts";
-
-import { type TimeValue } from "./predict.ts";
-
-export type CalculateData = {
-  values: TimeValue[];
-  forDays: readonly number[];
-  quantiles: readonly number[];
+// LevelStats are the statistics of the predicted levels over the last ForDays
+// days. They are calculated by the server and stored into the page.
+export type LevelStats = {
+  Name: string;
+  ForDays: number;
+  Mean: number;
+  StdDev: number;
+  // Quantiles maps percentiles, such as 95 for the 95th percentile, to the
+  // level below which that percentage of the levels lie.
+  Quantiles: Record<string, number>;
 };
 
-export type ValueStatistic<QuantilesT extends readonly number[] = []> = {
-  mean: number;
-  stdDev: number;
-  quantiles: Record<QuantilesT[number], number>;
-};
+// quantile returns the q-quantile of values for q in [0, 1], linearly
+// interpolating between the closest values. It is the same as Quantile in
+// internal/stats.
+export function quantile(values: number[], q: number): number {
+  if (values.length == 0 || q < 0 || q > 1) {
+    return NaN;
+  }
 
-export function calculate<
-  QuantilesT extends readonly number[],
-  ResultT extends ValueStatistic<QuantilesT>,
->(
-  values: TimeValue[],
-  days: number,
-  quantiles: QuantilesT,
-): ResultT {
-  const averageHours = days * 24;
-  const hoursValues = values.slice(-averageHours).map((v) => v.v);
-  return {
-    mean: statistics.average(hoursValues),
-    stdDev: statistics.stdDev(hoursValues),
-    quantiles: quantiles.reduce((quantiles, q) => {
-      return {
-        ...quantiles,
-        [q]: statistics.quartile(hoursValues, q),
-      };
-    }, {}),
-  } as ResultT;
+  const sorted = [...values].sort((a, b) => a - b);
+  const pos = q * (sorted.length - 1);
+  const i = Math.floor(pos);
+  if (i + 1 >= sorted.length) {
+    return sorted[sorted.length - 1];
+  }
+  return sorted[i] + (pos - i) * (sorted[i + 1] - sorted[i]);
 }
@@ -0,0 +1,24 @@
+// Package values contains the pharmacokinetic values used by both the
+// TypeScript and Go predictors. The values are shared as JSON files so that
+// both sides always agree on them.
+package values
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+//go:embed patches.json
+var patchesJSON []byte
+
+// Patches contains the hourly estrogen levels in pg/mL of a single patch
+// starting from the time it was applied.
+var Patches = mustParse(patchesJSON)
+
+func mustParse(b []byte) []float64 {
+	var v []float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		panic(err)
+	}
+	return v
+}
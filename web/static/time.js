@@ -27,14 +27,21 @@ function formatDuration(duration, long = false) {
   return relativeFormatters[long ? "long" : "short"].format(duration, unit);
 }
 
+// timeZone is the time zone configured on the server, if any. Times are shown
+// in it rather than in the time zone of the browser, so they match the ones
+// rendered by the server.
+const timeZone = document.querySelector('meta[name="time-zone"]')?.content || undefined;
+
 const absoluteFormatters = {
   short: new Intl.DateTimeFormat("en", {
     hour: "numeric",
     minute: "numeric",
+    timeZone,
   }),
   long: new Intl.DateTimeFormat("en", {
     timeStyle: "medium",
     dateStyle: "medium",
+    timeZone,
   }),
 };
 
@@ -42,19 +49,21 @@ function formatTime(time, long = false) {
   return absoluteFormatters[long ? "long" : "short"].format(time);
 }
 
-document.querySelectorAll("time").forEach((el) => {
-  const time = Date.parse(el.getAttribute("datetime"));
-  const long = el.getAttribute("data-format") == "long";
-  // const title = !!el.getAttribute("data-format-title");
-  const format = el.classList.contains("relative")
-    ? () => formatDuration(time - Date.now(), long)
-    : () => formatTime(time, long);
-  const update = () => {
-    el.textContent = format();
-  };
-  setInterval(update, 1000);
-  update();
-});
+// updateTimes updates all <time> elements on the page. It looks up the elements
+// every time, so elements replaced by events.js are also updated.
+function updateTimes() {
+  document.querySelectorAll("time").forEach((el) => {
+    const time = Date.parse(el.getAttribute("datetime"));
+    const long = el.getAttribute("data-format") == "long";
+    // const title = !!el.getAttribute("data-format-title");
+    el.textContent = el.classList.contains("relative")
+      ? formatDuration(time - Date.now(), long)
+      : formatTime(time, long);
+  });
+}
+
+setInterval(updateTimes, 1000);
+updateTimes();
 
 document.querySelectorAll("button[data-destructive]").forEach((el) => {
   const confirmation = el.getAttribute("data-confirmation") || "Are you sure?";
@@ -21,6 +21,9 @@ var embedFS embed.FS
 // Templates contains the templates for the web server.
 type Templates struct {
 	*tmplutil.Templater
+	// Location returns the time zone that the templates show times in. It
+	// is the time zone of the machine if nil.
+	Location func() *time.Location
 }
 
 // EmbeddedTemplates returns a new Templates instance with the embedded filesystem.
@@ -34,6 +37,7 @@ func EmbeddedTemplates() *Templates {
 
 // NewTemplates returns a new templater with the given filesystem.
 func NewTemplates(fs fs.FS) (*Templates, error) {
+	templates := &Templates{}
 	t := &tmplutil.Templater{
 		FileSystem: fs,
 		Includes: map[string]string{
@@ -45,6 +49,24 @@ func NewTemplates(fs fs.FS) (*Templates, error) {
 				"rfc3339": func(t time.Time) string {
 					return t.Format(time.RFC3339)
 				},
+				"duration": formatDuration,
+				// location is the time zone of the page.
+				"location": func() *time.Location {
+					return templates.location()
+				},
+				// local returns the time in the time zone of the page.
+				"local": func(t time.Time) time.Time {
+					return t.In(templates.location())
+				},
+				// timeZone is the IANA name of the time zone of the page,
+				// so that times formatted in the browser match the ones on
+				// the page. It is empty for the time zone of the machine.
+				"timeZone": func() string {
+					if name := templates.location().String(); name != "Local" {
+						return name
+					}
+					return ""
+				},
 				"storeJSON": func(name string, v any) template.HTML {
 					b, err := json.Marshal(v)
 					if err != nil {
@@ -67,7 +89,15 @@ func NewTemplates(fs fs.FS) (*Templates, error) {
 	if err := t.Preregister("pages"); err != nil {
 		return nil, fmt.Errorf("failed to preregister pages: %w", err)
 	}
-	return &Templates{t}, nil
+	templates.Templater = t
+	return templates, nil
+}
+
+func (t *Templates) location() *time.Location {
+	if t.Location != nil {
+		return t.Location()
+	}
+	return time.Local
 }
 
 func joinFuncMaps(maps ...map[string]any) map[string]any {
@@ -88,3 +118,28 @@ func (t *Templates) StaticHandler() http.Handler {
 	}
 	return http.StripPrefix("/static", http.FileServer(http.FS(fs_)))
 }
+
+// formatDuration formats the duration to the minute in days, hours and minutes,
+// such as "1d 12h 5m".
+func formatDuration(d time.Duration) string {
+	var s strings.Builder
+	if d < 0 {
+		s.WriteString("-")
+		d = -d
+	}
+	d = d.Round(time.Minute)
+
+	days := d / (24 * time.Hour)
+	hours := d % (24 * time.Hour) / time.Hour
+	minutes := d % time.Hour / time.Minute
+
+	switch {
+	case days > 0:
+		fmt.Fprintf(&s, "%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		fmt.Fprintf(&s, "%dh %dm", hours, minutes)
+	default:
+		fmt.Fprintf(&s, "%dm", minutes)
+	}
+	return s.String()
+}
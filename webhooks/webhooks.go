@@ -0,0 +1,204 @@
+// Package webhooks delivers events from the event bus to the outgoing
+// webhooks in the configuration.
+//
+// Each event is sent as a JSON POST request whose body is the events.Event.
+// The JSON schema for the body of each event type is available from Schema.
+// The body is signed using HMAC-SHA256 with the webhook's secret, and the
+// signature is sent in the X-Hrtclicker-Signature header as "sha256=<hex>".
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/db"
+	"libdb.so/hrtclicker/events"
+)
+
+//go:embed schemas
+var schemas embed.FS
+
+// Schema returns the JSON schema of the payload for the given event type.
+func Schema(t events.Type) ([]byte, error) {
+	return schemas.ReadFile("schemas/" + string(t) + ".json")
+}
+
+const (
+	// maxAttempts is the maximum number of attempts to deliver an event.
+	maxAttempts = 5
+	// initialBackoff is the time waited before the first retry. It doubles
+	// with every retry after.
+	initialBackoff = 5 * time.Second
+	// requestTimeout is the timeout for a single delivery attempt.
+	requestTimeout = 15 * time.Second
+	// logRetention is how long delivery logs are kept for.
+	logRetention = 30 * 24 * time.Hour
+)
+
+// Dependencies is a set of dependencies required by the Dispatcher.
+type Dependencies struct {
+	Config   *hrtclicker.ReloadableConfig
+	Logger   *slog.Logger
+	Database *db.SQLiteDB
+	Events   *events.Bus
+}
+
+// Dispatcher delivers the events published on the event bus to the configured
+// webhooks.
+type Dispatcher struct {
+	Dependencies
+	client *http.Client
+}
+
+// NewDispatcher creates a new Dispatcher.
+func NewDispatcher(deps Dependencies) *Dispatcher {
+	return &Dispatcher{
+		Dependencies: deps,
+		client:       &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run delivers events until ctx is canceled. Deliveries that are still being
+// retried are abandoned.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for ev := range d.Events.SubscribeAll(ctx) {
+		for _, webhook := range d.Config.Load().Webhooks {
+			if !webhook.Wants(ev.Type) {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.deliver(ctx, webhook, ev)
+			}()
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, webhook hrtclicker.WebhookConfig, ev events.Event) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		d.Logger.Error(
+			"failed to marshal webhook payload",
+			"event_type", ev.Type,
+			"err", err)
+		return
+	}
+
+	backoff := initialBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		statusCode, err := d.send(ctx, webhook, ev, body)
+		d.logDelivery(ctx, webhook, ev, attempt, statusCode, err)
+		if err == nil {
+			return
+		}
+
+		d.Logger.Warn(
+			"failed to deliver webhook",
+			"url", webhook.URL,
+			"event_type", ev.Type,
+			"event_id", ev.ID,
+			"attempt", attempt,
+			"err", err)
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+
+	d.Logger.Error(
+		"giving up delivering webhook",
+		"url", webhook.URL,
+		"event_type", ev.Type,
+		"event_id", ev.ID)
+}
+
+// send sends a single delivery attempt. It returns the response's status code,
+// or 0 if there was no response.
+func (d *Dispatcher) send(ctx context.Context, webhook hrtclicker.WebhookConfig, ev events.Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hrtclicker-webhooks")
+	req.Header.Set("X-Hrtclicker-Event", string(ev.Type))
+	req.Header.Set("X-Hrtclicker-Delivery", ev.ID)
+	req.Header.Set("X-Hrtclicker-Signature", Sign(webhook.Secret, body))
+
+	r, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(r.Body, 1024))
+		return r.StatusCode, fmt.Errorf("unexpected status code: %d (%s)", r.StatusCode, body)
+	}
+
+	return r.StatusCode, nil
+}
+
+func (d *Dispatcher) logDelivery(ctx context.Context, webhook hrtclicker.WebhookConfig, ev events.Event, attempt, statusCode int, deliveryErr error) {
+	now := time.Now().UTC()
+
+	params := db.LogWebhookDeliveryParams{
+		EventID:     ev.ID,
+		EventType:   string(ev.Type),
+		Url:         webhook.URL,
+		Attempt:     int64(attempt),
+		StatusCode:  sql.NullInt64{Int64: int64(statusCode), Valid: statusCode != 0},
+		AttemptedAt: now,
+	}
+	if deliveryErr != nil {
+		params.Error = sql.NullString{String: deliveryErr.Error(), Valid: true}
+	}
+
+	err := d.Database.Tx(func(q *db.Queries) error {
+		if err := q.LogWebhookDelivery(ctx, params); err != nil {
+			return err
+		}
+		return q.PruneWebhookDeliveries(ctx, now.Add(-logRetention))
+	})
+	if err != nil {
+		d.Logger.Error(
+			"failed to log webhook delivery",
+			"event_id", ev.ID,
+			"err", err)
+	}
+}
+
+// Sign returns the value of the X-Hrtclicker-Signature header for the given
+// payload body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
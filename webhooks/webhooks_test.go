@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"libdb.so/hrtclicker"
+	"libdb.so/hrtclicker/events"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		body   string
+		want   string
+	}{
+		{
+			// Test case 2 of RFC 4231.
+			name:   "RFC 4231",
+			secret: "Jefe",
+			body:   "what do ya want for nothing?",
+			want:   "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		},
+		{
+			name:   "empty secret",
+			secret: "",
+			body:   "",
+			want:   "sha256=b613679a0814d9ec772f95d778c35fc5ff1697c493715653c6c712144292c5ad",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Sign(test.secret, []byte(test.body)); got != test.want {
+				t.Errorf("Sign() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	const secret = "s3cret"
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{"delivered", http.StatusNoContent, false},
+		{"redirect", http.StatusFound, true},
+		{"server error", http.StatusInternalServerError, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got *http.Request
+			var gotBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				gotBody, _ = io.ReadAll(r.Body)
+				w.Header().Set("Location", "/elsewhere")
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			ev := events.Event{ID: "01J0000000000000000000000", Type: events.DoseRecorded}
+			body, err := json.Marshal(ev)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := NewDispatcher(Dependencies{})
+			d.client = server.Client()
+			d.client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+			webhook := hrtclicker.WebhookConfig{URL: server.URL, Secret: secret}
+			statusCode, err := d.send(context.Background(), webhook, ev, body)
+			if statusCode != test.statusCode {
+				t.Errorf("send() status code = %d, want %d", statusCode, test.statusCode)
+			}
+			if (err != nil) != test.wantErr {
+				t.Errorf("send() = %v, want an error: %v", err, test.wantErr)
+			}
+
+			if got == nil {
+				t.Fatal("no request was received")
+			}
+			if got.Header.Get("X-Hrtclicker-Event") != string(events.DoseRecorded) {
+				t.Errorf("X-Hrtclicker-Event = %q", got.Header.Get("X-Hrtclicker-Event"))
+			}
+			if got.Header.Get("X-Hrtclicker-Delivery") != ev.ID {
+				t.Errorf("X-Hrtclicker-Delivery = %q", got.Header.Get("X-Hrtclicker-Delivery"))
+			}
+
+			// Receivers verify the signature against the body they got.
+			signature := got.Header.Get("X-Hrtclicker-Signature")
+			if !strings.HasPrefix(signature, "sha256=") {
+				t.Errorf("X-Hrtclicker-Signature = %q, want a sha256= prefix", signature)
+			}
+			if !hmac.Equal([]byte(signature), []byte(Sign(secret, gotBody))) {
+				t.Errorf("X-Hrtclicker-Signature = %q does not match the body", signature)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	for _, eventType := range events.Types {
+		t.Run(string(eventType), func(t *testing.T) {
+			schema, err := Schema(eventType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(schema) {
+				t.Error("schema is not valid JSON")
+			}
+		})
+	}
+}